@@ -0,0 +1,93 @@
+// Команда simulate разыгрывает тысячи лобби методом Монте-Карло на настоящей логике LobbyService
+// (с репозиториями в памяти) и оценивает экономику игры: доходность создателя, доход сервиса,
+// разброс выплат и пул, необходимый, чтобы пережить серии побед игроков.
+//
+//	go run ./cmd/simulate -games 500 -lobbies 20 -multiplier 2 -commission 0.05 -format csv
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/logger"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "simulate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+
+	cfg := Config{}
+	skills := fs.String("skill", strings.Join(Skills, ","), "comma-separated player skill models: "+strings.Join(Skills, ", "))
+	fs.IntVar(&cfg.Games, "games", 200, "games (target words) per skill model")
+	fs.IntVar(&cfg.LobbiesPerGame, "lobbies", 20, "lobbies played in each game")
+	fs.IntVar(&cfg.Length, "length", 5, "word length")
+	fs.IntVar(&cfg.MaxTries, "tries", 6, "max tries per lobby")
+	fs.Float64Var(&cfg.Bet, "bet", 1, "bet amount")
+	fs.Float64Var(&cfg.Multiplier, "multiplier", 2, "reward multiplier")
+	fs.Float64Var(&cfg.Commission, "commission", 0.05, "service commission rate")
+	fs.Uint64Var(&cfg.Seed, "seed", 1, "random seed")
+	dictPath := fs.String("dict", "", "dictionary file (one word per line), empty - built-in dictionary")
+	format := fs.String("format", "json", "output format: json or csv")
+	outputPath := fs.String("output", "", "output file, empty - stdout")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg.Skills = strings.Split(*skills, ",")
+	for i := range cfg.Skills {
+		cfg.Skills[i] = strings.TrimSpace(cfg.Skills[i])
+	}
+
+	var write func(io.Writer, Output) error
+	switch *format {
+	case "json":
+		write = WriteJSON
+	case "csv":
+		write = WriteCSV
+	default:
+		return fmt.Errorf("unknown output format %q, must be json or csv", *format)
+	}
+
+	dict := dictionary.Default()
+	if *dictPath != "" {
+		var err error
+		if dict, err = dictionary.Load(*dictPath); err != nil {
+			return err
+		}
+	}
+
+	// Логи сервисов на каждое лобби только замедляют симуляцию
+	logger.Init(logger.Config{DisableConsole: true, DisableLokiFile: true})
+
+	simulator, err := NewSimulator(cfg, dict)
+	if err != nil {
+		return err
+	}
+	reports, err := simulator.Run(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if *outputPath != "" {
+		f, err := os.Create(*outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return write(w, Output{Config: cfg, Results: reports})
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// Report итоги симуляции для одной модели игрока. Суммы - в валюте ставки
+type Report struct {
+	Skill           string  `json:"skill"`
+	Games           int     `json:"games"`
+	Lobbies         int     `json:"lobbies"`
+	Wins            int     `json:"wins"`
+	WinRate         float64 `json:"win_rate"`
+	AvgWinningTries float64 `json:"avg_winning_tries"`
+	MaxWinStreak    int     `json:"max_win_streak"` // Самая длинная серия побед игроков подряд в одной игре
+	TotalBets       float64 `json:"total_bets"`
+	TotalPayouts    float64 `json:"total_payouts"`
+	HouseRevenue    float64 `json:"house_revenue"`
+	CreatorPnL      float64 `json:"creator_pnl"`
+	CreatorEdge     float64 `json:"creator_edge"` // Доход создателя на единицу ставки
+	CreatorROI      float64 `json:"creator_roi"`  // Доход создателя на единицу пула, необходимого играм
+	LosingGames     int     `json:"losing_games"` // Игр, закончившихся для создателя убытком
+	PayoutMean      float64 `json:"payout_mean"`  // Средняя выплата на единицу ставки
+	PayoutVariance  float64 `json:"payout_variance"`
+	PayoutStdDev    float64 `json:"payout_stddev"`
+	RequiredPoolAvg float64 `json:"required_pool_avg"` // Пул, при котором игра приняла бы все ставки: в среднем,
+	RequiredPoolP95 float64 `json:"required_pool_p95"` // в 95% игр
+	RequiredPoolMax float64 `json:"required_pool_max"` // и в худшей игре
+}
+
+// lobbyResult результат одного лобби. Пул - изменение с начала игры
+type lobbyResult struct {
+	won         bool
+	tries       int
+	bet         float64
+	payout      float64
+	poolBefore  float64
+	poolAfter   float64
+	reservation float64
+}
+
+// accumulator собирает результаты лобби одной модели игрока
+type accumulator struct {
+	skill         string
+	games         int
+	losingGames   int
+	lobbies       int
+	wins          int
+	winningTries  int
+	maxWinStreak  int
+	bets          float64
+	payouts       float64
+	creatorPnL    float64
+	payoutSum     float64
+	payoutSquares float64
+	requiredPools []float64
+
+	// Текущая игра
+	winStreak int
+	gamePnL   float64
+	gamePool  float64
+}
+
+func newAccumulator(skill string) *accumulator {
+	return &accumulator{skill: skill}
+}
+
+func (a *accumulator) startGame() {
+	a.winStreak = 0
+	a.gamePnL = 0
+	a.gamePool = 0
+}
+
+func (a *accumulator) addLobby(r lobbyResult) {
+	a.lobbies++
+	a.bets += r.bet
+	a.payouts += r.payout
+	ratio := r.payout / r.bet
+	a.payoutSum += ratio
+	a.payoutSquares += ratio * ratio
+
+	if r.won {
+		a.wins++
+		a.winningTries += r.tries
+		a.winStreak++
+		a.maxWinStreak = max(a.maxWinStreak, a.winStreak)
+	} else {
+		a.winStreak = 0
+	}
+
+	// Ставка принимается, если свободный пул покрывает резерв, а выплата не должна увести пул в минус
+	a.gamePool = max(a.gamePool, r.reservation-r.poolBefore, -r.poolAfter)
+	a.gamePnL = r.poolAfter
+}
+
+func (a *accumulator) finishGame() {
+	a.games++
+	a.creatorPnL += a.gamePnL
+	if a.gamePnL < 0 {
+		a.losingGames++
+	}
+	a.requiredPools = append(a.requiredPools, a.gamePool)
+}
+
+func (a *accumulator) report() *Report {
+	r := &Report{
+		Skill:        a.skill,
+		Games:        a.games,
+		Lobbies:      a.lobbies,
+		Wins:         a.wins,
+		MaxWinStreak: a.maxWinStreak,
+		TotalBets:    round(a.bets),
+		TotalPayouts: round(a.payouts),
+		HouseRevenue: round(a.bets - a.payouts - a.creatorPnL),
+		CreatorPnL:   round(a.creatorPnL),
+		LosingGames:  a.losingGames,
+	}
+
+	if a.lobbies > 0 {
+		n := float64(a.lobbies)
+		mean := a.payoutSum / n
+		variance := math.Max(0, a.payoutSquares/n-mean*mean)
+
+		r.WinRate = round(float64(a.wins) / n)
+		r.CreatorEdge = round(a.creatorPnL / a.bets)
+		r.PayoutMean = round(mean)
+		r.PayoutVariance = round(variance)
+		r.PayoutStdDev = round(math.Sqrt(variance))
+	}
+	if a.wins > 0 {
+		r.AvgWinningTries = round(float64(a.winningTries) / float64(a.wins))
+	}
+
+	if len(a.requiredPools) > 0 {
+		pools := slices.Clone(a.requiredPools)
+		slices.Sort(pools)
+
+		var total float64
+		for _, p := range pools {
+			total += p
+		}
+		r.RequiredPoolAvg = round(total / float64(len(pools)))
+		r.RequiredPoolP95 = round(pools[int(math.Ceil(0.95*float64(len(pools))))-1])
+		r.RequiredPoolMax = round(pools[len(pools)-1])
+		if total > 0 {
+			r.CreatorROI = round(a.creatorPnL / total)
+		}
+	}
+
+	return r
+}
+
+// round округляет до 6 знаков, чтобы не выводить погрешность вычислений
+func round(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
+
+// Output результат симуляции
+type Output struct {
+	Config  Config    `json:"config"`
+	Results []*Report `json:"results"`
+}
+
+// WriteJSON выводит результат в JSON
+func WriteJSON(w io.Writer, out Output) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// csvHeader колонки CSV в порядке полей Report
+var csvHeader = []string{
+	"skill", "games", "lobbies", "wins", "win_rate", "avg_winning_tries", "max_win_streak",
+	"total_bets", "total_payouts", "house_revenue", "creator_pnl", "creator_edge", "creator_roi", "losing_games",
+	"payout_mean", "payout_variance", "payout_stddev", "required_pool_avg", "required_pool_p95", "required_pool_max",
+}
+
+// WriteCSV выводит результат в CSV: по строке на модель игрока
+func WriteCSV(w io.Writer, out Output) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, r := range out.Results {
+		row := []string{
+			r.Skill, strconv.Itoa(r.Games), strconv.Itoa(r.Lobbies), strconv.Itoa(r.Wins),
+			f(r.WinRate), f(r.AvgWinningTries), strconv.Itoa(r.MaxWinStreak),
+			f(r.TotalBets), f(r.TotalPayouts), f(r.HouseRevenue), f(r.CreatorPnL),
+			f(r.CreatorEdge), f(r.CreatorROI), strconv.Itoa(r.LosingGames),
+			f(r.PayoutMean), f(r.PayoutVariance), f(r.PayoutStdDev),
+			f(r.RequiredPoolAvg), f(r.RequiredPoolP95), f(r.RequiredPoolMax),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/TakuroBreath/wordle/internal/service"
+	"github.com/google/uuid"
+)
+
+// Модели игроков
+const (
+	SkillRandom    = "random"    // Называет случайные слова, не глядя на подсказки
+	SkillHeuristic = "heuristic" // Называет случайное слово, не противоречащее подсказкам
+	SkillOptimal   = "optimal"   // Решатель, выбирающий ход с максимальной энтропией подсказок
+)
+
+// Skills все модели игроков в порядке вывода
+var Skills = []string{SkillRandom, SkillHeuristic, SkillOptimal}
+
+// Config параметры симуляции
+type Config struct {
+	Skills         []string `json:"skills"`
+	Games          int      `json:"games"`            // Сколько игр (загаданных слов) на каждую модель игрока
+	LobbiesPerGame int      `json:"lobbies_per_game"` // Сколько лобби играется в каждой игре
+	Length         int      `json:"length"`
+	MaxTries       int      `json:"max_tries"`
+	Bet            float64  `json:"bet"`
+	Multiplier     float64  `json:"multiplier"`
+	Commission     float64  `json:"commission"`
+	Seed           uint64   `json:"seed"`
+}
+
+// Validate проверяет параметры симуляции
+func (c Config) Validate() error {
+	if len(c.Skills) == 0 {
+		return errors.New("at least one skill model is required")
+	}
+	for _, skill := range c.Skills {
+		if !slices.Contains(Skills, skill) {
+			return fmt.Errorf("unknown skill model %q, must be one of %s", skill, strings.Join(Skills, ", "))
+		}
+	}
+	if c.Games <= 0 || c.LobbiesPerGame <= 0 {
+		return errors.New("games and lobbies per game must be positive")
+	}
+	if c.Length <= 0 || c.MaxTries <= 0 {
+		return errors.New("word length and max tries must be positive")
+	}
+	if c.Bet <= 0 || c.Multiplier < 1 {
+		return errors.New("bet must be positive and multiplier must be >= 1.0")
+	}
+	if c.Commission < 0 || c.Commission >= 1 {
+		return errors.New("commission must be in [0, 1)")
+	}
+	return nil
+}
+
+// player модель игрока: предлагает ход и учитывает подсказку на него
+type player interface {
+	Guess() string
+	Update(guess string, result []int)
+}
+
+// randomPlayer называет случайные слова словаря
+type randomPlayer struct {
+	words []string
+	rng   *rand.Rand
+}
+
+func (p *randomPlayer) Guess() string {
+	return p.words[p.rng.IntN(len(p.words))]
+}
+
+func (p *randomPlayer) Update(string, []int) {}
+
+// heuristicPlayer называет случайное слово из ещё возможных, как делает большинство живых игроков
+type heuristicPlayer struct {
+	solver *dictionary.Solver
+	random randomPlayer
+}
+
+func (p *heuristicPlayer) Guess() string {
+	candidates := p.solver.Candidates()
+	if len(candidates) == 0 {
+		return p.random.Guess()
+	}
+	return candidates[p.random.rng.IntN(len(candidates))]
+}
+
+func (p *heuristicPlayer) Update(guess string, result []int) {
+	p.solver.Update(guess, result)
+}
+
+// optimalPlayer играет ходами решателя. Ход решателя зависит только от предыдущих ходов и подсказок,
+// поэтому выбранные ходы кешируются между лобби
+type optimalPlayer struct {
+	solver  *dictionary.Solver
+	random  randomPlayer
+	history string
+	moves   map[string]string
+}
+
+func (p *optimalPlayer) Guess() string {
+	guess, ok := p.moves[p.history]
+	if !ok {
+		guess = p.solver.Guess()
+		p.moves[p.history] = guess
+	}
+	if guess == "" {
+		return p.random.Guess()
+	}
+	return guess
+}
+
+func (p *optimalPlayer) Update(guess string, result []int) {
+	p.history += fmt.Sprint(guess, result)
+	p.solver.Update(guess, result)
+}
+
+// Simulator разыгрывает лобби на настоящем LobbyService с репозиториями в памяти
+type Simulator struct {
+	cfg   Config
+	dict  *dictionary.Dictionary
+	words []string
+	moves map[string]string // Кеш ходов решателя
+}
+
+// NewSimulator создает симулятор. Загаданные слова и ходы игроков берутся из словаря
+func NewSimulator(cfg Config, dict *dictionary.Dictionary) (*Simulator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	words := dict.Words(cfg.Length)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("dictionary has no words of length %d", cfg.Length)
+	}
+	return &Simulator{cfg: cfg, dict: dict, words: words, moves: make(map[string]string)}, nil
+}
+
+// Run разыгрывает игры для каждой модели игрока. Все модели играют одни и те же загаданные слова
+func (s *Simulator) Run(ctx context.Context) ([]*Report, error) {
+	reports := make([]*Report, 0, len(s.cfg.Skills))
+	for i, skill := range s.cfg.Skills {
+		targets := rand.New(rand.NewPCG(s.cfg.Seed, 0))
+		moves := rand.New(rand.NewPCG(s.cfg.Seed, uint64(i+1)))
+
+		acc := newAccumulator(skill)
+		for g := 0; g < s.cfg.Games; g++ {
+			word := s.words[targets.IntN(len(s.words))]
+			if err := s.playGame(ctx, skill, word, moves, acc); err != nil {
+				return nil, fmt.Errorf("%s skill, game %d: %w", skill, g+1, err)
+			}
+		}
+		reports = append(reports, acc.report())
+	}
+	return reports, nil
+}
+
+// environment набор сервисов одной игры. Каждая игра получает свои репозитории, чтобы они не разрастались
+type environment struct {
+	games   *mocks.MockGameRepository
+	users   *mocks.MockUserRepository
+	lobbies models.LobbyService
+}
+
+func (s *Simulator) newEnvironment() *environment {
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+
+	txService := service.NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := service.NewUserServiceImpl(userRepo, txService)
+	historyService := service.NewHistoryService(mocks.NewMockHistoryRepository(), gameRepo, userRepo, lobbyRepo)
+	commissionService := service.NewCommissionService(mocks.NewMockCommissionRepository(), models.CommissionPolicy{DefaultRate: s.cfg.Commission}, nil)
+
+	return &environment{
+		games: gameRepo,
+		users: userRepo,
+		lobbies: service.NewLobbyService(service.LobbyServiceDeps{
+			LobbyRepo:          lobbyRepo,
+			GameRepo:           gameRepo,
+			AttemptRepo:        mocks.NewMockAttemptRepository(),
+			RedisRepo:          mocks.NewMockRedisRepository(),
+			UserService:        userService,
+			TransactionService: txService,
+			HistoryService:     historyService,
+			Commission:         commissionService,
+			Dictionary:         s.dict,
+		}),
+	}
+}
+
+// poolPnL возвращает изменение пула игры с начала симуляции
+func (e *environment) poolPnL(ctx context.Context, gameID uuid.UUID, initialPool float64) (float64, error) {
+	game, err := e.games.GetByID(ctx, gameID)
+	if err != nil {
+		return 0, err
+	}
+	return game.RewardPoolTon - initialPool, nil
+}
+
+// newPlayer создает игрока заданной модели
+func (s *Simulator) newPlayer(skill string, rng *rand.Rand) player {
+	random := randomPlayer{words: s.words, rng: rng}
+	switch skill {
+	case SkillHeuristic:
+		return &heuristicPlayer{solver: s.dict.NewSolver(s.cfg.Length), random: random}
+	case SkillOptimal:
+		return &optimalPlayer{solver: s.dict.NewSolver(s.cfg.Length), random: random, moves: s.moves}
+	default:
+		return &random
+	}
+}
+
+// playGame создаёт игру с загаданным словом и разыгрывает в ней лобби подряд.
+// Пул заведомо покрывает любые выплаты: необходимый пул считается по фактическим результатам
+func (s *Simulator) playGame(ctx context.Context, skill, word string, rng *rand.Rand, acc *accumulator) error {
+	env := s.newEnvironment()
+
+	maxReward := s.cfg.Bet * s.cfg.Multiplier * 1.5
+	game := &models.Game{
+		ID:               uuid.New(),
+		CreatorID:        1,
+		Title:            "simulation",
+		Word:             word,
+		Length:           s.cfg.Length,
+		MaxTries:         s.cfg.MaxTries,
+		TimeLimit:        60,
+		MinBet:           s.cfg.Bet,
+		MaxBet:           s.cfg.Bet,
+		RewardMultiplier: s.cfg.Multiplier,
+		Currency:         models.CurrencyTON,
+		RewardPoolTon:    maxReward * float64(s.cfg.LobbiesPerGame+1),
+		Status:           models.GameStatusActive,
+	}
+	if err := env.games.Create(ctx, game); err != nil {
+		return err
+	}
+	initialPool := game.RewardPoolTon
+
+	acc.startGame()
+	for l := 0; l < s.cfg.LobbiesPerGame; l++ {
+		userID := uint64(l + 2)
+		if err := env.users.Create(ctx, &models.User{TelegramID: userID, Username: fmt.Sprintf("player%d", l+1), BalanceTon: s.cfg.Bet}); err != nil {
+			return err
+		}
+
+		poolBefore, err := env.poolPnL(ctx, game.ID, initialPool)
+		if err != nil {
+			return err
+		}
+		lobby := &models.Lobby{GameID: game.ID, UserID: userID, BetAmount: s.cfg.Bet}
+		if err := env.lobbies.CreateLobby(ctx, lobby); err != nil {
+			return fmt.Errorf("failed to create lobby: %w", err)
+		}
+
+		won, tries, err := s.playLobby(ctx, env, lobby.ID, s.newPlayer(skill, rng))
+		if err != nil {
+			return err
+		}
+
+		poolAfter, err := env.poolPnL(ctx, game.ID, initialPool)
+		if err != nil {
+			return err
+		}
+		user, err := env.users.GetByTelegramID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		acc.addLobby(lobbyResult{
+			won:         won,
+			tries:       tries,
+			bet:         s.cfg.Bet,
+			payout:      user.BalanceTon,
+			poolBefore:  poolBefore,
+			poolAfter:   poolAfter,
+			reservation: s.cfg.Bet * s.cfg.Multiplier,
+		})
+	}
+	acc.finishGame()
+
+	return nil
+}
+
+// playLobby делает ходы игрока, пока лобби не завершится
+func (s *Simulator) playLobby(ctx context.Context, env *environment, lobbyID uuid.UUID, p player) (bool, int, error) {
+	for tries := 1; tries <= s.cfg.MaxTries; tries++ {
+		guess := p.Guess()
+		result, err := env.lobbies.ProcessAttempt(ctx, lobbyID, guess)
+		if err != nil {
+			return false, tries, fmt.Errorf("failed to process attempt: %w", err)
+		}
+		if !slices.ContainsFunc(result, func(r int) bool { return r != 2 }) {
+			return true, tries, nil
+		}
+		p.Update(guess, result)
+	}
+	return false, s.cfg.MaxTries, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/logger"
+)
+
+func testConfig() Config {
+	return Config{
+		Skills: Skills, Games: 10, LobbiesPerGame: 5, Length: 5, MaxTries: 6,
+		Bet: 1, Multiplier: 2, Commission: 0.05, Seed: 7,
+	}
+}
+
+func TestSimulator_Run(t *testing.T) {
+	logger.Init(logger.Config{DisableConsole: true, DisableLokiFile: true})
+
+	simulator, err := NewSimulator(testConfig(), dictionary.Default())
+	if err != nil {
+		t.Fatalf("NewSimulator() error = %v", err)
+	}
+	reports, err := simulator.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(reports) != len(Skills) {
+		t.Fatalf("Run() returned %d reports, want %d", len(reports), len(Skills))
+	}
+
+	for _, r := range reports {
+		if r.Lobbies != 50 {
+			t.Errorf("%s: lobbies = %d, want 50", r.Skill, r.Lobbies)
+		}
+
+		// Сервис получает комиссию с проигранной ставки и всю ставку победителя
+		wantHouse := float64(r.Lobbies-r.Wins)*0.05 + float64(r.Wins)
+		if math.Abs(r.HouseRevenue-wantHouse) > 1e-6 {
+			t.Errorf("%s: house revenue = %v, want %v", r.Skill, r.HouseRevenue, wantHouse)
+		}
+		if math.Abs(r.TotalBets-r.TotalPayouts-r.HouseRevenue-r.CreatorPnL) > 1e-6 {
+			t.Errorf("%s: bets %v do not add up to payouts, house revenue and creator P&L", r.Skill, r.TotalBets)
+		}
+		if r.RequiredPoolMax < r.RequiredPoolP95 || r.RequiredPoolP95 < 2 {
+			t.Errorf("%s: required pool p95/max = %v/%v, want at least one reservation", r.Skill, r.RequiredPoolP95, r.RequiredPoolMax)
+		}
+	}
+
+	random, heuristic, optimal := reports[0], reports[1], reports[2]
+	if !(random.WinRate < heuristic.WinRate && heuristic.WinRate <= optimal.WinRate) {
+		t.Errorf("win rates random/heuristic/optimal = %v/%v/%v, want increasing with skill",
+			random.WinRate, heuristic.WinRate, optimal.WinRate)
+	}
+	if random.CreatorPnL <= 0 || optimal.CreatorPnL >= random.CreatorPnL {
+		t.Errorf("creator P&L random/optimal = %v/%v, want profit against random players", random.CreatorPnL, optimal.CreatorPnL)
+	}
+
+	// Одинаковый seed воспроизводит результат
+	again, _ := simulator.Run(context.Background())
+	if *again[1] != *heuristic {
+		t.Errorf("second run = %+v, want %+v", again[1], heuristic)
+	}
+}
+
+func TestRun_Output(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"-games", "2", "-lobbies", "2", "-format", "csv"}, &out); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(Skills)+1 || !strings.HasPrefix(lines[0], "skill,games,lobbies") {
+		t.Errorf("csv output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"-games", "2", "-lobbies", "2", "-skill", "optimal"}, &out); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	var result Output
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("json output: %v", err)
+	}
+	if len(result.Results) != 1 || result.Results[0].Skill != SkillOptimal {
+		t.Errorf("json results = %+v", result.Results)
+	}
+
+	for _, args := range [][]string{{"-format", "xml"}, {"-skill", "cheater"}, {"-length", "42"}} {
+		if err := run(args, &out); err == nil {
+			t.Errorf("run(%v) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// AchievementHandler представляет обработчики для достижений и серий
+type AchievementHandler struct {
+	achievementService models.AchievementService
+}
+
+// NewAchievementHandler создает новый экземпляр AchievementHandler
+func NewAchievementHandler(achievementService models.AchievementService) *AchievementHandler {
+	return &AchievementHandler{
+		achievementService: achievementService,
+	}
+}
+
+// GetAchievements возвращает достижения и серии текущего пользователя
+func (h *AchievementHandler) GetAchievements(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	summary, err := h.achievementService.GetAchievements(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// ClaimDailyReward зачисляет ежедневную награду текущему пользователю
+func (h *AchievementHandler) ClaimDailyReward(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	reward, err := h.achievementService.ClaimDailyReward(c, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrDailyRewardClaimed) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reward)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BotSecretHeader заголовок, в котором Bot API передаёт секрет, указанный при регистрации webhook
+const BotSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// BotHandler представляет обработчик webhook Telegram бота
+type BotHandler struct {
+	botService models.BotService
+	secret     string
+}
+
+// NewBotHandler создает новый экземпляр BotHandler
+func NewBotHandler(botService models.BotService, secret string) *BotHandler {
+	return &BotHandler{
+		botService: botService,
+		secret:     secret,
+	}
+}
+
+// Webhook принимает обновления от Bot API. Ошибки обработки только логируются:
+// ответ не 2xx заставил бы Telegram повторять то же обновление
+func (h *BotHandler) Webhook(c *gin.Context) {
+	if h.secret == "" || subtle.ConstantTimeCompare([]byte(c.GetHeader(BotSecretHeader)), []byte(h.secret)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid secret token"})
+		return
+	}
+
+	var update models.BotUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.botService.HandleUpdate(c, &update); err != nil {
+		logger.Log.Warn("Failed to handle bot update",
+			zap.Int64("update_id", update.UpdateID),
+			zap.Error(err))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
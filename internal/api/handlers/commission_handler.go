@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCommissionReportRange период отчёта по комиссии по умолчанию
+const defaultCommissionReportRange = 30 * 24 * time.Hour
+
+// CommissionHandler представляет обработчики для отчётов по комиссии сервиса
+type CommissionHandler struct {
+	commissionService models.CommissionService
+}
+
+// NewCommissionHandler создает новый экземпляр CommissionHandler
+func NewCommissionHandler(commissionService models.CommissionService) *CommissionHandler {
+	return &CommissionHandler{
+		commissionService: commissionService,
+	}
+}
+
+// GetHouseAccounts возвращает счета сервиса, на которые зачисляется комиссия
+func (h *CommissionHandler) GetHouseAccounts(c *gin.Context) {
+	accounts, err := h.commissionService.GetHouseAccounts(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"accounts":     accounts,
+		"default_rate": h.commissionService.GetCommissionRate(),
+	})
+}
+
+// GetReport возвращает заработанную комиссию по периодам.
+// Параметры: period (day, week, month), from и to (RFC 3339 или YYYY-MM-DD), по умолчанию - последние 30 дней по дням
+func (h *CommissionHandler) GetReport(c *gin.Context) {
+	period := c.DefaultQuery("period", models.CommissionPeriodDay)
+
+	to := time.Now()
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseReportTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to: use RFC 3339 or YYYY-MM-DD"})
+			return
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultCommissionReportRange)
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseReportTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from: use RFC 3339 or YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+
+	report, err := h.commissionService.GetReport(c, period, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"period": period,
+		"from":   from,
+		"to":     to,
+		"report": report,
+	})
+}
+
+// parseReportTime разбирает время в формате RFC 3339 или дату YYYY-MM-DD (по UTC)
+func parseReportTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
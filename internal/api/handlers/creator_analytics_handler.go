@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// defaultAnalyticsRange период аналитики по умолчанию
+const defaultAnalyticsRange = 30 * 24 * time.Hour
+
+// CreatorAnalyticsHandler представляет обработчики для аналитики создателей
+type CreatorAnalyticsHandler struct {
+	analyticsService models.CreatorAnalyticsService
+}
+
+// NewCreatorAnalyticsHandler создает новый экземпляр CreatorAnalyticsHandler
+func NewCreatorAnalyticsHandler(analyticsService models.CreatorAnalyticsService) *CreatorAnalyticsHandler {
+	return &CreatorAnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetGameAnalytics возвращает аналитику игры (только для её создателя).
+// Параметры: period (day, week, month), from и to (RFC 3339 или YYYY-MM-DD), по умолчанию - последние 30 дней по дням
+func (h *CreatorAnalyticsHandler) GetGameAnalytics(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	period, from, to, err := parseAnalyticsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	analytics, err := h.analyticsService.GetGameAnalytics(c, id, userID, period, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrGameNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+		case errors.Is(err, models.ErrNotGameCreator):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetCreatorAnalytics возвращает аналитику всех игр текущего пользователя.
+// Параметры те же, что у GetGameAnalytics
+func (h *CreatorAnalyticsHandler) GetCreatorAnalytics(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	period, from, to, err := parseAnalyticsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	analytics, err := h.analyticsService.GetCreatorAnalytics(c, userID, period, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// parseAnalyticsRange разбирает период группировки и интервал аналитики из параметров запроса
+func parseAnalyticsRange(c *gin.Context) (period string, from, to time.Time, err error) {
+	period = c.DefaultQuery("period", models.CommissionPeriodDay)
+
+	to = time.Now()
+	if value := c.Query("to"); value != "" {
+		if to, err = parseReportTime(value); err != nil {
+			return period, from, to, errors.New("invalid to: use RFC 3339 or YYYY-MM-DD")
+		}
+	}
+	from = to.Add(-defaultAnalyticsRange)
+	if value := c.Query("from"); value != "" {
+		if from, err = parseReportTime(value); err != nil {
+			return period, from, to, errors.New("invalid from: use RFC 3339 or YYYY-MM-DD")
+		}
+	}
+
+	return period, from, to, nil
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DuelHandler представляет обработчики для дуэлей
+type DuelHandler struct {
+	duelService models.DuelService
+}
+
+// NewDuelHandler создает новый экземпляр DuelHandler
+func NewDuelHandler(duelService models.DuelService) *DuelHandler {
+	return &DuelHandler{
+		duelService: duelService,
+	}
+}
+
+// CreateDuel создает вызов на дуэль
+func (h *DuelHandler) CreateDuel(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var input struct {
+		OpponentID uint64  `json:"opponent_id"` // 0 - открытый вызов по ссылке
+		Word       string  `json:"word" binding:"required"`
+		Stake      float64 `json:"stake" binding:"required,gt=0"`
+		Currency   string  `json:"currency" binding:"required"`
+		MaxTries   int     `json:"max_tries"`
+		TimeLimit  int     `json:"time_limit"` // Минуты
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	duel := &models.Duel{
+		ChallengerID: userID,
+		OpponentID:   input.OpponentID,
+		Stake:        input.Stake,
+		Currency:     input.Currency,
+		MaxTries:     input.MaxTries,
+		TimeLimit:    input.TimeLimit,
+	}
+
+	if err := h.duelService.CreateDuel(c, duel, input.Word); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, duel)
+}
+
+// GetUserDuels возвращает дуэли текущего пользователя
+func (h *DuelHandler) GetUserDuels(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	limit, offset := getPagination(c)
+
+	duels, err := h.duelService.GetUserDuels(c, userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, duels)
+}
+
+// GetDuel возвращает дуэль и попытки текущего пользователя
+func (h *DuelHandler) GetDuel(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duel ID"})
+		return
+	}
+
+	duel, err := h.duelService.GetDuel(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{"duel": duel}
+	if duel.IsParticipant(userID) {
+		attempts, err := h.duelService.GetAttempts(c, id, userID)
+		if err == nil {
+			response["attempts"] = attempts
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetInvite возвращает вызов по коду приглашения (параметр startapp)
+func (h *DuelHandler) GetInvite(c *gin.Context) {
+	duel, err := h.duelService.GetDuelByInvite(c, c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, duel)
+}
+
+// AcceptDuel принимает вызов по коду приглашения
+func (h *DuelHandler) AcceptDuel(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var input struct {
+		Word string `json:"word" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	duel, err := h.duelService.AcceptDuel(c, c.Param("code"), userID, input.Word)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, duel)
+}
+
+// CancelDuel отменяет или отклоняет непринятый вызов
+func (h *DuelHandler) CancelDuel(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duel ID"})
+		return
+	}
+
+	if err := h.duelService.CancelDuel(c, id, userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "duel canceled"})
+}
+
+// MakeAttempt обрабатывает попытку игрока в дуэли
+func (h *DuelHandler) MakeAttempt(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duel ID"})
+		return
+	}
+
+	var input struct {
+		Word string `json:"word" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attempt, err := h.duelService.ProcessAttempt(c, id, userID, input.Word)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	duel, err := h.duelService.GetDuel(c, id)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"attempt": attempt})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attempt": attempt,
+		"duel":    duel,
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// EventHandler представляет обработчик просмотра и повтора недоставленных доменных событий
+type EventHandler struct {
+	eventService models.EventService
+}
+
+// NewEventHandler создает новый экземпляр EventHandler
+func NewEventHandler(eventService models.EventService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// GetDeadLetters возвращает события, доставка которых прекращена после исчерпания попыток (только для администраторов)
+func (h *EventHandler) GetDeadLetters(c *gin.Context) {
+	limit, offset := getPagination(c)
+
+	events, err := h.eventService.GetDeadLetters(c, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// RetryDeadLetter возвращает событие в очередь доставки (только для администраторов)
+func (h *EventHandler) RetryDeadLetter(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	if err := h.eventService.RetryDeadLetter(c, eventID); err != nil {
+		if errors.Is(err, models.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "event requeued"})
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FollowHandler представляет обработчики для подписок на создателей, закладок и ленты игр
+type FollowHandler struct {
+	followService models.FollowService
+}
+
+// NewFollowHandler создает новый экземпляр FollowHandler
+func NewFollowHandler(followService models.FollowService) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+	}
+}
+
+// followErrorStatus возвращает HTTP-статус ошибки подписок и закладок
+func followErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrUserNotFound), errors.Is(err, models.ErrGameNotFound),
+		errors.Is(err, models.ErrFollowNotFound), errors.Is(err, models.ErrBookmarkNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrCannotFollowSelf):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// gameList формирует список игр для ответа
+func gameList(games []*models.Game) []gin.H {
+	result := make([]gin.H, 0, len(games))
+	for _, game := range games {
+		result = append(result, gameListItem(game))
+	}
+	return result
+}
+
+// FollowCreator подписывает текущего пользователя на создателя
+func (h *FollowHandler) FollowCreator(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	creatorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	// Тело запроса необязательно: без него уведомления выключены
+	var input struct {
+		Notify bool `json:"notify"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	follow, err := h.followService.FollowCreator(c, userID, creatorID, input.Notify)
+	if err != nil {
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, follow)
+}
+
+// UnfollowCreator отменяет подписку текущего пользователя на создателя
+func (h *FollowHandler) UnfollowCreator(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	creatorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.followService.UnfollowCreator(c, userID, creatorID); err != nil {
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "unfollowed"})
+}
+
+// GetFollowing возвращает подписки текущего пользователя
+func (h *FollowHandler) GetFollowing(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	limit, offset := getPagination(c)
+
+	follows, err := h.followService.GetFollowing(c, userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, follows)
+}
+
+// BookmarkGame сохраняет игру в закладки текущего пользователя
+func (h *FollowHandler) BookmarkGame(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	gameID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	if err := h.followService.BookmarkGame(c, userID, gameID); err != nil {
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "game bookmarked"})
+}
+
+// RemoveBookmark удаляет игру из закладок текущего пользователя
+func (h *FollowHandler) RemoveBookmark(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	gameID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	if err := h.followService.RemoveBookmark(c, userID, gameID); err != nil {
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "bookmark removed"})
+}
+
+// GetBookmarks возвращает игры из закладок текущего пользователя
+func (h *FollowHandler) GetBookmarks(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	limit, offset := getPagination(c)
+
+	games, err := h.followService.GetBookmarks(c, userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Закладки могут указывать на уже закрытые игры, поэтому статус возвращается
+	result := gameList(games)
+	for i, game := range games {
+		result[i]["status"] = game.Status
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetFeed возвращает новые игры отслеживаемых создателей и рекомендации для текущего пользователя
+func (h *FollowHandler) GetFeed(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	limit, _ := getPagination(c)
+
+	feed, err := h.followService.GetFeed(c, userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"following":   gameList(feed.Following),
+		"recommended": gameList(feed.Recommended),
+	})
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// respondGameAccessError отвечает на ошибку проверки доступа к приватной игре
+func respondGameAccessError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrGameAccessDenied) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// gameAccessResponse формирует настройки доступа к игре для создателя
+func (h *GameHandler) gameAccessResponse(game *models.Game) gin.H {
+	access := gin.H{"visibility": game.Visibility}
+	if game.IsPrivate() {
+		access["invite_token"] = game.InviteToken
+		access["invite_link"] = h.gameService.InviteLink(game)
+		access["start_param"] = game.StartParam()
+		access["allowed_user_ids"] = game.AllowedUserIDs
+		access["allowed_chat_id"] = game.AllowedChatID
+	}
+	return access
+}
+
+// GetInviteGame возвращает приватную игру по токену приглашения (параметру startapp)
+func (h *GameHandler) GetInviteGame(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	token := c.Param("token")
+	game, err := h.gameService.GetGameByInvite(c, token)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+		return
+	}
+
+	if err := h.gameService.CheckAccess(c, game, userID, token); err != nil {
+		respondGameAccessError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":                game.ID,
+		"short_id":          game.ShortID,
+		"creator_id":        game.CreatorID,
+		"word":              gin.H{"length": game.Length},
+		"difficulty":        game.Difficulty,
+		"difficulty_score":  game.DifficultyScore,
+		"max_tries":         game.MaxTries,
+		"time_limit":        game.TimeLimit,
+		"title":             game.Title,
+		"description":       game.Description,
+		"min_bet":           game.MinBet,
+		"max_bet":           game.MaxBet,
+		"reward_multiplier": game.RewardMultiplier,
+		"currency":          game.Currency,
+		"available_pool":    game.GetAvailableRewardPool(),
+		"status":            game.Status,
+		"visibility":        game.Visibility,
+		"invite_token":      models.ParseGameStartParam(token),
+		"created_at":        game.CreatedAt,
+	})
+}
+
+// UpdateGameAccess изменяет видимость игры, белый список и группу (только для создателя)
+func (h *GameHandler) UpdateGameAccess(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	var input models.GameAccess
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	game, err := h.gameService.UpdateGameAccess(c, id, userID, input)
+	if err != nil {
+		if errors.Is(err, models.ErrGameNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.gameAccessResponse(game))
+}
+
+// RotateInviteToken выпускает новую ссылку-приглашение, старая перестаёт действовать
+func (h *GameHandler) RotateInviteToken(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	game, err := h.gameService.RotateInviteToken(c, id, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrGameNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.gameAccessResponse(game))
+}
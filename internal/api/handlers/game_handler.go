@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/TakuroBreath/wordle/internal/api/middleware"
 	"github.com/TakuroBreath/wordle/internal/models"
@@ -42,16 +45,26 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 	}
 
 	var input struct {
-		Word             string  `json:"word" binding:"required"`
-		Difficulty       string  `json:"difficulty" binding:"required"`
-		MaxTries         int     `json:"max_tries" binding:"required,min=1,max=20"`
-		TimeLimit        int     `json:"time_limit" binding:"min=1,max=60"` // минуты
-		Title            string  `json:"title" binding:"required"`
-		Description      string  `json:"description"`
-		MinBet           float64 `json:"min_bet" binding:"required,gt=0"`
-		MaxBet           float64 `json:"max_bet" binding:"required,gt=0"`
-		RewardMultiplier float64 `json:"reward_multiplier" binding:"required,gte=1"`
-		Currency         string  `json:"currency" binding:"required"`
+		Word              string     `json:"word" binding:"required"`
+		Difficulty        string     `json:"difficulty"`
+		MaxTries          int        `json:"max_tries" binding:"required,min=1,max=20"`
+		TimeLimit         int        `json:"time_limit" binding:"min=1,max=60"` // минуты
+		Title             string     `json:"title" binding:"required"`
+		Description       string     `json:"description"`
+		MinBet            float64    `json:"min_bet" binding:"required,gt=0"`
+		MaxBet            float64    `json:"max_bet" binding:"required,gt=0"`
+		RewardMultiplier  float64    `json:"reward_multiplier" binding:"required,gte=1"`
+		Currency          string     `json:"currency" binding:"required"`
+		Visibility        string     `json:"visibility"`                          // public (по умолчанию) или private
+		AllowedUserIDs    []uint64   `json:"allowed_user_ids"`                    // Белый список Telegram ID (для private)
+		AllowedChatID     int64      `json:"allowed_chat_id"`                     // Telegram-группа, участникам которой открыта игра (для private)
+		StartsAt          *time.Time `json:"starts_at"`                           // Запланированное время активации (RFC 3339)
+		EndsAt            *time.Time `json:"ends_at"`                             // Запланированное время закрытия (RFC 3339)
+		MaxDailyLoss      float64    `json:"max_daily_loss" binding:"gte=0"`      // Лимит убытка за день (0 - без лимита)
+		MaxActiveLobbies  int        `json:"max_active_lobbies" binding:"gte=0"`  // Лимит одновременных лобби (0 - без лимита)
+		PoolFloor         float64    `json:"pool_floor" binding:"gte=0"`          // Неснижаемый остаток пула
+		OddsMinMultiplier float64    `json:"odds_min_multiplier" binding:"gte=0"` // Нижняя граница автоподстройки мультипликатора
+		OddsMaxMultiplier float64    `json:"odds_max_multiplier" binding:"gte=0"` // Верхняя граница автоподстройки (0 - выключено)
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -70,8 +83,8 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 		return
 	}
 
-	// Проверка валидности сложности
-	if input.Difficulty != "easy" && input.Difficulty != "medium" && input.Difficulty != "hard" {
+	// Проверка валидности сложности. Без сложности игра получает уровень по оценке слова
+	if input.Difficulty != "" && input.Difficulty != "easy" && input.Difficulty != "medium" && input.Difficulty != "hard" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty, must be easy, medium or hard"})
 		return
 	}
@@ -86,20 +99,30 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 	depositAmount := input.MaxBet * input.RewardMultiplier
 
 	game := &models.Game{
-		CreatorID:        userID,
-		Word:             input.Word,
-		Length:           len([]rune(input.Word)),
-		Difficulty:       input.Difficulty,
-		MaxTries:         input.MaxTries,
-		TimeLimit:        input.TimeLimit,
-		Title:            input.Title,
-		Description:      input.Description,
-		MinBet:           input.MinBet,
-		MaxBet:           input.MaxBet,
-		RewardMultiplier: input.RewardMultiplier,
-		DepositAmount:    depositAmount,
-		Currency:         input.Currency,
-		Status:           models.GameStatusPending,
+		CreatorID:         userID,
+		Word:              input.Word,
+		Length:            len([]rune(input.Word)),
+		Difficulty:        input.Difficulty,
+		MaxTries:          input.MaxTries,
+		TimeLimit:         input.TimeLimit,
+		Title:             input.Title,
+		Description:       input.Description,
+		MinBet:            input.MinBet,
+		MaxBet:            input.MaxBet,
+		RewardMultiplier:  input.RewardMultiplier,
+		DepositAmount:     depositAmount,
+		Currency:          input.Currency,
+		Status:            models.GameStatusPending,
+		Visibility:        input.Visibility,
+		AllowedUserIDs:    input.AllowedUserIDs,
+		AllowedChatID:     input.AllowedChatID,
+		StartsAt:          input.StartsAt,
+		EndsAt:            input.EndsAt,
+		MaxDailyLoss:      input.MaxDailyLoss,
+		MaxActiveLobbies:  input.MaxActiveLobbies,
+		PoolFloor:         input.PoolFloor,
+		OddsMinMultiplier: input.OddsMinMultiplier,
+		OddsMaxMultiplier: input.OddsMaxMultiplier,
 	}
 
 	if err := h.gameService.CreateGame(c, game); err != nil {
@@ -117,6 +140,7 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 			"creator_id":        game.CreatorID,
 			"word_length":       game.Length,
 			"difficulty":        game.Difficulty,
+			"difficulty_score":  game.DifficultyScore,
 			"max_tries":         game.MaxTries,
 			"time_limit":        game.TimeLimit,
 			"title":             game.Title,
@@ -127,6 +151,10 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 			"deposit_amount":    game.DepositAmount,
 			"currency":          game.Currency,
 			"status":            game.Status,
+			"starts_at":         game.StartsAt,
+			"ends_at":           game.EndsAt,
+			"access":            h.gameAccessResponse(game),
+			"odds":              game.Odds,
 			"message":           "Game created. Please deposit to activate.",
 		})
 		return
@@ -138,6 +166,7 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 		"creator_id":        game.CreatorID,
 		"word_length":       game.Length,
 		"difficulty":        game.Difficulty,
+		"difficulty_score":  game.DifficultyScore,
 		"max_tries":         game.MaxTries,
 		"time_limit":        game.TimeLimit,
 		"title":             game.Title,
@@ -148,6 +177,10 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 		"deposit_amount":    game.DepositAmount,
 		"currency":          game.Currency,
 		"status":            game.Status,
+		"starts_at":         game.StartsAt,
+		"ends_at":           game.EndsAt,
+		"access":            h.gameAccessResponse(game),
+		"odds":              game.Odds,
 		"payment":           paymentInfo,
 	})
 }
@@ -179,33 +212,51 @@ func (h *GameHandler) GetGame(c *gin.Context) {
 	userID, exists := middleware.GetCurrentUserID(c)
 	isCreator := exists && userID == game.CreatorID
 
+	// Приватная игра видна только тем, у кого есть доступ
+	if game.IsPrivate() && h.gameService.CheckAccess(c, game, userID, c.Query("invite")) != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+		return
+	}
+
 	wordInfo := gin.H{"length": game.Length}
 	if isCreator {
 		wordInfo["word"] = game.Word
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":                game.ID,
-		"short_id":          game.ShortID,
-		"creator_id":        game.CreatorID,
-		"word":              wordInfo,
-		"difficulty":        game.Difficulty,
-		"max_tries":         game.MaxTries,
-		"time_limit":        game.TimeLimit,
-		"title":             game.Title,
-		"description":       game.Description,
-		"min_bet":           game.MinBet,
-		"max_bet":           game.MaxBet,
-		"reward_multiplier": game.RewardMultiplier,
-		"deposit_amount":    game.DepositAmount,
-		"currency":          game.Currency,
-		"reward_pool_ton":   game.RewardPoolTon,
-		"reward_pool_usdt":  game.RewardPoolUsdt,
-		"reserved_amount":   game.ReservedAmount,
-		"available_pool":    game.GetAvailableRewardPool(),
-		"status":            game.Status,
-		"created_at":        game.CreatedAt,
-	})
+	response := gin.H{
+		"id":                 game.ID,
+		"short_id":           game.ShortID,
+		"creator_id":         game.CreatorID,
+		"word":               wordInfo,
+		"difficulty":         game.Difficulty,
+		"difficulty_score":   game.DifficultyScore,
+		"max_tries":          game.MaxTries,
+		"time_limit":         game.TimeLimit,
+		"title":              game.Title,
+		"description":        game.Description,
+		"min_bet":            game.MinBet,
+		"max_bet":            game.MaxBet,
+		"reward_multiplier":  game.RewardMultiplier,
+		"deposit_amount":     game.DepositAmount,
+		"currency":           game.Currency,
+		"reward_pool_ton":    game.RewardPoolTon,
+		"reward_pool_usdt":   game.RewardPoolUsdt,
+		"reserved_amount":    game.ReservedAmount,
+		"available_pool":     game.GetAvailableRewardPool(),
+		"creator_reputation": game.CreatorReputation,
+		"status":             game.Status,
+		"visibility":         game.Visibility,
+		"starts_at":          game.StartsAt,
+		"ends_at":            game.EndsAt,
+		"created_at":         game.CreatedAt,
+	}
+
+	if isCreator {
+		response["access"] = h.gameAccessResponse(game)
+		response["pool"] = gamePoolResponse(game)
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetPaymentInfo получает информацию для оплаты депозита игры
@@ -258,9 +309,10 @@ func (h *GameHandler) JoinGame(c *gin.Context) {
 	}
 
 	var input struct {
-		GameID     string  `json:"game_id" binding:"required"` // Может быть UUID или short_id
-		BetAmount  float64 `json:"bet_amount" binding:"required,gt=0"`
-		UseBalance bool    `json:"use_balance"` // Использовать баланс вместо блокчейн платежа
+		GameID      string  `json:"game_id" binding:"required"` // Может быть UUID или short_id
+		BetAmount   float64 `json:"bet_amount" binding:"required,gt=0"`
+		UseBalance  bool    `json:"use_balance"`  // Использовать баланс вместо блокчейн платежа
+		InviteToken string  `json:"invite_token"` // Токен или параметр startapp для приватной игры
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -284,11 +336,17 @@ func (h *GameHandler) JoinGame(c *gin.Context) {
 		return
 	}
 
-	if game.Status != models.GameStatusActive {
+	if !game.AcceptsBets(time.Now()) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "game is not active"})
 		return
 	}
 
+	// Проверяем доступ к приватной игре
+	if err := h.gameService.CheckAccess(c, game, userID, input.InviteToken); err != nil {
+		respondGameAccessError(c, err)
+		return
+	}
+
 	// Проверяем ставку
 	if input.BetAmount < game.MinBet || input.BetAmount > game.MaxBet {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -324,12 +382,17 @@ func (h *GameHandler) JoinGame(c *gin.Context) {
 	if input.UseBalance {
 		// Создаём лобби с оплатой с баланса
 		lobby := &models.Lobby{
-			GameID:    game.ID,
-			UserID:    userID,
-			BetAmount: input.BetAmount,
+			GameID:      game.ID,
+			UserID:      userID,
+			BetAmount:   input.BetAmount,
+			InviteToken: input.InviteToken,
 		}
 
 		if err := h.lobbyService.CreateLobby(c, lobby); err != nil {
+			if errors.Is(err, models.ErrGameAccessDenied) {
+				respondGameAccessError(c, err)
+				return
+			}
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -349,7 +412,7 @@ func (h *GameHandler) JoinGame(c *gin.Context) {
 	}
 
 	// Генерируем платёжную информацию для блокчейн оплаты
-	paymentInfo, err := h.lobbyService.GetJoinPaymentInfo(c, game.ShortID, userID, input.BetAmount)
+	paymentInfo, err := h.lobbyService.GetJoinPaymentInfo(c, game.ShortID, userID, input.BetAmount, input.InviteToken)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -367,28 +430,21 @@ func (h *GameHandler) JoinGame(c *gin.Context) {
 	})
 }
 
-// GetActiveGames получает список активных игр
+// GetActiveGames получает страницу активных игр. Курсор следующей страницы возвращается в заголовке X-Next-Cursor
 func (h *GameHandler) GetActiveGames(c *gin.Context) {
-	limit := 10
-	offset := 0
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
-			limit = val
-		}
-	}
+	limit, cursor := getCursorPagination(c)
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
-			offset = val
-		}
-	}
-
-	games, err := h.gameService.GetActiveGames(c, limit, offset)
+	page, err := h.gameService.GetActiveGames(c, limit, cursor)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get active games"})
 		return
 	}
+	setNextCursor(c, page.NextCursor)
+	games := page.Games
 
 	// Получаем ID текущего пользователя для фильтрации своих игр
 	userID, _ := middleware.GetCurrentUserID(c)
@@ -400,28 +456,37 @@ func (h *GameHandler) GetActiveGames(c *gin.Context) {
 			continue
 		}
 
-		result = append(result, gin.H{
-			"id":                game.ID,
-			"short_id":          game.ShortID,
-			"creator_id":        game.CreatorID,
-			"word_length":       game.Length,
-			"difficulty":        game.Difficulty,
-			"max_tries":         game.MaxTries,
-			"time_limit":        game.TimeLimit,
-			"title":             game.Title,
-			"description":       game.Description,
-			"min_bet":           game.MinBet,
-			"max_bet":           game.MaxBet,
-			"reward_multiplier": game.RewardMultiplier,
-			"currency":          game.Currency,
-			"available_pool":    game.GetAvailableRewardPool(),
-			"created_at":        game.CreatedAt,
-		})
+		result = append(result, gameListItem(game))
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
+// gameListItem формирует элемент списка игр без загаданного слова
+func gameListItem(game *models.Game) gin.H {
+	return gin.H{
+		"id":                 game.ID,
+		"short_id":           game.ShortID,
+		"creator_id":         game.CreatorID,
+		"word_length":        game.Length,
+		"difficulty":         game.Difficulty,
+		"difficulty_score":   game.DifficultyScore,
+		"max_tries":          game.MaxTries,
+		"time_limit":         game.TimeLimit,
+		"title":              game.Title,
+		"description":        game.Description,
+		"min_bet":            game.MinBet,
+		"max_bet":            game.MaxBet,
+		"reward_multiplier":  game.RewardMultiplier,
+		"currency":           game.Currency,
+		"available_pool":     game.GetAvailableRewardPool(),
+		"plays":              game.Plays,
+		"win_rate":           game.WinRate(),
+		"creator_reputation": game.CreatorReputation,
+		"created_at":         game.CreatedAt,
+	}
+}
+
 // GetUserGames получает список игр пользователя
 func (h *GameHandler) GetUserGames(c *gin.Context) {
 	userID, exists := middleware.GetCurrentUserID(c)
@@ -459,6 +524,7 @@ func (h *GameHandler) GetUserGames(c *gin.Context) {
 			"word":              game.Word, // Создатель видит слово
 			"word_length":       game.Length,
 			"difficulty":        game.Difficulty,
+			"difficulty_score":  game.DifficultyScore,
 			"max_tries":         game.MaxTries,
 			"time_limit":        game.TimeLimit,
 			"title":             game.Title,
@@ -585,65 +651,145 @@ func (h *GameHandler) DeactivateGame(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Game deactivated successfully"})
 }
 
-// SearchGames осуществляет поиск игр по параметрам
+// CloseGame закрывает игру: новые ставки не принимаются, остаток пула возвращается
+// создателю после завершения активных лобби
+func (h *GameHandler) CloseGame(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	game, err := h.gameService.CloseGame(c, id, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrGameNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":              game.ID,
+		"status":          game.Status,
+		"reserved_amount": game.ReservedAmount,
+		"message":         "Game is closing. The remaining pool is refunded once active games finish.",
+	})
+}
+
+// SearchGames осуществляет поиск игр по параметрам.
+// Курсор следующей страницы возвращается в заголовке X-Next-Cursor и действителен для той же сортировки
 func (h *GameHandler) SearchGames(c *gin.Context) {
-	minBet := 0.0
-	maxBet := 1000000.0
-	difficulty := ""
-	limit := 10
-	offset := 0
+	filter := models.GameSearchFilter{MaxBet: 1000000.0}
 
 	if minBetStr := c.Query("min_bet"); minBetStr != "" {
 		if val, err := strconv.ParseFloat(minBetStr, 64); err == nil && val > 0 {
-			minBet = val
+			filter.MinBet = val
 		}
 	}
 
 	if maxBetStr := c.Query("max_bet"); maxBetStr != "" {
 		if val, err := strconv.ParseFloat(maxBetStr, 64); err == nil && val > 0 {
-			maxBet = val
+			filter.MaxBet = val
 		}
 	}
 
-	difficulty = c.Query("difficulty")
+	filter.Difficulty = c.Query("difficulty")
 
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
-			limit = val
+	if scoreStr := c.Query("min_difficulty_score"); scoreStr != "" {
+		if val, err := strconv.ParseFloat(scoreStr, 64); err == nil && val > 0 {
+			filter.MinDifficultyScore = val
 		}
 	}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
-			offset = val
+	if scoreStr := c.Query("max_difficulty_score"); scoreStr != "" {
+		if val, err := strconv.ParseFloat(scoreStr, 64); err == nil && val > 0 {
+			filter.MaxDifficultyScore = val
+		}
+	}
+
+	if currency := c.Query("currency"); currency != "" {
+		if currency != models.CurrencyTON && currency != models.CurrencyUSDT {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "currency must be TON or USDT"})
+			return
+		}
+		filter.Currency = currency
+	}
+
+	if lengthStr := c.Query("word_length"); lengthStr != "" {
+		if val, err := strconv.Atoi(lengthStr); err == nil && val > 0 {
+			filter.WordLength = val
+		}
+	}
+
+	if multiplierStr := c.Query("min_multiplier"); multiplierStr != "" {
+		if val, err := strconv.ParseFloat(multiplierStr, 64); err == nil && val > 0 {
+			filter.MinMultiplier = val
+		}
+	}
+
+	if multiplierStr := c.Query("max_multiplier"); multiplierStr != "" {
+		if val, err := strconv.ParseFloat(multiplierStr, 64); err == nil && val > 0 {
+			filter.MaxMultiplier = val
 		}
 	}
 
-	games, err := h.gameService.SearchGames(c, minBet, maxBet, difficulty, limit, offset)
+	if timeLimitStr := c.Query("min_time_limit"); timeLimitStr != "" {
+		if val, err := strconv.Atoi(timeLimitStr); err == nil && val > 0 {
+			filter.MinTimeLimit = val
+		}
+	}
+
+	if timeLimitStr := c.Query("max_time_limit"); timeLimitStr != "" {
+		if val, err := strconv.Atoi(timeLimitStr); err == nil && val > 0 {
+			filter.MaxTimeLimit = val
+		}
+	}
+
+	if poolStr := c.Query("min_pool_bets"); poolStr != "" {
+		if val, err := strconv.ParseFloat(poolStr, 64); err == nil && val > 0 {
+			filter.MinPoolBets = val
+		}
+	}
+
+	if creatorStr := c.Query("creator_id"); creatorStr != "" {
+		if val, err := strconv.ParseUint(creatorStr, 10, 64); err == nil {
+			filter.CreatorID = val
+		}
+	}
+
+	filter.Query = strings.TrimSpace(c.Query("q"))
+
+	filter.Sort = c.DefaultQuery("sort", models.GameSortNewest)
+	if !models.IsValidGameSort(filter.Sort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be newest, pool, popular, win_rate or reputation"})
+		return
+	}
+
+	limit, cursor := getCursorPagination(c)
+
+	page, err := h.gameService.SearchGames(c, filter, limit, cursor)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search games"})
 		return
 	}
+	setNextCursor(c, page.NextCursor)
+	games := page.Games
 
 	result := make([]gin.H, 0, len(games))
 	for _, game := range games {
-		result = append(result, gin.H{
-			"id":                game.ID,
-			"short_id":          game.ShortID,
-			"creator_id":        game.CreatorID,
-			"word_length":       game.Length,
-			"difficulty":        game.Difficulty,
-			"max_tries":         game.MaxTries,
-			"time_limit":        game.TimeLimit,
-			"title":             game.Title,
-			"description":       game.Description,
-			"min_bet":           game.MinBet,
-			"max_bet":           game.MaxBet,
-			"reward_multiplier": game.RewardMultiplier,
-			"currency":          game.Currency,
-			"available_pool":    game.GetAvailableRewardPool(),
-			"created_at":        game.CreatedAt,
-		})
+		result = append(result, gameListItem(game))
 	}
 
 	c.JSON(http.StatusOK, result)
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetGameOdds возвращает создателю оценку справедливого мультипликатора и ожидаемого дохода игры
+func (h *GameHandler) GetGameOdds(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	odds, err := h.gameService.GetGameOdds(c, id, userID)
+	if err != nil {
+		respondGamePoolError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, odds)
+}
+
+// SetOddsAutoAdjust задаёт границы автоподстройки мультипликатора (max_multiplier = 0 выключает автоподстройку)
+func (h *GameHandler) SetOddsAutoAdjust(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	var input models.OddsAdjustRule
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	game, err := h.gameService.SetOddsAutoAdjust(c, id, userID, input)
+	if err != nil {
+		respondGamePoolError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":                game.ID,
+		"reward_multiplier": game.RewardMultiplier,
+		"auto_adjust": models.OddsAdjustRule{
+			MinMultiplier: game.OddsMinMultiplier,
+			MaxMultiplier: game.OddsMaxMultiplier,
+		},
+	})
+}
+
+// AnalyzeWord оценивает сложность слова до создания игры
+func (h *GameHandler) AnalyzeWord(c *gin.Context) {
+	var input struct {
+		Word string `json:"word" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	difficulty, err := h.gameService.AnalyzeWord(c, input.Word)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, difficulty)
+}
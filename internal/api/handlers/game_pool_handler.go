@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// respondGamePoolError отвечает на ошибку операции с пулом игры
+func respondGamePoolError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrGameNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// gamePoolResponse формирует состояние пула игры для создателя
+func gamePoolResponse(game *models.Game) gin.H {
+	return gin.H{
+		"id":               game.ID,
+		"currency":         game.Currency,
+		"reward_pool_ton":  game.RewardPoolTon,
+		"reward_pool_usdt": game.RewardPoolUsdt,
+		"reserved_amount":  game.ReservedAmount,
+		"available_pool":   game.GetAvailableRewardPool(),
+		"withdrawable":     game.GetWithdrawablePool(),
+		"auto_top_up": models.AutoTopUpRule{
+			Threshold: game.AutoTopUpThreshold,
+			Amount:    game.AutoTopUpAmount,
+		},
+		"risk_limits": models.GameRiskLimits{
+			MaxDailyLoss:     game.MaxDailyLoss,
+			MaxActiveLobbies: game.MaxActiveLobbies,
+			PoolFloor:        game.PoolFloor,
+		},
+		"daily_loss": game.GetDailyLoss(time.Now()),
+	}
+}
+
+// WithdrawFromPool выводит часть пула активной игры на баланс создателя
+func (h *GameHandler) WithdrawFromPool(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	var input struct {
+		Amount float64 `json:"amount" binding:"required,gt=0"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	game, err := h.gameService.WithdrawFromPool(c, id, userID, input.Amount)
+	if err != nil {
+		respondGamePoolError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gamePoolResponse(game))
+}
+
+// SetAutoTopUp задаёт правило автопополнения пула (amount = 0 выключает автопополнение)
+func (h *GameHandler) SetAutoTopUp(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	var input models.AutoTopUpRule
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	game, err := h.gameService.SetAutoTopUp(c, id, userID, input)
+	if err != nil {
+		respondGamePoolError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gamePoolResponse(game))
+}
+
+// GetPoolPnL возвращает сводку доходов и расходов создателя по пулу игры
+func (h *GameHandler) GetPoolPnL(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	pnl, err := h.gameService.GetPoolPnL(c, id, userID)
+	if err != nil {
+		respondGamePoolError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pnl)
+}
+
+// UpdateRiskLimits задаёт лимиты риска игры: убыток за день, число одновременных лобби, нижнюю границу пула
+func (h *GameHandler) UpdateRiskLimits(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	var input models.GameRiskLimits
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	game, err := h.gameService.UpdateRiskLimits(c, id, userID, input)
+	if err != nil {
+		respondGamePoolError(c, err)
+		return
+	}
+
+	response := gamePoolResponse(game)
+	response["status"] = game.Status
+	c.JSON(http.StatusOK, response)
+}
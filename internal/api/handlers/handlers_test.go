@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/TakuroBreath/wordle/internal/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -351,6 +353,56 @@ func TestErrorResponse(t *testing.T) {
 	}
 }
 
+// stubBotService запоминает полученные обновления бота
+type stubBotService struct {
+	updates []*models.BotUpdate
+}
+
+func (s *stubBotService) HandleUpdate(ctx context.Context, update *models.BotUpdate) error {
+	s.updates = append(s.updates, update)
+	return nil
+}
+
+func (s *stubBotService) RegisterWebhook(ctx context.Context, url, secret string) error {
+	return nil
+}
+
+func TestBotWebhookSecret(t *testing.T) {
+	bot := &stubBotService{}
+	router := setupTestRouter()
+	router.POST("/webhook", NewBotHandler(bot, "secret").Webhook)
+
+	tests := []struct {
+		name           string
+		secret         string
+		expectedStatus int
+	}{
+		{"без секрета", "", http.StatusUnauthorized},
+		{"неверный секрет", "wrong", http.StatusUnauthorized},
+		{"верный секрет", "secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhook", bytes.NewBufferString(`{"update_id":1,"message":{"text":"/games"}}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.secret != "" {
+				req.Header.Set(BotSecretHeader, tt.secret)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+
+	if len(bot.updates) != 1 || bot.updates[0].Message.Text != "/games" {
+		t.Errorf("Expected one update to reach the bot service, got %d", len(bot.updates))
+	}
+}
+
 // Benchmark тесты
 func BenchmarkHealthCheck(b *testing.B) {
 	router := setupTestRouter()
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// JackpotHandler представляет обработчики для джекпота платформы
+type JackpotHandler struct {
+	jackpotService models.JackpotService
+}
+
+// NewJackpotHandler создает новый экземпляр JackpotHandler
+func NewJackpotHandler(jackpotService models.JackpotService) *JackpotHandler {
+	return &JackpotHandler{
+		jackpotService: jackpotService,
+	}
+}
+
+// GetJackpot возвращает текущий размер джекпота во всех валютах и условия его розыгрыша
+func (h *JackpotHandler) GetJackpot(c *gin.Context) {
+	jackpots, err := h.jackpotService.GetJackpots(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := h.jackpotService.GetRule()
+	c.JSON(http.StatusOK, gin.H{
+		"jackpots": jackpots,
+		"enabled":  rule.Enabled(),
+		"rule":     rule,
+	})
+}
+
+// GetHistory возвращает журнал отчислений в джекпот и его выплат
+func (h *JackpotHandler) GetHistory(c *gin.Context) {
+	currency := c.Query("currency")
+	limit, offset := getPagination(c)
+
+	entries, err := h.jackpotService.GetHistory(c, currency, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
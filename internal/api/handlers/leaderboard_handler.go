@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// LeaderboardHandler представляет обработчики для таблиц лидеров
+type LeaderboardHandler struct {
+	leaderboardService models.LeaderboardService
+}
+
+// NewLeaderboardHandler создает новый экземпляр LeaderboardHandler
+func NewLeaderboardHandler(leaderboardService models.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+	}
+}
+
+// GetLeaderboard возвращает таблицу лидеров.
+// Параметры: period (daily, weekly, monthly, all_time), metric (profit, win_rate, efficiency), currency, limit
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	board, err := h.leaderboardService.GetLeaderboard(c, c.Query("period"), c.Query("metric"), c.Query("currency"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// GetVisibility возвращает, участвует ли текущий пользователь в публичных рейтингах
+func (h *LeaderboardHandler) GetVisibility(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	optedOut, err := h.leaderboardService.IsOptedOut(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hidden": optedOut})
+}
+
+// SetVisibility скрывает текущего пользователя из публичных рейтингов или возвращает его в них
+func (h *LeaderboardHandler) SetVisibility(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var input struct {
+		Hidden *bool `json:"hidden" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.leaderboardService.SetOptOut(c, userID, *input.Hidden); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hidden": *input.Hidden})
+}
+
+// GetPayouts возвращает выплаченные призы сезонов (только для администраторов)
+func (h *LeaderboardHandler) GetPayouts(c *gin.Context) {
+	limit, offset := getPagination(c)
+
+	payouts, err := h.leaderboardService.GetPayouts(c, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, payouts)
+}
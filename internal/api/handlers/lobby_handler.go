@@ -1,8 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/TakuroBreath/wordle/internal/api/middleware"
 	"github.com/TakuroBreath/wordle/internal/models"
@@ -39,8 +40,9 @@ func (h *LobbyHandler) JoinGame(c *gin.Context) {
 	}
 
 	var input struct {
-		GameID    string  `json:"game_id" binding:"required"` // UUID или short_id
-		BetAmount float64 `json:"bet_amount" binding:"required,gt=0"`
+		GameID      string  `json:"game_id" binding:"required"` // UUID или short_id
+		BetAmount   float64 `json:"bet_amount" binding:"required,gt=0"`
+		InviteToken string  `json:"invite_token"` // Токен или параметр startapp для приватной игры
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -64,11 +66,17 @@ func (h *LobbyHandler) JoinGame(c *gin.Context) {
 		return
 	}
 
-	if game.Status != models.GameStatusActive {
+	if !game.AcceptsBets(time.Now()) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "game is not active"})
 		return
 	}
 
+	// Проверяем доступ к приватной игре
+	if err := h.gameService.CheckAccess(c, game, userID, input.InviteToken); err != nil {
+		respondGameAccessError(c, err)
+		return
+	}
+
 	// Проверяем ставку
 	if input.BetAmount < game.MinBet || input.BetAmount > game.MaxBet {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -98,12 +106,17 @@ func (h *LobbyHandler) JoinGame(c *gin.Context) {
 
 	// Создаём лобби
 	lobby := &models.Lobby{
-		GameID:    game.ID,
-		UserID:    userID,
-		BetAmount: input.BetAmount,
+		GameID:      game.ID,
+		UserID:      userID,
+		BetAmount:   input.BetAmount,
+		InviteToken: input.InviteToken,
 	}
 
 	if err := h.lobbyService.CreateLobby(c, lobby); err != nil {
+		if errors.Is(err, models.ErrGameAccessDenied) {
+			respondGameAccessError(c, err)
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -346,7 +359,7 @@ func (h *LobbyHandler) GetAttempts(c *gin.Context) {
 	})
 }
 
-// GetUserLobbies получает список лобби пользователя
+// GetUserLobbies получает страницу лобби пользователя. Курсор следующей страницы возвращается в заголовке X-Next-Cursor
 func (h *LobbyHandler) GetUserLobbies(c *gin.Context) {
 	userID, exists := middleware.GetCurrentUserID(c)
 	if !exists {
@@ -354,26 +367,19 @@ func (h *LobbyHandler) GetUserLobbies(c *gin.Context) {
 		return
 	}
 
-	limit := 10
-	offset := 0
+	limit, cursor := getCursorPagination(c)
 
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
-			limit = val
-		}
-	}
-
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
-			offset = val
-		}
-	}
-
-	lobbies, err := h.lobbyService.GetUserLobbies(c, userID, limit, offset)
+	page, err := h.lobbyService.GetUserLobbies(c, userID, limit, cursor)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user lobbies"})
 		return
 	}
+	setNextCursor(c, page.NextCursor)
+	lobbies := page.Lobbies
 
 	result := make([]gin.H, 0, len(lobbies))
 	for _, lobby := range lobbies {
@@ -435,6 +441,81 @@ func (h *LobbyHandler) CancelLobby(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Lobby cancelled successfully"})
 }
 
+// GetCashOutOffer возвращает предложение досрочной выплаты по лобби
+func (h *LobbyHandler) GetCashOutOffer(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, ok := h.getOwnedActiveLobbyID(c, userID)
+	if !ok {
+		return
+	}
+
+	offer, err := h.lobbyService.GetCashOutOffer(c, id, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// AcceptCashOut принимает предложение досрочной выплаты и завершает лобби
+func (h *LobbyHandler) AcceptCashOut(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, ok := h.getOwnedActiveLobbyID(c, userID)
+	if !ok {
+		return
+	}
+
+	offer, err := h.lobbyService.AcceptCashOut(c, id, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cash-out accepted",
+		"status":  models.LobbyStatusCashedOut,
+		"offer":   offer,
+	})
+}
+
+// getOwnedActiveLobbyID проверяет, что лобби из пути принадлежит пользователю и активно
+func (h *LobbyHandler) getOwnedActiveLobbyID(c *gin.Context, userID uint64) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lobby ID"})
+		return uuid.Nil, false
+	}
+
+	lobby, err := h.lobbyService.GetLobby(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "lobby not found"})
+		return uuid.Nil, false
+	}
+
+	if lobby.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return uuid.Nil, false
+	}
+
+	if lobby.Status != models.LobbyStatusActive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lobby is not active"})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
 // ExtendLobbyTime продлевает время жизни лобби (если это разрешено)
 func (h *LobbyHandler) ExtendLobbyTime(c *gin.Context) {
 	userID, exists := middleware.GetCurrentUserID(c)
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getPagination читает параметры limit и offset из запроса (по умолчанию 10 и 0)
+func getPagination(c *gin.Context) (limit, offset int) {
+	limit = 10
+	offset = 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
+			offset = val
+		}
+	}
+
+	return limit, offset
+}
+
+// getCursorPagination читает параметры limit и cursor из запроса (по умолчанию 10 и первая страница)
+func getCursorPagination(c *gin.Context) (limit int, cursor string) {
+	limit = 10
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	return limit, c.Query("cursor")
+}
+
+// setNextCursor передаёт курсор следующей страницы в заголовке X-Next-Cursor, тело ответа остаётся списком
+func setNextCursor(c *gin.Context, cursor string) {
+	if cursor != "" {
+		c.Header("X-Next-Cursor", cursor)
+	}
+}
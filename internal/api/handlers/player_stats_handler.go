@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// PlayerStatsHandler представляет обработчики для статистики игроков
+type PlayerStatsHandler struct {
+	playerStatsService models.PlayerStatsService
+}
+
+// NewPlayerStatsHandler создает новый экземпляр PlayerStatsHandler
+func NewPlayerStatsHandler(playerStatsService models.PlayerStatsService) *PlayerStatsHandler {
+	return &PlayerStatsHandler{
+		playerStatsService: playerStatsService,
+	}
+}
+
+// GetStats возвращает статистику текущего пользователя.
+// Параметры: from, to (RFC 3339 или YYYY-MM-DD, включительно) или days - последние N дней; без параметров - вся история
+func (h *PlayerStatsHandler) GetStats(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	from, to, err := parseStatsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.playerStatsService.GetStats(c, userID, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// parseStatsRange разбирает период статистики из параметров запроса
+func parseStatsRange(c *gin.Context) (from, to time.Time, err error) {
+	if value := c.Query("to"); value != "" {
+		if to, err = parseReportTime(value); err != nil {
+			return from, to, errors.New("invalid to: use RFC 3339 or YYYY-MM-DD")
+		}
+	}
+
+	if value := c.Query("days"); value != "" {
+		if c.Query("from") != "" {
+			return from, to, errors.New("use either from or days")
+		}
+		days, err := strconv.Atoi(value)
+		if err != nil || days <= 0 {
+			return from, to, errors.New("days must be a positive integer")
+		}
+		end := to
+		if end.IsZero() {
+			end = time.Now()
+		}
+		return end.AddDate(0, 0, -(days - 1)), to, nil
+	}
+
+	if value := c.Query("from"); value != "" {
+		if from, err = parseReportTime(value); err != nil {
+			return from, to, errors.New("invalid from: use RFC 3339 or YYYY-MM-DD")
+		}
+	}
+
+	return from, to, nil
+}
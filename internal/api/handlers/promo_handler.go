@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PromoHandler представляет обработчики для промокодов
+type PromoHandler struct {
+	promoService models.PromoService
+}
+
+// NewPromoHandler создает новый экземпляр PromoHandler
+func NewPromoHandler(promoService models.PromoService) *PromoHandler {
+	return &PromoHandler{
+		promoService: promoService,
+	}
+}
+
+// RedeemPromoCode активирует промокод текущего пользователя
+func (h *PromoHandler) RedeemPromoCode(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	redemption, err := h.promoService.RedeemPromoCode(c, userID, input.Code)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, models.ErrPromoCodeNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, redemption)
+}
+
+// GetRedemptions возвращает активации промокодов текущим пользователем
+func (h *PromoHandler) GetRedemptions(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	limit, offset := getPagination(c)
+
+	redemptions, err := h.promoService.GetRedemptions(c, userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, redemptions)
+}
+
+// CreatePromoCode создает промокод (только для администраторов)
+func (h *PromoHandler) CreatePromoCode(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var input struct {
+		Code            string     `json:"code" binding:"required"`
+		Type            string     `json:"type"` // bonus (по умолчанию) или free_bet
+		Currency        string     `json:"currency" binding:"required"`
+		Amount          float64    `json:"amount" binding:"required,gt=0"`
+		WagerMultiplier float64    `json:"wager_multiplier"`
+		MaxRedemptions  int        `json:"max_redemptions"` // 0 - без ограничения
+		PerUserLimit    int        `json:"per_user_limit"`  // По умолчанию 1
+		ExpiresAt       *time.Time `json:"expires_at"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	promo := &models.PromoCode{
+		Code:            input.Code,
+		Type:            input.Type,
+		Currency:        input.Currency,
+		Amount:          input.Amount,
+		WagerMultiplier: input.WagerMultiplier,
+		MaxRedemptions:  input.MaxRedemptions,
+		PerUserLimit:    input.PerUserLimit,
+		ExpiresAt:       input.ExpiresAt,
+		CreatedBy:       userID,
+	}
+
+	if err := h.promoService.CreatePromoCode(c, promo); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, models.ErrPromoCodeExists) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, promo)
+}
+
+// GetPromoCodes возвращает промокоды (только для администраторов)
+func (h *PromoHandler) GetPromoCodes(c *gin.Context) {
+	limit, offset := getPagination(c)
+
+	promos, err := h.promoService.GetPromoCodes(c, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promos)
+}
+
+// SetPromoCodeActive включает или выключает промокод (только для администраторов)
+func (h *PromoHandler) SetPromoCodeActive(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid promo code ID"})
+		return
+	}
+
+	var input struct {
+		Active *bool `json:"active" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.promoService.SetPromoCodeActive(c, id, *input.Active); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrPromoCodeNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": id, "active": *input.Active})
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ReferralHandler представляет обработчики для реферальной программы
+type ReferralHandler struct {
+	referralService models.ReferralService
+}
+
+// NewReferralHandler создает новый экземпляр ReferralHandler
+func NewReferralHandler(referralService models.ReferralService) *ReferralHandler {
+	return &ReferralHandler{
+		referralService: referralService,
+	}
+}
+
+// GetSummary возвращает реферальную ссылку текущего пользователя, приглашённых пользователей и заработок
+func (h *ReferralHandler) GetSummary(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	limit, offset := getPagination(c)
+
+	summary, err := h.referralService.GetSummary(c, userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
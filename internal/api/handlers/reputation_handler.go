@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReputationHandler представляет обработчики для оценок игр и репутации создателей
+type ReputationHandler struct {
+	reputationService models.ReputationService
+}
+
+// NewReputationHandler создает новый экземпляр ReputationHandler
+func NewReputationHandler(reputationService models.ReputationService) *ReputationHandler {
+	return &ReputationHandler{
+		reputationService: reputationService,
+	}
+}
+
+// reviewErrorStatus возвращает HTTP-статус ошибки оценок и отзывов
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrGameNotFound), errors.Is(err, models.ErrReviewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrReviewNotAllowed):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// RateGame сохраняет оценку и отзыв текущего пользователя об игре
+func (h *ReputationHandler) RateGame(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	gameID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	var input struct {
+		Rating int    `json:"rating" binding:"required"`
+		Review string `json:"review"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reputationService.RateGame(c, gameID, userID, input.Rating, input.Review)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// GetGameReviews возвращает сводку оценок и опубликованные отзывы об игре
+func (h *ReputationHandler) GetGameReviews(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game ID"})
+		return
+	}
+
+	rating, err := h.reputationService.GetGameRating(c, gameID)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, offset := getPagination(c)
+	reviews, err := h.reputationService.GetGameReviews(c, gameID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rating":  rating,
+		"reviews": reviews,
+	})
+}
+
+// GetCreatorReputation возвращает репутацию создателя игр
+func (h *ReputationHandler) GetCreatorReputation(c *gin.Context) {
+	creatorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	reputation, err := h.reputationService.GetCreatorReputation(c, creatorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reputation)
+}
+
+// GetReviewsForModeration возвращает отзывы для модерации (только для администраторов)
+func (h *ReputationHandler) GetReviewsForModeration(c *gin.Context) {
+	limit, offset := getPagination(c)
+
+	reviews, err := h.reputationService.GetReviewsForModeration(c, c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ModerateReview скрывает отзыв или публикует его снова (только для администраторов)
+func (h *ReputationHandler) ModerateReview(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	reviewID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review ID"})
+		return
+	}
+
+	var input struct {
+		Hidden *bool  `json:"hidden" binding:"required"`
+		Note   string `json:"note"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reputationService.ModerateReview(c, reviewID, userID, *input.Hidden, input.Note)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// RecordDispute записывает исход спора игрока с создателем (только для администраторов)
+func (h *ReputationHandler) RecordDispute(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var input struct {
+		CreatorID uint64     `json:"creator_id" binding:"required"`
+		UserID    uint64     `json:"user_id" binding:"required"`
+		LobbyID   *uuid.UUID `json:"lobby_id"`
+		Outcome   string     `json:"outcome" binding:"required"` // player или creator
+		Note      string     `json:"note"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dispute := &models.CreatorDispute{
+		CreatorID:  input.CreatorID,
+		UserID:     input.UserID,
+		LobbyID:    input.LobbyID,
+		Outcome:    input.Outcome,
+		Note:       input.Note,
+		ResolvedBy: userID,
+	}
+
+	if err := h.reputationService.RecordDispute(c, dispute); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dispute)
+}
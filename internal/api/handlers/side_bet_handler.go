@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SideBetHandler представляет обработчики для ставок зрителей
+type SideBetHandler struct {
+	sideBetService models.SideBetService
+}
+
+// NewSideBetHandler создает новый экземпляр SideBetHandler
+func NewSideBetHandler(sideBetService models.SideBetService) *SideBetHandler {
+	return &SideBetHandler{
+		sideBetService: sideBetService,
+	}
+}
+
+// GetMarket возвращает лобби глазами зрителя (только цвета) и котировки ставок
+func (h *SideBetHandler) GetMarket(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lobby ID"})
+		return
+	}
+
+	market, err := h.sideBetService.GetMarket(c, id, userID, c.Query("invite"))
+	if err != nil {
+		if errors.Is(err, models.ErrGameAccessDenied) {
+			respondGameAccessError(c, err)
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, market)
+}
+
+// PlaceSideBet принимает ставку зрителя на лобби
+func (h *SideBetHandler) PlaceSideBet(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lobby ID"})
+		return
+	}
+
+	var input struct {
+		WithinTries int     `json:"within_tries" binding:"required,min=1"`
+		Amount      float64 `json:"amount" binding:"required,gt=0"`
+		TriesUsed   *int    `json:"tries_used" binding:"required,min=0"` // Версия котировки из рынка
+		InviteToken string  `json:"invite_token"`                        // Токен или параметр startapp для приватной игры
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bet, err := h.sideBetService.PlaceSideBet(c, id, userID, input.WithinTries, input.Amount, *input.TriesUsed, input.InviteToken)
+	if err != nil {
+		if errors.Is(err, models.ErrGameAccessDenied) {
+			respondGameAccessError(c, err)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"bet":              bet,
+		"potential_payout": bet.PotentialPayout(),
+	})
+}
+
+// GetLobbySideBets возвращает ставки зрителей на лобби
+func (h *SideBetHandler) GetLobbySideBets(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lobby ID"})
+		return
+	}
+
+	bets, err := h.sideBetService.GetLobbySideBets(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"side_bets": bets})
+}
+
+// GetUserSideBets возвращает ставки текущего пользователя
+func (h *SideBetHandler) GetUserSideBets(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	limit, offset := getPagination(c)
+
+	bets, err := h.sideBetService.GetUserSideBets(c, userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"side_bets": bets,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// GetAccounts возвращает счета рынка ставок зрителей: принятые ставки за вычетом выплат
+func (h *SideBetHandler) GetAccounts(c *gin.Context) {
+	accounts, err := h.sideBetService.GetAccounts(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accounts": accounts})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -27,7 +28,7 @@ func NewTransactionHandler(
 	}
 }
 
-// GetUserTransactions получает список транзакций пользователя
+// GetUserTransactions получает страницу транзакций пользователя. Курсор следующей страницы возвращается в заголовке X-Next-Cursor
 func (h *TransactionHandler) GetUserTransactions(c *gin.Context) {
 	userID, exists := middleware.GetCurrentUserID(c)
 	if !exists {
@@ -35,26 +36,19 @@ func (h *TransactionHandler) GetUserTransactions(c *gin.Context) {
 		return
 	}
 
-	limit := 10
-	offset := 0
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
-			limit = val
-		}
-	}
+	limit, cursor := getCursorPagination(c)
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
-			offset = val
-		}
-	}
-
-	transactions, err := h.transactionService.GetUserTransactions(c, userID, limit, offset)
+	page, err := h.transactionService.GetUserTransactions(c, userID, limit, cursor)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user transactions"})
 		return
 	}
+	setNextCursor(c, page.NextCursor)
+	transactions := page.Transactions
 
 	result := make([]gin.H, 0, len(transactions))
 	for _, tx := range transactions {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -287,7 +288,7 @@ func (h *UserHandler) GetTransactionHistory(c *gin.Context) {
 	}
 
 	limit := 20
-	offset := 0
+	cursor := c.Query("cursor")
 	txType := c.Query("type") // deposit, withdraw, bet, reward, all
 
 	if limitStr := c.Query("limit"); limitStr != "" {
@@ -296,17 +297,17 @@ func (h *UserHandler) GetTransactionHistory(c *gin.Context) {
 		}
 	}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
-			offset = val
-		}
-	}
-
-	transactions, err := h.transactionService.GetUserTransactions(c, userID, limit, offset)
+	page, err := h.transactionService.GetUserTransactions(c, userID, limit, cursor)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get transactions"})
 		return
 	}
+	setNextCursor(c, page.NextCursor)
+	transactions := page.Transactions
 
 	result := make([]gin.H, 0, len(transactions))
 	for _, tx := range transactions {
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TakuroBreath/wordle/internal/api/middleware"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler представляет обработчики исходящих webhook пользователя
+type WebhookHandler struct {
+	webhookService models.WebhookService
+}
+
+// NewWebhookHandler создает новый экземпляр WebhookHandler
+func NewWebhookHandler(webhookService models.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// webhookErrorStatus возвращает HTTP-статус ошибки webhook
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrWebhookNotFound), errors.Is(err, models.ErrWebhookDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidWebhookURL), errors.Is(err, models.ErrInvalidWebhookEventType),
+		errors.Is(err, models.ErrInvalidWebhookSecret), errors.Is(err, models.ErrWebhookEventTypesMissing):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrWebhookLimitReached):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreateWebhook создаёт webhook текущего пользователя. Ключ подписи возвращается только в этом ответе
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var input struct {
+		URL        string   `json:"url" binding:"required"`
+		Secret     string   `json:"secret"`
+		EventTypes []string `json:"event_types" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c, userID, input.URL, input.Secret, input.EventTypes)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhooks возвращает webhook текущего пользователя
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks":    webhooks,
+		"event_types": models.WebhookEventTypes,
+	})
+}
+
+// UpdateWebhook меняет адрес, типы событий или активность webhook. Не переданные поля не меняются
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.WebhookUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c, userID, webhookID, &input)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook удаляет webhook вместе с журналом доставок
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c, userID, webhookID); err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "webhook deleted"})
+}
+
+// GetDeliveries возвращает журнал доставок webhook с кодами ответов
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	limit, offset := getPagination(c)
+	deliveries, err := h.webhookService.GetDeliveries(c, userID, webhookID, limit, offset)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayDelivery ставит в очередь повторную отправку события из журнала доставок
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery ID"})
+		return
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(c, userID, webhookID, deliveryID)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// SendTestEvent отправляет на webhook тестовое событие и возвращает результат доставки
+func (h *WebhookHandler) SendTestEvent(c *gin.Context) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.SendTestEvent(c, userID, webhookID)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// webhookParams читает текущего пользователя и ID webhook из пути. При ошибке ответ уже отправлен
+func webhookParams(c *gin.Context) (uint64, uuid.UUID, bool) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return 0, uuid.Nil, false
+	}
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return 0, uuid.Nil, false
+	}
+
+	return userID, webhookID, true
+}
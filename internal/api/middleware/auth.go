@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -252,6 +253,18 @@ func (m *AuthMiddleware) IsAuthEnabled() bool {
 	return m.config.Enabled
 }
 
+// RequireAdmin пропускает только администраторов сервиса. Используется после RequireAuth
+func RequireAdmin(adminIDs []uint64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetCurrentUserID(c)
+		if !ok || !slices.Contains(adminIDs, userID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
+
 // GetCurrentUser возвращает текущего пользователя из контекста
 func GetCurrentUser(c *gin.Context) (*models.User, bool) {
 	user, exists := c.Get("user")
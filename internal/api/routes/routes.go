@@ -12,18 +12,36 @@ import (
 
 // RouterConfig конфигурация роутера
 type RouterConfig struct {
-	AuthEnabled bool
-	BotToken    string
+	AuthEnabled      bool
+	BotToken         string
+	AdminIDs         []uint64 // Telegram ID администраторов сервиса
+	BotWebhookSecret string   // Секрет webhook бота из заголовка X-Telegram-Bot-Api-Secret-Token
 }
 
 // Services содержит все сервисы для роутера
 type Services struct {
-	AuthService        models.AuthService
-	UserService        models.UserService
-	GameService        models.GameService
-	LobbyService       models.LobbyService
-	TransactionService models.TransactionService
-	TONService         models.TONService
+	AuthService         models.AuthService
+	UserService         models.UserService
+	GameService         models.GameService
+	LobbyService        models.LobbyService
+	TransactionService  models.TransactionService
+	TONService          models.TONService
+	SideBetService      models.SideBetService
+	JackpotService      models.JackpotService
+	CommissionService   models.CommissionService
+	ReferralService     models.ReferralService
+	PromoService        models.PromoService
+	AchievementService  models.AchievementService
+	LeaderboardService  models.LeaderboardService
+	PlayerStatsService  models.PlayerStatsService
+	AnalyticsService    models.CreatorAnalyticsService
+	ReputationService   models.ReputationService
+	FollowService       models.FollowService
+	NotificationService models.NotificationService
+	EventService        models.EventService
+	WebhookService      models.WebhookService
+	BotService          models.BotService
+	DuelService         models.DuelService
 }
 
 // SetupRouter настраивает маршруты API и middleware
@@ -60,6 +78,19 @@ func SetupRouterWithConfig(
 	tonService models.TONService,
 	config RouterConfig,
 ) *gin.Engine {
+	return SetupRouterWithServices(Services{
+		AuthService:        authService,
+		UserService:        userService,
+		GameService:        gameService,
+		LobbyService:       lobbyService,
+		TransactionService: transactionService,
+		TONService:         tonService,
+	}, config)
+}
+
+// SetupRouterWithServices настраивает роутер используя структуру Services.
+// Необязательные сервисы (например, SideBetService) регистрируют маршруты, только если заданы
+func SetupRouterWithServices(services Services, config RouterConfig) *gin.Engine {
 	logger.Log.Info("Setting up router",
 		zap.Bool("auth_enabled", config.AuthEnabled))
 
@@ -72,16 +103,16 @@ func SetupRouterWithConfig(
 	router.Use(metrics.MiddlewareMetrics())
 
 	// Инициализация обработчиков
-	authHandler := handlers.NewAuthHandler(authService, config.BotToken)
-	userHandler := handlers.NewUserHandler(userService, transactionService, tonService)
-	gameHandler := handlers.NewGameHandler(gameService, userService, transactionService, lobbyService)
-	lobbyHandler := handlers.NewLobbyHandler(lobbyService, gameService, userService)
-	transactionHandler := handlers.NewTransactionHandler(transactionService, userService)
+	authHandler := handlers.NewAuthHandler(services.AuthService, config.BotToken)
+	userHandler := handlers.NewUserHandler(services.UserService, services.TransactionService, services.TONService)
+	gameHandler := handlers.NewGameHandler(services.GameService, services.UserService, services.TransactionService, services.LobbyService)
+	lobbyHandler := handlers.NewLobbyHandler(services.LobbyService, services.GameService, services.UserService)
+	transactionHandler := handlers.NewTransactionHandler(services.TransactionService, services.UserService)
 
 	logger.Log.Info("Handlers initialized")
 
 	// Middleware для аутентификации с конфигурацией
-	authMiddleware := middleware.NewAuthMiddlewareWithConfig(authService, middleware.AuthConfig{
+	authMiddleware := middleware.NewAuthMiddlewareWithConfig(services.AuthService, middleware.AuthConfig{
 		Enabled:     config.AuthEnabled,
 		BotToken:    config.BotToken,
 		DefaultUser: middleware.DefaultDevUser(),
@@ -99,6 +130,28 @@ func SetupRouterWithConfig(
 		public.GET("/games", gameHandler.GetActiveGames)
 		public.GET("/games/search", gameHandler.SearchGames)
 		public.GET("/games/:id", gameHandler.GetGame)
+
+		// Текущий джекпот платформы
+		if services.JackpotService != nil {
+			public.GET("/jackpot", handlers.NewJackpotHandler(services.JackpotService).GetJackpot)
+		}
+
+		// Таблицы лидеров по истории игр
+		if services.LeaderboardService != nil {
+			public.GET("/leaderboards", handlers.NewLeaderboardHandler(services.LeaderboardService).GetLeaderboard)
+		}
+
+		// Оценки игр и репутация создателей
+		if services.ReputationService != nil {
+			reputationHandler := handlers.NewReputationHandler(services.ReputationService)
+			public.GET("/games/:id/reviews", reputationHandler.GetGameReviews)
+			public.GET("/users/:id/reputation", reputationHandler.GetCreatorReputation)
+		}
+
+		// Webhook Telegram бота: обновления подписаны секретом в заголовке, а не токеном пользователя
+		if services.BotService != nil {
+			public.POST("/telegram/webhook", handlers.NewBotHandler(services.BotService, config.BotWebhookSecret).Webhook)
+		}
 	}
 
 	logger.Log.Info("Public routes configured", zap.String("route_group", "/api/v1"))
@@ -110,7 +163,11 @@ func SetupRouterWithConfig(
 		// Пользователи
 		private.GET("/users/me", userHandler.GetCurrentUser)
 		private.GET("/users/balance", userHandler.GetUserBalance)
-		private.GET("/users/stats", userHandler.GetUserStats)
+		if services.PlayerStatsService != nil {
+			private.GET("/users/stats", handlers.NewPlayerStatsHandler(services.PlayerStatsService).GetStats)
+		} else {
+			private.GET("/users/stats", userHandler.GetUserStats)
+		}
 		private.GET("/users/top", userHandler.GetTopUsers)
 		private.GET("/users/:id", userHandler.GetUserByID)
 		private.PUT("/users/me", userHandler.UpdateUser)
@@ -131,6 +188,19 @@ func SetupRouterWithConfig(
 		private.POST("/games/:id/reward", gameHandler.AddToRewardPool)
 		private.POST("/games/:id/activate", gameHandler.ActivateGame)
 		private.POST("/games/:id/deactivate", gameHandler.DeactivateGame)
+		private.POST("/games/:id/close", gameHandler.CloseGame)
+		private.POST("/games/:id/pool/withdraw", gameHandler.WithdrawFromPool)
+		private.PUT("/games/:id/pool/auto-top-up", gameHandler.SetAutoTopUp)
+		private.GET("/games/:id/pool/pnl", gameHandler.GetPoolPnL)
+		private.PUT("/games/:id/risk-limits", gameHandler.UpdateRiskLimits)
+		private.GET("/games/:id/odds", gameHandler.GetGameOdds)
+		private.PUT("/games/:id/odds/auto-adjust", gameHandler.SetOddsAutoAdjust)
+		private.POST("/games/difficulty", gameHandler.AnalyzeWord)
+
+		// Приватные игры
+		private.GET("/games/invite/:token", gameHandler.GetInviteGame)
+		private.PUT("/games/:id/access", gameHandler.UpdateGameAccess)
+		private.POST("/games/:id/invite", gameHandler.RotateInviteToken)
 		
 		// Вступление в игру (join)
 		private.POST("/games/join", gameHandler.JoinGame)
@@ -144,6 +214,8 @@ func SetupRouterWithConfig(
 		private.GET("/lobbies/:id/attempts", lobbyHandler.GetAttempts)
 		private.POST("/lobbies/:id/extend", lobbyHandler.ExtendLobbyTime)
 		private.POST("/lobbies/:id/cancel", lobbyHandler.CancelLobby)
+		private.GET("/lobbies/:id/cashout", lobbyHandler.GetCashOutOffer)
+		private.POST("/lobbies/:id/cashout", lobbyHandler.AcceptCashOut)
 
 		// Транзакции
 		private.GET("/transactions", transactionHandler.GetUserTransactions)
@@ -156,20 +228,137 @@ func SetupRouterWithConfig(
 		// Блокчейн операции (legacy)
 		private.GET("/wallet/address", transactionHandler.GetDepositAddress)
 		private.POST("/wallet/withdraw/prepare", transactionHandler.PrepareWithdraw)
+
+		// Ставки зрителей на лобби
+		if services.SideBetService != nil {
+			sideBetHandler := handlers.NewSideBetHandler(services.SideBetService)
+			private.GET("/lobbies/:id/spectate", sideBetHandler.GetMarket)
+			private.GET("/lobbies/:id/side-bets", sideBetHandler.GetLobbySideBets)
+			private.POST("/lobbies/:id/side-bets", sideBetHandler.PlaceSideBet)
+			private.GET("/users/side-bets", sideBetHandler.GetUserSideBets)
+
+			admin := private.Group("/admin", middleware.RequireAdmin(config.AdminIDs))
+			admin.GET("/side-bets/accounts", sideBetHandler.GetAccounts)
+		}
+
+		// Журнал джекпота
+		if services.JackpotService != nil {
+			private.GET("/jackpot/history", handlers.NewJackpotHandler(services.JackpotService).GetHistory)
+		}
+
+		// Отчёты по комиссии сервиса (только для администраторов)
+		if services.CommissionService != nil {
+			commissionHandler := handlers.NewCommissionHandler(services.CommissionService)
+			admin := private.Group("/admin", middleware.RequireAdmin(config.AdminIDs))
+			admin.GET("/commission/accounts", commissionHandler.GetHouseAccounts)
+			admin.GET("/commission/report", commissionHandler.GetReport)
+		}
+
+		// Реферальная программа
+		if services.ReferralService != nil {
+			private.GET("/referrals", handlers.NewReferralHandler(services.ReferralService).GetSummary)
+		}
+
+		// Промокоды и бонусные балансы
+		if services.PromoService != nil {
+			promoHandler := handlers.NewPromoHandler(services.PromoService)
+			private.POST("/promo/redeem", promoHandler.RedeemPromoCode)
+			private.GET("/promo/redemptions", promoHandler.GetRedemptions)
+
+			// Управление промокодами (только для администраторов)
+			admin := private.Group("/admin", middleware.RequireAdmin(config.AdminIDs))
+			admin.POST("/promo-codes", promoHandler.CreatePromoCode)
+			admin.GET("/promo-codes", promoHandler.GetPromoCodes)
+			admin.POST("/promo-codes/:id/active", promoHandler.SetPromoCodeActive)
+		}
+
+		// Достижения, серии и ежедневные награды
+		if services.AchievementService != nil {
+			achievementHandler := handlers.NewAchievementHandler(services.AchievementService)
+			private.GET("/users/me/achievements", achievementHandler.GetAchievements)
+			private.POST("/users/me/daily-reward", achievementHandler.ClaimDailyReward)
+		}
+
+		// Участие в публичных рейтингах и призы сезонов
+		if services.LeaderboardService != nil {
+			leaderboardHandler := handlers.NewLeaderboardHandler(services.LeaderboardService)
+			private.GET("/users/me/leaderboard-visibility", leaderboardHandler.GetVisibility)
+			private.PUT("/users/me/leaderboard-visibility", leaderboardHandler.SetVisibility)
+
+			admin := private.Group("/admin", middleware.RequireAdmin(config.AdminIDs))
+			admin.GET("/leaderboard-payouts", leaderboardHandler.GetPayouts)
+		}
+
+		// Аналитика игр для создателей
+		if services.AnalyticsService != nil {
+			analyticsHandler := handlers.NewCreatorAnalyticsHandler(services.AnalyticsService)
+			private.GET("/games/:id/analytics", analyticsHandler.GetGameAnalytics)
+			private.GET("/users/me/creator-analytics", analyticsHandler.GetCreatorAnalytics)
+		}
+
+		// Подписки на создателей, закладки и персональная лента
+		if services.FollowService != nil {
+			followHandler := handlers.NewFollowHandler(services.FollowService)
+			private.GET("/feed", followHandler.GetFeed)
+			private.POST("/users/:id/follow", followHandler.FollowCreator)
+			private.DELETE("/users/:id/follow", followHandler.UnfollowCreator)
+			private.GET("/users/me/following", followHandler.GetFollowing)
+			private.POST("/games/:id/bookmark", followHandler.BookmarkGame)
+			private.DELETE("/games/:id/bookmark", followHandler.RemoveBookmark)
+			private.GET("/users/me/bookmarks", followHandler.GetBookmarks)
+		}
+
+		// Настройки уведомлений бота
+		if services.NotificationService != nil {
+			notificationHandler := handlers.NewNotificationHandler(services.NotificationService)
+			private.GET("/users/me/notifications", notificationHandler.GetPreferences)
+			private.PUT("/users/me/notifications", notificationHandler.UpdatePreferences)
+		}
+
+		// Недоставленные доменные события
+		if services.EventService != nil {
+			eventHandler := handlers.NewEventHandler(services.EventService)
+
+			admin := private.Group("/admin", middleware.RequireAdmin(config.AdminIDs))
+			admin.GET("/events/dead-letters", eventHandler.GetDeadLetters)
+			admin.POST("/events/:id/retry", eventHandler.RetryDeadLetter)
+		}
+
+		// Исходящие webhook партнёров и создателей
+		if services.WebhookService != nil {
+			webhookHandler := handlers.NewWebhookHandler(services.WebhookService)
+			private.GET("/webhooks", webhookHandler.GetWebhooks)
+			private.POST("/webhooks", webhookHandler.CreateWebhook)
+			private.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+			private.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+			private.GET("/webhooks/:id/deliveries", webhookHandler.GetDeliveries)
+			private.POST("/webhooks/:id/deliveries/:delivery_id/replay", webhookHandler.ReplayDelivery)
+			private.POST("/webhooks/:id/test", webhookHandler.SendTestEvent)
+		}
+
+		// Оценки игр игроками и модерация отзывов
+		if services.ReputationService != nil {
+			reputationHandler := handlers.NewReputationHandler(services.ReputationService)
+			private.POST("/games/:id/reviews", reputationHandler.RateGame)
+
+			admin := private.Group("/admin", middleware.RequireAdmin(config.AdminIDs))
+			admin.GET("/reviews", reputationHandler.GetReviewsForModeration)
+			admin.POST("/reviews/:id/moderate", reputationHandler.ModerateReview)
+			admin.POST("/disputes", reputationHandler.RecordDispute)
+		}
+
+		// Дуэли
+		if services.DuelService != nil {
+			duelHandler := handlers.NewDuelHandler(services.DuelService)
+			private.GET("/duels", duelHandler.GetUserDuels)
+			private.POST("/duels", duelHandler.CreateDuel)
+			private.GET("/duels/invite/:code", duelHandler.GetInvite)
+			private.POST("/duels/invite/:code/accept", duelHandler.AcceptDuel)
+			private.GET("/duels/:id", duelHandler.GetDuel)
+			private.POST("/duels/:id/cancel", duelHandler.CancelDuel)
+			private.POST("/duels/:id/attempts", duelHandler.MakeAttempt)
+		}
 	}
 
 	return router
 }
-
-// SetupRouterWithServices настраивает роутер используя структуру Services
-func SetupRouterWithServices(services Services, config RouterConfig) *gin.Engine {
-	return SetupRouterWithConfig(
-		services.AuthService,
-		services.UserService,
-		services.GameService,
-		services.LobbyService,
-		services.TransactionService,
-		services.TONService,
-		config,
-	)
-}
@@ -16,12 +16,14 @@ import (
 
 // Config представляет конфигурацию сервера
 type Config struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	IdleTimeout  time.Duration
-	BotToken     string
-	AuthEnabled  bool
+	Port             string
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+	IdleTimeout      time.Duration
+	BotToken         string
+	AuthEnabled      bool
+	AdminIDs         []uint64
+	BotWebhookSecret string // Секрет webhook Telegram бота
 }
 
 // Server представляет HTTP-сервер приложения
@@ -33,16 +35,36 @@ type Server struct {
 // NewServer создает новый экземпляр сервера
 func NewServer(cfg Config, services *service.ServiceImpl) *Server {
 	// Настройка маршрутов с конфигурацией
-	router := routes.SetupRouterWithConfig(
-		services.Auth(),
-		services.User(),
-		services.Game(),
-		services.Lobby(),
-		services.Transaction(),
-		services.TONService(),
+	router := routes.SetupRouterWithServices(
+		routes.Services{
+			AuthService:         services.Auth(),
+			UserService:         services.User(),
+			GameService:         services.Game(),
+			LobbyService:        services.Lobby(),
+			TransactionService:  services.Transaction(),
+			TONService:          services.TONService(),
+			SideBetService:      services.SideBet(),
+			JackpotService:      services.Jackpot(),
+			CommissionService:   services.Commission(),
+			ReferralService:     services.Referral(),
+			PromoService:        services.Promo(),
+			AchievementService:  services.Achievement(),
+			LeaderboardService:  services.Leaderboard(),
+			PlayerStatsService:  services.PlayerStats(),
+			AnalyticsService:    services.CreatorAnalytics(),
+			ReputationService:   services.Reputation(),
+			FollowService:       services.Follow(),
+			NotificationService: services.Notification(),
+			EventService:        services.Event(),
+			WebhookService:      services.Webhook(),
+			BotService:          services.Bot(),
+			DuelService:         services.Duel(),
+		},
 		routes.RouterConfig{
-			AuthEnabled: cfg.AuthEnabled,
-			BotToken:    cfg.BotToken,
+			AuthEnabled:      cfg.AuthEnabled,
+			BotToken:         cfg.BotToken,
+			AdminIDs:         cfg.AdminIDs,
+			BotWebhookSecret: cfg.BotWebhookSecret,
 		},
 	)
 
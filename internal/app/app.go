@@ -2,16 +2,20 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/TakuroBreath/wordle/internal/api/server"
 	"github.com/TakuroBreath/wordle/internal/config"
+	"github.com/TakuroBreath/wordle/internal/models"
 	"github.com/TakuroBreath/wordle/internal/repository"
 	"github.com/TakuroBreath/wordle/internal/repository/memory"
 	"github.com/TakuroBreath/wordle/internal/repository/postgresql"
 	"github.com/TakuroBreath/wordle/internal/service"
+	"github.com/TakuroBreath/wordle/internal/telegram"
 	"github.com/TakuroBreath/wordle/pkg/metrics"
+	"github.com/google/uuid"
 )
 
 // App представляет структуру приложения
@@ -34,25 +38,78 @@ func New(cfg *config.Config) (*App, error) {
 	repos := postgresql.NewRepository(postgresDB)
 	memoryRepos := memory.NewRepository()
 
+	commissionPolicy, err := newCommissionPolicy(cfg.Commission)
+	if err != nil {
+		return nil, err
+	}
+	commissionRate := cfg.Commission.DefaultRate
+	if commissionRate <= 0 {
+		commissionRate = cfg.Blockchain.TON.CommissionRate
+	}
+
 	// Инициализация сервисов с полной конфигурацией
 	serviceCfg := service.ServiceConfig{
 		JWTSecret:       cfg.Auth.JWTSecret,
 		BotToken:        cfg.Auth.BotToken,
 		Network:         string(cfg.Network),
 		UseMockProvider: cfg.UseMockProvider,
+		DictionaryPath:  cfg.Dictionary.Path,
+		BotUsername:     cfg.Telegram.BotUsername,
+		MiniAppName:     cfg.Telegram.MiniAppName,
+		BotAPIEndpoint:  cfg.Telegram.APIEndpoint,
 		Blockchain:      cfg.Blockchain,
+		CommissionRate:  commissionRate,
+		Commission:      commissionPolicy,
+		Jackpot: models.JackpotRule{
+			CommissionShare: cfg.Jackpot.CommissionShare,
+			MaxTries:        cfg.Jackpot.MaxTries,
+			MinBet:          cfg.Jackpot.MinBet,
+		},
+		Referral: models.ReferralRule{
+			CommissionShare: cfg.Referral.CommissionShare,
+			Period:          cfg.Referral.Period,
+		},
+		Achievements: models.AchievementRule{
+			Currency:           cfg.Achievements.Currency,
+			Rewards:            cfg.Achievements.Rewards,
+			DailyReward:        cfg.Achievements.DailyReward,
+			DailyRewardStep:    cfg.Achievements.DailyRewardStep,
+			DailyRewardMaxDays: cfg.Achievements.DailyRewardMaxDays,
+		},
+		Leaderboard: newLeaderboardRule(cfg.Leaderboard),
+		Notifications: models.NotificationRule{
+			MaxAttempts:   cfg.Notifications.MaxAttempts,
+			RetryDelay:    cfg.Notifications.RetryDelay,
+			RatePerSecond: cfg.Notifications.RatePerSecond,
+			BatchSize:     cfg.Notifications.BatchSize,
+			ExpiryNotice:  cfg.Notifications.ExpiryNotice,
+		},
+		Events: models.EventRule{
+			MaxAttempts: cfg.Events.MaxAttempts,
+			RetryDelay:  cfg.Events.RetryDelay,
+			BatchSize:   cfg.Events.BatchSize,
+		},
+		Webhooks: models.WebhookRule{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			RetryDelay:  cfg.Webhooks.RetryDelay,
+			BatchSize:   cfg.Webhooks.BatchSize,
+			Timeout:     cfg.Webhooks.Timeout,
+			MaxPerOwner: cfg.Webhooks.MaxPerUser,
+		},
 	}
 	services := service.NewServiceWithConfig(repos, memoryRepos, serviceCfg)
 	servicesImpl := services.(*service.ServiceImpl)
 
 	// Инициализация сервера
 	serverConfig := server.Config{
-		Port:         cfg.HTTP.Port,
-		ReadTimeout:  cfg.HTTP.ReadTimeout,
-		WriteTimeout: cfg.HTTP.WriteTimeout,
-		IdleTimeout:  cfg.HTTP.IdleTimeout,
-		BotToken:     cfg.Auth.BotToken,
-		AuthEnabled:  cfg.IsAuthEnabled(),
+		Port:             cfg.HTTP.Port,
+		ReadTimeout:      cfg.HTTP.ReadTimeout,
+		WriteTimeout:     cfg.HTTP.WriteTimeout,
+		IdleTimeout:      cfg.HTTP.IdleTimeout,
+		BotToken:         cfg.Auth.BotToken,
+		AuthEnabled:      cfg.IsAuthEnabled(),
+		AdminIDs:         cfg.Auth.AdminIDs,
+		BotWebhookSecret: botWebhookSecret(cfg),
 	}
 	httpServer := server.NewServer(serverConfig, servicesImpl)
 
@@ -85,10 +142,62 @@ func (a *App) Run() error {
 		)
 	}()
 
+	// Регистрация webhook бота: Bot API начнёт присылать обновления на указанный адрес
+	if a.cfg.Telegram.WebhookURL != "" && a.services.Bot() != nil {
+		if err := a.services.Bot().RegisterWebhook(ctx, a.cfg.Telegram.WebhookURL, botWebhookSecret(a.cfg)); err != nil {
+			log.Printf("Failed to register bot webhook: %v", err)
+		}
+	}
+
 	// Запуск HTTP-сервера
 	return a.server.Run()
 }
 
+// botWebhookSecret возвращает секрет webhook бота: из конфигурации или производный от токена бота
+func botWebhookSecret(cfg *config.Config) string {
+	if cfg.Telegram.WebhookSecret != "" {
+		return cfg.Telegram.WebhookSecret
+	}
+	if cfg.Auth.BotToken == "" {
+		return ""
+	}
+	return telegram.WebhookSecret(cfg.Auth.BotToken)
+}
+
+// newCommissionPolicy преобразует конфигурацию комиссии в уровни и переопределения ставок
+func newCommissionPolicy(cfg config.CommissionConfig) (models.CommissionPolicy, error) {
+	policy := models.CommissionPolicy{
+		CreatorRates: cfg.CreatorRates,
+		GameRates:    make(map[uuid.UUID]float64, len(cfg.GameRates)),
+	}
+	for _, tier := range cfg.Tiers {
+		policy.Tiers = append(policy.Tiers, models.CommissionTier{MinVolume: tier.MinVolume, Rate: tier.Rate})
+	}
+	for id, rate := range cfg.GameRates {
+		gameID, err := uuid.Parse(id)
+		if err != nil {
+			return policy, fmt.Errorf("invalid game ID %q in commission.game_rates: %w", id, err)
+		}
+		policy.GameRates[gameID] = rate
+	}
+	return policy, nil
+}
+
+// newLeaderboardRule преобразует конфигурацию таблиц лидеров в условия сезонов с призами
+func newLeaderboardRule(cfg config.LeaderboardConfig) models.LeaderboardRule {
+	rule := models.LeaderboardRule{MinGames: cfg.MinGames}
+	for _, season := range cfg.Seasons {
+		rule.Seasons = append(rule.Seasons, models.LeaderboardSeason{
+			Period:   season.Period,
+			Metric:   season.Metric,
+			Currency: season.Currency,
+			MinGames: season.MinGames,
+			Prizes:   season.Prizes,
+		})
+	}
+	return rule
+}
+
 // Shutdown выполняет корректное завершение работы приложения
 func (a *App) Shutdown() {
 	// Закрытие соединений с базами данных
@@ -34,12 +34,22 @@ type Config struct {
 	UseMockProvider bool        `yaml:"use_mock_provider"`
 
 	// Компоненты
-	HTTP       HTTPConfig       `yaml:"http"`
-	Postgres   PostgresConfig   `yaml:"postgres"`
-	Auth       AuthConfig       `yaml:"auth"`
-	Metrics    MetricsConfig    `yaml:"metrics"`
-	Logging    logger.Config    `yaml:"logging"`
-	Blockchain BlockchainConfig `yaml:"blockchain"`
+	HTTP          HTTPConfig          `yaml:"http"`
+	Postgres      PostgresConfig      `yaml:"postgres"`
+	Auth          AuthConfig          `yaml:"auth"`
+	Telegram      TelegramConfig      `yaml:"telegram"`
+	Metrics       MetricsConfig       `yaml:"metrics"`
+	Logging       logger.Config       `yaml:"logging"`
+	Blockchain    BlockchainConfig    `yaml:"blockchain"`
+	Dictionary    DictionaryConfig    `yaml:"dictionary"`
+	Jackpot       JackpotConfig       `yaml:"jackpot"`
+	Commission    CommissionConfig    `yaml:"commission"`
+	Referral      ReferralConfig      `yaml:"referral"`
+	Achievements  AchievementsConfig  `yaml:"achievements"`
+	Leaderboard   LeaderboardConfig   `yaml:"leaderboard"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Events        EventsConfig        `yaml:"events"`
+	Webhooks      WebhooksConfig      `yaml:"webhooks"`
 }
 
 // HTTPConfig представляет конфигурацию HTTP-сервера
@@ -66,6 +76,41 @@ type AuthConfig struct {
 	JWTSecret string        `yaml:"jwt_secret"`
 	BotToken  string        `yaml:"bot_token"`
 	TokenTTL  time.Duration `yaml:"token_ttl"`
+	AdminIDs  []uint64      `yaml:"admin_ids"` // Telegram ID администраторов (отчёты по комиссии)
+}
+
+// TelegramConfig представляет настройки Telegram бота и Mini App
+type TelegramConfig struct {
+	BotUsername   string `yaml:"bot_username"`   // Username бота без @ (для ссылок t.me)
+	MiniAppName   string `yaml:"mini_app_name"`  // Короткое имя Mini App (пусто - основное приложение бота)
+	APIEndpoint   string `yaml:"api_endpoint"`   // Адрес Bot API (пусто - https://api.telegram.org)
+	WebhookURL    string `yaml:"webhook_url"`    // Публичный адрес /api/v1/telegram/webhook (пусто - webhook не регистрируется при запуске)
+	WebhookSecret string `yaml:"webhook_secret"` // Секрет заголовка X-Telegram-Bot-Api-Secret-Token (пусто - выводится из токена бота)
+}
+
+// NotificationsConfig представляет конфигурацию уведомлений бота
+type NotificationsConfig struct {
+	MaxAttempts   int           `yaml:"max_attempts"`    // Попыток отправки сообщения (0 - 5)
+	RetryDelay    time.Duration `yaml:"retry_delay"`     // Задержка перед первым повтором, далее удваивается (0 - 30s)
+	RatePerSecond int           `yaml:"rate_per_second"` // Сообщений в секунду (0 - 25, лимит Bot API - 30)
+	BatchSize     int           `yaml:"batch_size"`      // Сообщений за запуск фоновой задачи (0 - 100)
+	ExpiryNotice  time.Duration `yaml:"expiry_notice"`   // За сколько до истечения лобби напоминать игроку (0 - 2m)
+}
+
+// EventsConfig представляет конфигурацию доставки доменных событий подписчикам
+type EventsConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"` // Попыток доставки до перевода в dead letter (0 - 8)
+	RetryDelay  time.Duration `yaml:"retry_delay"`  // Задержка перед первым повтором, далее удваивается (0 - 10s)
+	BatchSize   int           `yaml:"batch_size"`   // Событий за запуск фоновой задачи (0 - 200)
+}
+
+// WebhooksConfig представляет конфигурацию доставки исходящих webhook
+type WebhooksConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"` // Попыток доставки до статуса failed (0 - 8)
+	RetryDelay  time.Duration `yaml:"retry_delay"`  // Задержка перед первым повтором, далее удваивается (0 - 30s)
+	BatchSize   int           `yaml:"batch_size"`   // Доставок за запуск фоновой задачи (0 - 100)
+	Timeout     time.Duration `yaml:"timeout"`      // Время ожидания ответа получателя (0 - 10s)
+	MaxPerUser  int           `yaml:"max_per_user"` // Webhook на одного пользователя (0 - 10)
 }
 
 // MetricsConfig представляет конфигурацию для метрик Prometheus
@@ -74,6 +119,63 @@ type MetricsConfig struct {
 	Port    string `yaml:"port"`
 }
 
+// DictionaryConfig представляет конфигурацию словаря слов
+type DictionaryConfig struct {
+	Path string `yaml:"path"` // Файл со словами (по одному на строку), пусто - встроенный словарь
+}
+
+// CommissionConfig представляет конфигурацию комиссии сервиса
+type CommissionConfig struct {
+	DefaultRate  float64                `yaml:"default_rate"`  // Ставка по умолчанию (0 - blockchain.ton.commission_rate)
+	Tiers        []CommissionTierConfig `yaml:"tiers"`         // Уровни по обороту ставок в играх создателя за 30 дней
+	CreatorRates map[uint64]float64     `yaml:"creator_rates"` // Ставки для отдельных создателей (Telegram ID)
+	GameRates    map[string]float64     `yaml:"game_rates"`    // Ставки для отдельных игр (ID игры)
+}
+
+// CommissionTierConfig представляет уровень комиссии по обороту создателя
+type CommissionTierConfig struct {
+	MinVolume float64 `yaml:"min_volume"`
+	Rate      float64 `yaml:"rate"`
+}
+
+// JackpotConfig представляет конфигурацию джекпота платформы
+type JackpotConfig struct {
+	CommissionShare float64 `yaml:"commission_share"` // Доля комиссии с проигранных ставок, уходящая в джекпот (0 - выключен)
+	MaxTries        int     `yaml:"max_tries"`        // Джекпот выигрывает угадавший слово не более чем за столько попыток
+	MinBet          float64 `yaml:"min_bet"`          // Минимальная ставка лобби для розыгрыша джекпота
+}
+
+// ReferralConfig представляет конфигурацию реферальной программы
+type ReferralConfig struct {
+	CommissionShare float64       `yaml:"commission_share"` // Доля комиссии с приглашённых пользователей, получаемая реферером (0 - выключена)
+	Period          time.Duration `yaml:"period"`           // Сколько реферер получает долю после регистрации приглашённого
+}
+
+// AchievementsConfig представляет конфигурацию наград за достижения и ежедневный вход.
+// Награды зачисляются на бонусный баланс с отыгрышем в размере награды
+type AchievementsConfig struct {
+	Currency           string             `yaml:"currency"`              // Валюта наград (TON или USDT)
+	Rewards            map[string]float64 `yaml:"rewards"`               // Награды по кодам достижений (нет - только бейдж)
+	DailyReward        float64            `yaml:"daily_reward"`          // Награда за первый день серии входов (0 - выключена)
+	DailyRewardStep    float64            `yaml:"daily_reward_step"`     // Прибавка за каждый следующий день серии
+	DailyRewardMaxDays int                `yaml:"daily_reward_max_days"` // День серии, после которого награда перестаёт расти
+}
+
+// LeaderboardConfig представляет конфигурацию таблиц лидеров
+type LeaderboardConfig struct {
+	MinGames int                       `yaml:"min_games"` // Минимум лобби для рейтингов по доле побед и эффективности
+	Seasons  []LeaderboardSeasonConfig `yaml:"seasons"`   // Сезоны с автоматической выплатой призов
+}
+
+// LeaderboardSeasonConfig представляет сезон таблицы лидеров с призами
+type LeaderboardSeasonConfig struct {
+	Period   string    `yaml:"period"`    // daily, weekly или monthly
+	Metric   string    `yaml:"metric"`    // profit, win_rate или efficiency
+	Currency string    `yaml:"currency"`  // Валюта таблицы и призов
+	MinGames int       `yaml:"min_games"` // 0 - как для публичной таблицы
+	Prizes   []float64 `yaml:"prizes"`    // Призы по местам, начиная с первого
+}
+
 // BlockchainConfig представляет конфигурацию блокчейна
 type BlockchainConfig struct {
 	TON      TONConfig      `yaml:"ton"`
@@ -200,6 +302,20 @@ func defaultConfig() *Config {
 		Logging: logger.Config{
 			Level: "debug",
 		},
+		Jackpot: JackpotConfig{
+			CommissionShare: 0.1,
+			MaxTries:        1,
+		},
+		Referral: ReferralConfig{
+			CommissionShare: 0.1,
+			Period:          90 * 24 * time.Hour,
+		},
+		Achievements: AchievementsConfig{
+			Currency: "TON",
+		},
+		Leaderboard: LeaderboardConfig{
+			MinGames: 10,
+		},
 		Blockchain: BlockchainConfig{
 			TON: TONConfig{
 				APIEndpoint:           "https://testnet.toncenter.com/api/v3",
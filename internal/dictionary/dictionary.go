@@ -0,0 +1,212 @@
+// Package dictionary содержит словарь допустимых слов и утилиты для анализа подсказок Wordle
+package dictionary
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Значения подсказок для каждой буквы
+const (
+	ResultAbsent  = 0 // Буквы нет в слове
+	ResultPresent = 1 // Буква есть, но не на своём месте
+	ResultCorrect = 2 // Буква на своём месте
+)
+
+//go:embed words_ru.txt words_en.txt
+var defaultFiles embed.FS
+
+var (
+	defaultOnce sync.Once
+	defaultDict *Dictionary
+)
+
+// Guess представляет собой слово попытки и полученную подсказку
+type Guess struct {
+	Word   string
+	Result []int
+}
+
+// Dictionary представляет собой словарь слов, сгруппированных по длине
+type Dictionary struct {
+	byLength map[int][]string
+	index    map[string]struct{}
+
+	statsMu sync.Mutex
+	stats   map[int]*lengthStats // Статистика для анализа сложности (вычисляется при первом обращении)
+}
+
+// New создает словарь из списка слов
+func New(words []string) *Dictionary {
+	d := &Dictionary{
+		byLength: make(map[int][]string),
+		index:    make(map[string]struct{}),
+		stats:    make(map[int]*lengthStats),
+	}
+	for _, w := range words {
+		d.add(w)
+	}
+	return d
+}
+
+// Default возвращает встроенный словарь (русские и английские слова)
+func Default() *Dictionary {
+	defaultOnce.Do(func() {
+		defaultDict = New(nil)
+		for _, name := range []string{"words_ru.txt", "words_en.txt"} {
+			f, err := defaultFiles.Open(name)
+			if err != nil {
+				continue
+			}
+			_ = defaultDict.read(f)
+			f.Close()
+		}
+	})
+	return defaultDict
+}
+
+// Load загружает словарь из файла (одно слово на строку, строки с # игнорируются)
+func Load(path string) (*Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary: %w", err)
+	}
+	defer f.Close()
+
+	d := New(nil)
+	if err := d.read(f); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+	return d, nil
+}
+
+// read читает слова из потока
+func (d *Dictionary) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		d.add(line)
+	}
+	return scanner.Err()
+}
+
+// add добавляет слово в словарь
+func (d *Dictionary) add(word string) {
+	word = Normalize(word)
+	if word == "" {
+		return
+	}
+	if _, ok := d.index[word]; ok {
+		return
+	}
+	d.index[word] = struct{}{}
+	length := len([]rune(word))
+	d.byLength[length] = append(d.byLength[length], word)
+}
+
+// Size возвращает количество слов в словаре
+func (d *Dictionary) Size() int {
+	return len(d.index)
+}
+
+// Words возвращает слова заданной длины
+func (d *Dictionary) Words(length int) []string {
+	return d.byLength[length]
+}
+
+// Contains проверяет, есть ли слово в словаре
+func (d *Dictionary) Contains(word string) bool {
+	_, ok := d.index[Normalize(word)]
+	return ok
+}
+
+// Consistent возвращает слова заданной длины, не противоречащие подсказкам
+func (d *Dictionary) Consistent(length int, guesses []Guess) []string {
+	var result []string
+	for _, w := range d.byLength[length] {
+		if IsConsistent(w, guesses) {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// CountConsistent возвращает количество слов заданной длины, не противоречащих подсказкам
+func (d *Dictionary) CountConsistent(length int, guesses []Guess) int {
+	count := 0
+	for _, w := range d.byLength[length] {
+		if IsConsistent(w, guesses) {
+			count++
+		}
+	}
+	return count
+}
+
+// Normalize приводит слово к нижнему регистру и убирает пробелы
+func Normalize(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
+
+// Feedback вычисляет подсказку для слова относительно загаданного
+// (2 - буква на месте, 1 - буква есть в слове, 0 - буквы нет)
+func Feedback(word, target string) []int {
+	wordRunes := []rune(strings.ToLower(word))
+	targetRunes := []rune(strings.ToLower(target))
+
+	if len(wordRunes) != len(targetRunes) {
+		return nil
+	}
+
+	result := make([]int, len(targetRunes))
+	targetUsed := make([]bool, len(targetRunes))
+	wordUsed := make([]bool, len(wordRunes))
+
+	// Первый проход: точные совпадения (зелёные)
+	for i := 0; i < len(wordRunes); i++ {
+		if wordRunes[i] == targetRunes[i] {
+			result[i] = ResultCorrect
+			targetUsed[i] = true
+			wordUsed[i] = true
+		}
+	}
+
+	// Второй проход: буквы есть, но не на месте (жёлтые)
+	for i := 0; i < len(wordRunes); i++ {
+		if wordUsed[i] {
+			continue
+		}
+		for j := 0; j < len(targetRunes); j++ {
+			if !targetUsed[j] && wordRunes[i] == targetRunes[j] {
+				result[i] = ResultPresent
+				targetUsed[j] = true
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// IsConsistent проверяет, могло ли слово-кандидат дать такие подсказки
+func IsConsistent(candidate string, guesses []Guess) bool {
+	for _, g := range guesses {
+		fb := Feedback(g.Word, candidate)
+		if len(fb) != len(g.Result) {
+			return false
+		}
+		for i := range fb {
+			if fb[i] != g.Result[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
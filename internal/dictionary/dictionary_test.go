@@ -0,0 +1,114 @@
+package dictionary
+
+import "testing"
+
+func TestFeedback(t *testing.T) {
+	tests := []struct {
+		name     string
+		word     string
+		target   string
+		expected []int
+	}{
+		{name: "полное совпадение", word: "слово", target: "слово", expected: []int{2, 2, 2, 2, 2}},
+		{name: "буквы переставлены", word: "колос", target: "сокол", expected: []int{1, 2, 1, 2, 1}},
+		{name: "лишняя повторяющаяся буква серая", word: "олово", target: "слово", expected: []int{0, 2, 2, 2, 2}},
+		{name: "разная длина", word: "слон", target: "слово", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Feedback(tt.word, tt.target)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Feedback() = %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Feedback()[%d] = %d, want %d", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDictionary_CountConsistent(t *testing.T) {
+	d := New([]string{"слово", "слава", "сокол", "Сонар", "кот"})
+
+	if d.Size() != 5 {
+		t.Fatalf("Size() = %d, want 5", d.Size())
+	}
+	if !d.Contains("СОНАР") {
+		t.Error("Contains() should ignore case")
+	}
+
+	guesses := []Guess{{Word: "сокол", Result: Feedback("сокол", "слово")}}
+	if got := d.CountConsistent(5, guesses); got != 1 {
+		t.Errorf("CountConsistent() = %d, want 1", got)
+	}
+	if got := d.CountConsistent(5, nil); got != 4 {
+		t.Errorf("CountConsistent() without guesses = %d, want 4", got)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if len(Default().Words(5)) == 0 {
+		t.Error("built-in dictionary has no 5-letter words")
+	}
+}
+
+func TestDictionary_Analyze(t *testing.T) {
+	d := New([]string{"cater", "hater", "later", "water", "mater", "rater", "crane", "slate", "stare", "jazzy", "fuzzy", "pizza"})
+
+	common := d.Analyze("Crane")
+	if common.Word != "crane" || !common.InDictionary {
+		t.Errorf("Analyze(Crane) = %+v, want normalized dictionary word", common)
+	}
+	if common.Score < 0 || common.Score > 100 {
+		t.Errorf("Score = %v, want 0-100", common.Score)
+	}
+
+	// У слова много соседей: каждую букву приходится угадывать отдельной попыткой
+	crowded := d.Analyze("hater")
+	if crowded.Neighbors != 5 {
+		t.Errorf("Neighbors(hater) = %d, want 5", crowded.Neighbors)
+	}
+	if crowded.Score <= common.Score {
+		t.Errorf("Score(hater) = %v, want greater than Score(crane) = %v", crowded.Score, common.Score)
+	}
+
+	// Редкие и повторяющиеся буквы
+	rare := d.Analyze("jazzy")
+	if rare.RepeatedLetters != 1 {
+		t.Errorf("RepeatedLetters(jazzy) = %d, want 1", rare.RepeatedLetters)
+	}
+	if rare.LetterRarity <= common.LetterRarity {
+		t.Errorf("LetterRarity(jazzy) = %v, want greater than LetterRarity(crane) = %v", rare.LetterRarity, common.LetterRarity)
+	}
+
+	// Слово не из словаря всё равно оценивается
+	unknown := d.Analyze("zzzzz")
+	if unknown.InDictionary || unknown.SolverTries == 0 {
+		t.Errorf("Analyze(zzzzz) = %+v, want solver result for unknown word", unknown)
+	}
+
+	if empty := d.Analyze(""); empty.Score != 0 || empty.Level != LevelEasy {
+		t.Errorf("Analyze(\"\") = %+v, want zero easy score", empty)
+	}
+}
+
+func TestLevelOf(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{score: 0, want: LevelEasy},
+		{score: levelMediumScore, want: LevelMedium},
+		{score: levelHardScore, want: LevelHard},
+		{score: 1, want: LevelHard},
+	}
+
+	for _, tt := range tests {
+		if got := levelOf(tt.score); got != tt.want {
+			t.Errorf("levelOf(%v) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
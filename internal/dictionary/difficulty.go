@@ -0,0 +1,259 @@
+package dictionary
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// Уровни сложности слова
+const (
+	LevelEasy   = "easy"
+	LevelMedium = "medium"
+	LevelHard   = "hard"
+)
+
+// Параметры анализа сложности
+const (
+	solverGuessPool  = 200  // Сколько слов с самыми частыми буквами решатель рассматривает как очередной ход
+	solverOpenings   = 3    // Сколько лучших стартовых слов усредняется
+	solverMaxTries   = 10   // Предел ходов решателя
+	neighborsCap     = 8    // Число соседей, при котором их вклад в сложность максимален
+	levelMediumScore = 0.35 // Нижняя граница оценки для medium
+	levelHardScore   = 0.6  // Нижняя граница оценки для hard
+)
+
+// Analysis результат анализа сложности загаданного слова
+type Analysis struct {
+	Word            string
+	Score           float64 // Итоговая оценка от 0 (лёгкое) до 100 (сложное)
+	Level           string  // easy, medium или hard
+	InDictionary    bool    // Слово есть в словаре
+	LetterRarity    float64 // Редкость букв относительно слов той же длины (0 - частые, 1 - редкие)
+	RepeatedLetters int     // Число повторов букв
+	Neighbors       int     // Слов словаря, отличающихся от загаданного одной буквой
+	SolverTries     float64 // Среднее число попыток решателя, максимизирующего энтропию подсказок (0 - слов такой длины нет)
+}
+
+// lengthStats статистика слов одной длины, общая для всех анализов
+type lengthStats struct {
+	words    []string         // Слова по убыванию частоты букв: самые информативные ходы первыми
+	share    map[rune]float64 // Доля слов, содержащих букву
+	average  float64          // Средняя частота букв слова
+	openings []string         // Лучшие стартовые слова решателя
+}
+
+// Analyze оценивает сложность загаданного слова по словарю: частоте букв, повторам,
+// числу соседних слов (отличающихся одной буквой) и числу попыток, которое нужно решателю,
+// выбирающему ход с максимальной энтропией подсказок
+func (d *Dictionary) Analyze(word string) *Analysis {
+	word = Normalize(word)
+	runes := []rune(word)
+	letters := distinctLetters(runes)
+
+	analysis := &Analysis{
+		Word:            word,
+		InDictionary:    d.Contains(word),
+		LetterRarity:    0.5,
+		RepeatedLetters: len(runes) - len(letters),
+	}
+	if len(runes) == 0 {
+		analysis.Level = levelOf(0)
+		return analysis
+	}
+
+	stats := d.lengthStats(len(runes))
+	solverScore := 0.5
+	if len(stats.words) > 0 {
+		if stats.average > 0 {
+			analysis.LetterRarity = clamp01(0.5 + 0.5*(1-stats.commonness(letters)/stats.average))
+		}
+		analysis.Neighbors = countNeighbors(runes, stats.words)
+
+		// Решатель не знает загаданного слова, но должен уметь его назвать
+		words := stats.words
+		if !analysis.InDictionary {
+			words = append(slices.Clone(words), word)
+		}
+		total := 0
+		for _, opening := range stats.openings {
+			total += solve(word, opening, words)
+		}
+		analysis.SolverTries = float64(total) / float64(len(stats.openings))
+		solverScore = clamp01((analysis.SolverTries - 2) / 4)
+	}
+
+	score := 0.2*analysis.LetterRarity +
+		0.1*math.Min(1, float64(analysis.RepeatedLetters)/2) +
+		0.3*math.Min(1, float64(analysis.Neighbors)/neighborsCap) +
+		0.4*solverScore
+
+	analysis.Score = math.Round(score*1000) / 10
+	analysis.Level = levelOf(score)
+	return analysis
+}
+
+// lengthStats возвращает статистику слов заданной длины, вычисляя её при первом обращении
+func (d *Dictionary) lengthStats(length int) *lengthStats {
+	d.statsMu.Lock()
+	defer d.statsMu.Unlock()
+
+	if stats, ok := d.stats[length]; ok {
+		return stats
+	}
+	stats := newLengthStats(d.byLength[length])
+	d.stats[length] = stats
+	return stats
+}
+
+// newLengthStats вычисляет частоты букв и лучшие стартовые слова
+func newLengthStats(words []string) *lengthStats {
+	stats := &lengthStats{share: make(map[rune]float64)}
+	if len(words) == 0 {
+		return stats
+	}
+
+	for _, w := range words {
+		for r := range distinctLetters([]rune(w)) {
+			stats.share[r]++
+		}
+	}
+	for r := range stats.share {
+		stats.share[r] /= float64(len(words))
+	}
+
+	commonness := make(map[string]float64, len(words))
+	for _, w := range words {
+		commonness[w] = stats.commonness(distinctLetters([]rune(w)))
+		stats.average += commonness[w]
+	}
+	stats.average /= float64(len(words))
+
+	stats.words = slices.Clone(words)
+	sort.SliceStable(stats.words, func(i, j int) bool {
+		if commonness[stats.words[i]] != commonness[stats.words[j]] {
+			return commonness[stats.words[i]] > commonness[stats.words[j]]
+		}
+		return stats.words[i] < stats.words[j]
+	})
+
+	stats.openings = bestGuesses(stats.words, solverOpenings)
+	return stats
+}
+
+// commonness возвращает среднюю долю слов, содержащих буквы
+func (s *lengthStats) commonness(letters map[rune]struct{}) float64 {
+	if len(letters) == 0 {
+		return 0
+	}
+	var sum float64
+	for r := range letters {
+		sum += s.share[r]
+	}
+	return sum / float64(len(letters))
+}
+
+// solve возвращает число попыток, за которое решатель угадывает target, начиная с opening.
+// Каждый следующий ход - слово из оставшихся кандидатов с максимальной энтропией подсказок
+func solve(target, opening string, words []string) int {
+	candidates := words
+	guess := opening
+	for tries := 1; ; tries++ {
+		if guess == target || tries >= solverMaxTries {
+			return tries
+		}
+
+		candidates = narrowCandidates(candidates, guess, feedbackKey(Feedback(guess, target)))
+		if len(candidates) == 0 {
+			return solverMaxTries
+		}
+
+		guess = bestGuesses(candidates, 1)[0]
+	}
+}
+
+// bestGuesses возвращает n ходов с максимальной энтропией подсказок относительно кандидатов.
+// Ходы выбираются из первых solverGuessPool кандидатов (с самыми частыми буквами)
+func bestGuesses(candidates []string, n int) []string {
+	pool := candidates[:min(len(candidates), solverGuessPool)]
+	if len(candidates) <= 2 {
+		return pool[:min(len(pool), n)]
+	}
+
+	entropy := make(map[string]float64, len(pool))
+	counts := make(map[int]int)
+	for _, guess := range pool {
+		clear(counts)
+		for _, c := range candidates {
+			counts[feedbackKey(Feedback(guess, c))]++
+		}
+		var h float64
+		for _, count := range counts {
+			p := float64(count) / float64(len(candidates))
+			h -= p * math.Log2(p)
+		}
+		entropy[guess] = h
+	}
+
+	ranked := slices.Clone(pool)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return entropy[ranked[i]] > entropy[ranked[j]]
+	})
+	return ranked[:min(len(ranked), n)]
+}
+
+// feedbackKey кодирует подсказку числом в троичной системе
+func feedbackKey(result []int) int {
+	key := 0
+	for _, r := range result {
+		key = key*3 + r
+	}
+	return key
+}
+
+// countNeighbors возвращает число слов, отличающихся от слова ровно одной буквой
+func countNeighbors(runes []rune, words []string) int {
+	count := 0
+	for _, w := range words {
+		other := []rune(w)
+		if len(other) != len(runes) {
+			continue
+		}
+		diff := 0
+		for i := range runes {
+			if runes[i] != other[i] {
+				diff++
+			}
+		}
+		if diff == 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// distinctLetters возвращает множество букв слова
+func distinctLetters(runes []rune) map[rune]struct{} {
+	letters := make(map[rune]struct{}, len(runes))
+	for _, r := range runes {
+		letters[r] = struct{}{}
+	}
+	return letters
+}
+
+// levelOf переводит оценку (0-1) в уровень сложности
+func levelOf(score float64) string {
+	switch {
+	case score >= levelHardScore:
+		return LevelHard
+	case score >= levelMediumScore:
+		return LevelMedium
+	default:
+		return LevelEasy
+	}
+}
+
+// clamp01 ограничивает значение отрезком [0, 1]
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(v, 1))
+}
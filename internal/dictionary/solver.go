@@ -0,0 +1,51 @@
+package dictionary
+
+// Solver угадывает загаданное слово по подсказкам: каждый ход - слово с максимальной энтропией
+// подсказок относительно оставшихся кандидатов
+type Solver struct {
+	candidates []string
+	opening    string
+}
+
+// NewSolver создает решатель для слов заданной длины
+func (d *Dictionary) NewSolver(length int) *Solver {
+	stats := d.lengthStats(length)
+	solver := &Solver{candidates: stats.words}
+	if len(stats.openings) > 0 {
+		solver.opening = stats.openings[0]
+	}
+	return solver
+}
+
+// Guess возвращает следующий ход ("" - кандидатов не осталось)
+func (s *Solver) Guess() string {
+	if s.opening != "" {
+		return s.opening
+	}
+	if len(s.candidates) == 0 {
+		return ""
+	}
+	return bestGuesses(s.candidates, 1)[0]
+}
+
+// Candidates возвращает слова, не противоречащие полученным подсказкам
+func (s *Solver) Candidates() []string {
+	return s.candidates
+}
+
+// Update учитывает подсказку на ход
+func (s *Solver) Update(guess string, result []int) {
+	s.opening = ""
+	s.candidates = narrowCandidates(s.candidates, guess, feedbackKey(result))
+}
+
+// narrowCandidates оставляет кандидатов, для которых ход дал бы ту же подсказку
+func narrowCandidates(candidates []string, guess string, key int) []string {
+	next := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		if feedbackKey(Feedback(guess, c)) == key {
+			next = append(next, c)
+		}
+	}
+	return next
+}
@@ -1,7 +1,13 @@
 package mocks
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -124,6 +130,95 @@ func (m *MockUserRepository) UpdateWallet(ctx context.Context, telegramID uint64
 	return models.ErrUserNotFound
 }
 
+// bonusFields возвращает поля бонусного баланса, оборота ставок и основного баланса для валюты
+func bonusFields(user *models.User, currency string) (bonus, wager, balance *float64, err error) {
+	switch currency {
+	case models.CurrencyTON:
+		return &user.BonusTon, &user.WagerTon, &user.BalanceTon, nil
+	case models.CurrencyUSDT:
+		return &user.BonusUsdt, &user.WagerUsdt, &user.BalanceUsdt, nil
+	default:
+		return nil, nil, nil, errors.New("unsupported currency")
+	}
+}
+
+func (m *MockUserRepository) DebitBalance(ctx context.Context, telegramID uint64, currency string, amount float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[telegramID]
+	if !ok {
+		return models.ErrUserNotFound
+	}
+	_, _, balance, err := bonusFields(user, currency)
+	if err != nil {
+		return err
+	}
+	if !user.HasSufficientBalance(amount, currency) {
+		return models.ErrInsufficientFunds
+	}
+	*balance -= amount
+	user.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *MockUserRepository) UpdateBonusBalance(ctx context.Context, telegramID uint64, currency string, amount, wager float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[telegramID]
+	if !ok {
+		return models.ErrUserNotFound
+	}
+	bonus, remaining, _, err := bonusFields(user, currency)
+	if err != nil {
+		return err
+	}
+	if *bonus+amount < 0 {
+		return models.ErrInsufficientBonus
+	}
+	*bonus += amount
+	*remaining = max(*remaining+wager, 0)
+	user.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *MockUserRepository) SpendBonusBalance(ctx context.Context, telegramID uint64, currency string, amount float64) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[telegramID]
+	if !ok {
+		return 0, models.ErrUserNotFound
+	}
+	bonus, _, _, err := bonusFields(user, currency)
+	if err != nil {
+		return 0, err
+	}
+	spent := min(*bonus, amount)
+	*bonus -= spent
+	user.UpdatedAt = time.Now()
+	return spent, nil
+}
+
+func (m *MockUserRepository) ConvertBonusBalance(ctx context.Context, telegramID uint64, currency string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[telegramID]
+	if !ok {
+		return 0, models.ErrUserNotFound
+	}
+	bonus, remaining, balance, err := bonusFields(user, currency)
+	if err != nil {
+		return 0, err
+	}
+	if *remaining > 0 || *bonus <= 0 {
+		return 0, nil
+	}
+	converted := *bonus
+	*balance += converted
+	*bonus = 0
+	user.UpdatedAt = time.Now()
+	return converted, nil
+}
+
 func (m *MockUserRepository) UpdatePendingWithdrawal(ctx context.Context, telegramID uint64, amount float64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -192,13 +287,27 @@ func (m *MockUserRepository) ValidateBalance(ctx context.Context, userID uint64,
 
 // MockGameRepository мок для GameRepository
 type MockGameRepository struct {
-	mu    sync.RWMutex
-	games map[uuid.UUID]*models.Game
+	mu          sync.RWMutex
+	games       map[uuid.UUID]*models.Game
+	reputations map[uint64]float64 // Репутация создателей, как в creator_reputations
 }
 
 func NewMockGameRepository() *MockGameRepository {
 	return &MockGameRepository{
-		games: make(map[uuid.UUID]*models.Game),
+		games:       make(map[uuid.UUID]*models.Game),
+		reputations: make(map[uint64]float64),
+	}
+}
+
+// SetCreatorReputation сохраняет репутацию создателя и обновляет её в его играх
+func (m *MockGameRepository) SetCreatorReputation(creatorID uint64, score float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reputations[creatorID] = score
+	for _, game := range m.games {
+		if game.CreatorID == creatorID {
+			game.CreatorReputation = score
+		}
 	}
 }
 
@@ -212,6 +321,10 @@ func (m *MockGameRepository) Create(ctx context.Context, game *models.Game) erro
 		game.CreatedAt = time.Now()
 	}
 	game.UpdatedAt = time.Now()
+	game.CreatorReputation = models.DefaultReputationScore
+	if score, ok := m.reputations[game.CreatorID]; ok {
+		game.CreatorReputation = score
+	}
 	m.games[game.ID] = game
 	return nil
 }
@@ -236,6 +349,17 @@ func (m *MockGameRepository) GetByShortID(ctx context.Context, shortID string) (
 	return nil, models.ErrGameNotFound
 }
 
+func (m *MockGameRepository) GetByInviteToken(ctx context.Context, token string) (*models.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, game := range m.games {
+		if token != "" && game.InviteToken == token {
+			return game, nil
+		}
+	}
+	return nil, models.ErrGameNotFound
+}
+
 func (m *MockGameRepository) GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*models.Game, error) {
 	return m.GetByCreator(ctx, userID, limit, offset)
 }
@@ -257,7 +381,7 @@ func (m *MockGameRepository) GetActive(ctx context.Context, limit, offset int) (
 	defer m.mu.RUnlock()
 	var games []*models.Game
 	for _, game := range m.games {
-		if game.Status == models.GameStatusActive {
+		if game.Status == models.GameStatusActive && !game.IsPrivate() && !game.IsEnded(time.Now()) {
 			games = append(games, game)
 		}
 	}
@@ -318,27 +442,109 @@ func (m *MockGameRepository) UpdateRewardPool(ctx context.Context, id uuid.UUID,
 	return models.ErrGameNotFound
 }
 
-func (m *MockGameRepository) SearchGames(ctx context.Context, minBet, maxBet float64, difficulty string, limit, offset int) ([]*models.Game, error) {
+func (m *MockGameRepository) SearchGames(ctx context.Context, filter models.GameSearchFilter, limit int, cursor *models.PageCursor) ([]*models.Game, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	sortBy := filter.Sort
+	if sortBy == "" {
+		sortBy = models.GameSortNewest
+	}
 	var games []*models.Game
 	for _, game := range m.games {
-		if game.Status == models.GameStatusActive {
-			if minBet > 0 && game.MinBet < minBet {
+		if game.Status == models.GameStatusActive && !game.IsPrivate() && !game.IsEnded(time.Now()) {
+			if filter.MinBet > 0 && game.MinBet < filter.MinBet {
+				continue
+			}
+			if filter.MaxBet > 0 && game.MaxBet > filter.MaxBet {
+				continue
+			}
+			if filter.Difficulty != "" && game.Difficulty != filter.Difficulty {
+				continue
+			}
+			if filter.MinDifficultyScore > 0 && game.DifficultyScore < filter.MinDifficultyScore {
+				continue
+			}
+			if filter.MaxDifficultyScore > 0 && game.DifficultyScore > filter.MaxDifficultyScore {
 				continue
 			}
-			if maxBet > 0 && game.MaxBet > maxBet {
+			if filter.Currency != "" && game.Currency != filter.Currency {
 				continue
 			}
-			if difficulty != "" && game.Difficulty != difficulty {
+			if filter.WordLength > 0 && game.Length != filter.WordLength {
+				continue
+			}
+			if filter.MinMultiplier > 0 && game.RewardMultiplier < filter.MinMultiplier {
+				continue
+			}
+			if filter.MaxMultiplier > 0 && game.RewardMultiplier > filter.MaxMultiplier {
+				continue
+			}
+			if filter.MinTimeLimit > 0 && game.TimeLimit < filter.MinTimeLimit {
+				continue
+			}
+			if filter.MaxTimeLimit > 0 && game.TimeLimit > filter.MaxTimeLimit {
+				continue
+			}
+			if filter.MinPoolBets > 0 && game.GetAvailableRewardPool() < filter.MinPoolBets*game.MaxBet {
+				continue
+			}
+			if filter.CreatorID != 0 && game.CreatorID != filter.CreatorID {
+				continue
+			}
+			if filter.Query != "" && !matchesQuery(game.Title+" "+game.Description, filter.Query) {
+				continue
+			}
+			if cursor != nil && !pageAfter(game.Cursor(sortBy), cursor) {
 				continue
 			}
 			games = append(games, game)
 		}
 	}
+	sort.Slice(games, func(i, j int) bool {
+		return pageAfter(games[j].Cursor(sortBy), games[i].Cursor(sortBy))
+	})
+	if limit > 0 && len(games) > limit {
+		games = games[:limit]
+	}
 	return games, nil
 }
 
+// matchesQuery проверяет, что текст содержит все слова запроса (упрощённый полнотекстовый поиск)
+func matchesQuery(text, query string) bool {
+	text = strings.ToLower(text)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// pageAfter проверяет, что запись с ключом key идёт после cursor при сортировке по убыванию ключа и ID
+func pageAfter(key, cursor *models.PageCursor) bool {
+	if !key.Time.Equal(cursor.Time) {
+		return key.Time.Before(cursor.Time)
+	}
+	if key.Value != cursor.Value {
+		return key.Value < cursor.Value
+	}
+	return bytes.Compare(key.ID[:], cursor.ID[:]) < 0
+}
+
+func (m *MockGameRepository) RecordPlay(ctx context.Context, id uuid.UUID, won bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	game, ok := m.games[id]
+	if !ok {
+		return models.ErrGameNotFound
+	}
+	game.Plays++
+	if won {
+		game.Wins++
+	}
+	return nil
+}
+
 func (m *MockGameRepository) UpdateReservedAmount(ctx context.Context, id uuid.UUID, reservedAmount float64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -375,6 +581,140 @@ func (m *MockGameRepository) DecrementReservedAmount(ctx context.Context, id uui
 	return models.ErrGameNotFound
 }
 
+func (m *MockGameRepository) GetDueToStart(ctx context.Context, now time.Time, limit int) ([]*models.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var games []*models.Game
+	for _, game := range m.games {
+		if game.Status == models.GameStatusScheduled && game.StartsAt != nil && !game.StartsAt.After(now) {
+			games = append(games, game)
+		}
+	}
+	return games, nil
+}
+
+func (m *MockGameRepository) GetDueToClose(ctx context.Context, now time.Time, limit int) ([]*models.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var games []*models.Game
+	for _, game := range m.games {
+		if game.Status != models.GameStatusClosing && game.Status != models.GameStatusClosed && game.IsEnded(now) {
+			games = append(games, game)
+		}
+	}
+	return games, nil
+}
+
+func (m *MockGameRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	game, ok := m.games[id]
+	if !ok {
+		return false, models.ErrGameNotFound
+	}
+	if game.Status != from {
+		return false, nil
+	}
+	game.Status = to
+	game.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *MockGameRepository) IncrementRewardPool(ctx context.Context, id uuid.UUID, amount float64) error {
+	return m.adjustRewardPool(id, amount)
+}
+
+func (m *MockGameRepository) DecrementRewardPool(ctx context.Context, id uuid.UUID, amount float64) error {
+	return m.adjustRewardPool(id, -amount)
+}
+
+func (m *MockGameRepository) adjustRewardPool(id uuid.UUID, delta float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	game, ok := m.games[id]
+	if !ok {
+		return models.ErrGameNotFound
+	}
+	if game.Currency == models.CurrencyTON {
+		game.RewardPoolTon += delta
+	} else {
+		game.RewardPoolUsdt += delta
+	}
+	game.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *MockGameRepository) WithdrawRewardPool(ctx context.Context, id uuid.UUID, amount, margin float64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	game, ok := m.games[id]
+	if !ok {
+		return false, models.ErrGameNotFound
+	}
+	if game.GetAvailableRewardPool()-amount < margin {
+		return false, nil
+	}
+	if game.Currency == models.CurrencyTON {
+		game.RewardPoolTon -= amount
+	} else {
+		game.RewardPoolUsdt -= amount
+	}
+	game.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *MockGameRepository) GetBelowTopUpThreshold(ctx context.Context, limit int) ([]*models.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var games []*models.Game
+	for _, game := range m.games {
+		if game.Status == models.GameStatusActive && game.NeedsTopUp() {
+			games = append(games, game)
+		}
+	}
+	return games, nil
+}
+
+func (m *MockGameRepository) AddDailyLoss(ctx context.Context, id uuid.UUID, amount float64, day time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	game, ok := m.games[id]
+	if !ok {
+		return models.ErrGameNotFound
+	}
+	if game.DailyLossDay == nil || !game.DailyLossDay.Equal(day) {
+		game.DailyLoss = 0
+	}
+	game.DailyLoss += amount
+	game.DailyLossDay = &day
+	game.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *MockGameRepository) GetWithAutoOdds(ctx context.Context, limit int) ([]*models.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var games []*models.Game
+	for _, game := range m.games {
+		if game.Status == models.GameStatusActive && game.HasAutoOdds() {
+			games = append(games, game)
+		}
+	}
+	return games, nil
+}
+
+func (m *MockGameRepository) UpdateRewardMultiplier(ctx context.Context, id uuid.UUID, multiplier float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	game, ok := m.games[id]
+	if !ok {
+		return models.ErrGameNotFound
+	}
+	game.RewardMultiplier = multiplier
+	game.UpdatedAt = time.Now()
+	return nil
+}
+
 func (m *MockGameRepository) CountByUser(ctx context.Context, userID uint64) (int, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -448,6 +788,27 @@ func (m *MockTransactionRepository) GetByUserID(ctx context.Context, userID uint
 	return txs, nil
 }
 
+func (m *MockTransactionRepository) GetByUserIDAfter(ctx context.Context, userID uint64, limit int, cursor *models.PageCursor) ([]*models.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	key := func(tx *models.Transaction) *models.PageCursor {
+		return &models.PageCursor{Time: tx.CreatedAt, ID: tx.ID}
+	}
+	var txs []*models.Transaction
+	for _, tx := range m.transactions {
+		if tx.UserID == userID && (cursor == nil || pageAfter(key(tx), cursor)) {
+			txs = append(txs, tx)
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		return pageAfter(key(txs[j]), key(txs[i]))
+	})
+	if limit > 0 && len(txs) > limit {
+		txs = txs[:limit]
+	}
+	return txs, nil
+}
+
 func (m *MockTransactionRepository) GetByTxHash(ctx context.Context, txHash string) (*models.Transaction, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -497,6 +858,18 @@ func (m *MockTransactionRepository) GetByType(ctx context.Context, txType string
 	return txs, nil
 }
 
+func (m *MockTransactionRepository) SumByGame(ctx context.Context, gameID uuid.UUID) (map[string]float64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sums := make(map[string]float64)
+	for _, tx := range m.transactions {
+		if tx.GameID != nil && *tx.GameID == gameID && tx.Status == models.TransactionStatusCompleted {
+			sums[tx.Type] += tx.Amount
+		}
+	}
+	return sums, nil
+}
+
 func (m *MockTransactionRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.Transaction, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -629,6 +1002,27 @@ func (m *MockLobbyRepository) GetByUserID(ctx context.Context, userID uint64, li
 	return lobbies, nil
 }
 
+func (m *MockLobbyRepository) GetByUserIDAfter(ctx context.Context, userID uint64, limit int, cursor *models.PageCursor) ([]*models.Lobby, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	key := func(lobby *models.Lobby) *models.PageCursor {
+		return &models.PageCursor{Time: lobby.CreatedAt, ID: lobby.ID}
+	}
+	var lobbies []*models.Lobby
+	for _, lobby := range m.lobbies {
+		if lobby.UserID == userID && (cursor == nil || pageAfter(key(lobby), cursor)) {
+			lobbies = append(lobbies, lobby)
+		}
+	}
+	sort.Slice(lobbies, func(i, j int) bool {
+		return pageAfter(key(lobbies[j]), key(lobbies[i]))
+	})
+	if limit > 0 && len(lobbies) > limit {
+		lobbies = lobbies[:limit]
+	}
+	return lobbies, nil
+}
+
 func (m *MockLobbyRepository) GetPendingByGameShortID(ctx context.Context, gameShortID string, userID uint64) (*models.Lobby, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -678,6 +1072,21 @@ func (m *MockLobbyRepository) UpdateStatus(ctx context.Context, id uuid.UUID, st
 	return models.ErrLobbyNotFound
 }
 
+func (m *MockLobbyRepository) TransitionStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lobby, ok := m.lobbies[id]
+	if !ok {
+		return false, models.ErrLobbyNotFound
+	}
+	if lobby.Status != fromStatus {
+		return false, nil
+	}
+	lobby.Status = toStatus
+	lobby.UpdatedAt = time.Now()
+	return true, nil
+}
+
 func (m *MockLobbyRepository) UpdateTriesUsed(ctx context.Context, id uuid.UUID, triesUsed int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -918,6 +1327,7 @@ func (m *MockAttemptRepository) ValidateWord(ctx context.Context, word string) (
 type MockHistoryRepository struct {
 	mu        sync.RWMutex
 	histories map[uuid.UUID]*models.History
+	games     models.GameRepository // Для выборок по правилам игр (может быть nil)
 }
 
 func NewMockHistoryRepository() *MockHistoryRepository {
@@ -1049,3 +1459,2361 @@ func (m *MockHistoryRepository) GetGameHistoryStats(ctx context.Context, gameID
 	}
 	return map[string]any{"count": count}, nil
 }
+
+func (m *MockHistoryRepository) CountGameResults(ctx context.Context, gameID uuid.UUID) (total, playerWins int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, history := range m.histories {
+		if history.GameID == gameID {
+			total++
+			if history.Status == models.HistoryStatusPlayerWin {
+				playerWins++
+			}
+		}
+	}
+	return total, playerWins, nil
+}
+
+// SetGameRepository задаёт репозиторий игр для выборок по правилам игр
+func (m *MockHistoryRepository) SetGameRepository(games models.GameRepository) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.games = games
+}
+
+func (m *MockHistoryRepository) CountResultsByRules(ctx context.Context, length, maxTries int) (total, playerWins int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.games == nil {
+		return 0, 0, nil
+	}
+	for _, history := range m.histories {
+		game, err := m.games.GetByID(ctx, history.GameID)
+		if err != nil || game.Length != length || game.MaxTries != maxTries {
+			continue
+		}
+		total++
+		if history.Status == models.HistoryStatusPlayerWin {
+			playerWins++
+		}
+	}
+	return total, playerWins, nil
+}
+
+// MockSideBetRepository мок для SideBetRepository. Место ставки проверяется по лобби из MockLobbyRepository
+type MockSideBetRepository struct {
+	mu       sync.RWMutex
+	bets     map[uuid.UUID]*models.SideBet
+	accounts map[string]*models.SideBetAccount
+	lobbies  *MockLobbyRepository
+}
+
+func NewMockSideBetRepository(lobbies *MockLobbyRepository) *MockSideBetRepository {
+	return &MockSideBetRepository{
+		bets:     make(map[uuid.UUID]*models.SideBet),
+		accounts: make(map[string]*models.SideBetAccount),
+		lobbies:  lobbies,
+	}
+}
+
+func (m *MockSideBetRepository) Place(ctx context.Context, bet *models.SideBet) (bool, error) {
+	m.lobbies.mu.RLock()
+	defer m.lobbies.mu.RUnlock()
+	lobby, ok := m.lobbies.lobbies[bet.LobbyID]
+	if !ok || lobby.Status != models.LobbyStatusActive || lobby.TriesUsed != bet.PlacedAtTry {
+		return false, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if bet.ID == uuid.Nil {
+		bet.ID = uuid.New()
+	}
+	bet.CreatedAt = time.Now()
+	bet.UpdatedAt = bet.CreatedAt
+	m.bets[bet.ID] = bet
+	m.adjustAccount(bet.Currency, bet.Amount)
+	return true, nil
+}
+
+func (m *MockSideBetRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SideBet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if bet, ok := m.bets[id]; ok {
+		return bet, nil
+	}
+	return nil, models.ErrSideBetNotFound
+}
+
+func (m *MockSideBetRepository) GetByLobbyID(ctx context.Context, lobbyID uuid.UUID) ([]*models.SideBet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var bets []*models.SideBet
+	for _, bet := range m.bets {
+		if bet.LobbyID == lobbyID {
+			bets = append(bets, bet)
+		}
+	}
+	return bets, nil
+}
+
+func (m *MockSideBetRepository) GetOpenByLobbyID(ctx context.Context, lobbyID uuid.UUID) ([]*models.SideBet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var bets []*models.SideBet
+	for _, bet := range m.bets {
+		if bet.LobbyID == lobbyID && bet.Status == models.SideBetStatusOpen {
+			bets = append(bets, bet)
+		}
+	}
+	return bets, nil
+}
+
+func (m *MockSideBetRepository) GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*models.SideBet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var bets []*models.SideBet
+	for _, bet := range m.bets {
+		if bet.UserID == userID {
+			bets = append(bets, bet)
+		}
+	}
+	return bets, nil
+}
+
+func (m *MockSideBetRepository) Settle(ctx context.Context, id uuid.UUID, status string, payout float64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bet, ok := m.bets[id]
+	if !ok {
+		return false, models.ErrSideBetNotFound
+	}
+	if bet.Status != models.SideBetStatusOpen {
+		return false, nil
+	}
+	now := time.Now()
+	bet.Status = status
+	bet.Payout = payout
+	bet.SettledAt = &now
+	bet.UpdatedAt = now
+	m.adjustAccount(bet.Currency, -payout)
+	return true, nil
+}
+
+func (m *MockSideBetRepository) GetAccounts(ctx context.Context) ([]*models.SideBetAccount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	accounts := make([]*models.SideBetAccount, 0, len(m.accounts))
+	for _, account := range m.accounts {
+		copied := *account
+		accounts = append(accounts, &copied)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Currency < accounts[j].Currency })
+	return accounts, nil
+}
+
+// adjustAccount изменяет баланс счёта рынка (вызывается под m.mu)
+func (m *MockSideBetRepository) adjustAccount(currency string, delta float64) {
+	account, ok := m.accounts[currency]
+	if !ok {
+		account = &models.SideBetAccount{Currency: currency}
+		m.accounts[currency] = account
+	}
+	account.Balance += delta
+	account.UpdatedAt = time.Now()
+}
+
+// MockDuelRepository мок для DuelRepository
+type MockDuelRepository struct {
+	mu       sync.RWMutex
+	duels    map[uuid.UUID]*models.Duel
+	attempts map[uuid.UUID][]*models.DuelAttempt
+}
+
+func NewMockDuelRepository() *MockDuelRepository {
+	return &MockDuelRepository{
+		duels:    make(map[uuid.UUID]*models.Duel),
+		attempts: make(map[uuid.UUID][]*models.DuelAttempt),
+	}
+}
+
+func (m *MockDuelRepository) Create(ctx context.Context, duel *models.Duel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if duel.ID == uuid.Nil {
+		duel.ID = uuid.New()
+	}
+	duel.CreatedAt = time.Now()
+	duel.UpdatedAt = duel.CreatedAt
+	m.duels[duel.ID] = duel
+	return nil
+}
+
+func (m *MockDuelRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Duel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if duel, ok := m.duels[id]; ok {
+		return duel, nil
+	}
+	return nil, models.ErrDuelNotFound
+}
+
+func (m *MockDuelRepository) GetByShortID(ctx context.Context, shortID string) (*models.Duel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, duel := range m.duels {
+		if duel.ShortID == shortID {
+			return duel, nil
+		}
+	}
+	return nil, models.ErrDuelNotFound
+}
+
+func (m *MockDuelRepository) GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*models.Duel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var duels []*models.Duel
+	for _, duel := range m.duels {
+		if duel.ChallengerID == userID || duel.OpponentID == userID {
+			duels = append(duels, duel)
+		}
+	}
+	return duels, nil
+}
+
+func (m *MockDuelRepository) GetExpiredInvites(ctx context.Context) ([]*models.Duel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var duels []*models.Duel
+	for _, duel := range m.duels {
+		if duel.IsInviteExpired() {
+			duels = append(duels, duel)
+		}
+	}
+	return duels, nil
+}
+
+func (m *MockDuelRepository) GetExpiredActive(ctx context.Context) ([]*models.Duel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var duels []*models.Duel
+	for _, duel := range m.duels {
+		if duel.IsPlayExpired() {
+			duels = append(duels, duel)
+		}
+	}
+	return duels, nil
+}
+
+func (m *MockDuelRepository) Accept(ctx context.Context, duel *models.Duel) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.duels[duel.ID]
+	if !ok {
+		return false, models.ErrDuelNotFound
+	}
+	if stored.Status != models.DuelStatusPending {
+		return false, nil
+	}
+	if stored.OpponentID != 0 && stored.OpponentID != duel.OpponentID {
+		return false, nil
+	}
+	stored.OpponentID = duel.OpponentID
+	stored.OpponentWord = duel.OpponentWord
+	stored.StartedAt = duel.StartedAt
+	stored.EndsAt = duel.EndsAt
+	stored.Status = models.DuelStatusActive
+	stored.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *MockDuelRepository) TransitionStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	duel, ok := m.duels[id]
+	if !ok {
+		return false, models.ErrDuelNotFound
+	}
+	if duel.Status != fromStatus {
+		return false, nil
+	}
+	duel.Status = toStatus
+	duel.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *MockDuelRepository) RecordAttempt(ctx context.Context, duel *models.Duel, userID uint64, prevTries int, attempt *models.DuelAttempt) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.duels[duel.ID]
+	if !ok {
+		return false, models.ErrDuelNotFound
+	}
+	storedTries, _, storedFinishedAt := stored.PlayerState(userID)
+	if stored.Status != models.DuelStatusActive || storedTries != prevTries || storedFinishedAt != nil {
+		return false, nil
+	}
+	tries, solved, finishedAt := duel.PlayerState(userID)
+	stored.SetPlayerState(userID, tries, solved, finishedAt)
+	stored.UpdatedAt = time.Now()
+
+	if attempt.ID == uuid.Nil {
+		attempt.ID = uuid.New()
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = time.Now()
+	}
+	m.attempts[attempt.DuelID] = append(m.attempts[attempt.DuelID], attempt)
+	return true, nil
+}
+
+func (m *MockDuelRepository) Settle(ctx context.Context, duel *models.Duel) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.duels[duel.ID]
+	if !ok {
+		return false, models.ErrDuelNotFound
+	}
+	if stored.Status != models.DuelStatusActive {
+		return false, nil
+	}
+	now := time.Now()
+	stored.Status = duel.Status
+	stored.WinnerID = duel.WinnerID
+	stored.Payout = duel.Payout
+	stored.Commission = duel.Commission
+	stored.SettledAt = &now
+	stored.UpdatedAt = now
+	return true, nil
+}
+
+func (m *MockDuelRepository) GetAttempts(ctx context.Context, duelID uuid.UUID, userID uint64) ([]*models.DuelAttempt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var attempts []*models.DuelAttempt
+	for _, attempt := range m.attempts[duelID] {
+		if attempt.UserID == userID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+// MockJackpotRepository мок для JackpotRepository
+type MockJackpotRepository struct {
+	mu       sync.Mutex
+	jackpots map[string]*models.Jackpot
+	entries  []*models.JackpotEntry
+}
+
+func NewMockJackpotRepository() *MockJackpotRepository {
+	return &MockJackpotRepository{
+		jackpots: make(map[string]*models.Jackpot),
+	}
+}
+
+func (m *MockJackpotRepository) Get(ctx context.Context, currency string) (*models.Jackpot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if jackpot, ok := m.jackpots[currency]; ok {
+		copied := *jackpot
+		return &copied, nil
+	}
+	return &models.Jackpot{Currency: currency}, nil
+}
+
+func (m *MockJackpotRepository) GetAll(ctx context.Context) ([]*models.Jackpot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Jackpot
+	for _, jackpot := range m.jackpots {
+		copied := *jackpot
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Currency < result[j].Currency })
+	return result, nil
+}
+
+func (m *MockJackpotRepository) Contribute(ctx context.Context, entry *models.JackpotEntry) (bool, error) {
+	entry.Type = models.JackpotEntryContribution
+	return m.applyEntry(entry, func(current float64) (float64, bool) {
+		return current + entry.Amount, true
+	})
+}
+
+func (m *MockJackpotRepository) Payout(ctx context.Context, entry *models.JackpotEntry) (bool, error) {
+	entry.Type = models.JackpotEntryPayout
+	return m.applyEntry(entry, func(current float64) (float64, bool) {
+		entry.Amount = current
+		return 0, current > 0
+	})
+}
+
+func (m *MockJackpotRepository) applyEntry(entry *models.JackpotEntry, apply func(current float64) (float64, bool)) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.entries {
+		if existing.LobbyID == entry.LobbyID && existing.Type == entry.Type {
+			return false, nil
+		}
+	}
+
+	jackpot, ok := m.jackpots[entry.Currency]
+	if !ok {
+		jackpot = &models.Jackpot{Currency: entry.Currency}
+		m.jackpots[entry.Currency] = jackpot
+	}
+	balance, ok := apply(jackpot.Amount)
+	if !ok {
+		return false, nil
+	}
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	entry.BalanceAfter = balance
+	entry.CreatedAt = time.Now()
+	jackpot.Amount = balance
+	jackpot.UpdatedAt = entry.CreatedAt
+
+	copied := *entry
+	m.entries = append(m.entries, &copied)
+	return true, nil
+}
+
+func (m *MockJackpotRepository) GetEntries(ctx context.Context, currency string, limit, offset int) ([]*models.JackpotEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.JackpotEntry
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if currency == "" || m.entries[i].Currency == currency {
+			result = append(result, m.entries[i])
+		}
+	}
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// MockCommissionRepository мок для CommissionRepository
+type MockCommissionRepository struct {
+	mu       sync.Mutex
+	accounts map[string]*models.HouseAccount
+	entries  []*models.CommissionEntry
+}
+
+func NewMockCommissionRepository() *MockCommissionRepository {
+	return &MockCommissionRepository{
+		accounts: make(map[string]*models.HouseAccount),
+	}
+}
+
+func (m *MockCommissionRepository) Book(ctx context.Context, entry *models.CommissionEntry) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.entries {
+		if existing.ReferenceID == entry.ReferenceID && existing.Source == entry.Source {
+			return false, nil
+		}
+	}
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	copied := *entry
+	m.entries = append(m.entries, &copied)
+
+	account, ok := m.accounts[entry.Currency]
+	if !ok {
+		account = &models.HouseAccount{Currency: entry.Currency}
+		m.accounts[entry.Currency] = account
+	}
+	account.Balance += entry.Amount
+	account.UpdatedAt = entry.CreatedAt
+	return true, nil
+}
+
+func (m *MockCommissionRepository) GetHouseAccounts(ctx context.Context) ([]*models.HouseAccount, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.HouseAccount
+	for _, account := range m.accounts {
+		copied := *account
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Currency < result[j].Currency })
+	return result, nil
+}
+
+func (m *MockCommissionRepository) GetCreatorVolume(ctx context.Context, creatorID uint64, currency string, since time.Time) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var volume float64
+	for _, entry := range m.entries {
+		if entry.CreatorID == creatorID && entry.Currency == currency && !entry.CreatedAt.Before(since) &&
+			(entry.Source == models.CommissionSourceLobbyLoss || entry.Source == models.CommissionSourceLobbyWin) {
+			volume += entry.Volume
+		}
+	}
+	return volume, nil
+}
+
+func (m *MockCommissionRepository) GetReport(ctx context.Context, period string, from, to time.Time) ([]*models.CommissionReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rows := make(map[string]*models.CommissionReport)
+	var result []*models.CommissionReport
+	for _, entry := range m.entries {
+		if entry.CreatedAt.Before(from) || !entry.CreatedAt.Before(to) {
+			continue
+		}
+		start := models.CommissionPeriodStart(entry.CreatedAt, period)
+		key := start.String() + entry.Currency + entry.Source
+		row, ok := rows[key]
+		if !ok {
+			row = &models.CommissionReport{PeriodStart: start, Currency: entry.Currency, Source: entry.Source}
+			rows[key] = row
+			result = append(result, row)
+		}
+		row.Count++
+		row.Volume += entry.Volume
+		row.Amount += entry.Amount
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PeriodStart.Equal(result[j].PeriodStart) {
+			return result[i].PeriodStart.Before(result[j].PeriodStart)
+		}
+		if result[i].Currency != result[j].Currency {
+			return result[i].Currency < result[j].Currency
+		}
+		return result[i].Source < result[j].Source
+	})
+	return result, nil
+}
+
+// MockReferralRepository мок для ReferralRepository
+type MockReferralRepository struct {
+	mu        sync.Mutex
+	codes     map[uint64]string
+	referrals map[uint64]*models.Referral
+	earnings  []*models.ReferralEarning
+}
+
+func NewMockReferralRepository() *MockReferralRepository {
+	return &MockReferralRepository{
+		codes:     make(map[uint64]string),
+		referrals: make(map[uint64]*models.Referral),
+	}
+}
+
+func (m *MockReferralRepository) EnsureCode(ctx context.Context, userID uint64, code string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored, ok := m.codes[userID]; ok {
+		return stored, nil
+	}
+	for _, existing := range m.codes {
+		if existing == code {
+			return "", errors.New("referral code already exists")
+		}
+	}
+	m.codes[userID] = code
+	return code, nil
+}
+
+func (m *MockReferralRepository) GetUserByCode(ctx context.Context, code string) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for userID, existing := range m.codes {
+		if existing == code {
+			return userID, nil
+		}
+	}
+	return 0, models.ErrReferralNotFound
+}
+
+func (m *MockReferralRepository) Create(ctx context.Context, referral *models.Referral) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.referrals[referral.RefereeID]; ok {
+		return false, nil
+	}
+	copied := *referral
+	m.referrals[referral.RefereeID] = &copied
+	return true, nil
+}
+
+func (m *MockReferralRepository) GetByReferee(ctx context.Context, refereeID uint64) (*models.Referral, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	referral, ok := m.referrals[refereeID]
+	if !ok {
+		return nil, models.ErrReferralNotFound
+	}
+	copied := *referral
+	return &copied, nil
+}
+
+func (m *MockReferralRepository) GetByReferrer(ctx context.Context, referrerID uint64, limit, offset int) ([]*models.Referral, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Referral
+	for _, referral := range m.referrals {
+		if referral.ReferrerID == referrerID {
+			copied := *referral
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockReferralRepository) Block(ctx context.Context, refereeID uint64, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	referral, ok := m.referrals[refereeID]
+	if !ok {
+		return models.ErrReferralNotFound
+	}
+	referral.Status = models.ReferralStatusBlocked
+	referral.BlockReason = reason
+	return nil
+}
+
+func (m *MockReferralRepository) AddEarning(ctx context.Context, earning *models.ReferralEarning) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.earnings {
+		if existing.CommissionEntryID == earning.CommissionEntryID {
+			return false, nil
+		}
+	}
+	if earning.ID == uuid.Nil {
+		earning.ID = uuid.New()
+	}
+	if earning.Status == "" {
+		earning.Status = models.ReferralEarningPending
+	}
+	earning.CreatedAt = time.Now()
+	copied := *earning
+	m.earnings = append(m.earnings, &copied)
+	return true, nil
+}
+
+func (m *MockReferralRepository) GetPendingTotals(ctx context.Context, limit int) ([]*models.ReferralEarningTotal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.totals(func(earning *models.ReferralEarning) bool {
+		return earning.Status == models.ReferralEarningPending
+	}, limit), nil
+}
+
+func (m *MockReferralRepository) ClaimPending(ctx context.Context, refereeID uint64, currency string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var amount float64
+	for _, earning := range m.earnings {
+		if earning.RefereeID == refereeID && earning.Currency == currency && earning.Status == models.ReferralEarningPending {
+			earning.Status = models.ReferralEarningCredited
+			earning.CreditedAt = &now
+			amount += earning.Amount
+		}
+	}
+	return amount, nil
+}
+
+func (m *MockReferralRepository) CancelPending(ctx context.Context, refereeID uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, earning := range m.earnings {
+		if earning.RefereeID == refereeID && earning.Status == models.ReferralEarningPending {
+			earning.Status = models.ReferralEarningCanceled
+		}
+	}
+	return nil
+}
+
+func (m *MockReferralRepository) GetTotalsByReferrer(ctx context.Context, referrerID uint64) ([]*models.ReferralEarningTotal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.totals(func(earning *models.ReferralEarning) bool {
+		return earning.ReferrerID == referrerID
+	}, 0), nil
+}
+
+// totals группирует начисления по реферальной паре, валюте и статусу
+func (m *MockReferralRepository) totals(match func(*models.ReferralEarning) bool, limit int) []*models.ReferralEarningTotal {
+	grouped := make(map[string]*models.ReferralEarningTotal)
+	var result []*models.ReferralEarningTotal
+	for _, earning := range m.earnings {
+		if !match(earning) {
+			continue
+		}
+		key := fmt.Sprintf("%d:%d:%s:%s", earning.ReferrerID, earning.RefereeID, earning.Currency, earning.Status)
+		total, ok := grouped[key]
+		if !ok {
+			if limit > 0 && len(result) >= limit {
+				continue
+			}
+			total = &models.ReferralEarningTotal{
+				ReferrerID: earning.ReferrerID,
+				RefereeID:  earning.RefereeID,
+				Currency:   earning.Currency,
+				Status:     earning.Status,
+			}
+			grouped[key] = total
+			result = append(result, total)
+		}
+		total.Amount += earning.Amount
+	}
+	return result
+}
+
+// MockPromoRepository мок для PromoRepository
+type MockPromoRepository struct {
+	mu          sync.Mutex
+	promos      map[string]*models.PromoCode
+	redemptions []*models.PromoRedemption
+	stakes      map[uuid.UUID]*models.BonusStake
+}
+
+func NewMockPromoRepository() *MockPromoRepository {
+	return &MockPromoRepository{
+		promos: make(map[string]*models.PromoCode),
+		stakes: make(map[uuid.UUID]*models.BonusStake),
+	}
+}
+
+func (m *MockPromoRepository) Create(ctx context.Context, promo *models.PromoCode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.promos[promo.Code]; ok {
+		return models.ErrPromoCodeExists
+	}
+	if promo.ID == uuid.Nil {
+		promo.ID = uuid.New()
+	}
+	promo.CreatedAt = time.Now()
+	copied := *promo
+	m.promos[promo.Code] = &copied
+	return nil
+}
+
+func (m *MockPromoRepository) GetByCode(ctx context.Context, code string) (*models.PromoCode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	promo, ok := m.promos[code]
+	if !ok {
+		return nil, models.ErrPromoCodeNotFound
+	}
+	copied := *promo
+	return &copied, nil
+}
+
+func (m *MockPromoRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.PromoCode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.PromoCode
+	for _, promo := range m.promos {
+		copied := *promo
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockPromoRepository) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, promo := range m.promos {
+		if promo.ID == id {
+			promo.Active = active
+			return nil
+		}
+	}
+	return models.ErrPromoCodeNotFound
+}
+
+func (m *MockPromoRepository) Redeem(ctx context.Context, code string, redemption *models.PromoRedemption) (*models.PromoCode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	promo, ok := m.promos[code]
+	if !ok {
+		return nil, models.ErrPromoCodeNotFound
+	}
+	now := time.Now()
+	switch {
+	case !promo.Active:
+		return nil, models.ErrPromoCodeInactive
+	case promo.IsExpired(now):
+		return nil, models.ErrPromoCodeExpired
+	case promo.MaxRedemptions > 0 && promo.Redemptions >= promo.MaxRedemptions:
+		return nil, models.ErrPromoCodeExhausted
+	}
+	userRedemptions := 0
+	for _, existing := range m.redemptions {
+		if existing.PromoCodeID == promo.ID && existing.UserID == redemption.UserID {
+			userRedemptions++
+		}
+	}
+	if userRedemptions >= promo.PerUserLimit {
+		return nil, models.ErrPromoCodeRedeemed
+	}
+
+	if redemption.ID == uuid.Nil {
+		redemption.ID = uuid.New()
+	}
+	redemption.PromoCodeID = promo.ID
+	redemption.Code = promo.Code
+	redemption.Currency = promo.Currency
+	redemption.Amount = promo.Amount
+	redemption.Wager = promo.Wager()
+	redemption.CreatedAt = now
+	copied := *redemption
+	m.redemptions = append(m.redemptions, &copied)
+
+	promo.Redemptions++
+	result := *promo
+	return &result, nil
+}
+
+func (m *MockPromoRepository) GetRedemptionsByUser(ctx context.Context, userID uint64, limit, offset int) ([]*models.PromoRedemption, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.PromoRedemption
+	for i := len(m.redemptions) - 1; i >= 0; i-- {
+		if m.redemptions[i].UserID == userID {
+			copied := *m.redemptions[i]
+			result = append(result, &copied)
+		}
+	}
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockPromoRepository) CreateBonusStake(ctx context.Context, stake *models.BonusStake) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stake.CreatedAt = time.Now()
+	copied := *stake
+	m.stakes[stake.LobbyID] = &copied
+	return nil
+}
+
+func (m *MockPromoRepository) DeleteBonusStake(ctx context.Context, lobbyID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.stakes, lobbyID)
+	return nil
+}
+
+func (m *MockPromoRepository) SettleBonusStake(ctx context.Context, lobbyID uuid.UUID) (*models.BonusStake, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stake, ok := m.stakes[lobbyID]
+	if !ok || stake.Settled {
+		return nil, nil
+	}
+	stake.Settled = true
+	copied := *stake
+	return &copied, nil
+}
+
+// MockAchievementRepository мок для AchievementRepository
+type MockAchievementRepository struct {
+	mu           sync.Mutex
+	streaks      map[uint64]*models.UserStreak
+	achievements []*models.UserAchievement
+	players      map[uuid.UUID]map[uint64]bool
+}
+
+func NewMockAchievementRepository() *MockAchievementRepository {
+	return &MockAchievementRepository{
+		streaks: make(map[uint64]*models.UserStreak),
+		players: make(map[uuid.UUID]map[uint64]bool),
+	}
+}
+
+func (m *MockAchievementRepository) GetStreak(ctx context.Context, userID uint64) (*models.UserStreak, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if streak, ok := m.streaks[userID]; ok {
+		copied := *streak
+		return &copied, nil
+	}
+	return &models.UserStreak{UserID: userID}, nil
+}
+
+func (m *MockAchievementRepository) RecordPlay(ctx context.Context, userID uint64, status string, at time.Time) (*models.UserStreak, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	streak := m.streak(userID)
+	streak.RecordPlay(status, at)
+	copied := *streak
+	return &copied, nil
+}
+
+func (m *MockAchievementRepository) RecordLogin(ctx context.Context, userID uint64, at time.Time) (*models.UserStreak, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	streak := m.streak(userID)
+	recorded := streak.RecordLogin(at)
+	copied := *streak
+	return &copied, recorded, nil
+}
+
+// streak возвращает серии пользователя, создавая их при первом обращении
+func (m *MockAchievementRepository) streak(userID uint64) *models.UserStreak {
+	streak, ok := m.streaks[userID]
+	if !ok {
+		streak = &models.UserStreak{UserID: userID}
+		m.streaks[userID] = streak
+	}
+	return streak
+}
+
+func (m *MockAchievementRepository) Award(ctx context.Context, achievement *models.UserAchievement) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.achievements {
+		if existing.UserID == achievement.UserID && existing.Code == achievement.Code {
+			return false, nil
+		}
+	}
+	if achievement.AwardedAt.IsZero() {
+		achievement.AwardedAt = time.Now()
+	}
+	copied := *achievement
+	m.achievements = append(m.achievements, &copied)
+	return true, nil
+}
+
+func (m *MockAchievementRepository) GetByUser(ctx context.Context, userID uint64) ([]*models.UserAchievement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.UserAchievement
+	for _, achievement := range m.achievements {
+		if achievement.UserID == userID {
+			copied := *achievement
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAchievementRepository) AddGamePlayer(ctx context.Context, gameID uuid.UUID, userID uint64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.players[gameID] == nil {
+		m.players[gameID] = make(map[uint64]bool)
+	}
+	m.players[gameID][userID] = true
+	return len(m.players[gameID]), nil
+}
+
+// MockLeaderboardRepository мок для LeaderboardRepository.
+// Таблицы лидеров строятся по записям MockHistoryRepository
+type MockLeaderboardRepository struct {
+	mu      sync.Mutex
+	history *MockHistoryRepository
+	users   models.UserRepository // Для имён пользователей (может быть nil)
+	optOuts map[uint64]bool
+	payouts []*models.LeaderboardPayout
+}
+
+func NewMockLeaderboardRepository(history *MockHistoryRepository, users models.UserRepository) *MockLeaderboardRepository {
+	return &MockLeaderboardRepository{
+		history: history,
+		users:   users,
+		optOuts: make(map[uint64]bool),
+	}
+}
+
+func (m *MockLeaderboardRepository) GetEntries(ctx context.Context, query *models.LeaderboardQuery) ([]*models.LeaderboardEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	byUser := make(map[uint64]*models.LeaderboardEntry)
+	tries := make(map[uint64]int)
+	m.history.mu.RLock()
+	for _, history := range m.history.histories {
+		if history.CreatedAt.Before(query.From) || !history.CreatedAt.Before(query.To) || m.optOuts[history.UserID] {
+			continue
+		}
+		if query.Metric == models.LeaderboardMetricProfit && history.Currency != query.Currency {
+			continue
+		}
+		entry, ok := byUser[history.UserID]
+		if !ok {
+			entry = &models.LeaderboardEntry{UserID: history.UserID}
+			byUser[history.UserID] = entry
+		}
+		entry.Games++
+		if history.Status == models.HistoryStatusPlayerWin {
+			entry.Wins++
+			tries[history.UserID] += history.TriesUsed
+		}
+		if history.Currency == query.Currency {
+			entry.Profit += history.Reward - history.BetAmount
+		}
+	}
+	m.history.mu.RUnlock()
+
+	var entries []*models.LeaderboardEntry
+	for userID, entry := range byUser {
+		if entry.Games < query.MinGames {
+			continue
+		}
+		if query.Metric == models.LeaderboardMetricEfficiency && entry.Wins == 0 {
+			continue
+		}
+		if entry.Wins > 0 {
+			entry.AvgTries = float64(tries[userID]) / float64(entry.Wins)
+		}
+		if m.users != nil {
+			if user, err := m.users.GetByTelegramID(ctx, userID); err == nil {
+				entry.Username = user.Username
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch query.Metric {
+		case models.LeaderboardMetricProfit:
+			if a.Profit != b.Profit {
+				return a.Profit > b.Profit
+			}
+		case models.LeaderboardMetricWinRate:
+			rateA, rateB := float64(a.Wins)/float64(a.Games), float64(b.Wins)/float64(b.Games)
+			if rateA != rateB {
+				return rateA > rateB
+			}
+		case models.LeaderboardMetricEfficiency:
+			if a.AvgTries != b.AvgTries {
+				return a.AvgTries < b.AvgTries
+			}
+			if a.Wins != b.Wins {
+				return a.Wins > b.Wins
+			}
+		}
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.UserID < b.UserID
+	})
+
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return entries, nil
+}
+
+func (m *MockLeaderboardRepository) SetOptOut(ctx context.Context, userID uint64, optOut bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if optOut {
+		m.optOuts[userID] = true
+	} else {
+		delete(m.optOuts, userID)
+	}
+	return nil
+}
+
+func (m *MockLeaderboardRepository) IsOptedOut(ctx context.Context, userID uint64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.optOuts[userID], nil
+}
+
+func (m *MockLeaderboardRepository) CreatePayout(ctx context.Context, payout *models.LeaderboardPayout) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.payouts {
+		if existing.Period == payout.Period && existing.Metric == payout.Metric && existing.Currency == payout.Currency &&
+			existing.PeriodStart.Equal(payout.PeriodStart) && existing.Rank == payout.Rank {
+			return false, nil
+		}
+	}
+	if payout.ID == uuid.Nil {
+		payout.ID = uuid.New()
+	}
+	if payout.CreatedAt.IsZero() {
+		payout.CreatedAt = time.Now()
+	}
+	copied := *payout
+	m.payouts = append(m.payouts, &copied)
+	return true, nil
+}
+
+func (m *MockLeaderboardRepository) GetPayouts(ctx context.Context, limit, offset int) ([]*models.LeaderboardPayout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.LeaderboardPayout
+	for i := len(m.payouts) - 1 - offset; i >= 0 && len(result) < limit; i-- {
+		copied := *m.payouts[i]
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+// MockPlayerStatsRepository мок для PlayerStatsRepository
+type MockPlayerStatsRepository struct {
+	mu      sync.Mutex
+	daily   map[string]*models.PlayerDailyStats
+	guesses []models.PlayerResult // Результаты побед с числом попыток
+	words   []models.PlayerResult // Результаты со стартовым словом
+}
+
+func NewMockPlayerStatsRepository() *MockPlayerStatsRepository {
+	return &MockPlayerStatsRepository{
+		daily: make(map[string]*models.PlayerDailyStats),
+	}
+}
+
+func (m *MockPlayerStatsRepository) RecordResult(ctx context.Context, result *models.PlayerResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := fmt.Sprintf("%d:%s:%s", result.UserID, result.Day.Format("2006-01-02"), result.Currency)
+	day, ok := m.daily[key]
+	if !ok {
+		day = &models.PlayerDailyStats{UserID: result.UserID, Day: result.Day, Currency: result.Currency}
+		m.daily[key] = day
+	}
+	day.Games++
+	day.Wagered += result.BetAmount
+	day.PaidOut += result.Payout
+	if result.Won() {
+		day.Wins++
+		if result.SolveSeconds > 0 {
+			day.SolveSeconds += result.SolveSeconds
+			day.TimedSolves++
+		}
+		if result.TriesUsed > 0 {
+			m.guesses = append(m.guesses, *result)
+		}
+	}
+	if result.Status == models.LobbyStatusCashedOut {
+		day.CashOuts++
+	}
+	if result.StartWord != "" {
+		m.words = append(m.words, *result)
+	}
+	return nil
+}
+
+// inRange проверяет, что результат или агрегат относится к игроку и попадает в [from, to)
+func inRange(userID uint64, day time.Time, wantUserID uint64, from, to time.Time) bool {
+	return userID == wantUserID && !day.Before(from) && day.Before(to)
+}
+
+func (m *MockPlayerStatsRepository) GetDaily(ctx context.Context, userID uint64, from, to time.Time) ([]*models.PlayerDailyStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.PlayerDailyStats
+	for _, day := range m.daily {
+		if inRange(day.UserID, day.Day, userID, from, to) {
+			copied := *day
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Day.Equal(result[j].Day) {
+			return result[i].Day.Before(result[j].Day)
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result, nil
+}
+
+func (m *MockPlayerStatsRepository) GetGuessDistribution(ctx context.Context, userID uint64, from, to time.Time) (map[int]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	distribution := make(map[int]int)
+	for _, result := range m.guesses {
+		if inRange(result.UserID, result.Day, userID, from, to) {
+			distribution[result.TriesUsed]++
+		}
+	}
+	return distribution, nil
+}
+
+func (m *MockPlayerStatsRepository) GetStartWords(ctx context.Context, userID uint64, from, to time.Time, limit int) ([]models.WordCount, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make(map[string]int)
+	for _, result := range m.words {
+		if inRange(result.UserID, result.Day, userID, from, to) {
+			counts[result.StartWord]++
+		}
+	}
+	var words []models.WordCount
+	for word, count := range counts {
+		words = append(words, models.WordCount{Word: word, Count: count})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Count != words[j].Count {
+			return words[i].Count > words[j].Count
+		}
+		return words[i].Word < words[j].Word
+	})
+	if len(words) > limit {
+		words = words[:limit]
+	}
+	return words, nil
+}
+
+// MockAnalyticsRepository мок для AnalyticsRepository, строящий аналитику по данным других моков
+type MockAnalyticsRepository struct {
+	games        *MockGameRepository
+	history      *MockHistoryRepository
+	attempts     *MockAttemptRepository
+	transactions *MockTransactionRepository
+	commission   *MockCommissionRepository
+}
+
+func NewMockAnalyticsRepository(games *MockGameRepository, history *MockHistoryRepository, attempts *MockAttemptRepository,
+	transactions *MockTransactionRepository, commission *MockCommissionRepository) *MockAnalyticsRepository {
+	return &MockAnalyticsRepository{
+		games:        games,
+		history:      history,
+		attempts:     attempts,
+		transactions: transactions,
+		commission:   commission,
+	}
+}
+
+// matches проверяет, что игра принадлежит создателю из запроса, а время попадает в [From, To)
+func (m *MockAnalyticsRepository) matches(query *models.AnalyticsQuery, gameID *uuid.UUID, at time.Time) bool {
+	if gameID == nil || at.Before(query.From) || !at.Before(query.To) {
+		return false
+	}
+	if query.GameID != nil && *query.GameID != *gameID {
+		return false
+	}
+	m.games.mu.RLock()
+	defer m.games.mu.RUnlock()
+	game, ok := m.games.games[*gameID]
+	return ok && game.CreatorID == query.CreatorID
+}
+
+func (m *MockAnalyticsRepository) GetBuckets(ctx context.Context, query *models.AnalyticsQuery) ([]*models.AnalyticsBucket, error) {
+	buckets := make(map[string]*models.AnalyticsBucket)
+	var result []*models.AnalyticsBucket
+	bucket := func(at time.Time, currency string) *models.AnalyticsBucket {
+		start := models.CommissionPeriodStart(at, query.Period)
+		key := start.String() + currency
+		b, ok := buckets[key]
+		if !ok {
+			b = &models.AnalyticsBucket{PeriodStart: start, Currency: currency}
+			buckets[key] = b
+			result = append(result, b)
+		}
+		return b
+	}
+
+	m.history.mu.RLock()
+	for _, history := range m.history.histories {
+		if !m.matches(query, &history.GameID, history.CreatedAt) {
+			continue
+		}
+		b := bucket(history.CreatedAt, history.Currency)
+		b.Lobbies++
+		b.GrossBets += history.BetAmount
+		switch history.Status {
+		case models.HistoryStatusCreatorWin:
+			b.LostBets += history.BetAmount
+			b.NetPnL += history.BetAmount
+		default:
+			if history.Status == models.HistoryStatusPlayerWin {
+				b.Wins++
+			} else {
+				b.CashOuts++
+			}
+			b.Payouts += history.Reward
+			b.NetPnL -= history.Reward
+		}
+	}
+	m.history.mu.RUnlock()
+
+	m.commission.mu.Lock()
+	for _, entry := range m.commission.entries {
+		if !m.matches(query, entry.GameID, entry.CreatedAt) {
+			continue
+		}
+		b := bucket(entry.CreatedAt, entry.Currency)
+		b.Commission += entry.Amount
+		if entry.Source == models.CommissionSourceLobbyLoss {
+			b.NetPnL -= entry.Volume * entry.Rate
+		}
+	}
+	m.commission.mu.Unlock()
+
+	m.transactions.mu.RLock()
+	for _, tx := range m.transactions.transactions {
+		if tx.Status != models.TransactionStatusCompleted || !m.matches(query, tx.GameID, tx.CreatedAt) {
+			continue
+		}
+		switch tx.Type {
+		case models.TransactionTypeGameDeposit, models.TransactionTypePoolTopUp:
+			bucket(tx.CreatedAt, tx.Currency).PoolIn += tx.Amount
+		case models.TransactionTypePoolWithdraw, models.TransactionTypeGameRefund:
+			bucket(tx.CreatedAt, tx.Currency).PoolOut += tx.Amount
+		}
+	}
+	m.transactions.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PeriodStart.Equal(result[j].PeriodStart) {
+			return result[i].PeriodStart.Before(result[j].PeriodStart)
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result, nil
+}
+
+func (m *MockAnalyticsRepository) GetTriesHistogram(ctx context.Context, query *models.AnalyticsQuery) (map[int]int, error) {
+	m.history.mu.RLock()
+	defer m.history.mu.RUnlock()
+	histogram := make(map[int]int)
+	for _, history := range m.history.histories {
+		if history.Status == models.HistoryStatusPlayerWin && history.TriesUsed > 0 &&
+			m.matches(query, &history.GameID, history.CreatedAt) {
+			histogram[history.TriesUsed]++
+		}
+	}
+	return histogram, nil
+}
+
+func (m *MockAnalyticsRepository) GetGameSummaries(ctx context.Context, query *models.AnalyticsQuery) ([]*models.AnalyticsGameSummary, error) {
+	m.games.mu.RLock()
+	var gameIDs []uuid.UUID
+	for id, game := range m.games.games {
+		if game.CreatorID == query.CreatorID && (query.GameID == nil || *query.GameID == id) {
+			gameIDs = append(gameIDs, id)
+		}
+	}
+	m.games.mu.RUnlock()
+
+	var summaries []*models.AnalyticsGameSummary
+	for _, id := range gameIDs {
+		gameID := id
+		gameQuery := *query
+		gameQuery.GameID = &gameID
+		buckets, _ := m.GetBuckets(ctx, &gameQuery)
+
+		game, _ := m.games.GetByID(ctx, gameID)
+		summary := &models.AnalyticsGameSummary{GameID: gameID, ShortID: game.ShortID, Title: game.Title, Status: game.Status}
+		summary.Currency = game.Currency
+		for _, b := range buckets {
+			summary.Add(b)
+		}
+		if summary.Lobbies > 0 {
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Lobbies > summaries[j].Lobbies
+	})
+	return summaries, nil
+}
+
+func (m *MockAnalyticsRepository) GetTopGuesses(ctx context.Context, gameID uuid.UUID, from, to time.Time, exclude string, limit int) ([]models.WordCount, error) {
+	m.attempts.mu.RLock()
+	counts := make(map[string]int)
+	for _, attempt := range m.attempts.attempts {
+		word := strings.ToLower(attempt.Word)
+		if attempt.GameID != gameID || attempt.CreatedAt.Before(from) || !attempt.CreatedAt.Before(to) ||
+			word == strings.ToLower(exclude) {
+			continue
+		}
+		counts[word]++
+	}
+	m.attempts.mu.RUnlock()
+
+	var guesses []models.WordCount
+	for word, count := range counts {
+		guesses = append(guesses, models.WordCount{Word: word, Count: count})
+	}
+	sort.Slice(guesses, func(i, j int) bool {
+		if guesses[i].Count != guesses[j].Count {
+			return guesses[i].Count > guesses[j].Count
+		}
+		return guesses[i].Word < guesses[j].Word
+	})
+	if len(guesses) > limit {
+		guesses = guesses[:limit]
+	}
+	return guesses, nil
+}
+
+type MockReputationRepository struct {
+	mu           sync.RWMutex
+	reviews      map[uuid.UUID]*models.GameReview
+	disputes     []*models.CreatorDispute
+	reputations  map[uint64]*models.CreatorReputation
+	games        *MockGameRepository
+	lobbies      *MockLobbyRepository
+	history      *MockHistoryRepository
+	transactions *MockTransactionRepository
+}
+
+func NewMockReputationRepository(games *MockGameRepository, lobbies *MockLobbyRepository, history *MockHistoryRepository,
+	transactions *MockTransactionRepository) *MockReputationRepository {
+	return &MockReputationRepository{
+		reviews:      make(map[uuid.UUID]*models.GameReview),
+		reputations:  make(map[uint64]*models.CreatorReputation),
+		games:        games,
+		lobbies:      lobbies,
+		history:      history,
+		transactions: transactions,
+	}
+}
+
+func (m *MockReputationRepository) SaveReview(ctx context.Context, review *models.GameReview) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, existing := range m.reviews {
+		if existing.GameID == review.GameID && existing.UserID == review.UserID {
+			existing.Rating = review.Rating
+			existing.Review = review.Review
+			existing.UpdatedAt = now
+			*review = *existing
+			return nil
+		}
+	}
+	if review.ID == uuid.Nil {
+		review.ID = uuid.New()
+	}
+	review.Status = models.ReviewStatusVisible
+	review.CreatedAt, review.UpdatedAt = now, now
+	stored := *review
+	m.reviews[review.ID] = &stored
+	return nil
+}
+
+func (m *MockReputationRepository) GetReview(ctx context.Context, id uuid.UUID) (*models.GameReview, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	review, ok := m.reviews[id]
+	if !ok {
+		return nil, models.ErrReviewNotFound
+	}
+	result := *review
+	return &result, nil
+}
+
+// filterReviews возвращает копии отзывов, отобранных match, от новых к старым
+func (m *MockReputationRepository) filterReviews(match func(*models.GameReview) bool, limit, offset int) []*models.GameReview {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*models.GameReview
+	for _, review := range m.reviews {
+		if match(review) {
+			copied := *review
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UpdatedAt.After(result[j].UpdatedAt)
+	})
+	if offset >= len(result) {
+		return nil
+	}
+	result = result[offset:]
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func (m *MockReputationRepository) GetGameReviews(ctx context.Context, gameID uuid.UUID, limit, offset int) ([]*models.GameReview, error) {
+	return m.filterReviews(func(review *models.GameReview) bool {
+		return review.GameID == gameID && review.Status == models.ReviewStatusVisible
+	}, limit, offset), nil
+}
+
+func (m *MockReputationRepository) GetReviewsByStatus(ctx context.Context, status string, limit, offset int) ([]*models.GameReview, error) {
+	return m.filterReviews(func(review *models.GameReview) bool {
+		return status == "" || review.Status == status
+	}, limit, offset), nil
+}
+
+func (m *MockReputationRepository) ModerateReview(ctx context.Context, id uuid.UUID, status, note string, moderatorID uint64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	review, ok := m.reviews[id]
+	if !ok {
+		return models.ErrReviewNotFound
+	}
+	review.Status = status
+	review.ModerationNote = note
+	review.ModeratedBy = moderatorID
+	review.ModeratedAt = &at
+	return nil
+}
+
+func (m *MockReputationRepository) GetGameRating(ctx context.Context, gameID uuid.UUID) (*models.GameRating, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rating := &models.GameRating{GameID: gameID, Distribution: make(map[int]int)}
+	var sum int
+	for _, review := range m.reviews {
+		if review.GameID == gameID && review.Status == models.ReviewStatusVisible {
+			rating.Distribution[review.Rating]++
+			rating.Count++
+			sum += review.Rating
+		}
+	}
+	if rating.Count > 0 {
+		rating.Average = float64(sum) / float64(rating.Count)
+	}
+	return rating, nil
+}
+
+func (m *MockReputationRepository) HasFinishedLobby(ctx context.Context, gameID uuid.UUID, userID uint64) (bool, error) {
+	m.history.mu.RLock()
+	defer m.history.mu.RUnlock()
+	for _, history := range m.history.histories {
+		if history.GameID == gameID && history.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockReputationRepository) CreateDispute(ctx context.Context, dispute *models.CreatorDispute) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if dispute.ID == uuid.Nil {
+		dispute.ID = uuid.New()
+	}
+	dispute.CreatedAt = time.Now()
+	stored := *dispute
+	m.disputes = append(m.disputes, &stored)
+	return nil
+}
+
+// creatorOf возвращает создателя игры
+func (m *MockReputationRepository) creatorOf(gameID uuid.UUID) (uint64, bool) {
+	m.games.mu.RLock()
+	defer m.games.mu.RUnlock()
+	game, ok := m.games.games[gameID]
+	if !ok {
+		return 0, false
+	}
+	return game.CreatorID, true
+}
+
+func (m *MockReputationRepository) GetReputationInputs(ctx context.Context, creatorID uint64) (*models.ReputationInputs, error) {
+	var inputs models.ReputationInputs
+
+	m.mu.RLock()
+	for _, review := range m.reviews {
+		if review.CreatorID == creatorID && review.Status == models.ReviewStatusVisible {
+			inputs.RatingSum += float64(review.Rating)
+			inputs.RatingCount++
+		}
+	}
+	for _, dispute := range m.disputes {
+		if dispute.CreatorID == creatorID {
+			inputs.Disputes++
+			if dispute.Outcome == models.DisputeOutcomePlayer {
+				inputs.DisputesLost++
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	m.lobbies.mu.RLock()
+	var lobbies []*models.Lobby
+	for _, lobby := range m.lobbies.lobbies {
+		lobbies = append(lobbies, lobby)
+	}
+	m.lobbies.mu.RUnlock()
+	for _, lobby := range lobbies {
+		if creator, ok := m.creatorOf(lobby.GameID); !ok || creator != creatorID {
+			continue
+		}
+		switch lobby.Status {
+		case models.LobbyStatusSuccess, models.LobbyStatusFailedTries, models.LobbyStatusCashedOut:
+			inputs.Lobbies++
+			inputs.Completed++
+		case models.LobbyStatusFailedExpired, models.LobbyStatusCanceled:
+			inputs.Lobbies++
+		}
+	}
+
+	m.transactions.mu.RLock()
+	var refunds []*models.Transaction
+	for _, tx := range m.transactions.transactions {
+		if tx.Type == models.TransactionTypeRefund && tx.GameID != nil {
+			refunds = append(refunds, tx)
+		}
+	}
+	m.transactions.mu.RUnlock()
+	for _, tx := range refunds {
+		if creator, ok := m.creatorOf(*tx.GameID); ok && creator == creatorID {
+			inputs.Refunds++
+		}
+	}
+
+	return &inputs, nil
+}
+
+func (m *MockReputationRepository) SaveReputation(ctx context.Context, reputation *models.CreatorReputation) error {
+	m.mu.Lock()
+	stored := *reputation
+	m.reputations[reputation.CreatorID] = &stored
+	m.mu.Unlock()
+	m.games.SetCreatorReputation(reputation.CreatorID, reputation.Score)
+	return nil
+}
+
+func (m *MockReputationRepository) GetReputation(ctx context.Context, creatorID uint64) (*models.CreatorReputation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	reputation, ok := m.reputations[creatorID]
+	if !ok {
+		return nil, models.ErrReputationNotFound
+	}
+	result := *reputation
+	return &result, nil
+}
+
+func (m *MockReputationRepository) GetStaleCreators(ctx context.Context, limit int) ([]uint64, error) {
+	type event struct {
+		gameID uuid.UUID
+		at     time.Time
+	}
+	var events []event
+	m.history.mu.RLock()
+	for _, history := range m.history.histories {
+		events = append(events, event{history.GameID, history.CreatedAt})
+	}
+	m.history.mu.RUnlock()
+	m.transactions.mu.RLock()
+	for _, tx := range m.transactions.transactions {
+		if tx.Type == models.TransactionTypeRefund && tx.GameID != nil {
+			events = append(events, event{*tx.GameID, tx.CreatedAt})
+		}
+	}
+	m.transactions.mu.RUnlock()
+
+	seen := make(map[uint64]bool)
+	var creators []uint64
+	for _, e := range events {
+		creator, ok := m.creatorOf(e.gameID)
+		if !ok || seen[creator] || len(creators) >= limit {
+			continue
+		}
+		m.mu.RLock()
+		reputation, calculated := m.reputations[creator]
+		m.mu.RUnlock()
+		if !calculated || e.at.After(reputation.UpdatedAt) {
+			seen[creator] = true
+			creators = append(creators, creator)
+		}
+	}
+	return creators, nil
+}
+
+type MockFollowRepository struct {
+	mu          sync.RWMutex
+	follows     map[uint64]map[uint64]*models.CreatorFollow // follower_id -> creator_id
+	bookmarks   map[uint64]map[uuid.UUID]time.Time
+	activations map[uuid.UUID]time.Time
+	games       *MockGameRepository
+	history     *MockHistoryRepository
+}
+
+func NewMockFollowRepository(games *MockGameRepository, history *MockHistoryRepository) *MockFollowRepository {
+	return &MockFollowRepository{
+		follows:     make(map[uint64]map[uint64]*models.CreatorFollow),
+		bookmarks:   make(map[uint64]map[uuid.UUID]time.Time),
+		activations: make(map[uuid.UUID]time.Time),
+		games:       games,
+		history:     history,
+	}
+}
+
+func (m *MockFollowRepository) Follow(ctx context.Context, follow *models.CreatorFollow) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.follows[follow.FollowerID] == nil {
+		m.follows[follow.FollowerID] = make(map[uint64]*models.CreatorFollow)
+	}
+	if existing, ok := m.follows[follow.FollowerID][follow.CreatorID]; ok {
+		existing.Notify = follow.Notify
+		follow.CreatedAt = existing.CreatedAt
+		return nil
+	}
+	follow.CreatedAt = time.Now()
+	stored := *follow
+	m.follows[follow.FollowerID][follow.CreatorID] = &stored
+	return nil
+}
+
+func (m *MockFollowRepository) Unfollow(ctx context.Context, followerID, creatorID uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.follows[followerID][creatorID]; !ok {
+		return models.ErrFollowNotFound
+	}
+	delete(m.follows[followerID], creatorID)
+	return nil
+}
+
+func (m *MockFollowRepository) GetFollowing(ctx context.Context, followerID uint64, limit, offset int) ([]*models.CreatorFollow, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var follows []*models.CreatorFollow
+	for _, follow := range m.follows[followerID] {
+		copied := *follow
+		follows = append(follows, &copied)
+	}
+	sort.Slice(follows, func(i, j int) bool {
+		if !follows[i].CreatedAt.Equal(follows[j].CreatedAt) {
+			return follows[i].CreatedAt.After(follows[j].CreatedAt)
+		}
+		return follows[i].CreatorID < follows[j].CreatorID
+	})
+	if offset >= len(follows) {
+		return nil, nil
+	}
+	follows = follows[offset:]
+	if len(follows) > limit {
+		follows = follows[:limit]
+	}
+	return follows, nil
+}
+
+func (m *MockFollowRepository) GetNotifiedFollowers(ctx context.Context, creatorID uint64) ([]uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var followers []uint64
+	for followerID, follows := range m.follows {
+		if follow, ok := follows[creatorID]; ok && follow.Notify {
+			followers = append(followers, followerID)
+		}
+	}
+	sort.Slice(followers, func(i, j int) bool { return followers[i] < followers[j] })
+	return followers, nil
+}
+
+func (m *MockFollowRepository) AddBookmark(ctx context.Context, bookmark *models.GameBookmark) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.bookmarks[bookmark.UserID] == nil {
+		m.bookmarks[bookmark.UserID] = make(map[uuid.UUID]time.Time)
+	}
+	if _, ok := m.bookmarks[bookmark.UserID][bookmark.GameID]; !ok {
+		m.bookmarks[bookmark.UserID][bookmark.GameID] = time.Now()
+	}
+	return nil
+}
+
+func (m *MockFollowRepository) RemoveBookmark(ctx context.Context, userID uint64, gameID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.bookmarks[userID][gameID]; !ok {
+		return models.ErrBookmarkNotFound
+	}
+	delete(m.bookmarks[userID], gameID)
+	return nil
+}
+
+// sortedGames возвращает игры из at, упорядоченные по убыванию времени
+func (m *MockFollowRepository) sortedGames(at map[uuid.UUID]time.Time, match func(*models.Game) bool) []*models.Game {
+	m.games.mu.RLock()
+	defer m.games.mu.RUnlock()
+	var games []*models.Game
+	for id := range at {
+		if game, ok := m.games.games[id]; ok && match(game) {
+			games = append(games, game)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if !at[games[i].ID].Equal(at[games[j].ID]) {
+			return at[games[i].ID].After(at[games[j].ID])
+		}
+		return games[i].ID.String() < games[j].ID.String()
+	})
+	return games
+}
+
+func (m *MockFollowRepository) GetBookmarkedGames(ctx context.Context, userID uint64, limit, offset int) ([]*models.Game, error) {
+	m.mu.RLock()
+	bookmarks := make(map[uuid.UUID]time.Time)
+	for id, at := range m.bookmarks[userID] {
+		bookmarks[id] = at
+	}
+	m.mu.RUnlock()
+
+	games := m.sortedGames(bookmarks, func(*models.Game) bool { return true })
+	if offset >= len(games) {
+		return nil, nil
+	}
+	games = games[offset:]
+	if len(games) > limit {
+		games = games[:limit]
+	}
+	return games, nil
+}
+
+func (m *MockFollowRepository) RecordActivation(ctx context.Context, gameID uuid.UUID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.activations[gameID] = at
+	return nil
+}
+
+func (m *MockFollowRepository) GetFollowedGames(ctx context.Context, followerID uint64, since time.Time, limit int) ([]*models.Game, error) {
+	m.mu.RLock()
+	followed := make(map[uint64]bool)
+	for creatorID := range m.follows[followerID] {
+		followed[creatorID] = true
+	}
+	activations := make(map[uuid.UUID]time.Time)
+	for id, at := range m.activations {
+		if at.After(since) {
+			activations[id] = at
+		}
+	}
+	m.mu.RUnlock()
+
+	games := m.sortedGames(activations, func(game *models.Game) bool {
+		return followed[game.CreatorID] && game.Status == models.GameStatusActive && !game.IsPrivate() &&
+			!game.IsEnded(time.Now())
+	})
+	if len(games) > limit {
+		games = games[:limit]
+	}
+	return games, nil
+}
+
+func (m *MockFollowRepository) GetPlayProfile(ctx context.Context, userID uint64) (*models.PlayProfile, error) {
+	profile := &models.PlayProfile{
+		Lengths:      make(map[int]int),
+		Difficulties: make(map[string]int),
+	}
+
+	m.history.mu.RLock()
+	var histories []*models.History
+	for _, history := range m.history.histories {
+		if history.UserID == userID {
+			histories = append(histories, history)
+		}
+	}
+	m.history.mu.RUnlock()
+
+	for _, history := range histories {
+		if profile.Games == 0 || history.BetAmount < profile.MinBet {
+			profile.MinBet = history.BetAmount
+		}
+		if history.BetAmount > profile.MaxBet {
+			profile.MaxBet = history.BetAmount
+		}
+		profile.Games++
+		if game, err := m.games.GetByID(ctx, history.GameID); err == nil {
+			profile.Lengths[game.Length]++
+			profile.Difficulties[game.Difficulty]++
+		}
+	}
+	return profile, nil
+}
+
+type MockNotificationRepository struct {
+	mu            sync.RWMutex
+	notifications []*models.Notification
+	dedupKeys     map[string]bool
+	preferences   map[uint64]map[string]bool
+	lobbies       *MockLobbyRepository
+}
+
+func NewMockNotificationRepository(lobbies *MockLobbyRepository) *MockNotificationRepository {
+	return &MockNotificationRepository{
+		dedupKeys:   make(map[string]bool),
+		preferences: make(map[uint64]map[string]bool),
+		lobbies:     lobbies,
+	}
+}
+
+func (m *MockNotificationRepository) Enqueue(ctx context.Context, notification *models.Notification) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if notification.DedupKey != "" {
+		if m.dedupKeys[notification.DedupKey] {
+			return false, nil
+		}
+		m.dedupKeys[notification.DedupKey] = true
+	}
+	if notification.ID == uuid.Nil {
+		notification.ID = uuid.New()
+	}
+	if notification.Status == "" {
+		notification.Status = models.NotificationStatusPending
+	}
+	notification.CreatedAt = time.Now()
+	if notification.NextAttemptAt.IsZero() {
+		notification.NextAttemptAt = notification.CreatedAt
+	}
+	stored := *notification
+	m.notifications = append(m.notifications, &stored)
+	return true, nil
+}
+
+func (m *MockNotificationRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.Notification, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var due []*models.Notification
+	for _, notification := range m.notifications {
+		if notification.Status == models.NotificationStatusPending && !notification.NextAttemptAt.After(now) {
+			copied := *notification
+			due = append(due, &copied)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *MockNotificationRepository) UpdateDelivery(ctx context.Context, notification *models.Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.notifications {
+		if stored.ID == notification.ID {
+			stored.Status = notification.Status
+			stored.Attempts = notification.Attempts
+			stored.NextAttemptAt = notification.NextAttemptAt
+			stored.LastError = notification.LastError
+			stored.SentAt = notification.SentAt
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MockNotificationRepository) GetPreferences(ctx context.Context, userID uint64) (map[string]bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	preferences := make(map[string]bool)
+	for notificationType, enabled := range m.preferences[userID] {
+		preferences[notificationType] = enabled
+	}
+	return preferences, nil
+}
+
+func (m *MockNotificationRepository) SetPreference(ctx context.Context, userID uint64, notificationType string, enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.preferences[userID] == nil {
+		m.preferences[userID] = make(map[string]bool)
+	}
+	m.preferences[userID][notificationType] = enabled
+	return nil
+}
+
+func (m *MockNotificationRepository) GetExpiringLobbies(ctx context.Context, now, deadline time.Time, notice time.Duration, limit int) ([]*models.Lobby, error) {
+	m.mu.RLock()
+	notified := make(map[string]bool, len(m.dedupKeys))
+	for key := range m.dedupKeys {
+		notified[key] = true
+	}
+	m.mu.RUnlock()
+
+	m.lobbies.mu.RLock()
+	defer m.lobbies.mu.RUnlock()
+	var lobbies []*models.Lobby
+	for _, lobby := range m.lobbies.lobbies {
+		if lobby.Status != models.LobbyStatusActive || !lobby.ExpiresAt.After(now) || lobby.ExpiresAt.After(deadline) ||
+			lobby.ExpiresAt.Sub(lobby.CreatedAt) <= notice ||
+			notified[models.NotificationLobbyExpiring+":"+lobby.ID.String()] {
+			continue
+		}
+		lobbies = append(lobbies, lobby)
+	}
+	sort.Slice(lobbies, func(i, j int) bool {
+		return lobbies[i].ExpiresAt.Before(lobbies[j].ExpiresAt)
+	})
+	if len(lobbies) > limit {
+		lobbies = lobbies[:limit]
+	}
+	return lobbies, nil
+}
+
+// Notifications возвращает копии всех уведомлений в очереди
+func (m *MockNotificationRepository) Notifications() []*models.Notification {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]*models.Notification, 0, len(m.notifications))
+	for _, notification := range m.notifications {
+		copied := *notification
+		result = append(result, &copied)
+	}
+	return result
+}
+
+// MockTransactor выполняет fn без транзакции: моки не поддерживают откат изменений
+type MockTransactor struct{}
+
+func NewMockTransactor() *MockTransactor {
+	return &MockTransactor{}
+}
+
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockEventRepository struct {
+	mu     sync.RWMutex
+	events []*models.DomainEvent
+}
+
+func NewMockEventRepository() *MockEventRepository {
+	return &MockEventRepository{}
+}
+
+// Events возвращает копии всех записанных событий в порядке записи
+func (m *MockEventRepository) Events() []*models.DomainEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	events := make([]*models.DomainEvent, 0, len(m.events))
+	for _, event := range m.events {
+		events = append(events, copyDomainEvent(event))
+	}
+	return events
+}
+
+func (m *MockEventRepository) Append(ctx context.Context, event *models.DomainEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.Status == "" {
+		event.Status = models.EventStatusPending
+	}
+	event.CreatedAt = time.Now()
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = event.CreatedAt
+	}
+	m.events = append(m.events, copyDomainEvent(event))
+	return nil
+}
+
+func (m *MockEventRepository) ClaimDue(ctx context.Context, now, claimUntil time.Time, limit int) ([]*models.DomainEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []*models.DomainEvent
+	for _, event := range m.events {
+		if len(due) == limit {
+			break
+		}
+		if event.Status == models.EventStatusPending && !event.NextAttemptAt.After(now) {
+			event.NextAttemptAt = claimUntil
+			due = append(due, copyDomainEvent(event))
+		}
+	}
+	return due, nil
+}
+
+func (m *MockEventRepository) MarkDelivered(ctx context.Context, id uuid.UUID, subscriber string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, event := range m.events {
+		if event.ID == id && !event.IsDeliveredTo(subscriber) {
+			event.DeliveredTo = append(event.DeliveredTo, subscriber)
+		}
+	}
+	return nil
+}
+
+func (m *MockEventRepository) UpdateDelivery(ctx context.Context, event *models.DomainEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.events {
+		if stored.ID == event.ID {
+			stored.Status = event.Status
+			stored.Attempts = event.Attempts
+			stored.NextAttemptAt = event.NextAttemptAt
+			stored.LastError = event.LastError
+			stored.DeliveredAt = event.DeliveredAt
+		}
+	}
+	return nil
+}
+
+func (m *MockEventRepository) GetDeadLetters(ctx context.Context, limit, offset int) ([]*models.DomainEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var dead []*models.DomainEvent
+	for i := len(m.events) - 1; i >= 0; i-- {
+		if m.events[i].Status == models.EventStatusDead {
+			dead = append(dead, copyDomainEvent(m.events[i]))
+		}
+	}
+	if offset >= len(dead) {
+		return nil, nil
+	}
+	dead = dead[offset:]
+	if len(dead) > limit {
+		dead = dead[:limit]
+	}
+	return dead, nil
+}
+
+func (m *MockEventRepository) Requeue(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, event := range m.events {
+		if event.ID == id && event.Status == models.EventStatusDead {
+			event.Status = models.EventStatusPending
+			event.Attempts = 0
+			event.NextAttemptAt = time.Now()
+			return nil
+		}
+	}
+	return models.ErrEventNotFound
+}
+
+func copyDomainEvent(event *models.DomainEvent) *models.DomainEvent {
+	copied := *event
+	copied.DeliveredTo = append([]string(nil), event.DeliveredTo...)
+	return &copied
+}
+
+type MockWebhookRepository struct {
+	mu         sync.RWMutex
+	webhooks   map[uuid.UUID]*models.Webhook
+	deliveries []*models.WebhookDelivery
+}
+
+func NewMockWebhookRepository() *MockWebhookRepository {
+	return &MockWebhookRepository{
+		webhooks: make(map[uuid.UUID]*models.Webhook),
+	}
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+	m.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	webhook, ok := m.webhooks[id]
+	if !ok {
+		return nil, models.ErrWebhookNotFound
+	}
+	return copyWebhook(webhook), nil
+}
+
+func (m *MockWebhookRepository) GetByOwner(ctx context.Context, ownerID uint64) ([]*models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var webhooks []*models.Webhook
+	for _, webhook := range m.webhooks {
+		if webhook.OwnerID == ownerID {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	sortWebhooks(webhooks)
+	return webhooks, nil
+}
+
+func (m *MockWebhookRepository) GetSubscribed(ctx context.Context, ownerIDs []uint64, eventType string) ([]*models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var webhooks []*models.Webhook
+	for _, webhook := range m.webhooks {
+		if webhook.Active && slices.Contains(ownerIDs, webhook.OwnerID) && webhook.Subscribes(eventType) {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	sortWebhooks(webhooks)
+	return webhooks, nil
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.webhooks[webhook.ID]
+	if !ok {
+		return models.ErrWebhookNotFound
+	}
+	webhook.UpdatedAt = time.Now()
+	stored.URL = webhook.URL
+	stored.EventTypes = append([]string(nil), webhook.EventTypes...)
+	stored.Active = webhook.Active
+	stored.UpdatedAt = webhook.UpdatedAt
+	return nil
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.webhooks[id]; !ok {
+		return models.ErrWebhookNotFound
+	}
+	delete(m.webhooks, id)
+	deliveries := m.deliveries[:0]
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID != id {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	m.deliveries = deliveries
+	return nil
+}
+
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if delivery.DedupKey != "" {
+		for _, stored := range m.deliveries {
+			if stored.WebhookID == delivery.WebhookID && stored.DedupKey == delivery.DedupKey {
+				return false, nil
+			}
+		}
+	}
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.Status == "" {
+		delivery.Status = models.WebhookDeliveryPending
+	}
+	delivery.CreatedAt = time.Now()
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = delivery.CreatedAt
+	}
+	stored := *delivery
+	m.deliveries = append(m.deliveries, &stored)
+	return true, nil
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, delivery := range m.deliveries {
+		if delivery.ID == id {
+			copied := *delivery
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrWebhookDeliveryNotFound
+}
+
+func (m *MockWebhookRepository) GetDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]*models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var deliveries []*models.WebhookDelivery
+	for i := len(m.deliveries) - 1; i >= 0; i-- {
+		if m.deliveries[i].WebhookID == webhookID {
+			copied := *m.deliveries[i]
+			deliveries = append(deliveries, &copied)
+		}
+	}
+	if offset >= len(deliveries) {
+		return nil, nil
+	}
+	deliveries = deliveries[offset:]
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (m *MockWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var due []*models.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		webhook, ok := m.webhooks[delivery.WebhookID]
+		if ok && webhook.Active && delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			copied := *delivery
+			due = append(due, &copied)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.deliveries {
+		if stored.ID == delivery.ID {
+			stored.Status = delivery.Status
+			stored.Attempts = delivery.Attempts
+			stored.NextAttemptAt = delivery.NextAttemptAt
+			stored.ResponseCode = delivery.ResponseCode
+			stored.LastError = delivery.LastError
+			stored.DeliveredAt = delivery.DeliveredAt
+		}
+	}
+	return nil
+}
+
+func copyWebhook(webhook *models.Webhook) *models.Webhook {
+	copied := *webhook
+	copied.EventTypes = append([]string(nil), webhook.EventTypes...)
+	return &copied
+}
+
+func sortWebhooks(webhooks []*models.Webhook) {
+	sort.SliceStable(webhooks, func(i, j int) bool {
+		if !webhooks[i].CreatedAt.Equal(webhooks[j].CreatedAt) {
+			return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+		}
+		return webhooks[i].ID.String() < webhooks[j].ID.String()
+	})
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Коды достижений
+const (
+	AchievementFirstWin    = "first_win"    // Первая победа
+	AchievementWinStreak   = "win_streak"   // Серия побед подряд
+	AchievementQuickSolve  = "quick_solve"  // Слово угадано за несколько попыток
+	AchievementVeteran     = "veteran"      // Сыграно много игр
+	AchievementPopularGame = "popular_game" // В игре создателя сыграло много игроков
+)
+
+// Пороги достижений
+const (
+	AchievementWinStreakLength = 5   // Побед подряд для AchievementWinStreak
+	AchievementQuickSolveTries = 2   // Максимум попыток для AchievementQuickSolve
+	AchievementGamesPlayed     = 100 // Сыгранных игр для AchievementVeteran
+	AchievementGamePlayers     = 50  // Игроков в игре создателя для AchievementPopularGame
+)
+
+// Типы событий, по которым проверяются достижения
+const (
+	AchievementEventLobbyFinished = "lobby_finished" // Лобби рассчитано
+)
+
+// Achievement описывает достижение (бейдж)
+type Achievement struct {
+	Code        string  `json:"code"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Reward      float64 `json:"reward,omitempty"` // Награда на бонусный баланс (0 - только бейдж)
+	Currency    string  `json:"currency,omitempty"`
+}
+
+// AchievementCatalog список всех достижений
+var AchievementCatalog = []Achievement{
+	{Code: AchievementFirstWin, Title: "First win", Description: "Win your first game"},
+	{Code: AchievementWinStreak, Title: "On fire", Description: "Win 5 games in a row"},
+	{Code: AchievementQuickSolve, Title: "Sharp mind", Description: "Guess a word in 2 tries or fewer"},
+	{Code: AchievementVeteran, Title: "Veteran", Description: "Play 100 games"},
+	{Code: AchievementPopularGame, Title: "Crowd puller", Description: "Create a game played by 50 players"},
+}
+
+// FindAchievement возвращает описание достижения по коду
+func FindAchievement(code string) (Achievement, bool) {
+	for _, achievement := range AchievementCatalog {
+		if achievement.Code == code {
+			return achievement, true
+		}
+	}
+	return Achievement{}, false
+}
+
+// AchievementEvent представляет собой доменное событие, по которому проверяются правила достижений
+type AchievementEvent struct {
+	Type      string    `json:"type"`
+	UserID    uint64    `json:"user_id"`    // Игрок
+	CreatorID uint64    `json:"creator_id"` // Создатель игры
+	GameID    uuid.UUID `json:"game_id"`
+	LobbyID   uuid.UUID `json:"lobby_id"`
+	Status    string    `json:"status"` // Итоговый статус лобби
+	TriesUsed int       `json:"tries_used"`
+	At        time.Time `json:"at"`
+}
+
+// Won проверяет, выиграл ли игрок лобби
+func (e *AchievementEvent) Won() bool {
+	return e.Status == LobbyStatusSuccess
+}
+
+// UserAchievement представляет собой полученное пользователем достижение.
+// Каждое достижение выдаётся пользователю только один раз
+type UserAchievement struct {
+	UserID    uint64    `json:"user_id" db:"user_id"`
+	Code      string    `json:"code" db:"code"`
+	Reward    float64   `json:"reward" db:"reward"`
+	Currency  string    `json:"currency" db:"currency"`
+	AwardedAt time.Time `json:"awarded_at" db:"awarded_at"`
+}
+
+// UserStreak представляет собой серии пользователя: победы подряд, дни с игрой и дни со входом
+type UserStreak struct {
+	UserID          uint64     `json:"user_id" db:"user_id"`
+	GamesPlayed     int        `json:"games_played" db:"games_played"`
+	WinStreak       int        `json:"win_streak" db:"win_streak"`
+	BestWinStreak   int        `json:"best_win_streak" db:"best_win_streak"`
+	PlayStreak      int        `json:"play_streak" db:"play_streak"` // Дней подряд с хотя бы одной игрой
+	BestPlayStreak  int        `json:"best_play_streak" db:"best_play_streak"`
+	LastPlayedOn    *time.Time `json:"last_played_on,omitempty" db:"last_played_on"`
+	LoginStreak     int        `json:"login_streak" db:"login_streak"` // Дней подряд с полученной ежедневной наградой
+	BestLoginStreak int        `json:"best_login_streak" db:"best_login_streak"`
+	LastLoginOn     *time.Time `json:"last_login_on,omitempty" db:"last_login_on"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// StreakDay возвращает календарный день (UTC), по которому считаются дневные серии
+func StreakDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// nextDailyStreak возвращает длину дневной серии после активности в день day
+func nextDailyStreak(current int, last *time.Time, day time.Time) int {
+	if last == nil {
+		return 1
+	}
+	switch lastDay := StreakDay(*last); {
+	case lastDay.Equal(day):
+		return current
+	case lastDay.Equal(day.AddDate(0, 0, -1)):
+		return current + 1
+	default:
+		return 1
+	}
+}
+
+// RecordPlay учитывает рассчитанное лобби: победа продлевает серию побед, проигрыш её обрывает,
+// досрочная выплата серию не меняет. Дневная серия продлевается первой игрой дня
+func (s *UserStreak) RecordPlay(status string, at time.Time) {
+	day := StreakDay(at)
+
+	s.GamesPlayed++
+	switch status {
+	case LobbyStatusSuccess:
+		s.WinStreak++
+	case LobbyStatusCashedOut:
+	default:
+		s.WinStreak = 0
+	}
+	s.BestWinStreak = max(s.BestWinStreak, s.WinStreak)
+
+	s.PlayStreak = nextDailyStreak(s.PlayStreak, s.LastPlayedOn, day)
+	s.BestPlayStreak = max(s.BestPlayStreak, s.PlayStreak)
+	s.LastPlayedOn = &day
+	s.UpdatedAt = at
+}
+
+// RecordLogin учитывает ежедневный вход. Возвращает false, если вход в этот день уже учтён
+func (s *UserStreak) RecordLogin(at time.Time) bool {
+	day := StreakDay(at)
+	if s.LastLoginOn != nil && StreakDay(*s.LastLoginOn).Equal(day) {
+		return false
+	}
+
+	s.LoginStreak = nextDailyStreak(s.LoginStreak, s.LastLoginOn, day)
+	s.BestLoginStreak = max(s.BestLoginStreak, s.LoginStreak)
+	s.LastLoginOn = &day
+	s.UpdatedAt = at
+	return true
+}
+
+// AchievementRule условия наград за достижения и ежедневный вход.
+// Награды зачисляются на бонусный баланс с отыгрышем в размере награды
+type AchievementRule struct {
+	Currency           string             `json:"currency"`              // Валюта наград
+	Rewards            map[string]float64 `json:"rewards"`               // Награды по кодам достижений (нет - только бейдж)
+	DailyReward        float64            `json:"daily_reward"`          // Награда за первый день серии входов (0 - выключена)
+	DailyRewardStep    float64            `json:"daily_reward_step"`     // Прибавка за каждый следующий день серии
+	DailyRewardMaxDays int                `json:"daily_reward_max_days"` // День серии, после которого награда перестаёт расти
+}
+
+// Reward возвращает награду за достижение
+func (r AchievementRule) Reward(code string) float64 {
+	return max(r.Rewards[code], 0)
+}
+
+// DailyRewardFor возвращает награду за ежедневный вход при длине серии streak
+func (r AchievementRule) DailyRewardFor(streak int) float64 {
+	if r.DailyReward <= 0 || streak <= 0 {
+		return 0
+	}
+	days := streak
+	if r.DailyRewardMaxDays > 0 {
+		days = min(days, r.DailyRewardMaxDays)
+	}
+	return r.DailyReward + max(r.DailyRewardStep, 0)*float64(days-1)
+}
+
+// DailyReward представляет собой результат получения ежедневной награды
+type DailyReward struct {
+	Streak   int     `json:"streak"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// AchievementSummary представляет собой достижения и серии пользователя
+type AchievementSummary struct {
+	Streak         *UserStreak        `json:"streak"`
+	Earned         []*UserAchievement `json:"earned"`
+	Catalog        []Achievement      `json:"catalog"`                // Все достижения с наградами
+	DailyAvailable float64            `json:"daily_reward_available"` // Ежедневная награда, которую можно получить сейчас (0 - уже получена или выключена)
+}
@@ -0,0 +1,48 @@
+package models
+
+// BotUpdate входящее обновление Telegram Bot API (webhook). Разбираются только поля, которые использует бот
+type BotUpdate struct {
+	UpdateID    int64           `json:"update_id"`
+	Message     *BotMessage     `json:"message,omitempty"`
+	InlineQuery *BotInlineQuery `json:"inline_query,omitempty"`
+}
+
+// BotUser отправитель сообщения или inline-запроса
+type BotUser struct {
+	ID           uint64 `json:"id"`
+	IsBot        bool   `json:"is_bot"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+// BotChat чат, в который пришло сообщение
+type BotChat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"` // private, group, supergroup или channel
+}
+
+// BotMessage текстовое сообщение боту
+type BotMessage struct {
+	MessageID int64    `json:"message_id"`
+	From      *BotUser `json:"from,omitempty"`
+	Chat      BotChat  `json:"chat"`
+	Text      string   `json:"text,omitempty"`
+}
+
+// BotInlineQuery inline-запрос (@bot текст) из любого чата
+type BotInlineQuery struct {
+	ID    string  `json:"id"`
+	From  BotUser `json:"from"`
+	Query string  `json:"query"`
+}
+
+// BotInlineResult результат inline-запроса: карточка игры, которую пользователь отправляет в чат
+type BotInlineResult struct {
+	ID          string // Уникален в пределах ответа
+	Title       string
+	Description string
+	MessageText string // Текст сообщения, отправляемого в чат при выборе результата
+	URL         string // Ссылка на игру (пусто - без кнопки)
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Источники комиссии
+const (
+	CommissionSourceLobbyLoss = "lobby_loss" // Комиссия с проигранной ставки
+	CommissionSourceLobbyWin  = "lobby_win"  // Комиссия, удержанная из выигрыша
+	CommissionSourceDuel      = "duel"       // Комиссия с банка дуэли
+)
+
+// Периоды отчёта по комиссии
+const (
+	CommissionPeriodDay   = "day"
+	CommissionPeriodWeek  = "week"
+	CommissionPeriodMonth = "month"
+)
+
+// CommissionTier уровень комиссии по обороту создателя
+type CommissionTier struct {
+	MinVolume float64 `json:"min_volume"` // Оборот ставок в играх создателя, начиная с которого действует ставка
+	Rate      float64 `json:"rate"`
+}
+
+// CommissionPolicy правила расчёта ставки комиссии.
+// Приоритет: ставка игры, ставка создателя, уровень по обороту создателя, ставка по умолчанию
+type CommissionPolicy struct {
+	DefaultRate  float64               `json:"default_rate"`
+	Tiers        []CommissionTier      `json:"tiers"`
+	CreatorRates map[uint64]float64    `json:"creator_rates"`
+	GameRates    map[uuid.UUID]float64 `json:"game_rates"`
+}
+
+// CommissionEntry представляет собой запись о комиссии, зачисленной на счёт сервиса.
+// По каждому источнику (лобби или дуэли) возможна только одна запись каждого типа
+type CommissionEntry struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Source      string     `json:"source" db:"source"`
+	ReferenceID uuid.UUID  `json:"reference_id" db:"reference_id"` // ID лобби или дуэли
+	GameID      *uuid.UUID `json:"game_id,omitempty" db:"game_id"`
+	CreatorID   uint64     `json:"creator_id,omitempty" db:"creator_id"` // Создатель игры (0 для дуэлей)
+	UserID      uint64     `json:"user_id" db:"user_id"`                 // Игрок, с чьей ставки или выигрыша удержана комиссия
+	Currency    string     `json:"currency" db:"currency"`
+	Volume      float64    `json:"volume" db:"volume"` // Ставка, учитываемая в обороте
+	Rate        float64    `json:"rate" db:"rate"`
+	Amount      float64    `json:"amount" db:"amount"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// HouseAccount представляет собой счёт сервиса, на который зачисляется комиссия
+type HouseAccount struct {
+	Currency  string    `json:"currency" db:"currency"`
+	Balance   float64   `json:"balance" db:"balance"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CommissionReport итог комиссии за период по валюте и источнику
+type CommissionReport struct {
+	PeriodStart time.Time `json:"period_start"`
+	Currency    string    `json:"currency"`
+	Source      string    `json:"source"`
+	Count       int       `json:"count"`
+	Volume      float64   `json:"volume"`
+	Amount      float64   `json:"amount"`
+}
+
+// IsValidCommissionPeriod проверяет период отчёта
+func IsValidCommissionPeriod(period string) bool {
+	return period == CommissionPeriodDay || period == CommissionPeriodWeek || period == CommissionPeriodMonth
+}
+
+// CommissionPeriodStart возвращает начало периода (по UTC), в который попадает t.
+// Недели начинаются с понедельника, как date_trunc в PostgreSQL
+func CommissionPeriodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case CommissionPeriodWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case CommissionPeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
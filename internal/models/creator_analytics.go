@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalyticsTopGuesses сколько самых частых попыток возвращается в аналитике игры
+const AnalyticsTopGuesses = 10
+
+// AnalyticsQuery параметры выборки аналитики создателя.
+// Периоды группировки совпадают с периодами отчёта о комиссии: day, week, month
+type AnalyticsQuery struct {
+	CreatorID uint64
+	GameID    *uuid.UUID // nil - все игры создателя
+	Period    string
+	From      time.Time // Нулевое значение - с начала истории
+	To        time.Time
+}
+
+// AnalyticsBucket представляет собой показатели игр создателя за один период в одной валюте
+type AnalyticsBucket struct {
+	PeriodStart time.Time `json:"period_start"`
+	Currency    string    `json:"currency"`
+	Lobbies     int       `json:"lobbies"`
+	Wins        int       `json:"wins"`
+	CashOuts    int       `json:"cash_outs"`
+	GrossBets   float64   `json:"gross_bets"`
+	Payouts     float64   `json:"payouts"`      // Выплаты игрокам из пула (выигрыши и досрочные выплаты)
+	LostBets    float64   `json:"lost_bets"`    // Проигранные ставки до удержания комиссии
+	Commission  float64   `json:"commission"`   // Комиссия сервиса по лобби игр создателя
+	NetPnL      float64   `json:"net_pnl"`      // Результат создателя: проигранные ставки за вычетом комиссии минус выплаты
+	PoolIn      float64   `json:"pool_in"`      // Депозиты и пополнения пула
+	PoolOut     float64   `json:"pool_out"`     // Выводы и возвраты пула
+	PoolBalance float64   `json:"pool_balance"` // Расчётный пул на конец периода
+	Utilization float64   `json:"utilization"`  // Доля доступного в периоде пула, выплаченная игрокам
+}
+
+// AnalyticsTotals представляет собой итоги игр создателя в одной валюте
+type AnalyticsTotals struct {
+	Currency   string  `json:"currency"`
+	Lobbies    int     `json:"lobbies"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	CashOuts   int     `json:"cash_outs"`
+	WinRate    float64 `json:"win_rate"`
+	GrossBets  float64 `json:"gross_bets"`
+	Payouts    float64 `json:"payouts"`
+	Commission float64 `json:"commission"`
+	NetPnL     float64 `json:"net_pnl"`
+}
+
+// Add учитывает в итогах показатели периода
+func (t *AnalyticsTotals) Add(bucket *AnalyticsBucket) {
+	t.Lobbies += bucket.Lobbies
+	t.Wins += bucket.Wins
+	t.CashOuts += bucket.CashOuts
+	t.Losses = t.Lobbies - t.Wins - t.CashOuts
+	if t.Lobbies > 0 {
+		t.WinRate = float64(t.Wins) / float64(t.Lobbies)
+	}
+	t.GrossBets += bucket.GrossBets
+	t.Payouts += bucket.Payouts
+	t.Commission += bucket.Commission
+	t.NetPnL += bucket.NetPnL
+}
+
+// AnalyticsGameSummary представляет собой итоги одной игры в аналитике создателя
+type AnalyticsGameSummary struct {
+	GameID  uuid.UUID `json:"game_id"`
+	ShortID string    `json:"short_id"`
+	Title   string    `json:"title"`
+	Status  string    `json:"status"`
+	AnalyticsTotals
+}
+
+// GameAnalytics представляет собой аналитику игры для её создателя
+type GameAnalytics struct {
+	GameID         uuid.UUID          `json:"game_id"`
+	Title          string             `json:"title"`
+	Status         string             `json:"status"`
+	Period         string             `json:"period"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	Totals         AnalyticsTotals    `json:"totals"`
+	TriesHistogram map[int]int        `json:"tries_histogram"` // Число побед по количеству попыток
+	Series         []*AnalyticsBucket `json:"series"`
+	TopGuesses     []WordCount        `json:"top_guesses"` // Частые попытки игроков, кроме загаданного слова
+	CurrentPool    float64            `json:"current_pool"`
+	Reserved       float64            `json:"reserved"`
+}
+
+// CreatorAnalytics представляет собой аналитику всех игр создателя
+type CreatorAnalytics struct {
+	CreatorID      uint64                  `json:"creator_id"`
+	Period         string                  `json:"period"`
+	From           time.Time               `json:"from"`
+	To             time.Time               `json:"to"`
+	Totals         []AnalyticsTotals       `json:"totals"` // По валютам
+	TriesHistogram map[int]int             `json:"tries_histogram"`
+	Series         []*AnalyticsBucket      `json:"series"`
+	Games          []*AnalyticsGameSummary `json:"games"`
+}
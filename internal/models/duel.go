@@ -0,0 +1,130 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы дуэли
+const (
+	DuelStatusPending  = "pending"  // Вызов отправлен, ожидает принятия
+	DuelStatusActive   = "active"   // Оба игрока играют
+	DuelStatusFinished = "finished" // Определён победитель
+	DuelStatusDraw     = "draw"     // Ничья, ставки возвращены
+	DuelStatusExpired  = "expired"  // Вызов не принят вовремя
+	DuelStatusCanceled = "canceled" // Вызов отменён или отклонён
+)
+
+// DuelStartParamPrefix - префикс параметра startapp в ссылке-приглашении
+const DuelStartParamPrefix = "duel_"
+
+// Duel представляет собой дуэль двух игроков.
+// Каждый загадывает слово для соперника, оба вносят одинаковую ставку в эскроу.
+// Побеждает тот, кто угадает за меньшее число попыток, при равенстве - быстрее
+type Duel struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	ShortID      string    `json:"short_id" db:"short_id"`           // Код приглашения
+	ChallengerID uint64    `json:"challenger_id" db:"challenger_id"` // Telegram ID вызвавшего
+	OpponentID   uint64    `json:"opponent_id" db:"opponent_id"`     // Telegram ID соперника (0 - открытый вызов)
+	Stake        float64   `json:"stake" db:"stake"`                 // Ставка каждого игрока
+	Currency     string    `json:"currency" db:"currency"`           // Валюта
+	WordLength   int       `json:"word_length" db:"word_length"`     // Длина слов
+	MaxTries     int       `json:"max_tries" db:"max_tries"`         // Максимум попыток у каждого
+	TimeLimit    int       `json:"time_limit" db:"time_limit"`       // Время на игру в минутах
+
+	ChallengerWord string `json:"-" db:"challenger_word"` // Слово вызвавшего (отгадывает соперник)
+	OpponentWord   string `json:"-" db:"opponent_word"`   // Слово соперника (отгадывает вызвавший)
+
+	ChallengerTries      int        `json:"challenger_tries" db:"challenger_tries"`
+	ChallengerSolved     bool       `json:"challenger_solved" db:"challenger_solved"`
+	ChallengerFinishedAt *time.Time `json:"challenger_finished_at,omitempty" db:"challenger_finished_at"`
+	OpponentTries        int        `json:"opponent_tries" db:"opponent_tries"`
+	OpponentSolved       bool       `json:"opponent_solved" db:"opponent_solved"`
+	OpponentFinishedAt   *time.Time `json:"opponent_finished_at,omitempty" db:"opponent_finished_at"`
+
+	WinnerID   *uint64 `json:"winner_id,omitempty" db:"winner_id"` // Победитель (nil - не определён или ничья)
+	Payout     float64 `json:"payout" db:"payout"`                 // Выплата победителю
+	Commission float64 `json:"commission" db:"commission"`         // Комиссия сервиса
+
+	Status          string     `json:"status" db:"status"`
+	InviteLink      string     `json:"invite_link,omitempty" db:"-"`             // Ссылка-приглашение (Telegram startapp)
+	InviteExpiresAt time.Time  `json:"invite_expires_at" db:"invite_expires_at"` // Срок принятия вызова
+	StartedAt       *time.Time `json:"started_at,omitempty" db:"started_at"`
+	EndsAt          *time.Time `json:"ends_at,omitempty" db:"ends_at"` // Крайний срок игры
+	SettledAt       *time.Time `json:"settled_at,omitempty" db:"settled_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// DuelAttempt представляет собой попытку игрока в дуэли
+type DuelAttempt struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	DuelID    uuid.UUID `json:"duel_id" db:"duel_id"`
+	UserID    uint64    `json:"user_id" db:"user_id"`
+	Word      string    `json:"word" db:"word"`
+	Result    []int     `json:"result" db:"result"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// StartParam возвращает параметр startapp для ссылки-приглашения
+func (d *Duel) StartParam() string {
+	return DuelStartParamPrefix + d.ShortID
+}
+
+// ParseDuelStartParam извлекает код приглашения из параметра startapp.
+// Возвращает сам параметр, если префикса нет
+func ParseDuelStartParam(param string) string {
+	return strings.TrimPrefix(strings.TrimSpace(param), DuelStartParamPrefix)
+}
+
+// IsParticipant проверяет, участвует ли пользователь в дуэли
+func (d *Duel) IsParticipant(userID uint64) bool {
+	return userID != 0 && (d.ChallengerID == userID || d.OpponentID == userID)
+}
+
+// IsInviteExpired проверяет, истёк ли срок принятия вызова
+func (d *Duel) IsInviteExpired() bool {
+	return d.Status == DuelStatusPending && time.Now().After(d.InviteExpiresAt)
+}
+
+// IsPlayExpired проверяет, истекло ли время игры
+func (d *Duel) IsPlayExpired() bool {
+	return d.Status == DuelStatusActive && d.EndsAt != nil && time.Now().After(*d.EndsAt)
+}
+
+// TargetWord возвращает слово, которое должен отгадать пользователь
+func (d *Duel) TargetWord(userID uint64) string {
+	if userID == d.ChallengerID {
+		return d.OpponentWord
+	}
+	return d.ChallengerWord
+}
+
+// PlayerState возвращает прогресс пользователя: попытки, угадал ли, время окончания
+func (d *Duel) PlayerState(userID uint64) (tries int, solved bool, finishedAt *time.Time) {
+	if userID == d.ChallengerID {
+		return d.ChallengerTries, d.ChallengerSolved, d.ChallengerFinishedAt
+	}
+	return d.OpponentTries, d.OpponentSolved, d.OpponentFinishedAt
+}
+
+// SetPlayerState обновляет прогресс пользователя
+func (d *Duel) SetPlayerState(userID uint64, tries int, solved bool, finishedAt *time.Time) {
+	if userID == d.ChallengerID {
+		d.ChallengerTries, d.ChallengerSolved, d.ChallengerFinishedAt = tries, solved, finishedAt
+		return
+	}
+	d.OpponentTries, d.OpponentSolved, d.OpponentFinishedAt = tries, solved, finishedAt
+}
+
+// BothFinished проверяет, закончили ли игру оба игрока
+func (d *Duel) BothFinished() bool {
+	return d.ChallengerFinishedAt != nil && d.OpponentFinishedAt != nil
+}
+
+// Pot возвращает общую сумму в эскроу
+func (d *Duel) Pot() float64 {
+	return d.Stake * 2
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий
+const (
+	EventLobbyFinished       = "lobby_finished"       // Лобби завершено: выигрыш, проигрыш или досрочная выплата
+	EventDepositCredited     = "deposit_credited"     // Депозит зачислен на баланс
+	EventGameActivated       = "game_activated"       // Игра перешла в статус active
+	EventWithdrawalRequested = "withdrawal_requested" // Создана заявка на вывод
+	EventWithdrawalSent      = "withdrawal_sent"      // Вывод отправлен в блокчейн
+)
+
+// Статусы доставки доменных событий
+const (
+	EventStatusPending   = "pending"   // Ожидает доставки или повторной попытки
+	EventStatusDelivered = "delivered" // Доставлено всем подписчикам
+	EventStatusDead      = "dead"      // Попытки исчерпаны, событие ждёт разбора администратором
+)
+
+// ErrEventNotFound возвращается, если событие не найдено среди недоставленных
+var ErrEventNotFound = errors.New("event not found")
+
+// DomainEvent представляет собой доменное событие в outbox. Событие записывается в той же транзакции БД,
+// что и изменение состояния, и доставляется подписчикам фоновой задачей не менее одного раза
+type DomainEvent struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	Type          string          `json:"type" db:"type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id" db:"aggregate_id"` // Лобби, транзакция или игра, к которой относится событие
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	Status        string          `json:"status" db:"status"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty" db:"last_error"`
+	DeliveredTo   []string        `json:"delivered_to" db:"delivered_to"` // Подписчики, уже обработавшие событие
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// NewDomainEvent создает событие с сериализованными данными
+func NewDomainEvent(eventType string, aggregateID uuid.UUID, payload any) (*DomainEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	return &DomainEvent{
+		ID:          uuid.New(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+		Status:      EventStatusPending,
+	}, nil
+}
+
+// Decode разбирает данные события в структуру, соответствующую его типу
+func (e *DomainEvent) Decode(payload any) error {
+	if err := json.Unmarshal(e.Payload, payload); err != nil {
+		return fmt.Errorf("failed to decode %s event %s: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+// IsDeliveredTo проверяет, обработал ли подписчик событие
+func (e *DomainEvent) IsDeliveredTo(subscriber string) bool {
+	for _, name := range e.DeliveredTo {
+		if name == subscriber {
+			return true
+		}
+	}
+	return false
+}
+
+// LobbyFinishedEvent данные события EventLobbyFinished
+type LobbyFinishedEvent struct {
+	LobbyID    uuid.UUID `json:"lobby_id"`
+	GameID     uuid.UUID `json:"game_id"`
+	UserID     uint64    `json:"user_id"`
+	CreatorID  uint64    `json:"creator_id"`
+	Status     string    `json:"status"` // Финальный статус лобби
+	TriesUsed  int       `json:"tries_used"`
+	BetAmount  float64   `json:"bet_amount"`
+	Reward     float64   `json:"reward"` // Выплата игроку за вычетом комиссии (0 при проигрыше)
+	Currency   string    `json:"currency"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// TransactionEvent данные событий по транзакциям: EventDepositCredited, EventWithdrawalRequested, EventWithdrawalSent
+type TransactionEvent struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	UserID        uint64    `json:"user_id"`
+	Amount        float64   `json:"amount"`
+	Fee           float64   `json:"fee,omitempty"`
+	Currency      string    `json:"currency"`
+	ToAddress     string    `json:"to_address,omitempty"`
+}
+
+// NewTransactionEvent формирует данные события по транзакции
+func NewTransactionEvent(tx *Transaction) TransactionEvent {
+	return TransactionEvent{
+		TransactionID: tx.ID,
+		UserID:        tx.UserID,
+		Amount:        tx.Amount,
+		Fee:           tx.Fee,
+		Currency:      tx.Currency,
+		ToAddress:     tx.ToAddress,
+	}
+}
+
+// Transaction восстанавливает транзакцию из данных события (только поля, попавшие в событие)
+func (e TransactionEvent) Transaction() *Transaction {
+	return &Transaction{
+		ID:        e.TransactionID,
+		UserID:    e.UserID,
+		Amount:    e.Amount,
+		Fee:       e.Fee,
+		Currency:  e.Currency,
+		ToAddress: e.ToAddress,
+	}
+}
+
+// GameActivatedEvent данные события EventGameActivated
+type GameActivatedEvent struct {
+	GameID    uuid.UUID `json:"game_id"`
+	CreatorID uint64    `json:"creator_id"`
+}
+
+// EventRule параметры доставки доменных событий
+type EventRule struct {
+	MaxAttempts int           // Попыток доставки до статуса dead
+	RetryDelay  time.Duration // Задержка перед первой повторной попыткой, далее удваивается
+	BatchSize   int           // Событий за один запуск фоновой задачи
+}
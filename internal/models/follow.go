@@ -0,0 +1,44 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedFollowingWindow период, за который в ленте показываются активированные игры отслеживаемых создателей
+const FeedFollowingWindow = 14 * 24 * time.Hour
+
+// ErrCannotFollowSelf возвращается при попытке подписаться на самого себя
+var ErrCannotFollowSelf = errors.New("you cannot follow yourself")
+
+// CreatorFollow представляет собой подписку пользователя на создателя игр
+type CreatorFollow struct {
+	FollowerID uint64    `json:"follower_id" db:"follower_id"`
+	CreatorID  uint64    `json:"creator_id" db:"creator_id"`
+	Notify     bool      `json:"notify" db:"notify"` // Уведомлять о новых активных играх создателя
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// GameBookmark представляет собой игру, сохранённую пользователем в закладки
+type GameBookmark struct {
+	UserID    uint64    `json:"user_id" db:"user_id"`
+	GameID    uuid.UUID `json:"game_id" db:"game_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// PlayProfile предпочтения игрока по истории сыгранных лобби
+type PlayProfile struct {
+	Games        int            // Рассчитанных лобби игрока
+	Lengths      map[int]int    // Число лобби по длине слова
+	Difficulties map[string]int // Число лобби по сложности игры
+	MinBet       float64
+	MaxBet       float64
+}
+
+// Feed персональная лента игр пользователя
+type Feed struct {
+	Following   []*Game // Недавно активированные игры отслеживаемых создателей, сначала новые
+	Recommended []*Game // Игры, похожие на сыгранные пользователем
+}
@@ -1,9 +1,14 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,12 +16,29 @@ import (
 
 // Статусы игры
 const (
-	GameStatusPending  = "pending"  // Ожидает оплаты депозита
-	GameStatusActive   = "active"   // Активна и доступна для игры
-	GameStatusInactive = "inactive" // Неактивна (временно выключена создателем)
-	GameStatusClosed   = "closed"   // Закрыта (завершена)
+	GameStatusPending   = "pending"   // Ожидает оплаты депозита
+	GameStatusScheduled = "scheduled" // Оплачена, ожидает времени начала
+	GameStatusActive    = "active"    // Активна и доступна для игры
+	GameStatusInactive  = "inactive"  // Неактивна (временно выключена создателем)
+	GameStatusClosing   = "closing"   // Закрывается: ставки не принимаются, ожидает завершения активных лобби
+	GameStatusClosed    = "closed"    // Закрыта (завершена), остаток пула возвращён создателю
 )
 
+// Видимость игры
+const (
+	GameVisibilityPublic  = "public"  // Отображается в общих списках
+	GameVisibilityPrivate = "private" // Доступна только по приглашению
+)
+
+// GameStartParamPrefix - префикс параметра startapp в ссылке-приглашении в приватную игру
+const GameStartParamPrefix = "game_"
+
+// ErrGameAccessDenied возвращается, если у пользователя нет доступа к приватной игре
+var ErrGameAccessDenied = errors.New("access to private game denied")
+
+// ErrRiskLimitReached возвращается, если ставка не укладывается в лимиты риска создателя
+var ErrRiskLimitReached = errors.New("game risk limit reached")
+
 // Game представляет собой модель игры
 type Game struct {
 	ID               uuid.UUID `json:"id" db:"id"`
@@ -39,10 +61,140 @@ type Game struct {
 	ReservedAmount   float64   `json:"reserved_amount" db:"reserved_amount"`         // Зарезервированная сумма для активных игр
 	DepositTxHash    string    `json:"deposit_tx_hash,omitempty" db:"deposit_tx_hash"` // Хеш транзакции депозита
 	Status           string    `json:"status" db:"status"`                           // Статус игры
+	Visibility       string    `json:"visibility" db:"visibility"`                   // Видимость (public или private)
+	InviteToken      string    `json:"-" db:"invite_token"`                          // Токен приглашения (только для приватных игр)
+	AllowedUserIDs   []uint64  `json:"allowed_user_ids,omitempty" db:"allowed_user_ids"` // Белый список Telegram ID
+	AllowedChatID    int64     `json:"allowed_chat_id,omitempty" db:"allowed_chat_id"` // Доступ только участникам Telegram-группы
+	InviteLink       string    `json:"invite_link,omitempty" db:"-"`                 // Ссылка-приглашение (Telegram startapp)
+	StartsAt         *time.Time `json:"starts_at,omitempty" db:"starts_at"`          // Запланированное время активации
+	EndsAt           *time.Time `json:"ends_at,omitempty" db:"ends_at"`              // Запланированное время закрытия
+	AutoTopUpThreshold float64  `json:"auto_top_up_threshold" db:"auto_top_up_threshold"` // Порог доступного пула для автопополнения (0 - выключено)
+	AutoTopUpAmount  float64    `json:"auto_top_up_amount" db:"auto_top_up_amount"`  // Сумма автопополнения с баланса создателя
+	MaxDailyLoss     float64    `json:"max_daily_loss" db:"max_daily_loss"`          // Лимит убытка создателя за день (0 - без лимита)
+	MaxActiveLobbies int        `json:"max_active_lobbies" db:"max_active_lobbies"`  // Лимит одновременных лобби (0 - без лимита)
+	PoolFloor        float64    `json:"pool_floor" db:"pool_floor"`                  // Неснижаемый остаток пула
+	DailyLoss        float64    `json:"daily_loss" db:"daily_loss"`                  // Убыток создателя за день DailyLossDay (выплаты минус проигранные ставки)
+	DailyLossDay     *time.Time `json:"daily_loss_day,omitempty" db:"daily_loss_day"` // День (UTC), за который посчитан DailyLoss
+	OddsMinMultiplier float64   `json:"odds_min_multiplier" db:"odds_min_multiplier"` // Нижняя граница автоподстройки мультипликатора
+	OddsMaxMultiplier float64   `json:"odds_max_multiplier" db:"odds_max_multiplier"` // Верхняя граница автоподстройки мультипликатора (0 - выключено)
+	DifficultyScore  float64    `json:"difficulty_score" db:"difficulty_score"`     // Оценка сложности слова по словарю (0-100, 0 - не оценивалась)
+	Plays            int        `json:"plays" db:"plays_count"`                     // Рассчитанных лобби
+	Wins             int        `json:"wins" db:"wins_count"`                       // Лобби, выигранных игроками
+	CreatorReputation float64   `json:"creator_reputation" db:"-"`                  // Репутация создателя (0-100)
+	Odds             *GameOdds  `json:"odds,omitempty" db:"-"`                       // Оценка коэффициента (заполняется при создании игры)
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// GameAccess описывает настройки доступа к игре, задаваемые создателем
+type GameAccess struct {
+	Visibility     string   `json:"visibility"`
+	AllowedUserIDs []uint64 `json:"allowed_user_ids"`
+	AllowedChatID  int64    `json:"allowed_chat_id"`
+}
+
+// GameRiskLimits описывает лимиты риска, задаваемые создателем. Нулевое значение - без лимита
+type GameRiskLimits struct {
+	MaxDailyLoss     float64 `json:"max_daily_loss"`
+	MaxActiveLobbies int     `json:"max_active_lobbies"`
+	PoolFloor        float64 `json:"pool_floor"`
+}
+
+// AutoTopUpRule описывает правило автопополнения пула, задаваемое создателем
+type AutoTopUpRule struct {
+	Threshold float64 `json:"threshold"`
+	Amount    float64 `json:"amount"`
+}
+
+// Сортировки списка игр
+const (
+	GameSortNewest     = "newest"     // Сначала новые
+	GameSortPool       = "pool"       // По доступному пулу наград
+	GameSortPopular    = "popular"    // По числу сыгранных лобби
+	GameSortWinRate    = "win_rate"   // По доле побед игроков
+	GameSortReputation = "reputation" // По репутации создателя
+)
+
+// IsValidGameSort проверяет, поддерживается ли сортировка списка игр
+func IsValidGameSort(sort string) bool {
+	switch sort {
+	case GameSortNewest, GameSortPool, GameSortPopular, GameSortWinRate, GameSortReputation:
+		return true
+	}
+	return false
+}
+
+// GameSearchFilter параметры поиска публичных игр. Нулевое значение - без ограничения
+type GameSearchFilter struct {
+	MinBet             float64
+	MaxBet             float64
+	Difficulty         string
+	MinDifficultyScore float64
+	MaxDifficultyScore float64
+	Currency           string
+	WordLength         int
+	MinMultiplier      float64
+	MaxMultiplier      float64
+	MinTimeLimit       int     // В минутах
+	MaxTimeLimit       int     // В минутах
+	MinPoolBets        float64 // Доступный пул не меньше MinPoolBets максимальных ставок
+	CreatorID          uint64
+	Query              string // Полнотекстовый поиск по названию и описанию
+	Sort               string // Пусто - GameSortNewest
+}
+
+// WordDifficulty оценка сложности загаданного слова по словарю
+type WordDifficulty struct {
+	Word            string  `json:"word"`
+	Length          int     `json:"length"`
+	Score           float64 `json:"score"`            // От 0 (лёгкое) до 100 (сложное)
+	Level           string  `json:"level"`            // Рекомендуемая сложность игры: easy, medium или hard
+	InDictionary    bool    `json:"in_dictionary"`    // Слово есть в словаре
+	LetterRarity    float64 `json:"letter_rarity"`    // Редкость букв (0 - частые, 1 - редкие)
+	RepeatedLetters int     `json:"repeated_letters"` // Число повторов букв
+	Neighbors       int     `json:"neighbors"`        // Слов, отличающихся одной буквой
+	SolverTries     float64 `json:"solver_tries"`     // Среднее число попыток решателя, максимизирующего энтропию подсказок
+}
+
+// OddsAdjustRule описывает границы автоподстройки мультипликатора, задаваемые создателем.
+// Нулевая верхняя граница выключает автоподстройку
+type OddsAdjustRule struct {
+	MinMultiplier float64 `json:"min_multiplier"`
+	MaxMultiplier float64 `json:"max_multiplier"`
+}
+
+// GameOdds оценка справедливого мультипликатора и ожидаемого дохода сторон на единицу ставки
+type GameOdds struct {
+	WinProbability      float64 `json:"win_probability"`      // Оценка вероятности победы игрока
+	WordDifficulty      float64 `json:"word_difficulty"`      // Сложность слова по словарю (0 - лёгкое, 1 - сложное)
+	PlatformResults     int     `json:"platform_results"`     // Результатов игр с такими же правилами в истории
+	GameResults         int     `json:"game_results"`         // Результатов самой игры в истории
+	Multiplier          float64 `json:"multiplier"`           // Текущий мультипликатор
+	FairMultiplier      float64 `json:"fair_multiplier"`      // Мультипликатор с нулевым ожидаемым доходом создателя
+	SuggestedMultiplier float64 `json:"suggested_multiplier"` // Рекомендуемый мультипликатор
+	ExpectedPayout      float64 `json:"expected_payout"`      // Ожидаемая выплата игроку
+	CreatorEdge         float64 `json:"creator_edge"`         // Ожидаемый доход создателя (отрицательный - убыток)
+	HouseEdge           float64 `json:"house_edge"`           // Ожидаемый доход сервиса (комиссия и ставка победителя)
+	Warning             string  `json:"warning,omitempty"`
+}
+
+// GamePoolPnL сводка доходов и расходов создателя по пулу игры
+type GamePoolPnL struct {
+	GameID       uuid.UUID     `json:"game_id"`
+	Currency     string        `json:"currency"`
+	Deposited    float64       `json:"deposited"`     // Внесено депозитом при создании
+	ToppedUp     float64       `json:"topped_up"`     // Внесено автопополнением
+	Withdrawn    float64       `json:"withdrawn"`     // Выведено из активной игры
+	Refunded     float64       `json:"refunded"`      // Возвращено при закрытии
+	BetsReceived float64       `json:"bets_received"` // Ставки игроков
+	RewardsPaid  float64       `json:"rewards_paid"`  // Выплаты игрокам
+	CurrentPool  float64       `json:"current_pool"`  // Текущий пул
+	Reserved     float64       `json:"reserved"`      // Зарезервировано под активные лобби
+	Withdrawable float64       `json:"withdrawable"`  // Доступно к выводу
+	NetResult    float64       `json:"net_result"`    // Итог: выведено + возвращено + пул - внесено
+	AutoTopUp    AutoTopUpRule `json:"auto_top_up"`
+}
+
 // GetRequiredDeposit возвращает минимально необходимый депозит для игры
 func (g *Game) GetRequiredDeposit() float64 {
 	return g.MaxBet * g.RewardMultiplier
@@ -56,12 +208,184 @@ func (g *Game) GetAvailableRewardPool() float64 {
 	return g.RewardPoolUsdt - g.ReservedAmount
 }
 
+// WinRate возвращает долю лобби, выигранных игроками (0, если лобби не было)
+func (g *Game) WinRate() float64 {
+	if g.Plays == 0 {
+		return 0
+	}
+	return float64(g.Wins) / float64(g.Plays)
+}
+
+// Cursor возвращает курсор, указывающий на игру при сортировке sort
+func (g *Game) Cursor(sort string) *PageCursor {
+	cursor := &PageCursor{Sort: sort, ID: g.ID}
+	switch sort {
+	case GameSortPool:
+		cursor.Value = g.GetAvailableRewardPool()
+	case GameSortPopular:
+		cursor.Value = float64(g.Plays)
+	case GameSortWinRate:
+		cursor.Value = g.WinRate()
+	case GameSortReputation:
+		cursor.Value = g.CreatorReputation
+	default:
+		cursor.Time = g.CreatedAt
+	}
+	return cursor
+}
+
+// ActivationStatus возвращает статус оплаченной игры: scheduled, если время начала ещё не наступило
+func (g *Game) ActivationStatus(now time.Time) string {
+	if g.StartsAt != nil && now.Before(*g.StartsAt) {
+		return GameStatusScheduled
+	}
+	return GameStatusActive
+}
+
+// IsEnded проверяет, наступило ли запланированное время закрытия
+func (g *Game) IsEnded(now time.Time) bool {
+	return g.EndsAt != nil && !now.Before(*g.EndsAt)
+}
+
+// AcceptsBets проверяет, принимает ли игра новые ставки
+func (g *Game) AcceptsBets(now time.Time) bool {
+	return g.Status == GameStatusActive && !g.IsEnded(now)
+}
+
+// GetRewardPool возвращает пул наград в валюте игры
+func (g *Game) GetRewardPool() float64 {
+	if g.Currency == CurrencyTON {
+		return g.RewardPoolTon
+	}
+	return g.RewardPoolUsdt
+}
+
+// RiskDay возвращает день (UTC), к которому относится дневной убыток
+func RiskDay(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// GetDailyLoss возвращает убыток создателя за текущий день
+func (g *Game) GetDailyLoss(now time.Time) float64 {
+	if g.DailyLossDay == nil || !RiskDay(*g.DailyLossDay).Equal(RiskDay(now)) {
+		return 0
+	}
+	return g.DailyLoss
+}
+
+// CheckExposure проверяет, что худший исход (выигрыш во всех активных лобби и в новом)
+// укладывается в лимиты риска игры
+func (g *Game) CheckExposure(potentialReward float64, activeLobbies int, now time.Time) error {
+	if g.MaxActiveLobbies > 0 && activeLobbies >= g.MaxActiveLobbies {
+		return fmt.Errorf("%w: too many active lobbies", ErrRiskLimitReached)
+	}
+
+	exposure := g.ReservedAmount + potentialReward
+	if g.MaxDailyLoss > 0 && g.GetDailyLoss(now)+exposure > g.MaxDailyLoss {
+		return fmt.Errorf("%w: daily loss limit", ErrRiskLimitReached)
+	}
+	if g.PoolFloor > 0 && g.GetRewardPool()-exposure < g.PoolFloor {
+		return fmt.Errorf("%w: pool floor", ErrRiskLimitReached)
+	}
+	return nil
+}
+
+// MaxPotentialReward возвращает наибольшую выплату по ставке: с бонусом за угадывание с первой попытки
+func (g *Game) MaxPotentialReward(betAmount float64) float64 {
+	if g.MaxTries <= 0 {
+		return betAmount * g.RewardMultiplier
+	}
+	return betAmount * g.RewardMultiplier * RewardTriesBonus(1, g.MaxTries)
+}
+
+// RewardTriesBonus возвращает бонус за быстрое угадывание: до +50% при угадывании с первой попытки
+func RewardTriesBonus(triesUsed, maxTries int) float64 {
+	return 1.0 + (float64(maxTries-triesUsed)/float64(maxTries))*0.5
+}
+
+// RiskLimitReached возвращает сработавший лимит, если по уже состоявшимся результатам
+// игра не может принять даже минимальную ставку. Пустая строка - лимиты не исчерпаны
+func (g *Game) RiskLimitReached(now time.Time) string {
+	minExposure := g.MaxPotentialReward(g.MinBet)
+	if g.MaxDailyLoss > 0 && g.GetDailyLoss(now)+minExposure > g.MaxDailyLoss {
+		return "daily loss limit"
+	}
+	if g.PoolFloor > 0 && g.GetRewardPool()-minExposure < g.PoolFloor {
+		return "pool floor"
+	}
+	return ""
+}
+
+// HasAutoOdds проверяет, включена ли автоподстройка мультипликатора
+func (g *Game) HasAutoOdds() bool {
+	return g.OddsMaxMultiplier > 0
+}
+
+// GetPoolSafetyMargin возвращает неснижаемый остаток доступного пула:
+// игра должна принять ставку на максимум и не уйти ниже порога автопополнения и нижней границы пула
+func (g *Game) GetPoolSafetyMargin() float64 {
+	return max(g.GetRequiredDeposit(), g.AutoTopUpThreshold, g.PoolFloor)
+}
+
+// GetWithdrawablePool возвращает сумму, которую создатель может вывести из пула активной игры
+func (g *Game) GetWithdrawablePool() float64 {
+	return max(g.GetAvailableRewardPool()-g.GetPoolSafetyMargin(), 0)
+}
+
+// NeedsTopUp проверяет, опустился ли доступный пул ниже порога автопополнения
+func (g *Game) NeedsTopUp() bool {
+	return g.AutoTopUpAmount > 0 && g.GetAvailableRewardPool() < g.AutoTopUpThreshold
+}
+
+// GetRefundablePool возвращает остаток пула, который можно вернуть создателю при закрытии
+func (g *Game) GetRefundablePool() float64 {
+	return max(g.GetAvailableRewardPool(), 0)
+}
+
 // CanAcceptBet проверяет, может ли игра принять ставку указанного размера
 func (g *Game) CanAcceptBet(betAmount float64) bool {
 	potentialReward := betAmount * g.RewardMultiplier
 	return g.GetAvailableRewardPool() >= potentialReward
 }
 
+// IsPrivate проверяет, является ли игра приватной
+func (g *Game) IsPrivate() bool {
+	return g.Visibility == GameVisibilityPrivate
+}
+
+// HasMemberRestriction проверяет, ограничен ли доступ белым списком или группой
+func (g *Game) HasMemberRestriction() bool {
+	return len(g.AllowedUserIDs) > 0 || g.AllowedChatID != 0
+}
+
+// IsWhitelisted проверяет, есть ли пользователь в белом списке игры
+func (g *Game) IsWhitelisted(userID uint64) bool {
+	return slices.Contains(g.AllowedUserIDs, userID)
+}
+
+// InviteTicket возвращает билет приглашения для пользователя.
+// Билет передаётся в комментарии on-chain ставки вместо самого токена: комментарии публичны,
+// а билет действителен только для кошелька, привязанного к этому пользователю
+func (g *Game) InviteTicket(userID uint64) string {
+	if g.InviteToken == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(g.InviteToken + ":" + strconv.FormatUint(userID, 10)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// StartParam возвращает параметр startapp для ссылки-приглашения
+func (g *Game) StartParam() string {
+	return GameStartParamPrefix + g.InviteToken
+}
+
+// ParseGameStartParam извлекает токен приглашения из параметра startapp.
+// Возвращает сам параметр, если префикса нет
+func ParseGameStartParam(param string) string {
+	return strings.TrimPrefix(strings.TrimSpace(param), GameStartParamPrefix)
+}
+
 // UnmarshalJSON реализует интерфейс json.Unmarshaler для корректной обработки поля max_tries
 // которое может быть как числом, так и строкой
 func (g *Game) UnmarshalJSON(data []byte) error {
@@ -87,6 +411,19 @@ func (g *Game) UnmarshalJSON(data []byte) error {
 		ReservedAmount   float64         `json:"reserved_amount"`
 		DepositTxHash    string          `json:"deposit_tx_hash"`
 		Status           string          `json:"status"`
+		Visibility       string          `json:"visibility"`
+		AllowedUserIDs   []uint64        `json:"allowed_user_ids"`
+		AllowedChatID    int64           `json:"allowed_chat_id"`
+		StartsAt         *time.Time      `json:"starts_at"`
+		EndsAt           *time.Time      `json:"ends_at"`
+		AutoTopUpThreshold float64       `json:"auto_top_up_threshold"`
+		AutoTopUpAmount  float64         `json:"auto_top_up_amount"`
+		MaxDailyLoss     float64         `json:"max_daily_loss"`
+		MaxActiveLobbies int             `json:"max_active_lobbies"`
+		PoolFloor        float64         `json:"pool_floor"`
+		OddsMinMultiplier float64        `json:"odds_min_multiplier"`
+		OddsMaxMultiplier float64        `json:"odds_max_multiplier"`
+		DifficultyScore  float64         `json:"difficulty_score"`
 		CreatedAt        time.Time       `json:"created_at"`
 		UpdatedAt        time.Time       `json:"updated_at"`
 	}
@@ -117,6 +454,19 @@ func (g *Game) UnmarshalJSON(data []byte) error {
 	g.ReservedAmount = aux.ReservedAmount
 	g.DepositTxHash = aux.DepositTxHash
 	g.Status = aux.Status
+	g.Visibility = aux.Visibility
+	g.AllowedUserIDs = aux.AllowedUserIDs
+	g.AllowedChatID = aux.AllowedChatID
+	g.StartsAt = aux.StartsAt
+	g.EndsAt = aux.EndsAt
+	g.AutoTopUpThreshold = aux.AutoTopUpThreshold
+	g.AutoTopUpAmount = aux.AutoTopUpAmount
+	g.MaxDailyLoss = aux.MaxDailyLoss
+	g.MaxActiveLobbies = aux.MaxActiveLobbies
+	g.PoolFloor = aux.PoolFloor
+	g.OddsMinMultiplier = aux.OddsMinMultiplier
+	g.OddsMaxMultiplier = aux.OddsMaxMultiplier
+	g.DifficultyScore = aux.DifficultyScore
 	g.CreatedAt = aux.CreatedAt
 	g.UpdatedAt = aux.UpdatedAt
 
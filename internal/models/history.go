@@ -10,6 +10,7 @@ import (
 const (
 	HistoryStatusPlayerWin  = "player_win"  // Игрок выиграл
 	HistoryStatusCreatorWin = "creator_win" // Создатель выиграл (игрок проиграл)
+	HistoryStatusCashOut    = "cash_out"    // Игрок забрал досрочную выплату
 )
 
 // History представляет собой модель истории игр
@@ -18,7 +19,7 @@ type History struct {
 	UserID    uint64    `json:"user_id" db:"user_id"` // Telegram ID игрока
 	GameID    uuid.UUID `json:"game_id" db:"game_id"`
 	LobbyID   uuid.UUID `json:"lobby_id" db:"lobby_id"`     // Связь с конкретным лобби
-	Status    string    `json:"status" db:"status"`         // Статус завершения (player_win, creator_win)
+	Status    string    `json:"status" db:"status"`         // Статус завершения (player_win, creator_win, cash_out)
 	BetAmount float64   `json:"bet_amount" db:"bet_amount"` // Сумма ставки
 	Reward    float64   `json:"reward" db:"reward"`         // Сумма выигрыша (0 при проигрыше)
 	Currency  string    `json:"currency" db:"currency"`     // Валюта ставки/выигрыша
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы записей журнала джекпота
+const (
+	JackpotEntryContribution = "contribution" // Отчисление с комиссии проигранной ставки
+	JackpotEntryPayout       = "payout"       // Выплата джекпота победителю
+)
+
+// Jackpot представляет собой прогрессивный джекпот платформы в одной валюте
+type Jackpot struct {
+	Currency  string    `json:"currency" db:"currency"`
+	Amount    float64   `json:"amount" db:"amount"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// JackpotEntry представляет собой запись журнала джекпота.
+// По каждому лобби возможна только одна запись каждого типа
+type JackpotEntry struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	Currency     string    `json:"currency" db:"currency"`
+	Type         string    `json:"type" db:"type"`
+	Amount       float64   `json:"amount" db:"amount"`
+	BalanceAfter float64   `json:"balance_after" db:"balance_after"` // Размер джекпота после записи
+	LobbyID      uuid.UUID `json:"lobby_id" db:"lobby_id"`
+	GameID       uuid.UUID `json:"game_id" db:"game_id"`
+	UserID       uint64    `json:"user_id" db:"user_id"` // Игрок лобби
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// JackpotRule условия пополнения и розыгрыша джекпота
+type JackpotRule struct {
+	CommissionShare float64 `json:"commission_share"` // Доля комиссии с проигранной ставки, поступающая в джекпот (0 - джекпот выключен)
+	MaxTries        int     `json:"max_tries"`        // Джекпот выигрывает игрок, угадавший слово не более чем за столько попыток
+	MinBet          float64 `json:"min_bet"`          // Минимальная ставка лобби для розыгрыша джекпота
+}
+
+// Enabled проверяет, включён ли джекпот
+func (r JackpotRule) Enabled() bool {
+	return r.CommissionShare > 0
+}
+
+// Wins проверяет, выигрывает ли завершённое лобби джекпот
+func (r JackpotRule) Wins(lobby *Lobby, finalStatus string) bool {
+	return r.Enabled() &&
+		finalStatus == LobbyStatusSuccess &&
+		lobby.BetAmount > 0 && lobby.BetAmount >= r.MinBet &&
+		lobby.TriesUsed > 0 && lobby.TriesUsed <= r.MaxTries
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Периоды таблиц лидеров
+const (
+	LeaderboardPeriodDaily   = "daily"
+	LeaderboardPeriodWeekly  = "weekly"
+	LeaderboardPeriodMonthly = "monthly"
+	LeaderboardPeriodAllTime = "all_time"
+)
+
+// Метрики таблиц лидеров
+const (
+	LeaderboardMetricProfit     = "profit"     // Чистая прибыль игрока (выплаты минус ставки) в валюте таблицы
+	LeaderboardMetricWinRate    = "win_rate"   // Доля побед среди сыгранных лобби
+	LeaderboardMetricEfficiency = "efficiency" // Среднее число попыток в выигранных лобби (меньше - лучше)
+)
+
+// LeaderboardDefaultMinGames минимум сыгранных лобби для рейтингов по доле побед и эффективности
+const LeaderboardDefaultMinGames = 10
+
+// IsValidLeaderboardPeriod проверяет период таблицы лидеров
+func IsValidLeaderboardPeriod(period string) bool {
+	switch period {
+	case LeaderboardPeriodDaily, LeaderboardPeriodWeekly, LeaderboardPeriodMonthly, LeaderboardPeriodAllTime:
+		return true
+	}
+	return false
+}
+
+// IsValidLeaderboardMetric проверяет метрику таблицы лидеров
+func IsValidLeaderboardMetric(metric string) bool {
+	switch metric {
+	case LeaderboardMetricProfit, LeaderboardMetricWinRate, LeaderboardMetricEfficiency:
+		return true
+	}
+	return false
+}
+
+// LeaderboardWindow возвращает интервал [from, to) периода, в который попадает now.
+// Для all_time from нулевой. Границы считаются по UTC, недели начинаются с понедельника
+func LeaderboardWindow(period string, now time.Time) (from, to time.Time) {
+	now = now.UTC()
+	switch period {
+	case LeaderboardPeriodDaily:
+		from = CommissionPeriodStart(now, CommissionPeriodDay)
+		return from, from.AddDate(0, 0, 1)
+	case LeaderboardPeriodWeekly:
+		from = CommissionPeriodStart(now, CommissionPeriodWeek)
+		return from, from.AddDate(0, 0, 7)
+	case LeaderboardPeriodMonthly:
+		from = CommissionPeriodStart(now, CommissionPeriodMonth)
+		return from, from.AddDate(0, 1, 0)
+	default:
+		return time.Time{}, now
+	}
+}
+
+// PreviousLeaderboardWindow возвращает интервал [from, to) периода, предшествующего текущему (завершённый сезон)
+func PreviousLeaderboardWindow(period string, now time.Time) (from, to time.Time) {
+	current, _ := LeaderboardWindow(period, now)
+	return LeaderboardWindow(period, current.Add(-time.Nanosecond))
+}
+
+// LeaderboardQuery параметры выборки таблицы лидеров из истории игр
+type LeaderboardQuery struct {
+	Metric   string
+	Currency string    // Прибыль считается по лобби в этой валюте
+	From     time.Time // Нулевое значение - с начала истории
+	To       time.Time
+	MinGames int // Минимум сыгранных лобби в периоде
+	Limit    int
+}
+
+// LeaderboardEntry представляет собой строку таблицы лидеров
+type LeaderboardEntry struct {
+	Rank     int     `json:"rank"`
+	UserID   uint64  `json:"user_id"`
+	Username string  `json:"username"`
+	Games    int     `json:"games"`
+	Wins     int     `json:"wins"`
+	Profit   float64 `json:"profit"`
+	WinRate  float64 `json:"win_rate"`
+	AvgTries float64 `json:"avg_tries"` // Среднее число попыток в выигранных лобби (0 - побед нет)
+	Value    float64 `json:"value"`     // Значение метрики, по которой построена таблица
+}
+
+// Leaderboard представляет собой таблицу лидеров за период
+type Leaderboard struct {
+	Period   string              `json:"period"`
+	Metric   string              `json:"metric"`
+	Currency string              `json:"currency"`
+	From     *time.Time          `json:"from,omitempty"` // Нет для all_time
+	To       time.Time           `json:"to"`
+	MinGames int                 `json:"min_games"`
+	Entries  []*LeaderboardEntry `json:"entries"`
+}
+
+// LeaderboardSeason условия автоматической выплаты призов победителям завершившегося периода
+type LeaderboardSeason struct {
+	Period   string    `json:"period"` // daily, weekly или monthly
+	Metric   string    `json:"metric"`
+	Currency string    `json:"currency"`
+	MinGames int       `json:"min_games"`
+	Prizes   []float64 `json:"prizes"` // Призы по местам: первый элемент - за первое место
+}
+
+// LeaderboardRule условия таблиц лидеров
+type LeaderboardRule struct {
+	MinGames int                 `json:"min_games"` // Минимум лобби для рейтингов по доле побед и эффективности (0 - LeaderboardDefaultMinGames)
+	Seasons  []LeaderboardSeason `json:"seasons"`   // Сезоны с автоматической выплатой призов (нет - призы выключены)
+}
+
+// LeaderboardPayout представляет собой выплаченный приз сезона.
+// По каждому месту сезона возможна только одна выплата
+type LeaderboardPayout struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Period      string    `json:"period" db:"period"`
+	Metric      string    `json:"metric" db:"metric"`
+	Currency    string    `json:"currency" db:"currency"`
+	PeriodStart time.Time `json:"period_start" db:"period_start"`
+	Rank        int       `json:"rank" db:"rank"`
+	UserID      uint64    `json:"user_id" db:"user_id"`
+	Amount      float64   `json:"amount" db:"amount"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -18,6 +18,7 @@ const (
 	LobbyStatusFailedExpired  = "failed_expired"   // Время истекло
 	LobbyStatusFailedInternal = "failed_internal"  // Внутренняя ошибка
 	LobbyStatusCanceled       = "canceled"         // Отменено
+	LobbyStatusCashedOut      = "cashed_out"       // Игрок забрал выплату досрочно
 )
 
 // Lobby представляет собой модель игрового лобби (игровая сессия)
@@ -29,6 +30,7 @@ type Lobby struct {
 	MaxTries        int       `json:"max_tries" db:"max_tries"`                   // Максимум попыток
 	TriesUsed       int       `json:"tries_used" db:"tries_used"`                 // Использовано попыток
 	BetAmount       float64   `json:"bet_amount" db:"bet_amount"`                 // Размер ставки
+	BonusAmount     float64   `json:"bonus_amount,omitempty" db:"-"`              // Часть ставки, оплаченная бонусом (только при создании)
 	PotentialReward float64   `json:"potential_reward" db:"potential_reward"`     // Потенциальная награда
 	PaymentTxHash   string    `json:"payment_tx_hash,omitempty" db:"payment_tx_hash"` // Хеш транзакции оплаты
 	Currency        string    `json:"currency" db:"currency"`                     // Валюта
@@ -38,6 +40,21 @@ type Lobby struct {
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 	Attempts        []Attempt `json:"attempts,omitempty"`                         // Список попыток
+	InviteToken     string    `json:"-" db:"-"`                                   // Токен приглашения в приватную игру (не сохраняется)
+}
+
+// CashOutOffer представляет собой предложение досрочной выплаты по активному лобби
+type CashOutOffer struct {
+	LobbyID         uuid.UUID `json:"lobby_id"`
+	Amount          float64   `json:"amount"`           // Сумма выплаты
+	Fraction        float64   `json:"fraction"`         // Доля от потенциальной награды
+	PotentialReward float64   `json:"potential_reward"` // Потенциальная награда (за вычетом комиссии)
+	Currency        string    `json:"currency"`
+	TriesLeft       int       `json:"tries_left"`      // Оставшиеся попытки
+	Greens          int       `json:"greens"`          // Угаданные позиции
+	Yellows         int       `json:"yellows"`         // Найденные буквы не на месте
+	CandidatesLeft  int       `json:"candidates_left"` // Слов в словаре, подходящих под подсказки (0 - неизвестно)
+	QuotedAt        time.Time `json:"quoted_at"`
 }
 
 // IsExpired проверяет, истекло ли время лобби
@@ -68,6 +85,15 @@ func (l *Lobby) GetRemainingTime() int64 {
 	return int64(remaining.Seconds())
 }
 
+// Multiplier возвращает мультипликатор, зафиксированный при создании лобби.
+// Мультипликатор игры может меняться автоподстройкой, пока лобби активно
+func (l *Lobby) Multiplier(gameMultiplier float64) float64 {
+	if l.BetAmount <= 0 || l.PotentialReward <= 0 {
+		return gameMultiplier
+	}
+	return l.PotentialReward / l.BetAmount
+}
+
 // UnmarshalJSON реализует интерфейс json.Unmarshaler для корректной обработки поля max_tries
 // которое может быть как числом, так и строкой
 func (l *Lobby) UnmarshalJSON(data []byte) error {
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы уведомлений бота
+const (
+	NotificationDepositCredited = "deposit_credited" // Депозит зачислен на баланс
+	NotificationGameActivated   = "game_activated"   // Игра создателя стала активной
+	NotificationGameResult      = "game_result"      // Игрок выиграл или проиграл в игре создателя
+	NotificationLobbyExpiring   = "lobby_expiring"   // Время лобби игрока скоро истечёт
+	NotificationWithdrawalSent  = "withdrawal_sent"  // Вывод отправлен в блокчейн
+)
+
+// NotificationTypes все типы уведомлений, которые пользователь может отключить
+var NotificationTypes = []string{
+	NotificationDepositCredited,
+	NotificationGameActivated,
+	NotificationGameResult,
+	NotificationLobbyExpiring,
+	NotificationWithdrawalSent,
+}
+
+// Статусы уведомлений в очереди отправки
+const (
+	NotificationStatusPending = "pending" // Ожидает отправки или повторной попытки
+	NotificationStatusSent    = "sent"    // Доставлено в Bot API
+	NotificationStatusFailed  = "failed"  // Попытки исчерпаны или пользователь недоступен
+)
+
+// ErrInvalidNotificationType возвращается при изменении настроек неизвестного типа уведомлений
+var ErrInvalidNotificationType = errors.New("invalid notification type")
+
+// Notification представляет собой сообщение бота в очереди отправки (outbox)
+type Notification struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	UserID        uint64     `json:"user_id" db:"user_id"`
+	Type          string     `json:"type" db:"type"`
+	DedupKey      string     `json:"-" db:"dedup_key"` // Ключ события: повторное событие не ставит сообщение в очередь (пусто - без проверки)
+	Text          string     `json:"text" db:"text"`   // Текст на языке пользователя
+	Status        string     `json:"status" db:"status"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+}
+
+// NotificationRule параметры отправки уведомлений
+type NotificationRule struct {
+	MaxAttempts   int           // Попыток отправки до статуса failed
+	RetryDelay    time.Duration // Задержка перед первой повторной попыткой, далее удваивается
+	RatePerSecond int           // Не больше стольких сообщений в секунду (лимит Bot API - 30)
+	BatchSize     int           // Сообщений за один запуск фоновой задачи
+	ExpiryNotice  time.Duration // За сколько до истечения лобби напоминать игроку
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PageCursor позиция keyset-пагинации: ключ сортировки последней записи страницы и её ID.
+// Записи упорядочены по убыванию ключа, при равенстве - по убыванию ID
+type PageCursor struct {
+	Sort  string    `json:"s,omitempty"` // Сортировка, для которой выдан курсор
+	Time  time.Time `json:"t,omitempty"` // Ключ сортировки по времени
+	Value float64   `json:"v,omitempty"` // Числовой ключ сортировки
+	ID    uuid.UUID `json:"i"`
+}
+
+// Encode возвращает непрозрачное представление курсора для клиента
+func (c *PageCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageCursor разбирает курсор, выданный Encode. Пустая строка - первая страница (nil)
+func DecodePageCursor(token, sort string) (*PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor PageCursor
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil || cursor.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// GamePage страница списка игр. Пустой NextCursor - страниц больше нет
+type GamePage struct {
+	Games      []*Game `json:"games"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// LobbyPage страница списка лобби
+type LobbyPage struct {
+	Lobbies    []*Lobby `json:"lobbies"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// TransactionPage страница списка транзакций
+type TransactionPage struct {
+	Transactions []*Transaction `json:"transactions"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -95,3 +96,13 @@ func ParsePaymentComment(comment string) (paymentType string, shortID string, ok
 	// Если второе подчёркивание не найдено, весь остаток - это short ID
 	return paymentType, rest, true
 }
+
+// ParsePaymentInviteTicket извлекает билет приглашения в приватную игру из комментария ставки.
+// Формат: LB_SHORTID_TIMESTAMP_TICKET. Возвращает пустую строку, если билета нет
+func ParsePaymentInviteTicket(comment string) string {
+	parts := strings.SplitN(comment, "_", 4)
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[3]
+}
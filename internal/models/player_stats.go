@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+// PlayerStatsFavoriteWords сколько любимых стартовых слов возвращается в статистике
+const PlayerStatsFavoriteWords = 5
+
+// PlayerResult представляет собой результат рассчитанного лобби, по которому обновляются агрегаты статистики игрока
+type PlayerResult struct {
+	UserID       uint64
+	Day          time.Time // Календарный день (UTC), см. StreakDay
+	Currency     string
+	Status       string // Итоговый статус лобби
+	BetAmount    float64
+	Payout       float64 // Выплата игроку (0 при проигрыше)
+	TriesUsed    int
+	StartWord    string  // Первое слово игрока в лобби (пусто - попыток не было)
+	SolveSeconds float64 // Время решения для выигранного лобби (0 - неизвестно)
+}
+
+// Won проверяет, выиграл ли игрок лобби
+func (r *PlayerResult) Won() bool {
+	return r.Status == LobbyStatusSuccess
+}
+
+// PlayerDailyStats представляет собой дневной агрегат статистики игрока в одной валюте
+type PlayerDailyStats struct {
+	UserID       uint64    `json:"user_id" db:"user_id"`
+	Day          time.Time `json:"day" db:"day"`
+	Currency     string    `json:"currency" db:"currency"`
+	Games        int       `json:"games" db:"games"`
+	Wins         int       `json:"wins" db:"wins"`
+	CashOuts     int       `json:"cash_outs" db:"cash_outs"`
+	Wagered      float64   `json:"wagered" db:"wagered"`
+	PaidOut      float64   `json:"paid_out" db:"paid_out"`
+	SolveSeconds float64   `json:"solve_seconds" db:"solve_seconds"` // Суммарное время решения выигранных лобби
+	TimedSolves  int       `json:"timed_solves" db:"timed_solves"`   // Выигранные лобби с известным временем решения
+}
+
+// WordCount представляет собой слово и число его использований
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// CurrencyProfit представляет собой итог ставок и выплат игрока в одной валюте
+type CurrencyProfit struct {
+	Currency string  `json:"currency"`
+	Wagered  float64 `json:"wagered"`
+	PaidOut  float64 `json:"paid_out"`
+	Profit   float64 `json:"profit"`
+}
+
+// ProfitPoint представляет собой точку графика прибыли игрока по дням
+type ProfitPoint struct {
+	Day        time.Time `json:"day"`
+	Currency   string    `json:"currency"`
+	Profit     float64   `json:"profit"`     // Прибыль за день
+	Cumulative float64   `json:"cumulative"` // Прибыль с начала выбранного периода
+}
+
+// PlayerStats представляет собой статистику игрока за период
+type PlayerStats struct {
+	UserID             uint64           `json:"user_id"`
+	From               *time.Time       `json:"from,omitempty"` // Нет - с начала истории
+	To                 time.Time        `json:"to"`
+	Games              int              `json:"games"`
+	Wins               int              `json:"wins"`
+	Losses             int              `json:"losses"`
+	CashOuts           int              `json:"cash_outs"`
+	WinRate            float64          `json:"win_rate"`
+	GuessDistribution  map[int]int      `json:"guess_distribution"` // Число побед по количеству попыток
+	AvgSolveSeconds    float64          `json:"avg_solve_seconds"`
+	CurrentStreak      int              `json:"current_streak"` // Побед подряд (за всё время)
+	MaxStreak          int              `json:"max_streak"`
+	PlayStreak         int              `json:"play_streak"` // Дней подряд с игрой (за всё время)
+	MaxPlayStreak      int              `json:"max_play_streak"`
+	Profit             []CurrencyProfit `json:"profit"`
+	ProfitSeries       []ProfitPoint    `json:"profit_series"`
+	FavoriteStartWords []WordCount      `json:"favorite_start_words"`
+}
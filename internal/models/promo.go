@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы промокодов
+const (
+	PromoTypeBonus   = "bonus"    // Бонусные средства с требованием отыгрыша
+	PromoTypeFreeBet = "free_bet" // Бесплатная ставка: бонус, который достаточно поставить один раз
+)
+
+// PromoCode представляет собой промокод, начисляющий бонусные средства.
+// Бонус переводится в основной баланс после того, как пользователь сделает ставок на Amount * WagerMultiplier
+type PromoCode struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	Code            string     `json:"code" db:"code"`
+	Type            string     `json:"type" db:"type"`
+	Currency        string     `json:"currency" db:"currency"`
+	Amount          float64    `json:"amount" db:"amount"`
+	WagerMultiplier float64    `json:"wager_multiplier" db:"wager_multiplier"` // Требуемый оборот ставок в долях бонуса
+	MaxRedemptions  int        `json:"max_redemptions" db:"max_redemptions"`   // Всего активаций (0 - без ограничения)
+	PerUserLimit    int        `json:"per_user_limit" db:"per_user_limit"`     // Активаций на одного пользователя
+	Redemptions     int        `json:"redemptions" db:"redemptions"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	Active          bool       `json:"active" db:"active"`
+	CreatedBy       uint64     `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsExpired проверяет, истёк ли срок действия промокода
+func (p *PromoCode) IsExpired(at time.Time) bool {
+	return p.ExpiresAt != nil && !at.Before(*p.ExpiresAt)
+}
+
+// Wager возвращает оборот ставок, необходимый для отыгрыша бонуса по промокоду
+func (p *PromoCode) Wager() float64 {
+	return p.Amount * p.WagerMultiplier
+}
+
+// PromoRedemption представляет собой активацию промокода пользователем
+type PromoRedemption struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	PromoCodeID uuid.UUID `json:"promo_code_id" db:"promo_code_id"`
+	Code        string    `json:"code" db:"code"`
+	UserID      uint64    `json:"user_id" db:"user_id"`
+	Currency    string    `json:"currency" db:"currency"`
+	Amount      float64   `json:"amount" db:"amount"`
+	Wager       float64   `json:"wager" db:"wager"` // Добавленный к отыгрышу оборот ставок
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// BonusStake представляет собой часть ставки лобби, оплаченную бонусными средствами.
+// Такая же доля выигрыша возвращается на бонусный баланс
+type BonusStake struct {
+	LobbyID     uuid.UUID `json:"lobby_id" db:"lobby_id"`
+	UserID      uint64    `json:"user_id" db:"user_id"`
+	Currency    string    `json:"currency" db:"currency"`
+	BetAmount   float64   `json:"bet_amount" db:"bet_amount"`
+	BonusAmount float64   `json:"bonus_amount" db:"bonus_amount"`
+	Settled     bool      `json:"settled" db:"settled"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// BonusShare возвращает долю суммы, приходящуюся на бонусную часть ставки
+func (b *BonusStake) BonusShare(amount float64) float64 {
+	if b.BetAmount <= 0 {
+		return 0
+	}
+	return amount * min(b.BonusAmount/b.BetAmount, 1)
+}
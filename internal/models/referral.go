@@ -0,0 +1,112 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReferralStartParamPrefix - префикс параметра startapp в реферальной ссылке
+const ReferralStartParamPrefix = "ref_"
+
+// Статусы реферала
+const (
+	ReferralStatusActive  = "active"  // Реферер получает долю комиссии
+	ReferralStatusBlocked = "blocked" // Заблокирован антифродом
+)
+
+// Статусы реферальных начислений
+const (
+	ReferralEarningPending  = "pending"  // Начислено, ожидает выплаты
+	ReferralEarningCredited = "credited" // Выплачено на баланс реферера
+	ReferralEarningCanceled = "canceled" // Отменено (реферал заблокирован)
+)
+
+// ReferralRule условия реферальной программы
+type ReferralRule struct {
+	CommissionShare float64       `json:"commission_share"` // Доля комиссии с рефералов, получаемая реферером (0 - программа выключена)
+	Period          time.Duration `json:"-"`                // Сколько реферер получает долю после регистрации реферала
+}
+
+// Enabled проверяет, включена ли реферальная программа
+func (r ReferralRule) Enabled() bool {
+	return r.CommissionShare > 0 && r.Period > 0
+}
+
+// Referral представляет собой пользователя, пришедшего по реферальной ссылке
+type Referral struct {
+	RefereeID   uint64    `json:"referee_id" db:"referee_id"`   // Приглашённый пользователь
+	ReferrerID  uint64    `json:"referrer_id" db:"referrer_id"` // Пригласивший пользователь
+	Code        string    `json:"code" db:"code"`
+	Status      string    `json:"status" db:"status"`
+	BlockReason string    `json:"block_reason,omitempty" db:"block_reason"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"` // До этого момента реферер получает долю комиссии
+}
+
+// Earns проверяет, получает ли реферер долю комиссии, начисленной в момент at
+func (r *Referral) Earns(at time.Time) bool {
+	return r.Status == ReferralStatusActive && !at.Before(r.CreatedAt) && at.Before(r.ExpiresAt)
+}
+
+// ReferralEarning представляет собой начисление рефереру с комиссии реферала.
+// По каждой записи комиссии возможно только одно начисление
+type ReferralEarning struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	CommissionEntryID uuid.UUID  `json:"commission_entry_id" db:"commission_entry_id"`
+	ReferrerID        uint64     `json:"referrer_id" db:"referrer_id"`
+	RefereeID         uint64     `json:"referee_id" db:"referee_id"`
+	Currency          string     `json:"currency" db:"currency"`
+	Amount            float64    `json:"amount" db:"amount"`
+	Status            string     `json:"status" db:"status"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	CreditedAt        *time.Time `json:"credited_at,omitempty" db:"credited_at"`
+}
+
+// ReferralEarningTotal сумма начислений по рефералу в валюте и статусе
+type ReferralEarningTotal struct {
+	ReferrerID uint64
+	RefereeID  uint64
+	Currency   string
+	Status     string
+	Amount     float64
+}
+
+// ReferredUser приглашённый пользователь в отчёте реферера
+type ReferredUser struct {
+	UserID    uint64             `json:"user_id"`
+	Username  string             `json:"username"`
+	Status    string             `json:"status"`
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Earned    map[string]float64 `json:"earned"`  // Выплачено по валютам
+	Pending   map[string]float64 `json:"pending"` // Ожидает выплаты по валютам
+}
+
+// ReferralSummary реферальная ссылка пользователя, приглашённые пользователи и заработок
+type ReferralSummary struct {
+	Code            string             `json:"code"`
+	StartParam      string             `json:"start_param"`
+	InviteLink      string             `json:"invite_link,omitempty"`
+	CommissionShare float64            `json:"commission_share"`
+	PeriodDays      int                `json:"period_days"`
+	Referred        []*ReferredUser    `json:"referred"`
+	Earned          map[string]float64 `json:"earned"`
+	Pending         map[string]float64 `json:"pending"`
+}
+
+// ReferralStartParam возвращает параметр startapp для реферальной ссылки
+func ReferralStartParam(code string) string {
+	return ReferralStartParamPrefix + code
+}
+
+// ParseReferralStartParam извлекает реферальный код из параметра startapp.
+// Возвращает пустую строку, если параметр не реферальный
+func ParseReferralStartParam(param string) string {
+	param = strings.TrimSpace(param)
+	if !strings.HasPrefix(param, ReferralStartParamPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(param, ReferralStartParamPrefix)
+}
@@ -16,6 +16,23 @@ var (
 	ErrTransactionNotFound = errors.New("transaction not found")
 	ErrInsufficientFunds   = errors.New("insufficient funds")
 	ErrDuplicateTransaction = errors.New("duplicate transaction")
+	ErrSideBetNotFound     = errors.New("side bet not found")
+	ErrDuelNotFound        = errors.New("duel not found")
+	ErrReferralNotFound    = errors.New("referral not found")
+	ErrInsufficientBonus   = errors.New("insufficient bonus balance")
+	ErrPromoCodeNotFound   = errors.New("promo code not found")
+	ErrPromoCodeExists     = errors.New("promo code already exists")
+	ErrPromoCodeInactive   = errors.New("promo code is not active")
+	ErrPromoCodeExpired    = errors.New("promo code expired")
+	ErrPromoCodeExhausted  = errors.New("promo code redemption limit reached")
+	ErrPromoCodeRedeemed   = errors.New("promo code already redeemed")
+	ErrDailyRewardClaimed  = errors.New("daily reward already claimed today")
+	ErrNotGameCreator      = errors.New("only the game creator can view analytics")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrReputationNotFound  = errors.New("creator reputation not found")
+	ErrFollowNotFound      = errors.New("you do not follow this creator")
+	ErrBookmarkNotFound    = errors.New("bookmark not found")
 )
 
 // GameRepository определяет методы для работы с играми
@@ -23,6 +40,7 @@ type GameRepository interface {
 	Create(ctx context.Context, game *Game) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Game, error)
 	GetByShortID(ctx context.Context, shortID string) (*Game, error)
+	GetByInviteToken(ctx context.Context, token string) (*Game, error)
 	GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*Game, error)
 	GetByCreator(ctx context.Context, creatorID uint64, limit, offset int) ([]*Game, error)
 	Update(ctx context.Context, game *Game) error
@@ -32,12 +50,29 @@ type GameRepository interface {
 	GetPending(ctx context.Context, limit, offset int) ([]*Game, error)
 	CountByUser(ctx context.Context, userID uint64) (int, error)
 	GetGameStats(ctx context.Context, gameID uuid.UUID) (map[string]any, error)
-	SearchGames(ctx context.Context, minBet, maxBet float64, difficulty string, limit, offset int) ([]*Game, error)
+	// SearchGames ищет публичные активные игры в порядке filter.Sort, начиная после cursor (nil - с начала)
+	SearchGames(ctx context.Context, filter GameSearchFilter, limit int, cursor *PageCursor) ([]*Game, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
 	UpdateRewardPool(ctx context.Context, id uuid.UUID, rewardPoolTon, rewardPoolUsdt float64) error
 	UpdateReservedAmount(ctx context.Context, id uuid.UUID, reservedAmount float64) error
 	IncrementReservedAmount(ctx context.Context, id uuid.UUID, amount float64) error
 	DecrementReservedAmount(ctx context.Context, id uuid.UUID, amount float64) error
+	GetDueToStart(ctx context.Context, now time.Time, limit int) ([]*Game, error)
+	GetDueToClose(ctx context.Context, now time.Time, limit int) ([]*Game, error)
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to string) (bool, error)
+	// IncrementRewardPool и DecrementRewardPool атомарно изменяют пул в валюте игры
+	IncrementRewardPool(ctx context.Context, id uuid.UUID, amount float64) error
+	DecrementRewardPool(ctx context.Context, id uuid.UUID, amount float64) error
+	// WithdrawRewardPool списывает сумму из пула, только если доступный остаток не опустится ниже margin.
+	// Возвращает false, если средств недостаточно
+	WithdrawRewardPool(ctx context.Context, id uuid.UUID, amount, margin float64) (bool, error)
+	GetBelowTopUpThreshold(ctx context.Context, limit int) ([]*Game, error)
+	// AddDailyLoss атомарно добавляет результат лобби к дневному убытку создателя (счётчик сбрасывается при смене дня)
+	AddDailyLoss(ctx context.Context, id uuid.UUID, amount float64, day time.Time) error
+	GetWithAutoOdds(ctx context.Context, limit int) ([]*Game, error)
+	UpdateRewardMultiplier(ctx context.Context, id uuid.UUID, multiplier float64) error
+	// RecordPlay учитывает рассчитанное лобби в счётчиках популярности и доли побед игры
+	RecordPlay(ctx context.Context, id uuid.UUID, won bool) error
 }
 
 // UserRepository определяет методы для работы с пользователями
@@ -51,6 +86,16 @@ type UserRepository interface {
 	UpdateWallet(ctx context.Context, telegramID uint64, wallet string) error
 	UpdateTonBalance(ctx context.Context, telegramID uint64, amount float64) error
 	UpdateUsdtBalance(ctx context.Context, telegramID uint64, amount float64) error
+	// DebitBalance атомарно списывает amount с доступного баланса в валюте.
+	// Возвращает ErrInsufficientFunds, если доступного баланса не хватает
+	DebitBalance(ctx context.Context, telegramID uint64, currency string, amount float64) error
+	// UpdateBonusBalance изменяет бонусный баланс на amount и оставшийся оборот ставок на wager (не ниже нуля).
+	// Возвращает ErrInsufficientBonus, если бонусный баланс стал бы отрицательным
+	UpdateBonusBalance(ctx context.Context, telegramID uint64, currency string, amount, wager float64) error
+	// SpendBonusBalance атомарно списывает с бонусного баланса не больше amount и возвращает списанную сумму
+	SpendBonusBalance(ctx context.Context, telegramID uint64, currency string, amount float64) (float64, error)
+	// ConvertBonusBalance переводит бонусный баланс в основной, если оборот ставок отыгран, и возвращает переведённую сумму
+	ConvertBonusBalance(ctx context.Context, telegramID uint64, currency string) (float64, error)
 	UpdatePendingWithdrawal(ctx context.Context, telegramID uint64, amount float64) error
 	SetWithdrawalLock(ctx context.Context, telegramID uint64, lockUntil time.Time) error
 	GetTopUsers(ctx context.Context, limit int) ([]*User, error)
@@ -66,11 +111,16 @@ type LobbyRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*Lobby, error)
 	GetByGameID(ctx context.Context, gameID uuid.UUID, limit, offset int) ([]*Lobby, error)
 	GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*Lobby, error)
+	// GetByUserIDAfter получает лобби пользователя от новых к старым, начиная после cursor (nil - с начала)
+	GetByUserIDAfter(ctx context.Context, userID uint64, limit int, cursor *PageCursor) ([]*Lobby, error)
 	GetPendingByGameShortID(ctx context.Context, gameShortID string, userID uint64) (*Lobby, error)
 	Update(ctx context.Context, lobby *Lobby) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetActive(ctx context.Context, limit, offset int) ([]*Lobby, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+	// TransitionStatus атомарно меняет статус, только если текущий статус равен fromStatus.
+	// Возвращает false, если лобби уже было переведено в другой статус
+	TransitionStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus string) (bool, error)
 	UpdateTriesUsed(ctx context.Context, id uuid.UUID, triesUsed int) error
 	GetExpired(ctx context.Context) ([]*Lobby, error)
 	GetActiveByGameAndUser(ctx context.Context, gameID uuid.UUID, userID uint64) (*Lobby, error)
@@ -111,6 +161,9 @@ type HistoryRepository interface {
 	CountByUser(ctx context.Context, userID uint64) (int, error)
 	GetUserStats(ctx context.Context, userID uint64) (map[string]any, error)
 	GetGameHistoryStats(ctx context.Context, gameID uuid.UUID) (map[string]any, error)
+	CountGameResults(ctx context.Context, gameID uuid.UUID) (total, playerWins int, err error)
+	// CountResultsByRules возвращает результаты по всем играм с заданной длиной слова и числом попыток
+	CountResultsByRules(ctx context.Context, length, maxTries int) (total, playerWins int, err error)
 }
 
 // TransactionRepository определяет методы для работы с транзакциями
@@ -119,6 +172,8 @@ type TransactionRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*Transaction, error)
 	GetByTxHash(ctx context.Context, txHash string) (*Transaction, error)
 	GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*Transaction, error)
+	// GetByUserIDAfter получает транзакции пользователя от новых к старым, начиная после cursor (nil - с начала)
+	GetByUserIDAfter(ctx context.Context, userID uint64, limit int, cursor *PageCursor) ([]*Transaction, error)
 	Update(ctx context.Context, transaction *Transaction) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByType(ctx context.Context, transactionType string, limit, offset int) ([]*Transaction, error)
@@ -132,4 +187,260 @@ type TransactionRepository interface {
 	ExistsByTxHash(ctx context.Context, txHash string) (bool, error)
 	GetLastProcessedLt(ctx context.Context) (int64, error)
 	UpdateLastProcessedLt(ctx context.Context, lt int64) error
+	// SumByGame возвращает суммы завершённых транзакций игры по типам
+	SumByGame(ctx context.Context, gameID uuid.UUID) (map[string]float64, error)
+}
+
+// SideBetRepository определяет методы для работы со ставками зрителей
+type SideBetRepository interface {
+	// Place записывает ставку и зачисляет её на счёт рынка. Ставка записывается, только если лобби активно
+	// и tries_used не изменился с котировки (PlacedAtTry). Возвращает false, если рынок уже закрыт
+	Place(ctx context.Context, bet *SideBet) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*SideBet, error)
+	GetByLobbyID(ctx context.Context, lobbyID uuid.UUID) ([]*SideBet, error)
+	GetOpenByLobbyID(ctx context.Context, lobbyID uuid.UUID) ([]*SideBet, error)
+	GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*SideBet, error)
+	// Settle атомарно рассчитывает открытую ставку и списывает выплату со счёта рынка.
+	// Возвращает false, если ставка уже рассчитана
+	Settle(ctx context.Context, id uuid.UUID, status string, payout float64) (bool, error)
+	// GetAccounts возвращает счета рынка ставок во всех валютах
+	GetAccounts(ctx context.Context) ([]*SideBetAccount, error)
+}
+
+// CommissionRepository определяет методы для работы с комиссией и счётом сервиса
+type CommissionRepository interface {
+	// Book записывает комиссию и зачисляет её на счёт сервиса в одной транзакции.
+	// Возвращает false, если запись по источнику уже есть
+	Book(ctx context.Context, entry *CommissionEntry) (bool, error)
+	GetHouseAccounts(ctx context.Context) ([]*HouseAccount, error)
+	// GetCreatorVolume возвращает оборот ставок в играх создателя в валюте начиная с since
+	GetCreatorVolume(ctx context.Context, creatorID uint64, currency string, since time.Time) (float64, error)
+	// GetReport возвращает комиссию за [from, to), сгруппированную по периодам, валютам и источникам
+	GetReport(ctx context.Context, period string, from, to time.Time) ([]*CommissionReport, error)
+}
+
+// PromoRepository определяет методы для работы с промокодами и бонусными ставками
+type PromoRepository interface {
+	// Create создает промокод. Возвращает ErrPromoCodeExists, если код уже занят
+	Create(ctx context.Context, promo *PromoCode) error
+	GetByCode(ctx context.Context, code string) (*PromoCode, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*PromoCode, error)
+	SetActive(ctx context.Context, id uuid.UUID, active bool) error
+	// Redeem атомарно проверяет срок и лимиты промокода и записывает активацию.
+	// Сумма бонуса и оборот отыгрыша берутся из промокода и записываются в redemption
+	Redeem(ctx context.Context, code string, redemption *PromoRedemption) (*PromoCode, error)
+	GetRedemptionsByUser(ctx context.Context, userID uint64, limit, offset int) ([]*PromoRedemption, error)
+	CreateBonusStake(ctx context.Context, stake *BonusStake) error
+	DeleteBonusStake(ctx context.Context, lobbyID uuid.UUID) error
+	// SettleBonusStake атомарно помечает бонусную ставку лобби рассчитанной и возвращает её.
+	// Возвращает nil, если бонусной ставки нет или она уже рассчитана
+	SettleBonusStake(ctx context.Context, lobbyID uuid.UUID) (*BonusStake, error)
+}
+
+// AchievementRepository определяет методы для работы с достижениями и сериями пользователей
+type AchievementRepository interface {
+	// GetStreak возвращает серии пользователя (пустые, если пользователь ещё не играл)
+	GetStreak(ctx context.Context, userID uint64) (*UserStreak, error)
+	// RecordPlay атомарно учитывает рассчитанное лобби в сериях пользователя
+	RecordPlay(ctx context.Context, userID uint64, status string, at time.Time) (*UserStreak, error)
+	// RecordLogin атомарно учитывает ежедневный вход. Возвращает false, если вход в этот день уже учтён
+	RecordLogin(ctx context.Context, userID uint64, at time.Time) (*UserStreak, bool, error)
+	// Award выдаёт достижение. Возвращает false, если достижение уже было выдано
+	Award(ctx context.Context, achievement *UserAchievement) (bool, error)
+	GetByUser(ctx context.Context, userID uint64) ([]*UserAchievement, error)
+	// AddGamePlayer запоминает игрока игры и возвращает число разных игроков игры
+	AddGamePlayer(ctx context.Context, gameID uuid.UUID, userID uint64) (int, error)
+}
+
+// PlayerStatsRepository определяет методы для работы с агрегатами статистики игроков.
+// Агрегаты хранятся по дням (UTC), выборки принимают интервал дней [from, to)
+type PlayerStatsRepository interface {
+	// RecordResult атомарно учитывает результат лобби во всех агрегатах игрока
+	RecordResult(ctx context.Context, result *PlayerResult) error
+	GetDaily(ctx context.Context, userID uint64, from, to time.Time) ([]*PlayerDailyStats, error)
+	GetGuessDistribution(ctx context.Context, userID uint64, from, to time.Time) (map[int]int, error)
+	GetStartWords(ctx context.Context, userID uint64, from, to time.Time, limit int) ([]WordCount, error)
+}
+
+// AnalyticsRepository определяет методы для построения аналитики создателей по истории, попыткам, транзакциям и комиссии.
+// Выборки принимают интервал [From, To) и группируют данные по периодам (по UTC)
+type AnalyticsRepository interface {
+	// GetBuckets возвращает показатели игр по периодам и валютам (без расчётного пула)
+	GetBuckets(ctx context.Context, query *AnalyticsQuery) ([]*AnalyticsBucket, error)
+	// GetTriesHistogram возвращает число побед по количеству попыток
+	GetTriesHistogram(ctx context.Context, query *AnalyticsQuery) (map[int]int, error)
+	// GetGameSummaries возвращает итоги по каждой игре создателя, в которой были лобби
+	GetGameSummaries(ctx context.Context, query *AnalyticsQuery) ([]*AnalyticsGameSummary, error)
+	// GetTopGuesses возвращает самые частые попытки в игре, кроме exclude
+	GetTopGuesses(ctx context.Context, gameID uuid.UUID, from, to time.Time, exclude string, limit int) ([]WordCount, error)
+}
+
+// ReputationRepository определяет методы для работы с отзывами, спорами и репутацией создателей
+type ReputationRepository interface {
+	// SaveReview создаёт оценку игрока или обновляет его прежнюю оценку игры. Решение модератора сохраняется
+	SaveReview(ctx context.Context, review *GameReview) error
+	GetReview(ctx context.Context, id uuid.UUID) (*GameReview, error)
+	// GetGameReviews получает опубликованные отзывы об игре от новых к старым
+	GetGameReviews(ctx context.Context, gameID uuid.UUID, limit, offset int) ([]*GameReview, error)
+	// GetReviewsByStatus получает отзывы для модерации (пустой статус - все)
+	GetReviewsByStatus(ctx context.Context, status string, limit, offset int) ([]*GameReview, error)
+	ModerateReview(ctx context.Context, id uuid.UUID, status, note string, moderatorID uint64, at time.Time) error
+	GetGameRating(ctx context.Context, gameID uuid.UUID) (*GameRating, error)
+	// HasFinishedLobby проверяет, что у игрока есть рассчитанное лобби в игре
+	HasFinishedLobby(ctx context.Context, gameID uuid.UUID, userID uint64) (bool, error)
+	CreateDispute(ctx context.Context, dispute *CreatorDispute) error
+	GetReputationInputs(ctx context.Context, creatorID uint64) (*ReputationInputs, error)
+	SaveReputation(ctx context.Context, reputation *CreatorReputation) error
+	GetReputation(ctx context.Context, creatorID uint64) (*CreatorReputation, error)
+	// GetStaleCreators возвращает создателей, у которых после расчёта репутации завершились лобби или были возвраты ставок
+	GetStaleCreators(ctx context.Context, limit int) ([]uint64, error)
+}
+
+// FollowRepository определяет методы для работы с подписками на создателей, закладками и лентой игр
+type FollowRepository interface {
+	// Follow создаёт подписку или обновляет настройку уведомлений существующей
+	Follow(ctx context.Context, follow *CreatorFollow) error
+	Unfollow(ctx context.Context, followerID, creatorID uint64) error
+	GetFollowing(ctx context.Context, followerID uint64, limit, offset int) ([]*CreatorFollow, error)
+	// GetNotifiedFollowers возвращает подписчиков создателя, включивших уведомления
+	GetNotifiedFollowers(ctx context.Context, creatorID uint64) ([]uint64, error)
+	// AddBookmark сохраняет игру в закладки (повторное добавление не ошибка)
+	AddBookmark(ctx context.Context, bookmark *GameBookmark) error
+	RemoveBookmark(ctx context.Context, userID uint64, gameID uuid.UUID) error
+	// GetBookmarkedGames получает игры из закладок пользователя, сначала добавленные последними
+	GetBookmarkedGames(ctx context.Context, userID uint64, limit, offset int) ([]*Game, error)
+	// RecordActivation запоминает время перехода игры в статус active
+	RecordActivation(ctx context.Context, gameID uuid.UUID, at time.Time) error
+	// GetFollowedGames получает активные публичные игры отслеживаемых создателей, активированные после since
+	GetFollowedGames(ctx context.Context, followerID uint64, since time.Time, limit int) ([]*Game, error)
+	// GetPlayProfile собирает предпочтения игрока по истории
+	GetPlayProfile(ctx context.Context, userID uint64) (*PlayProfile, error)
+}
+
+// NotificationRepository определяет методы для работы с очередью уведомлений и настройками пользователей
+type NotificationRepository interface {
+	// Enqueue ставит уведомление в очередь. Возвращает false, если сообщение с тем же DedupKey уже было
+	Enqueue(ctx context.Context, notification *Notification) (bool, error)
+	// GetDue получает ожидающие уведомления, время попытки которых наступило, в порядке очереди
+	GetDue(ctx context.Context, now time.Time, limit int) ([]*Notification, error)
+	// UpdateDelivery сохраняет результат попытки отправки: статус, число попыток, время следующей попытки и ошибку
+	UpdateDelivery(ctx context.Context, notification *Notification) error
+	// GetPreferences возвращает изменённые пользователем настройки по типам уведомлений
+	GetPreferences(ctx context.Context, userID uint64) (map[string]bool, error)
+	SetPreference(ctx context.Context, userID uint64, notificationType string, enabled bool) error
+	// GetExpiringLobbies получает активные лобби, истекающие в (now, deadline], длившиеся дольше notice,
+	// о которых ещё не отправлялось напоминание
+	GetExpiringLobbies(ctx context.Context, now, deadline time.Time, notice time.Duration, limit int) ([]*Lobby, error)
+}
+
+// Transactor выполняет изменения нескольких репозиториев в одной транзакции БД
+type Transactor interface {
+	// WithinTx выполняет fn в транзакции: методы репозиториев, вызванные с переданным контекстом, участвуют в ней.
+	// Ошибка fn откатывает транзакцию. Вложенный вызов присоединяется к уже открытой транзакции
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// EventRepository определяет методы для работы с outbox доменных событий
+type EventRepository interface {
+	// Append записывает событие в outbox (внутри WithinTx - в ту же транзакцию)
+	Append(ctx context.Context, event *DomainEvent) error
+	// ClaimDue забирает недоставленные события, время попытки которых наступило, в порядке записи
+	// и откладывает их следующую попытку до claimUntil, чтобы другие экземпляры их не получили
+	ClaimDue(ctx context.Context, now, claimUntil time.Time, limit int) ([]*DomainEvent, error)
+	// MarkDelivered отмечает, что подписчик обработал событие, чтобы повтор не доставил его этому подписчику снова
+	MarkDelivered(ctx context.Context, id uuid.UUID, subscriber string) error
+	// UpdateDelivery сохраняет результат попытки доставки: статус, число попыток, время следующей попытки и ошибку
+	UpdateDelivery(ctx context.Context, event *DomainEvent) error
+	// GetDeadLetters получает события, доставка которых прекращена после исчерпания попыток
+	GetDeadLetters(ctx context.Context, limit, offset int) ([]*DomainEvent, error)
+	// Requeue возвращает событие из dead letter в очередь с обнулёнными попытками (ErrEventNotFound, если его там нет)
+	Requeue(ctx context.Context, id uuid.UUID) error
+}
+
+// WebhookRepository определяет методы для работы с webhook и журналом их доставок
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Webhook, error)
+	GetByOwner(ctx context.Context, ownerID uint64) ([]*Webhook, error)
+	// GetSubscribed получает активные webhook перечисленных владельцев, подписанные на тип события
+	GetSubscribed(ctx context.Context, ownerIDs []uint64, eventType string) ([]*Webhook, error)
+	Update(ctx context.Context, webhook *Webhook) error
+	// Delete удаляет webhook вместе с журналом доставок
+	Delete(ctx context.Context, id uuid.UUID) error
+	// CreateDelivery ставит доставку в очередь. Возвращает false, если доставка с тем же DedupKey уже была
+	CreateDelivery(ctx context.Context, delivery *WebhookDelivery) (bool, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*WebhookDelivery, error)
+	// GetDeliveries получает журнал доставок webhook, начиная с последних
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]*WebhookDelivery, error)
+	// GetDueDeliveries получает ожидающие доставки, время попытки которых наступило, в порядке очереди
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*WebhookDelivery, error)
+	// UpdateDelivery сохраняет результат попытки: статус, число попыток, время следующей попытки, код ответа и ошибку
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+}
+
+// LeaderboardRepository определяет методы для работы с таблицами лидеров
+type LeaderboardRepository interface {
+	// GetEntries строит таблицу лидеров по истории игр, исключая пользователей, скрытых из рейтингов
+	GetEntries(ctx context.Context, query *LeaderboardQuery) ([]*LeaderboardEntry, error)
+	SetOptOut(ctx context.Context, userID uint64, optOut bool) error
+	IsOptedOut(ctx context.Context, userID uint64) (bool, error)
+	// CreatePayout записывает приз сезона. Возвращает false, если приз за это место уже записан
+	CreatePayout(ctx context.Context, payout *LeaderboardPayout) (bool, error)
+	GetPayouts(ctx context.Context, limit, offset int) ([]*LeaderboardPayout, error)
+}
+
+// ReferralRepository определяет методы для работы с реферальной программой
+type ReferralRepository interface {
+	// EnsureCode сохраняет код пользователя, если его ещё нет, и возвращает сохранённый код
+	EnsureCode(ctx context.Context, userID uint64, code string) (string, error)
+	GetUserByCode(ctx context.Context, code string) (uint64, error)
+	// Create записывает реферала. Возвращает false, если у пользователя уже есть реферер
+	Create(ctx context.Context, referral *Referral) (bool, error)
+	GetByReferee(ctx context.Context, refereeID uint64) (*Referral, error)
+	GetByReferrer(ctx context.Context, referrerID uint64, limit, offset int) ([]*Referral, error)
+	Block(ctx context.Context, refereeID uint64, reason string) error
+	// AddEarning записывает начисление. Возвращает false, если начисление по записи комиссии уже есть
+	AddEarning(ctx context.Context, earning *ReferralEarning) (bool, error)
+	// GetPendingTotals возвращает суммы невыплаченных начислений по рефералам и валютам
+	GetPendingTotals(ctx context.Context, limit int) ([]*ReferralEarningTotal, error)
+	// ClaimPending атомарно помечает невыплаченные начисления реферала в валюте выплаченными и возвращает их сумму
+	ClaimPending(ctx context.Context, refereeID uint64, currency string) (float64, error)
+	CancelPending(ctx context.Context, refereeID uint64) error
+	// GetTotalsByReferrer возвращает суммы начислений реферера по рефералам, валютам и статусам
+	GetTotalsByReferrer(ctx context.Context, referrerID uint64) ([]*ReferralEarningTotal, error)
+}
+
+// JackpotRepository определяет методы для работы с джекпотом
+type JackpotRepository interface {
+	// Get возвращает джекпот в валюте (нулевой, если отчислений ещё не было)
+	Get(ctx context.Context, currency string) (*Jackpot, error)
+	GetAll(ctx context.Context) ([]*Jackpot, error)
+	// Contribute атомарно добавляет отчисление к джекпоту и записывает его в журнал.
+	// Возвращает false, если отчисление по лобби уже было
+	Contribute(ctx context.Context, entry *JackpotEntry) (bool, error)
+	// Payout атомарно забирает весь джекпот в валюте entry (заполняет Amount) и записывает выплату в журнал.
+	// Возвращает false, если выплата по лобби уже была или джекпот пуст
+	Payout(ctx context.Context, entry *JackpotEntry) (bool, error)
+	// GetEntries возвращает журнал джекпота от новых записей к старым (пустая валюта - все валюты)
+	GetEntries(ctx context.Context, currency string, limit, offset int) ([]*JackpotEntry, error)
+}
+
+// DuelRepository определяет методы для работы с дуэлями
+type DuelRepository interface {
+	Create(ctx context.Context, duel *Duel) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Duel, error)
+	GetByShortID(ctx context.Context, shortID string) (*Duel, error)
+	GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*Duel, error)
+	GetExpiredInvites(ctx context.Context) ([]*Duel, error)
+	GetExpiredActive(ctx context.Context) ([]*Duel, error)
+	// Accept атомарно принимает ожидающий вызов. Возвращает false, если вызов уже не ожидает принятия
+	Accept(ctx context.Context, duel *Duel) (bool, error)
+	// TransitionStatus атомарно меняет статус, если текущий статус равен fromStatus
+	TransitionStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus string) (bool, error)
+	// RecordAttempt атомарно сохраняет попытку и прогресс игрока активной дуэли, если игрок не закончил
+	// и число его попыток всё ещё равно prevTries. Возвращает false, если параллельная попытка уже засчитана
+	RecordAttempt(ctx context.Context, duel *Duel, userID uint64, prevTries int, attempt *DuelAttempt) (bool, error)
+	// Settle атомарно завершает активную дуэль. Возвращает false, если дуэль уже завершена
+	Settle(ctx context.Context, duel *Duel) (bool, error)
+	GetAttempts(ctx context.Context, duelID uuid.UUID, userID uint64) ([]*DuelAttempt, error)
 }
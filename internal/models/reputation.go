@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы отзывов
+const (
+	ReviewStatusVisible = "visible" // Отзыв опубликован
+	ReviewStatusHidden  = "hidden"  // Скрыт модератором и не учитывается в оценках
+)
+
+// ReviewMaxLength максимальная длина текста отзыва в символах
+const ReviewMaxLength = 500
+
+// Исходы споров игроков с создателями
+const (
+	DisputeOutcomePlayer  = "player"  // Спор решён в пользу игрока
+	DisputeOutcomeCreator = "creator" // Спор решён в пользу создателя
+)
+
+// ErrReviewNotAllowed возвращается, если игрок не завершил ни одного лобби в игре
+var ErrReviewNotAllowed = errors.New("only players who finished a lobby in this game can rate it")
+
+// DefaultReputationScore репутация создателя без оценок и истории игр
+const DefaultReputationScore = 75.0
+
+// GameReview представляет собой оценку игры игроком, завершившим в ней лобби.
+// Игрок оставляет одну оценку на игру и может её изменить
+type GameReview struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	GameID         uuid.UUID  `json:"game_id" db:"game_id"`
+	CreatorID      uint64     `json:"creator_id" db:"creator_id"`
+	UserID         uint64     `json:"user_id" db:"user_id"`
+	Rating         int        `json:"rating" db:"rating"` // От 1 до 5
+	Review         string     `json:"review" db:"review"`
+	Status         string     `json:"status" db:"status"`
+	ModerationNote string     `json:"moderation_note,omitempty" db:"moderation_note"`
+	ModeratedBy    uint64     `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty" db:"moderated_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// GameRating сводка опубликованных оценок игры
+type GameRating struct {
+	GameID       uuid.UUID   `json:"game_id"`
+	Average      float64     `json:"average"`
+	Count        int         `json:"count"`
+	Distribution map[int]int `json:"distribution"` // Число оценок по значению (1-5)
+}
+
+// CreatorDispute представляет собой исход спора игрока с создателем, записанный администратором
+type CreatorDispute struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	CreatorID  uint64     `json:"creator_id" db:"creator_id"`
+	UserID     uint64     `json:"user_id" db:"user_id"`
+	LobbyID    *uuid.UUID `json:"lobby_id,omitempty" db:"lobby_id"`
+	Outcome    string     `json:"outcome" db:"outcome"`
+	Note       string     `json:"note" db:"note"`
+	ResolvedBy uint64     `json:"resolved_by" db:"resolved_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ReputationInputs исходные данные для расчёта репутации создателя
+type ReputationInputs struct {
+	RatingSum    float64 // Сумма опубликованных оценок игр создателя
+	RatingCount  int
+	Lobbies      int // Завершённых лобби в играх создателя
+	Completed    int // Из них доиграно до результата (без отмен и внутренних ошибок)
+	Refunds      int // Ставок, возвращённых из-за закрытия игры или лимитов создателя
+	Disputes     int
+	DisputesLost int // Решено в пользу игрока
+}
+
+// CreatorReputation представляет собой репутацию создателя игр (0-100) и её составляющие
+type CreatorReputation struct {
+	CreatorID      uint64    `json:"creator_id" db:"creator_id"`
+	Score          float64   `json:"score" db:"score"`
+	RatingAverage  float64   `json:"rating_average" db:"rating_average"`
+	RatingCount    int       `json:"rating_count" db:"rating_count"`
+	CompletionRate float64   `json:"completion_rate" db:"completion_rate"`
+	RefundRate     float64   `json:"refund_rate" db:"refund_rate"`
+	Disputes       int       `json:"disputes" db:"disputes"`
+	DisputesLost   int       `json:"disputes_lost" db:"disputes_lost"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
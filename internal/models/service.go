@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,15 +20,29 @@ type GameService interface {
 	DeleteGame(ctx context.Context, id uuid.UUID) error
 	
 	// Получение списков
-	GetActiveGames(ctx context.Context, limit, offset int) ([]*Game, error)
+	GetActiveGames(ctx context.Context, limit int, cursor string) (*GamePage, error)
 	GetPendingGames(ctx context.Context, limit, offset int) ([]*Game, error)
-	SearchGames(ctx context.Context, minBet, maxBet float64, difficulty string, limit, offset int) ([]*Game, error)
+	SearchGames(ctx context.Context, filter GameSearchFilter, limit int, cursor string) (*GamePage, error)
 	GetGameStats(ctx context.Context, gameID uuid.UUID) (map[string]any, error)
 	
 	// Управление статусом и балансом
 	AddToRewardPool(ctx context.Context, gameID uuid.UUID, amount float64) error
 	ActivateGame(ctx context.Context, gameID uuid.UUID) error
 	DeactivateGame(ctx context.Context, gameID uuid.UUID) error
+
+	// Расписание и закрытие
+	CloseGame(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*Game, error)
+	ProcessScheduledGames(ctx context.Context) error
+	WithdrawFromPool(ctx context.Context, gameID uuid.UUID, creatorID uint64, amount float64) (*Game, error)
+	SetAutoTopUp(ctx context.Context, gameID uuid.UUID, creatorID uint64, rule AutoTopUpRule) (*Game, error)
+	ProcessAutoTopUps(ctx context.Context) error
+	GetPoolPnL(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*GamePoolPnL, error)
+	UpdateRiskLimits(ctx context.Context, gameID uuid.UUID, creatorID uint64, limits GameRiskLimits) (*Game, error)
+	EnforceRiskLimits(ctx context.Context, gameID uuid.UUID) error
+	GetGameOdds(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*GameOdds, error)
+	AnalyzeWord(ctx context.Context, word string) (*WordDifficulty, error)
+	SetOddsAutoAdjust(ctx context.Context, gameID uuid.UUID, creatorID uint64, rule OddsAdjustRule) (*Game, error)
+	ProcessOddsAdjustments(ctx context.Context) error
 	
 	// Резервирование средств
 	ReserveForBet(ctx context.Context, gameID uuid.UUID, betAmount float64, multiplier float64) error
@@ -35,6 +50,13 @@ type GameService interface {
 	
 	// Генерация платежной информации
 	GetPaymentInfo(ctx context.Context, gameID uuid.UUID) (*PaymentInfo, error)
+
+	// Приватные игры
+	CheckAccess(ctx context.Context, game *Game, userID uint64, inviteToken string) error
+	GetGameByInvite(ctx context.Context, startParam string) (*Game, error)
+	UpdateGameAccess(ctx context.Context, gameID uuid.UUID, creatorID uint64, access GameAccess) (*Game, error)
+	RotateInviteToken(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*Game, error)
+	InviteLink(game *Game) string
 	
 	// Проверка слова (утилиты)
 	CheckWord(word, target string) string
@@ -57,6 +79,8 @@ type UserService interface {
 	// Управление балансом
 	UpdateTonBalance(ctx context.Context, telegramID uint64, amount float64) error
 	UpdateUsdtBalance(ctx context.Context, telegramID uint64, amount float64) error
+	// DebitBalance атомарно списывает amount, если доступного баланса хватает (иначе ErrInsufficientFunds)
+	DebitBalance(ctx context.Context, telegramID uint64, currency string, amount float64) error
 	ValidateBalance(ctx context.Context, telegramID uint64, requiredAmount float64, currency string) (bool, error)
 	
 	// Статистика
@@ -77,7 +101,7 @@ type LobbyService interface {
 	CreateLobby(ctx context.Context, lobby *Lobby) error
 	GetLobby(ctx context.Context, id uuid.UUID) (*Lobby, error)
 	GetGameLobbies(ctx context.Context, gameID uuid.UUID, limit, offset int) ([]*Lobby, error)
-	GetUserLobbies(ctx context.Context, userID uint64, limit, offset int) ([]*Lobby, error)
+	GetUserLobbies(ctx context.Context, userID uint64, limit int, cursor string) (*LobbyPage, error)
 	UpdateLobby(ctx context.Context, lobby *Lobby) error
 	DeleteLobby(ctx context.Context, id uuid.UUID) error
 	
@@ -94,9 +118,13 @@ type LobbyService interface {
 	ProcessAttempt(ctx context.Context, lobbyID uuid.UUID, word string) ([]int, error)
 	FinishLobby(ctx context.Context, lobbyID uuid.UUID, success bool) error
 	StartLobby(ctx context.Context, lobbyID uuid.UUID) error
+
+	// Досрочная выплата (cash-out)
+	GetCashOutOffer(ctx context.Context, lobbyID uuid.UUID, userID uint64) (*CashOutOffer, error)
+	AcceptCashOut(ctx context.Context, lobbyID uuid.UUID, userID uint64) (*CashOutOffer, error)
 	
 	// Генерация платежной информации для вступления в игру
-	GetJoinPaymentInfo(ctx context.Context, gameShortID string, userID uint64, betAmount float64) (*PaymentInfo, error)
+	GetJoinPaymentInfo(ctx context.Context, gameShortID string, userID uint64, betAmount float64, inviteToken string) (*PaymentInfo, error)
 	
 	// Утилиты
 	CheckWord(word, target string) []int
@@ -104,6 +132,231 @@ type LobbyService interface {
 	CalculateReward(bet float64, multiplier float64, triesUsed, maxTries int) float64
 }
 
+// SideBetService определяет методы для работы со ставками зрителей
+type SideBetService interface {
+	GetMarket(ctx context.Context, lobbyID uuid.UUID, userID uint64, inviteToken string) (*SideBetMarket, error)
+	PlaceSideBet(ctx context.Context, lobbyID uuid.UUID, userID uint64, withinTries int, amount float64, atTry int, inviteToken string) (*SideBet, error)
+	GetLobbySideBets(ctx context.Context, lobbyID uuid.UUID) ([]*SideBet, error)
+	GetUserSideBets(ctx context.Context, userID uint64, limit, offset int) ([]*SideBet, error)
+	// SettleLobby рассчитывает все открытые ставки по завершённому лобби
+	SettleLobby(ctx context.Context, lobby *Lobby, finalStatus string) error
+	// GetAccounts возвращает счета рынка ставок зрителей
+	GetAccounts(ctx context.Context) ([]*SideBetAccount, error)
+}
+
+// PromoService определяет методы для работы с промокодами и бонусным балансом
+type PromoService interface {
+	CreatePromoCode(ctx context.Context, promo *PromoCode) error
+	GetPromoCodes(ctx context.Context, limit, offset int) ([]*PromoCode, error)
+	SetPromoCodeActive(ctx context.Context, id uuid.UUID, active bool) error
+	// RedeemPromoCode активирует промокод и зачисляет бонус на бонусный баланс пользователя
+	RedeemPromoCode(ctx context.Context, userID uint64, code string) (*PromoRedemption, error)
+	GetRedemptions(ctx context.Context, userID uint64, limit, offset int) ([]*PromoRedemption, error)
+	// ChargeBet списывает в счёт ставки лобби бонусные средства.
+	// Возвращает сумму, оплаченную бонусом; остаток ставки списывается с основного баланса
+	ChargeBet(ctx context.Context, lobby *Lobby) (float64, error)
+	// RefundBet возвращает бонусную часть ставки, если лобби не удалось создать
+	RefundBet(ctx context.Context, lobby *Lobby, bonusAmount float64) error
+	// SettleBet засчитывает рассчитанную ставку лобби в отыгрыш и зачисляет на бонусный баланс долю выплаты,
+	// приходящуюся на бонусную часть ставки. Возвращает оставшуюся часть выплаты для основного баланса
+	SettleBet(ctx context.Context, lobby *Lobby, reward float64) (float64, error)
+}
+
+// AchievementService определяет методы для работы с достижениями, сериями и ежедневными наградами
+type AchievementService interface {
+	// HandleEvent обновляет серии по доменному событию и выдаёт достижения, правила которых выполнены
+	HandleEvent(ctx context.Context, event *AchievementEvent) error
+	// ClaimDailyReward продлевает серию ежедневных входов и зачисляет награду (один раз в день)
+	ClaimDailyReward(ctx context.Context, userID uint64) (*DailyReward, error)
+	GetStreak(ctx context.Context, userID uint64) (*UserStreak, error)
+	GetAchievements(ctx context.Context, userID uint64) (*AchievementSummary, error)
+}
+
+// PlayerStatsService определяет методы для работы со статистикой игроков
+type PlayerStatsService interface {
+	// RecordLobby обновляет агрегаты статистики игрока по рассчитанному лобби
+	RecordLobby(ctx context.Context, lobby *Lobby, game *Game, finalStatus string, payout float64) error
+	// GetStats возвращает статистику игрока за [from, to]. Нулевой from - с начала истории, нулевой to - по текущий момент
+	GetStats(ctx context.Context, userID uint64, from, to time.Time) (*PlayerStats, error)
+}
+
+// CreatorAnalyticsService определяет методы для работы с аналитикой игр создателя
+type CreatorAnalyticsService interface {
+	// GetGameAnalytics возвращает аналитику игры. Доступна только создателю игры (ErrNotGameCreator)
+	GetGameAnalytics(ctx context.Context, gameID uuid.UUID, creatorID uint64, period string, from, to time.Time) (*GameAnalytics, error)
+	GetCreatorAnalytics(ctx context.Context, creatorID uint64, period string, from, to time.Time) (*CreatorAnalytics, error)
+}
+
+// ReputationService определяет методы для работы с оценками игр и репутацией создателей
+type ReputationService interface {
+	// RateGame сохраняет оценку (1-5) и отзыв игрока. Доступно только после рассчитанного лобби в игре (ErrReviewNotAllowed)
+	RateGame(ctx context.Context, gameID uuid.UUID, userID uint64, rating int, review string) (*GameReview, error)
+	GetGameReviews(ctx context.Context, gameID uuid.UUID, limit, offset int) ([]*GameReview, error)
+	GetGameRating(ctx context.Context, gameID uuid.UUID) (*GameRating, error)
+	// GetCreatorReputation возвращает последнюю рассчитанную репутацию создателя
+	GetCreatorReputation(ctx context.Context, creatorID uint64) (*CreatorReputation, error)
+	RefreshReputation(ctx context.Context, creatorID uint64) (*CreatorReputation, error)
+	// ProcessStaleReputations пересчитывает репутацию создателей с новыми завершёнными лобби и возвратами
+	ProcessStaleReputations(ctx context.Context) error
+	GetReviewsForModeration(ctx context.Context, status string, limit, offset int) ([]*GameReview, error)
+	// ModerateReview скрывает отзыв (не учитывается в оценках) или публикует его снова
+	ModerateReview(ctx context.Context, reviewID uuid.UUID, moderatorID uint64, hidden bool, note string) (*GameReview, error)
+	// RecordDispute записывает исход спора игрока с создателем и пересчитывает репутацию создателя
+	RecordDispute(ctx context.Context, dispute *CreatorDispute) error
+}
+
+// GameActivationNotifier получает уведомление о переходе игры в статус active
+type GameActivationNotifier interface {
+	GameActivated(ctx context.Context, game *Game) error
+}
+
+// GameActivationNotifiers передаёт событие активации игры нескольким получателям по очереди.
+// Ошибка одного получателя не мешает остальным
+type GameActivationNotifiers []GameActivationNotifier
+
+// GameActivated уведомляет всех получателей и объединяет их ошибки
+func (n GameActivationNotifiers) GameActivated(ctx context.Context, game *Game) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.GameActivated(ctx, game); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FollowService определяет методы для работы с подписками на создателей, закладками и лентой игр
+type FollowService interface {
+	GameActivationNotifier
+	FollowCreator(ctx context.Context, followerID, creatorID uint64, notify bool) (*CreatorFollow, error)
+	UnfollowCreator(ctx context.Context, followerID, creatorID uint64) error
+	GetFollowing(ctx context.Context, followerID uint64, limit, offset int) ([]*CreatorFollow, error)
+	BookmarkGame(ctx context.Context, userID uint64, gameID uuid.UUID) error
+	RemoveBookmark(ctx context.Context, userID uint64, gameID uuid.UUID) error
+	GetBookmarks(ctx context.Context, userID uint64, limit, offset int) ([]*Game, error)
+	// GetFeed возвращает новые игры отслеживаемых создателей и рекомендации по истории игр пользователя
+	GetFeed(ctx context.Context, userID uint64, limit int) (*Feed, error)
+}
+
+// NotificationService определяет методы для уведомлений пользователей через бота.
+// События ставят локализованные сообщения в очередь, фоновая задача отправляет их с повторами
+type NotificationService interface {
+	// GameActivated уведомляет создателя о том, что его игра стала активной
+	GameActivationNotifier
+	// GetPreferences возвращает настройки по всем типам уведомлений (по умолчанию включены)
+	GetPreferences(ctx context.Context, userID uint64) (map[string]bool, error)
+	// UpdatePreferences меняет настройки переданных типов (ErrInvalidNotificationType для неизвестного типа)
+	UpdatePreferences(ctx context.Context, userID uint64, preferences map[string]bool) (map[string]bool, error)
+	DepositCredited(ctx context.Context, tx *Transaction) error
+	WithdrawalSent(ctx context.Context, tx *Transaction) error
+	// LobbyFinished уведомляет создателя игры о выигрыше или проигрыше игрока
+	LobbyFinished(ctx context.Context, lobby *Lobby, game *Game, finalStatus string, reward float64) error
+	// ProcessExpiringLobbies напоминает игрокам о скором истечении времени лобби
+	ProcessExpiringLobbies(ctx context.Context) error
+	// ProcessOutbox отправляет накопившиеся уведомления с ограничением частоты и повторами при ошибках
+	ProcessOutbox(ctx context.Context) error
+}
+
+// EventHandler обрабатывает доменное событие. Ошибка приводит к повторной доставке,
+// поэтому обработчик должен быть идемпотентным
+type EventHandler func(ctx context.Context, event *DomainEvent) error
+
+// EventService определяет методы шины доменных событий: запись в outbox и доставку подписчикам
+type EventService interface {
+	// WithinTx выполняет изменение состояния и запись событий в одной транзакции БД
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// Publish записывает событие в outbox (внутри WithinTx - в ту же транзакцию)
+	Publish(ctx context.Context, event *DomainEvent) error
+	// Subscribe регистрирует подписчика на события перечисленных типов. Имя подписчика должно быть постоянным:
+	// по нему отмечается доставка
+	Subscribe(name string, handler EventHandler, eventTypes ...string)
+	// ProcessEvents доставляет накопившиеся события подписчикам с повторами при ошибках
+	ProcessEvents(ctx context.Context) error
+	// GetDeadLetters получает события, доставка которых прекращена после исчерпания попыток
+	GetDeadLetters(ctx context.Context, limit, offset int) ([]*DomainEvent, error)
+	// RetryDeadLetter возвращает событие из dead letter в очередь доставки
+	RetryDeadLetter(ctx context.Context, id uuid.UUID) error
+}
+
+// WebhookService определяет методы исходящих webhook: подписки пользователей на доменные события
+// и их доставку с подписью HMAC и повторами. Чужой webhook недоступен владельцу (ErrWebhookNotFound)
+type WebhookService interface {
+	// CreateWebhook создаёт webhook. Пустой secret генерируется; ключ возвращается только в ответе на создание
+	CreateWebhook(ctx context.Context, ownerID uint64, webhookURL, secret string, eventTypes []string) (*Webhook, error)
+	GetWebhooks(ctx context.Context, ownerID uint64) ([]*Webhook, error)
+	UpdateWebhook(ctx context.Context, ownerID uint64, id uuid.UUID, update *WebhookUpdate) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, ownerID uint64, id uuid.UUID) error
+	// GetDeliveries возвращает журнал доставок webhook с кодами ответов, начиная с последних
+	GetDeliveries(ctx context.Context, ownerID uint64, id uuid.UUID, limit, offset int) ([]*WebhookDelivery, error)
+	// ReplayDelivery ставит в очередь повторную отправку события из журнала
+	ReplayDelivery(ctx context.Context, ownerID uint64, id, deliveryID uuid.UUID) (*WebhookDelivery, error)
+	// SendTestEvent сразу отправляет на webhook тестовое событие и возвращает результат попытки
+	SendTestEvent(ctx context.Context, ownerID uint64, id uuid.UUID) (*WebhookDelivery, error)
+	// HandleEvent ставит доменное событие в очередь доставки на webhook его участников
+	HandleEvent(ctx context.Context, event *DomainEvent) error
+	// ProcessDeliveries отправляет накопившиеся доставки с повторами при ошибках
+	ProcessDeliveries(ctx context.Context) error
+}
+
+// BotService определяет методы Telegram бота: игра командами в чате и inline-режим
+type BotService interface {
+	// HandleUpdate обрабатывает обновление из webhook и отвечает через Bot API
+	HandleUpdate(ctx context.Context, update *BotUpdate) error
+	// RegisterWebhook регистрирует адрес webhook и секрет, который Bot API передаёт в заголовке
+	RegisterWebhook(ctx context.Context, url, secret string) error
+}
+
+// LeaderboardService определяет методы для работы с таблицами лидеров
+type LeaderboardService interface {
+	// GetLeaderboard возвращает таблицу лидеров за текущий период (daily, weekly, monthly, all_time)
+	GetLeaderboard(ctx context.Context, period, metric, currency string, limit int) (*Leaderboard, error)
+	// SetOptOut скрывает пользователя из публичных рейтингов или возвращает его в них
+	SetOptOut(ctx context.Context, userID uint64, optOut bool) error
+	IsOptedOut(ctx context.Context, userID uint64) (bool, error)
+	// ProcessSeasonPayouts выплачивает призы победителям завершившихся периодов
+	ProcessSeasonPayouts(ctx context.Context) error
+	GetPayouts(ctx context.Context, limit, offset int) ([]*LeaderboardPayout, error)
+}
+
+// ReferralService определяет методы для работы с реферальной программой
+type ReferralService interface {
+	// GetCode возвращает реферальный код пользователя, создавая его при первом обращении
+	GetCode(ctx context.Context, userID uint64) (string, error)
+	// ApplyStartParam записывает реферера нового пользователя по параметру startapp с реферальным кодом
+	ApplyStartParam(ctx context.Context, refereeID uint64, startParam string) error
+	// AccrueCommission начисляет рефереру долю зачисленной комиссии реферала
+	AccrueCommission(ctx context.Context, entry *CommissionEntry) error
+	// ProcessPayouts выплачивает накопленные начисления реферерам
+	ProcessPayouts(ctx context.Context) error
+	GetSummary(ctx context.Context, userID uint64, limit, offset int) (*ReferralSummary, error)
+}
+
+// JackpotService определяет методы для работы с прогрессивным джекпотом
+type JackpotService interface {
+	// Contribute отчисляет долю комиссии проигранного лобби в джекпот и возвращает отчисленную сумму
+	Contribute(ctx context.Context, lobby *Lobby, commission float64) (float64, error)
+	// AwardJackpot выплачивает джекпот, если завершённое лобби выполнило условие розыгрыша.
+	// Возвращает nil, если джекпот не выигран
+	AwardJackpot(ctx context.Context, lobby *Lobby, finalStatus string) (*JackpotEntry, error)
+	GetJackpots(ctx context.Context) ([]*Jackpot, error)
+	GetHistory(ctx context.Context, currency string, limit, offset int) ([]*JackpotEntry, error)
+	GetRule() JackpotRule
+}
+
+// DuelService определяет методы для работы с дуэлями
+type DuelService interface {
+	CreateDuel(ctx context.Context, duel *Duel, word string) error
+	GetDuel(ctx context.Context, id uuid.UUID) (*Duel, error)
+	GetDuelByInvite(ctx context.Context, startParam string) (*Duel, error)
+	GetUserDuels(ctx context.Context, userID uint64, limit, offset int) ([]*Duel, error)
+	AcceptDuel(ctx context.Context, startParam string, userID uint64, word string) (*Duel, error)
+	CancelDuel(ctx context.Context, id uuid.UUID, userID uint64) error
+	ProcessAttempt(ctx context.Context, id uuid.UUID, userID uint64, word string) (*DuelAttempt, error)
+	GetAttempts(ctx context.Context, id uuid.UUID, userID uint64) ([]*DuelAttempt, error)
+	// ProcessExpiredDuels закрывает непринятые вызовы и завершает дуэли с истёкшим временем
+	ProcessExpiredDuels(ctx context.Context) error
+}
+
 // HistoryService определяет методы для работы с историей
 type HistoryService interface {
 	CreateHistory(ctx context.Context, history *History) error
@@ -124,17 +377,27 @@ type TransactionService interface {
 	CreateTransaction(ctx context.Context, transaction *Transaction) error
 	GetTransaction(ctx context.Context, id uuid.UUID) (*Transaction, error)
 	GetTransactionByTxHash(ctx context.Context, txHash string) (*Transaction, error)
-	GetUserTransactions(ctx context.Context, userID uint64, limit, offset int) ([]*Transaction, error)
+	GetUserTransactions(ctx context.Context, userID uint64, limit int, cursor string) (*TransactionPage, error)
 	UpdateTransaction(ctx context.Context, transaction *Transaction) error
 	DeleteTransaction(ctx context.Context, id uuid.UUID) error
 	GetTransactionsByType(ctx context.Context, transactionType string, limit, offset int) ([]*Transaction, error)
 	GetUserBalance(ctx context.Context, userID uint64) (float64, error)
 	GetTransactionStats(ctx context.Context, userID uint64) (map[string]any, error)
+	GetGameTotals(ctx context.Context, gameID uuid.UUID) (map[string]float64, error)
 	
 	// Обработка транзакций
 	ProcessWithdraw(ctx context.Context, userID uint64, amount float64, currency string, toAddress string) (*Transaction, error)
 	ProcessDeposit(ctx context.Context, userID uint64, amount float64, currency string, txHash string, network string) error
 	ProcessReward(ctx context.Context, userID uint64, amount float64, currency string, gameID *uuid.UUID, lobbyID *uuid.UUID) error
+	HoldDuelStake(ctx context.Context, userID uint64, amount float64, currency string, duelID uuid.UUID) error
+	RefundDuelStake(ctx context.Context, userID uint64, amount float64, currency string, duelID uuid.UUID) error
+	PayDuelWinnings(ctx context.Context, userID uint64, amount, commission float64, currency string, duelID uuid.UUID) error
+	RefundGamePool(ctx context.Context, creatorID uint64, amount float64, currency string, gameID uuid.UUID) error
+	WithdrawGamePool(ctx context.Context, creatorID uint64, amount float64, currency string, gameID uuid.UUID) error
+	TopUpGamePool(ctx context.Context, creatorID uint64, amount float64, currency string, gameID uuid.UUID) error
+	PayJackpot(ctx context.Context, userID uint64, amount float64, currency string, gameID uuid.UUID) error
+	PayReferral(ctx context.Context, referrerID uint64, amount float64, currency string, refereeID uint64) error
+	PayLeaderboardPrize(ctx context.Context, userID uint64, amount float64, currency string, description string) error
 	ConfirmDeposit(ctx context.Context, transactionID uuid.UUID) error
 	ConfirmWithdrawal(ctx context.Context, transactionID uuid.UUID, txHash string) error
 	FailTransaction(ctx context.Context, transactionID uuid.UUID, reason string) error
@@ -144,6 +407,40 @@ type TransactionService interface {
 	ExistsByTxHash(ctx context.Context, txHash string) (bool, error)
 }
 
+// GameAccessChecker проверяет доступ пользователя к игре (приватные игры)
+type GameAccessChecker interface {
+	CheckAccess(ctx context.Context, game *Game, userID uint64, inviteToken string) error
+}
+
+// GameRiskGuard приостанавливает игру, исчерпавшую лимиты риска создателя
+type GameRiskGuard interface {
+	EnforceRiskLimits(ctx context.Context, gameID uuid.UUID) error
+}
+
+// PricingService оценивает сложность слова и справедливый мультипликатор игры
+// по сложности слова, правилам и исторической доле побед игроков
+type PricingService interface {
+	AnalyzeWord(word string) (*WordDifficulty, error)
+	EstimateOdds(ctx context.Context, game *Game) (*GameOdds, error)
+}
+
+// UserNotifier отправляет пользователю уведомление (личное сообщение от бота)
+type UserNotifier interface {
+	NotifyUser(ctx context.Context, userID uint64, text string) error
+}
+
+// BotMessenger отправляет ответы бота в чаты и на inline-запросы
+type BotMessenger interface {
+	SendMessage(ctx context.Context, chatID int64, text string) error
+	AnswerInlineQuery(ctx context.Context, queryID string, results []BotInlineResult) error
+	SetWebhook(ctx context.Context, url, secret string) error
+}
+
+// ChatMembershipChecker проверяет, состоит ли пользователь в Telegram-группе
+type ChatMembershipChecker interface {
+	IsChatMember(ctx context.Context, chatID int64, userID uint64) (bool, error)
+}
+
 // TONService определяет методы для работы с TON блокчейном
 type TONService interface {
 	// Получение информации
@@ -192,6 +489,16 @@ type AuthService interface {
 // JobService определяет методы для обработки фоновых задач
 type JobService interface {
 	ProcessExpiredLobbies(ctx context.Context) error
+	ProcessExpiredDuels(ctx context.Context) error
+	ProcessScheduledGames(ctx context.Context) error
+	ProcessAutoTopUps(ctx context.Context) error
+	ProcessOddsAdjustments(ctx context.Context) error
+	ProcessReferralPayouts(ctx context.Context) error
+	ProcessLeaderboardPayouts(ctx context.Context) error
+	ProcessReputationRefresh(ctx context.Context) error
+	ProcessNotifications(ctx context.Context) error
+	ProcessDomainEvents(ctx context.Context) error
+	ProcessWebhooks(ctx context.Context) error
 	ProcessPendingTransactions(ctx context.Context) error
 	StartJobScheduler(ctx context.Context, lobbyCheckInterval, transactionCheckInterval time.Duration)
 	RunOnce(ctx context.Context) error
@@ -205,4 +512,11 @@ type CommissionService interface {
 	GetCommissionRate() float64
 	// DeductCommission вычитает комиссию и возвращает итоговую сумму
 	DeductCommission(amount float64) (netAmount float64, commission float64)
+	// GetGameRate возвращает ставку комиссии для игры с учётом переопределений и уровня оборота создателя
+	GetGameRate(ctx context.Context, game *Game) float64
+	// BookCommission зачисляет комиссию на счёт сервиса. Повторная запись по тому же источнику не производится
+	BookCommission(ctx context.Context, entry *CommissionEntry) error
+	GetHouseAccounts(ctx context.Context) ([]*HouseAccount, error)
+	// GetReport возвращает заработанную комиссию по периодам (day, week, month) за [from, to)
+	GetReport(ctx context.Context, period string, from, to time.Time) ([]*CommissionReport, error)
 }
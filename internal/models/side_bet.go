@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы ставок зрителей
+const (
+	SideBetStatusOpen     = "open"     // Ставка ожидает завершения лобби
+	SideBetStatusWon      = "won"      // Ставка выиграла
+	SideBetStatusLost     = "lost"     // Ставка проиграла
+	SideBetStatusRefunded = "refunded" // Ставка возвращена (лобби отменено, завершилось с ошибкой или досрочной выплатой)
+)
+
+// SideBet представляет собой ставку зрителя на исход чужого лобби.
+// Зритель ставит на то, что игрок угадает слово не позднее попытки WithinTries.
+// Ставки учитываются отдельно от пула наград создателя игры
+type SideBet struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	LobbyID     uuid.UUID  `json:"lobby_id" db:"lobby_id"`
+	GameID      uuid.UUID  `json:"game_id" db:"game_id"`
+	UserID      uint64     `json:"user_id" db:"user_id"`             // Telegram ID зрителя
+	WithinTries int        `json:"within_tries" db:"within_tries"`   // Игрок угадает слово не позднее этой попытки
+	PlacedAtTry int        `json:"placed_at_try" db:"placed_at_try"` // Сколько попыток было сделано на момент ставки
+	Amount      float64    `json:"amount" db:"amount"`               // Размер ставки
+	Odds        float64    `json:"odds" db:"odds"`                   // Коэффициент (десятичный)
+	Payout      float64    `json:"payout" db:"payout"`               // Выплата (0 до расчёта и при проигрыше)
+	Currency    string     `json:"currency" db:"currency"`
+	Status      string     `json:"status" db:"status"`
+	SettledAt   *time.Time `json:"settled_at,omitempty" db:"settled_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// PotentialPayout возвращает выплату при выигрыше ставки
+func (b *SideBet) PotentialPayout() float64 {
+	return b.Amount * b.Odds
+}
+
+// SideBetAccount представляет собой счёт рынка ставок зрителей в валюте.
+// Ставки зачисляются на счёт, выигрыши и возвраты списываются с него, баланс - результат рынка
+type SideBetAccount struct {
+	Currency  string    `json:"currency" db:"currency"`
+	Balance   float64   `json:"balance" db:"balance"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SpectatorAttempt представляет собой попытку, видимую зрителю (только цвета, без букв)
+type SpectatorAttempt struct {
+	Result    []int     `json:"result"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SideBetQuote представляет собой котировку на исход "угадает не позднее N попыток"
+type SideBetQuote struct {
+	WithinTries int     `json:"within_tries"`
+	Probability float64 `json:"probability"`
+	Odds        float64 `json:"odds"`
+}
+
+// SideBetMarket представляет собой рынок ставок зрителей для лобби
+type SideBetMarket struct {
+	LobbyID       uuid.UUID          `json:"lobby_id"`
+	GameShortID   string             `json:"game_short_id"`
+	LobbyStatus   string             `json:"lobby_status"`
+	Open          bool               `json:"open"`       // Принимаются ли ставки
+	TriesUsed     int                `json:"tries_used"` // Версия котировок: ставка принимается только при том же значении
+	MaxTries      int                `json:"max_tries"`
+	WordLength    int                `json:"word_length"`
+	Currency      string             `json:"currency"`
+	MinStake      float64            `json:"min_stake"`
+	MaxStake      float64            `json:"max_stake"`
+	RemainingTime int64              `json:"remaining_time"`
+	WinRate       float64            `json:"win_rate"` // Историческая доля побед в игре
+	Board         []SpectatorAttempt `json:"board"`
+	Quotes        []SideBetQuote     `json:"quotes"`
+}
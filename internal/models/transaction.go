@@ -24,6 +24,18 @@ const (
 	TransactionTypeGameRefund    = "game_refund"    // Возврат депозита игры при закрытии
 	TransactionTypeReserve       = "reserve"        // Резервирование средств
 	TransactionTypeReleaseReserve = "release_reserve" // Освобождение резерва
+	TransactionTypeSideBet       = "side_bet"        // Ставка зрителя на лобби
+	TransactionTypeSideBetPayout = "side_bet_payout" // Выплата по ставке зрителя
+	TransactionTypeDuelStake     = "duel_stake"      // Ставка в дуэли (эскроу)
+	TransactionTypeDuelPayout    = "duel_payout"     // Выигрыш в дуэли
+	TransactionTypeDuelRefund    = "duel_refund"     // Возврат ставки дуэли
+	TransactionTypePoolWithdraw  = "pool_withdraw"   // Вывод создателем части пула активной игры
+	TransactionTypePoolTopUp     = "pool_top_up"     // Автопополнение пула игры с баланса создателя
+	TransactionTypeJackpot       = "jackpot"         // Выигрыш джекпота
+	TransactionTypeReferral      = "referral"        // Доля комиссии с приглашённых пользователей
+	TransactionTypeBonus         = "bonus"           // Зачисление на бонусный баланс (промокод или выигрыш бонусной ставки)
+	TransactionTypeBonusConversion = "bonus_conversion" // Перевод отыгранного бонуса в основной баланс
+	TransactionTypeLeaderboardPrize = "leaderboard_prize" // Приз победителю сезона таблицы лидеров
 )
 
 // Статусы транзакций
@@ -10,9 +10,14 @@ type User struct {
 	Username          string    `json:"username" db:"username"`
 	FirstName         string    `json:"first_name" db:"first_name"`
 	LastName          string    `json:"last_name" db:"last_name"`
+	LanguageCode      string    `json:"language_code" db:"language_code"`           // Язык интерфейса Telegram (IETF), используется для уведомлений
 	Wallet            string    `json:"wallet" db:"wallet"`                         // TON кошелек пользователя
 	BalanceTon        float64   `json:"balance_ton" db:"balance_ton"`               // Баланс в TON
 	BalanceUsdt       float64   `json:"balance_usdt" db:"balance_usdt"`             // Баланс в USDT
+	BonusTon          float64   `json:"bonus_ton" db:"bonus_ton"`                   // Бонусный баланс TON (не выводится до отыгрыша)
+	BonusUsdt         float64   `json:"bonus_usdt" db:"bonus_usdt"`                 // Бонусный баланс USDT (не выводится до отыгрыша)
+	WagerTon          float64   `json:"wager_ton" db:"wager_ton"`                   // Оборот ставок TON, оставшийся до перевода бонуса в основной баланс
+	WagerUsdt         float64   `json:"wager_usdt" db:"wager_usdt"`                 // Оборот ставок USDT, оставшийся до перевода бонуса в основной баланс
 	PendingWithdrawal float64   `json:"pending_withdrawal" db:"pending_withdrawal"` // Сумма в процессе вывода
 	WithdrawalLockUntil *time.Time `json:"withdrawal_lock_until,omitempty" db:"withdrawal_lock_until"` // Блокировка вывода до
 	Wins              int       `json:"wins" db:"wins"`                             // Количество побед
@@ -47,3 +52,27 @@ func (u *User) GetAvailableBalance(currency string) float64 {
 func (u *User) HasSufficientBalance(amount float64, currency string) bool {
 	return u.GetAvailableBalance(currency) >= amount
 }
+
+// GetBonusBalance возвращает бонусный баланс в валюте
+func (u *User) GetBonusBalance(currency string) float64 {
+	switch currency {
+	case CurrencyTON:
+		return u.BonusTon
+	case CurrencyUSDT:
+		return u.BonusUsdt
+	default:
+		return 0
+	}
+}
+
+// GetWagerRemaining возвращает оборот ставок, оставшийся до перевода бонуса в основной баланс
+func (u *User) GetWagerRemaining(currency string) float64 {
+	switch currency {
+	case CurrencyTON:
+		return u.WagerTon
+	case CurrencyUSDT:
+		return u.WagerUsdt
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventTest тип тестового события, которое владелец отправляет на свой webhook вручную
+const WebhookEventTest = "webhook_test"
+
+// WebhookEventTypes типы доменных событий, на которые можно подписать webhook
+var WebhookEventTypes = []string{
+	EventGameActivated,
+	EventLobbyFinished,
+	EventDepositCredited,
+	EventWithdrawalRequested,
+	EventWithdrawalSent,
+}
+
+// Статусы доставок webhook
+const (
+	WebhookDeliveryPending   = "pending"   // Ожидает отправки или повторной попытки
+	WebhookDeliveryDelivered = "delivered" // Получатель ответил кодом 2xx
+	WebhookDeliveryFailed    = "failed"    // Попытки исчерпаны
+)
+
+// Ошибки webhook
+var (
+	ErrWebhookNotFound          = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound  = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL        = errors.New("webhook url must be an absolute http or https url with a public host")
+	ErrInvalidWebhookEventType  = errors.New("invalid webhook event type")
+	ErrInvalidWebhookSecret     = errors.New("webhook secret is too short")
+	ErrWebhookLimitReached      = errors.New("webhook limit reached")
+	ErrWebhookEventTypesMissing = errors.New("at least one webhook event type is required")
+)
+
+// Webhook представляет собой подписку партнёра или создателя на доменные события.
+// Webhook получает события, в которых участвует его владелец: активацию своих игр, лобби в своих играх
+// или своей игры, свои депозиты и выводы
+type Webhook struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	OwnerID    uint64    `json:"owner_id" db:"owner_id"`
+	URL        string    `json:"url" db:"url"`
+	Secret     string    `json:"secret,omitempty" db:"secret"` // Ключ подписи HMAC, возвращается только при создании
+	EventTypes []string  `json:"event_types" db:"event_types"`
+	Active     bool      `json:"active" db:"active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes проверяет, подписан ли webhook на тип события
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookUpdate изменения webhook. Пустые поля не меняются
+type WebhookUpdate struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookDelivery представляет собой отправку события на webhook и журнал попыток её доставки
+type WebhookDelivery struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	WebhookID     uuid.UUID       `json:"webhook_id" db:"webhook_id"`
+	EventID       uuid.UUID       `json:"event_id" db:"event_id"` // Доменное событие; повторная отправка сохраняет его, чтобы получатель мог отбросить дубль
+	EventType     string          `json:"event_type" db:"event_type"`
+	DedupKey      string          `json:"-" db:"dedup_key"` // Ключ события: повторное событие не создаёт доставку (пусто - без проверки)
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	Status        string          `json:"status" db:"status"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	ResponseCode  int             `json:"response_code,omitempty" db:"response_code"` // HTTP-код ответа последней попытки (0 - ответа не было)
+	LastError     string          `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// WebhookEnvelope тело запроса на webhook
+type WebhookEnvelope struct {
+	ID        uuid.UUID       `json:"id"` // Идентификатор события, одинаковый при повторных отправках
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookRule параметры доставки webhook
+type WebhookRule struct {
+	MaxAttempts int           // Попыток отправки до статуса failed
+	RetryDelay  time.Duration // Задержка перед первой повторной попыткой, далее удваивается
+	BatchSize   int           // Доставок за один запуск фоновой задачи
+	Timeout     time.Duration // Время ожидания ответа получателя
+	MaxPerOwner int           // Не больше стольких webhook у одного пользователя
+}
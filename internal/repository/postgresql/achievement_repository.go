@@ -0,0 +1,190 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+const streakColumns = `user_id, games_played, win_streak, best_win_streak, play_streak, best_play_streak,
+	last_played_on, login_streak, best_login_streak, last_login_on, updated_at`
+
+// AchievementRepository представляет собой реализацию репозитория для работы с достижениями и сериями
+type AchievementRepository struct {
+	db *sql.DB
+}
+
+// NewAchievementRepository создает новый экземпляр AchievementRepository
+func NewAchievementRepository(db *sql.DB) *AchievementRepository {
+	return &AchievementRepository{
+		db: db,
+	}
+}
+
+// GetStreak возвращает серии пользователя (пустые, если пользователь ещё не играл)
+func (r *AchievementRepository) GetStreak(ctx context.Context, userID uint64) (*models.UserStreak, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+streakColumns+` FROM user_streaks WHERE user_id = $1`, userID)
+	streak, err := scanStreak(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.UserStreak{UserID: userID}, nil
+		}
+		return nil, fmt.Errorf("failed to get streak: %w", err)
+	}
+	return streak, nil
+}
+
+// RecordPlay атомарно учитывает рассчитанное лобби в сериях пользователя
+func (r *AchievementRepository) RecordPlay(ctx context.Context, userID uint64, status string, at time.Time) (*models.UserStreak, error) {
+	streak, _, err := r.updateStreak(ctx, userID, func(streak *models.UserStreak) bool {
+		streak.RecordPlay(status, at)
+		return true
+	})
+	return streak, err
+}
+
+// RecordLogin атомарно учитывает ежедневный вход. Возвращает false, если вход в этот день уже учтён
+func (r *AchievementRepository) RecordLogin(ctx context.Context, userID uint64, at time.Time) (*models.UserStreak, bool, error) {
+	return r.updateStreak(ctx, userID, func(streak *models.UserStreak) bool {
+		return streak.RecordLogin(at)
+	})
+}
+
+// updateStreak блокирует строку серий пользователя, применяет к ней apply и сохраняет результат,
+// если apply вернул true. Если транзакция уже открыта, серии обновляются в ней
+func (r *AchievementRepository) updateStreak(ctx context.Context, userID uint64, apply func(*models.UserStreak) bool) (*models.UserStreak, bool, error) {
+	var (
+		streak  *models.UserStreak
+		updated bool
+	)
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO user_streaks (user_id, updated_at) VALUES ($1, $2)
+			ON CONFLICT (user_id) DO NOTHING
+		`, userID, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to create streak: %w", err)
+		}
+
+		row := tx.QueryRowContext(ctx, `SELECT `+streakColumns+` FROM user_streaks WHERE user_id = $1 FOR UPDATE`, userID)
+		streak, err = scanStreak(row)
+		if err != nil {
+			return fmt.Errorf("failed to get streak: %w", err)
+		}
+
+		if !apply(streak) {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE user_streaks
+			SET games_played = $1, win_streak = $2, best_win_streak = $3, play_streak = $4, best_play_streak = $5,
+				last_played_on = $6, login_streak = $7, best_login_streak = $8, last_login_on = $9, updated_at = $10
+			WHERE user_id = $11
+		`, streak.GamesPlayed, streak.WinStreak, streak.BestWinStreak, streak.PlayStreak, streak.BestPlayStreak,
+			streak.LastPlayedOn, streak.LoginStreak, streak.BestLoginStreak, streak.LastLoginOn, streak.UpdatedAt, userID)
+		if err != nil {
+			return fmt.Errorf("failed to update streak: %w", err)
+		}
+
+		updated = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return streak, updated, nil
+}
+
+// Award выдаёт достижение. Возвращает false, если достижение уже было выдано
+func (r *AchievementRepository) Award(ctx context.Context, achievement *models.UserAchievement) (bool, error) {
+	if achievement.AwardedAt.IsZero() {
+		achievement.AwardedAt = time.Now()
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO user_achievements (user_id, code, reward, currency, awarded_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, code) DO NOTHING
+	`, achievement.UserID, achievement.Code, achievement.Reward, achievement.Currency, achievement.AwardedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to award achievement: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetByUser возвращает достижения пользователя в порядке получения
+func (r *AchievementRepository) GetByUser(ctx context.Context, userID uint64) ([]*models.UserAchievement, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, code, reward, currency, awarded_at
+		FROM user_achievements
+		WHERE user_id = $1
+		ORDER BY awarded_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievements: %w", err)
+	}
+	defer rows.Close()
+
+	var achievements []*models.UserAchievement
+	for rows.Next() {
+		var achievement models.UserAchievement
+		if err := rows.Scan(&achievement.UserID, &achievement.Code, &achievement.Reward, &achievement.Currency,
+			&achievement.AwardedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement: %w", err)
+		}
+		achievements = append(achievements, &achievement)
+	}
+
+	return achievements, rows.Err()
+}
+
+// AddGamePlayer запоминает игрока игры и возвращает число разных игроков игры
+func (r *AchievementRepository) AddGamePlayer(ctx context.Context, gameID uuid.UUID, userID uint64) (int, error) {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO game_players (game_id, user_id, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (game_id, user_id) DO NOTHING
+	`, gameID, userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to add game player: %w", err)
+	}
+
+	var players int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM game_players WHERE game_id = $1`, gameID).Scan(&players); err != nil {
+		return 0, fmt.Errorf("failed to count game players: %w", err)
+	}
+
+	return players, nil
+}
+
+// scanStreak считывает серии пользователя из строки результата
+func scanStreak(row interface{ Scan(dest ...any) error }) (*models.UserStreak, error) {
+	var streak models.UserStreak
+	var lastPlayedOn, lastLoginOn sql.NullTime
+	err := row.Scan(&streak.UserID, &streak.GamesPlayed, &streak.WinStreak, &streak.BestWinStreak, &streak.PlayStreak,
+		&streak.BestPlayStreak, &lastPlayedOn, &streak.LoginStreak, &streak.BestLoginStreak, &lastLoginOn, &streak.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if lastPlayedOn.Valid {
+		streak.LastPlayedOn = &lastPlayedOn.Time
+	}
+	if lastLoginOn.Valid {
+		streak.LastLoginOn = &lastLoginOn.Time
+	}
+	return &streak, nil
+}
@@ -0,0 +1,278 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// AnalyticsRepository представляет собой реализацию репозитория для аналитики создателей
+type AnalyticsRepository struct {
+	db *sql.DB
+}
+
+// NewAnalyticsRepository создает новый экземпляр AnalyticsRepository
+func NewAnalyticsRepository(db *sql.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		db: db,
+	}
+}
+
+// analyticsGameID возвращает параметр фильтра по игре (nil - все игры создателя)
+func analyticsGameID(query *models.AnalyticsQuery) interface{} {
+	if query.GameID == nil {
+		return nil
+	}
+	return *query.GameID
+}
+
+// bucketKey возвращает ключ периода и валюты
+func bucketKey(periodStart time.Time, currency string) string {
+	return periodStart.Format(time.DateOnly) + ":" + currency
+}
+
+// GetBuckets возвращает показатели игр по периодам и валютам.
+// Лобби берутся из истории, комиссия - из журнала комиссии, движение пула - из транзакций игр
+func (r *AnalyticsRepository) GetBuckets(ctx context.Context, query *models.AnalyticsQuery) ([]*models.AnalyticsBucket, error) {
+	buckets := make(map[string]*models.AnalyticsBucket)
+	var result []*models.AnalyticsBucket
+	bucket := func(periodStart time.Time, currency string) *models.AnalyticsBucket {
+		periodStart = time.Date(periodStart.Year(), periodStart.Month(), periodStart.Day(), 0, 0, 0, 0, time.UTC)
+		key := bucketKey(periodStart, currency)
+		b, ok := buckets[key]
+		if !ok {
+			b = &models.AnalyticsBucket{PeriodStart: periodStart, Currency: currency}
+			buckets[key] = b
+			result = append(result, b)
+		}
+		return b
+	}
+	args := []interface{}{query.Period, query.CreatorID, analyticsGameID(query), query.From, query.To}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT date_trunc($1, h.created_at AT TIME ZONE 'UTC') AS period_start, h.currency,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE h.status = $6),
+			COUNT(*) FILTER (WHERE h.status = $7),
+			COALESCE(SUM(h.bet_amount), 0),
+			COALESCE(SUM(h.reward) FILTER (WHERE h.status <> $8), 0),
+			COALESCE(SUM(h.bet_amount) FILTER (WHERE h.status = $8), 0)
+		FROM history h
+		JOIN games g ON g.id = h.game_id
+		WHERE g.creator_id = $2 AND ($3::uuid IS NULL OR g.id = $3)
+			AND h.created_at >= $4 AND h.created_at < $5
+		GROUP BY period_start, h.currency
+	`, append(args, models.HistoryStatusPlayerWin, models.HistoryStatusCashOut, models.HistoryStatusCreatorWin)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lobby analytics: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var periodStart time.Time
+		var currency string
+		var row models.AnalyticsBucket
+		if err := rows.Scan(&periodStart, &currency, &row.Lobbies, &row.Wins, &row.CashOuts, &row.GrossBets,
+			&row.Payouts, &row.LostBets); err != nil {
+			return nil, fmt.Errorf("failed to scan lobby analytics: %w", err)
+		}
+		b := bucket(periodStart, currency)
+		b.Lobbies, b.Wins, b.CashOuts = row.Lobbies, row.Wins, row.CashOuts
+		b.GrossBets, b.Payouts, b.LostBets = row.GrossBets, row.Payouts, row.LostBets
+		b.NetPnL += row.LostBets - row.Payouts
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Из проигранной ставки в пул попадает сумма за вычетом полной комиссии (включая долю джекпота)
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT date_trunc($1, c.created_at AT TIME ZONE 'UTC') AS period_start, c.currency,
+			COALESCE(SUM(c.amount), 0),
+			COALESCE(SUM(c.volume * c.rate) FILTER (WHERE c.source = $6), 0)
+		FROM commission_entries c
+		JOIN games g ON g.id = c.game_id
+		WHERE g.creator_id = $2 AND ($3::uuid IS NULL OR g.id = $3)
+			AND c.created_at >= $4 AND c.created_at < $5
+		GROUP BY period_start, c.currency
+	`, append(args, models.CommissionSourceLobbyLoss)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commission analytics: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var periodStart time.Time
+		var currency string
+		var commission, lossCommission float64
+		if err := rows.Scan(&periodStart, &currency, &commission, &lossCommission); err != nil {
+			return nil, fmt.Errorf("failed to scan commission analytics: %w", err)
+		}
+		b := bucket(periodStart, currency)
+		b.Commission = commission
+		b.NetPnL -= lossCommission
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT date_trunc($1, t.created_at AT TIME ZONE 'UTC') AS period_start, t.currency,
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type IN ($6, $7)), 0),
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type IN ($8, $9)), 0)
+		FROM transactions t
+		JOIN games g ON g.id = t.game_id
+		WHERE g.creator_id = $2 AND ($3::uuid IS NULL OR g.id = $3)
+			AND t.created_at >= $4 AND t.created_at < $5
+			AND t.status = 'completed' AND t.type IN ($6, $7, $8, $9)
+		GROUP BY period_start, t.currency
+	`, append(args, models.TransactionTypeGameDeposit, models.TransactionTypePoolTopUp,
+		models.TransactionTypePoolWithdraw, models.TransactionTypeGameRefund)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool analytics: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var periodStart time.Time
+		var currency string
+		var poolIn, poolOut float64
+		if err := rows.Scan(&periodStart, &currency, &poolIn, &poolOut); err != nil {
+			return nil, fmt.Errorf("failed to scan pool analytics: %w", err)
+		}
+		b := bucket(periodStart, currency)
+		b.PoolIn, b.PoolOut = poolIn, poolOut
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sortAnalyticsBuckets(result)
+	return result, nil
+}
+
+// GetTriesHistogram возвращает число побед в играх создателя по количеству попыток
+func (r *AnalyticsRepository) GetTriesHistogram(ctx context.Context, query *models.AnalyticsQuery) (map[int]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT h.tries_used, COUNT(*)
+		FROM history h
+		JOIN games g ON g.id = h.game_id
+		WHERE g.creator_id = $1 AND ($2::uuid IS NULL OR g.id = $2)
+			AND h.created_at >= $3 AND h.created_at < $4
+			AND h.status = $5 AND h.tries_used > 0
+		GROUP BY h.tries_used
+	`, query.CreatorID, analyticsGameID(query), query.From, query.To, models.HistoryStatusPlayerWin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tries histogram: %w", err)
+	}
+	defer rows.Close()
+
+	histogram := make(map[int]int)
+	for rows.Next() {
+		var tries, count int
+		if err := rows.Scan(&tries, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan tries histogram: %w", err)
+		}
+		histogram[tries] = count
+	}
+
+	return histogram, rows.Err()
+}
+
+// GetGameSummaries возвращает итоги по каждой игре создателя, в которой были лобби, в порядке числа лобби
+func (r *AnalyticsRepository) GetGameSummaries(ctx context.Context, query *models.AnalyticsQuery) ([]*models.AnalyticsGameSummary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH h AS (
+			SELECT h.game_id,
+				COUNT(*) AS lobbies,
+				COUNT(*) FILTER (WHERE h.status = $5) AS wins,
+				COUNT(*) FILTER (WHERE h.status = $6) AS cash_outs,
+				COALESCE(SUM(h.bet_amount), 0) AS gross_bets,
+				COALESCE(SUM(h.reward) FILTER (WHERE h.status <> $7), 0) AS payouts,
+				COALESCE(SUM(h.bet_amount) FILTER (WHERE h.status = $7), 0) AS lost_bets
+			FROM history h
+			JOIN games g ON g.id = h.game_id
+			WHERE g.creator_id = $1 AND ($2::uuid IS NULL OR g.id = $2)
+				AND h.created_at >= $3 AND h.created_at < $4
+			GROUP BY h.game_id
+		), c AS (
+			SELECT c.game_id,
+				COALESCE(SUM(c.amount), 0) AS commission,
+				COALESCE(SUM(c.volume * c.rate) FILTER (WHERE c.source = $8), 0) AS loss_commission
+			FROM commission_entries c
+			WHERE c.game_id IN (SELECT game_id FROM h)
+				AND c.created_at >= $3 AND c.created_at < $4
+			GROUP BY c.game_id
+		)
+		SELECT g.id, g.short_id, g.title, g.status, g.currency,
+			h.lobbies, h.wins, h.cash_outs, h.gross_bets, h.payouts,
+			COALESCE(c.commission, 0),
+			h.lost_bets - COALESCE(c.loss_commission, 0) - h.payouts
+		FROM h
+		JOIN games g ON g.id = h.game_id
+		LEFT JOIN c ON c.game_id = h.game_id
+		ORDER BY h.lobbies DESC, g.created_at DESC
+	`, query.CreatorID, analyticsGameID(query), query.From, query.To,
+		models.HistoryStatusPlayerWin, models.HistoryStatusCashOut, models.HistoryStatusCreatorWin,
+		models.CommissionSourceLobbyLoss)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*models.AnalyticsGameSummary
+	for rows.Next() {
+		var summary models.AnalyticsGameSummary
+		if err := rows.Scan(&summary.GameID, &summary.ShortID, &summary.Title, &summary.Status, &summary.Currency,
+			&summary.Lobbies, &summary.Wins, &summary.CashOuts, &summary.GrossBets, &summary.Payouts,
+			&summary.Commission, &summary.NetPnL); err != nil {
+			return nil, fmt.Errorf("failed to scan game summary: %w", err)
+		}
+		summary.Losses = summary.Lobbies - summary.Wins - summary.CashOuts
+		if summary.Lobbies > 0 {
+			summary.WinRate = float64(summary.Wins) / float64(summary.Lobbies)
+		}
+		summaries = append(summaries, &summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// GetTopGuesses возвращает самые частые попытки в игре, кроме exclude
+func (r *AnalyticsRepository) GetTopGuesses(ctx context.Context, gameID uuid.UUID, from, to time.Time, exclude string, limit int) ([]models.WordCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT LOWER(word) AS guess, COUNT(*) AS total
+		FROM attempts
+		WHERE game_id = $1 AND created_at >= $2 AND created_at < $3 AND LOWER(word) <> LOWER($4)
+		GROUP BY guess
+		ORDER BY total DESC, guess
+		LIMIT $5
+	`, gameID, from, to, exclude, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top guesses: %w", err)
+	}
+	defer rows.Close()
+
+	var guesses []models.WordCount
+	for rows.Next() {
+		var guess models.WordCount
+		if err := rows.Scan(&guess.Word, &guess.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan guess: %w", err)
+		}
+		guesses = append(guesses, guess)
+	}
+
+	return guesses, rows.Err()
+}
+
+// sortAnalyticsBuckets упорядочивает периоды по времени и валюте
+func sortAnalyticsBuckets(buckets []*models.AnalyticsBucket) {
+	sort.Slice(buckets, func(i, j int) bool {
+		if !buckets[i].PeriodStart.Equal(buckets[j].PeriodStart) {
+			return buckets[i].PeriodStart.Before(buckets[j].PeriodStart)
+		}
+		return buckets[i].Currency < buckets[j].Currency
+	})
+}
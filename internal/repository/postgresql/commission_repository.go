@@ -0,0 +1,138 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// CommissionRepository представляет собой реализацию репозитория для работы с комиссией
+type CommissionRepository struct {
+	db *sql.DB
+}
+
+// NewCommissionRepository создает новый экземпляр CommissionRepository
+func NewCommissionRepository(db *sql.DB) *CommissionRepository {
+	return &CommissionRepository{
+		db: db,
+	}
+}
+
+// Book записывает комиссию и зачисляет её на счёт сервиса в одной транзакции (или в уже открытой).
+// Уникальный индекс (reference_id, source) гарантирует, что комиссия по источнику зачисляется не более одного раза
+func (r *CommissionRepository) Book(ctx context.Context, entry *models.CommissionEntry) (bool, error) {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	entry.CreatedAt = time.Now()
+
+	var booked bool
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		result, err := conn(ctx, r.db).ExecContext(ctx, `
+			INSERT INTO commission_entries (id, source, reference_id, game_id, creator_id, user_id, currency, volume, rate, amount, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (reference_id, source) DO NOTHING
+		`, entry.ID, entry.Source, entry.ReferenceID, entry.GameID, entry.CreatorID, entry.UserID,
+			entry.Currency, entry.Volume, entry.Rate, entry.Amount, entry.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create commission entry: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			// Комиссия по источнику уже зачислена
+			return nil
+		}
+
+		_, err = conn(ctx, r.db).ExecContext(ctx, `
+			INSERT INTO house_accounts (currency, balance, updated_at) VALUES ($1, $2, $3)
+			ON CONFLICT (currency) DO UPDATE
+			SET balance = house_accounts.balance + EXCLUDED.balance, updated_at = EXCLUDED.updated_at
+		`, entry.Currency, entry.Amount, entry.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to credit house account: %w", err)
+		}
+
+		booked = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return booked, nil
+}
+
+// GetHouseAccounts возвращает счета сервиса во всех валютах
+func (r *CommissionRepository) GetHouseAccounts(ctx context.Context) ([]*models.HouseAccount, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT currency, balance, updated_at FROM house_accounts ORDER BY currency`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get house accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*models.HouseAccount
+	for rows.Next() {
+		var account models.HouseAccount
+		if err := rows.Scan(&account.Currency, &account.Balance, &account.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan house account: %w", err)
+		}
+		accounts = append(accounts, &account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// GetCreatorVolume возвращает оборот ставок в играх создателя в валюте начиная с since
+func (r *CommissionRepository) GetCreatorVolume(ctx context.Context, creatorID uint64, currency string, since time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(volume), 0)
+		FROM commission_entries
+		WHERE creator_id = $1 AND currency = $2 AND created_at >= $3
+			AND source IN ('lobby_loss', 'lobby_win')
+	`
+
+	var volume float64
+	if err := r.db.QueryRowContext(ctx, query, creatorID, currency, since).Scan(&volume); err != nil {
+		return 0, fmt.Errorf("failed to get creator volume: %w", err)
+	}
+
+	return volume, nil
+}
+
+// GetReport возвращает комиссию за [from, to), сгруппированную по периодам (по UTC), валютам и источникам
+func (r *CommissionRepository) GetReport(ctx context.Context, period string, from, to time.Time) ([]*models.CommissionReport, error) {
+	query := `
+		SELECT date_trunc($1, created_at AT TIME ZONE 'UTC') AS period_start,
+			currency, source, COUNT(*), COALESCE(SUM(volume), 0), COALESCE(SUM(amount), 0)
+		FROM commission_entries
+		WHERE created_at >= $2 AND created_at < $3
+		GROUP BY period_start, currency, source
+		ORDER BY period_start, currency, source
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, period, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commission report: %w", err)
+	}
+	defer rows.Close()
+
+	var report []*models.CommissionReport
+	for rows.Next() {
+		var row models.CommissionReport
+		if err := rows.Scan(&row.PeriodStart, &row.Currency, &row.Source, &row.Count, &row.Volume, &row.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan commission report: %w", err)
+		}
+		row.PeriodStart = time.Date(row.PeriodStart.Year(), row.PeriodStart.Month(), row.PeriodStart.Day(), 0, 0, 0, 0, time.UTC)
+		report = append(report, &row)
+	}
+
+	return report, rows.Err()
+}
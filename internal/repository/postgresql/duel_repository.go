@@ -0,0 +1,441 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+const duelColumns = `id, short_id, challenger_id, opponent_id, stake, currency, word_length, max_tries,
+			time_limit, challenger_word, opponent_word, challenger_tries, challenger_solved,
+			challenger_finished_at, opponent_tries, opponent_solved, opponent_finished_at,
+			winner_id, payout, commission, status, invite_expires_at, started_at, ends_at,
+			settled_at, created_at, updated_at`
+
+// DuelRepository представляет собой реализацию репозитория для работы с дуэлями
+type DuelRepository struct {
+	db *sql.DB
+}
+
+// NewDuelRepository создает новый экземпляр DuelRepository
+func NewDuelRepository(db *sql.DB) *DuelRepository {
+	return &DuelRepository{
+		db: db,
+	}
+}
+
+// nullableUserID преобразует Telegram ID в NULL, если он не задан
+func nullableUserID(id uint64) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// scanDuel считывает дуэль из строки результата
+func scanDuel(row rowScanner) (*models.Duel, error) {
+	var duel models.Duel
+	var opponentID, winnerID sql.NullInt64
+	var opponentWord sql.NullString
+	var challengerFinishedAt, opponentFinishedAt, startedAt, endsAt, settledAt sql.NullTime
+
+	err := row.Scan(
+		&duel.ID,
+		&duel.ShortID,
+		&duel.ChallengerID,
+		&opponentID,
+		&duel.Stake,
+		&duel.Currency,
+		&duel.WordLength,
+		&duel.MaxTries,
+		&duel.TimeLimit,
+		&duel.ChallengerWord,
+		&opponentWord,
+		&duel.ChallengerTries,
+		&duel.ChallengerSolved,
+		&challengerFinishedAt,
+		&duel.OpponentTries,
+		&duel.OpponentSolved,
+		&opponentFinishedAt,
+		&winnerID,
+		&duel.Payout,
+		&duel.Commission,
+		&duel.Status,
+		&duel.InviteExpiresAt,
+		&startedAt,
+		&endsAt,
+		&settledAt,
+		&duel.CreatedAt,
+		&duel.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if opponentID.Valid {
+		duel.OpponentID = uint64(opponentID.Int64)
+	}
+	if winnerID.Valid {
+		winner := uint64(winnerID.Int64)
+		duel.WinnerID = &winner
+	}
+	duel.OpponentWord = opponentWord.String
+	if challengerFinishedAt.Valid {
+		duel.ChallengerFinishedAt = &challengerFinishedAt.Time
+	}
+	if opponentFinishedAt.Valid {
+		duel.OpponentFinishedAt = &opponentFinishedAt.Time
+	}
+	if startedAt.Valid {
+		duel.StartedAt = &startedAt.Time
+	}
+	if endsAt.Valid {
+		duel.EndsAt = &endsAt.Time
+	}
+	if settledAt.Valid {
+		duel.SettledAt = &settledAt.Time
+	}
+
+	return &duel, nil
+}
+
+// queryDuels выполняет запрос и считывает список дуэлей
+func (r *DuelRepository) queryDuels(ctx context.Context, query string, args ...any) ([]*models.Duel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duels: %w", err)
+	}
+	defer rows.Close()
+
+	var duels []*models.Duel
+	for rows.Next() {
+		duel, err := scanDuel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan duel: %w", err)
+		}
+		duels = append(duels, duel)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duels: %w", err)
+	}
+
+	return duels, nil
+}
+
+// getOne выполняет запрос и считывает одну дуэль
+func (r *DuelRepository) getOne(ctx context.Context, query string, args ...any) (*models.Duel, error) {
+	duel, err := scanDuel(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrDuelNotFound
+		}
+		return nil, fmt.Errorf("failed to get duel: %w", err)
+	}
+	return duel, nil
+}
+
+// Create создает новую дуэль
+func (r *DuelRepository) Create(ctx context.Context, duel *models.Duel) error {
+	query := `
+		INSERT INTO duels (id, short_id, challenger_id, opponent_id, stake, currency, word_length,
+			max_tries, time_limit, challenger_word, status, invite_expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+
+	if duel.ID == uuid.Nil {
+		duel.ID = uuid.New()
+	}
+
+	now := time.Now()
+	duel.CreatedAt = now
+	duel.UpdatedAt = now
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		duel.ID,
+		duel.ShortID,
+		duel.ChallengerID,
+		nullableUserID(duel.OpponentID),
+		duel.Stake,
+		duel.Currency,
+		duel.WordLength,
+		duel.MaxTries,
+		duel.TimeLimit,
+		duel.ChallengerWord,
+		duel.Status,
+		duel.InviteExpiresAt,
+		duel.CreatedAt,
+		duel.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create duel: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает дуэль по ID
+func (r *DuelRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Duel, error) {
+	return r.getOne(ctx, `SELECT `+duelColumns+` FROM duels WHERE id = $1`, id)
+}
+
+// GetByShortID получает дуэль по коду приглашения
+func (r *DuelRepository) GetByShortID(ctx context.Context, shortID string) (*models.Duel, error) {
+	return r.getOne(ctx, `SELECT `+duelColumns+` FROM duels WHERE short_id = $1`, shortID)
+}
+
+// GetByUserID получает дуэли пользователя с пагинацией
+func (r *DuelRepository) GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*models.Duel, error) {
+	query := `
+		SELECT ` + duelColumns + `
+		FROM duels
+		WHERE challenger_id = $1 OR opponent_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryDuels(ctx, query, userID, limit, offset)
+}
+
+// GetExpiredInvites получает вызовы, которые не были приняты вовремя
+func (r *DuelRepository) GetExpiredInvites(ctx context.Context) ([]*models.Duel, error) {
+	query := `
+		SELECT ` + duelColumns + `
+		FROM duels
+		WHERE status = $1 AND invite_expires_at < $2
+	`
+
+	return r.queryDuels(ctx, query, models.DuelStatusPending, time.Now())
+}
+
+// GetExpiredActive получает активные дуэли с истёкшим временем игры
+func (r *DuelRepository) GetExpiredActive(ctx context.Context) ([]*models.Duel, error) {
+	query := `
+		SELECT ` + duelColumns + `
+		FROM duels
+		WHERE status = $1 AND ends_at < $2
+	`
+
+	return r.queryDuels(ctx, query, models.DuelStatusActive, time.Now())
+}
+
+// Accept атомарно принимает ожидающий вызов
+func (r *DuelRepository) Accept(ctx context.Context, duel *models.Duel) (bool, error) {
+	query := `
+		UPDATE duels
+		SET opponent_id = $1, opponent_word = $2, status = $3, started_at = $4, ends_at = $5, updated_at = $6
+		WHERE id = $7 AND status = $8 AND (opponent_id IS NULL OR opponent_id = $1)
+	`
+
+	duel.UpdatedAt = time.Now()
+
+	result, err := r.db.ExecContext(ctx, query,
+		duel.OpponentID,
+		duel.OpponentWord,
+		models.DuelStatusActive,
+		duel.StartedAt,
+		duel.EndsAt,
+		duel.UpdatedAt,
+		duel.ID,
+		models.DuelStatusPending,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to accept duel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// TransitionStatus атомарно меняет статус дуэли
+func (r *DuelRepository) TransitionStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus string) (bool, error) {
+	query := `
+		UPDATE duels
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, toStatus, time.Now(), id, fromStatus)
+	if err != nil {
+		return false, fmt.Errorf("failed to transition duel status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RecordAttempt атомарно сохраняет попытку и прогресс игрока. Прогресс обновляется условно по числу попыток
+// (как TransitionStatus по статусу): из двух параллельных попыток с одним prevTries засчитывается одна
+func (r *DuelRepository) RecordAttempt(ctx context.Context, duel *models.Duel, userID uint64, prevTries int, attempt *models.DuelAttempt) (bool, error) {
+	query := `
+		UPDATE duels
+		SET challenger_tries = $1, challenger_solved = $2, challenger_finished_at = $3, updated_at = $4
+		WHERE id = $5 AND status = $6 AND challenger_tries = $7 AND challenger_finished_at IS NULL
+	`
+	if userID != duel.ChallengerID {
+		query = `
+			UPDATE duels
+			SET opponent_tries = $1, opponent_solved = $2, opponent_finished_at = $3, updated_at = $4
+			WHERE id = $5 AND status = $6 AND opponent_tries = $7 AND opponent_finished_at IS NULL
+		`
+	}
+
+	tries, solved, finishedAt := duel.PlayerState(userID)
+	duel.UpdatedAt = time.Now()
+
+	var recorded bool
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		result, err := conn(ctx, r.db).ExecContext(ctx, query, tries, solved, finishedAt, duel.UpdatedAt, duel.ID,
+			models.DuelStatusActive, prevTries)
+		if err != nil {
+			return fmt.Errorf("failed to update duel player state: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return nil
+		}
+
+		recorded = true
+		return r.createAttempt(ctx, attempt)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return recorded, nil
+}
+
+// Settle атомарно завершает активную дуэль
+func (r *DuelRepository) Settle(ctx context.Context, duel *models.Duel) (bool, error) {
+	query := `
+		UPDATE duels
+		SET status = $1, winner_id = $2, payout = $3, commission = $4, settled_at = $5, updated_at = $5
+		WHERE id = $6 AND status = $7
+	`
+
+	var winnerID sql.NullInt64
+	if duel.WinnerID != nil {
+		winnerID = nullableUserID(*duel.WinnerID)
+	}
+
+	now := time.Now()
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		duel.Status,
+		winnerID,
+		duel.Payout,
+		duel.Commission,
+		now,
+		duel.ID,
+		models.DuelStatusActive,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to settle duel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		duel.SettledAt = &now
+		duel.UpdatedAt = now
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// createAttempt сохраняет попытку игрока (внутри RecordAttempt)
+func (r *DuelRepository) createAttempt(ctx context.Context, attempt *models.DuelAttempt) error {
+	query := `
+		INSERT INTO duel_attempts (id, duel_id, user_id, word, result, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if attempt.ID == uuid.Nil {
+		attempt.ID = uuid.New()
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = time.Now()
+	}
+
+	resultJSON, err := json.Marshal(attempt.Result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal attempt result: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		attempt.ID,
+		attempt.DuelID,
+		attempt.UserID,
+		attempt.Word,
+		resultJSON,
+		attempt.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create duel attempt: %w", err)
+	}
+
+	return nil
+}
+
+// GetAttempts получает попытки игрока в дуэли
+func (r *DuelRepository) GetAttempts(ctx context.Context, duelID uuid.UUID, userID uint64) ([]*models.DuelAttempt, error) {
+	query := `
+		SELECT id, duel_id, user_id, word, result, created_at
+		FROM duel_attempts
+		WHERE duel_id = $1 AND user_id = $2
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, duelID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duel attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []*models.DuelAttempt
+	for rows.Next() {
+		var attempt models.DuelAttempt
+		var resultJSON []byte
+
+		if err := rows.Scan(
+			&attempt.ID,
+			&attempt.DuelID,
+			&attempt.UserID,
+			&attempt.Word,
+			&resultJSON,
+			&attempt.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan duel attempt: %w", err)
+		}
+
+		if err := json.Unmarshal(resultJSON, &attempt.Result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal attempt result: %w", err)
+		}
+
+		attempts = append(attempts, &attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duel attempts: %w", err)
+	}
+
+	return attempts, nil
+}
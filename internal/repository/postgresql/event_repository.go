@@ -0,0 +1,176 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// EventRepository представляет собой реализацию репозитория для работы с outbox доменных событий
+type EventRepository struct {
+	db *sql.DB
+}
+
+// NewEventRepository создает новый экземпляр EventRepository
+func NewEventRepository(db *sql.DB) *EventRepository {
+	return &EventRepository{
+		db: db,
+	}
+}
+
+// Append записывает событие в outbox. Внутри WithinTx запись выполняется в открытой транзакции
+func (r *EventRepository) Append(ctx context.Context, event *models.DomainEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.Status == "" {
+		event.Status = models.EventStatusPending
+	}
+	event.CreatedAt = time.Now()
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = event.CreatedAt
+	}
+	if event.DeliveredTo == nil {
+		event.DeliveredTo = []string{}
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO domain_events (id, type, aggregate_id, payload, status, attempts, next_attempt_at, last_error, delivered_to, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, event.ID, event.Type, event.AggregateID, []byte(event.Payload), event.Status, event.Attempts,
+		event.NextAttemptAt, event.LastError, pq.Array(event.DeliveredTo), event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to append domain event: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue забирает ожидающие события, время попытки которых наступило, и откладывает их следующую попытку
+// до claimUntil. Строки, уже заблокированные другим экземпляром, пропускаются, поэтому параллельные
+// обработчики не получают одно и то же событие. Если обработчик упал, событие вернётся в очередь после claimUntil
+func (r *EventRepository) ClaimDue(ctx context.Context, now, claimUntil time.Time, limit int) ([]*models.DomainEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH claimed AS (
+			UPDATE domain_events SET next_attempt_at = $2
+			WHERE id IN (
+				SELECT id FROM domain_events
+				WHERE status = 'pending' AND next_attempt_at <= $1
+				ORDER BY created_at, id
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, type, aggregate_id, payload, status, attempts, next_attempt_at, last_error, delivered_to, created_at, delivered_at
+		)
+		SELECT id, type, aggregate_id, payload, status, attempts, next_attempt_at, last_error, delivered_to, created_at, delivered_at
+		FROM claimed
+		ORDER BY created_at, id
+	`, now, claimUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due domain events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDomainEvents(rows)
+}
+
+// MarkDelivered добавляет подписчика в список обработавших событие
+func (r *EventRepository) MarkDelivered(ctx context.Context, id uuid.UUID, subscriber string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE domain_events
+		SET delivered_to = array_append(delivered_to, $1)
+		WHERE id = $2 AND NOT ($1 = ANY(delivered_to))
+	`, subscriber, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark domain event delivered: %w", err)
+	}
+	return nil
+}
+
+// UpdateDelivery сохраняет результат попытки доставки
+func (r *EventRepository) UpdateDelivery(ctx context.Context, event *models.DomainEvent) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE domain_events
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, delivered_at = $5
+		WHERE id = $6
+	`, event.Status, event.Attempts, event.NextAttemptAt, event.LastError, event.DeliveredAt, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update domain event delivery: %w", err)
+	}
+	return nil
+}
+
+// GetDeadLetters получает события, доставка которых прекращена, начиная с последних
+func (r *EventRepository) GetDeadLetters(ctx context.Context, limit, offset int) ([]*models.DomainEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, type, aggregate_id, payload, status, attempts, next_attempt_at, last_error, delivered_to, created_at, delivered_at
+		FROM domain_events
+		WHERE status = 'dead'
+		ORDER BY created_at DESC, id
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead domain events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDomainEvents(rows)
+}
+
+// Requeue возвращает событие из dead letter в очередь. Подписчики, уже обработавшие событие, его не получат
+func (r *EventRepository) Requeue(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE domain_events
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND status = 'dead'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to requeue domain event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrEventNotFound
+	}
+	return nil
+}
+
+// scanDomainEvents читает события из результата запроса
+func scanDomainEvents(rows *sql.Rows) ([]*models.DomainEvent, error) {
+	var events []*models.DomainEvent
+	for rows.Next() {
+		var event models.DomainEvent
+		var payload []byte
+		var deliveredAt sql.NullTime
+		err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.AggregateID,
+			&payload,
+			&event.Status,
+			&event.Attempts,
+			&event.NextAttemptAt,
+			&event.LastError,
+			pq.Array(&event.DeliveredTo),
+			&event.CreatedAt,
+			&deliveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan domain event: %w", err)
+		}
+		event.Payload = payload
+		if deliveredAt.Valid {
+			event.DeliveredAt = &deliveredAt.Time
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
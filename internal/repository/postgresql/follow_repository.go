@@ -0,0 +1,237 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// FollowRepository представляет собой реализацию репозитория для работы с подписками, закладками и лентой игр
+type FollowRepository struct {
+	db *sql.DB
+}
+
+// NewFollowRepository создает новый экземпляр FollowRepository
+func NewFollowRepository(db *sql.DB) *FollowRepository {
+	return &FollowRepository{
+		db: db,
+	}
+}
+
+// Follow создаёт подписку или обновляет настройку уведомлений существующей
+func (r *FollowRepository) Follow(ctx context.Context, follow *models.CreatorFollow) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO creator_follows (follower_id, creator_id, notify, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (follower_id, creator_id) DO UPDATE SET notify = EXCLUDED.notify
+		RETURNING created_at
+	`, follow.FollowerID, follow.CreatorID, follow.Notify).Scan(&follow.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to follow creator: %w", err)
+	}
+	return nil
+}
+
+// Unfollow удаляет подписку
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, creatorID uint64) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM creator_follows WHERE follower_id = $1 AND creator_id = $2
+	`, followerID, creatorID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow creator: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrFollowNotFound
+	}
+
+	return nil
+}
+
+// GetFollowing получает подписки пользователя, сначала новые
+func (r *FollowRepository) GetFollowing(ctx context.Context, followerID uint64, limit, offset int) ([]*models.CreatorFollow, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT follower_id, creator_id, notify, created_at
+		FROM creator_follows
+		WHERE follower_id = $1
+		ORDER BY created_at DESC, creator_id
+		LIMIT $2 OFFSET $3
+	`, followerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get following: %w", err)
+	}
+	defer rows.Close()
+
+	var follows []*models.CreatorFollow
+	for rows.Next() {
+		var follow models.CreatorFollow
+		if err := rows.Scan(&follow.FollowerID, &follow.CreatorID, &follow.Notify, &follow.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan follow: %w", err)
+		}
+		follows = append(follows, &follow)
+	}
+
+	return follows, rows.Err()
+}
+
+// GetNotifiedFollowers возвращает подписчиков создателя, включивших уведомления
+func (r *FollowRepository) GetNotifiedFollowers(ctx context.Context, creatorID uint64) ([]uint64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT follower_id FROM creator_follows WHERE creator_id = $1 AND notify
+	`, creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followers: %w", err)
+	}
+	defer rows.Close()
+
+	var followers []uint64
+	for rows.Next() {
+		var followerID uint64
+		if err := rows.Scan(&followerID); err != nil {
+			return nil, fmt.Errorf("failed to scan follower: %w", err)
+		}
+		followers = append(followers, followerID)
+	}
+
+	return followers, rows.Err()
+}
+
+// AddBookmark сохраняет игру в закладки
+func (r *FollowRepository) AddBookmark(ctx context.Context, bookmark *models.GameBookmark) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO game_bookmarks (user_id, game_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, game_id) DO NOTHING
+	`, bookmark.UserID, bookmark.GameID)
+	if err != nil {
+		return fmt.Errorf("failed to add bookmark: %w", err)
+	}
+	return nil
+}
+
+// RemoveBookmark удаляет игру из закладок
+func (r *FollowRepository) RemoveBookmark(ctx context.Context, userID uint64, gameID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM game_bookmarks WHERE user_id = $1 AND game_id = $2
+	`, userID, gameID)
+	if err != nil {
+		return fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrBookmarkNotFound
+	}
+
+	return nil
+}
+
+// GetBookmarkedGames получает игры из закладок пользователя в любом статусе, сначала добавленные последними
+func (r *FollowRepository) GetBookmarkedGames(ctx context.Context, userID uint64, limit, offset int) ([]*models.Game, error) {
+	return r.queryGames(ctx, `SELECT `+gameColumns+`
+		FROM games
+		JOIN (SELECT game_id, created_at AS bookmarked_at FROM game_bookmarks WHERE user_id = $1) b ON b.game_id = games.id
+		ORDER BY b.bookmarked_at DESC, id
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+}
+
+// RecordActivation запоминает время последней активации игры
+func (r *FollowRepository) RecordActivation(ctx context.Context, gameID uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO game_activations (game_id, activated_at)
+		VALUES ($1, $2)
+		ON CONFLICT (game_id) DO UPDATE SET activated_at = EXCLUDED.activated_at
+	`, gameID, at)
+	if err != nil {
+		return fmt.Errorf("failed to record game activation: %w", err)
+	}
+	return nil
+}
+
+// GetFollowedGames получает активные публичные игры отслеживаемых создателей, активированные после since
+func (r *FollowRepository) GetFollowedGames(ctx context.Context, followerID uint64, since time.Time, limit int) ([]*models.Game, error) {
+	return r.queryGames(ctx, `SELECT `+gameColumns+`
+		FROM games
+		JOIN (SELECT game_id, activated_at FROM game_activations WHERE activated_at > $2) a ON a.game_id = games.id
+		WHERE status = 'active' AND visibility = 'public' AND (ends_at IS NULL OR ends_at > NOW())
+		AND creator_id IN (SELECT creator_id FROM creator_follows WHERE follower_id = $1)
+		ORDER BY a.activated_at DESC, id
+		LIMIT $3
+	`, followerID, since, limit)
+}
+
+// GetPlayProfile собирает число лобби по длине слова и сложности игр и диапазон ставок игрока
+func (r *FollowRepository) GetPlayProfile(ctx context.Context, userID uint64) (*models.PlayProfile, error) {
+	profile := &models.PlayProfile{
+		Lengths:      make(map[int]int),
+		Difficulties: make(map[string]int),
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(MIN(bet_amount), 0), COALESCE(MAX(bet_amount), 0)
+		FROM history
+		WHERE user_id = $1
+	`, userID).Scan(&profile.Games, &profile.MinBet, &profile.MaxBet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get play profile: %w", err)
+	}
+	if profile.Games == 0 {
+		return profile, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.length, g.difficulty, COUNT(*)
+		FROM history h
+		JOIN games g ON g.id = h.game_id
+		WHERE h.user_id = $1
+		GROUP BY g.length, g.difficulty
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get play profile: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var length, count int
+		var difficulty string
+		if err := rows.Scan(&length, &difficulty, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan play profile: %w", err)
+		}
+		profile.Lengths[length] += count
+		profile.Difficulties[difficulty] += count
+	}
+
+	return profile, rows.Err()
+}
+
+// queryGames выполняет выборку игр с колонками gameColumns
+func (r *FollowRepository) queryGames(ctx context.Context, query string, args ...any) ([]*models.Game, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []*models.Game
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
@@ -3,12 +3,14 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"time"
 
 	"github.com/TakuroBreath/wordle/internal/logger"
 	"github.com/TakuroBreath/wordle/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -34,8 +36,10 @@ func (r *GameRepository) Create(ctx context.Context, game *models.Game) error {
 		zap.String("title", game.Title))
 
 	query := `
-		INSERT INTO games (id, creator_id, word, length, difficulty, max_tries, title, description, min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		INSERT INTO games (id, creator_id, word, length, difficulty, max_tries, title, description, min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt, status, created_at, updated_at,
+			visibility, invite_token, allowed_user_ids, allowed_chat_id, starts_at, ends_at, auto_top_up_threshold, auto_top_up_amount,
+			max_daily_loss, max_active_lobbies, pool_floor, odds_min_multiplier, odds_max_multiplier, difficulty_score)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31)
 	`
 
 	// Генерация UUID, если он не был установлен
@@ -50,6 +54,10 @@ func (r *GameRepository) Create(ctx context.Context, game *models.Game) error {
 	}
 	game.UpdatedAt = now
 
+	if game.Visibility == "" {
+		game.Visibility = models.GameVisibilityPublic
+	}
+
 	_, err := r.db.ExecContext(
 		ctx,
 		query,
@@ -70,6 +78,20 @@ func (r *GameRepository) Create(ctx context.Context, game *models.Game) error {
 		game.Status,
 		game.CreatedAt,
 		game.UpdatedAt,
+		game.Visibility,
+		nullableString(game.InviteToken),
+		telegramIDArray(game.AllowedUserIDs),
+		nullableChatID(game.AllowedChatID),
+		game.StartsAt,
+		game.EndsAt,
+		game.AutoTopUpThreshold,
+		game.AutoTopUpAmount,
+		game.MaxDailyLoss,
+		game.MaxActiveLobbies,
+		game.PoolFloor,
+		game.OddsMinMultiplier,
+		game.OddsMaxMultiplier,
+		game.DifficultyScore,
 	)
 
 	if err != nil {
@@ -88,7 +110,11 @@ func (r *GameRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Gam
 	query := `
 		SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
 			min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
-			status, created_at, updated_at
+			status, created_at, updated_at,
+			visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+			auto_top_up_threshold, auto_top_up_amount,
+			max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score,
+			` + creatorReputationColumn + `
 		FROM games
 		WHERE id = $1
 	`
@@ -113,6 +139,23 @@ func (r *GameRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Gam
 		&game.Status,
 		&game.CreatedAt,
 		&game.UpdatedAt,
+		&game.Visibility,
+		&game.InviteToken,
+		(*telegramIDArray)(&game.AllowedUserIDs),
+		&game.AllowedChatID,
+		&game.StartsAt,
+		&game.EndsAt,
+		&game.AutoTopUpThreshold,
+		&game.AutoTopUpAmount,
+		&game.MaxDailyLoss,
+		&game.MaxActiveLobbies,
+		&game.PoolFloor,
+		&game.DailyLoss,
+		&game.DailyLossDay,
+		&game.OddsMinMultiplier,
+		&game.OddsMaxMultiplier,
+		&game.DifficultyScore,
+		&game.CreatorReputation,
 	)
 
 	if err != nil {
@@ -134,7 +177,10 @@ func (r *GameRepository) GetAll(ctx context.Context, limit, offset int) ([]*mode
 	query := `
 	SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
 		min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
-		status, created_at, updated_at
+		status, created_at, updated_at,
+		visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+		auto_top_up_threshold, auto_top_up_amount,
+		max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score
 	FROM games
 	ORDER BY created_at DESC
 	LIMIT $1 OFFSET $2
@@ -169,6 +215,22 @@ func (r *GameRepository) GetAll(ctx context.Context, limit, offset int) ([]*mode
 			&game.Status,
 			&game.CreatedAt,
 			&game.UpdatedAt,
+			&game.Visibility,
+			&game.InviteToken,
+			(*telegramIDArray)(&game.AllowedUserIDs),
+			&game.AllowedChatID,
+			&game.StartsAt,
+			&game.EndsAt,
+			&game.AutoTopUpThreshold,
+			&game.AutoTopUpAmount,
+			&game.MaxDailyLoss,
+			&game.MaxActiveLobbies,
+			&game.PoolFloor,
+			&game.DailyLoss,
+			&game.DailyLossDay,
+			&game.OddsMinMultiplier,
+			&game.OddsMaxMultiplier,
+			&game.DifficultyScore,
 		)
 
 		if err != nil {
@@ -194,9 +256,12 @@ func (r *GameRepository) GetActive(ctx context.Context, limit, offset int) ([]*m
 	query := `
 	SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
 		min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
-		status, created_at, updated_at
+		status, created_at, updated_at,
+		visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+		auto_top_up_threshold, auto_top_up_amount,
+		max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score
 	FROM games
-	WHERE status = 'active'
+	WHERE status = 'active' AND visibility = 'public' AND (ends_at IS NULL OR ends_at > NOW())
 	ORDER BY created_at DESC
 	LIMIT $1 OFFSET $2
 	`
@@ -230,6 +295,22 @@ func (r *GameRepository) GetActive(ctx context.Context, limit, offset int) ([]*m
 			&game.Status,
 			&game.CreatedAt,
 			&game.UpdatedAt,
+			&game.Visibility,
+			&game.InviteToken,
+			(*telegramIDArray)(&game.AllowedUserIDs),
+			&game.AllowedChatID,
+			&game.StartsAt,
+			&game.EndsAt,
+			&game.AutoTopUpThreshold,
+			&game.AutoTopUpAmount,
+			&game.MaxDailyLoss,
+			&game.MaxActiveLobbies,
+			&game.PoolFloor,
+			&game.DailyLoss,
+			&game.DailyLossDay,
+			&game.OddsMinMultiplier,
+			&game.OddsMaxMultiplier,
+			&game.DifficultyScore,
 		)
 
 		if err != nil {
@@ -255,7 +336,10 @@ func (r *GameRepository) GetByCreator(ctx context.Context, creatorID uint64, lim
 	query := `
 	SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
 		min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
-		status, created_at, updated_at
+		status, created_at, updated_at,
+		visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+		auto_top_up_threshold, auto_top_up_amount,
+		max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score
 	FROM games
 	WHERE creator_id = $1
 	ORDER BY created_at DESC
@@ -291,6 +375,22 @@ func (r *GameRepository) GetByCreator(ctx context.Context, creatorID uint64, lim
 			&game.Status,
 			&game.CreatedAt,
 			&game.UpdatedAt,
+			&game.Visibility,
+			&game.InviteToken,
+			(*telegramIDArray)(&game.AllowedUserIDs),
+			&game.AllowedChatID,
+			&game.StartsAt,
+			&game.EndsAt,
+			&game.AutoTopUpThreshold,
+			&game.AutoTopUpAmount,
+			&game.MaxDailyLoss,
+			&game.MaxActiveLobbies,
+			&game.PoolFloor,
+			&game.DailyLoss,
+			&game.DailyLossDay,
+			&game.OddsMinMultiplier,
+			&game.OddsMaxMultiplier,
+			&game.DifficultyScore,
 		)
 
 		if err != nil {
@@ -319,15 +419,19 @@ func (r *GameRepository) Update(ctx context.Context, game *models.Game) error {
 		UPDATE games
 		SET creator_id = $1, word = $2, length = $3, difficulty = $4, max_tries = $5,
 			title = $6, description = $7, min_bet = $8, max_bet = $9, reward_multiplier = $10,
-			currency = $11, reward_pool_ton = $12, reward_pool_usdt = $13, status = $14, updated_at = $15
-		WHERE id = $16
+			currency = $11, reward_pool_ton = $12, reward_pool_usdt = $13, status = $14, updated_at = $15,
+			visibility = $16, invite_token = $17, allowed_user_ids = $18, allowed_chat_id = $19,
+			starts_at = $20, ends_at = $21, auto_top_up_threshold = $22, auto_top_up_amount = $23,
+			max_daily_loss = $24, max_active_lobbies = $25, pool_floor = $26,
+			odds_min_multiplier = $27, odds_max_multiplier = $28, difficulty_score = $29
+		WHERE id = $30
 	`
 
 	game.UpdatedAt = time.Now()
 
 	log.Debug("Executing SQL query", zap.String("query", query))
 
-	_, err := r.db.ExecContext(
+	_, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		game.CreatorID,
@@ -345,6 +449,20 @@ func (r *GameRepository) Update(ctx context.Context, game *models.Game) error {
 		game.RewardPoolUsdt,
 		game.Status,
 		game.UpdatedAt,
+		game.Visibility,
+		nullableString(game.InviteToken),
+		telegramIDArray(game.AllowedUserIDs),
+		nullableChatID(game.AllowedChatID),
+		game.StartsAt,
+		game.EndsAt,
+		game.AutoTopUpThreshold,
+		game.AutoTopUpAmount,
+		game.MaxDailyLoss,
+		game.MaxActiveLobbies,
+		game.PoolFloor,
+		game.OddsMinMultiplier,
+		game.OddsMaxMultiplier,
+		game.DifficultyScore,
 		game.ID,
 	)
 
@@ -381,7 +499,10 @@ func (r *GameRepository) GetActiveByCreator(ctx context.Context, creatorID uint6
 	query := `
 		SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
 			min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
-			status, created_at, updated_at
+			status, created_at, updated_at,
+			visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+			auto_top_up_threshold, auto_top_up_amount,
+			max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score
 		FROM games
 		WHERE creator_id = $1 AND status = 'active'
 		ORDER BY created_at DESC
@@ -414,6 +535,22 @@ func (r *GameRepository) GetActiveByCreator(ctx context.Context, creatorID uint6
 			&game.Status,
 			&game.CreatedAt,
 			&game.UpdatedAt,
+			&game.Visibility,
+			&game.InviteToken,
+			(*telegramIDArray)(&game.AllowedUserIDs),
+			&game.AllowedChatID,
+			&game.StartsAt,
+			&game.EndsAt,
+			&game.AutoTopUpThreshold,
+			&game.AutoTopUpAmount,
+			&game.MaxDailyLoss,
+			&game.MaxActiveLobbies,
+			&game.PoolFloor,
+			&game.DailyLoss,
+			&game.DailyLossDay,
+			&game.OddsMinMultiplier,
+			&game.OddsMaxMultiplier,
+			&game.DifficultyScore,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan game: %w", err)
@@ -440,7 +577,7 @@ func (r *GameRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 
 	log.Debug("Executing SQL query", zap.String("query", query))
 
-	_, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, status, time.Now(), id)
 	if err != nil {
 		log.Error("Failed to update game status", zap.Error(err))
 		return fmt.Errorf("failed to update game status: %w", err)
@@ -504,7 +641,10 @@ func (r *GameRepository) GetByDifficulty(ctx context.Context, difficulty string,
 	query := `
 		SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
 			min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
-			status, created_at, updated_at
+			status, created_at, updated_at,
+			visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+			auto_top_up_threshold, auto_top_up_amount,
+			max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score
 		FROM games
 		WHERE difficulty = $1 AND status = 'active'
 		ORDER BY created_at DESC
@@ -538,6 +678,22 @@ func (r *GameRepository) GetByDifficulty(ctx context.Context, difficulty string,
 			&game.Status,
 			&game.CreatedAt,
 			&game.UpdatedAt,
+			&game.Visibility,
+			&game.InviteToken,
+			(*telegramIDArray)(&game.AllowedUserIDs),
+			&game.AllowedChatID,
+			&game.StartsAt,
+			&game.EndsAt,
+			&game.AutoTopUpThreshold,
+			&game.AutoTopUpAmount,
+			&game.MaxDailyLoss,
+			&game.MaxActiveLobbies,
+			&game.PoolFloor,
+			&game.DailyLoss,
+			&game.DailyLossDay,
+			&game.OddsMinMultiplier,
+			&game.OddsMaxMultiplier,
+			&game.DifficultyScore,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan game: %w", err)
@@ -561,7 +717,10 @@ func (r *GameRepository) GetByUserID(ctx context.Context, userID uint64, limit,
 	query := `
 		SELECT g.id, g.creator_id, g.word, g.length, g.difficulty, g.max_tries, g.title, g.description,
 			g.min_bet, g.max_bet, g.reward_multiplier, g.currency, g.reward_pool_ton, g.reward_pool_usdt,
-			g.status, g.created_at, g.updated_at
+			g.status, g.created_at, g.updated_at,
+			g.visibility, COALESCE(g.invite_token, ''), g.allowed_user_ids, COALESCE(g.allowed_chat_id, 0), g.starts_at, g.ends_at,
+			g.auto_top_up_threshold, g.auto_top_up_amount,
+			g.max_daily_loss, g.max_active_lobbies, g.pool_floor, g.daily_loss, g.daily_loss_day, g.odds_min_multiplier, g.odds_max_multiplier, g.difficulty_score
 		FROM games g
 		JOIN lobbies l ON g.id = l.game_id
 		WHERE l.user_id = $1
@@ -598,6 +757,22 @@ func (r *GameRepository) GetByUserID(ctx context.Context, userID uint64, limit,
 			&game.Status,
 			&game.CreatedAt,
 			&game.UpdatedAt,
+			&game.Visibility,
+			&game.InviteToken,
+			(*telegramIDArray)(&game.AllowedUserIDs),
+			&game.AllowedChatID,
+			&game.StartsAt,
+			&game.EndsAt,
+			&game.AutoTopUpThreshold,
+			&game.AutoTopUpAmount,
+			&game.MaxDailyLoss,
+			&game.MaxActiveLobbies,
+			&game.PoolFloor,
+			&game.DailyLoss,
+			&game.DailyLossDay,
+			&game.OddsMinMultiplier,
+			&game.OddsMaxMultiplier,
+			&game.DifficultyScore,
 		)
 
 		if err != nil {
@@ -619,7 +794,10 @@ func (r *GameRepository) GetByStatus(ctx context.Context, status string, limit,
 	query := `
 		SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
 			min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
-			status, created_at, updated_at
+			status, created_at, updated_at,
+			visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+			auto_top_up_threshold, auto_top_up_amount,
+			max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score
 		FROM games
 		WHERE status = $1
 		ORDER BY created_at DESC
@@ -654,6 +832,22 @@ func (r *GameRepository) GetByStatus(ctx context.Context, status string, limit,
 			&game.Status,
 			&game.CreatedAt,
 			&game.UpdatedAt,
+			&game.Visibility,
+			&game.InviteToken,
+			(*telegramIDArray)(&game.AllowedUserIDs),
+			&game.AllowedChatID,
+			&game.StartsAt,
+			&game.EndsAt,
+			&game.AutoTopUpThreshold,
+			&game.AutoTopUpAmount,
+			&game.MaxDailyLoss,
+			&game.MaxActiveLobbies,
+			&game.PoolFloor,
+			&game.DailyLoss,
+			&game.DailyLossDay,
+			&game.OddsMinMultiplier,
+			&game.OddsMaxMultiplier,
+			&game.DifficultyScore,
 		)
 
 		if err != nil {
@@ -731,76 +925,107 @@ func (r *GameRepository) GetGameStats(ctx context.Context, gameID uuid.UUID) (ma
 	}, nil
 }
 
-// SearchGames ищет игры по параметрам
-func (r *GameRepository) SearchGames(ctx context.Context, minBet, maxBet float64, difficulty string, limit, offset int) ([]*models.Game, error) {
-	query := `
-		SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
-			   min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
-			   status, created_at, updated_at
+// gameSortKey выражение ключа сортировки списка игр и тип параметра курсора
+type gameSortKey struct {
+	expr string
+	cast string
+}
+
+// gameSortKeys ключи сортировки списка игр. Пул сравнивается в валюте каждой игры.
+// Числовые ключи считаются во float8 так же, как в Go, чтобы значение из курсора совпадало с ключом записи
+var gameSortKeys = map[string]gameSortKey{
+	models.GameSortNewest:     {expr: `created_at`, cast: `timestamptz`},
+	models.GameSortPool:       {expr: `(` + rewardPoolColumn + `)::float8 - COALESCE(reserved_amount, 0)::float8`, cast: `float8`},
+	models.GameSortPopular:    {expr: `plays_count::float8`, cast: `float8`},
+	models.GameSortWinRate:    {expr: `COALESCE(wins_count::float8 / NULLIF(plays_count, 0), 0)`, cast: `float8`},
+	models.GameSortReputation: {expr: creatorReputationColumn, cast: `float8`},
+}
+
+// gameSearchDocument документ полнотекстового поиска игры (совпадает с индексом idx_games_search)
+const gameSearchDocument = `to_tsvector('simple', COALESCE(title, '') || ' ' || COALESCE(description, ''))`
+
+// SearchGames ищет публичные активные игры по параметрам.
+// Игры упорядочены по убыванию ключа сортировки и ID, cursor задаёт последнюю запись предыдущей страницы
+func (r *GameRepository) SearchGames(ctx context.Context, filter models.GameSearchFilter, limit int, cursor *models.PageCursor) ([]*models.Game, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = models.GameSortNewest
+	}
+	key, ok := gameSortKeys[sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort %q", filter.Sort)
+	}
+
+	var afterID, afterKey any
+	if cursor != nil {
+		afterID = cursor.ID
+		afterKey = cursor.Value
+		if sort == models.GameSortNewest {
+			afterKey = cursor.Time
+		}
+	}
+
+	query := `SELECT ` + gameColumns + `
 		FROM games
-		WHERE status = 'active'
+		WHERE status = 'active' AND visibility = 'public' AND (ends_at IS NULL OR ends_at > NOW())
 		AND ($1 = 0 OR min_bet >= $1)
 		AND ($2 = 0 OR max_bet <= $2)
 		AND ($3 = '' OR difficulty = $3)
-		ORDER BY created_at DESC
-		LIMIT $4 OFFSET $5
+		AND ($4 = 0 OR difficulty_score >= $4)
+		AND ($5 = 0 OR difficulty_score <= $5)
+		AND ($6 = '' OR currency = $6)
+		AND ($7 = 0 OR length = $7)
+		AND ($8 = 0 OR reward_multiplier >= $8)
+		AND ($9 = 0 OR reward_multiplier <= $9)
+		AND ($10 = 0 OR COALESCE(time_limit, 5) >= $10)
+		AND ($11 = 0 OR COALESCE(time_limit, 5) <= $11)
+		AND ($12 = 0 OR ` + rewardPoolColumn + ` - COALESCE(reserved_amount, 0) >= $12 * max_bet)
+		AND ($13 = 0 OR creator_id = $13)
+		AND ($14 = '' OR ` + gameSearchDocument + ` @@ websearch_to_tsquery('simple', $14))
+		AND ($15::uuid IS NULL OR (` + key.expr + `, id) < ($16::` + key.cast + `, $15::uuid))
+		ORDER BY ` + key.expr + ` DESC, id DESC
+		LIMIT $17
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, minBet, maxBet, difficulty, limit, offset)
+	games, err := r.queryGames(ctx, query, filter.MinBet, filter.MaxBet, filter.Difficulty,
+		filter.MinDifficultyScore, filter.MaxDifficultyScore, filter.Currency, filter.WordLength,
+		filter.MinMultiplier, filter.MaxMultiplier, filter.MinTimeLimit, filter.MaxTimeLimit,
+		filter.MinPoolBets, filter.CreatorID, filter.Query, afterID, afterKey, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search games: %w", err)
 	}
-	defer rows.Close()
-
-	var games []*models.Game
-	for rows.Next() {
-		var game models.Game
-		err := rows.Scan(
-			&game.ID,
-			&game.CreatorID,
-			&game.Word,
-			&game.Length,
-			&game.Difficulty,
-			&game.MaxTries,
-			&game.Title,
-			&game.Description,
-			&game.MinBet,
-			&game.MaxBet,
-			&game.RewardMultiplier,
-			&game.Currency,
-			&game.RewardPoolTon,
-			&game.RewardPoolUsdt,
-			&game.Status,
-			&game.CreatedAt,
-			&game.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan game: %w", err)
-		}
-		games = append(games, &game)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating games: %w", err)
-	}
 
 	return games, nil
 }
 
 // GetByShortID получает игру по короткому ID
 func (r *GameRepository) GetByShortID(ctx context.Context, shortID string) (*models.Game, error) {
+	return r.getByUniqueKey(ctx, "short_id", shortID)
+}
+
+// GetByInviteToken получает приватную игру по токену приглашения
+func (r *GameRepository) GetByInviteToken(ctx context.Context, token string) (*models.Game, error) {
+	return r.getByUniqueKey(ctx, "invite_token", token)
+}
+
+// getByUniqueKey получает игру по значению уникальной колонки (short_id или invite_token)
+func (r *GameRepository) getByUniqueKey(ctx context.Context, column, value string) (*models.Game, error) {
 	query := `
 		SELECT id, creator_id, word, length, difficulty, max_tries, title, description,
 			min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
 			status, created_at, updated_at,
 			COALESCE(short_id, ''), COALESCE(time_limit, 5), COALESCE(deposit_amount, 0), 
-			COALESCE(reserved_amount, 0), COALESCE(deposit_tx_hash, '')
+			COALESCE(reserved_amount, 0), COALESCE(deposit_tx_hash, ''),
+			visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+			auto_top_up_threshold, auto_top_up_amount,
+			max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score,
+			` + creatorReputationColumn + `
 		FROM games
-		WHERE short_id = $1
+		WHERE ` + column + ` = $1
 	`
 
 	var game models.Game
-	err := r.db.QueryRowContext(ctx, query, shortID).Scan(
+	err := r.db.QueryRowContext(ctx, query, value).Scan(
 		&game.ID,
 		&game.CreatorID,
 		&game.Word,
@@ -823,13 +1048,30 @@ func (r *GameRepository) GetByShortID(ctx context.Context, shortID string) (*mod
 		&game.DepositAmount,
 		&game.ReservedAmount,
 		&game.DepositTxHash,
+		&game.Visibility,
+		&game.InviteToken,
+		(*telegramIDArray)(&game.AllowedUserIDs),
+		&game.AllowedChatID,
+		&game.StartsAt,
+		&game.EndsAt,
+		&game.AutoTopUpThreshold,
+		&game.AutoTopUpAmount,
+		&game.MaxDailyLoss,
+		&game.MaxActiveLobbies,
+		&game.PoolFloor,
+		&game.DailyLoss,
+		&game.DailyLossDay,
+		&game.OddsMinMultiplier,
+		&game.OddsMaxMultiplier,
+		&game.DifficultyScore,
+		&game.CreatorReputation,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, models.ErrGameNotFound
 		}
-		return nil, fmt.Errorf("failed to get game by short_id: %w", err)
+		return nil, fmt.Errorf("failed to get game by %s: %w", column, err)
 	}
 
 	return &game, nil
@@ -853,7 +1095,7 @@ func (r *GameRepository) UpdateReservedAmount(ctx context.Context, id uuid.UUID,
 // IncrementReservedAmount увеличивает зарезервированную сумму
 func (r *GameRepository) IncrementReservedAmount(ctx context.Context, id uuid.UUID, amount float64) error {
 	query := `UPDATE games SET reserved_amount = reserved_amount + $1, updated_at = $2 WHERE id = $3`
-	_, err := r.db.ExecContext(ctx, query, amount, time.Now(), id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, amount, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("failed to increment reserved amount: %w", err)
 	}
@@ -863,9 +1105,343 @@ func (r *GameRepository) IncrementReservedAmount(ctx context.Context, id uuid.UU
 // DecrementReservedAmount уменьшает зарезервированную сумму
 func (r *GameRepository) DecrementReservedAmount(ctx context.Context, id uuid.UUID, amount float64) error {
 	query := `UPDATE games SET reserved_amount = GREATEST(0, reserved_amount - $1), updated_at = $2 WHERE id = $3`
-	_, err := r.db.ExecContext(ctx, query, amount, time.Now(), id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, amount, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("failed to decrement reserved amount: %w", err)
 	}
 	return nil
 }
+
+// telegramIDArray адаптирует список Telegram ID к массиву BIGINT[] PostgreSQL
+type telegramIDArray []uint64
+
+// Value реализует driver.Valuer
+func (a telegramIDArray) Value() (driver.Value, error) {
+	ids := make(pq.Int64Array, len(a))
+	for i, id := range a {
+		ids[i] = int64(id)
+	}
+	return ids.Value()
+}
+
+// Scan реализует sql.Scanner
+func (a *telegramIDArray) Scan(src any) error {
+	var ids pq.Int64Array
+	if err := ids.Scan(src); err != nil {
+		return err
+	}
+	*a = nil
+	for _, id := range ids {
+		*a = append(*a, uint64(id))
+	}
+	return nil
+}
+
+// nullableString преобразует пустую строку в NULL (для уникальных колонок)
+func nullableString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullableChatID преобразует незаданный ID чата в NULL
+func nullableChatID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// creatorReputationColumn репутация создателя игры; создатели без расчёта получают значение по умолчанию
+var creatorReputationColumn = fmt.Sprintf(
+	`COALESCE((SELECT score FROM creator_reputations r WHERE r.creator_id = games.creator_id), %g)`,
+	models.DefaultReputationScore)
+
+// gameColumns список колонок игры для выборок планировщика
+var gameColumns = `
+	id, creator_id, word, length, difficulty, max_tries, title, description,
+	min_bet, max_bet, reward_multiplier, currency, reward_pool_ton, reward_pool_usdt,
+	status, created_at, updated_at,
+	COALESCE(short_id, ''), COALESCE(time_limit, 5), COALESCE(deposit_amount, 0),
+	COALESCE(reserved_amount, 0), COALESCE(deposit_tx_hash, ''),
+	visibility, COALESCE(invite_token, ''), allowed_user_ids, COALESCE(allowed_chat_id, 0), starts_at, ends_at,
+	auto_top_up_threshold, auto_top_up_amount,
+	max_daily_loss, max_active_lobbies, pool_floor, daily_loss, daily_loss_day, odds_min_multiplier, odds_max_multiplier, difficulty_score,
+	plays_count, wins_count, ` + creatorReputationColumn
+
+// scanGame сканирует строку, выбранную с gameColumns
+func scanGame(row rowScanner) (*models.Game, error) {
+	var game models.Game
+	err := row.Scan(
+		&game.ID,
+		&game.CreatorID,
+		&game.Word,
+		&game.Length,
+		&game.Difficulty,
+		&game.MaxTries,
+		&game.Title,
+		&game.Description,
+		&game.MinBet,
+		&game.MaxBet,
+		&game.RewardMultiplier,
+		&game.Currency,
+		&game.RewardPoolTon,
+		&game.RewardPoolUsdt,
+		&game.Status,
+		&game.CreatedAt,
+		&game.UpdatedAt,
+		&game.ShortID,
+		&game.TimeLimit,
+		&game.DepositAmount,
+		&game.ReservedAmount,
+		&game.DepositTxHash,
+		&game.Visibility,
+		&game.InviteToken,
+		(*telegramIDArray)(&game.AllowedUserIDs),
+		&game.AllowedChatID,
+		&game.StartsAt,
+		&game.EndsAt,
+		&game.AutoTopUpThreshold,
+		&game.AutoTopUpAmount,
+		&game.MaxDailyLoss,
+		&game.MaxActiveLobbies,
+		&game.PoolFloor,
+		&game.DailyLoss,
+		&game.DailyLossDay,
+		&game.OddsMinMultiplier,
+		&game.OddsMaxMultiplier,
+		&game.DifficultyScore,
+		&game.Plays,
+		&game.Wins,
+		&game.CreatorReputation,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+// queryGames выполняет выборку игр с колонками gameColumns
+func (r *GameRepository) queryGames(ctx context.Context, query string, args ...any) ([]*models.Game, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []*models.Game
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		games = append(games, game)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating games: %w", err)
+	}
+
+	return games, nil
+}
+
+// GetDueToStart получает оплаченные игры, время начала которых наступило
+func (r *GameRepository) GetDueToStart(ctx context.Context, now time.Time, limit int) ([]*models.Game, error) {
+	query := `SELECT ` + gameColumns + `
+		FROM games
+		WHERE status = 'scheduled' AND starts_at <= $1
+		ORDER BY starts_at
+		LIMIT $2
+	`
+	return r.queryGames(ctx, query, now, limit)
+}
+
+// GetDueToClose получает незакрытые игры, время окончания которых наступило
+func (r *GameRepository) GetDueToClose(ctx context.Context, now time.Time, limit int) ([]*models.Game, error) {
+	query := `SELECT ` + gameColumns + `
+		FROM games
+		WHERE status IN ('pending', 'scheduled', 'active', 'inactive') AND ends_at <= $1
+		ORDER BY ends_at
+		LIMIT $2
+	`
+	return r.queryGames(ctx, query, now, limit)
+}
+
+// TransitionStatus атомарно меняет статус игры, если текущий статус равен from.
+// Возвращает false, если игра уже в другом статусе
+func (r *GameRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to string) (bool, error) {
+	query := `
+		UPDATE games
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, to, time.Now(), id, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to transition game status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// rewardPoolColumn выбирает колонку пула в валюте игры
+const rewardPoolColumn = `CASE WHEN currency = 'TON' THEN reward_pool_ton ELSE reward_pool_usdt END`
+
+// IncrementRewardPool атомарно увеличивает пул в валюте игры
+func (r *GameRepository) IncrementRewardPool(ctx context.Context, id uuid.UUID, amount float64) error {
+	return r.adjustRewardPool(ctx, id, amount)
+}
+
+// DecrementRewardPool атомарно уменьшает пул в валюте игры
+func (r *GameRepository) DecrementRewardPool(ctx context.Context, id uuid.UUID, amount float64) error {
+	return r.adjustRewardPool(ctx, id, -amount)
+}
+
+// adjustRewardPool изменяет пул в валюте игры на delta
+func (r *GameRepository) adjustRewardPool(ctx context.Context, id uuid.UUID, delta float64) error {
+	query := `
+		UPDATE games
+		SET reward_pool_ton = CASE WHEN currency = 'TON' THEN reward_pool_ton + $1 ELSE reward_pool_ton END,
+			reward_pool_usdt = CASE WHEN currency = 'TON' THEN reward_pool_usdt ELSE reward_pool_usdt + $1 END,
+			updated_at = $2
+		WHERE id = $3
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, delta, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to adjust reward pool: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return models.ErrGameNotFound
+	}
+
+	return nil
+}
+
+// WithdrawRewardPool списывает сумму из пула, если доступный остаток не опустится ниже margin
+func (r *GameRepository) WithdrawRewardPool(ctx context.Context, id uuid.UUID, amount, margin float64) (bool, error) {
+	query := `
+		UPDATE games
+		SET reward_pool_ton = CASE WHEN currency = 'TON' THEN reward_pool_ton - $1 ELSE reward_pool_ton END,
+			reward_pool_usdt = CASE WHEN currency = 'TON' THEN reward_pool_usdt ELSE reward_pool_usdt - $1 END,
+			updated_at = $2
+		WHERE id = $3 AND ` + rewardPoolColumn + ` - COALESCE(reserved_amount, 0) - $1 >= $4
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, amount, time.Now(), id, margin)
+	if err != nil {
+		return false, fmt.Errorf("failed to withdraw from reward pool: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetBelowTopUpThreshold получает активные игры, доступный пул которых опустился ниже порога автопополнения
+func (r *GameRepository) GetBelowTopUpThreshold(ctx context.Context, limit int) ([]*models.Game, error) {
+	query := `SELECT ` + gameColumns + `
+		FROM games
+		WHERE status = 'active' AND auto_top_up_amount > 0
+			AND ` + rewardPoolColumn + ` - COALESCE(reserved_amount, 0) < auto_top_up_threshold
+		ORDER BY updated_at
+		LIMIT $1
+	`
+	return r.queryGames(ctx, query, limit)
+}
+
+// AddDailyLoss атомарно добавляет результат лобби к убытку создателя за день day.
+// При смене дня счётчик начинается заново
+func (r *GameRepository) AddDailyLoss(ctx context.Context, id uuid.UUID, amount float64, day time.Time) error {
+	query := `
+		UPDATE games
+		SET daily_loss = CASE WHEN daily_loss_day = $2::date THEN daily_loss + $1 ELSE $1 END,
+			daily_loss_day = $2::date,
+			updated_at = $3
+		WHERE id = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, amount, day, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to add daily loss: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return models.ErrGameNotFound
+	}
+
+	return nil
+}
+
+// GetWithAutoOdds получает активные игры с включённой автоподстройкой мультипликатора.
+// Давно не обновлявшиеся игры идут первыми, чтобы пакеты обходили все игры по очереди
+func (r *GameRepository) GetWithAutoOdds(ctx context.Context, limit int) ([]*models.Game, error) {
+	query := `SELECT ` + gameColumns + `
+		FROM games
+		WHERE status = 'active' AND odds_max_multiplier > 0
+		ORDER BY updated_at
+		LIMIT $1
+	`
+	return r.queryGames(ctx, query, limit)
+}
+
+// UpdateRewardMultiplier изменяет только мультипликатор награды, не затрагивая пул и резерв
+func (r *GameRepository) UpdateRewardMultiplier(ctx context.Context, id uuid.UUID, multiplier float64) error {
+	query := `
+		UPDATE games
+		SET reward_multiplier = $1, updated_at = $2
+		WHERE id = $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, multiplier, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update reward multiplier: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return models.ErrGameNotFound
+	}
+
+	return nil
+}
+
+// RecordPlay учитывает рассчитанное лобби в счётчиках популярности и доли побед игры
+func (r *GameRepository) RecordPlay(ctx context.Context, id uuid.UUID, won bool) error {
+	query := `
+		UPDATE games
+		SET plays_count = plays_count + 1,
+			wins_count = wins_count + CASE WHEN $1 THEN 1 ELSE 0 END
+		WHERE id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, won, id)
+	if err != nil {
+		return fmt.Errorf("failed to record game play: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return models.ErrGameNotFound
+	}
+
+	return nil
+}
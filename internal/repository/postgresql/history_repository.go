@@ -25,8 +25,8 @@ func NewHistoryRepository(db *sql.DB) *HistoryRepository {
 // Create создает новую запись в истории
 func (r *HistoryRepository) Create(ctx context.Context, history *models.History) error {
 	query := `
-		INSERT INTO history (id, game_id, user_id, lobby_id, status, reward, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO history (id, game_id, user_id, lobby_id, status, bet_amount, reward, currency, tries_used, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	// Генерация UUID, если он не был установлен
@@ -47,7 +47,10 @@ func (r *HistoryRepository) Create(ctx context.Context, history *models.History)
 		history.UserID,
 		history.LobbyID,
 		history.Status,
+		history.BetAmount,
 		history.Reward,
+		history.Currency,
+		history.TriesUsed,
 		history.CreatedAt,
 		history.UpdatedAt,
 	)
@@ -62,7 +65,7 @@ func (r *HistoryRepository) Create(ctx context.Context, history *models.History)
 // GetByID получает запись истории по ID
 func (r *HistoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.History, error) {
 	query := `
-		SELECT id, game_id, user_id, lobby_id, status, reward, created_at, updated_at
+		SELECT id, game_id, user_id, lobby_id, status, bet_amount, reward, currency, tries_used, created_at, updated_at
 		FROM history
 		WHERE id = $1
 	`
@@ -75,7 +78,10 @@ func (r *HistoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 		&history.UserID,
 		&history.LobbyID,
 		&history.Status,
+		&history.BetAmount,
 		&history.Reward,
+		&history.Currency,
+		&history.TriesUsed,
 		&history.CreatedAt,
 		&history.UpdatedAt,
 	)
@@ -93,7 +99,7 @@ func (r *HistoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 // GetByGameID получает все записи истории для конкретной игры с пагинацией
 func (r *HistoryRepository) GetByGameID(ctx context.Context, gameID uuid.UUID, limit, offset int) ([]*models.History, error) {
 	query := `
-		SELECT id, game_id, user_id, lobby_id, status, reward, created_at, updated_at
+		SELECT id, game_id, user_id, lobby_id, status, bet_amount, reward, currency, tries_used, created_at, updated_at
 		FROM history
 		WHERE game_id = $1
 		ORDER BY created_at DESC
@@ -116,7 +122,10 @@ func (r *HistoryRepository) GetByGameID(ctx context.Context, gameID uuid.UUID, l
 			&history.UserID,
 			&history.LobbyID,
 			&history.Status,
+			&history.BetAmount,
 			&history.Reward,
+			&history.Currency,
+			&history.TriesUsed,
 			&history.CreatedAt,
 			&history.UpdatedAt,
 		)
@@ -138,7 +147,7 @@ func (r *HistoryRepository) GetByGameID(ctx context.Context, gameID uuid.UUID, l
 // GetByUserID получает все записи истории пользователя с пагинацией
 func (r *HistoryRepository) GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*models.History, error) {
 	query := `
-		SELECT id, game_id, user_id, lobby_id, status, reward, created_at, updated_at
+		SELECT id, game_id, user_id, lobby_id, status, bet_amount, reward, currency, tries_used, created_at, updated_at
 		FROM history
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -161,7 +170,10 @@ func (r *HistoryRepository) GetByUserID(ctx context.Context, userID uint64, limi
 			&history.UserID,
 			&history.LobbyID,
 			&history.Status,
+			&history.BetAmount,
 			&history.Reward,
+			&history.Currency,
+			&history.TriesUsed,
 			&history.CreatedAt,
 			&history.UpdatedAt,
 		)
@@ -184,8 +196,9 @@ func (r *HistoryRepository) GetByUserID(ctx context.Context, userID uint64, limi
 func (r *HistoryRepository) Update(ctx context.Context, history *models.History) error {
 	query := `
 		UPDATE history
-		SET game_id = $1, user_id = $2, lobby_id = $3, status = $4, reward = $5, updated_at = $6
-		WHERE id = $7
+		SET game_id = $1, user_id = $2, lobby_id = $3, status = $4, bet_amount = $5, reward = $6, currency = $7,
+			tries_used = $8, updated_at = $9
+		WHERE id = $10
 	`
 
 	history.UpdatedAt = time.Now()
@@ -197,7 +210,10 @@ func (r *HistoryRepository) Update(ctx context.Context, history *models.History)
 		history.UserID,
 		history.LobbyID,
 		history.Status,
+		history.BetAmount,
 		history.Reward,
+		history.Currency,
+		history.TriesUsed,
 		history.UpdatedAt,
 		history.ID,
 	)
@@ -224,7 +240,7 @@ func (r *HistoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
 // GetByLobbyID получает историю по ID лобби
 func (r *HistoryRepository) GetByLobbyID(ctx context.Context, lobbyID uuid.UUID) (*models.History, error) {
 	query := `
-		SELECT id, game_id, user_id, lobby_id, status, reward, created_at, updated_at
+		SELECT id, game_id, user_id, lobby_id, status, bet_amount, reward, currency, tries_used, created_at, updated_at
 		FROM history
 		WHERE lobby_id = $1
 	`
@@ -237,7 +253,10 @@ func (r *HistoryRepository) GetByLobbyID(ctx context.Context, lobbyID uuid.UUID)
 		&history.UserID,
 		&history.LobbyID,
 		&history.Status,
+		&history.BetAmount,
 		&history.Reward,
+		&history.Currency,
+		&history.TriesUsed,
 		&history.CreatedAt,
 		&history.UpdatedAt,
 	)
@@ -255,7 +274,7 @@ func (r *HistoryRepository) GetByLobbyID(ctx context.Context, lobbyID uuid.UUID)
 // GetByStatus получает записи истории по статусу с пагинацией
 func (r *HistoryRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.History, error) {
 	query := `
-		SELECT id, game_id, user_id, lobby_id, status, reward, created_at, updated_at
+		SELECT id, game_id, user_id, lobby_id, status, bet_amount, reward, currency, tries_used, created_at, updated_at
 		FROM history
 		WHERE status = $1
 		ORDER BY created_at DESC
@@ -278,7 +297,10 @@ func (r *HistoryRepository) GetByStatus(ctx context.Context, status string, limi
 			&history.UserID,
 			&history.LobbyID,
 			&history.Status,
+			&history.BetAmount,
 			&history.Reward,
+			&history.Currency,
+			&history.TriesUsed,
 			&history.CreatedAt,
 			&history.UpdatedAt,
 		)
@@ -416,7 +438,7 @@ func (r *HistoryRepository) GetTopPlayers(ctx context.Context, limit int) ([]map
 // GetRecentGames получает последние игры с пагинацией
 func (r *HistoryRepository) GetRecentGames(ctx context.Context, limit, offset int) ([]*models.History, error) {
 	query := `
-		SELECT id, game_id, user_id, lobby_id, status, reward, created_at, updated_at
+		SELECT id, game_id, user_id, lobby_id, status, bet_amount, reward, currency, tries_used, created_at, updated_at
 		FROM history
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -438,7 +460,10 @@ func (r *HistoryRepository) GetRecentGames(ctx context.Context, limit, offset in
 			&history.UserID,
 			&history.LobbyID,
 			&history.Status,
+			&history.BetAmount,
 			&history.Reward,
+			&history.Currency,
+			&history.TriesUsed,
 			&history.CreatedAt,
 			&history.UpdatedAt,
 		)
@@ -503,3 +528,41 @@ func (r *HistoryRepository) GetGameHistoryStats(ctx context.Context, gameID uuid
 		"win_rate":       float64(stats.TotalWins) / float64(stats.TotalGames),
 	}, nil
 }
+
+// CountGameResults возвращает количество завершённых лобби игры и побед игроков в ней
+func (r *HistoryRepository) CountGameResults(ctx context.Context, gameID uuid.UUID) (total, playerWins int, err error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(CASE WHEN status = $2 THEN 1 END)
+		FROM history
+		WHERE game_id = $1
+	`
+
+	err = r.db.QueryRowContext(ctx, query, gameID, models.HistoryStatusPlayerWin).Scan(&total, &playerWins)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count game results: %w", err)
+	}
+
+	return total, playerWins, nil
+}
+
+// CountResultsByRules возвращает количество завершённых лобби и побед игроков по всем играм
+// с заданной длиной слова и числом попыток
+func (r *HistoryRepository) CountResultsByRules(ctx context.Context, length, maxTries int) (total, playerWins int, err error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(CASE WHEN h.status = $3 THEN 1 END)
+		FROM history h
+		JOIN games g ON g.id = h.game_id
+		WHERE g.length = $1 AND g.max_tries = $2
+	`
+
+	err = r.db.QueryRowContext(ctx, query, length, maxTries, models.HistoryStatusPlayerWin).Scan(&total, &playerWins)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count results by rules: %w", err)
+	}
+
+	return total, playerWins, nil
+}
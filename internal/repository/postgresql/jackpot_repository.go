@@ -0,0 +1,190 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+const jackpotEntryColumns = `id, currency, type, amount, balance_after, lobby_id, game_id, user_id, created_at`
+
+// JackpotRepository представляет собой реализацию репозитория для работы с джекпотом
+type JackpotRepository struct {
+	db *sql.DB
+}
+
+// NewJackpotRepository создает новый экземпляр JackpotRepository
+func NewJackpotRepository(db *sql.DB) *JackpotRepository {
+	return &JackpotRepository{
+		db: db,
+	}
+}
+
+// Get возвращает джекпот в валюте (нулевой, если отчислений ещё не было)
+func (r *JackpotRepository) Get(ctx context.Context, currency string) (*models.Jackpot, error) {
+	query := `SELECT currency, amount, updated_at FROM jackpots WHERE currency = $1`
+
+	jackpot := &models.Jackpot{Currency: currency}
+	err := r.db.QueryRowContext(ctx, query, currency).Scan(&jackpot.Currency, &jackpot.Amount, &jackpot.UpdatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get jackpot: %w", err)
+	}
+
+	return jackpot, nil
+}
+
+// GetAll возвращает джекпоты во всех валютах
+func (r *JackpotRepository) GetAll(ctx context.Context) ([]*models.Jackpot, error) {
+	query := `SELECT currency, amount, updated_at FROM jackpots ORDER BY currency`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jackpots: %w", err)
+	}
+	defer rows.Close()
+
+	var jackpots []*models.Jackpot
+	for rows.Next() {
+		var jackpot models.Jackpot
+		if err := rows.Scan(&jackpot.Currency, &jackpot.Amount, &jackpot.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan jackpot: %w", err)
+		}
+		jackpots = append(jackpots, &jackpot)
+	}
+
+	return jackpots, rows.Err()
+}
+
+// Contribute атомарно добавляет отчисление к джекпоту и записывает его в журнал.
+// Возвращает false, если отчисление по лобби уже было
+func (r *JackpotRepository) Contribute(ctx context.Context, entry *models.JackpotEntry) (bool, error) {
+	entry.Type = models.JackpotEntryContribution
+	return r.applyEntry(ctx, entry, func(current float64) (float64, bool) {
+		return current + entry.Amount, true
+	})
+}
+
+// Payout атомарно забирает весь джекпот в валюте entry и записывает выплату в журнал.
+// Возвращает false, если выплата по лобби уже была или джекпот пуст
+func (r *JackpotRepository) Payout(ctx context.Context, entry *models.JackpotEntry) (bool, error) {
+	entry.Type = models.JackpotEntryPayout
+	return r.applyEntry(ctx, entry, func(current float64) (float64, bool) {
+		entry.Amount = current
+		return 0, current > 0
+	})
+}
+
+// applyEntry в одной транзакции блокирует джекпот, записывает запись журнала и сохраняет новый размер джекпота.
+// Если транзакция уже открыта, запись применяется в ней.
+// apply возвращает новый размер джекпота и false, если запись применять не нужно.
+// Уникальный индекс (lobby_id, type) гарантирует, что запись по лобби применяется не более одного раза
+func (r *JackpotRepository) applyEntry(ctx context.Context, entry *models.JackpotEntry, apply func(current float64) (float64, bool)) (bool, error) {
+	var applied bool
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		now := time.Now()
+
+		// Создаём джекпот при первом обращении и блокируем его до конца транзакции
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO jackpots (currency, amount, updated_at) VALUES ($1, 0, $2)
+			ON CONFLICT (currency) DO NOTHING
+		`, entry.Currency, now)
+		if err != nil {
+			return fmt.Errorf("failed to init jackpot: %w", err)
+		}
+
+		var current float64
+		err = tx.QueryRowContext(ctx, `SELECT amount FROM jackpots WHERE currency = $1 FOR UPDATE`, entry.Currency).Scan(&current)
+		if err != nil {
+			return fmt.Errorf("failed to lock jackpot: %w", err)
+		}
+
+		balance, ok := apply(current)
+		if !ok {
+			return nil
+		}
+
+		if entry.ID == uuid.Nil {
+			entry.ID = uuid.New()
+		}
+		entry.BalanceAfter = balance
+		entry.CreatedAt = now
+
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO jackpot_entries (`+jackpotEntryColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (lobby_id, type) DO NOTHING
+		`, entry.ID, entry.Currency, entry.Type, entry.Amount, entry.BalanceAfter,
+			entry.LobbyID, entry.GameID, entry.UserID, entry.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create jackpot entry: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			// Запись по лобби уже есть
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE jackpots SET amount = $1, updated_at = $2 WHERE currency = $3`,
+			balance, now, entry.Currency)
+		if err != nil {
+			return fmt.Errorf("failed to update jackpot: %w", err)
+		}
+
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// GetEntries возвращает журнал джекпота от новых записей к старым (пустая валюта - все валюты)
+func (r *JackpotRepository) GetEntries(ctx context.Context, currency string, limit, offset int) ([]*models.JackpotEntry, error) {
+	query := `
+		SELECT ` + jackpotEntryColumns + `
+		FROM jackpot_entries
+		WHERE $1 = '' OR currency = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, currency, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jackpot entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.JackpotEntry
+	for rows.Next() {
+		var entry models.JackpotEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Currency,
+			&entry.Type,
+			&entry.Amount,
+			&entry.BalanceAfter,
+			&entry.LobbyID,
+			&entry.GameID,
+			&entry.UserID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan jackpot entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
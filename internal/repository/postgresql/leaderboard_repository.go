@@ -0,0 +1,157 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// leaderboardOrder условия отбора и порядок строк таблицы лидеров по метрике
+var leaderboardOrder = map[string]string{
+	models.LeaderboardMetricProfit:     `ORDER BY s.profit DESC, s.games DESC, s.user_id`,
+	models.LeaderboardMetricWinRate:    `ORDER BY s.wins::float / s.games DESC, s.games DESC, s.user_id`,
+	models.LeaderboardMetricEfficiency: `WHERE s.wins > 0 ORDER BY s.avg_tries, s.wins DESC, s.user_id`,
+}
+
+// LeaderboardRepository представляет собой реализацию репозитория для работы с таблицами лидеров
+type LeaderboardRepository struct {
+	db *sql.DB
+}
+
+// NewLeaderboardRepository создает новый экземпляр LeaderboardRepository
+func NewLeaderboardRepository(db *sql.DB) *LeaderboardRepository {
+	return &LeaderboardRepository{
+		db: db,
+	}
+}
+
+// GetEntries строит таблицу лидеров по истории игр, исключая пользователей, скрытых из рейтингов.
+// Прибыль считается по лобби в валюте таблицы; для рейтинга по прибыли учитываются только такие лобби
+func (r *LeaderboardRepository) GetEntries(ctx context.Context, query *models.LeaderboardQuery) ([]*models.LeaderboardEntry, error) {
+	order, ok := leaderboardOrder[query.Metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown leaderboard metric: %s", query.Metric)
+	}
+	onlyCurrency := query.Metric == models.LeaderboardMetricProfit
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH s AS (
+			SELECT h.user_id,
+				COUNT(*) AS games,
+				COUNT(*) FILTER (WHERE h.status = $1) AS wins,
+				COALESCE(SUM(h.reward - h.bet_amount) FILTER (WHERE h.currency = $2), 0) AS profit,
+				COALESCE(AVG(h.tries_used) FILTER (WHERE h.status = $1), 0) AS avg_tries
+			FROM history h
+			WHERE h.created_at >= $3 AND h.created_at < $4
+				AND (NOT $5 OR h.currency = $2)
+				AND NOT EXISTS (SELECT 1 FROM leaderboard_opt_outs o WHERE o.user_id = h.user_id)
+			GROUP BY h.user_id
+			HAVING COUNT(*) >= $6
+		)
+		SELECT s.user_id, COALESCE(u.username, ''), s.games, s.wins, s.profit, s.avg_tries
+		FROM s
+		LEFT JOIN users u ON u.telegram_id = s.user_id
+		`+order+`
+		LIMIT $7
+	`, models.HistoryStatusPlayerWin, query.Currency, query.From, query.To, onlyCurrency, query.MinGames, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.LeaderboardEntry
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+		if err := rows.Scan(&entry.UserID, &entry.Username, &entry.Games, &entry.Wins, &entry.Profit,
+			&entry.AvgTries); err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// SetOptOut скрывает пользователя из рейтингов или возвращает его в них
+func (r *LeaderboardRepository) SetOptOut(ctx context.Context, userID uint64, optOut bool) error {
+	var err error
+	if optOut {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO leaderboard_opt_outs (user_id, created_at) VALUES ($1, $2)
+			ON CONFLICT (user_id) DO NOTHING
+		`, userID, time.Now())
+	} else {
+		_, err = r.db.ExecContext(ctx, `DELETE FROM leaderboard_opt_outs WHERE user_id = $1`, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update leaderboard opt-out: %w", err)
+	}
+	return nil
+}
+
+// IsOptedOut проверяет, скрыт ли пользователь из рейтингов
+func (r *LeaderboardRepository) IsOptedOut(ctx context.Context, userID uint64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM leaderboard_opt_outs WHERE user_id = $1)`, userID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check leaderboard opt-out: %w", err)
+	}
+	return exists, nil
+}
+
+// CreatePayout записывает приз сезона. Возвращает false, если приз за это место уже записан
+func (r *LeaderboardRepository) CreatePayout(ctx context.Context, payout *models.LeaderboardPayout) (bool, error) {
+	if payout.ID == uuid.Nil {
+		payout.ID = uuid.New()
+	}
+	if payout.CreatedAt.IsZero() {
+		payout.CreatedAt = time.Now()
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO leaderboard_payouts (id, period, metric, currency, period_start, rank, user_id, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (period, metric, currency, period_start, rank) DO NOTHING
+	`, payout.ID, payout.Period, payout.Metric, payout.Currency, payout.PeriodStart, payout.Rank, payout.UserID,
+		payout.Amount, payout.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create leaderboard payout: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetPayouts возвращает выплаченные призы сезонов, начиная с последних
+func (r *LeaderboardRepository) GetPayouts(ctx context.Context, limit, offset int) ([]*models.LeaderboardPayout, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, period, metric, currency, period_start, rank, user_id, amount, created_at
+		FROM leaderboard_payouts
+		ORDER BY period_start DESC, period, metric, currency, rank
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard payouts: %w", err)
+	}
+	defer rows.Close()
+
+	var payouts []*models.LeaderboardPayout
+	for rows.Next() {
+		var payout models.LeaderboardPayout
+		if err := rows.Scan(&payout.ID, &payout.Period, &payout.Metric, &payout.Currency, &payout.PeriodStart,
+			&payout.Rank, &payout.UserID, &payout.Amount, &payout.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard payout: %w", err)
+		}
+		payouts = append(payouts, &payout)
+	}
+
+	return payouts, rows.Err()
+}
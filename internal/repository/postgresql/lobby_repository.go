@@ -228,6 +228,60 @@ func (r *LobbyRepository) GetByUserID(ctx context.Context, userID uint64, limit,
 	return lobbies, nil
 }
 
+// GetByUserIDAfter получает лобби пользователя от новых к старым, начиная после cursor (nil - с начала)
+func (r *LobbyRepository) GetByUserIDAfter(ctx context.Context, userID uint64, limit int, cursor *models.PageCursor) ([]*models.Lobby, error) {
+	var afterID, afterTime any
+	if cursor != nil {
+		afterID, afterTime = cursor.ID, cursor.Time
+	}
+
+	query := `
+		SELECT id, game_id, user_id, max_tries, tries_used, bet_amount,
+			potential_reward, status, created_at, updated_at, expires_at
+		FROM lobbies
+		WHERE user_id = $1 AND ($2::uuid IS NULL OR (created_at, id) < ($3::timestamptz, $2::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, afterID, afterTime, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user lobbies: %w", err)
+	}
+	defer rows.Close()
+
+	var lobbies []*models.Lobby
+	for rows.Next() {
+		var lobby models.Lobby
+
+		err := rows.Scan(
+			&lobby.ID,
+			&lobby.GameID,
+			&lobby.UserID,
+			&lobby.MaxTries,
+			&lobby.TriesUsed,
+			&lobby.BetAmount,
+			&lobby.PotentialReward,
+			&lobby.Status,
+			&lobby.CreatedAt,
+			&lobby.UpdatedAt,
+			&lobby.ExpiresAt,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lobby: %w", err)
+		}
+
+		lobbies = append(lobbies, &lobby)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user lobbies: %w", err)
+	}
+
+	return lobbies, nil
+}
+
 // GetActive получает все активные лобби
 func (r *LobbyRepository) GetActive(ctx context.Context, limit, offset int) ([]*models.Lobby, error) {
 	query := `
@@ -327,6 +381,27 @@ func (r *LobbyRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	return nil
 }
 
+// TransitionStatus атомарно переводит лобби из статуса fromStatus в toStatus
+func (r *LobbyRepository) TransitionStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus string) (bool, error) {
+	query := `
+		UPDATE lobbies
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, toStatus, time.Now(), id, fromStatus)
+	if err != nil {
+		return false, fmt.Errorf("failed to transition lobby status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
 // UpdateTriesUsed обновляет количество использованных попыток
 func (r *LobbyRepository) UpdateTriesUsed(ctx context.Context, id uuid.UUID, triesUsed int) error {
 	query := `
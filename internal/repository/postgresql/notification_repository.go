@@ -0,0 +1,197 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// NotificationRepository представляет собой реализацию репозитория для работы с очередью уведомлений и настройками
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository создает новый экземпляр NotificationRepository
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+// Enqueue ставит уведомление в очередь. Сообщение с уже известным ключом события не добавляется
+func (r *NotificationRepository) Enqueue(ctx context.Context, notification *models.Notification) (bool, error) {
+	if notification.ID == uuid.Nil {
+		notification.ID = uuid.New()
+	}
+	if notification.Status == "" {
+		notification.Status = models.NotificationStatusPending
+	}
+	notification.CreatedAt = time.Now()
+	if notification.NextAttemptAt.IsZero() {
+		notification.NextAttemptAt = notification.CreatedAt
+	}
+
+	var dedupKey sql.NullString
+	if notification.DedupKey != "" {
+		dedupKey = sql.NullString{String: notification.DedupKey, Valid: true}
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO notifications (id, user_id, type, dedup_key, text, status, attempts, next_attempt_at, last_error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (dedup_key) DO NOTHING
+	`, notification.ID, notification.UserID, notification.Type, dedupKey, notification.Text, notification.Status,
+		notification.Attempts, notification.NextAttemptAt, notification.LastError, notification.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetDue получает ожидающие уведомления, время попытки которых наступило
+func (r *NotificationRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.Notification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, type, COALESCE(dedup_key, ''), text, status, attempts, next_attempt_at, last_error, created_at, sent_at
+		FROM notifications
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, created_at, id
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var notification models.Notification
+		var sentAt sql.NullTime
+		err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Type,
+			&notification.DedupKey,
+			&notification.Text,
+			&notification.Status,
+			&notification.Attempts,
+			&notification.NextAttemptAt,
+			&notification.LastError,
+			&notification.CreatedAt,
+			&sentAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if sentAt.Valid {
+			notification.SentAt = &sentAt.Time
+		}
+		notifications = append(notifications, &notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// UpdateDelivery сохраняет результат попытки отправки
+func (r *NotificationRepository) UpdateDelivery(ctx context.Context, notification *models.Notification) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE notifications
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, sent_at = $5
+		WHERE id = $6
+	`, notification.Status, notification.Attempts, notification.NextAttemptAt, notification.LastError,
+		notification.SentAt, notification.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update notification delivery: %w", err)
+	}
+	return nil
+}
+
+// GetPreferences возвращает изменённые пользователем настройки уведомлений
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID uint64) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT type, enabled FROM notification_preferences WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := make(map[string]bool)
+	for rows.Next() {
+		var notificationType string
+		var enabled bool
+		if err := rows.Scan(&notificationType, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		preferences[notificationType] = enabled
+	}
+
+	return preferences, rows.Err()
+}
+
+// SetPreference сохраняет настройку типа уведомлений
+func (r *NotificationRepository) SetPreference(ctx context.Context, userID uint64, notificationType string, enabled bool) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notification_preferences (user_id, type, enabled, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at
+	`, userID, notificationType, enabled)
+	if err != nil {
+		return fmt.Errorf("failed to set notification preference: %w", err)
+	}
+	return nil
+}
+
+// GetExpiringLobbies получает активные лобби, истекающие в (now, deadline], без отправленного напоминания.
+// Лобби короче notice не попадают в выборку: игрок только что начал игру
+func (r *NotificationRepository) GetExpiringLobbies(ctx context.Context, now, deadline time.Time, notice time.Duration, limit int) ([]*models.Lobby, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT l.id, l.game_id, l.user_id, l.max_tries, l.tries_used, l.bet_amount,
+			l.potential_reward, COALESCE(l.currency, ''), l.status, l.created_at, l.updated_at, l.expires_at
+		FROM lobbies l
+		WHERE l.status = 'active' AND l.expires_at > $1 AND l.expires_at <= $2
+		AND l.expires_at - l.created_at > make_interval(secs => $3)
+		AND NOT EXISTS (
+			SELECT 1 FROM notifications n WHERE n.dedup_key = $4 || l.id::text
+		)
+		ORDER BY l.expires_at
+		LIMIT $5
+	`, now, deadline, notice.Seconds(), models.NotificationLobbyExpiring+":", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expiring lobbies: %w", err)
+	}
+	defer rows.Close()
+
+	var lobbies []*models.Lobby
+	for rows.Next() {
+		var lobby models.Lobby
+		err := rows.Scan(
+			&lobby.ID,
+			&lobby.GameID,
+			&lobby.UserID,
+			&lobby.MaxTries,
+			&lobby.TriesUsed,
+			&lobby.BetAmount,
+			&lobby.PotentialReward,
+			&lobby.Currency,
+			&lobby.Status,
+			&lobby.CreatedAt,
+			&lobby.UpdatedAt,
+			&lobby.ExpiresAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lobby: %w", err)
+		}
+		lobbies = append(lobbies, &lobby)
+	}
+
+	return lobbies, rows.Err()
+}
@@ -0,0 +1,163 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+)
+
+// PlayerStatsRepository представляет собой реализацию репозитория для работы с агрегатами статистики игроков
+type PlayerStatsRepository struct {
+	db *sql.DB
+}
+
+// NewPlayerStatsRepository создает новый экземпляр PlayerStatsRepository
+func NewPlayerStatsRepository(db *sql.DB) *PlayerStatsRepository {
+	return &PlayerStatsRepository{
+		db: db,
+	}
+}
+
+// RecordResult атомарно учитывает результат лобби в дневных агрегатах, распределении попыток и стартовых словах
+func (r *PlayerStatsRepository) RecordResult(ctx context.Context, result *models.PlayerResult) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	wins, cashOuts, timedSolves := 0, 0, 0
+	if result.Won() {
+		wins = 1
+		if result.SolveSeconds > 0 {
+			timedSolves = 1
+		}
+	}
+	if result.Status == models.LobbyStatusCashedOut {
+		cashOuts = 1
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO player_daily_stats (user_id, day, currency, games, wins, cash_outs, wagered, paid_out, solve_seconds, timed_solves)
+		VALUES ($1, $2, $3, 1, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id, day, currency) DO UPDATE SET
+			games = player_daily_stats.games + 1,
+			wins = player_daily_stats.wins + EXCLUDED.wins,
+			cash_outs = player_daily_stats.cash_outs + EXCLUDED.cash_outs,
+			wagered = player_daily_stats.wagered + EXCLUDED.wagered,
+			paid_out = player_daily_stats.paid_out + EXCLUDED.paid_out,
+			solve_seconds = player_daily_stats.solve_seconds + EXCLUDED.solve_seconds,
+			timed_solves = player_daily_stats.timed_solves + EXCLUDED.timed_solves
+	`, result.UserID, result.Day, result.Currency, wins, cashOuts, result.BetAmount, result.Payout,
+		result.SolveSeconds, timedSolves)
+	if err != nil {
+		return fmt.Errorf("failed to update daily stats: %w", err)
+	}
+
+	if result.Won() && result.TriesUsed > 0 {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO player_daily_guesses (user_id, day, tries, count) VALUES ($1, $2, $3, 1)
+			ON CONFLICT (user_id, day, tries) DO UPDATE SET count = player_daily_guesses.count + 1
+		`, result.UserID, result.Day, result.TriesUsed)
+		if err != nil {
+			return fmt.Errorf("failed to update guess distribution: %w", err)
+		}
+	}
+
+	if result.StartWord != "" {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO player_daily_start_words (user_id, day, word, count) VALUES ($1, $2, $3, 1)
+			ON CONFLICT (user_id, day, word) DO UPDATE SET count = player_daily_start_words.count + 1
+		`, result.UserID, result.Day, result.StartWord)
+		if err != nil {
+			return fmt.Errorf("failed to update start words: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stats: %w", err)
+	}
+
+	return nil
+}
+
+// GetDaily возвращает дневные агрегаты игрока за [from, to) в порядке дней
+func (r *PlayerStatsRepository) GetDaily(ctx context.Context, userID uint64, from, to time.Time) ([]*models.PlayerDailyStats, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, day, currency, games, wins, cash_outs, wagered, paid_out, solve_seconds, timed_solves
+		FROM player_daily_stats
+		WHERE user_id = $1 AND day >= $2 AND day < $3
+		ORDER BY day, currency
+	`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.PlayerDailyStats
+	for rows.Next() {
+		var day models.PlayerDailyStats
+		if err := rows.Scan(&day.UserID, &day.Day, &day.Currency, &day.Games, &day.Wins, &day.CashOuts,
+			&day.Wagered, &day.PaidOut, &day.SolveSeconds, &day.TimedSolves); err != nil {
+			return nil, fmt.Errorf("failed to scan daily stats: %w", err)
+		}
+		stats = append(stats, &day)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetGuessDistribution возвращает число побед игрока по количеству попыток за [from, to)
+func (r *PlayerStatsRepository) GetGuessDistribution(ctx context.Context, userID uint64, from, to time.Time) (map[int]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tries, SUM(count)
+		FROM player_daily_guesses
+		WHERE user_id = $1 AND day >= $2 AND day < $3
+		GROUP BY tries
+	`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guess distribution: %w", err)
+	}
+	defer rows.Close()
+
+	distribution := make(map[int]int)
+	for rows.Next() {
+		var tries, count int
+		if err := rows.Scan(&tries, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan guess distribution: %w", err)
+		}
+		distribution[tries] = count
+	}
+
+	return distribution, rows.Err()
+}
+
+// GetStartWords возвращает самые частые стартовые слова игрока за [from, to)
+func (r *PlayerStatsRepository) GetStartWords(ctx context.Context, userID uint64, from, to time.Time, limit int) ([]models.WordCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT word, SUM(count) AS total
+		FROM player_daily_start_words
+		WHERE user_id = $1 AND day >= $2 AND day < $3
+		GROUP BY word
+		ORDER BY total DESC, word
+		LIMIT $4
+	`, userID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get start words: %w", err)
+	}
+	defer rows.Close()
+
+	var words []models.WordCount
+	for rows.Next() {
+		var word models.WordCount
+		if err := rows.Scan(&word.Word, &word.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan start word: %w", err)
+		}
+		words = append(words, word)
+	}
+
+	return words, rows.Err()
+}
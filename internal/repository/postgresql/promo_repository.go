@@ -0,0 +1,265 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// PromoRepository представляет собой реализацию репозитория для работы с промокодами
+type PromoRepository struct {
+	db *sql.DB
+}
+
+// NewPromoRepository создает новый экземпляр PromoRepository
+func NewPromoRepository(db *sql.DB) *PromoRepository {
+	return &PromoRepository{
+		db: db,
+	}
+}
+
+const promoColumns = `id, code, type, currency, amount, wager_multiplier, max_redemptions, per_user_limit,
+	redemptions, expires_at, active, created_by, created_at`
+
+// Create создает промокод
+func (r *PromoRepository) Create(ctx context.Context, promo *models.PromoCode) error {
+	if promo.ID == uuid.Nil {
+		promo.ID = uuid.New()
+	}
+	promo.CreatedAt = time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO promo_codes (`+promoColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (code) DO NOTHING
+	`, promo.ID, promo.Code, promo.Type, promo.Currency, promo.Amount, promo.WagerMultiplier,
+		promo.MaxRedemptions, promo.PerUserLimit, promo.Redemptions, promo.ExpiresAt, promo.Active,
+		promo.CreatedBy, promo.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create promo code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrPromoCodeExists
+	}
+
+	return nil
+}
+
+// GetByCode получает промокод по коду
+func (r *PromoRepository) GetByCode(ctx context.Context, code string) (*models.PromoCode, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+promoColumns+` FROM promo_codes WHERE code = $1`, code)
+	promo, err := scanPromoCode(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrPromoCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get promo code: %w", err)
+	}
+	return promo, nil
+}
+
+// GetAll возвращает промокоды, начиная с последних
+func (r *PromoRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.PromoCode, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+promoColumns+` FROM promo_codes
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promo codes: %w", err)
+	}
+	defer rows.Close()
+
+	var promos []*models.PromoCode
+	for rows.Next() {
+		promo, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan promo code: %w", err)
+		}
+		promos = append(promos, promo)
+	}
+
+	return promos, rows.Err()
+}
+
+// SetActive включает или выключает промокод
+func (r *PromoRepository) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE promo_codes SET active = $1 WHERE id = $2`, active, id)
+	if err != nil {
+		return fmt.Errorf("failed to update promo code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrPromoCodeNotFound
+	}
+
+	return nil
+}
+
+// Redeem атомарно проверяет срок и лимиты промокода и записывает активацию.
+// Строка промокода блокируется, поэтому параллельные активации не превышают лимиты.
+// Если транзакция уже открыта, активация записывается в ней
+func (r *PromoRepository) Redeem(ctx context.Context, code string, redemption *models.PromoRedemption) (*models.PromoCode, error) {
+	var promo *models.PromoCode
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		row := tx.QueryRowContext(ctx, `SELECT `+promoColumns+` FROM promo_codes WHERE code = $1 FOR UPDATE`, code)
+		var err error
+		promo, err = scanPromoCode(row)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.ErrPromoCodeNotFound
+			}
+			return fmt.Errorf("failed to get promo code: %w", err)
+		}
+
+		now := time.Now()
+		switch {
+		case !promo.Active:
+			return models.ErrPromoCodeInactive
+		case promo.IsExpired(now):
+			return models.ErrPromoCodeExpired
+		case promo.MaxRedemptions > 0 && promo.Redemptions >= promo.MaxRedemptions:
+			return models.ErrPromoCodeExhausted
+		}
+
+		var userRedemptions int
+		err = tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM promo_redemptions WHERE promo_code_id = $1 AND user_id = $2
+		`, promo.ID, redemption.UserID).Scan(&userRedemptions)
+		if err != nil {
+			return fmt.Errorf("failed to count promo redemptions: %w", err)
+		}
+		if userRedemptions >= promo.PerUserLimit {
+			return models.ErrPromoCodeRedeemed
+		}
+
+		if redemption.ID == uuid.Nil {
+			redemption.ID = uuid.New()
+		}
+		redemption.PromoCodeID = promo.ID
+		redemption.Code = promo.Code
+		redemption.Currency = promo.Currency
+		redemption.Amount = promo.Amount
+		redemption.Wager = promo.Wager()
+		redemption.CreatedAt = now
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO promo_redemptions (id, promo_code_id, code, user_id, currency, amount, wager, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, redemption.ID, redemption.PromoCodeID, redemption.Code, redemption.UserID, redemption.Currency,
+			redemption.Amount, redemption.Wager, redemption.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create promo redemption: %w", err)
+		}
+
+		if _, err = tx.ExecContext(ctx, `UPDATE promo_codes SET redemptions = redemptions + 1 WHERE id = $1`, promo.ID); err != nil {
+			return fmt.Errorf("failed to update promo code: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	promo.Redemptions++
+	return promo, nil
+}
+
+// GetRedemptionsByUser возвращает активации промокодов пользователем, начиная с последних
+func (r *PromoRepository) GetRedemptionsByUser(ctx context.Context, userID uint64, limit, offset int) ([]*models.PromoRedemption, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, promo_code_id, code, user_id, currency, amount, wager, created_at
+		FROM promo_redemptions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promo redemptions: %w", err)
+	}
+	defer rows.Close()
+
+	var redemptions []*models.PromoRedemption
+	for rows.Next() {
+		var redemption models.PromoRedemption
+		if err := rows.Scan(&redemption.ID, &redemption.PromoCodeID, &redemption.Code, &redemption.UserID,
+			&redemption.Currency, &redemption.Amount, &redemption.Wager, &redemption.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan promo redemption: %w", err)
+		}
+		redemptions = append(redemptions, &redemption)
+	}
+
+	return redemptions, rows.Err()
+}
+
+// CreateBonusStake записывает часть ставки лобби, оплаченную бонусными средствами
+func (r *PromoRepository) CreateBonusStake(ctx context.Context, stake *models.BonusStake) error {
+	stake.CreatedAt = time.Now()
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO bonus_stakes (lobby_id, user_id, currency, bet_amount, bonus_amount, settled, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, stake.LobbyID, stake.UserID, stake.Currency, stake.BetAmount, stake.BonusAmount, stake.Settled, stake.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create bonus stake: %w", err)
+	}
+	return nil
+}
+
+// DeleteBonusStake удаляет бонусную ставку лобби (при откате создания лобби)
+func (r *PromoRepository) DeleteBonusStake(ctx context.Context, lobbyID uuid.UUID) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM bonus_stakes WHERE lobby_id = $1`, lobbyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bonus stake: %w", err)
+	}
+	return nil
+}
+
+// SettleBonusStake атомарно помечает бонусную ставку лобби рассчитанной и возвращает её
+func (r *PromoRepository) SettleBonusStake(ctx context.Context, lobbyID uuid.UUID) (*models.BonusStake, error) {
+	var stake models.BonusStake
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		UPDATE bonus_stakes SET settled = TRUE
+		WHERE lobby_id = $1 AND NOT settled
+		RETURNING lobby_id, user_id, currency, bet_amount, bonus_amount, settled, created_at
+	`, lobbyID).Scan(&stake.LobbyID, &stake.UserID, &stake.Currency, &stake.BetAmount, &stake.BonusAmount,
+		&stake.Settled, &stake.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to settle bonus stake: %w", err)
+	}
+	return &stake, nil
+}
+
+// scanPromoCode считывает промокод из строки результата
+func scanPromoCode(row interface{ Scan(dest ...any) error }) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	var expiresAt sql.NullTime
+	err := row.Scan(&promo.ID, &promo.Code, &promo.Type, &promo.Currency, &promo.Amount, &promo.WagerMultiplier,
+		&promo.MaxRedemptions, &promo.PerUserLimit, &promo.Redemptions, &expiresAt, &promo.Active,
+		&promo.CreatedBy, &promo.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		promo.ExpiresAt = &expiresAt.Time
+	}
+	return &promo, nil
+}
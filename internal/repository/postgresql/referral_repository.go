@@ -0,0 +1,260 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// ReferralRepository представляет собой реализацию репозитория для работы с реферальной программой
+type ReferralRepository struct {
+	db *sql.DB
+}
+
+// NewReferralRepository создает новый экземпляр ReferralRepository
+func NewReferralRepository(db *sql.DB) *ReferralRepository {
+	return &ReferralRepository{
+		db: db,
+	}
+}
+
+// EnsureCode сохраняет код пользователя, если его ещё нет, и возвращает сохранённый код.
+// При совпадении кода с кодом другого пользователя возвращает ошибку уникальности
+func (r *ReferralRepository) EnsureCode(ctx context.Context, userID uint64, code string) (string, error) {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO referral_codes (user_id, code, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID, code, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to create referral code: %w", err)
+	}
+
+	var stored string
+	err = r.db.QueryRowContext(ctx, `SELECT code FROM referral_codes WHERE user_id = $1`, userID).Scan(&stored)
+	if err != nil {
+		return "", fmt.Errorf("failed to get referral code: %w", err)
+	}
+
+	return stored, nil
+}
+
+// GetUserByCode возвращает владельца реферального кода
+func (r *ReferralRepository) GetUserByCode(ctx context.Context, code string) (uint64, error) {
+	var userID uint64
+	err := r.db.QueryRowContext(ctx, `SELECT user_id FROM referral_codes WHERE code = $1`, code).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrReferralNotFound
+		}
+		return 0, fmt.Errorf("failed to get referral code owner: %w", err)
+	}
+	return userID, nil
+}
+
+// Create записывает реферала. Возвращает false, если у пользователя уже есть реферер
+func (r *ReferralRepository) Create(ctx context.Context, referral *models.Referral) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO referrals (referee_id, referrer_id, code, status, block_reason, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (referee_id) DO NOTHING
+	`, referral.RefereeID, referral.ReferrerID, referral.Code, referral.Status, referral.BlockReason,
+		referral.CreatedAt, referral.ExpiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create referral: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetByReferee возвращает реферера пользователя
+func (r *ReferralRepository) GetByReferee(ctx context.Context, refereeID uint64) (*models.Referral, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT referee_id, referrer_id, code, status, block_reason, created_at, expires_at
+		FROM referrals WHERE referee_id = $1
+	`, refereeID)
+
+	referral, err := scanReferral(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrReferralNotFound
+		}
+		return nil, fmt.Errorf("failed to get referral: %w", err)
+	}
+	return referral, nil
+}
+
+// GetByReferrer возвращает пользователей, приглашённых реферером, начиная с последних
+func (r *ReferralRepository) GetByReferrer(ctx context.Context, referrerID uint64, limit, offset int) ([]*models.Referral, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT referee_id, referrer_id, code, status, block_reason, created_at, expires_at
+		FROM referrals WHERE referrer_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, referrerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referrals: %w", err)
+	}
+	defer rows.Close()
+
+	var referrals []*models.Referral
+	for rows.Next() {
+		referral, err := scanReferral(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan referral: %w", err)
+		}
+		referrals = append(referrals, referral)
+	}
+
+	return referrals, rows.Err()
+}
+
+// Block блокирует реферала, после чего реферер перестаёт получать долю комиссии
+func (r *ReferralRepository) Block(ctx context.Context, refereeID uint64, reason string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE referrals SET status = $1, block_reason = $2 WHERE referee_id = $3
+	`, models.ReferralStatusBlocked, reason, refereeID)
+	if err != nil {
+		return fmt.Errorf("failed to block referral: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrReferralNotFound
+	}
+
+	return nil
+}
+
+// AddEarning записывает начисление рефереру.
+// Уникальный индекс по commission_entry_id гарантирует одно начисление на запись комиссии
+func (r *ReferralRepository) AddEarning(ctx context.Context, earning *models.ReferralEarning) (bool, error) {
+	if earning.ID == uuid.Nil {
+		earning.ID = uuid.New()
+	}
+	if earning.Status == "" {
+		earning.Status = models.ReferralEarningPending
+	}
+	earning.CreatedAt = time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO referral_earnings (id, commission_entry_id, referrer_id, referee_id, currency, amount, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (commission_entry_id) DO NOTHING
+	`, earning.ID, earning.CommissionEntryID, earning.ReferrerID, earning.RefereeID,
+		earning.Currency, earning.Amount, earning.Status, earning.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create referral earning: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetPendingTotals возвращает суммы невыплаченных начислений по рефералам и валютам
+func (r *ReferralRepository) GetPendingTotals(ctx context.Context, limit int) ([]*models.ReferralEarningTotal, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT referrer_id, referee_id, currency, COALESCE(SUM(amount), 0)
+		FROM referral_earnings
+		WHERE status = $1
+		GROUP BY referrer_id, referee_id, currency
+		ORDER BY MIN(created_at)
+		LIMIT $2
+	`, models.ReferralEarningPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending referral earnings: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []*models.ReferralEarningTotal
+	for rows.Next() {
+		total := models.ReferralEarningTotal{Status: models.ReferralEarningPending}
+		if err := rows.Scan(&total.ReferrerID, &total.RefereeID, &total.Currency, &total.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan pending referral earnings: %w", err)
+		}
+		totals = append(totals, &total)
+	}
+
+	return totals, rows.Err()
+}
+
+// ClaimPending атомарно помечает невыплаченные начисления реферала в валюте выплаченными и возвращает их сумму.
+// Параллельные вызовы не могут забрать одно начисление дважды
+func (r *ReferralRepository) ClaimPending(ctx context.Context, refereeID uint64, currency string) (float64, error) {
+	var amount float64
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		WITH claimed AS (
+			UPDATE referral_earnings SET status = $1, credited_at = $2
+			WHERE referee_id = $3 AND currency = $4 AND status = $5
+			RETURNING amount
+		)
+		SELECT COALESCE(SUM(amount), 0) FROM claimed
+	`, models.ReferralEarningCredited, time.Now(), refereeID, currency, models.ReferralEarningPending).Scan(&amount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim referral earnings: %w", err)
+	}
+	return amount, nil
+}
+
+// CancelPending отменяет невыплаченные начисления реферала
+func (r *ReferralRepository) CancelPending(ctx context.Context, refereeID uint64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE referral_earnings SET status = $1 WHERE referee_id = $2 AND status = $3
+	`, models.ReferralEarningCanceled, refereeID, models.ReferralEarningPending)
+	if err != nil {
+		return fmt.Errorf("failed to cancel referral earnings: %w", err)
+	}
+	return nil
+}
+
+// GetTotalsByReferrer возвращает суммы начислений реферера по рефералам, валютам и статусам
+func (r *ReferralRepository) GetTotalsByReferrer(ctx context.Context, referrerID uint64) ([]*models.ReferralEarningTotal, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT referrer_id, referee_id, currency, status, COALESCE(SUM(amount), 0)
+		FROM referral_earnings
+		WHERE referrer_id = $1
+		GROUP BY referrer_id, referee_id, currency, status
+	`, referrerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral earnings: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []*models.ReferralEarningTotal
+	for rows.Next() {
+		var total models.ReferralEarningTotal
+		if err := rows.Scan(&total.ReferrerID, &total.RefereeID, &total.Currency, &total.Status, &total.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan referral earnings: %w", err)
+		}
+		totals = append(totals, &total)
+	}
+
+	return totals, rows.Err()
+}
+
+// scanReferral считывает реферала из строки результата
+func scanReferral(row interface{ Scan(dest ...any) error }) (*models.Referral, error) {
+	var referral models.Referral
+	err := row.Scan(&referral.RefereeID, &referral.ReferrerID, &referral.Code, &referral.Status,
+		&referral.BlockReason, &referral.CreatedAt, &referral.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
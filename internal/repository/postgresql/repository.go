@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/TakuroBreath/wordle/internal/models"
@@ -10,12 +11,27 @@ import (
 type Repository struct {
 	db *sql.DB
 
-	game        models.GameRepository
-	user        models.UserRepository
-	lobby       models.LobbyRepository
-	attempt     models.AttemptRepository
-	history     models.HistoryRepository
-	transaction models.TransactionRepository
+	game         models.GameRepository
+	user         models.UserRepository
+	lobby        models.LobbyRepository
+	attempt      models.AttemptRepository
+	history      models.HistoryRepository
+	transaction  models.TransactionRepository
+	sideBet      models.SideBetRepository
+	duel         models.DuelRepository
+	jackpot      models.JackpotRepository
+	commission   models.CommissionRepository
+	referral     models.ReferralRepository
+	promo        models.PromoRepository
+	achievement  models.AchievementRepository
+	leaderboard  models.LeaderboardRepository
+	playerStats  models.PlayerStatsRepository
+	analytics    models.AnalyticsRepository
+	reputation   models.ReputationRepository
+	follow       models.FollowRepository
+	notification models.NotificationRepository
+	event        models.EventRepository
+	webhook      models.WebhookRepository
 }
 
 // NewRepository создает новый экземпляр Repository
@@ -73,7 +89,132 @@ func (r *Repository) Transaction() models.TransactionRepository {
 	return r.transaction
 }
 
+// SideBet возвращает репозиторий для работы со ставками зрителей
+func (r *Repository) SideBet() models.SideBetRepository {
+	if r.sideBet == nil {
+		r.sideBet = NewSideBetRepository(r.db)
+	}
+	return r.sideBet
+}
+
 // Close закрывает соединение с базой данных
 func (r *Repository) Close() error {
 	return r.db.Close()
 }
+
+// Duel возвращает репозиторий для работы с дуэлями
+func (r *Repository) Duel() models.DuelRepository {
+	if r.duel == nil {
+		r.duel = NewDuelRepository(r.db)
+	}
+	return r.duel
+}
+
+// Jackpot возвращает репозиторий для работы с джекпотом
+func (r *Repository) Jackpot() models.JackpotRepository {
+	if r.jackpot == nil {
+		r.jackpot = NewJackpotRepository(r.db)
+	}
+	return r.jackpot
+}
+
+// Commission возвращает репозиторий для работы с комиссией
+func (r *Repository) Commission() models.CommissionRepository {
+	if r.commission == nil {
+		r.commission = NewCommissionRepository(r.db)
+	}
+	return r.commission
+}
+
+// Referral возвращает репозиторий для работы с реферальной программой
+func (r *Repository) Referral() models.ReferralRepository {
+	if r.referral == nil {
+		r.referral = NewReferralRepository(r.db)
+	}
+	return r.referral
+}
+
+// Promo возвращает репозиторий для работы с промокодами
+func (r *Repository) Promo() models.PromoRepository {
+	if r.promo == nil {
+		r.promo = NewPromoRepository(r.db)
+	}
+	return r.promo
+}
+
+// Achievement возвращает репозиторий для работы с достижениями и сериями
+func (r *Repository) Achievement() models.AchievementRepository {
+	if r.achievement == nil {
+		r.achievement = NewAchievementRepository(r.db)
+	}
+	return r.achievement
+}
+
+// Leaderboard возвращает репозиторий для работы с таблицами лидеров
+func (r *Repository) Leaderboard() models.LeaderboardRepository {
+	if r.leaderboard == nil {
+		r.leaderboard = NewLeaderboardRepository(r.db)
+	}
+	return r.leaderboard
+}
+
+// PlayerStats возвращает репозиторий для работы с агрегатами статистики игроков
+func (r *Repository) PlayerStats() models.PlayerStatsRepository {
+	if r.playerStats == nil {
+		r.playerStats = NewPlayerStatsRepository(r.db)
+	}
+	return r.playerStats
+}
+
+// Analytics возвращает репозиторий для аналитики создателей
+func (r *Repository) Analytics() models.AnalyticsRepository {
+	if r.analytics == nil {
+		r.analytics = NewAnalyticsRepository(r.db)
+	}
+	return r.analytics
+}
+
+// Reputation возвращает репозиторий для работы с отзывами и репутацией создателей
+func (r *Repository) Reputation() models.ReputationRepository {
+	if r.reputation == nil {
+		r.reputation = NewReputationRepository(r.db)
+	}
+	return r.reputation
+}
+
+// Follow возвращает репозиторий для работы с подписками, закладками и лентой игр
+func (r *Repository) Follow() models.FollowRepository {
+	if r.follow == nil {
+		r.follow = NewFollowRepository(r.db)
+	}
+	return r.follow
+}
+
+// Notification возвращает репозиторий для работы с очередью уведомлений и настройками пользователей
+func (r *Repository) Notification() models.NotificationRepository {
+	if r.notification == nil {
+		r.notification = NewNotificationRepository(r.db)
+	}
+	return r.notification
+}
+
+// Event возвращает репозиторий для работы с outbox доменных событий
+func (r *Repository) Event() models.EventRepository {
+	if r.event == nil {
+		r.event = NewEventRepository(r.db)
+	}
+	return r.event
+}
+
+// Webhook возвращает репозиторий для работы с webhook и журналом их доставок
+func (r *Repository) Webhook() models.WebhookRepository {
+	if r.webhook == nil {
+		r.webhook = NewWebhookRepository(r.db)
+	}
+	return r.webhook
+}
+
+// WithinTx выполняет fn в транзакции БД. В ней участвуют методы репозиториев, которые получают соединение через conn
+func (r *Repository) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTx(ctx, r.db, fn)
+}
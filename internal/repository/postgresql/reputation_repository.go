@@ -0,0 +1,314 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// ReputationRepository представляет собой реализацию репозитория для работы с отзывами и репутацией создателей
+type ReputationRepository struct {
+	db *sql.DB
+}
+
+// NewReputationRepository создает новый экземпляр ReputationRepository
+func NewReputationRepository(db *sql.DB) *ReputationRepository {
+	return &ReputationRepository{
+		db: db,
+	}
+}
+
+const reviewColumns = `id, game_id, creator_id, user_id, rating, review, status, moderation_note,
+	COALESCE(moderated_by, 0), moderated_at, created_at, updated_at`
+
+// Статусы лобби, учитываемые в доле доигранных лобби создателя
+const (
+	reputationPlayedStatuses    = `'success', 'failed_tries', 'cashed_out'`
+	reputationAbandonedStatuses = `'failed_expired', 'canceled'`
+)
+
+// SaveReview создаёт оценку игрока или обновляет его прежнюю оценку игры.
+// Статус и решение модератора при обновлении не меняются
+func (r *ReputationRepository) SaveReview(ctx context.Context, review *models.GameReview) error {
+	if review.ID == uuid.Nil {
+		review.ID = uuid.New()
+	}
+	now := time.Now()
+
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO game_reviews (id, game_id, creator_id, user_id, rating, review, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (game_id, user_id) DO UPDATE
+		SET rating = EXCLUDED.rating, review = EXCLUDED.review, updated_at = EXCLUDED.updated_at
+		RETURNING `+reviewColumns,
+		review.ID, review.GameID, review.CreatorID, review.UserID, review.Rating, review.Review,
+		models.ReviewStatusVisible, now)
+
+	saved, err := scanReview(row)
+	if err != nil {
+		return fmt.Errorf("failed to save review: %w", err)
+	}
+	*review = *saved
+	return nil
+}
+
+// GetReview получает отзыв по ID
+func (r *ReputationRepository) GetReview(ctx context.Context, id uuid.UUID) (*models.GameReview, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+reviewColumns+` FROM game_reviews WHERE id = $1`, id)
+	review, err := scanReview(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return review, nil
+}
+
+// GetGameReviews получает опубликованные отзывы об игре от новых к старым
+func (r *ReputationRepository) GetGameReviews(ctx context.Context, gameID uuid.UUID, limit, offset int) ([]*models.GameReview, error) {
+	return r.queryReviews(ctx, `
+		SELECT `+reviewColumns+` FROM game_reviews
+		WHERE game_id = $1 AND status = 'visible'
+		ORDER BY updated_at DESC
+		LIMIT $2 OFFSET $3
+	`, gameID, limit, offset)
+}
+
+// GetReviewsByStatus получает отзывы с указанным статусом (пустой статус - все) от новых к старым
+func (r *ReputationRepository) GetReviewsByStatus(ctx context.Context, status string, limit, offset int) ([]*models.GameReview, error) {
+	return r.queryReviews(ctx, `
+		SELECT `+reviewColumns+` FROM game_reviews
+		WHERE $1 = '' OR status = $1
+		ORDER BY updated_at DESC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+}
+
+// ModerateReview меняет статус отзыва и записывает решение модератора
+func (r *ReputationRepository) ModerateReview(ctx context.Context, id uuid.UUID, status, note string, moderatorID uint64, at time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE game_reviews
+		SET status = $1, moderation_note = $2, moderated_by = $3, moderated_at = $4
+		WHERE id = $5
+	`, status, note, moderatorID, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to moderate review: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrReviewNotFound
+	}
+
+	return nil
+}
+
+// GetGameRating возвращает сводку опубликованных оценок игры
+func (r *ReputationRepository) GetGameRating(ctx context.Context, gameID uuid.UUID) (*models.GameRating, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT rating, COUNT(*) FROM game_reviews
+		WHERE game_id = $1 AND status = 'visible'
+		GROUP BY rating
+	`, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game rating: %w", err)
+	}
+	defer rows.Close()
+
+	rating := &models.GameRating{GameID: gameID, Distribution: make(map[int]int)}
+	var sum int
+	for rows.Next() {
+		var value, count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan game rating: %w", err)
+		}
+		rating.Distribution[value] = count
+		rating.Count += count
+		sum += value * count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating game rating: %w", err)
+	}
+
+	if rating.Count > 0 {
+		rating.Average = float64(sum) / float64(rating.Count)
+	}
+	return rating, nil
+}
+
+// HasFinishedLobby проверяет, что у игрока есть запись истории по игре
+func (r *ReputationRepository) HasFinishedLobby(ctx context.Context, gameID uuid.UUID, userID uint64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM history WHERE game_id = $1 AND user_id = $2)
+	`, gameID, userID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check finished lobby: %w", err)
+	}
+	return exists, nil
+}
+
+// CreateDispute записывает исход спора
+func (r *ReputationRepository) CreateDispute(ctx context.Context, dispute *models.CreatorDispute) error {
+	if dispute.ID == uuid.Nil {
+		dispute.ID = uuid.New()
+	}
+	dispute.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO creator_disputes (id, creator_id, user_id, lobby_id, outcome, note, resolved_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, dispute.ID, dispute.CreatorID, dispute.UserID, dispute.LobbyID, dispute.Outcome, dispute.Note,
+		dispute.ResolvedBy, dispute.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create dispute: %w", err)
+	}
+	return nil
+}
+
+// GetReputationInputs собирает оценки, исходы лобби, возвраты ставок и споры по играм создателя
+func (r *ReputationRepository) GetReputationInputs(ctx context.Context, creatorID uint64) (*models.ReputationInputs, error) {
+	var inputs models.ReputationInputs
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE((SELECT SUM(rating) FROM game_reviews WHERE creator_id = $1 AND status = 'visible'), 0),
+			(SELECT COUNT(*) FROM game_reviews WHERE creator_id = $1 AND status = 'visible'),
+			(SELECT COUNT(*) FROM lobbies l JOIN games g ON g.id = l.game_id
+				WHERE g.creator_id = $1 AND l.status IN (`+reputationPlayedStatuses+`, `+reputationAbandonedStatuses+`)),
+			(SELECT COUNT(*) FROM lobbies l JOIN games g ON g.id = l.game_id
+				WHERE g.creator_id = $1 AND l.status IN (`+reputationPlayedStatuses+`)),
+			(SELECT COUNT(*) FROM transactions t JOIN games g ON g.id = t.game_id
+				WHERE g.creator_id = $1 AND t.type = 'refund'),
+			(SELECT COUNT(*) FROM creator_disputes WHERE creator_id = $1),
+			(SELECT COUNT(*) FROM creator_disputes WHERE creator_id = $1 AND outcome = 'player')
+	`, creatorID).Scan(
+		&inputs.RatingSum,
+		&inputs.RatingCount,
+		&inputs.Lobbies,
+		&inputs.Completed,
+		&inputs.Refunds,
+		&inputs.Disputes,
+		&inputs.DisputesLost,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reputation inputs: %w", err)
+	}
+	return &inputs, nil
+}
+
+// SaveReputation сохраняет рассчитанную репутацию создателя
+func (r *ReputationRepository) SaveReputation(ctx context.Context, reputation *models.CreatorReputation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO creator_reputations (creator_id, score, rating_average, rating_count, completion_rate,
+			refund_rate, disputes, disputes_lost, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (creator_id) DO UPDATE
+		SET score = EXCLUDED.score, rating_average = EXCLUDED.rating_average, rating_count = EXCLUDED.rating_count,
+			completion_rate = EXCLUDED.completion_rate, refund_rate = EXCLUDED.refund_rate,
+			disputes = EXCLUDED.disputes, disputes_lost = EXCLUDED.disputes_lost, updated_at = EXCLUDED.updated_at
+	`, reputation.CreatorID, reputation.Score, reputation.RatingAverage, reputation.RatingCount,
+		reputation.CompletionRate, reputation.RefundRate, reputation.Disputes, reputation.DisputesLost,
+		reputation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save reputation: %w", err)
+	}
+	return nil
+}
+
+// GetReputation получает сохранённую репутацию создателя
+func (r *ReputationRepository) GetReputation(ctx context.Context, creatorID uint64) (*models.CreatorReputation, error) {
+	var reputation models.CreatorReputation
+	err := r.db.QueryRowContext(ctx, `
+		SELECT creator_id, score, rating_average, rating_count, completion_rate, refund_rate,
+			disputes, disputes_lost, updated_at
+		FROM creator_reputations
+		WHERE creator_id = $1
+	`, creatorID).Scan(
+		&reputation.CreatorID,
+		&reputation.Score,
+		&reputation.RatingAverage,
+		&reputation.RatingCount,
+		&reputation.CompletionRate,
+		&reputation.RefundRate,
+		&reputation.Disputes,
+		&reputation.DisputesLost,
+		&reputation.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrReputationNotFound
+		}
+		return nil, fmt.Errorf("failed to get reputation: %w", err)
+	}
+	return &reputation, nil
+}
+
+// GetStaleCreators возвращает создателей, у которых после расчёта репутации завершились лобби или были возвраты ставок
+func (r *ReputationRepository) GetStaleCreators(ctx context.Context, limit int) ([]uint64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.creator_id
+		FROM games g
+		LEFT JOIN creator_reputations cr ON cr.creator_id = g.creator_id
+		WHERE EXISTS (SELECT 1 FROM history h WHERE h.game_id = g.id AND (cr.updated_at IS NULL OR h.created_at > cr.updated_at))
+			OR EXISTS (SELECT 1 FROM transactions t WHERE t.game_id = g.id AND t.type = 'refund'
+				AND (cr.updated_at IS NULL OR t.created_at > cr.updated_at))
+		GROUP BY g.creator_id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale creators: %w", err)
+	}
+	defer rows.Close()
+
+	var creators []uint64
+	for rows.Next() {
+		var creatorID uint64
+		if err := rows.Scan(&creatorID); err != nil {
+			return nil, fmt.Errorf("failed to scan creator: %w", err)
+		}
+		creators = append(creators, creatorID)
+	}
+
+	return creators, rows.Err()
+}
+
+// queryReviews выполняет выборку отзывов с колонками reviewColumns
+func (r *ReputationRepository) queryReviews(ctx context.Context, query string, args ...any) ([]*models.GameReview, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*models.GameReview
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// scanReview сканирует строку, выбранную с reviewColumns
+func scanReview(row rowScanner) (*models.GameReview, error) {
+	var review models.GameReview
+	err := row.Scan(&review.ID, &review.GameID, &review.CreatorID, &review.UserID, &review.Rating, &review.Review,
+		&review.Status, &review.ModerationNote, &review.ModeratedBy, &review.ModeratedAt,
+		&review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
@@ -0,0 +1,264 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+const sideBetColumns = `id, lobby_id, game_id, user_id, within_tries, placed_at_try, amount, odds,
+			payout, currency, status, settled_at, created_at, updated_at`
+
+// SideBetRepository представляет собой реализацию репозитория для работы со ставками зрителей
+type SideBetRepository struct {
+	db *sql.DB
+}
+
+// NewSideBetRepository создает новый экземпляр SideBetRepository
+func NewSideBetRepository(db *sql.DB) *SideBetRepository {
+	return &SideBetRepository{
+		db: db,
+	}
+}
+
+// scanSideBet считывает ставку из строки результата
+func scanSideBet(row rowScanner) (*models.SideBet, error) {
+	var bet models.SideBet
+	var settledAt sql.NullTime
+
+	err := row.Scan(
+		&bet.ID,
+		&bet.LobbyID,
+		&bet.GameID,
+		&bet.UserID,
+		&bet.WithinTries,
+		&bet.PlacedAtTry,
+		&bet.Amount,
+		&bet.Odds,
+		&bet.Payout,
+		&bet.Currency,
+		&bet.Status,
+		&settledAt,
+		&bet.CreatedAt,
+		&bet.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if settledAt.Valid {
+		bet.SettledAt = &settledAt.Time
+	}
+
+	return &bet, nil
+}
+
+// querySideBets выполняет запрос и считывает список ставок
+func (r *SideBetRepository) querySideBets(ctx context.Context, query string, args ...any) ([]*models.SideBet, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get side bets: %w", err)
+	}
+	defer rows.Close()
+
+	var bets []*models.SideBet
+	for rows.Next() {
+		bet, err := scanSideBet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan side bet: %w", err)
+		}
+		bets = append(bets, bet)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating side bets: %w", err)
+	}
+
+	return bets, nil
+}
+
+// Place записывает ставку зрителя и зачисляет её на счёт рынка в одной транзакции.
+// Строка лобби блокируется (FOR SHARE), и ставка записывается, только если лобби активно и tries_used
+// совпадает с попыткой котировки: попытка игрока, сделанная параллельно, закрывает рынок для старых котировок
+func (r *SideBetRepository) Place(ctx context.Context, bet *models.SideBet) (bool, error) {
+	if bet.ID == uuid.Nil {
+		bet.ID = uuid.New()
+	}
+
+	now := time.Now()
+	bet.CreatedAt = now
+	bet.UpdatedAt = now
+
+	var placed bool
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		result, err := conn(ctx, r.db).ExecContext(ctx, `
+			INSERT INTO side_bets (`+sideBetColumns+`)
+			SELECT $1, l.id, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			FROM (SELECT id FROM lobbies WHERE id = $2 AND status = $15 AND tries_used = $6 FOR SHARE) l
+		`,
+			bet.ID,
+			bet.LobbyID,
+			bet.GameID,
+			bet.UserID,
+			bet.WithinTries,
+			bet.PlacedAtTry,
+			bet.Amount,
+			bet.Odds,
+			bet.Payout,
+			bet.Currency,
+			bet.Status,
+			bet.SettledAt,
+			bet.CreatedAt,
+			bet.UpdatedAt,
+			models.LobbyStatusActive,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create side bet: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			// Лобби завершено или игрок уже сделал следующую попытку
+			return nil
+		}
+
+		placed = true
+		return r.adjustAccount(ctx, bet.Currency, bet.Amount)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return placed, nil
+}
+
+// GetByID получает ставку по ID
+func (r *SideBetRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SideBet, error) {
+	query := `SELECT ` + sideBetColumns + ` FROM side_bets WHERE id = $1`
+
+	bet, err := scanSideBet(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrSideBetNotFound
+		}
+		return nil, fmt.Errorf("failed to get side bet: %w", err)
+	}
+
+	return bet, nil
+}
+
+// GetByLobbyID получает все ставки на лобби
+func (r *SideBetRepository) GetByLobbyID(ctx context.Context, lobbyID uuid.UUID) ([]*models.SideBet, error) {
+	query := `
+		SELECT ` + sideBetColumns + `
+		FROM side_bets
+		WHERE lobby_id = $1
+		ORDER BY created_at ASC
+	`
+
+	return r.querySideBets(ctx, query, lobbyID)
+}
+
+// GetOpenByLobbyID получает нерассчитанные ставки на лобби
+func (r *SideBetRepository) GetOpenByLobbyID(ctx context.Context, lobbyID uuid.UUID) ([]*models.SideBet, error) {
+	query := `
+		SELECT ` + sideBetColumns + `
+		FROM side_bets
+		WHERE lobby_id = $1 AND status = $2
+		ORDER BY created_at ASC
+	`
+
+	return r.querySideBets(ctx, query, lobbyID, models.SideBetStatusOpen)
+}
+
+// GetByUserID получает ставки зрителя с пагинацией
+func (r *SideBetRepository) GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*models.SideBet, error) {
+	query := `
+		SELECT ` + sideBetColumns + `
+		FROM side_bets
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.querySideBets(ctx, query, userID, limit, offset)
+}
+
+// Settle атомарно переводит открытую ставку в финальный статус и списывает выплату со счёта рынка
+func (r *SideBetRepository) Settle(ctx context.Context, id uuid.UUID, status string, payout float64) (bool, error) {
+	query := `
+		UPDATE side_bets
+		SET status = $1, payout = $2, settled_at = $3, updated_at = $3
+		WHERE id = $4 AND status = $5
+		RETURNING currency
+	`
+
+	var settled bool
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		var currency string
+		err := conn(ctx, r.db).QueryRowContext(ctx, query, status, payout, time.Now(), id, models.SideBetStatusOpen).Scan(&currency)
+		if err == sql.ErrNoRows {
+			// Ставка уже рассчитана
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to settle side bet: %w", err)
+		}
+
+		settled = true
+		if payout <= 0 {
+			return nil
+		}
+		return r.adjustAccount(ctx, currency, -payout)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return settled, nil
+}
+
+// GetAccounts возвращает счета рынка ставок во всех валютах
+func (r *SideBetRepository) GetAccounts(ctx context.Context) ([]*models.SideBetAccount, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT currency, balance, updated_at FROM side_bet_accounts ORDER BY currency`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get side bet accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*models.SideBetAccount
+	for rows.Next() {
+		var account models.SideBetAccount
+		if err := rows.Scan(&account.Currency, &account.Balance, &account.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan side bet account: %w", err)
+		}
+		accounts = append(accounts, &account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// adjustAccount изменяет баланс счёта рынка в валюте: ставки зачисляются, выплаты списываются
+func (r *SideBetRepository) adjustAccount(ctx context.Context, currency string, delta float64) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO side_bet_accounts (currency, balance, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (currency) DO UPDATE
+		SET balance = side_bet_accounts.balance + EXCLUDED.balance, updated_at = EXCLUDED.updated_at
+	`, currency, delta, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update side bet account: %w", err)
+	}
+	return nil
+}
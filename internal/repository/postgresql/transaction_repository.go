@@ -44,11 +44,11 @@ func (r *TransactionRepository) Create(ctx context.Context, transaction *models.
 	}
 	transaction.UpdatedAt = now
 
-	_, err := r.db.ExecContext(
+	_, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		transaction.ID,
-		transaction.UserID,
+		nullableUserID(transaction.UserID),
 		transaction.Amount,
 		transaction.Type,
 		transaction.Status,
@@ -72,7 +72,7 @@ func (r *TransactionRepository) Create(ctx context.Context, transaction *models.
 // GetByID получает транзакцию по ID
 func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
 	query := `
-		SELECT id, user_id, amount, type, status, currency, description, tx_hash, 
+		SELECT id, COALESCE(user_id, 0), amount, type, status, currency, description, tx_hash, 
 		       network, game_id, lobby_id, created_at, updated_at
 		FROM transactions
 		WHERE id = $1
@@ -166,10 +166,59 @@ func (r *TransactionRepository) GetByUserID(ctx context.Context, userID uint64,
 	return transactions, nil
 }
 
+// GetByUserIDAfter получает транзакции пользователя от новых к старым, начиная после cursor (nil - с начала)
+func (r *TransactionRepository) GetByUserIDAfter(ctx context.Context, userID uint64, limit int, cursor *models.PageCursor) ([]*models.Transaction, error) {
+	var afterID, afterTime any
+	if cursor != nil {
+		afterID, afterTime = cursor.ID, cursor.Time
+	}
+
+	query := `
+		SELECT id, user_id, amount, type, status, created_at, updated_at
+		FROM transactions
+		WHERE user_id = $1 AND ($2::uuid IS NULL OR (created_at, id) < ($3::timestamptz, $2::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, afterID, afterTime, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*models.Transaction
+	for rows.Next() {
+		var transaction models.Transaction
+
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.UserID,
+			&transaction.Amount,
+			&transaction.Type,
+			&transaction.Status,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+
+		transactions = append(transactions, &transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
 // GetByType получает транзакции по типу с пагинацией
 func (r *TransactionRepository) GetByType(ctx context.Context, transactionType string, limit, offset int) ([]*models.Transaction, error) {
 	query := `
-		SELECT id, user_id, amount, type, status, created_at, updated_at
+		SELECT id, COALESCE(user_id, 0), amount, type, status, created_at, updated_at
 		FROM transactions
 		WHERE type = $1
 		ORDER BY created_at DESC
@@ -220,10 +269,10 @@ func (r *TransactionRepository) Update(ctx context.Context, transaction *models.
 
 	transaction.UpdatedAt = time.Now()
 
-	_, err := r.db.ExecContext(
+	_, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
-		transaction.UserID,
+		nullableUserID(transaction.UserID),
 		transaction.Amount,
 		transaction.Type,
 		transaction.Status,
@@ -355,7 +404,7 @@ func (r *TransactionRepository) CheckSufficientFunds(ctx context.Context, userID
 // GetByTxHash получает транзакцию по хешу блокчейна
 func (r *TransactionRepository) GetByTxHash(ctx context.Context, txHash string) (*models.Transaction, error) {
 	query := `
-		SELECT id, user_id, type, amount, currency, status, tx_hash, network, game_id, lobby_id,
+		SELECT id, COALESCE(user_id, 0), type, amount, currency, status, tx_hash, network, game_id, lobby_id,
 			COALESCE(description, ''), COALESCE(fee, 0), COALESCE(blockchain_lt, 0),
 			COALESCE(from_address, ''), COALESCE(to_address, ''), COALESCE(comment, ''),
 			COALESCE(game_short_id, ''), COALESCE(error_message, ''), COALESCE(confirmations, 0),
@@ -433,7 +482,7 @@ func (r *TransactionRepository) ExistsByTxHash(ctx context.Context, txHash strin
 // GetByStatus получает транзакции по статусу
 func (r *TransactionRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.Transaction, error) {
 	query := `
-		SELECT id, user_id, type, amount, currency, status, tx_hash, network, game_id, lobby_id,
+		SELECT id, COALESCE(user_id, 0), type, amount, currency, status, tx_hash, network, game_id, lobby_id,
 			COALESCE(description, ''), created_at, updated_at
 		FROM transactions
 		WHERE status = $1
@@ -492,7 +541,7 @@ func (r *TransactionRepository) GetByStatus(ctx context.Context, status string,
 // GetPendingByGameShortID получает pending транзакции для игры
 func (r *TransactionRepository) GetPendingByGameShortID(ctx context.Context, gameShortID string) ([]*models.Transaction, error) {
 	query := `
-		SELECT id, user_id, type, amount, currency, status, tx_hash, created_at, updated_at
+		SELECT id, COALESCE(user_id, 0), type, amount, currency, status, tx_hash, created_at, updated_at
 		FROM transactions
 		WHERE game_short_id = $1 AND status = 'pending'
 		ORDER BY created_at DESC
@@ -597,3 +646,31 @@ func (r *TransactionRepository) UpdateLastProcessedLt(ctx context.Context, lt in
 	}
 	return nil
 }
+
+// SumByGame возвращает суммы завершённых транзакций игры по типам
+func (r *TransactionRepository) SumByGame(ctx context.Context, gameID uuid.UUID) (map[string]float64, error) {
+	query := `
+		SELECT type, COALESCE(SUM(amount), 0)
+		FROM transactions
+		WHERE game_id = $1 AND status = 'completed'
+		GROUP BY type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum game transactions: %w", err)
+	}
+	defer rows.Close()
+
+	sums := make(map[string]float64)
+	for rows.Next() {
+		var txType string
+		var sum float64
+		if err := rows.Scan(&txType, &sum); err != nil {
+			return nil, fmt.Errorf("failed to scan game transaction sum: %w", err)
+		}
+		sums[txType] = sum
+	}
+
+	return sums, rows.Err()
+}
@@ -35,8 +35,8 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		zap.String("last_name", user.LastName))
 
 	query := `
-		INSERT INTO users (telegram_id, username, first_name, last_name, wallet, balance_ton, balance_usdt, wins, losses, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO users (telegram_id, username, first_name, last_name, language_code, wallet, balance_ton, balance_usdt, wins, losses, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
 	now := time.Now()
@@ -60,6 +60,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		user.Username,
 		user.FirstName,
 		user.LastName,
+		user.LanguageCode,
 		user.Wallet,
 		user.BalanceTon,
 		user.BalanceUsdt,
@@ -85,7 +86,8 @@ func (r *UserRepository) GetByTelegramID(ctx context.Context, telegramID uint64)
 	log.Info("Getting user by Telegram ID")
 
 	query := `
-		SELECT telegram_id, username, first_name, last_name, wallet, balance_ton, balance_usdt, wins, losses, created_at, updated_at
+		SELECT telegram_id, username, first_name, last_name, language_code, wallet, balance_ton, balance_usdt,
+			bonus_ton, bonus_usdt, wager_ton, wager_usdt, wins, losses, created_at, updated_at
 		FROM users
 		WHERE telegram_id = $1
 	`
@@ -99,9 +101,14 @@ func (r *UserRepository) GetByTelegramID(ctx context.Context, telegramID uint64)
 		&user.Username,
 		&user.FirstName,
 		&user.LastName,
+		&user.LanguageCode,
 		&user.Wallet,
 		&user.BalanceTon,
 		&user.BalanceUsdt,
+		&user.BonusTon,
+		&user.BonusUsdt,
+		&user.WagerTon,
+		&user.WagerUsdt,
 		&user.Wins,
 		&user.Losses,
 		&user.CreatedAt,
@@ -131,8 +138,8 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 
 	query := `
 		UPDATE users
-		SET username = $1, first_name = $2, last_name = $3, wallet = $4, balance_ton = $5, balance_usdt = $6, wins = $7, losses = $8, updated_at = $9
-		WHERE telegram_id = $10
+		SET username = $1, first_name = $2, last_name = $3, language_code = $4, wallet = $5, balance_ton = $6, balance_usdt = $7, wins = $8, losses = $9, updated_at = $10
+		WHERE telegram_id = $11
 	`
 
 	user.UpdatedAt = time.Now()
@@ -150,6 +157,7 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		user.Username,
 		user.FirstName,
 		user.LastName,
+		user.LanguageCode,
 		user.Wallet,
 		user.BalanceTon,
 		user.BalanceUsdt,
@@ -203,7 +211,8 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	log.Info("Getting user by username")
 
 	query := `
-		SELECT telegram_id, username, first_name, last_name, wallet, balance_ton, balance_usdt, wins, losses, created_at, updated_at
+		SELECT telegram_id, username, first_name, last_name, language_code, wallet, balance_ton, balance_usdt,
+			bonus_ton, bonus_usdt, wager_ton, wager_usdt, wins, losses, created_at, updated_at
 		FROM users
 		WHERE username = $1
 	`
@@ -217,9 +226,14 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 		&user.Username,
 		&user.FirstName,
 		&user.LastName,
+		&user.LanguageCode,
 		&user.Wallet,
 		&user.BalanceTon,
 		&user.BalanceUsdt,
+		&user.BonusTon,
+		&user.BonusUsdt,
+		&user.WagerTon,
+		&user.WagerUsdt,
 		&user.Wins,
 		&user.Losses,
 		&user.CreatedAt,
@@ -273,9 +287,10 @@ func (r *UserRepository) GetTopUsers(ctx context.Context, limit int) ([]*models.
 	log.Info("Getting top users", zap.Int("limit", limit))
 
 	query := `
-		SELECT telegram_id, username, first_name, last_name, wallet, balance_ton, balance_usdt, wins, losses, created_at, updated_at
+		SELECT telegram_id, username, first_name, last_name, language_code, wallet, balance_ton, balance_usdt,
+			bonus_ton, bonus_usdt, wager_ton, wager_usdt, wins, losses, created_at, updated_at
 		FROM users
-		ORDER BY wins DESC, balance_ton DESC
+		ORDER BY wins DESC, losses, telegram_id
 		LIMIT $1
 	`
 
@@ -297,9 +312,14 @@ func (r *UserRepository) GetTopUsers(ctx context.Context, limit int) ([]*models.
 			&user.Username,
 			&user.FirstName,
 			&user.LastName,
+			&user.LanguageCode,
 			&user.Wallet,
 			&user.BalanceTon,
 			&user.BalanceUsdt,
+			&user.BonusTon,
+			&user.BonusUsdt,
+			&user.WagerTon,
+			&user.WagerUsdt,
 			&user.Wins,
 			&user.Losses,
 			&user.CreatedAt,
@@ -416,7 +436,7 @@ func (r *UserRepository) UpdateTonBalance(ctx context.Context, telegramID uint64
 
 	log.Debug("Executing SQL query", zap.String("query", query))
 
-	_, err := r.db.ExecContext(ctx, query, amount, telegramID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, amount, telegramID)
 	if err != nil {
 		log.Error("Failed to update TON balance", zap.Error(err))
 		return fmt.Errorf("failed to update TON balance: %w", err)
@@ -438,7 +458,7 @@ func (r *UserRepository) UpdateUsdtBalance(ctx context.Context, telegramID uint6
 
 	log.Debug("Executing SQL query", zap.String("query", query))
 
-	_, err := r.db.ExecContext(ctx, query, amount, telegramID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, amount, telegramID)
 	if err != nil {
 		log.Error("Failed to update USDT balance", zap.Error(err))
 		return fmt.Errorf("failed to update USDT balance: %w", err)
@@ -453,7 +473,8 @@ func (r *UserRepository) UpdateUsdtBalance(ctx context.Context, telegramID uint6
 // GetByWallet получает пользователя по адресу кошелька
 func (r *UserRepository) GetByWallet(ctx context.Context, wallet string) (*models.User, error) {
 	query := `
-		SELECT telegram_id, username, first_name, last_name, wallet, balance_ton, balance_usdt,
+		SELECT telegram_id, username, first_name, last_name, language_code, wallet, balance_ton, balance_usdt,
+			bonus_ton, bonus_usdt, wager_ton, wager_usdt,
 			COALESCE(pending_withdrawal, 0), withdrawal_lock_until, wins, losses,
 			COALESCE(total_deposited, 0), COALESCE(total_withdrawn, 0), created_at, updated_at
 		FROM users
@@ -467,9 +488,14 @@ func (r *UserRepository) GetByWallet(ctx context.Context, wallet string) (*model
 		&user.Username,
 		&user.FirstName,
 		&user.LastName,
+		&user.LanguageCode,
 		&user.Wallet,
 		&user.BalanceTon,
 		&user.BalanceUsdt,
+		&user.BonusTon,
+		&user.BonusUsdt,
+		&user.WagerTon,
+		&user.WagerUsdt,
 		&user.PendingWithdrawal,
 		&withdrawalLockUntil,
 		&user.Wins,
@@ -507,7 +533,7 @@ func (r *UserRepository) UpdateWallet(ctx context.Context, telegramID uint64, wa
 // UpdatePendingWithdrawal обновляет сумму в процессе вывода
 func (r *UserRepository) UpdatePendingWithdrawal(ctx context.Context, telegramID uint64, amount float64) error {
 	query := `UPDATE users SET pending_withdrawal = COALESCE(pending_withdrawal, 0) + $1, updated_at = $2 WHERE telegram_id = $3`
-	_, err := r.db.ExecContext(ctx, query, amount, time.Now(), telegramID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, amount, time.Now(), telegramID)
 	if err != nil {
 		return fmt.Errorf("failed to update pending withdrawal: %w", err)
 	}
@@ -524,6 +550,129 @@ func (r *UserRepository) SetWithdrawalLock(ctx context.Context, telegramID uint6
 	return nil
 }
 
+// bonusColumns возвращает столбцы бонусного баланса, оборота ставок и основного баланса для валюты
+func bonusColumns(currency string) (bonus, wager, balance string, err error) {
+	switch currency {
+	case models.CurrencyTON:
+		return "bonus_ton", "wager_ton", "balance_ton", nil
+	case models.CurrencyUSDT:
+		return "bonus_usdt", "wager_usdt", "balance_usdt", nil
+	default:
+		return "", "", "", fmt.Errorf("unsupported currency: %s", currency)
+	}
+}
+
+// DebitBalance атомарно списывает amount с доступного баланса (за вычетом ожидающего вывода для TON)
+func (r *UserRepository) DebitBalance(ctx context.Context, telegramID uint64, currency string, amount float64) error {
+	var query string
+	switch currency {
+	case models.CurrencyTON:
+		query = `UPDATE users SET balance_ton = balance_ton - $1, updated_at = $2
+			WHERE telegram_id = $3 AND balance_ton - COALESCE(pending_withdrawal, 0) >= $1`
+	case models.CurrencyUSDT:
+		query = `UPDATE users SET balance_usdt = balance_usdt - $1, updated_at = $2
+			WHERE telegram_id = $3 AND balance_usdt >= $1`
+	default:
+		return fmt.Errorf("unsupported currency: %s", currency)
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, amount, time.Now(), telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to debit %s balance: %w", currency, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		if _, err := r.GetByTelegramID(ctx, telegramID); err != nil {
+			return err
+		}
+		return models.ErrInsufficientFunds
+	}
+	return nil
+}
+
+// UpdateBonusBalance изменяет бонусный баланс и оставшийся оборот ставок (не ниже нуля)
+func (r *UserRepository) UpdateBonusBalance(ctx context.Context, telegramID uint64, currency string, amount, wager float64) error {
+	bonusCol, wagerCol, _, err := bonusColumns(currency)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE users SET %[1]s = %[1]s + $1, %[2]s = GREATEST(%[2]s + $2, 0), updated_at = $3
+		WHERE telegram_id = $4 AND %[1]s + $1 >= 0
+	`, bonusCol, wagerCol)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, amount, wager, time.Now(), telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to update bonus balance: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		if _, err := r.GetByTelegramID(ctx, telegramID); err != nil {
+			return err
+		}
+		return models.ErrInsufficientBonus
+	}
+	return nil
+}
+
+// SpendBonusBalance атомарно списывает с бонусного баланса не больше amount и возвращает списанную сумму
+func (r *UserRepository) SpendBonusBalance(ctx context.Context, telegramID uint64, currency string, amount float64) (float64, error) {
+	bonusCol, _, _, err := bonusColumns(currency)
+	if err != nil {
+		return 0, err
+	}
+
+	// Подзапрос блокирует строку и возвращает баланс до списания
+	query := fmt.Sprintf(`
+		UPDATE users u SET %[1]s = u.%[1]s - LEAST(old.%[1]s, $1), updated_at = $2
+		FROM (SELECT telegram_id, %[1]s FROM users WHERE telegram_id = $3 FOR UPDATE) old
+		WHERE u.telegram_id = old.telegram_id
+		RETURNING LEAST(old.%[1]s, $1)
+	`, bonusCol)
+
+	var spent float64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, amount, time.Now(), telegramID).Scan(&spent); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, models.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("failed to spend bonus balance: %w", err)
+	}
+	return spent, nil
+}
+
+// ConvertBonusBalance переводит бонусный баланс в основной, если оборот ставок отыгран
+func (r *UserRepository) ConvertBonusBalance(ctx context.Context, telegramID uint64, currency string) (float64, error) {
+	bonusCol, wagerCol, balanceCol, err := bonusColumns(currency)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE users u SET %[3]s = u.%[3]s + old.%[1]s, %[1]s = 0, updated_at = $1
+		FROM (SELECT telegram_id, %[1]s FROM users WHERE telegram_id = $2 FOR UPDATE) old
+		WHERE u.telegram_id = old.telegram_id AND u.%[2]s <= 0 AND old.%[1]s > 0
+		RETURNING old.%[1]s
+	`, bonusCol, wagerCol, balanceCol)
+
+	var converted float64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, time.Now(), telegramID).Scan(&converted); err != nil {
+		if err == sql.ErrNoRows {
+			// Оборот ещё не отыгран или бонусного баланса нет
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to convert bonus balance: %w", err)
+	}
+	return converted, nil
+}
+
 // IncrementWins увеличивает количество побед
 func (r *UserRepository) IncrementWins(ctx context.Context, telegramID uint64) error {
 	query := `UPDATE users SET wins = wins + 1, updated_at = $1 WHERE telegram_id = $2`
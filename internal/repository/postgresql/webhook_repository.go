@@ -0,0 +1,311 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// WebhookRepository представляет собой реализацию репозитория для работы с webhook и журналом их доставок
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository создает новый экземпляр WebhookRepository
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// Create создает webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhooks (id, owner_id, url, secret, event_types, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, webhook.ID, webhook.OwnerID, webhook.URL, webhook.Secret, pq.Array(webhook.EventTypes), webhook.Active,
+		webhook.CreatedAt, webhook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+// GetByID получает webhook по ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, owner_id, url, secret, event_types, active, created_at, updated_at
+		FROM webhooks
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks, err := scanWebhooks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, models.ErrWebhookNotFound
+	}
+	return webhooks[0], nil
+}
+
+// GetByOwner получает webhook пользователя в порядке создания
+func (r *WebhookRepository) GetByOwner(ctx context.Context, ownerID uint64) ([]*models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, owner_id, url, secret, event_types, active, created_at, updated_at
+		FROM webhooks
+		WHERE owner_id = $1
+		ORDER BY created_at, id
+	`, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks by owner: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhooks(rows)
+}
+
+// GetSubscribed получает активные webhook владельцев, подписанные на тип события
+func (r *WebhookRepository) GetSubscribed(ctx context.Context, ownerIDs []uint64, eventType string) ([]*models.Webhook, error) {
+	ids := make([]int64, len(ownerIDs))
+	for i, ownerID := range ownerIDs {
+		ids[i] = int64(ownerID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, owner_id, url, secret, event_types, active, created_at, updated_at
+		FROM webhooks
+		WHERE active AND owner_id = ANY($1) AND $2 = ANY(event_types)
+		ORDER BY created_at, id
+	`, pq.Array(ids), eventType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribed webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhooks(rows)
+}
+
+// Update обновляет адрес, типы событий и активность webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	webhook.UpdatedAt = time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhooks
+		SET url = $1, event_types = $2, active = $3, updated_at = $4
+		WHERE id = $5
+	`, webhook.URL, pq.Array(webhook.EventTypes), webhook.Active, webhook.UpdatedAt, webhook.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Delete удаляет webhook. Журнал доставок удаляется каскадно
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// CreateDelivery ставит доставку в очередь. Доставка с уже известным ключом события не добавляется
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.Status == "" {
+		delivery.Status = models.WebhookDeliveryPending
+	}
+	delivery.CreatedAt = time.Now()
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = delivery.CreatedAt
+	}
+
+	var dedupKey sql.NullString
+	if delivery.DedupKey != "" {
+		dedupKey = sql.NullString{String: delivery.DedupKey, Valid: true}
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, dedup_key, payload, status, attempts,
+			next_attempt_at, response_code, last_error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (webhook_id, dedup_key) DO NOTHING
+	`, delivery.ID, delivery.WebhookID, delivery.EventID, delivery.EventType, dedupKey, []byte(delivery.Payload),
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.ResponseCode, delivery.LastError, delivery.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetDelivery получает доставку по ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, webhook_id, event_id, event_type, COALESCE(dedup_key, ''), payload, status, attempts, next_attempt_at,
+			response_code, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries, err := scanWebhookDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, models.ErrWebhookDeliveryNotFound
+	}
+	return deliveries[0], nil
+}
+
+// GetDeliveries получает журнал доставок webhook, начиная с последних
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]*models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, webhook_id, event_id, event_type, COALESCE(dedup_key, ''), payload, status, attempts, next_attempt_at,
+			response_code, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`, webhookID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookDeliveries(rows)
+}
+
+// GetDueDeliveries получает ожидающие доставки активных webhook, время попытки которых наступило
+func (r *WebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT d.id, d.webhook_id, d.event_id, d.event_type, COALESCE(d.dedup_key, ''), d.payload, d.status, d.attempts,
+			d.next_attempt_at, d.response_code, d.last_error, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.active
+		ORDER BY d.next_attempt_at, d.created_at, d.id
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookDeliveries(rows)
+}
+
+// UpdateDelivery сохраняет результат попытки доставки
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, response_code = $4, last_error = $5, delivered_at = $6
+		WHERE id = $7
+	`, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.ResponseCode, delivery.LastError,
+		delivery.DeliveredAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// scanWebhooks читает webhook из результата запроса
+func scanWebhooks(rows *sql.Rows) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		var webhook models.Webhook
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.OwnerID,
+			&webhook.URL,
+			&webhook.Secret,
+			pq.Array(&webhook.EventTypes),
+			&webhook.Active,
+			&webhook.CreatedAt,
+			&webhook.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// scanWebhookDeliveries читает доставки webhook из результата запроса
+func scanWebhookDeliveries(rows *sql.Rows) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var payload []byte
+		var deliveredAt sql.NullTime
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.DedupKey,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.ResponseCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&deliveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		delivery.Payload = payload
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
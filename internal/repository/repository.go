@@ -19,6 +19,22 @@ type Repository interface {
 	Attempt() models.AttemptRepository
 	History() models.HistoryRepository
 	Transaction() models.TransactionRepository
+	SideBet() models.SideBetRepository
+	Duel() models.DuelRepository
+	Jackpot() models.JackpotRepository
+	Commission() models.CommissionRepository
+	Referral() models.ReferralRepository
+	Promo() models.PromoRepository
+	Achievement() models.AchievementRepository
+	Leaderboard() models.LeaderboardRepository
+	PlayerStats() models.PlayerStatsRepository
+	Analytics() models.AnalyticsRepository
+	Reputation() models.ReputationRepository
+	Follow() models.FollowRepository
+	Notification() models.NotificationRepository
+	Event() models.EventRepository
+	Webhook() models.WebhookRepository
+	models.Transactor
 }
 
 // PostgresRepository представляет собой реализацию репозитория для PostgreSQL
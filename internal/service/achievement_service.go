@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// achievementProgress данные, по которым проверяются правила достижений
+type achievementProgress struct {
+	event       *models.AchievementEvent
+	streak      *models.UserStreak // Серии игрока после учёта события
+	gamePlayers int                // Разных игроков в игре (0 - неизвестно)
+}
+
+// achievementRule правило выдачи достижения
+type achievementRule struct {
+	code string
+	// recipient возвращает получателя достижения или 0, если правило не выполнено
+	recipient func(p *achievementProgress) uint64
+}
+
+// achievementRules правила достижений, проверяемые при расчёте лобби
+var achievementRules = []achievementRule{
+	{code: models.AchievementFirstWin, recipient: func(p *achievementProgress) uint64 {
+		if p.event.Won() {
+			return p.event.UserID
+		}
+		return 0
+	}},
+	{code: models.AchievementWinStreak, recipient: func(p *achievementProgress) uint64 {
+		if p.streak.WinStreak >= models.AchievementWinStreakLength {
+			return p.event.UserID
+		}
+		return 0
+	}},
+	{code: models.AchievementQuickSolve, recipient: func(p *achievementProgress) uint64 {
+		if p.event.Won() && p.event.TriesUsed > 0 && p.event.TriesUsed <= models.AchievementQuickSolveTries {
+			return p.event.UserID
+		}
+		return 0
+	}},
+	{code: models.AchievementVeteran, recipient: func(p *achievementProgress) uint64 {
+		if p.streak.GamesPlayed >= models.AchievementGamesPlayed {
+			return p.event.UserID
+		}
+		return 0
+	}},
+	{code: models.AchievementPopularGame, recipient: func(p *achievementProgress) uint64 {
+		if p.gamePlayers >= models.AchievementGamePlayers {
+			return p.event.CreatorID
+		}
+		return 0
+	}},
+}
+
+// AchievementServiceImpl представляет собой реализацию AchievementService
+type AchievementServiceImpl struct {
+	achievementRepo    models.AchievementRepository
+	userRepo           models.UserRepository
+	transactionService models.TransactionService
+	rule               models.AchievementRule
+	transactor         models.Transactor
+	logger             *zap.Logger
+}
+
+// NewAchievementService создает новый экземпляр AchievementService.
+// Награды зачисляются на бонусный баланс в валюте rule.Currency (по умолчанию TON)
+func NewAchievementService(
+	achievementRepo models.AchievementRepository,
+	userRepo models.UserRepository,
+	transactionService models.TransactionService,
+	rule models.AchievementRule,
+	transactor models.Transactor,
+) models.AchievementService {
+	if rule.Currency == "" {
+		rule.Currency = models.CurrencyTON
+	}
+	return &AchievementServiceImpl{
+		achievementRepo:    achievementRepo,
+		userRepo:           userRepo,
+		transactionService: transactionService,
+		rule:               rule,
+		transactor:         transactor,
+		logger:             logger.GetLogger(zap.String("service", "achievement")),
+	}
+}
+
+// HandleEvent обновляет серии по доменному событию и выдаёт достижения, правила которых выполнены
+func (s *AchievementServiceImpl) HandleEvent(ctx context.Context, event *models.AchievementEvent) error {
+	if event == nil || event.Type != models.AchievementEventLobbyFinished || event.UserID == 0 {
+		return nil
+	}
+	at := event.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	streak, err := s.achievementRepo.RecordPlay(ctx, event.UserID, event.Status, at)
+	if err != nil {
+		return err
+	}
+
+	progress := &achievementProgress{event: event, streak: streak}
+	if event.CreatorID != 0 && event.GameID != uuid.Nil {
+		players, err := s.achievementRepo.AddGamePlayer(ctx, event.GameID, event.UserID)
+		if err != nil {
+			return err
+		}
+		progress.gamePlayers = players
+	}
+
+	for _, rule := range achievementRules {
+		userID := rule.recipient(progress)
+		if userID == 0 {
+			continue
+		}
+		if err := s.award(ctx, userID, rule.code); err != nil {
+			s.logger.Error("Failed to award achievement",
+				zap.Uint64("user_id", userID),
+				zap.String("code", rule.code),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// award выдаёт достижение и зачисляет награду за него, если достижение выдаётся впервые.
+// Достижение и награда записываются в одной транзакции
+func (s *AchievementServiceImpl) award(ctx context.Context, userID uint64, code string) error {
+	achievement := &models.UserAchievement{
+		UserID:   userID,
+		Code:     code,
+		Reward:   s.rule.Reward(code),
+		Currency: s.rule.Currency,
+	}
+	title := code
+	if definition, ok := models.FindAchievement(code); ok {
+		title = definition.Title
+	}
+
+	var awarded bool
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		var err error
+		awarded, err = s.achievementRepo.Award(ctx, achievement)
+		if err != nil || !awarded || achievement.Reward <= 0 {
+			return err
+		}
+		return s.creditBonus(ctx, userID, achievement.Reward, fmt.Sprintf("Achievement: %s", title))
+	})
+	if err != nil || !awarded {
+		return err
+	}
+
+	s.logger.Info("Achievement awarded",
+		zap.Uint64("user_id", userID),
+		zap.String("code", code),
+		zap.Float64("reward", achievement.Reward))
+	return nil
+}
+
+// ClaimDailyReward продлевает серию ежедневных входов и зачисляет награду (один раз в день).
+// Серия и награда записываются в одной транзакции
+func (s *AchievementServiceImpl) ClaimDailyReward(ctx context.Context, userID uint64) (*models.DailyReward, error) {
+	if userID == 0 {
+		return nil, errors.New("user ID cannot be zero")
+	}
+
+	var reward *models.DailyReward
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		streak, recorded, err := s.achievementRepo.RecordLogin(ctx, userID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			return models.ErrDailyRewardClaimed
+		}
+
+		reward = &models.DailyReward{
+			Streak:   streak.LoginStreak,
+			Amount:   s.rule.DailyRewardFor(streak.LoginStreak),
+			Currency: s.rule.Currency,
+		}
+		if reward.Amount <= 0 {
+			return nil
+		}
+		return s.creditBonus(ctx, userID, reward.Amount, fmt.Sprintf("Daily reward, day %d", streak.LoginStreak))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reward, nil
+}
+
+// creditBonus зачисляет награду на бонусный баланс с отыгрышем в размере награды и записывает транзакцию.
+// Вызывается внутри транзакции, чтобы награда не зачислялась без записи в истории
+func (s *AchievementServiceImpl) creditBonus(ctx context.Context, userID uint64, amount float64, description string) error {
+	if err := s.userRepo.UpdateBonusBalance(ctx, userID, s.rule.Currency, amount, amount); err != nil {
+		return fmt.Errorf("failed to credit reward: %w", err)
+	}
+
+	tx := &models.Transaction{
+		UserID:      userID,
+		Type:        models.TransactionTypeBonus,
+		Amount:      amount,
+		Currency:    s.rule.Currency,
+		Status:      models.TransactionStatusCompleted,
+		Description: description,
+	}
+	if err := s.transactionService.CreateTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to record reward transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetStreak возвращает серии пользователя
+func (s *AchievementServiceImpl) GetStreak(ctx context.Context, userID uint64) (*models.UserStreak, error) {
+	return s.achievementRepo.GetStreak(ctx, userID)
+}
+
+// GetAchievements возвращает серии, полученные достижения и каталог достижений с наградами
+func (s *AchievementServiceImpl) GetAchievements(ctx context.Context, userID uint64) (*models.AchievementSummary, error) {
+	streak, err := s.achievementRepo.GetStreak(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	earned, err := s.achievementRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if earned == nil {
+		earned = []*models.UserAchievement{}
+	}
+
+	catalog := make([]models.Achievement, 0, len(models.AchievementCatalog))
+	for _, achievement := range models.AchievementCatalog {
+		if reward := s.rule.Reward(achievement.Code); reward > 0 {
+			achievement.Reward = reward
+			achievement.Currency = s.rule.Currency
+		}
+		catalog = append(catalog, achievement)
+	}
+
+	summary := &models.AchievementSummary{
+		Streak:  streak,
+		Earned:  earned,
+		Catalog: catalog,
+	}
+
+	// Награда за вход сегодня: считаем на копии серий, не сохраняя её
+	next := *streak
+	if next.RecordLogin(time.Now()) {
+		summary.DailyAvailable = s.rule.DailyRewardFor(next.LoginStreak)
+	}
+
+	return summary, nil
+}
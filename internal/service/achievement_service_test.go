@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func setupAchievementService(t *testing.T, rule models.AchievementRule) (*mocks.MockUserRepository, models.AchievementService) {
+	t.Helper()
+	userRepo := mocks.NewMockUserRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	achievements := NewAchievementService(mocks.NewMockAchievementRepository(), userRepo, txService, rule, mocks.NewMockTransactor())
+
+	ctx := context.Background()
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "player"})
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 2, Username: "creator"})
+	return userRepo, achievements
+}
+
+func earnedCodes(t *testing.T, achievements models.AchievementService, userID uint64) map[string]bool {
+	t.Helper()
+	summary, err := achievements.GetAchievements(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetAchievements() error = %v", err)
+	}
+	codes := make(map[string]bool, len(summary.Earned))
+	for _, achievement := range summary.Earned {
+		codes[achievement.Code] = true
+	}
+	return codes
+}
+
+func TestUserStreak_Days(t *testing.T) {
+	day := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	streak := &models.UserStreak{}
+
+	streak.RecordPlay(models.LobbyStatusSuccess, day)
+	streak.RecordPlay(models.LobbyStatusCashedOut, day.Add(30*time.Minute))
+	if streak.PlayStreak != 1 || streak.WinStreak != 1 || streak.GamesPlayed != 2 {
+		t.Errorf("streak = %+v, want 1 play day, 1 win in a row, 2 games", streak)
+	}
+
+	streak.RecordPlay(models.LobbyStatusSuccess, day.AddDate(0, 0, 1))
+	if streak.PlayStreak != 2 || streak.WinStreak != 2 {
+		t.Errorf("next day: play streak = %d, win streak = %d, want 2 and 2", streak.PlayStreak, streak.WinStreak)
+	}
+
+	streak.RecordPlay(models.LobbyStatusFailedTries, day.AddDate(0, 0, 3))
+	if streak.PlayStreak != 1 || streak.WinStreak != 0 || streak.BestWinStreak != 2 || streak.BestPlayStreak != 2 {
+		t.Errorf("after gap and loss: %+v, want streaks reset and best kept", streak)
+	}
+
+	if !streak.RecordLogin(day) || streak.RecordLogin(day.Add(time.Minute)) {
+		t.Error("RecordLogin() should count only the first login of a day")
+	}
+	if !streak.RecordLogin(day.AddDate(0, 0, 1)) || streak.LoginStreak != 2 {
+		t.Errorf("login streak = %d, want 2", streak.LoginStreak)
+	}
+}
+
+func TestAchievementRule_DailyRewardFor(t *testing.T) {
+	rule := models.AchievementRule{DailyReward: 0.01, DailyRewardStep: 0.005, DailyRewardMaxDays: 3}
+
+	tests := []struct {
+		streak int
+		want   float64
+	}{
+		{0, 0},
+		{1, 0.01},
+		{2, 0.015},
+		{3, 0.02},
+		{10, 0.02},
+	}
+	for _, tt := range tests {
+		if got := rule.DailyRewardFor(tt.streak); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("DailyRewardFor(%d) = %v, want %v", tt.streak, got, tt.want)
+		}
+	}
+
+	if got := (models.AchievementRule{}).DailyRewardFor(5); got != 0 {
+		t.Errorf("disabled DailyRewardFor() = %v, want 0", got)
+	}
+}
+
+func TestAchievementService_HandleEvent(t *testing.T) {
+	ctx := context.Background()
+	rule := models.AchievementRule{Rewards: map[string]float64{models.AchievementFirstWin: 0.5}}
+	userRepo, achievements := setupAchievementService(t, rule)
+	gameID := uuid.New()
+
+	play := func(status string, tries int) {
+		t.Helper()
+		event := &models.AchievementEvent{
+			Type:      models.AchievementEventLobbyFinished,
+			UserID:    1,
+			CreatorID: 2,
+			GameID:    gameID,
+			LobbyID:   uuid.New(),
+			Status:    status,
+			TriesUsed: tries,
+		}
+		if err := achievements.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("HandleEvent() error = %v", err)
+		}
+	}
+
+	play(models.LobbyStatusFailedTries, 6)
+	if codes := earnedCodes(t, achievements, 1); len(codes) != 0 {
+		t.Errorf("achievements after a loss = %v, want none", codes)
+	}
+
+	play(models.LobbyStatusSuccess, 4)
+	codes := earnedCodes(t, achievements, 1)
+	if !codes[models.AchievementFirstWin] || codes[models.AchievementQuickSolve] {
+		t.Errorf("achievements = %v, want only first_win", codes)
+	}
+
+	// Награда зачисляется на бонусный баланс только один раз
+	for i := 0; i < models.AchievementWinStreakLength-1; i++ {
+		play(models.LobbyStatusSuccess, 2)
+	}
+	user, _ := userRepo.GetByTelegramID(ctx, 1)
+	if user.BonusTon != 0.5 || user.WagerTon != 0.5 || user.BalanceTon != 0 {
+		t.Errorf("bonus = %v, wager = %v, balance = %v, want 0.5, 0.5, 0", user.BonusTon, user.WagerTon, user.BalanceTon)
+	}
+
+	codes = earnedCodes(t, achievements, 1)
+	if !codes[models.AchievementQuickSolve] || !codes[models.AchievementWinStreak] || codes[models.AchievementVeteran] {
+		t.Errorf("achievements = %v, want quick_solve and win_streak", codes)
+	}
+
+	streak, _ := achievements.GetStreak(ctx, 1)
+	if streak.WinStreak != models.AchievementWinStreakLength || streak.GamesPlayed != models.AchievementWinStreakLength+1 {
+		t.Errorf("streak = %+v, want %d wins in a row", streak, models.AchievementWinStreakLength)
+	}
+}
+
+func TestAchievementService_PopularGame(t *testing.T) {
+	ctx := context.Background()
+	_, achievements := setupAchievementService(t, models.AchievementRule{})
+	gameID := uuid.New()
+
+	for player := uint64(100); player < 100+models.AchievementGamePlayers; player++ {
+		if codes := earnedCodes(t, achievements, 2); codes[models.AchievementPopularGame] {
+			t.Fatalf("popular_game awarded after %d players", player-100)
+		}
+		// Повторная игра того же игрока не увеличивает число игроков
+		for i := 0; i < 2; i++ {
+			event := &models.AchievementEvent{
+				Type:      models.AchievementEventLobbyFinished,
+				UserID:    player,
+				CreatorID: 2,
+				GameID:    gameID,
+				Status:    models.LobbyStatusFailedTries,
+			}
+			if err := achievements.HandleEvent(ctx, event); err != nil {
+				t.Fatalf("HandleEvent() error = %v", err)
+			}
+		}
+	}
+
+	if codes := earnedCodes(t, achievements, 2); !codes[models.AchievementPopularGame] {
+		t.Errorf("achievements = %v, want popular_game for the creator", codes)
+	}
+}
+
+func TestAchievementService_ClaimDailyReward(t *testing.T) {
+	ctx := context.Background()
+	userRepo, achievements := setupAchievementService(t, models.AchievementRule{DailyReward: 0.01})
+
+	summary, err := achievements.GetAchievements(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetAchievements() error = %v", err)
+	}
+	if summary.DailyAvailable != 0.01 || len(summary.Catalog) != len(models.AchievementCatalog) {
+		t.Errorf("summary = %+v, want daily reward 0.01 and full catalog", summary)
+	}
+
+	reward, err := achievements.ClaimDailyReward(ctx, 1)
+	if err != nil {
+		t.Fatalf("ClaimDailyReward() error = %v", err)
+	}
+	if reward.Streak != 1 || reward.Amount != 0.01 || reward.Currency != models.CurrencyTON {
+		t.Errorf("reward = %+v, want day 1, 0.01 TON", reward)
+	}
+
+	if _, err := achievements.ClaimDailyReward(ctx, 1); !errors.Is(err, models.ErrDailyRewardClaimed) {
+		t.Errorf("second claim error = %v, want ErrDailyRewardClaimed", err)
+	}
+
+	user, _ := userRepo.GetByTelegramID(ctx, 1)
+	if user.BonusTon != 0.01 {
+		t.Errorf("bonus = %v, want 0.01", user.BonusTon)
+	}
+
+	summary, _ = achievements.GetAchievements(ctx, 1)
+	if summary.DailyAvailable != 0 {
+		t.Errorf("DailyAvailable = %v, want 0 after claim", summary.DailyAvailable)
+	}
+}
@@ -42,12 +42,14 @@ type AuthServiceImpl struct {
 	redisRepo repository.RedisRepository
 	jwtSecret string
 	botToken  string
+	referrals models.ReferralService
 	logger    *zap.Logger
 }
 
 // NewAuthService создает новый экземпляр AuthServiceImpl.
 // jwtSecret и botToken должны передаваться из конфигурации.
-func NewAuthService(userRepo models.UserRepository, redisRepo repository.RedisRepository, jwtSecret, botToken string) models.AuthService {
+// referrals может быть nil, тогда реферальные ссылки не обрабатываются.
+func NewAuthService(userRepo models.UserRepository, redisRepo repository.RedisRepository, jwtSecret, botToken string, referrals models.ReferralService) models.AuthService {
 	log := logger.GetLogger(zap.String("service", "auth"))
 	log.Info("Creating new AuthService",
 		zap.String("jwtSecret_length", fmt.Sprintf("%d", len(jwtSecret))),
@@ -58,6 +60,7 @@ func NewAuthService(userRepo models.UserRepository, redisRepo repository.RedisRe
 		redisRepo: redisRepo,
 		jwtSecret: jwtSecret,
 		botToken:  botToken,
+		referrals: referrals,
 		logger:    log,
 	}
 }
@@ -115,10 +118,11 @@ func (s *AuthServiceImpl) InitAuth(ctx context.Context, initDataStr string) (str
 				zap.String("username", data.User.Username))
 
 			newUser := &models.User{
-				TelegramID: telegramID,
-				Username:   data.User.Username,
-				FirstName:  data.User.FirstName,
-				LastName:   data.User.LastName,
+				TelegramID:   telegramID,
+				Username:     data.User.Username,
+				FirstName:    data.User.FirstName,
+				LastName:     data.User.LastName,
+				LanguageCode: data.User.LanguageCode,
 			}
 
 			log.Debug("Creating new user",
@@ -135,6 +139,15 @@ func (s *AuthServiceImpl) InitAuth(ctx context.Context, initDataStr string) (str
 			}
 			user = newUser
 			log.Info("User created successfully", zap.Uint64("telegram_id", telegramID))
+
+			// Новый пользователь, открывший реферальную ссылку, закрепляется за реферером
+			if s.referrals != nil && data.StartParam != "" {
+				if err := s.referrals.ApplyStartParam(ctx, telegramID, data.StartParam); err != nil {
+					log.Warn("Failed to apply referral start param", zap.Error(err),
+						zap.Uint64("telegram_id", telegramID),
+						zap.String("start_param", data.StartParam))
+				}
+			}
 		} else {
 			log.Error("Failed to get user", zap.Error(err),
 				zap.Uint64("telegram_id", telegramID))
@@ -149,7 +162,8 @@ func (s *AuthServiceImpl) InitAuth(ctx context.Context, initDataStr string) (str
 	// Обновляем информацию о пользователе, если она изменилась
 	if user.Username != data.User.Username ||
 		user.FirstName != data.User.FirstName ||
-		user.LastName != data.User.LastName {
+		user.LastName != data.User.LastName ||
+		user.LanguageCode != data.User.LanguageCode {
 
 		log.Info("Updating user information",
 			zap.Uint64("telegram_id", user.TelegramID),
@@ -159,6 +173,7 @@ func (s *AuthServiceImpl) InitAuth(ctx context.Context, initDataStr string) (str
 		user.Username = data.User.Username
 		user.FirstName = data.User.FirstName
 		user.LastName = data.User.LastName
+		user.LanguageCode = data.User.LanguageCode
 
 		err = s.userRepo.Update(ctx, user)
 		if err != nil {
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"go.uber.org/zap"
+)
+
+// Ограничения ответов бота
+const (
+	botGamesLimit       = 10 // Игр в ответе на /games
+	botInlineGamesLimit = 20 // Карточек в ответе на inline-запрос
+	botLobbiesScan      = 20 // Последних лобби пользователя, среди которых /guess ищет активное
+)
+
+// botFeedbackEmoji подсказки по буквам: 0 - буквы нет в слове, 1 - буква на другом месте, 2 - буква на своём месте
+var botFeedbackEmoji = [...]string{"⬜", "🟨", "🟩"}
+
+// botHelpText список команд бота
+const botHelpText = `Play Wordle for crypto right here:
+/games - list active games
+/join <game> <bet> - join a game with your balance
+/guess <word> - guess the word in your current game
+/balance - show your balance
+/help - show this message`
+
+// BotServiceImpl представляет собой реализацию BotService
+type BotServiceImpl struct {
+	userService  models.UserService
+	gameService  models.GameService
+	lobbyService models.LobbyService
+	messenger    models.BotMessenger
+	botUsername  string
+	logger       *zap.Logger
+}
+
+// NewBotService создает новый экземпляр BotService.
+// botUsername нужен для ссылок на игры в inline-режиме и для команд вида /games@bot в группах
+func NewBotService(
+	userService models.UserService,
+	gameService models.GameService,
+	lobbyService models.LobbyService,
+	messenger models.BotMessenger,
+	botUsername string,
+) models.BotService {
+	return &BotServiceImpl{
+		userService:  userService,
+		gameService:  gameService,
+		lobbyService: lobbyService,
+		messenger:    messenger,
+		botUsername:  botUsername,
+		logger:       logger.GetLogger(zap.String("service", "bot")),
+	}
+}
+
+// RegisterWebhook регистрирует адрес webhook в Bot API
+func (s *BotServiceImpl) RegisterWebhook(ctx context.Context, url, secret string) error {
+	if err := s.messenger.SetWebhook(ctx, url, secret); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+	return nil
+}
+
+// HandleUpdate обрабатывает команды в чате и inline-запросы. Остальные обновления игнорируются
+func (s *BotServiceImpl) HandleUpdate(ctx context.Context, update *models.BotUpdate) error {
+	switch {
+	case update.Message != nil:
+		return s.handleMessage(ctx, update.Message)
+	case update.InlineQuery != nil:
+		return s.handleInlineQuery(ctx, update.InlineQuery)
+	}
+	return nil
+}
+
+// handleMessage выполняет команду из сообщения и отвечает в тот же чат
+func (s *BotServiceImpl) handleMessage(ctx context.Context, message *models.BotMessage) error {
+	if message.From == nil || message.From.IsBot {
+		return nil
+	}
+	command, args := s.parseCommand(message.Text)
+	if command == "" {
+		return nil
+	}
+
+	user, err := s.ensureUser(ctx, message.From)
+	if err != nil {
+		return err
+	}
+
+	var reply string
+	switch command {
+	case "start":
+		reply = s.start(ctx, args)
+	case "help":
+		reply = botHelpText
+	case "games":
+		reply, err = s.listGames(ctx)
+	case "balance":
+		reply = fmt.Sprintf("Your balance: %s TON, %s USDT",
+			formatNotificationAmount(user.BalanceTon), formatNotificationAmount(user.BalanceUsdt))
+	case "join":
+		reply = s.join(ctx, user.TelegramID, args)
+	case "guess":
+		reply = s.guess(ctx, user.TelegramID, args)
+	default:
+		reply = "Unknown command.\n\n" + botHelpText
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.messenger.SendMessage(ctx, message.Chat.ID, reply)
+}
+
+// parseCommand выделяет команду и аргументы из текста сообщения. Команды другому боту (/games@other) пропускаются
+func (s *BotServiceImpl) parseCommand(text string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", nil
+	}
+
+	command := strings.TrimPrefix(fields[0], "/")
+	if name, username, found := strings.Cut(command, "@"); found {
+		if !strings.EqualFold(username, s.botUsername) {
+			return "", nil
+		}
+		command = name
+	}
+	return strings.ToLower(command), fields[1:]
+}
+
+// ensureUser возвращает пользователя бота, при первом обращении регистрирует его
+func (s *BotServiceImpl) ensureUser(ctx context.Context, from *models.BotUser) (*models.User, error) {
+	user, err := s.userService.GetUser(ctx, from.ID)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, models.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user = &models.User{
+		TelegramID:   from.ID,
+		Username:     from.Username,
+		FirstName:    from.FirstName,
+		LastName:     from.LastName,
+		LanguageCode: from.LanguageCode,
+	}
+	if err := s.userService.CreateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	s.logger.Info("User registered via bot", zap.Uint64("telegram_id", user.TelegramID))
+	return user, nil
+}
+
+// start приветствует пользователя. Параметр /start <game> приходит по ссылке из карточки игры
+func (s *BotServiceImpl) start(ctx context.Context, args []string) string {
+	if len(args) > 0 {
+		game, err := s.gameService.GetGameByShortID(ctx, args[0])
+		if err == nil && !game.IsPrivate() {
+			return botGameCard(game)
+		}
+	}
+	return "Welcome to Wordle!\n\n" + botHelpText
+}
+
+// listGames возвращает список активных игр
+func (s *BotServiceImpl) listGames(ctx context.Context) (string, error) {
+	page, err := s.gameService.GetActiveGames(ctx, botGamesLimit, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get active games: %w", err)
+	}
+	if len(page.Games) == 0 {
+		return "There are no active games right now.", nil
+	}
+
+	var b strings.Builder
+	b.WriteString("Active games:\n")
+	for _, game := range page.Games {
+		fmt.Fprintf(&b, "\n%s - %s\n%d letters, %d tries, bet %s-%s %s, x%s\n",
+			game.ShortID, game.Title, game.Length, game.MaxTries,
+			formatNotificationAmount(game.MinBet), formatNotificationAmount(game.MaxBet), game.Currency,
+			formatNotificationAmount(game.RewardMultiplier))
+	}
+	b.WriteString("\nJoin with /join <game> <bet>")
+	return b.String(), nil
+}
+
+// join создаёт лобби, оплачивая ставку с баланса пользователя
+func (s *BotServiceImpl) join(ctx context.Context, userID uint64, args []string) string {
+	if len(args) != 2 {
+		return "Usage: /join <game> <bet>, for example /join AB12CD 1.5"
+	}
+	bet, err := strconv.ParseFloat(strings.ReplaceAll(args[1], ",", "."), 64)
+	if err != nil || bet <= 0 {
+		return "Bet must be a positive number."
+	}
+
+	game, err := s.gameService.GetGameByShortID(ctx, args[0])
+	if err != nil {
+		return "Game not found."
+	}
+	if game.CreatorID == userID {
+		return "You cannot play your own game."
+	}
+
+	lobby := &models.Lobby{
+		GameID:    game.ID,
+		UserID:    userID,
+		BetAmount: bet,
+	}
+	if err := s.lobbyService.CreateLobby(ctx, lobby); err != nil {
+		if errors.Is(err, models.ErrGameAccessDenied) {
+			return "This game is private. Open it from its invite link."
+		}
+		return "Could not join the game: " + err.Error()
+	}
+
+	return fmt.Sprintf("You joined \"%s\". Guess the %d-letter word in %d tries within %d min.\n"+
+		"Bet: %s %s, potential reward: %s %s.\n\nSend /guess <word>",
+		game.Title, game.Length, lobby.MaxTries, game.TimeLimit,
+		formatNotificationAmount(lobby.BetAmount), lobby.Currency,
+		formatNotificationAmount(lobby.PotentialReward), lobby.Currency)
+}
+
+// guess отправляет попытку в последнее активное лобби пользователя и возвращает подсказку эмодзи
+func (s *BotServiceImpl) guess(ctx context.Context, userID uint64, args []string) string {
+	if len(args) != 1 {
+		return "Usage: /guess <word>"
+	}
+
+	lobby, err := s.currentLobby(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to get user lobbies", zap.Uint64("user_id", userID), zap.Error(err))
+		return "Could not load your game, try again later."
+	}
+	if lobby == nil {
+		return "You have no active game. Use /games and /join to start one."
+	}
+
+	result, err := s.lobbyService.ProcessAttempt(ctx, lobby.ID, args[0])
+	if err != nil {
+		return "Guess rejected: " + err.Error()
+	}
+
+	updated, err := s.lobbyService.GetLobby(ctx, lobby.ID)
+	if err != nil {
+		return botFeedback(args[0], result)
+	}
+
+	var b strings.Builder
+	b.WriteString(botFeedback(args[0], result))
+	switch updated.Status {
+	case models.LobbyStatusActive:
+		fmt.Fprintf(&b, "\nTries left: %d", updated.MaxTries-updated.TriesUsed)
+	case models.LobbyStatusSuccess:
+		fmt.Fprintf(&b, "\n\nCongratulations! You won %s %s.",
+			formatNotificationAmount(updated.PotentialReward), updated.Currency)
+	default:
+		b.WriteString("\n\nGame over.")
+		if game, err := s.gameService.GetGame(ctx, updated.GameID); err == nil {
+			fmt.Fprintf(&b, " The word was %s.", strings.ToUpper(game.Word))
+		}
+	}
+	return b.String()
+}
+
+// currentLobby возвращает последнее активное лобби пользователя (nil - нет активных)
+func (s *BotServiceImpl) currentLobby(ctx context.Context, userID uint64) (*models.Lobby, error) {
+	page, err := s.lobbyService.GetUserLobbies(ctx, userID, botLobbiesScan, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, lobby := range page.Lobbies {
+		if lobby.Status == models.LobbyStatusActive && !lobby.IsExpired() {
+			return lobby, nil
+		}
+	}
+	return nil, nil
+}
+
+// handleInlineQuery отвечает на inline-запрос карточками активных игр, подходящих под текст запроса
+func (s *BotServiceImpl) handleInlineQuery(ctx context.Context, query *models.BotInlineQuery) error {
+	filter := models.GameSearchFilter{Query: strings.TrimSpace(query.Query)}
+	page, err := s.gameService.SearchGames(ctx, filter, botInlineGamesLimit, "")
+	if err != nil {
+		return fmt.Errorf("failed to search games: %w", err)
+	}
+
+	results := make([]models.BotInlineResult, 0, len(page.Games))
+	for _, game := range page.Games {
+		link := s.gameLink(game)
+		text := botGameCard(game)
+		if link != "" {
+			text += "\n\nPlay: " + link
+		}
+		results = append(results, models.BotInlineResult{
+			ID:    game.ID.String(),
+			Title: game.Title,
+			Description: fmt.Sprintf("%d letters, %d tries, bet %s-%s %s",
+				game.Length, game.MaxTries, formatNotificationAmount(game.MinBet),
+				formatNotificationAmount(game.MaxBet), game.Currency),
+			MessageText: text,
+			URL:         link,
+		})
+	}
+
+	return s.messenger.AnswerInlineQuery(ctx, query.ID, results)
+}
+
+// gameLink ссылка на игру в чате с ботом: /start с коротким ID игры
+func (s *BotServiceImpl) gameLink(game *models.Game) string {
+	if s.botUsername == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s?start=%s", s.botUsername, game.ShortID)
+}
+
+// botGameCard описание игры с подсказкой, как в неё вступить
+func botGameCard(game *models.Game) string {
+	return fmt.Sprintf("\"%s\": guess a %d-letter word in %d tries within %d min.\n"+
+		"Bet %s-%s %s, reward up to x%s.\n\nJoin with /join %s <bet>",
+		game.Title, game.Length, game.MaxTries, game.TimeLimit,
+		formatNotificationAmount(game.MinBet), formatNotificationAmount(game.MaxBet), game.Currency,
+		formatNotificationAmount(game.RewardMultiplier), game.ShortID)
+}
+
+// botFeedback строка подсказки: эмодзи по буквам и само слово
+func botFeedback(word string, result []int) string {
+	var b strings.Builder
+	for _, r := range result {
+		if r >= 0 && r < len(botFeedbackEmoji) {
+			b.WriteString(botFeedbackEmoji[r])
+		}
+	}
+	b.WriteString(" ")
+	b.WriteString(strings.ToUpper(strings.TrimSpace(word)))
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+type botFixture struct {
+	users *mocks.MockUserRepository
+	bot   *fakeBotAPI
+	game  *models.Game
+	svc   models.BotService
+}
+
+func setupBotService(t *testing.T) *botFixture {
+	t.Helper()
+	ctx := context.Background()
+	bot, client := newFakeBotAPI(t)
+	f := &botFixture{users: mocks.NewMockUserRepository(), bot: bot}
+
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), f.users, nil)
+	userService := NewUserServiceImpl(f.users, txService)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+	})
+	historyService := NewHistoryService(mocks.NewMockHistoryRepository(), gameRepo, f.users, lobbyRepo)
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        mocks.NewMockAttemptRepository(),
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"crane"}),
+		GameAccess:         gameService,
+		RiskGuard:          gameService,
+	})
+
+	_ = f.users.Create(ctx, &models.User{TelegramID: 1, Username: "creator"})
+	_ = f.users.Create(ctx, &models.User{TelegramID: 2, Username: "player", BalanceTon: 10})
+	f.game = &models.Game{
+		ID: uuid.New(), ShortID: "CRANE1", CreatorID: 1, Title: "Birds", Word: "crane", Length: 5, MaxTries: 2, TimeLimit: 5,
+		MinBet: 1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive, Visibility: models.GameVisibilityPublic,
+	}
+	_ = gameRepo.Create(ctx, f.game)
+
+	f.svc = NewBotService(userService, gameService, lobbyService, client, "wordle_bot")
+	return f
+}
+
+// send отправляет боту команду от пользователя в его личный чат и возвращает ответ бота
+func (f *botFixture) send(t *testing.T, userID uint64, text string) string {
+	t.Helper()
+	update := &models.BotUpdate{Message: &models.BotMessage{
+		From: &models.BotUser{ID: userID, FirstName: "Player"},
+		Chat: models.BotChat{ID: int64(userID), Type: "private"},
+		Text: text,
+	}}
+	chatID := strconv.FormatUint(userID, 10)
+	before := len(f.bot.texts[chatID])
+	if err := f.svc.HandleUpdate(context.Background(), update); err != nil {
+		t.Fatalf("HandleUpdate(%q) error = %v", text, err)
+	}
+	if len(f.bot.texts[chatID]) == before {
+		return ""
+	}
+	return f.bot.texts[chatID][len(f.bot.texts[chatID])-1]
+}
+
+func TestBotService_PlayInChat(t *testing.T) {
+	f := setupBotService(t)
+
+	if reply := f.send(t, 2, "/games"); !strings.Contains(reply, "CRANE1 - Birds") {
+		t.Errorf("/games reply = %q, want the active game", reply)
+	}
+	if reply := f.send(t, 1, "/join CRANE1 1"); reply != "You cannot play your own game." {
+		t.Errorf("/join own game reply = %q", reply)
+	}
+	if reply := f.send(t, 2, "/guess crane"); !strings.Contains(reply, "no active game") {
+		t.Errorf("/guess without a game reply = %q", reply)
+	}
+
+	if reply := f.send(t, 2, "/join CRANE1 2"); !strings.HasPrefix(reply, "You joined \"Birds\"") {
+		t.Fatalf("/join reply = %q", reply)
+	}
+	player, _ := f.users.GetByTelegramID(context.Background(), 2)
+	if player.BalanceTon != 8 {
+		t.Errorf("balance = %v, want 8 after the bet", player.BalanceTon)
+	}
+
+	if reply := f.send(t, 2, "/guess cr"); !strings.HasPrefix(reply, "Guess rejected") {
+		t.Errorf("/guess with a short word reply = %q, want a rejection", reply)
+	}
+	if reply := f.send(t, 2, "/guess caner"); reply != "🟩🟨🟨🟨🟨 CANER\nTries left: 1" {
+		t.Errorf("/guess reply = %q", reply)
+	}
+	if reply := f.send(t, 2, "/guess@wordle_bot crane"); !strings.HasPrefix(reply, "🟩🟩🟩🟩🟩 CRANE\n\nCongratulations!") {
+		t.Errorf("/guess winning reply = %q", reply)
+	}
+
+	// Команды другому боту и обычный текст остаются без ответа
+	if reply := f.send(t, 2, "/games@other_bot"); reply != "" {
+		t.Errorf("reply to another bot's command = %q, want none", reply)
+	}
+	if reply := f.send(t, 2, "hello"); reply != "" {
+		t.Errorf("reply to plain text = %q, want none", reply)
+	}
+}
+
+func TestBotService_LostGameAndRegistration(t *testing.T) {
+	ctx := context.Background()
+	f := setupBotService(t)
+
+	// Новый пользователь регистрируется при первой команде
+	if reply := f.send(t, 5, "/start CRANE1"); !strings.Contains(reply, "/join CRANE1 <bet>") {
+		t.Errorf("/start with a game reply = %q, want the game card", reply)
+	}
+	if _, err := f.users.GetByTelegramID(ctx, 5); err != nil {
+		t.Fatalf("user was not registered: %v", err)
+	}
+	if reply := f.send(t, 5, "/join CRANE1 1"); !strings.HasPrefix(reply, "Could not join the game: insufficient") {
+		t.Errorf("/join without balance reply = %q", reply)
+	}
+
+	_ = f.send(t, 2, "/join CRANE1 1")
+	_ = f.send(t, 2, "/guess caner")
+	if reply := f.send(t, 2, "/guess nacre"); !strings.HasSuffix(reply, "Game over. The word was CRANE.") {
+		t.Errorf("/guess losing reply = %q", reply)
+	}
+}
+
+func TestBotService_InlineQuery(t *testing.T) {
+	f := setupBotService(t)
+
+	err := f.svc.HandleUpdate(context.Background(), &models.BotUpdate{
+		InlineQuery: &models.BotInlineQuery{ID: "q1", From: models.BotUser{ID: 2}},
+	})
+	if err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	if len(f.bot.inlineResults) != 1 {
+		t.Fatalf("got %d inline answers, want 1", len(f.bot.inlineResults))
+	}
+
+	var results []struct {
+		ID                  string `json:"id"`
+		Title               string `json:"title"`
+		URL                 string `json:"url"`
+		InputMessageContent struct {
+			MessageText string `json:"message_text"`
+		} `json:"input_message_content"`
+	}
+	if err := json.Unmarshal([]byte(f.bot.inlineResults[0]), &results); err != nil {
+		t.Fatalf("failed to decode inline results: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Birds" || results[0].URL != "https://t.me/wordle_bot?start=CRANE1" {
+		t.Fatalf("results = %+v, want the Birds card with a bot link", results)
+	}
+	if !strings.Contains(results[0].InputMessageContent.MessageText, "/join CRANE1 <bet>") {
+		t.Errorf("message text = %q, want join instructions", results[0].InputMessageContent.MessageText)
+	}
+}
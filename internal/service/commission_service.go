@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/TakuroBreath/wordle/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// Параметры комиссии
+const (
+	defaultCommissionRate = 0.05                // Ставка комиссии по умолчанию
+	commissionTierWindow  = 30 * 24 * time.Hour // Период, за который считается оборот создателя для уровней
+	commissionMaxReport   = 366 * 24 * time.Hour
+)
+
+// CommissionServiceImpl представляет собой реализацию CommissionService
+type CommissionServiceImpl struct {
+	commissionRepo models.CommissionRepository
+	policy         models.CommissionPolicy
+	referrals      models.ReferralService
+	logger         *zap.Logger
+}
+
+// NewCommissionService создает новый экземпляр CommissionService.
+// Уровни сортируются по обороту, некорректные ставки переопределений отбрасываются.
+// referrals может быть nil, если реферальная программа не используется
+func NewCommissionService(
+	commissionRepo models.CommissionRepository,
+	policy models.CommissionPolicy,
+	referrals models.ReferralService,
+) models.CommissionService {
+	log := logger.GetLogger(zap.String("service", "commission"))
+
+	if !validCommissionRate(policy.DefaultRate) {
+		policy.DefaultRate = defaultCommissionRate
+	}
+
+	tiers := make([]models.CommissionTier, 0, len(policy.Tiers))
+	for _, tier := range policy.Tiers {
+		if tier.MinVolume < 0 || !validCommissionRate(tier.Rate) {
+			log.Warn("Ignoring invalid commission tier", zap.Float64("min_volume", tier.MinVolume), zap.Float64("rate", tier.Rate))
+			continue
+		}
+		tiers = append(tiers, tier)
+	}
+	slices.SortFunc(tiers, func(a, b models.CommissionTier) int {
+		switch {
+		case a.MinVolume < b.MinVolume:
+			return -1
+		case a.MinVolume > b.MinVolume:
+			return 1
+		}
+		return 0
+	})
+	policy.Tiers = tiers
+
+	for creatorID, rate := range policy.CreatorRates {
+		if !validCommissionRate(rate) {
+			log.Warn("Ignoring invalid creator commission rate", zap.Uint64("creator_id", creatorID), zap.Float64("rate", rate))
+			delete(policy.CreatorRates, creatorID)
+		}
+	}
+	for gameID, rate := range policy.GameRates {
+		if !validCommissionRate(rate) {
+			log.Warn("Ignoring invalid game commission rate", zap.String("game_id", gameID.String()), zap.Float64("rate", rate))
+			delete(policy.GameRates, gameID)
+		}
+	}
+
+	return &CommissionServiceImpl{
+		commissionRepo: commissionRepo,
+		policy:         policy,
+		referrals:      referrals,
+		logger:         log,
+	}
+}
+
+// validCommissionRate проверяет, что ставка комиссии в [0, 1)
+func validCommissionRate(rate float64) bool {
+	return rate >= 0 && rate < 1
+}
+
+// CalculateCommission рассчитывает комиссию по ставке по умолчанию
+func (s *CommissionServiceImpl) CalculateCommission(amount float64) float64 {
+	return amount * s.policy.DefaultRate
+}
+
+// GetCommissionRate возвращает ставку комиссии по умолчанию
+func (s *CommissionServiceImpl) GetCommissionRate() float64 {
+	return s.policy.DefaultRate
+}
+
+// DeductCommission вычитает комиссию по ставке по умолчанию
+func (s *CommissionServiceImpl) DeductCommission(amount float64) (float64, float64) {
+	commission := s.CalculateCommission(amount)
+	return amount - commission, commission
+}
+
+// GetGameRate возвращает ставку комиссии для игры: ставку игры, ставку создателя,
+// ставку уровня по обороту создателя за последние 30 дней или ставку по умолчанию
+func (s *CommissionServiceImpl) GetGameRate(ctx context.Context, game *models.Game) float64 {
+	if rate, ok := s.policy.GameRates[game.ID]; ok {
+		return rate
+	}
+	if rate, ok := s.policy.CreatorRates[game.CreatorID]; ok {
+		return rate
+	}
+	if len(s.policy.Tiers) == 0 {
+		return s.policy.DefaultRate
+	}
+
+	volume, err := s.commissionRepo.GetCreatorVolume(ctx, game.CreatorID, game.Currency, time.Now().Add(-commissionTierWindow))
+	if err != nil {
+		s.logger.Error("Failed to get creator volume, using default commission rate",
+			zap.Uint64("creator_id", game.CreatorID), zap.Error(err))
+		return s.policy.DefaultRate
+	}
+
+	rate := s.policy.DefaultRate
+	for _, tier := range s.policy.Tiers {
+		if volume < tier.MinVolume {
+			break
+		}
+		rate = tier.Rate
+	}
+	return rate
+}
+
+// BookCommission зачисляет комиссию на счёт сервиса
+func (s *CommissionServiceImpl) BookCommission(ctx context.Context, entry *models.CommissionEntry) error {
+	if entry.Amount < 0 {
+		return errors.New("commission amount cannot be negative")
+	}
+	if entry.Amount == 0 && entry.Volume == 0 {
+		return nil
+	}
+
+	booked, err := s.commissionRepo.Book(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("failed to book commission: %w", err)
+	}
+	if !booked {
+		s.logger.Debug("Commission already booked",
+			zap.String("source", entry.Source),
+			zap.String("reference_id", entry.ReferenceID.String()))
+		return nil
+	}
+
+	// Записываем комиссию (revenue) в метрики
+	metrics.RecordCommission(entry.Currency, entry.Amount)
+
+	// Реферер получает долю комиссии приглашённого пользователя; ошибка начисления не отменяет комиссию
+	if s.referrals != nil {
+		if err := s.referrals.AccrueCommission(ctx, entry); err != nil {
+			s.logger.Error("Failed to accrue referral commission",
+				zap.String("entry_id", entry.ID.String()),
+				zap.Uint64("user_id", entry.UserID),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// GetHouseAccounts возвращает счета сервиса
+func (s *CommissionServiceImpl) GetHouseAccounts(ctx context.Context) ([]*models.HouseAccount, error) {
+	return s.commissionRepo.GetHouseAccounts(ctx)
+}
+
+// GetReport возвращает заработанную комиссию по периодам за [from, to), не больше чем за год
+func (s *CommissionServiceImpl) GetReport(ctx context.Context, period string, from, to time.Time) ([]*models.CommissionReport, error) {
+	if !models.IsValidCommissionPeriod(period) {
+		return nil, errors.New("invalid period: must be day, week or month")
+	}
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+	if to.Sub(from) > commissionMaxReport {
+		return nil, errors.New("report range cannot exceed one year")
+	}
+	return s.commissionRepo.GetReport(ctx, period, from, to)
+}
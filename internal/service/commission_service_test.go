@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// newTestCommissionService создает сервис комиссии со ставкой 5% без уровней и переопределений
+func newTestCommissionService() models.CommissionService {
+	return NewCommissionService(mocks.NewMockCommissionRepository(), models.CommissionPolicy{DefaultRate: 0.05}, nil)
+}
+
+func TestCommissionService_GetGameRate(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockCommissionRepository()
+	gameID := uuid.New()
+	commission := NewCommissionService(repo, models.CommissionPolicy{
+		DefaultRate: 0.05,
+		Tiers: []models.CommissionTier{
+			{MinVolume: 1000, Rate: 0.02},
+			{MinVolume: 100, Rate: 0.04},
+			{MinVolume: 500, Rate: 1.5}, // Некорректный уровень отбрасывается
+		},
+		CreatorRates: map[uint64]float64{2: 0.01},
+		GameRates:    map[uuid.UUID]float64{gameID: 0},
+	}, nil)
+
+	// Оборот создателя 1: 150 TON за последние 30 дней и 5000 TON раньше
+	book := func(volume float64, createdAt time.Time) {
+		_, _ = repo.Book(ctx, &models.CommissionEntry{
+			Source: models.CommissionSourceLobbyLoss, ReferenceID: uuid.New(), CreatorID: 1,
+			Currency: models.CurrencyTON, Volume: volume, CreatedAt: createdAt,
+		})
+	}
+	book(150, time.Now().Add(-time.Hour))
+	book(5000, time.Now().Add(-40*24*time.Hour))
+
+	tests := []struct {
+		name string
+		game models.Game
+		want float64
+	}{
+		{name: "ставка по умолчанию", game: models.Game{ID: uuid.New(), CreatorID: 3, Currency: models.CurrencyTON}, want: 0.05},
+		{name: "уровень по обороту", game: models.Game{ID: uuid.New(), CreatorID: 1, Currency: models.CurrencyTON}, want: 0.04},
+		{name: "оборот в другой валюте", game: models.Game{ID: uuid.New(), CreatorID: 1, Currency: models.CurrencyUSDT}, want: 0.05},
+		{name: "ставка создателя", game: models.Game{ID: uuid.New(), CreatorID: 2, Currency: models.CurrencyTON}, want: 0.01},
+		{name: "ставка игры важнее ставки создателя", game: models.Game{ID: gameID, CreatorID: 2, Currency: models.CurrencyTON}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commission.GetGameRate(ctx, &tt.game); got != tt.want {
+				t.Errorf("GetGameRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLobbyService_BooksCommission(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	commissionRepo := mocks.NewMockCommissionRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	historyService := NewHistoryService(mocks.NewMockHistoryRepository(), gameRepo, userRepo, lobbyRepo)
+
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 100, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	commission := NewCommissionService(commissionRepo, models.CommissionPolicy{
+		DefaultRate: 0.05,
+		GameRates:   map[uuid.UUID]float64{game.ID: 0.1},
+	}, nil)
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        mocks.NewMockAttemptRepository(),
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         commission,
+		Dictionary:         dictionary.New([]string{"слово"}),
+	})
+
+	// Проигрыш: 10% ставки на счёт сервиса, остальное в пул
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "loser", BalanceTon: 2})
+	lost := &models.Lobby{GameID: game.ID, UserID: 1, BetAmount: 2}
+	if err := lobbyService.CreateLobby(ctx, lost); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	if err := lobbyService.FinishLobby(ctx, lost.ID, false); err != nil {
+		t.Fatalf("FinishLobby() error = %v", err)
+	}
+	if want := 100 + 2*0.9; math.Abs(game.RewardPoolTon-want) > 1e-9 {
+		t.Errorf("reward pool = %v, want %v", game.RewardPoolTon, want)
+	}
+
+	// Победа с первой попытки: 10% выигрыша удерживается и зачисляется на счёт сервиса
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 2, Username: "winner", BalanceTon: 1})
+	won := &models.Lobby{GameID: game.ID, UserID: 2, BetAmount: 1}
+	if err := lobbyService.CreateLobby(ctx, won); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	if _, err := lobbyService.ProcessAttempt(ctx, won.ID, "слово"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+	gross := 1 * 2 * models.RewardTriesBonus(1, 6)
+	assertTonBalance(t, userRepo, 2, gross*0.9)
+
+	accounts, _ := commission.GetHouseAccounts(ctx)
+	if want := 2*0.1 + gross*0.1; len(accounts) != 1 || math.Abs(accounts[0].Balance-want) > 1e-9 {
+		t.Fatalf("house accounts = %+v, want %v TON", accounts, want)
+	}
+
+	now := time.Now()
+	report, err := commission.GetReport(ctx, models.CommissionPeriodDay, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetReport() error = %v", err)
+	}
+	if len(report) != 2 || report[0].Source != models.CommissionSourceLobbyLoss || report[1].Source != models.CommissionSourceLobbyWin ||
+		report[0].Volume != 2 || report[1].Volume != 1 || !report[0].PeriodStart.Equal(models.CommissionPeriodStart(now, models.CommissionPeriodDay)) {
+		t.Errorf("report = %+v, want loss and win rows for today", report)
+	}
+
+	// Повторная запись по тому же лобби не зачисляется
+	_ = commission.BookCommission(ctx, &models.CommissionEntry{
+		Source: models.CommissionSourceLobbyWin, ReferenceID: won.ID, Currency: models.CurrencyTON, Volume: 1, Amount: 5,
+	})
+	if again, _ := commission.GetHouseAccounts(ctx); again[0].Balance != accounts[0].Balance {
+		t.Errorf("house balance after duplicate booking = %v, want %v", again[0].Balance, accounts[0].Balance)
+	}
+
+	for _, args := range []struct {
+		period   string
+		from, to time.Time
+	}{
+		{"year", now.Add(-time.Hour), now},
+		{models.CommissionPeriodDay, now, now.Add(-time.Hour)},
+		{models.CommissionPeriodMonth, now.AddDate(-2, 0, 0), now},
+	} {
+		if _, err := commission.GetReport(ctx, args.period, args.from, args.to); err == nil {
+			t.Errorf("GetReport(%s, %v, %v) should fail", args.period, args.from, args.to)
+		}
+	}
+}
+
+func TestCommissionPeriodStart(t *testing.T) {
+	at := time.Date(2025, 3, 13, 15, 30, 0, 0, time.UTC) // Четверг
+	tests := []struct {
+		period string
+		want   time.Time
+	}{
+		{models.CommissionPeriodDay, time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{models.CommissionPeriodWeek, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{models.CommissionPeriodMonth, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := models.CommissionPeriodStart(at, tt.period); !got.Equal(tt.want) {
+			t.Errorf("CommissionPeriodStart(%s) = %v, want %v", tt.period, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// analyticsMaxRange максимальный период аналитики за один запрос
+const analyticsMaxRange = 366 * 24 * time.Hour
+
+// CreatorAnalyticsServiceImpl представляет собой реализацию CreatorAnalyticsService
+type CreatorAnalyticsServiceImpl struct {
+	analyticsRepo models.AnalyticsRepository
+	gameRepo      models.GameRepository
+	logger        *zap.Logger
+}
+
+// NewCreatorAnalyticsService создает новый экземпляр CreatorAnalyticsService
+func NewCreatorAnalyticsService(analyticsRepo models.AnalyticsRepository, gameRepo models.GameRepository) models.CreatorAnalyticsService {
+	return &CreatorAnalyticsServiceImpl{
+		analyticsRepo: analyticsRepo,
+		gameRepo:      gameRepo,
+		logger:        logger.GetLogger(zap.String("service", "creator_analytics")),
+	}
+}
+
+// GetGameAnalytics возвращает аналитику игры за [from, to) с группировкой по периодам.
+// Частые попытки игроков возвращаются без загаданного слова
+func (s *CreatorAnalyticsServiceImpl) GetGameAnalytics(ctx context.Context, gameID uuid.UUID, creatorID uint64, period string, from, to time.Time) (*models.GameAnalytics, error) {
+	if err := validateAnalyticsRange(period, from, to); err != nil {
+		return nil, err
+	}
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID != creatorID {
+		return nil, models.ErrNotGameCreator
+	}
+
+	query := &models.AnalyticsQuery{CreatorID: creatorID, GameID: &game.ID, Period: period, From: from, To: to}
+	series, err := s.series(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &models.GameAnalytics{
+		GameID:   game.ID,
+		Title:    game.Title,
+		Status:   game.Status,
+		Period:   period,
+		From:     from,
+		To:       to,
+		Totals:   models.AnalyticsTotals{Currency: game.Currency},
+		Series:   series,
+		Reserved: game.ReservedAmount,
+	}
+	for _, bucket := range series {
+		analytics.Totals.Add(bucket)
+	}
+	analytics.CurrentPool = game.RewardPoolUsdt
+	if game.Currency == models.CurrencyTON {
+		analytics.CurrentPool = game.RewardPoolTon
+	}
+
+	if analytics.TriesHistogram, err = s.analyticsRepo.GetTriesHistogram(ctx, query); err != nil {
+		return nil, err
+	}
+
+	analytics.TopGuesses, err = s.analyticsRepo.GetTopGuesses(ctx, game.ID, from, to, game.Word, models.AnalyticsTopGuesses)
+	if err != nil {
+		return nil, err
+	}
+	if analytics.TopGuesses == nil {
+		analytics.TopGuesses = []models.WordCount{}
+	}
+
+	return analytics, nil
+}
+
+// GetCreatorAnalytics возвращает аналитику всех игр создателя за [from, to) с итогами по валютам и по играм
+func (s *CreatorAnalyticsServiceImpl) GetCreatorAnalytics(ctx context.Context, creatorID uint64, period string, from, to time.Time) (*models.CreatorAnalytics, error) {
+	if creatorID == 0 {
+		return nil, errors.New("creator ID cannot be zero")
+	}
+	if err := validateAnalyticsRange(period, from, to); err != nil {
+		return nil, err
+	}
+
+	query := &models.AnalyticsQuery{CreatorID: creatorID, Period: period, From: from, To: to}
+	series, err := s.series(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &models.CreatorAnalytics{
+		CreatorID: creatorID,
+		Period:    period,
+		From:      from,
+		To:        to,
+		Totals:    []models.AnalyticsTotals{},
+		Series:    series,
+	}
+
+	totals := make(map[string]*models.AnalyticsTotals)
+	for _, bucket := range series {
+		total, ok := totals[bucket.Currency]
+		if !ok {
+			total = &models.AnalyticsTotals{Currency: bucket.Currency}
+			totals[bucket.Currency] = total
+		}
+		total.Add(bucket)
+	}
+	for _, total := range totals {
+		analytics.Totals = append(analytics.Totals, *total)
+	}
+	sort.Slice(analytics.Totals, func(i, j int) bool {
+		return analytics.Totals[i].Currency < analytics.Totals[j].Currency
+	})
+
+	if analytics.TriesHistogram, err = s.analyticsRepo.GetTriesHistogram(ctx, query); err != nil {
+		return nil, err
+	}
+
+	if analytics.Games, err = s.analyticsRepo.GetGameSummaries(ctx, query); err != nil {
+		return nil, err
+	}
+	if analytics.Games == nil {
+		analytics.Games = []*models.AnalyticsGameSummary{}
+	}
+
+	return analytics, nil
+}
+
+// series возвращает показатели по периодам с расчётным пулом и его использованием.
+// Пул на начало выборки восстанавливается по всей предыдущей истории игр
+func (s *CreatorAnalyticsServiceImpl) series(ctx context.Context, query *models.AnalyticsQuery) ([]*models.AnalyticsBucket, error) {
+	before := *query
+	before.From, before.To = time.Time{}, query.From
+	opening, err := s.analyticsRepo.GetBuckets(ctx, &before)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := make(map[string]float64)
+	for _, bucket := range opening {
+		balance[bucket.Currency] += bucket.PoolIn - bucket.PoolOut + bucket.NetPnL
+	}
+
+	series, err := s.analyticsRepo.GetBuckets(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil {
+		return []*models.AnalyticsBucket{}, nil
+	}
+
+	for _, bucket := range series {
+		// Доступно в периоде: остаток на начало, пополнения и поступившие в пул проигранные ставки
+		available := balance[bucket.Currency] + bucket.PoolIn + bucket.NetPnL + bucket.Payouts
+		if available > 0 {
+			bucket.Utilization = bucket.Payouts / available
+		}
+		balance[bucket.Currency] += bucket.PoolIn - bucket.PoolOut + bucket.NetPnL
+		bucket.PoolBalance = balance[bucket.Currency]
+	}
+
+	return series, nil
+}
+
+// validateAnalyticsRange проверяет период группировки и интервал аналитики
+func validateAnalyticsRange(period string, from, to time.Time) error {
+	if !models.IsValidCommissionPeriod(period) {
+		return errors.New("invalid period: must be day, week or month")
+	}
+	if !from.Before(to) {
+		return errors.New("from must be before to")
+	}
+	if to.Sub(from) > analyticsMaxRange {
+		return errors.New("analytics range cannot exceed one year")
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+type analyticsFixture struct {
+	games        *mocks.MockGameRepository
+	history      *mocks.MockHistoryRepository
+	attempts     *mocks.MockAttemptRepository
+	transactions *mocks.MockTransactionRepository
+	commission   *mocks.MockCommissionRepository
+	analytics    models.CreatorAnalyticsService
+}
+
+func setupCreatorAnalyticsService(t *testing.T) *analyticsFixture {
+	t.Helper()
+	f := &analyticsFixture{
+		games:        mocks.NewMockGameRepository(),
+		history:      mocks.NewMockHistoryRepository(),
+		attempts:     mocks.NewMockAttemptRepository(),
+		transactions: mocks.NewMockTransactionRepository(),
+		commission:   mocks.NewMockCommissionRepository(),
+	}
+	repo := mocks.NewMockAnalyticsRepository(f.games, f.history, f.attempts, f.transactions, f.commission)
+	f.analytics = NewCreatorAnalyticsService(repo, f.games)
+	return f
+}
+
+// addLobby добавляет в историю и журнал комиссии рассчитанное лобби игры
+func (f *analyticsFixture) addLobby(game *models.Game, status string, bet, reward, commission float64, tries int, at time.Time) {
+	ctx := context.Background()
+	lobbyID := uuid.New()
+	_ = f.history.Create(ctx, &models.History{UserID: 10, GameID: game.ID, LobbyID: lobbyID, Status: status,
+		BetAmount: bet, Reward: reward, Currency: game.Currency, TriesUsed: tries, CreatedAt: at})
+
+	source := models.CommissionSourceLobbyWin
+	if status == models.HistoryStatusCreatorWin {
+		source = models.CommissionSourceLobbyLoss
+	}
+	if commission > 0 {
+		_, _ = f.commission.Book(ctx, &models.CommissionEntry{Source: source, ReferenceID: lobbyID, GameID: &game.ID,
+			CreatorID: game.CreatorID, UserID: 10, Currency: game.Currency, Volume: bet, Rate: 0.05,
+			Amount: commission, CreatedAt: at})
+	}
+}
+
+func TestCreatorAnalyticsService_GetGameAnalytics(t *testing.T) {
+	ctx := context.Background()
+	f := setupCreatorAnalyticsService(t)
+
+	now := time.Now()
+	game := &models.Game{CreatorID: 1, Word: "слово", Title: "Test", Currency: models.CurrencyTON, RewardPoolTon: 98.4,
+		ReservedAmount: 3, Status: models.GameStatusActive}
+	_ = f.games.Create(ctx, game)
+
+	// Депозит до начала выборки формирует пул на начало периода
+	_ = f.transactions.Create(ctx, &models.Transaction{UserID: 1, Type: models.TransactionTypeGameDeposit, Amount: 100,
+		Currency: models.CurrencyTON, Status: models.TransactionStatusCompleted, GameID: &game.ID,
+		CreatedAt: now.AddDate(0, 0, -40)})
+
+	f.addLobby(game, models.HistoryStatusPlayerWin, 1, 3, 0.15, 2, now)
+	f.addLobby(game, models.HistoryStatusCreatorWin, 2, 0, 0.1, 6, now)
+	f.addLobby(game, models.HistoryStatusCashOut, 1, 0.5, 0, 1, now)
+
+	for i, word := range []string{"СЛОВО", "сокол", "сокол", "лампа", "слово"} {
+		_ = f.attempts.Create(ctx, &models.Attempt{GameID: game.ID, UserID: 10, Word: word,
+			CreatedAt: now.Add(time.Duration(i) * time.Second)})
+	}
+
+	from, to := now.AddDate(0, 0, -30), now.Add(time.Hour)
+	analytics, err := f.analytics.GetGameAnalytics(ctx, game.ID, 1, models.CommissionPeriodDay, from, to)
+	if err != nil {
+		t.Fatalf("GetGameAnalytics() error = %v", err)
+	}
+
+	totals := analytics.Totals
+	if totals.Lobbies != 3 || totals.Wins != 1 || totals.Losses != 1 || totals.CashOuts != 1 {
+		t.Errorf("lobbies/wins/losses/cash-outs = %d/%d/%d/%d, want 3/1/1/1", totals.Lobbies, totals.Wins, totals.Losses, totals.CashOuts)
+	}
+	// Проигранная ставка 2 за вычетом комиссии 0.1 минус выплаты 3.5
+	if math.Abs(totals.GrossBets-4) > 1e-9 || math.Abs(totals.Payouts-3.5) > 1e-9 ||
+		math.Abs(totals.Commission-0.25) > 1e-9 || math.Abs(totals.NetPnL+1.6) > 1e-9 {
+		t.Errorf("totals = %+v, want bets 4, payouts 3.5, commission 0.25, net -1.6", totals)
+	}
+	if analytics.TriesHistogram[2] != 1 || len(analytics.TriesHistogram) != 1 {
+		t.Errorf("tries histogram = %v, want one win in 2 tries", analytics.TriesHistogram)
+	}
+
+	if len(analytics.Series) != 1 {
+		t.Fatalf("got %d buckets, want 1", len(analytics.Series))
+	}
+	bucket := analytics.Series[0]
+	if math.Abs(bucket.PoolBalance-98.4) > 1e-9 || math.Abs(bucket.Utilization-3.5/101.9) > 1e-9 {
+		t.Errorf("pool balance = %v, utilization = %v, want 98.4 and %v", bucket.PoolBalance, bucket.Utilization, 3.5/101.9)
+	}
+
+	// Загаданное слово не попадает в частые попытки
+	if len(analytics.TopGuesses) != 2 || analytics.TopGuesses[0] != (models.WordCount{Word: "сокол", Count: 2}) {
+		t.Errorf("top guesses = %+v, want сокол and лампа only", analytics.TopGuesses)
+	}
+
+	if _, err := f.analytics.GetGameAnalytics(ctx, game.ID, 2, models.CommissionPeriodDay, from, to); !errors.Is(err, models.ErrNotGameCreator) {
+		t.Errorf("GetGameAnalytics() by another user error = %v, want ErrNotGameCreator", err)
+	}
+	if _, err := f.analytics.GetGameAnalytics(ctx, game.ID, 1, "year", from, to); err == nil {
+		t.Error("GetGameAnalytics() with unknown period should fail")
+	}
+}
+
+func TestCreatorAnalyticsService_GetCreatorAnalytics(t *testing.T) {
+	ctx := context.Background()
+	f := setupCreatorAnalyticsService(t)
+
+	now := time.Now()
+	ton := &models.Game{CreatorID: 1, Word: "слово", Currency: models.CurrencyTON}
+	usdt := &models.Game{CreatorID: 1, Word: "лампа", Currency: models.CurrencyUSDT}
+	other := &models.Game{CreatorID: 2, Word: "сокол", Currency: models.CurrencyTON}
+	for _, game := range []*models.Game{ton, usdt, other} {
+		_ = f.games.Create(ctx, game)
+	}
+
+	f.addLobby(ton, models.HistoryStatusPlayerWin, 1, 2, 0.1, 3, now)
+	f.addLobby(ton, models.HistoryStatusPlayerWin, 1, 2, 0.1, 4, now.AddDate(0, 0, -1))
+	f.addLobby(usdt, models.HistoryStatusCreatorWin, 10, 0, 0.5, 6, now)
+	f.addLobby(other, models.HistoryStatusPlayerWin, 1, 2, 0.1, 1, now)
+	// Лобби вне выбранного периода не учитывается
+	f.addLobby(ton, models.HistoryStatusPlayerWin, 1, 2, 0.1, 5, now.AddDate(0, 0, -60))
+
+	analytics, err := f.analytics.GetCreatorAnalytics(ctx, 1, models.CommissionPeriodDay, now.AddDate(0, 0, -30), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetCreatorAnalytics() error = %v", err)
+	}
+
+	if len(analytics.Totals) != 2 || analytics.Totals[0].Currency != models.CurrencyTON || analytics.Totals[0].Lobbies != 2 ||
+		analytics.Totals[1].Currency != models.CurrencyUSDT || math.Abs(analytics.Totals[1].NetPnL-9.5) > 1e-9 {
+		t.Errorf("totals = %+v, want 2 TON lobbies and USDT net 9.5", analytics.Totals)
+	}
+	if len(analytics.TriesHistogram) != 2 || analytics.TriesHistogram[3] != 1 || analytics.TriesHistogram[4] != 1 {
+		t.Errorf("tries histogram = %v, want wins in 3 and 4 tries", analytics.TriesHistogram)
+	}
+	if len(analytics.Series) != 3 {
+		t.Errorf("got %d buckets, want 3 (two days in TON, one in USDT)", len(analytics.Series))
+	}
+	if len(analytics.Games) != 2 || analytics.Games[0].GameID != ton.ID || analytics.Games[0].Lobbies != 2 {
+		t.Errorf("games = %+v, want TON game with 2 lobbies first", analytics.Games)
+	}
+}
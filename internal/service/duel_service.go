@@ -0,0 +1,547 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Параметры дуэлей
+const (
+	duelInviteTTL        = 24 * time.Hour // Срок принятия вызова
+	duelDefaultMaxTries  = 6
+	duelMaxTries         = 20
+	duelDefaultTimeLimit = 10 // Минут на игру по умолчанию
+	duelMaxTimeLimit     = 60
+)
+
+// errDuelWordUnknown возвращается для загаданного слова или попытки, которых нет в словаре
+var errDuelWordUnknown = errors.New("word is not in the dictionary")
+
+// DuelServiceImpl представляет собой реализацию DuelService
+type DuelServiceImpl struct {
+	duelRepo           models.DuelRepository
+	userService        models.UserService
+	transactionService models.TransactionService
+	commission         models.CommissionService
+	dictionary         *dictionary.Dictionary
+	botUsername        string
+	miniAppName        string
+	transactor         models.Transactor
+	logger             *zap.Logger
+}
+
+// NewDuelService создает новый экземпляр DuelService.
+// Загаданные слова и попытки проверяются по словарю dict (nil - встроенный словарь).
+// botUsername и miniAppName используются для ссылок-приглашений (t.me/<bot>/<app>?startapp=...).
+// transactor объединяет завершение дуэли с выплатой эскроу и комиссией
+func NewDuelService(
+	duelRepo models.DuelRepository,
+	userService models.UserService,
+	transactionService models.TransactionService,
+	commission models.CommissionService,
+	dict *dictionary.Dictionary,
+	botUsername string,
+	miniAppName string,
+	transactor models.Transactor,
+) models.DuelService {
+	if dict == nil {
+		dict = dictionary.Default()
+	}
+	return &DuelServiceImpl{
+		duelRepo:           duelRepo,
+		userService:        userService,
+		transactionService: transactionService,
+		commission:         commission,
+		dictionary:         dict,
+		botUsername:        botUsername,
+		miniAppName:        miniAppName,
+		transactor:         transactor,
+		logger:             logger.GetLogger(zap.String("service", "duel")),
+	}
+}
+
+// CreateDuel создает вызов на дуэль и переводит ставку вызвавшего в эскроу
+func (s *DuelServiceImpl) CreateDuel(ctx context.Context, duel *models.Duel, word string) error {
+	log := s.logger.With(zap.String("method", "CreateDuel"))
+
+	if duel == nil {
+		return errors.New("duel is nil")
+	}
+	if duel.ChallengerID == 0 {
+		return errors.New("invalid challenger ID")
+	}
+	if duel.OpponentID == duel.ChallengerID {
+		return errors.New("cannot challenge yourself")
+	}
+
+	word = dictionary.Normalize(word)
+	length := len([]rune(word))
+	if length < minWordLength || length > maxWordLength {
+		return fmt.Errorf("invalid word length (must be %d-%d)", minWordLength, maxWordLength)
+	}
+	if !s.dictionary.Contains(word) {
+		return errDuelWordUnknown
+	}
+
+	if duel.Stake <= 0 {
+		return errors.New("stake must be positive")
+	}
+	if duel.Currency != models.CurrencyTON && duel.Currency != models.CurrencyUSDT {
+		return fmt.Errorf("invalid currency: %s", duel.Currency)
+	}
+	if duel.MaxTries == 0 {
+		duel.MaxTries = duelDefaultMaxTries
+	}
+	if duel.MaxTries < 0 || duel.MaxTries > duelMaxTries {
+		return fmt.Errorf("max_tries must be between 1 and %d", duelMaxTries)
+	}
+	if duel.TimeLimit == 0 {
+		duel.TimeLimit = duelDefaultTimeLimit
+	}
+	if duel.TimeLimit < 0 || duel.TimeLimit > duelMaxTimeLimit {
+		return fmt.Errorf("time_limit must be between 1 and %d minutes", duelMaxTimeLimit)
+	}
+
+	if duel.OpponentID != 0 {
+		if _, err := s.userService.GetUser(ctx, duel.OpponentID); err != nil {
+			return fmt.Errorf("opponent not found: %w", err)
+		}
+	}
+
+	shortID := generateShortID()
+	for attempts := 0; attempts < 10; attempts++ {
+		existing, err := s.duelRepo.GetByShortID(ctx, shortID)
+		if err != nil || existing == nil {
+			break
+		}
+		shortID = generateShortID()
+	}
+
+	duel.ID = uuid.New()
+	duel.ShortID = shortID
+	duel.ChallengerWord = word
+	duel.WordLength = length
+	duel.Status = models.DuelStatusPending
+	duel.InviteExpiresAt = time.Now().Add(duelInviteTTL)
+
+	if err := s.transactionService.HoldDuelStake(ctx, duel.ChallengerID, duel.Stake, duel.Currency, duel.ID); err != nil {
+		return fmt.Errorf("failed to hold stake: %w", err)
+	}
+
+	if err := s.duelRepo.Create(ctx, duel); err != nil {
+		s.refund(ctx, duel, duel.ChallengerID)
+		return fmt.Errorf("failed to create duel: %w", err)
+	}
+
+	duel.InviteLink = s.inviteLink(duel)
+
+	log.Info("Duel created",
+		zap.String("duel_id", duel.ID.String()),
+		zap.String("short_id", duel.ShortID),
+		zap.Uint64("challenger_id", duel.ChallengerID),
+		zap.Uint64("opponent_id", duel.OpponentID),
+		zap.Float64("stake", duel.Stake))
+
+	return nil
+}
+
+// GetDuel получает дуэль по ID
+func (s *DuelServiceImpl) GetDuel(ctx context.Context, id uuid.UUID) (*models.Duel, error) {
+	duel, err := s.duelRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	duel.InviteLink = s.inviteLink(duel)
+	return duel, nil
+}
+
+// GetDuelByInvite получает дуэль по параметру startapp или коду приглашения
+func (s *DuelServiceImpl) GetDuelByInvite(ctx context.Context, startParam string) (*models.Duel, error) {
+	shortID := models.ParseDuelStartParam(startParam)
+	if shortID == "" {
+		return nil, errors.New("invite code cannot be empty")
+	}
+
+	duel, err := s.duelRepo.GetByShortID(ctx, shortID)
+	if err != nil {
+		return nil, err
+	}
+	duel.InviteLink = s.inviteLink(duel)
+	return duel, nil
+}
+
+// GetUserDuels получает дуэли пользователя
+func (s *DuelServiceImpl) GetUserDuels(ctx context.Context, userID uint64, limit, offset int) ([]*models.Duel, error) {
+	duels, err := s.duelRepo.GetByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, duel := range duels {
+		duel.InviteLink = s.inviteLink(duel)
+	}
+	return duels, nil
+}
+
+// AcceptDuel принимает вызов: соперник загадывает слово, ставка переводится в эскроу, игра начинается
+func (s *DuelServiceImpl) AcceptDuel(ctx context.Context, startParam string, userID uint64, word string) (*models.Duel, error) {
+	log := s.logger.With(zap.String("method", "AcceptDuel"), zap.Uint64("user_id", userID))
+
+	duel, err := s.GetDuelByInvite(ctx, startParam)
+	if err != nil {
+		return nil, err
+	}
+
+	if duel.Status != models.DuelStatusPending {
+		return nil, fmt.Errorf("duel is not pending, status: %s", duel.Status)
+	}
+	if duel.IsInviteExpired() {
+		s.expireInvite(ctx, duel)
+		return nil, errors.New("invite has expired")
+	}
+	if duel.ChallengerID == userID {
+		return nil, errors.New("cannot accept own challenge")
+	}
+	if duel.OpponentID != 0 && duel.OpponentID != userID {
+		return nil, errors.New("invite is addressed to another user")
+	}
+
+	word = dictionary.Normalize(word)
+	if len([]rune(word)) != duel.WordLength {
+		return nil, fmt.Errorf("invalid word length: expected %d, got %d", duel.WordLength, len([]rune(word)))
+	}
+	if !s.dictionary.Contains(word) {
+		return nil, errDuelWordUnknown
+	}
+
+	if err := s.transactionService.HoldDuelStake(ctx, userID, duel.Stake, duel.Currency, duel.ID); err != nil {
+		return nil, fmt.Errorf("failed to hold stake: %w", err)
+	}
+
+	now := time.Now()
+	endsAt := now.Add(time.Duration(duel.TimeLimit) * time.Minute)
+	started := *duel
+	started.OpponentID = userID
+	started.OpponentWord = word
+	started.StartedAt = &now
+	started.EndsAt = &endsAt
+
+	accepted, err := s.duelRepo.Accept(ctx, &started)
+	if err != nil || !accepted {
+		s.refund(ctx, duel, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to accept duel: %w", err)
+		}
+		return nil, errors.New("duel is no longer available")
+	}
+
+	started.Status = models.DuelStatusActive
+	started.InviteLink = ""
+
+	log.Info("Duel accepted", zap.String("duel_id", duel.ID.String()))
+
+	return &started, nil
+}
+
+// CancelDuel отменяет непринятый вызов (вызвавший) или отклоняет его (адресат)
+func (s *DuelServiceImpl) CancelDuel(ctx context.Context, id uuid.UUID, userID uint64) error {
+	duel, err := s.duelRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if duel.ChallengerID != userID && (duel.OpponentID == 0 || duel.OpponentID != userID) {
+		return errors.New("only participants can cancel the duel")
+	}
+	if duel.Status != models.DuelStatusPending {
+		return fmt.Errorf("only pending duels can be canceled, status: %s", duel.Status)
+	}
+
+	canceled, err := s.duelRepo.TransitionStatus(ctx, duel.ID, models.DuelStatusPending, models.DuelStatusCanceled)
+	if err != nil {
+		return fmt.Errorf("failed to cancel duel: %w", err)
+	}
+	if !canceled {
+		return errors.New("duel is no longer pending")
+	}
+
+	duel.Status = models.DuelStatusCanceled
+	s.refund(ctx, duel, duel.ChallengerID)
+
+	return nil
+}
+
+// ProcessAttempt обрабатывает попытку игрока в дуэли
+func (s *DuelServiceImpl) ProcessAttempt(ctx context.Context, id uuid.UUID, userID uint64, word string) (*models.DuelAttempt, error) {
+	log := s.logger.With(zap.String("method", "ProcessAttempt"),
+		zap.String("duel_id", id.String()),
+		zap.Uint64("user_id", userID))
+
+	duel, err := s.duelRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !duel.IsParticipant(userID) {
+		return nil, errors.New("user is not a participant of the duel")
+	}
+	if duel.Status != models.DuelStatusActive {
+		return nil, fmt.Errorf("duel is not active, status: %s", duel.Status)
+	}
+	if duel.IsPlayExpired() {
+		if err := s.settle(ctx, duel); err != nil {
+			log.Error("Failed to settle expired duel", zap.Error(err))
+		}
+		return nil, errors.New("duel time expired")
+	}
+
+	tries, _, finishedAt := duel.PlayerState(userID)
+	if finishedAt != nil {
+		return nil, errors.New("you have already finished this duel")
+	}
+
+	word = dictionary.Normalize(word)
+	if len([]rune(word)) != duel.WordLength {
+		return nil, fmt.Errorf("invalid word length: expected %d, got %d", duel.WordLength, len([]rune(word)))
+	}
+	if !s.dictionary.Contains(word) {
+		return nil, errDuelWordUnknown
+	}
+
+	target := duel.TargetWord(userID)
+	attempt := &models.DuelAttempt{
+		ID:        uuid.New(),
+		DuelID:    duel.ID,
+		UserID:    userID,
+		Word:      word,
+		Result:    dictionary.Feedback(word, target),
+		CreatedAt: time.Now(),
+	}
+
+	prevTries := tries
+	tries++
+	solved := word == target
+	if solved || tries >= duel.MaxTries {
+		finishedAt = &attempt.CreatedAt
+	}
+	updated := *duel
+	updated.SetPlayerState(userID, tries, solved, finishedAt)
+
+	// Попытка засчитывается, только если с чтения дуэли не было другой попытки игрока
+	recorded, err := s.duelRepo.RecordAttempt(ctx, &updated, userID, prevTries, attempt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save attempt: %w", err)
+	}
+	if !recorded {
+		return nil, errors.New("another attempt is already being processed, try again")
+	}
+
+	if finishedAt != nil {
+		// Перечитываем дуэль: соперник мог закончить параллельно
+		fresh, err := s.duelRepo.GetByID(ctx, duel.ID)
+		if err == nil && fresh.BothFinished() {
+			if err := s.settle(ctx, fresh); err != nil {
+				log.Error("Failed to settle duel", zap.Error(err))
+			}
+		}
+	}
+
+	return attempt, nil
+}
+
+// GetAttempts возвращает попытки пользователя в дуэли (попытки соперника не раскрываются)
+func (s *DuelServiceImpl) GetAttempts(ctx context.Context, id uuid.UUID, userID uint64) ([]*models.DuelAttempt, error) {
+	duel, err := s.duelRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !duel.IsParticipant(userID) {
+		return nil, errors.New("user is not a participant of the duel")
+	}
+
+	return s.duelRepo.GetAttempts(ctx, duel.ID, userID)
+}
+
+// ProcessExpiredDuels закрывает непринятые вызовы и завершает дуэли с истёкшим временем
+func (s *DuelServiceImpl) ProcessExpiredDuels(ctx context.Context) error {
+	log := s.logger.With(zap.String("method", "ProcessExpiredDuels"))
+
+	invites, err := s.duelRepo.GetExpiredInvites(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get expired invites: %w", err)
+	}
+	for _, duel := range invites {
+		s.expireInvite(ctx, duel)
+	}
+
+	active, err := s.duelRepo.GetExpiredActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get expired duels: %w", err)
+	}
+	for _, duel := range active {
+		if err := s.settle(ctx, duel); err != nil {
+			log.Error("Failed to settle expired duel", zap.String("duel_id", duel.ID.String()), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// settle определяет победителя и распределяет эскроу. Ничья возвращает ставки обоим игрокам
+func (s *DuelServiceImpl) settle(ctx context.Context, duel *models.Duel) error {
+	log := s.logger.With(zap.String("method", "settle"), zap.String("duel_id", duel.ID.String()))
+
+	// Итог рассчитываем на копии: статус исходной дуэли меняется только после атомарного завершения
+	result := *duel
+	winnerID, draw := decideDuelWinner(&result)
+	if draw {
+		result.Status = models.DuelStatusDraw
+		result.WinnerID = nil
+		result.Payout = 0
+		result.Commission = 0
+	} else {
+		result.Status = models.DuelStatusFinished
+		result.WinnerID = &winnerID
+		result.Commission = s.commission.CalculateCommission(result.Pot())
+		result.Payout = result.Pot() - result.Commission
+	}
+
+	// Завершение дуэли, выплата эскроу и комиссия проводятся в одной транзакции
+	var settled bool
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		var err error
+		settled, err = s.duelRepo.Settle(ctx, &result)
+		if err != nil {
+			return fmt.Errorf("failed to settle duel: %w", err)
+		}
+		if !settled {
+			// Дуэль уже завершена параллельно
+			return nil
+		}
+
+		if draw {
+			for _, userID := range []uint64{result.ChallengerID, result.OpponentID} {
+				if err := s.transactionService.RefundDuelStake(ctx, userID, result.Stake, result.Currency, result.ID); err != nil {
+					return fmt.Errorf("failed to refund duel stake: %w", err)
+				}
+			}
+			return nil
+		}
+
+		if err := s.transactionService.PayDuelWinnings(ctx, winnerID, result.Payout, result.Commission, result.Currency, result.ID); err != nil {
+			return fmt.Errorf("failed to pay duel winnings: %w", err)
+		}
+
+		// Зачисляем комиссию на счёт сервиса
+		err = s.commission.BookCommission(ctx, &models.CommissionEntry{
+			Source:      models.CommissionSourceDuel,
+			ReferenceID: result.ID,
+			UserID:      winnerID,
+			Currency:    result.Currency,
+			Volume:      result.Pot(),
+			Rate:        s.commission.GetCommissionRate(),
+			Amount:      result.Commission,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to book duel commission: %w", err)
+		}
+		return nil
+	})
+	if err != nil || !settled {
+		return err
+	}
+	*duel = result
+
+	if draw {
+		log.Info("Duel ended in a draw")
+		return nil
+	}
+
+	loserID := duel.ChallengerID
+	if winnerID == duel.ChallengerID {
+		loserID = duel.OpponentID
+	}
+	_ = s.userService.IncrementWins(ctx, winnerID)
+	_ = s.userService.IncrementLosses(ctx, loserID)
+
+	log.Info("Duel finished",
+		zap.Uint64("winner_id", winnerID),
+		zap.Float64("payout", duel.Payout),
+		zap.Float64("commission", duel.Commission))
+
+	return nil
+}
+
+// expireInvite закрывает непринятый вызов и возвращает ставку вызвавшему
+func (s *DuelServiceImpl) expireInvite(ctx context.Context, duel *models.Duel) {
+	expired, err := s.duelRepo.TransitionStatus(ctx, duel.ID, models.DuelStatusPending, models.DuelStatusExpired)
+	if err != nil {
+		s.logger.Error("Failed to expire duel invite", zap.String("duel_id", duel.ID.String()), zap.Error(err))
+		return
+	}
+	if !expired {
+		return
+	}
+
+	duel.Status = models.DuelStatusExpired
+	s.refund(ctx, duel, duel.ChallengerID)
+}
+
+// refund возвращает ставку игроку из эскроу
+func (s *DuelServiceImpl) refund(ctx context.Context, duel *models.Duel, userID uint64) {
+	if err := s.transactionService.RefundDuelStake(ctx, userID, duel.Stake, duel.Currency, duel.ID); err != nil {
+		s.logger.Error("Failed to refund duel stake",
+			zap.String("duel_id", duel.ID.String()),
+			zap.Uint64("user_id", userID),
+			zap.Error(err))
+	}
+}
+
+// inviteLink формирует ссылку-приглашение для ожидающего вызова
+func (s *DuelServiceImpl) inviteLink(duel *models.Duel) string {
+	if s.botUsername == "" || duel.Status != models.DuelStatusPending {
+		return ""
+	}
+	if s.miniAppName != "" {
+		return fmt.Sprintf("https://t.me/%s/%s?startapp=%s", s.botUsername, s.miniAppName, duel.StartParam())
+	}
+	return fmt.Sprintf("https://t.me/%s?startapp=%s", s.botUsername, duel.StartParam())
+}
+
+// decideDuelWinner определяет победителя дуэли: угадавший побеждает не угадавшего,
+// при равенстве - меньше попыток, затем быстрее. Возвращает draw, если победителя нет
+func decideDuelWinner(duel *models.Duel) (winnerID uint64, draw bool) {
+	switch {
+	case !duel.ChallengerSolved && !duel.OpponentSolved:
+		return 0, true
+	case duel.ChallengerSolved != duel.OpponentSolved:
+		if duel.ChallengerSolved {
+			return duel.ChallengerID, false
+		}
+		return duel.OpponentID, false
+	case duel.ChallengerTries != duel.OpponentTries:
+		if duel.ChallengerTries < duel.OpponentTries {
+			return duel.ChallengerID, false
+		}
+		return duel.OpponentID, false
+	}
+
+	// Оба угадали за одинаковое число попыток - сравниваем время
+	if duel.ChallengerFinishedAt == nil || duel.OpponentFinishedAt == nil {
+		return 0, true
+	}
+	switch {
+	case duel.ChallengerFinishedAt.Before(*duel.OpponentFinishedAt):
+		return duel.ChallengerID, false
+	case duel.OpponentFinishedAt.Before(*duel.ChallengerFinishedAt):
+		return duel.OpponentID, false
+	}
+	return 0, true
+}
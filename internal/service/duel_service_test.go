@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestDecideDuelWinner(t *testing.T) {
+	start := time.Now()
+	early := start.Add(time.Minute)
+	late := start.Add(2 * time.Minute)
+
+	tests := []struct {
+		name       string
+		duel       models.Duel
+		wantWinner uint64
+		wantDraw   bool
+	}{
+		{
+			name:     "никто не угадал",
+			duel:     models.Duel{ChallengerID: 1, OpponentID: 2, ChallengerTries: 6, OpponentTries: 6},
+			wantDraw: true,
+		},
+		{
+			name:       "угадал только соперник",
+			duel:       models.Duel{ChallengerID: 1, OpponentID: 2, ChallengerTries: 6, OpponentSolved: true, OpponentTries: 5},
+			wantWinner: 2,
+		},
+		{
+			name: "меньше попыток",
+			duel: models.Duel{ChallengerID: 1, OpponentID: 2,
+				ChallengerSolved: true, ChallengerTries: 3, ChallengerFinishedAt: &late,
+				OpponentSolved: true, OpponentTries: 4, OpponentFinishedAt: &early},
+			wantWinner: 1,
+		},
+		{
+			name: "равные попытки, быстрее",
+			duel: models.Duel{ChallengerID: 1, OpponentID: 2,
+				ChallengerSolved: true, ChallengerTries: 3, ChallengerFinishedAt: &late,
+				OpponentSolved: true, OpponentTries: 3, OpponentFinishedAt: &early},
+			wantWinner: 2,
+		},
+		{
+			name: "полное равенство",
+			duel: models.Duel{ChallengerID: 1, OpponentID: 2,
+				ChallengerSolved: true, ChallengerTries: 3, ChallengerFinishedAt: &early,
+				OpponentSolved: true, OpponentTries: 3, OpponentFinishedAt: &early},
+			wantDraw: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, draw := decideDuelWinner(&tt.duel)
+			if draw != tt.wantDraw {
+				t.Errorf("draw = %v, want %v", draw, tt.wantDraw)
+			}
+			if winner != tt.wantWinner {
+				t.Errorf("winner = %d, want %d", winner, tt.wantWinner)
+			}
+		})
+	}
+}
+
+// testDuelDictionary словарь слов, которые загадывают и угадывают в тестах дуэлей
+var testDuelDictionary = dictionary.New([]string{"слово", "сокол", "слава", "кот"})
+
+func setupDuelService(t *testing.T) (*mocks.MockUserRepository, *mocks.MockDuelRepository, models.DuelService) {
+	t.Helper()
+	ctx := context.Background()
+
+	userRepo := mocks.NewMockUserRepository()
+	duelRepo := mocks.NewMockDuelRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "challenger", BalanceTon: 10})
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 2, Username: "opponent", BalanceTon: 10})
+
+	return userRepo, duelRepo, NewDuelService(duelRepo, userService, txService, newTestCommissionService(), testDuelDictionary,
+		"wordle_bot", "play", mocks.NewMockTransactor())
+}
+
+func assertTonBalance(t *testing.T, userRepo *mocks.MockUserRepository, userID uint64, want float64) {
+	t.Helper()
+	user, _ := userRepo.GetByTelegramID(context.Background(), userID)
+	if math.Abs(user.BalanceTon-want) > 1e-9 {
+		t.Errorf("user %d balance = %v, want %v", userID, user.BalanceTon, want)
+	}
+}
+
+func TestDuelService_PlayAndSettle(t *testing.T) {
+	ctx := context.Background()
+	userRepo, _, duelService := setupDuelService(t)
+
+	duel := &models.Duel{ChallengerID: 1, OpponentID: 2, Stake: 2, Currency: models.CurrencyTON}
+	if err := duelService.CreateDuel(ctx, duel, "слово"); err != nil {
+		t.Fatalf("CreateDuel() error = %v", err)
+	}
+	if duel.InviteLink != "https://t.me/wordle_bot/play?startapp=duel_"+duel.ShortID {
+		t.Errorf("invite link = %s", duel.InviteLink)
+	}
+	assertTonBalance(t, userRepo, 1, 8)
+
+	if _, err := duelService.AcceptDuel(ctx, duel.StartParam(), 2, "кот"); err == nil {
+		t.Error("AcceptDuel() with wrong word length should fail")
+	}
+	if _, err := duelService.AcceptDuel(ctx, duel.StartParam(), 1, "сокол"); err == nil {
+		t.Error("AcceptDuel() by the challenger should fail")
+	}
+
+	if _, err := duelService.AcceptDuel(ctx, duel.StartParam(), 2, "сокол"); err != nil {
+		t.Fatalf("AcceptDuel() error = %v", err)
+	}
+	assertTonBalance(t, userRepo, 2, 8)
+
+	// Вызвавший угадывает слово соперника со второй попытки, соперник - с первой
+	if _, err := duelService.ProcessAttempt(ctx, duel.ID, 1, "слава"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+	if _, err := duelService.ProcessAttempt(ctx, duel.ID, 2, "слово"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+	if _, err := duelService.ProcessAttempt(ctx, duel.ID, 2, "слово"); err == nil {
+		t.Error("ProcessAttempt() after finishing should fail")
+	}
+	if _, err := duelService.ProcessAttempt(ctx, duel.ID, 1, "сокол"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+
+	settled, _ := duelService.GetDuel(ctx, duel.ID)
+	if settled.Status != models.DuelStatusFinished {
+		t.Fatalf("duel status = %s, want %s", settled.Status, models.DuelStatusFinished)
+	}
+	if settled.WinnerID == nil || *settled.WinnerID != 2 {
+		t.Fatalf("winner = %v, want 2", settled.WinnerID)
+	}
+
+	// Банк 4 TON минус 5% комиссии
+	assertTonBalance(t, userRepo, 2, 8+3.8)
+	assertTonBalance(t, userRepo, 1, 8)
+
+	attempts, err := duelService.GetAttempts(ctx, duel.ID, 1)
+	if err != nil {
+		t.Fatalf("GetAttempts() error = %v", err)
+	}
+	if len(attempts) != 2 {
+		t.Errorf("attempts = %d, want 2", len(attempts))
+	}
+}
+
+func TestDuelService_DrawRefundsBoth(t *testing.T) {
+	ctx := context.Background()
+	userRepo, _, duelService := setupDuelService(t)
+
+	duel := &models.Duel{ChallengerID: 1, Stake: 1, Currency: models.CurrencyTON, MaxTries: 1}
+	if err := duelService.CreateDuel(ctx, duel, "слово"); err != nil {
+		t.Fatalf("CreateDuel() error = %v", err)
+	}
+	if _, err := duelService.AcceptDuel(ctx, duel.ShortID, 2, "сокол"); err != nil {
+		t.Fatalf("AcceptDuel() error = %v", err)
+	}
+
+	_, _ = duelService.ProcessAttempt(ctx, duel.ID, 1, "слава")
+	_, _ = duelService.ProcessAttempt(ctx, duel.ID, 2, "слава")
+
+	settled, _ := duelService.GetDuel(ctx, duel.ID)
+	if settled.Status != models.DuelStatusDraw {
+		t.Fatalf("duel status = %s, want %s", settled.Status, models.DuelStatusDraw)
+	}
+	assertTonBalance(t, userRepo, 1, 10)
+	assertTonBalance(t, userRepo, 2, 10)
+}
+
+func TestDuelService_ExpiredInviteRefunds(t *testing.T) {
+	ctx := context.Background()
+	userRepo, duelRepo, duelService := setupDuelService(t)
+
+	duel := &models.Duel{ChallengerID: 1, Stake: 1, Currency: models.CurrencyTON}
+	if err := duelService.CreateDuel(ctx, duel, "слово"); err != nil {
+		t.Fatalf("CreateDuel() error = %v", err)
+	}
+
+	stored, _ := duelRepo.GetByID(ctx, duel.ID)
+	stored.InviteExpiresAt = time.Now().Add(-time.Minute)
+
+	if err := duelService.ProcessExpiredDuels(ctx); err != nil {
+		t.Fatalf("ProcessExpiredDuels() error = %v", err)
+	}
+
+	if stored.Status != models.DuelStatusExpired {
+		t.Errorf("duel status = %s, want %s", stored.Status, models.DuelStatusExpired)
+	}
+	assertTonBalance(t, userRepo, 1, 10)
+
+	if _, err := duelService.AcceptDuel(ctx, duel.ShortID, 2, "сокол"); err == nil {
+		t.Error("AcceptDuel() of an expired invite should fail")
+	}
+}
+
+func TestDuelService_RejectsUnknownWords(t *testing.T) {
+	ctx := context.Background()
+	userRepo, duelRepo, duelService := setupDuelService(t)
+
+	if err := duelService.CreateDuel(ctx, &models.Duel{ChallengerID: 1, Stake: 1, Currency: models.CurrencyTON}, "ааааа"); err == nil {
+		t.Error("CreateDuel() with a word outside the dictionary should fail")
+	}
+	assertTonBalance(t, userRepo, 1, 10)
+
+	duel := &models.Duel{ChallengerID: 1, Stake: 1, Currency: models.CurrencyTON}
+	if err := duelService.CreateDuel(ctx, duel, "слово"); err != nil {
+		t.Fatalf("CreateDuel() error = %v", err)
+	}
+	if _, err := duelService.AcceptDuel(ctx, duel.ShortID, 2, "ааааа"); err == nil {
+		t.Error("AcceptDuel() with a word outside the dictionary should fail")
+	}
+	assertTonBalance(t, userRepo, 2, 10)
+
+	if _, err := duelService.AcceptDuel(ctx, duel.ShortID, 2, "сокол"); err != nil {
+		t.Fatalf("AcceptDuel() error = %v", err)
+	}
+	if _, err := duelService.ProcessAttempt(ctx, duel.ID, 1, "ааааа"); err == nil {
+		t.Error("ProcessAttempt() with a guess outside the dictionary should fail")
+	}
+	if stored, _ := duelRepo.GetByID(ctx, duel.ID); stored.ChallengerTries != 0 {
+		t.Errorf("challenger tries = %d, want 0", stored.ChallengerTries)
+	}
+}
+
+func TestDuelService_StakeRequiresAvailableBalance(t *testing.T) {
+	ctx := context.Background()
+	userRepo, _, duelService := setupDuelService(t)
+
+	// Средства, ожидающие вывода, нельзя поставить на дуэль
+	user, _ := userRepo.GetByTelegramID(ctx, 1)
+	user.PendingWithdrawal = 9.5
+
+	duel := &models.Duel{ChallengerID: 1, Stake: 1, Currency: models.CurrencyTON}
+	if err := duelService.CreateDuel(ctx, duel, "слово"); err == nil {
+		t.Fatal("CreateDuel() above the available balance should fail")
+	}
+	assertTonBalance(t, userRepo, 1, 10)
+}
+
+// staleDuelRepository возвращает снимок дуэли, прочитанный до попытки игрока
+type staleDuelRepository struct {
+	*mocks.MockDuelRepository
+	stale *models.Duel
+}
+
+func (r *staleDuelRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Duel, error) {
+	copied := *r.stale
+	return &copied, nil
+}
+
+func TestDuelService_ConcurrentAttemptCountedOnce(t *testing.T) {
+	ctx := context.Background()
+	userRepo, duelRepo, duelService := setupDuelService(t)
+
+	duel := &models.Duel{ChallengerID: 1, OpponentID: 2, Stake: 1, Currency: models.CurrencyTON}
+	if err := duelService.CreateDuel(ctx, duel, "слово"); err != nil {
+		t.Fatalf("CreateDuel() error = %v", err)
+	}
+	if _, err := duelService.AcceptDuel(ctx, duel.StartParam(), 2, "сокол"); err != nil {
+		t.Fatalf("AcceptDuel() error = %v", err)
+	}
+
+	// Обе попытки прочитали дуэль до того, как первая из них была записана
+	snapshot, _ := duelRepo.GetByID(ctx, duel.ID)
+	stale := *snapshot
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	racing := NewDuelService(&staleDuelRepository{MockDuelRepository: duelRepo, stale: &stale},
+		NewUserServiceImpl(userRepo, txService), txService, newTestCommissionService(), testDuelDictionary, "", "",
+		mocks.NewMockTransactor())
+
+	if _, err := racing.ProcessAttempt(ctx, duel.ID, 1, "слава"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+	if _, err := racing.ProcessAttempt(ctx, duel.ID, 1, "сокол"); err == nil {
+		t.Error("ProcessAttempt() based on a stale read should fail")
+	}
+
+	stored, _ := duelRepo.GetByID(ctx, duel.ID)
+	if tries, _, _ := stored.PlayerState(1); tries != 1 {
+		t.Errorf("challenger tries = %d, want 1", tries)
+	}
+	attempts, _ := duelRepo.GetAttempts(ctx, duel.ID, 1)
+	if len(attempts) != 1 {
+		t.Errorf("attempts = %d, want 1", len(attempts))
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/TakuroBreath/wordle/pkg/metrics"
+)
+
+// Подписчики доменных событий. Имена сохраняются в outbox вместе с отметкой о доставке и не должны меняться
+const (
+	subscriberMetrics       = "metrics"
+	subscriberNotifications = "notifications"
+	subscriberFollows       = "follows"
+	subscriberAchievements  = "achievements"
+	subscriberPlayerStats   = "player_stats"
+	subscriberWebhooks      = "webhooks"
+)
+
+// metricsEventHandler записывает доменные события в метрики Prometheus
+func metricsEventHandler(ctx context.Context, event *models.DomainEvent) error {
+	switch event.Type {
+	case models.EventLobbyFinished:
+		var payload models.LobbyFinishedEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		switch payload.Status {
+		case models.LobbyStatusSuccess:
+			metrics.IncrementGameComplete(payload.TriesUsed)
+		case models.LobbyStatusCashedOut:
+			// Досрочная выплата не учитывается ни как завершённая, ни как брошенная игра
+		default:
+			metrics.IncrementGameAbandoned()
+		}
+	case models.EventGameActivated:
+		metrics.IncrementGameStart()
+	case models.EventDepositCredited, models.EventWithdrawalRequested, models.EventWithdrawalSent:
+		var payload models.TransactionEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		switch event.Type {
+		case models.EventDepositCredited:
+			metrics.RecordDeposit(payload.Currency, payload.Amount)
+		case models.EventWithdrawalRequested:
+			metrics.IncrementPendingWithdrawals()
+		case models.EventWithdrawalSent:
+			metrics.RecordWithdrawal(payload.Currency, payload.Amount)
+			metrics.DecrementPendingWithdrawals()
+		}
+	}
+	return nil
+}
+
+// notificationEventHandler ставит в очередь уведомления бота по доменным событиям
+func notificationEventHandler(
+	notifications models.NotificationService,
+	lobbyRepo models.LobbyRepository,
+	gameRepo models.GameRepository,
+) models.EventHandler {
+	activations := gameActivatedHandler(gameRepo, notifications)
+	return func(ctx context.Context, event *models.DomainEvent) error {
+		switch event.Type {
+		case models.EventLobbyFinished:
+			var payload models.LobbyFinishedEvent
+			if err := event.Decode(&payload); err != nil {
+				return err
+			}
+			lobby, game, err := loadFinishedLobby(ctx, lobbyRepo, gameRepo, &payload)
+			if err != nil {
+				return err
+			}
+			return notifications.LobbyFinished(ctx, lobby, game, payload.Status, payload.Reward)
+		case models.EventGameActivated:
+			return activations(ctx, event)
+		case models.EventDepositCredited, models.EventWithdrawalSent:
+			var payload models.TransactionEvent
+			if err := event.Decode(&payload); err != nil {
+				return err
+			}
+			if event.Type == models.EventDepositCredited {
+				return notifications.DepositCredited(ctx, payload.Transaction())
+			}
+			return notifications.WithdrawalSent(ctx, payload.Transaction())
+		}
+		return nil
+	}
+}
+
+// gameActivatedHandler передаёт активированную игру получателю уведомлений об активации
+func gameActivatedHandler(gameRepo models.GameRepository, activations models.GameActivationNotifier) models.EventHandler {
+	return func(ctx context.Context, event *models.DomainEvent) error {
+		var payload models.GameActivatedEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		game, err := gameRepo.GetByID(ctx, payload.GameID)
+		if err != nil {
+			return fmt.Errorf("failed to get game %s: %w", payload.GameID, err)
+		}
+		return activations.GameActivated(ctx, game)
+	}
+}
+
+// achievementEventHandler обновляет серии и достижения игрока и создателя по итогам лобби
+func achievementEventHandler(achievements models.AchievementService) models.EventHandler {
+	return func(ctx context.Context, event *models.DomainEvent) error {
+		var payload models.LobbyFinishedEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		return achievements.HandleEvent(ctx, &models.AchievementEvent{
+			Type:      models.AchievementEventLobbyFinished,
+			UserID:    payload.UserID,
+			CreatorID: payload.CreatorID,
+			GameID:    payload.GameID,
+			LobbyID:   payload.LobbyID,
+			Status:    payload.Status,
+			TriesUsed: payload.TriesUsed,
+			At:        payload.FinishedAt,
+		})
+	}
+}
+
+// playerStatsEventHandler обновляет агрегаты статистики игрока по итогам лобби
+func playerStatsEventHandler(
+	playerStats models.PlayerStatsService,
+	lobbyRepo models.LobbyRepository,
+	gameRepo models.GameRepository,
+) models.EventHandler {
+	return func(ctx context.Context, event *models.DomainEvent) error {
+		var payload models.LobbyFinishedEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		lobby, game, err := loadFinishedLobby(ctx, lobbyRepo, gameRepo, &payload)
+		if err != nil {
+			return err
+		}
+		return playerStats.RecordLobby(ctx, lobby, game, payload.Status, payload.Reward)
+	}
+}
+
+// loadFinishedLobby загружает лобби и игру из события завершения лобби.
+// Время обновления лобби - момент завершения: событие может быть доставлено позже
+func loadFinishedLobby(
+	ctx context.Context,
+	lobbyRepo models.LobbyRepository,
+	gameRepo models.GameRepository,
+	payload *models.LobbyFinishedEvent,
+) (*models.Lobby, *models.Game, error) {
+	lobby, err := lobbyRepo.GetByID(ctx, payload.LobbyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get lobby %s: %w", payload.LobbyID, err)
+	}
+	game, err := gameRepo.GetByID(ctx, payload.GameID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get game %s: %w", payload.GameID, err)
+	}
+	lobby.UpdatedAt = payload.FinishedAt
+	return lobby, game, nil
+}
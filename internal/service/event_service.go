@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Параметры доставки доменных событий по умолчанию
+const (
+	defaultEventMaxAttempts = 8
+	defaultEventRetryDelay  = 10 * time.Second
+	defaultEventBatchSize   = 200
+	maxEventRetryDelay      = time.Hour
+	eventClaimTimeout       = 5 * time.Minute // Через сколько забранное, но не обработанное событие вернётся в очередь
+)
+
+// eventSubscriber подписчик шины событий
+type eventSubscriber struct {
+	name       string
+	eventTypes []string
+	handler    models.EventHandler
+}
+
+// EventServiceImpl представляет собой реализацию EventService
+type EventServiceImpl struct {
+	eventRepo   models.EventRepository
+	transactor  models.Transactor
+	rule        models.EventRule
+	subscribers []eventSubscriber
+	logger      *zap.Logger
+}
+
+// NewEventService создает новый экземпляр EventService.
+// transactor объединяет изменение состояния и запись события в одну транзакцию (nil - без транзакции).
+// Нулевые параметры rule заменяются значениями по умолчанию
+func NewEventService(eventRepo models.EventRepository, transactor models.Transactor, rule models.EventRule) models.EventService {
+	if rule.MaxAttempts <= 0 {
+		rule.MaxAttempts = defaultEventMaxAttempts
+	}
+	if rule.RetryDelay <= 0 {
+		rule.RetryDelay = defaultEventRetryDelay
+	}
+	if rule.BatchSize <= 0 {
+		rule.BatchSize = defaultEventBatchSize
+	}
+	return &EventServiceImpl{
+		eventRepo:  eventRepo,
+		transactor: transactor,
+		rule:       rule,
+		logger:     logger.GetLogger(zap.String("service", "event")),
+	}
+}
+
+// WithinTx выполняет fn в транзакции БД, если подключён transactor
+func (s *EventServiceImpl) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.transactor == nil {
+		return fn(ctx)
+	}
+	return s.transactor.WithinTx(ctx, fn)
+}
+
+// Publish записывает событие в outbox
+func (s *EventServiceImpl) Publish(ctx context.Context, event *models.DomainEvent) error {
+	if event == nil {
+		return errors.New("event is nil")
+	}
+	return s.eventRepo.Append(ctx, event)
+}
+
+// Subscribe регистрирует подписчика. Вызывается при инициализации сервисов, до запуска фоновых задач
+func (s *EventServiceImpl) Subscribe(name string, handler models.EventHandler, eventTypes ...string) {
+	s.subscribers = append(s.subscribers, eventSubscriber{name: name, eventTypes: eventTypes, handler: handler})
+}
+
+// ProcessEvents доставляет события, время попытки которых наступило. Каждый подписчик получает событие,
+// пока не обработает его без ошибки; после этого повторы его пропускают. При ошибке событие откладывается
+// с удвоением задержки, после rule.MaxAttempts попыток переходит в dead letter
+func (s *EventServiceImpl) ProcessEvents(ctx context.Context) error {
+	now := time.Now()
+	events, err := s.eventRepo.ClaimDue(ctx, now, now.Add(eventClaimTimeout), s.rule.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due events: %w", err)
+	}
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		deliveryErr := s.deliver(ctx, event)
+		s.recordAttempt(event, deliveryErr, time.Now())
+		if err := s.eventRepo.UpdateDelivery(ctx, event); err != nil {
+			return fmt.Errorf("failed to update event %s: %w", event.ID, err)
+		}
+
+		if deliveryErr != nil {
+			s.logger.Warn("Failed to deliver domain event",
+				zap.String("event_id", event.ID.String()),
+				zap.String("type", event.Type),
+				zap.Int("attempts", event.Attempts),
+				zap.String("status", event.Status),
+				zap.Error(deliveryErr))
+		}
+	}
+
+	return nil
+}
+
+// deliver передаёт событие подписчикам, которые ещё не обработали его, и возвращает их ошибки
+func (s *EventServiceImpl) deliver(ctx context.Context, event *models.DomainEvent) error {
+	var errs []error
+	for _, subscriber := range s.subscribers {
+		if !slices.Contains(subscriber.eventTypes, event.Type) || event.IsDeliveredTo(subscriber.name) {
+			continue
+		}
+
+		if err := subscriber.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", subscriber.name, err))
+			continue
+		}
+		if err := s.eventRepo.MarkDelivered(ctx, event.ID, subscriber.name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", subscriber.name, err))
+			continue
+		}
+		event.DeliveredTo = append(event.DeliveredTo, subscriber.name)
+	}
+	return errors.Join(errs...)
+}
+
+// recordAttempt обновляет событие по результату попытки доставки
+func (s *EventServiceImpl) recordAttempt(event *models.DomainEvent, deliveryErr error, now time.Time) {
+	event.Attempts++
+	if deliveryErr == nil {
+		event.Status = models.EventStatusDelivered
+		event.LastError = ""
+		event.DeliveredAt = &now
+		return
+	}
+
+	event.LastError = deliveryErr.Error()
+	if event.Attempts >= s.rule.MaxAttempts {
+		event.Status = models.EventStatusDead
+		return
+	}
+
+	delay := s.rule.RetryDelay << (event.Attempts - 1)
+	if delay <= 0 || delay > maxEventRetryDelay {
+		delay = maxEventRetryDelay
+	}
+	event.NextAttemptAt = now.Add(delay)
+}
+
+// GetDeadLetters возвращает события, доставка которых прекращена после исчерпания попыток
+func (s *EventServiceImpl) GetDeadLetters(ctx context.Context, limit, offset int) ([]*models.DomainEvent, error) {
+	return s.eventRepo.GetDeadLetters(ctx, limit, offset)
+}
+
+// RetryDeadLetter возвращает событие в очередь. Подписчики, уже обработавшие его, событие повторно не получат
+func (s *EventServiceImpl) RetryDeadLetter(ctx context.Context, id uuid.UUID) error {
+	if err := s.eventRepo.Requeue(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Dead letter requeued", zap.String("event_id", id.String()))
+	return nil
+}
+
+// withinTx выполняет fn в транзакции transactor. Без transactor (сервис собран без базы) fn выполняется напрямую
+func withinTx(ctx context.Context, transactor models.Transactor, fn func(ctx context.Context) error) error {
+	if transactor == nil {
+		return fn(ctx)
+	}
+	return transactor.WithinTx(ctx, fn)
+}
+
+// withinEventTx выполняет изменение состояния change и записывает возвращённое им событие в outbox
+// в той же транзакции. Без шины событий изменение выполняется без транзакции, а событие не записывается
+func withinEventTx(ctx context.Context, events models.EventService, change func(ctx context.Context) (*models.DomainEvent, error)) error {
+	if events == nil {
+		_, err := change(ctx)
+		return err
+	}
+	return events.WithinTx(ctx, func(ctx context.Context) error {
+		event, err := change(ctx)
+		if err != nil || event == nil {
+			return err
+		}
+		return events.Publish(ctx, event)
+	})
+}
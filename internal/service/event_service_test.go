@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// recordingSubscriber запоминает полученные события и отвечает ошибкой, пока failures > 0
+type recordingSubscriber struct {
+	received []*models.DomainEvent
+	failures int
+}
+
+func (r *recordingSubscriber) handle(ctx context.Context, event *models.DomainEvent) error {
+	r.received = append(r.received, event)
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("subscriber unavailable")
+	}
+	return nil
+}
+
+func publishTestEvent(t *testing.T, events models.EventService, eventType string) *models.DomainEvent {
+	t.Helper()
+	event, err := models.NewDomainEvent(eventType, uuid.New(), &models.GameActivatedEvent{GameID: uuid.New(), CreatorID: 1})
+	if err != nil {
+		t.Fatalf("NewDomainEvent() error = %v", err)
+	}
+	if err := events.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	return event
+}
+
+func TestEventService_DeliveryAndRetries(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockEventRepository()
+	events := NewEventService(repo, mocks.NewMockTransactor(), models.EventRule{RetryDelay: time.Millisecond})
+
+	stable := &recordingSubscriber{}
+	flaky := &recordingSubscriber{failures: 1}
+	other := &recordingSubscriber{}
+	events.Subscribe("stable", stable.handle, models.EventGameActivated)
+	events.Subscribe("flaky", flaky.handle, models.EventGameActivated)
+	events.Subscribe("other", other.handle, models.EventDepositCredited)
+
+	publishTestEvent(t, events, models.EventGameActivated)
+	if err := events.ProcessEvents(ctx); err != nil {
+		t.Fatalf("ProcessEvents() error = %v", err)
+	}
+
+	event := repo.Events()[0]
+	if event.Status != models.EventStatusPending || event.Attempts != 1 || event.LastError == "" {
+		t.Fatalf("after a failed delivery: status = %s, attempts = %d, want pending after 1 attempt with an error",
+			event.Status, event.Attempts)
+	}
+	if !event.IsDeliveredTo("stable") || event.IsDeliveredTo("flaky") {
+		t.Errorf("delivered to = %v, want only the stable subscriber", event.DeliveredTo)
+	}
+
+	// Повтор доставляет событие только подписчику, который его ещё не обработал
+	time.Sleep(5 * time.Millisecond)
+	_ = events.ProcessEvents(ctx)
+	event = repo.Events()[0]
+	if event.Status != models.EventStatusDelivered || event.DeliveredAt == nil || event.LastError != "" {
+		t.Errorf("after the retry: status = %s, error = %q, want delivered", event.Status, event.LastError)
+	}
+	if len(stable.received) != 1 || len(flaky.received) != 2 || len(other.received) != 0 {
+		t.Errorf("deliveries: stable = %d, flaky = %d, other = %d, want 1, 2 and 0",
+			len(stable.received), len(flaky.received), len(other.received))
+	}
+}
+
+func TestEventService_DeadLetters(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockEventRepository()
+	events := NewEventService(repo, mocks.NewMockTransactor(), models.EventRule{MaxAttempts: 2, RetryDelay: time.Millisecond})
+
+	broken := &recordingSubscriber{failures: 2}
+	events.Subscribe("broken", broken.handle, models.EventWithdrawalSent)
+	event := publishTestEvent(t, events, models.EventWithdrawalSent)
+
+	_ = events.ProcessEvents(ctx)
+	time.Sleep(5 * time.Millisecond)
+	_ = events.ProcessEvents(ctx)
+
+	dead, err := events.GetDeadLetters(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("GetDeadLetters() error = %v", err)
+	}
+	if len(dead) != 1 || dead[0].ID != event.ID || dead[0].Attempts != 2 {
+		t.Fatalf("dead letters = %+v, want the event after 2 attempts", dead)
+	}
+
+	// Событие в dead letter больше не доставляется, пока его не вернут в очередь
+	time.Sleep(5 * time.Millisecond)
+	_ = events.ProcessEvents(ctx)
+	if len(broken.received) != 2 {
+		t.Errorf("deliveries = %d, want no delivery of a dead letter", len(broken.received))
+	}
+
+	if err := events.RetryDeadLetter(ctx, uuid.New()); !errors.Is(err, models.ErrEventNotFound) {
+		t.Errorf("RetryDeadLetter() unknown event error = %v, want ErrEventNotFound", err)
+	}
+	if err := events.RetryDeadLetter(ctx, event.ID); err != nil {
+		t.Fatalf("RetryDeadLetter() error = %v", err)
+	}
+	_ = events.ProcessEvents(ctx)
+	if got := repo.Events()[0]; got.Status != models.EventStatusDelivered || got.Attempts != 1 {
+		t.Errorf("after requeue: status = %s, attempts = %d, want delivered on the first new attempt", got.Status, got.Attempts)
+	}
+	if dead, _ := events.GetDeadLetters(ctx, 10, 0); len(dead) != 0 {
+		t.Errorf("dead letters = %d, want none after the retry", len(dead))
+	}
+}
+
+func TestEventService_ClaimedEventsSkipped(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockEventRepository()
+	events := NewEventService(repo, mocks.NewMockTransactor(), models.EventRule{})
+
+	subscriber := &recordingSubscriber{}
+	events.Subscribe("subscriber", subscriber.handle, models.EventGameActivated)
+	publishTestEvent(t, events, models.EventGameActivated)
+
+	// Событие уже забрал другой экземпляр и ещё не обработал
+	now := time.Now()
+	claimed, err := repo.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDue() = %d events, error = %v, want one event", len(claimed), err)
+	}
+	if err := events.ProcessEvents(ctx); err != nil {
+		t.Fatalf("ProcessEvents() error = %v", err)
+	}
+	if len(subscriber.received) != 0 {
+		t.Errorf("deliveries = %d, want none while the event is claimed", len(subscriber.received))
+	}
+}
+
+func TestEventService_StateChangesPublishEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockEventRepository()
+	events := NewEventService(repo, mocks.NewMockTransactor(), models.EventRule{})
+
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+	txService := NewTransactionServiceImpl(txRepo, userRepo, nil)
+	txService.(*TransactionServiceImpl).SetEvents(events)
+	userService := NewUserServiceImpl(userRepo, txService)
+	historyService := NewHistoryService(mocks.NewMockHistoryRepository(), gameRepo, userRepo, lobbyRepo)
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        mocks.NewMockAttemptRepository(),
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово"}),
+		Events:             events,
+	})
+
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 100, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, BalanceTon: 5})
+
+	lobby := &models.Lobby{GameID: game.ID, UserID: 1, BetAmount: 1}
+	if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	if _, err := lobbyService.ProcessAttempt(ctx, lobby.ID, "слово"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+	if _, err := txService.ProcessWithdraw(ctx, 1, 2, models.CurrencyTON, "UQ-address"); err != nil {
+		t.Fatalf("ProcessWithdraw() error = %v", err)
+	}
+
+	published := repo.Events()
+	if len(published) != 2 || published[0].Type != models.EventLobbyFinished || published[1].Type != models.EventWithdrawalRequested {
+		t.Fatalf("events = %d, want lobby_finished and withdrawal_requested", len(published))
+	}
+
+	var finished models.LobbyFinishedEvent
+	if err := published[0].Decode(&finished); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	gross := 1 * 2 * models.RewardTriesBonus(1, 6)
+	if finished.LobbyID != lobby.ID || finished.CreatorID != 100 || finished.Status != models.LobbyStatusSuccess ||
+		math.Abs(finished.Reward-gross*0.95) > 1e-9 {
+		t.Errorf("lobby finished event = %+v, want a win with reward %v", finished, gross*0.95)
+	}
+	user, _ := userRepo.GetByTelegramID(ctx, 1)
+	if math.Abs(user.BalanceTon-(4+finished.Reward)) > 1e-9 {
+		t.Errorf("balance = %v, want the reward from the event credited", user.BalanceTon)
+	}
+
+	// Подписчик получает данные события и может восстановить лобби
+	stats := &recordingSubscriber{}
+	events.Subscribe("stats", func(ctx context.Context, event *models.DomainEvent) error {
+		var payload models.LobbyFinishedEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		if _, _, err := loadFinishedLobby(ctx, lobbyRepo, gameRepo, &payload); err != nil {
+			return err
+		}
+		return stats.handle(ctx, event)
+	}, models.EventLobbyFinished)
+	if err := events.ProcessEvents(ctx); err != nil {
+		t.Fatalf("ProcessEvents() error = %v", err)
+	}
+	if len(stats.received) != 1 {
+		t.Errorf("subscriber received %d events, want 1", len(stats.received))
+	}
+}
+
+func TestUserService_RequestWithdrawPublishesEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockEventRepository()
+	events := NewEventService(repo, mocks.NewMockTransactor(), models.EventRule{})
+
+	userRepo := mocks.NewMockUserRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+	userService := NewUserService(userRepo, txRepo, nil, events, UserServiceConfig{MinWithdrawTON: 0.1, WithdrawFeeTON: 0.05})
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, BalanceTon: 5, Wallet: "UQ-address"})
+
+	result, err := userService.RequestWithdraw(ctx, 1, 2, models.CurrencyTON, "")
+	if err != nil {
+		t.Fatalf("RequestWithdraw() error = %v", err)
+	}
+
+	user, _ := userRepo.GetByTelegramID(ctx, 1)
+	if math.Abs(user.BalanceTon-3) > 1e-9 || math.Abs(user.PendingWithdrawal-2) > 1e-9 {
+		t.Errorf("balance = %v, pending = %v, want 3 and 2", user.BalanceTon, user.PendingWithdrawal)
+	}
+	if _, err := txRepo.GetByID(ctx, result.TransactionID); err != nil {
+		t.Errorf("withdrawal transaction not recorded: %v", err)
+	}
+
+	published := repo.Events()
+	if len(published) != 1 || published[0].Type != models.EventWithdrawalRequested {
+		t.Fatalf("events = %d, want one withdrawal_requested", len(published))
+	}
+	var payload models.TransactionEvent
+	if err := published[0].Decode(&payload); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if payload.TransactionID != result.TransactionID || payload.Amount != 2 || payload.ToAddress != "UQ-address" {
+		t.Errorf("withdrawal event = %+v, want transaction %s", payload, result.TransactionID)
+	}
+}
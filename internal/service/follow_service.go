@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// feedCandidateLimit число популярных игр, из которых выбираются рекомендации
+const feedCandidateLimit = 200
+
+// FollowServiceImpl представляет собой реализацию FollowService
+type FollowServiceImpl struct {
+	followRepo models.FollowRepository
+	gameRepo   models.GameRepository
+	userRepo   models.UserRepository
+	notifier   models.UserNotifier
+	logger     *zap.Logger
+}
+
+// NewFollowService создает новый экземпляр FollowService.
+// notifier используется для уведомления подписчиков о новых играх создателя (может быть nil)
+func NewFollowService(followRepo models.FollowRepository, gameRepo models.GameRepository, userRepo models.UserRepository, notifier models.UserNotifier) models.FollowService {
+	return &FollowServiceImpl{
+		followRepo: followRepo,
+		gameRepo:   gameRepo,
+		userRepo:   userRepo,
+		notifier:   notifier,
+		logger:     logger.GetLogger(zap.String("service", "follow")),
+	}
+}
+
+// FollowCreator подписывает пользователя на создателя. Повторная подписка меняет настройку уведомлений
+func (s *FollowServiceImpl) FollowCreator(ctx context.Context, followerID, creatorID uint64, notify bool) (*models.CreatorFollow, error) {
+	if followerID == creatorID {
+		return nil, models.ErrCannotFollowSelf
+	}
+	if _, err := s.userRepo.GetByTelegramID(ctx, creatorID); err != nil {
+		return nil, err
+	}
+
+	follow := &models.CreatorFollow{FollowerID: followerID, CreatorID: creatorID, Notify: notify}
+	if err := s.followRepo.Follow(ctx, follow); err != nil {
+		return nil, err
+	}
+	return follow, nil
+}
+
+// UnfollowCreator отменяет подписку на создателя
+func (s *FollowServiceImpl) UnfollowCreator(ctx context.Context, followerID, creatorID uint64) error {
+	return s.followRepo.Unfollow(ctx, followerID, creatorID)
+}
+
+// GetFollowing возвращает подписки пользователя
+func (s *FollowServiceImpl) GetFollowing(ctx context.Context, followerID uint64, limit, offset int) ([]*models.CreatorFollow, error) {
+	follows, err := s.followRepo.GetFollowing(ctx, followerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if follows == nil {
+		follows = []*models.CreatorFollow{}
+	}
+	return follows, nil
+}
+
+// BookmarkGame сохраняет игру в закладки пользователя
+func (s *FollowServiceImpl) BookmarkGame(ctx context.Context, userID uint64, gameID uuid.UUID) error {
+	if _, err := s.gameRepo.GetByID(ctx, gameID); err != nil {
+		return err
+	}
+	return s.followRepo.AddBookmark(ctx, &models.GameBookmark{UserID: userID, GameID: gameID})
+}
+
+// RemoveBookmark удаляет игру из закладок пользователя
+func (s *FollowServiceImpl) RemoveBookmark(ctx context.Context, userID uint64, gameID uuid.UUID) error {
+	return s.followRepo.RemoveBookmark(ctx, userID, gameID)
+}
+
+// GetBookmarks возвращает игры из закладок пользователя
+func (s *FollowServiceImpl) GetBookmarks(ctx context.Context, userID uint64, limit, offset int) ([]*models.Game, error) {
+	games, err := s.followRepo.GetBookmarkedGames(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if games == nil {
+		games = []*models.Game{}
+	}
+	return games, nil
+}
+
+// GetFeed возвращает игры отслеживаемых создателей, активированные за models.FeedFollowingWindow,
+// и рекомендации среди популярных игр по длине слова, сложности и ставкам сыгранных пользователем лобби.
+// Свои игры и игры из подписок в рекомендации не попадают
+func (s *FollowServiceImpl) GetFeed(ctx context.Context, userID uint64, limit int) (*models.Feed, error) {
+	limit = pageLimit(limit)
+
+	following, err := s.followRepo.GetFollowedGames(ctx, userID, time.Now().Add(-models.FeedFollowingWindow), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followed games: %w", err)
+	}
+	feed := &models.Feed{Following: following, Recommended: []*models.Game{}}
+	if feed.Following == nil {
+		feed.Following = []*models.Game{}
+	}
+
+	profile, err := s.followRepo.GetPlayProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get play profile: %w", err)
+	}
+
+	candidates, err := s.gameRepo.SearchGames(ctx, models.GameSearchFilter{Sort: models.GameSortPopular}, feedCandidateLimit, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate games: %w", err)
+	}
+
+	shown := make(map[uuid.UUID]bool, len(feed.Following))
+	for _, game := range feed.Following {
+		shown[game.ID] = true
+	}
+
+	type scored struct {
+		game  *models.Game
+		score float64
+	}
+	var recommended []scored
+	for _, game := range candidates {
+		if game.CreatorID == userID || shown[game.ID] {
+			continue
+		}
+		recommended = append(recommended, scored{game: game, score: recommendationScore(profile, game)})
+	}
+
+	// Кандидаты уже упорядочены по популярности, стабильная сортировка сохраняет этот порядок при равной оценке
+	sort.SliceStable(recommended, func(i, j int) bool {
+		return recommended[i].score > recommended[j].score
+	})
+	for _, item := range recommended {
+		if len(feed.Recommended) == limit {
+			break
+		}
+		feed.Recommended = append(feed.Recommended, item.game)
+	}
+
+	return feed, nil
+}
+
+// recommendationScore оценивает сходство игры с сыгранными лобби: доли лобби с той же длиной слова
+// и сложностью плюс единица, если диапазон ставок игры пересекается с диапазоном ставок игрока
+func recommendationScore(profile *models.PlayProfile, game *models.Game) float64 {
+	if profile.Games == 0 {
+		return 0
+	}
+	games := float64(profile.Games)
+	score := float64(profile.Lengths[game.Length])/games + float64(profile.Difficulties[game.Difficulty])/games
+	if game.MinBet <= profile.MaxBet && game.MaxBet >= profile.MinBet {
+		score++
+	}
+	return score
+}
+
+// GameActivated запоминает время активации игры для ленты и уведомляет подписчиков создателя,
+// включивших уведомления. Приватные игры не анонсируются, ошибки отправки не прерывают рассылку
+func (s *FollowServiceImpl) GameActivated(ctx context.Context, game *models.Game) error {
+	if game.IsPrivate() {
+		return nil
+	}
+
+	if err := s.followRepo.RecordActivation(ctx, game.ID, time.Now()); err != nil {
+		return err
+	}
+
+	if s.notifier == nil {
+		return nil
+	}
+	followers, err := s.followRepo.GetNotifiedFollowers(ctx, game.CreatorID)
+	if err != nil {
+		return fmt.Errorf("failed to get followers: %w", err)
+	}
+
+	text := fmt.Sprintf("New game \"%s\" from a creator you follow is live: %d letters, %d tries, bets %.4f-%.4f %s, x%.2f reward.",
+		game.Title, game.Length, game.MaxTries, game.MinBet, game.MaxBet, game.Currency, game.RewardMultiplier)
+	for _, followerID := range followers {
+		if err := s.notifier.NotifyUser(ctx, followerID, text); err != nil {
+			s.logger.Warn("Failed to notify follower",
+				zap.String("game_id", game.ID.String()),
+				zap.Uint64("follower_id", followerID),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
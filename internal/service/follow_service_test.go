@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+type followFixture struct {
+	games    *mocks.MockGameRepository
+	history  *mocks.MockHistoryRepository
+	users    *mocks.MockUserRepository
+	notifier *fakeNotifier
+	follows  models.FollowService
+	game     models.GameService
+	events   models.EventService
+}
+
+func setupFollowService(t *testing.T) *followFixture {
+	t.Helper()
+	f := &followFixture{
+		games:    mocks.NewMockGameRepository(),
+		history:  mocks.NewMockHistoryRepository(),
+		users:    mocks.NewMockUserRepository(),
+		notifier: &fakeNotifier{},
+	}
+	for _, id := range []uint64{1, 2, 3, 10, 11} {
+		_ = f.users.Create(context.Background(), &models.User{TelegramID: id})
+	}
+	f.follows = NewFollowService(mocks.NewMockFollowRepository(f.games, f.history), f.games, f.users, f.notifier)
+	// Подписчики узнают об активации из события GameActivated
+	f.events = NewEventService(mocks.NewMockEventRepository(), mocks.NewMockTransactor(), models.EventRule{})
+	f.events.Subscribe(subscriberFollows, gameActivatedHandler(f.games, f.follows), models.EventGameActivated)
+	f.game = NewGameService(GameServiceDeps{
+		GameRepo:   f.games,
+		Commission: newTestCommissionService(),
+		Events:     f.events,
+	})
+	return f
+}
+
+// addGame создаёт игру создателя, ожидающую активации
+func (f *followFixture) addGame(creatorID uint64, configure func(game *models.Game)) *models.Game {
+	game := &models.Game{CreatorID: creatorID, Title: "Test", Length: 5, Difficulty: "medium", MaxTries: 6,
+		MinBet: 1, MaxBet: 2, RewardMultiplier: 2, Currency: models.CurrencyTON, RewardPoolTon: 10,
+		Status: models.GameStatusPending, Visibility: models.GameVisibilityPublic}
+	if configure != nil {
+		configure(game)
+	}
+	_ = f.games.Create(context.Background(), game)
+	return game
+}
+
+func TestFollowService_FollowAndBookmark(t *testing.T) {
+	ctx := context.Background()
+	f := setupFollowService(t)
+
+	if _, err := f.follows.FollowCreator(ctx, 10, 10, false); !errors.Is(err, models.ErrCannotFollowSelf) {
+		t.Errorf("FollowCreator() self error = %v, want ErrCannotFollowSelf", err)
+	}
+	if _, err := f.follows.FollowCreator(ctx, 10, 99, false); !errors.Is(err, models.ErrUserNotFound) {
+		t.Errorf("FollowCreator() unknown creator error = %v, want ErrUserNotFound", err)
+	}
+
+	_, _ = f.follows.FollowCreator(ctx, 10, 1, false)
+	// Повторная подписка меняет только настройку уведомлений
+	if _, err := f.follows.FollowCreator(ctx, 10, 1, true); err != nil {
+		t.Fatalf("FollowCreator() error = %v", err)
+	}
+	following, _ := f.follows.GetFollowing(ctx, 10, 10, 0)
+	if len(following) != 1 || following[0].CreatorID != 1 || !following[0].Notify {
+		t.Errorf("following = %+v, want creator 1 with notifications", following)
+	}
+
+	if err := f.follows.UnfollowCreator(ctx, 10, 1); err != nil {
+		t.Fatalf("UnfollowCreator() error = %v", err)
+	}
+	if err := f.follows.UnfollowCreator(ctx, 10, 1); !errors.Is(err, models.ErrFollowNotFound) {
+		t.Errorf("UnfollowCreator() twice error = %v, want ErrFollowNotFound", err)
+	}
+
+	game := f.addGame(1, nil)
+	for i := 0; i < 2; i++ {
+		if err := f.follows.BookmarkGame(ctx, 10, game.ID); err != nil {
+			t.Fatalf("BookmarkGame() error = %v", err)
+		}
+	}
+	if err := f.follows.BookmarkGame(ctx, 10, uuid.New()); !errors.Is(err, models.ErrGameNotFound) {
+		t.Errorf("BookmarkGame() unknown game error = %v, want ErrGameNotFound", err)
+	}
+	bookmarks, _ := f.follows.GetBookmarks(ctx, 10, 10, 0)
+	if len(bookmarks) != 1 || bookmarks[0].ID != game.ID {
+		t.Errorf("bookmarks = %d games, want the bookmarked game once", len(bookmarks))
+	}
+
+	_ = f.follows.RemoveBookmark(ctx, 10, game.ID)
+	if err := f.follows.RemoveBookmark(ctx, 10, game.ID); !errors.Is(err, models.ErrBookmarkNotFound) {
+		t.Errorf("RemoveBookmark() twice error = %v, want ErrBookmarkNotFound", err)
+	}
+}
+
+func TestFollowService_ActivationNotifiesFollowers(t *testing.T) {
+	ctx := context.Background()
+	f := setupFollowService(t)
+
+	_, _ = f.follows.FollowCreator(ctx, 10, 1, true)
+	_, _ = f.follows.FollowCreator(ctx, 11, 1, false)
+
+	game := f.addGame(1, func(g *models.Game) { g.Title = "Весенняя" })
+	if err := f.game.ActivateGame(ctx, game.ID); err != nil {
+		t.Fatalf("ActivateGame() error = %v", err)
+	}
+	if err := f.events.ProcessEvents(ctx); err != nil {
+		t.Fatalf("ProcessEvents() error = %v", err)
+	}
+	if len(f.notifier.messages[10]) != 1 || len(f.notifier.messages[11]) != 0 {
+		t.Errorf("messages = %v, want one message for follower 10 only", f.notifier.messages)
+	}
+
+	// Приватные игры не анонсируются
+	private := f.addGame(1, func(g *models.Game) { g.Visibility = models.GameVisibilityPrivate })
+	_ = f.game.ActivateGame(ctx, private.ID)
+
+	// Отложенная игра анонсируется, когда её активирует планировщик
+	startsAt := time.Now().Add(time.Hour)
+	scheduled := f.addGame(1, func(g *models.Game) { g.StartsAt = &startsAt })
+	_ = f.game.ActivateGame(ctx, scheduled.ID)
+	_ = f.events.ProcessEvents(ctx)
+	if len(f.notifier.messages[10]) != 1 {
+		t.Fatalf("got %d messages before the scheduled start, want 1", len(f.notifier.messages[10]))
+	}
+	started := time.Now().Add(-time.Minute)
+	scheduled.StartsAt = &started
+	if err := f.game.ProcessScheduledGames(ctx); err != nil {
+		t.Fatalf("ProcessScheduledGames() error = %v", err)
+	}
+	_ = f.events.ProcessEvents(ctx)
+	if len(f.notifier.messages[10]) != 2 {
+		t.Errorf("got %d messages, want 2 after the scheduled activation", len(f.notifier.messages[10]))
+	}
+
+	feed, err := f.follows.GetFeed(ctx, 10, 10)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if len(feed.Following) != 2 || feed.Following[0].ID != scheduled.ID || feed.Following[1].ID != game.ID {
+		t.Errorf("following section has %d games, want scheduled and then the first game", len(feed.Following))
+	}
+}
+
+func TestFollowService_FeedRecommendations(t *testing.T) {
+	ctx := context.Background()
+	f := setupFollowService(t)
+
+	// Игрок 10 играл в игры из пяти букв средней сложности со ставками 1-2
+	played := f.addGame(2, nil)
+	for _, bet := range []float64{1, 2} {
+		_ = f.history.Create(ctx, &models.History{UserID: 10, GameID: played.ID, BetAmount: bet,
+			Status: models.HistoryStatusPlayerWin, CreatedAt: time.Now()})
+	}
+
+	similar := f.addGame(2, func(g *models.Game) { g.Status = models.GameStatusActive })
+	sameLength := f.addGame(3, func(g *models.Game) {
+		g.Status, g.Difficulty, g.MinBet, g.MaxBet = models.GameStatusActive, "hard", 10, 20
+	})
+	// Самая популярная, но не похожая на сыгранные
+	popular := f.addGame(3, func(g *models.Game) {
+		g.Status, g.Length, g.Difficulty, g.MinBet, g.MaxBet, g.Plays = models.GameStatusActive, 7, "hard", 10, 20, 50
+	})
+	own := f.addGame(10, func(g *models.Game) { g.Status = models.GameStatusActive })
+	played.Status = models.GameStatusActive
+
+	feed, err := f.follows.GetFeed(ctx, 10, 10)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if len(feed.Following) != 0 {
+		t.Errorf("following section has %d games, want none without follows", len(feed.Following))
+	}
+	want := []*models.Game{played, similar, sameLength, popular}
+	if len(feed.Recommended) != len(want) {
+		t.Fatalf("got %d recommendations, want %d", len(feed.Recommended), len(want))
+	}
+	for i, game := range feed.Recommended {
+		if game.ID == own.ID {
+			t.Error("own games should not be recommended")
+		}
+		if i >= 2 && game.ID != want[i].ID {
+			t.Errorf("recommendation %d = %s, want %s", i, game.Title, want[i].Title)
+		}
+	}
+
+	// Без истории рекомендуются популярные игры
+	feed, _ = f.follows.GetFeed(ctx, 11, 10)
+	if len(feed.Recommended) == 0 || feed.Recommended[0].ID != popular.ID {
+		t.Errorf("first recommendation without history should be the most popular game")
+	}
+}
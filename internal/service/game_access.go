@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxAllowedUsers - максимальный размер белого списка приватной игры
+const maxAllowedUsers = 500
+
+// generateInviteToken генерирует токен приглашения в приватную игру.
+// Токен не содержит подчёркиваний, чтобы его можно было передать в комментарии платежа
+func generateInviteToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate invite token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// applyGameAccess проверяет и применяет настройки доступа к игре.
+// Приватной игре выдаётся токен приглашения, у публичной токен и ограничения сбрасываются
+func applyGameAccess(game *models.Game, access models.GameAccess) error {
+	if access.Visibility == "" {
+		access.Visibility = models.GameVisibilityPublic
+	}
+
+	switch access.Visibility {
+	case models.GameVisibilityPublic:
+		game.Visibility = models.GameVisibilityPublic
+		game.InviteToken = ""
+		game.AllowedUserIDs = nil
+		game.AllowedChatID = 0
+		return nil
+	case models.GameVisibilityPrivate:
+	default:
+		return fmt.Errorf("invalid visibility: %s", access.Visibility)
+	}
+
+	if len(access.AllowedUserIDs) > maxAllowedUsers {
+		return fmt.Errorf("whitelist cannot exceed %d users", maxAllowedUsers)
+	}
+
+	allowed := make([]uint64, 0, len(access.AllowedUserIDs))
+	for _, id := range access.AllowedUserIDs {
+		if id != 0 && !slices.Contains(allowed, id) {
+			allowed = append(allowed, id)
+		}
+	}
+
+	game.Visibility = models.GameVisibilityPrivate
+	game.AllowedUserIDs = allowed
+	game.AllowedChatID = access.AllowedChatID
+
+	if game.InviteToken == "" {
+		token, err := generateInviteToken()
+		if err != nil {
+			return err
+		}
+		game.InviteToken = token
+	}
+
+	return nil
+}
+
+// CheckAccess проверяет, может ли пользователь играть в игру.
+// Публичные игры доступны всем, создатель всегда имеет доступ к своей игре.
+// Если создатель задал белый список или группу, пользователь должен входить в один из них,
+// иначе достаточно действующего токена приглашения
+func (s *GameServiceImpl) CheckAccess(ctx context.Context, game *models.Game, userID uint64, inviteToken string) error {
+	if game == nil {
+		return models.ErrGameNotFound
+	}
+	if !game.IsPrivate() || (userID != 0 && userID == game.CreatorID) {
+		return nil
+	}
+
+	log := s.logger.With(zap.String("method", "CheckAccess"),
+		zap.String("game_id", game.ID.String()),
+		zap.Uint64("user_id", userID))
+
+	if !game.HasMemberRestriction() {
+		if inviteToken != "" && models.ParseGameStartParam(inviteToken) == game.InviteToken {
+			return nil
+		}
+		log.Debug("Invalid or missing invite token")
+		return models.ErrGameAccessDenied
+	}
+
+	if game.IsWhitelisted(userID) {
+		return nil
+	}
+
+	if game.AllowedChatID != 0 && s.membership != nil {
+		member, err := s.membership.IsChatMember(ctx, game.AllowedChatID, userID)
+		if err != nil {
+			log.Error("Failed to check chat membership", zap.Int64("chat_id", game.AllowedChatID), zap.Error(err))
+			return fmt.Errorf("failed to check chat membership: %w", err)
+		}
+		if member {
+			return nil
+		}
+	}
+
+	log.Debug("User is not allowed to play private game")
+	return models.ErrGameAccessDenied
+}
+
+// GetGameByInvite получает приватную игру по параметру startapp или токену приглашения
+func (s *GameServiceImpl) GetGameByInvite(ctx context.Context, startParam string) (*models.Game, error) {
+	token := models.ParseGameStartParam(startParam)
+	if token == "" {
+		return nil, models.ErrGameNotFound
+	}
+
+	game, err := s.gameRepo.GetByInviteToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !game.IsPrivate() {
+		return nil, models.ErrGameNotFound
+	}
+
+	return game, nil
+}
+
+// UpdateGameAccess изменяет видимость игры и ограничения доступа (только для создателя)
+func (s *GameServiceImpl) UpdateGameAccess(ctx context.Context, gameID uuid.UUID, creatorID uint64, access models.GameAccess) (*models.Game, error) {
+	log := s.logger.With(zap.String("method", "UpdateGameAccess"), zap.String("game_id", gameID.String()))
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID != creatorID {
+		return nil, errors.New("only the creator can change game access")
+	}
+	if game.Status == models.GameStatusClosed {
+		return nil, errors.New("game is closed")
+	}
+
+	updated := *game
+	if err := applyGameAccess(&updated, access); err != nil {
+		return nil, err
+	}
+
+	if err := s.gameRepo.Update(ctx, &updated); err != nil {
+		log.Error("Failed to update game access", zap.Error(err))
+		return nil, err
+	}
+
+	updated.InviteLink = s.InviteLink(&updated)
+
+	log.Info("Game access updated",
+		zap.String("visibility", updated.Visibility),
+		zap.Int("allowed_users", len(updated.AllowedUserIDs)),
+		zap.Int64("allowed_chat_id", updated.AllowedChatID))
+
+	return &updated, nil
+}
+
+// RotateInviteToken выпускает новый токен приглашения, старая ссылка перестаёт действовать
+func (s *GameServiceImpl) RotateInviteToken(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*models.Game, error) {
+	log := s.logger.With(zap.String("method", "RotateInviteToken"), zap.String("game_id", gameID.String()))
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID != creatorID {
+		return nil, errors.New("only the creator can rotate the invite token")
+	}
+	if !game.IsPrivate() {
+		return nil, errors.New("game is not private")
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *game
+	updated.InviteToken = token
+	if err := s.gameRepo.Update(ctx, &updated); err != nil {
+		log.Error("Failed to rotate invite token", zap.Error(err))
+		return nil, err
+	}
+
+	updated.InviteLink = s.InviteLink(&updated)
+
+	log.Info("Invite token rotated")
+	return &updated, nil
+}
+
+// InviteLink формирует ссылку-приглашение в приватную игру (t.me/<bot>/<app>?startapp=game_<token>)
+func (s *GameServiceImpl) InviteLink(game *models.Game) string {
+	if s.botUsername == "" || !game.IsPrivate() || game.InviteToken == "" {
+		return ""
+	}
+	if s.miniAppName != "" {
+		return fmt.Sprintf("https://t.me/%s/%s?startapp=%s", s.botUsername, s.miniAppName, game.StartParam())
+	}
+	return fmt.Sprintf("https://t.me/%s?startapp=%s", s.botUsername, game.StartParam())
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// fakeMembership эмулирует проверку членства в Telegram-группе
+type fakeMembership struct {
+	members map[int64][]uint64
+	err     error
+}
+
+func (f *fakeMembership) IsChatMember(ctx context.Context, chatID int64, userID uint64) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	for _, id := range f.members[chatID] {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestGameService_CheckAccess(t *testing.T) {
+	membership := &fakeMembership{members: map[int64][]uint64{-100: {5}}}
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    mocks.NewMockGameRepository(),
+		Commission:  newTestCommissionService(),
+		Membership:  membership,
+		BotUsername: "wordle_bot",
+		MiniAppName: "play",
+	})
+
+	public := &models.Game{CreatorID: 1, Visibility: models.GameVisibilityPublic}
+	byToken := &models.Game{CreatorID: 1, Visibility: models.GameVisibilityPrivate, InviteToken: "abc123"}
+	restricted := &models.Game{CreatorID: 1, Visibility: models.GameVisibilityPrivate, InviteToken: "abc123",
+		AllowedUserIDs: []uint64{3}, AllowedChatID: -100}
+
+	tests := []struct {
+		name    string
+		game    *models.Game
+		userID  uint64
+		token   string
+		allowed bool
+	}{
+		{name: "публичная игра", game: public, userID: 2, allowed: true},
+		{name: "создатель", game: byToken, userID: 1, allowed: true},
+		{name: "верный токен", game: byToken, userID: 2, token: "abc123", allowed: true},
+		{name: "параметр startapp", game: byToken, userID: 2, token: "game_abc123", allowed: true},
+		{name: "без токена", game: byToken, userID: 2},
+		{name: "неверный токен", game: byToken, userID: 2, token: "other"},
+		{name: "в белом списке", game: restricted, userID: 3, allowed: true},
+		{name: "участник группы", game: restricted, userID: 5, allowed: true},
+		{name: "токена недостаточно при ограничениях", game: restricted, userID: 2, token: "abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := gameService.CheckAccess(context.Background(), tt.game, tt.userID, tt.token)
+			if tt.allowed && err != nil {
+				t.Errorf("CheckAccess() error = %v, want nil", err)
+			}
+			if !tt.allowed && !errors.Is(err, models.ErrGameAccessDenied) {
+				t.Errorf("CheckAccess() error = %v, want %v", err, models.ErrGameAccessDenied)
+			}
+		})
+	}
+
+	membership.err = errors.New("telegram unavailable")
+	if err := gameService.CheckAccess(context.Background(), restricted, 5, ""); err == nil || errors.Is(err, models.ErrGameAccessDenied) {
+		t.Errorf("CheckAccess() with failing membership check error = %v, want internal error", err)
+	}
+}
+
+func TestGameService_PrivateGames(t *testing.T) {
+	ctx := context.Background()
+	gameRepo := mocks.NewMockGameRepository()
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		Commission:  newTestCommissionService(),
+		BotUsername: "wordle_bot",
+		MiniAppName: "play",
+	})
+
+	newGame := func(visibility string) *models.Game {
+		return &models.Game{
+			CreatorID: 1, Word: "слово", Difficulty: "easy", MaxTries: 6, Title: "игра",
+			MinBet: 1, MaxBet: 2, RewardMultiplier: 2, Currency: models.CurrencyTON,
+			Visibility: visibility,
+		}
+	}
+
+	public := newGame("")
+	private := newGame(models.GameVisibilityPrivate)
+	for _, game := range []*models.Game{public, private} {
+		if err := gameService.CreateGame(ctx, game); err != nil {
+			t.Fatalf("CreateGame() error = %v", err)
+		}
+		_ = gameRepo.UpdateStatus(ctx, game.ID, models.GameStatusActive)
+	}
+
+	if public.Visibility != models.GameVisibilityPublic || public.InviteToken != "" {
+		t.Errorf("public game visibility = %s, token = %q", public.Visibility, public.InviteToken)
+	}
+	if private.InviteToken == "" {
+		t.Fatal("private game should get an invite token")
+	}
+	if private.InviteLink != "https://t.me/wordle_bot/play?startapp=game_"+private.InviteToken {
+		t.Errorf("invite link = %s", private.InviteLink)
+	}
+
+	if err := gameService.CreateGame(ctx, newGame("hidden")); err == nil {
+		t.Error("CreateGame() with invalid visibility should fail")
+	}
+
+	active, _ := gameService.GetActiveGames(ctx, 10, "")
+	found, _ := gameService.SearchGames(ctx, models.GameSearchFilter{}, 10, "")
+	for _, page := range []*models.GamePage{active, found} {
+		if len(page.Games) != 1 || page.Games[0].ID != public.ID {
+			t.Errorf("public listing = %v, want only the public game", page.Games)
+		}
+	}
+
+	byInvite, err := gameService.GetGameByInvite(ctx, private.StartParam())
+	if err != nil || byInvite.ID != private.ID {
+		t.Fatalf("GetGameByInvite() = %v, %v", byInvite, err)
+	}
+
+	oldToken := private.InviteToken
+	if _, err := gameService.RotateInviteToken(ctx, private.ID, 2); err == nil {
+		t.Error("RotateInviteToken() by another user should fail")
+	}
+	rotated, err := gameService.RotateInviteToken(ctx, private.ID, 1)
+	if err != nil {
+		t.Fatalf("RotateInviteToken() error = %v", err)
+	}
+	if rotated.InviteToken == oldToken {
+		t.Error("RotateInviteToken() should issue a new token")
+	}
+	if _, err := gameService.GetGameByInvite(ctx, oldToken); err == nil {
+		t.Error("GetGameByInvite() with a rotated token should fail")
+	}
+}
+
+func TestLobbyService_CreateLobbyPrivateGame(t *testing.T) {
+	ctx := context.Background()
+
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+	})
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        mocks.NewMockAttemptRepository(),
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово"}),
+		GameAccess:         gameService,
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 2, Username: "player", BalanceTon: 10})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+		Visibility: models.GameVisibilityPrivate, InviteToken: "abc123",
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	err := lobbyService.CreateLobby(ctx, &models.Lobby{GameID: game.ID, UserID: 2, BetAmount: 1})
+	if !errors.Is(err, models.ErrGameAccessDenied) {
+		t.Fatalf("CreateLobby() without invite error = %v, want %v", err, models.ErrGameAccessDenied)
+	}
+
+	lobby := &models.Lobby{GameID: game.ID, UserID: 2, BetAmount: 1, InviteToken: "abc123"}
+	if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("CreateLobby() with invite error = %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func setupDiscoveryService(t *testing.T) (*mocks.MockGameRepository, models.GameService) {
+	t.Helper()
+	gameRepo := mocks.NewMockGameRepository()
+	return gameRepo, NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		Commission:  newTestCommissionService(),
+		BotUsername: "wordle_bot",
+		MiniAppName: "play",
+	})
+}
+
+// discoveryGame создаёт активную публичную игру, которую затем настраивает тест
+func discoveryGame(gameRepo *mocks.MockGameRepository, title string, configure func(game *models.Game)) *models.Game {
+	game := &models.Game{CreatorID: 1, Title: title, Length: 5, MinBet: 1, MaxBet: 2, RewardMultiplier: 2, TimeLimit: 5,
+		Currency: models.CurrencyTON, RewardPoolTon: 10, Status: models.GameStatusActive, Visibility: models.GameVisibilityPublic}
+	if configure != nil {
+		configure(game)
+	}
+	_ = gameRepo.Create(context.Background(), game)
+	return game
+}
+
+func TestGameService_SearchGamesFilters(t *testing.T) {
+	ctx := context.Background()
+	gameRepo, gameService := setupDiscoveryService(t)
+
+	base := discoveryGame(gameRepo, "Утренняя разминка", nil)
+	usdt := discoveryGame(gameRepo, "Долларовая игра", func(g *models.Game) {
+		g.Currency, g.RewardPoolTon, g.RewardPoolUsdt = models.CurrencyUSDT, 0, 50
+	})
+	long := discoveryGame(gameRepo, "Длинные слова", func(g *models.Game) { g.Length = 7 })
+	generous := discoveryGame(gameRepo, "Щедрая игра", func(g *models.Game) { g.RewardMultiplier = 5 })
+	blitz := discoveryGame(gameRepo, "Блиц", func(g *models.Game) { g.TimeLimit = 1 })
+	_ = discoveryGame(gameRepo, "Почти пустой пул", func(g *models.Game) { g.ReservedAmount = 7 })
+	other := discoveryGame(gameRepo, "Чужая игра", func(g *models.Game) {
+		g.CreatorID = 2
+		g.Description = "Вечерняя разминка для друзей"
+	})
+
+	tests := []struct {
+		name   string
+		filter models.GameSearchFilter
+		want   []*models.Game
+	}{
+		{name: "валюта", filter: models.GameSearchFilter{Currency: models.CurrencyUSDT}, want: []*models.Game{usdt}},
+		{name: "длина слова", filter: models.GameSearchFilter{WordLength: 7}, want: []*models.Game{long}},
+		{name: "мультипликатор", filter: models.GameSearchFilter{MinMultiplier: 3, MaxMultiplier: 5}, want: []*models.Game{generous}},
+		{name: "лимит времени", filter: models.GameSearchFilter{MaxTimeLimit: 2}, want: []*models.Game{blitz}},
+		// Доступно 3 при максимальной ставке 2: пул меньше двух ставок
+		{name: "доступный пул", filter: models.GameSearchFilter{MinPoolBets: 2, CreatorID: 1, Currency: models.CurrencyTON},
+			want: []*models.Game{base, long, generous, blitz}},
+		{name: "создатель", filter: models.GameSearchFilter{CreatorID: 2}, want: []*models.Game{other}},
+		{name: "текст в названии и описании", filter: models.GameSearchFilter{Query: "разминка"}, want: []*models.Game{base, other}},
+		{name: "все слова запроса", filter: models.GameSearchFilter{Query: "вечерняя разминка"}, want: []*models.Game{other}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := gameService.SearchGames(ctx, tt.filter, 20, "")
+			if err != nil {
+				t.Fatalf("SearchGames() error = %v", err)
+			}
+			got := make(map[uuid.UUID]bool)
+			for _, game := range page.Games {
+				got[game.ID] = true
+			}
+			if len(page.Games) != len(tt.want) {
+				t.Fatalf("SearchGames() = %d games, want %d", len(page.Games), len(tt.want))
+			}
+			for _, game := range tt.want {
+				if !got[game.ID] {
+					t.Errorf("SearchGames() is missing %q", game.Title)
+				}
+			}
+		})
+	}
+}
+
+func TestGameService_SearchGamesSortAndCursor(t *testing.T) {
+	ctx := context.Background()
+	gameRepo, gameService := setupDiscoveryService(t)
+
+	created := time.Now().Add(-time.Hour)
+	plays := []int{3, 10, 3, 0, 7}
+	wins := []int{3, 1, 1, 0, 7}
+	games := make([]*models.Game, len(plays))
+	for i := range plays {
+		games[i] = discoveryGame(gameRepo, "Игра", func(g *models.Game) {
+			g.Plays, g.Wins = plays[i], wins[i]
+			g.RewardPoolTon = float64(10 * (i + 1))
+			// Одинаковое время создания: порядок внутри равных ключей определяется ID
+			g.CreatedAt = created
+		})
+	}
+
+	collect := func(sort string) []*models.Game {
+		t.Helper()
+		var all []*models.Game
+		cursor := ""
+		for range games {
+			page, err := gameService.SearchGames(ctx, models.GameSearchFilter{Sort: sort}, 2, cursor)
+			if err != nil {
+				t.Fatalf("SearchGames(%s) error = %v", sort, err)
+			}
+			all = append(all, page.Games...)
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		return all
+	}
+
+	for _, sort := range []string{models.GameSortNewest, models.GameSortPool, models.GameSortPopular, models.GameSortWinRate} {
+		all := collect(sort)
+		if len(all) != len(games) {
+			t.Fatalf("sort %s: got %d games across pages, want %d", sort, len(all), len(games))
+		}
+		seen := make(map[uuid.UUID]bool)
+		for i, game := range all {
+			if seen[game.ID] {
+				t.Errorf("sort %s: game %s returned twice", sort, game.ID)
+			}
+			seen[game.ID] = true
+			if i > 0 && game.Cursor(sort).Value > all[i-1].Cursor(sort).Value {
+				t.Errorf("sort %s: game %d is out of order", sort, i)
+			}
+		}
+	}
+
+	if top := collect(models.GameSortPool)[0]; top.ID != games[4].ID {
+		t.Errorf("largest pool = %v, want the last game", top.RewardPoolTon)
+	}
+	if top := collect(models.GameSortPopular)[0]; top.ID != games[1].ID {
+		t.Errorf("most popular game has %d plays, want 10", top.Plays)
+	}
+	// Доля побед 1 у двух игр, затем 1/3 и 0.1
+	byWinRate := collect(models.GameSortWinRate)
+	if byWinRate[2].ID != games[2].ID || byWinRate[3].ID != games[1].ID {
+		t.Errorf("win rate order = %v, %v, want 1/3 then 1/10", byWinRate[2].WinRate(), byWinRate[3].WinRate())
+	}
+
+	first, _ := gameService.SearchGames(ctx, models.GameSearchFilter{Sort: models.GameSortPool}, 2, "")
+	if _, err := gameService.SearchGames(ctx, models.GameSearchFilter{Sort: models.GameSortPopular}, 2, first.NextCursor); !errors.Is(err, models.ErrInvalidCursor) {
+		t.Errorf("cursor of another sort error = %v, want ErrInvalidCursor", err)
+	}
+	if _, err := gameService.SearchGames(ctx, models.GameSearchFilter{}, 2, "garbage"); !errors.Is(err, models.ErrInvalidCursor) {
+		t.Errorf("malformed cursor error = %v, want ErrInvalidCursor", err)
+	}
+	if _, err := gameService.SearchGames(ctx, models.GameSearchFilter{Sort: "random"}, 2, ""); err == nil {
+		t.Error("SearchGames() with unknown sort should fail")
+	}
+}
+
+func TestTransactionService_GetUserTransactionsCursor(t *testing.T) {
+	ctx := context.Background()
+	transactionRepo := mocks.NewMockTransactionRepository()
+	transactionService := NewTransactionServiceImpl(transactionRepo, mocks.NewMockUserRepository(), nil)
+
+	at := time.Now().Add(-time.Minute)
+	for i := 0; i < 5; i++ {
+		// Транзакции с одинаковым временем не должны теряться на границе страниц
+		_ = transactionRepo.Create(ctx, &models.Transaction{UserID: 1, Type: models.TransactionTypeDeposit, Amount: 1,
+			CreatedAt: at.Add(time.Duration(i/2) * time.Second)})
+	}
+	_ = transactionRepo.Create(ctx, &models.Transaction{UserID: 2, Type: models.TransactionTypeDeposit, Amount: 1})
+
+	seen := make(map[uuid.UUID]bool)
+	var last time.Time
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination did not finish")
+		}
+		page, err := transactionService.GetUserTransactions(ctx, 1, 2, cursor)
+		if err != nil {
+			t.Fatalf("GetUserTransactions() error = %v", err)
+		}
+		for _, tx := range page.Transactions {
+			if seen[tx.ID] || tx.UserID != 1 {
+				t.Errorf("unexpected transaction %+v", tx)
+			}
+			if !last.IsZero() && tx.CreatedAt.After(last) {
+				t.Error("transactions should go from newest to oldest")
+			}
+			seen[tx.ID] = true
+			last = tx.CreatedAt
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(seen) != 5 {
+		t.Errorf("got %d transactions across pages, want 5", len(seen))
+	}
+}
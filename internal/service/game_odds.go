@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// oddsMinAdjustResults - сколько результатов самой игры нужно, прежде чем подстраивать её мультипликатор
+const oddsMinAdjustResults = 10
+
+// validateOddsRule проверяет границы автоподстройки мультипликатора
+func validateOddsRule(rule models.OddsAdjustRule) error {
+	if rule.MinMultiplier < 0 || rule.MaxMultiplier < 0 {
+		return errors.New("odds multiplier bounds must not be negative")
+	}
+	if rule.MaxMultiplier == 0 {
+		if rule.MinMultiplier > 0 {
+			return errors.New("max multiplier is required")
+		}
+		return nil
+	}
+	if rule.MinMultiplier < pricingMinMultiplier {
+		return errors.New("min multiplier must be >= 1.0")
+	}
+	if rule.MaxMultiplier < rule.MinMultiplier {
+		return errors.New("max multiplier cannot be less than min multiplier")
+	}
+	return nil
+}
+
+// estimateOdds оценивает коэффициент игры. Ошибка оценки не прерывает операцию
+func (s *GameServiceImpl) estimateOdds(ctx context.Context, game *models.Game) *models.GameOdds {
+	if s.pricing == nil {
+		return nil
+	}
+	odds, err := s.pricing.EstimateOdds(ctx, game)
+	if err != nil {
+		s.logger.Warn("Failed to estimate game odds", zap.String("game_id", game.ID.String()), zap.Error(err))
+		return nil
+	}
+	return odds
+}
+
+// analyzeWord оценивает сложность слова. Ошибка оценки не прерывает операцию
+func (s *GameServiceImpl) analyzeWord(word string) *models.WordDifficulty {
+	if s.pricing == nil {
+		return nil
+	}
+	difficulty, err := s.pricing.AnalyzeWord(word)
+	if err != nil {
+		s.logger.Warn("Failed to analyze word difficulty", zap.Error(err))
+		return nil
+	}
+	return difficulty
+}
+
+// AnalyzeWord оценивает сложность слова до создания игры
+func (s *GameServiceImpl) AnalyzeWord(ctx context.Context, word string) (*models.WordDifficulty, error) {
+	if s.pricing == nil {
+		return nil, errors.New("word analysis is not available")
+	}
+
+	length := len([]rune(strings.TrimSpace(word)))
+	if length == 0 || length > 15 {
+		return nil, errors.New("invalid word length (must be 1-15)")
+	}
+
+	return s.pricing.AnalyzeWord(word)
+}
+
+// GetGameOdds возвращает оценку коэффициента игры (только для создателя: оценка раскрывает сложность слова)
+func (s *GameServiceImpl) GetGameOdds(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*models.GameOdds, error) {
+	if s.pricing == nil {
+		return nil, errors.New("odds estimation is not available")
+	}
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID != creatorID {
+		return nil, errors.New("only the creator can view game odds")
+	}
+
+	return s.pricing.EstimateOdds(ctx, game)
+}
+
+// SetOddsAutoAdjust задаёт границы автоподстройки мультипликатора. Нулевая верхняя граница выключает автоподстройку
+func (s *GameServiceImpl) SetOddsAutoAdjust(ctx context.Context, gameID uuid.UUID, creatorID uint64, rule models.OddsAdjustRule) (*models.Game, error) {
+	if err := validateOddsRule(rule); err != nil {
+		return nil, err
+	}
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID != creatorID {
+		return nil, errors.New("only the creator can change odds auto-adjustment")
+	}
+
+	switch game.Status {
+	case models.GameStatusClosing, models.GameStatusClosed:
+		return nil, errors.New("game is closed")
+	}
+
+	updated := *game
+	updated.OddsMinMultiplier = rule.MinMultiplier
+	updated.OddsMaxMultiplier = rule.MaxMultiplier
+	if err := s.gameRepo.Update(ctx, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ProcessOddsAdjustments подстраивает мультипликаторы активных игр под фактические результаты
+// в пределах границ, заданных создателями
+func (s *GameServiceImpl) ProcessOddsAdjustments(ctx context.Context) error {
+	log := s.logger.With(zap.String("method", "ProcessOddsAdjustments"))
+
+	if s.pricing == nil {
+		return nil
+	}
+
+	games, err := s.gameRepo.GetWithAutoOdds(ctx, scheduleBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, game := range games {
+		if err := s.adjustOdds(ctx, game); err != nil {
+			log.Warn("Failed to adjust game odds",
+				zap.String("game_id", game.ID.String()),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// adjustOdds приводит мультипликатор игры к рекомендуемому. Повышение ограничено доступным пулом:
+// игра должна по-прежнему принимать максимальную ставку. Активные лобби сохраняют свой мультипликатор
+func (s *GameServiceImpl) adjustOdds(ctx context.Context, game *models.Game) error {
+	odds, err := s.pricing.EstimateOdds(ctx, game)
+	if err != nil {
+		return err
+	}
+	if odds.GameResults < oddsMinAdjustResults {
+		return nil
+	}
+
+	target := math.Max(game.OddsMinMultiplier, math.Min(odds.SuggestedMultiplier, game.OddsMaxMultiplier))
+	if target > game.RewardMultiplier && game.MaxBet > 0 {
+		target = math.Max(game.RewardMultiplier, math.Min(target, math.Floor(game.GetAvailableRewardPool()/game.MaxBet*100)/100))
+	}
+	if math.Abs(target-game.RewardMultiplier) < 0.01 {
+		return nil
+	}
+
+	if err := s.gameRepo.UpdateRewardMultiplier(ctx, game.ID, target); err != nil {
+		return err
+	}
+
+	s.logger.Info("Game multiplier adjusted",
+		zap.String("game_id", game.ID.String()),
+		zap.Float64("from", game.RewardMultiplier),
+		zap.Float64("to", target),
+		zap.Float64("win_probability", odds.WinProbability),
+		zap.Int("game_results", odds.GameResults))
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// getCreatorGame получает игру и проверяет, что пользователь является её создателем
+func (s *GameServiceImpl) getCreatorGame(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*models.Game, error) {
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID != creatorID {
+		return nil, errors.New("only the creator can manage the game pool")
+	}
+	return game, nil
+}
+
+// WithdrawFromPool выводит часть пула активной игры на баланс создателя.
+// В пуле остаётся неснижаемый остаток: резерв активных лобби, максимальная выплата и порог автопополнения
+func (s *GameServiceImpl) WithdrawFromPool(ctx context.Context, gameID uuid.UUID, creatorID uint64, amount float64) (*models.Game, error) {
+	log := s.logger.With(zap.String("method", "WithdrawFromPool"),
+		zap.String("game_id", gameID.String()),
+		zap.Float64("amount", amount))
+
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	game, err := s.getCreatorGame(ctx, gameID, creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch game.Status {
+	case models.GameStatusClosing, models.GameStatusClosed:
+		return nil, errors.New("game is closed, the pool is refunded automatically")
+	}
+
+	if withdrawable := game.GetWithdrawablePool(); amount > withdrawable {
+		return nil, fmt.Errorf("amount exceeds withdrawable pool: %.4f %s available", withdrawable, game.Currency)
+	}
+
+	// Списание из пула, зачисление создателю и запись в журнал выполняются в одной транзакции.
+	// Списание атомарно проверяет остаток: параллельные ставки могли зарезервировать часть пула
+	err = withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		ok, err := s.gameRepo.WithdrawRewardPool(ctx, game.ID, amount, game.GetPoolSafetyMargin())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("withdrawable pool changed, try again")
+		}
+
+		if err := s.txService.WithdrawGamePool(ctx, creatorID, amount, game.Currency, game.ID); err != nil {
+			return fmt.Errorf("failed to credit pool withdrawal: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Pool withdrawn by creator", zap.Uint64("creator_id", creatorID))
+
+	return s.gameRepo.GetByID(ctx, game.ID)
+}
+
+// SetAutoTopUp задаёт правило автопополнения пула. Нулевая сумма выключает автопополнение
+func (s *GameServiceImpl) SetAutoTopUp(ctx context.Context, gameID uuid.UUID, creatorID uint64, rule models.AutoTopUpRule) (*models.Game, error) {
+	if rule.Threshold < 0 || rule.Amount < 0 {
+		return nil, errors.New("auto top-up threshold and amount must not be negative")
+	}
+	if rule.Amount > 0 && rule.Threshold == 0 {
+		return nil, errors.New("auto top-up threshold is required")
+	}
+	if rule.Amount == 0 {
+		rule.Threshold = 0
+	}
+
+	game, err := s.getCreatorGame(ctx, gameID, creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch game.Status {
+	case models.GameStatusClosing, models.GameStatusClosed:
+		return nil, errors.New("game is closed")
+	}
+
+	updated := *game
+	updated.AutoTopUpThreshold = rule.Threshold
+	updated.AutoTopUpAmount = rule.Amount
+	if err := s.gameRepo.Update(ctx, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ProcessAutoTopUps пополняет с баланса создателей пулы игр, опустившиеся ниже порога
+func (s *GameServiceImpl) ProcessAutoTopUps(ctx context.Context) error {
+	log := s.logger.With(zap.String("method", "ProcessAutoTopUps"))
+
+	games, err := s.gameRepo.GetBelowTopUpThreshold(ctx, scheduleBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get games below top-up threshold: %w", err)
+	}
+
+	for _, game := range games {
+		if err := s.topUpPool(ctx, game); err != nil {
+			log.Warn("Failed to top up game pool",
+				zap.String("game_id", game.ID.String()),
+				zap.Uint64("creator_id", game.CreatorID),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// topUpPool списывает сумму автопополнения с баланса создателя и зачисляет её в пул
+func (s *GameServiceImpl) topUpPool(ctx context.Context, game *models.Game) error {
+	if !game.NeedsTopUp() {
+		return nil
+	}
+
+	// Списание с создателя, пополнение пула и запись в журнал выполняются в одной транзакции
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.txService.TopUpGamePool(ctx, game.CreatorID, game.AutoTopUpAmount, game.Currency, game.ID); err != nil {
+			return err
+		}
+		if err := s.gameRepo.IncrementRewardPool(ctx, game.ID, game.AutoTopUpAmount); err != nil {
+			return fmt.Errorf("failed to top up reward pool: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Game pool topped up",
+		zap.String("game_id", game.ID.String()),
+		zap.Float64("amount", game.AutoTopUpAmount),
+		zap.String("currency", game.Currency))
+
+	return nil
+}
+
+// GetPoolPnL возвращает сводку доходов и расходов создателя по пулу игры
+func (s *GameServiceImpl) GetPoolPnL(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*models.GamePoolPnL, error) {
+	game, err := s.getCreatorGame(ctx, gameID, creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.txService.GetGameTotals(ctx, game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game transactions: %w", err)
+	}
+
+	currentPool := game.RewardPoolUsdt
+	if game.Currency == models.CurrencyTON {
+		currentPool = game.RewardPoolTon
+	}
+
+	pnl := &models.GamePoolPnL{
+		GameID:       game.ID,
+		Currency:     game.Currency,
+		Deposited:    totals[models.TransactionTypeGameDeposit],
+		ToppedUp:     totals[models.TransactionTypePoolTopUp],
+		Withdrawn:    totals[models.TransactionTypePoolWithdraw],
+		Refunded:     totals[models.TransactionTypeGameRefund],
+		BetsReceived: totals[models.TransactionTypeBet],
+		RewardsPaid:  totals[models.TransactionTypeReward],
+		CurrentPool:  currentPool,
+		Reserved:     game.ReservedAmount,
+		AutoTopUp: models.AutoTopUpRule{
+			Threshold: game.AutoTopUpThreshold,
+			Amount:    game.AutoTopUpAmount,
+		},
+	}
+	if game.Status != models.GameStatusClosing && game.Status != models.GameStatusClosed {
+		pnl.Withdrawable = game.GetWithdrawablePool()
+	}
+	pnl.NetResult = pnl.Withdrawn + pnl.Refunded + pnl.CurrentPool - pnl.Deposited - pnl.ToppedUp
+
+	return pnl, nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestGame_GetWithdrawablePool(t *testing.T) {
+	tests := []struct {
+		name string
+		game models.Game
+		want float64
+	}{
+		{name: "остаток под максимальную выплату", game: models.Game{MaxBet: 1, RewardMultiplier: 2, RewardPoolTon: 10}, want: 8},
+		{name: "с учётом резерва", game: models.Game{MaxBet: 1, RewardMultiplier: 2, RewardPoolTon: 10, ReservedAmount: 4}, want: 4},
+		{name: "порог автопополнения выше выплаты", game: models.Game{MaxBet: 1, RewardMultiplier: 2, RewardPoolTon: 10, AutoTopUpThreshold: 5}, want: 5},
+		{name: "пул меньше остатка", game: models.Game{MaxBet: 5, RewardMultiplier: 3, RewardPoolTon: 10}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.game.Currency = models.CurrencyTON
+			if got := tt.game.GetWithdrawablePool(); got != tt.want {
+				t.Errorf("GetWithdrawablePool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameService_PoolManagement(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+	txService := NewTransactionServiceImpl(txRepo, userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "creator", BalanceTon: 3})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, MaxBet: 1, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 10, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+	_ = txRepo.Create(ctx, &models.Transaction{
+		ID: uuid.New(), UserID: 1, Type: models.TransactionTypeGameDeposit, Amount: 10,
+		Currency: models.CurrencyTON, Status: models.TransactionStatusCompleted, GameID: &game.ID,
+	})
+
+	if _, err := gameService.WithdrawFromPool(ctx, game.ID, 2, 1); err == nil {
+		t.Error("WithdrawFromPool() by another user should fail")
+	}
+	if _, err := gameService.WithdrawFromPool(ctx, game.ID, 1, 9); err == nil {
+		t.Error("WithdrawFromPool() beyond the safety margin should fail")
+	}
+	if _, err := gameService.WithdrawFromPool(ctx, game.ID, 1, 6); err != nil {
+		t.Fatalf("WithdrawFromPool() error = %v", err)
+	}
+	if game.RewardPoolTon != 4 {
+		t.Errorf("reward pool = %v, want 4", game.RewardPoolTon)
+	}
+	assertTonBalance(t, userRepo, 1, 9)
+
+	if _, err := gameService.SetAutoTopUp(ctx, game.ID, 1, models.AutoTopUpRule{Amount: 2}); err == nil {
+		t.Error("SetAutoTopUp() without threshold should fail")
+	}
+	game, err := gameService.SetAutoTopUp(ctx, game.ID, 1, models.AutoTopUpRule{Threshold: 5, Amount: 2})
+	if err != nil {
+		t.Fatalf("SetAutoTopUp() error = %v", err)
+	}
+
+	// Доступный пул (4) ниже порога (5) - пополняем с баланса создателя
+	if err := gameService.ProcessAutoTopUps(ctx); err != nil {
+		t.Fatalf("ProcessAutoTopUps() error = %v", err)
+	}
+	if game.RewardPoolTon != 6 {
+		t.Errorf("reward pool after top-up = %v, want 6", game.RewardPoolTon)
+	}
+	assertTonBalance(t, userRepo, 1, 7)
+
+	// Пул выше порога - повторного пополнения нет
+	if err := gameService.ProcessAutoTopUps(ctx); err != nil {
+		t.Fatalf("ProcessAutoTopUps() error = %v", err)
+	}
+	assertTonBalance(t, userRepo, 1, 7)
+
+	pnl, err := gameService.GetPoolPnL(ctx, game.ID, 1)
+	if err != nil {
+		t.Fatalf("GetPoolPnL() error = %v", err)
+	}
+	if pnl.Deposited != 10 || pnl.Withdrawn != 6 || pnl.ToppedUp != 2 || pnl.CurrentPool != 6 {
+		t.Errorf("pnl = %+v", pnl)
+	}
+	if pnl.Withdrawable != 1 {
+		t.Errorf("withdrawable = %v, want 1", pnl.Withdrawable)
+	}
+	if pnl.NetResult != 0 {
+		t.Errorf("net result = %v, want 0", pnl.NetResult)
+	}
+}
+
+func TestGameService_AutoTopUpInsufficientBalance(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "creator", BalanceTon: 1})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, MaxBet: 1, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 2, Status: models.GameStatusActive, AutoTopUpThreshold: 5, AutoTopUpAmount: 3,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	if err := gameService.ProcessAutoTopUps(ctx); err != nil {
+		t.Fatalf("ProcessAutoTopUps() error = %v", err)
+	}
+	if game.RewardPoolTon != 2 {
+		t.Errorf("reward pool = %v, want 2", game.RewardPoolTon)
+	}
+	assertTonBalance(t, userRepo, 1, 1)
+}
+
+func TestGameService_PoolClosedGame(t *testing.T) {
+	ctx := context.Background()
+	gameRepo := mocks.NewMockGameRepository()
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:   gameRepo,
+		Commission: newTestCommissionService(),
+	})
+
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, MaxBet: 1, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 10, Status: models.GameStatusClosing,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	if _, err := gameService.WithdrawFromPool(ctx, game.ID, 1, 1); err == nil {
+		t.Error("WithdrawFromPool() from a closing game should fail")
+	}
+	if game.RewardPoolTon != 10 {
+		t.Errorf("reward pool = %v, want 10", game.RewardPoolTon)
+	}
+}
+
+func TestLobbyService_LostBetFundsPool(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	historyService := NewHistoryService(mocks.NewMockHistoryRepository(), gameRepo, userRepo, lobbyRepo)
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        mocks.NewMockAttemptRepository(),
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово"}),
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "player", BalanceTon: 10})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 2, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	lobby := &models.Lobby{GameID: game.ID, UserID: 1, BetAmount: 2}
+	if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+
+	// Пока лобби активно, пул под выплату зарезервирован и недоступен к выводу
+	if got := game.GetWithdrawablePool(); got != 100-4-10 {
+		t.Errorf("withdrawable with active lobby = %v, want %v", got, 100-4-10)
+	}
+
+	if err := lobbyService.FinishLobby(ctx, lobby.ID, false); err != nil {
+		t.Fatalf("FinishLobby() error = %v", err)
+	}
+
+	// Проигранная ставка за вычетом комиссии остаётся в пуле создателя
+	if want := 100 + 2*(1-0.05); game.RewardPoolTon < want-1e-9 || game.RewardPoolTon > want+1e-9 {
+		t.Errorf("reward pool = %v, want %v", game.RewardPoolTon, want)
+	}
+	if game.ReservedAmount != 0 {
+		t.Errorf("reserved amount = %v, want 0", game.ReservedAmount)
+	}
+}
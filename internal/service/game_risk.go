@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// validateRiskLimits проверяет лимиты риска, задаваемые создателем
+func validateRiskLimits(limits models.GameRiskLimits) error {
+	if limits.MaxDailyLoss < 0 || limits.MaxActiveLobbies < 0 || limits.PoolFloor < 0 {
+		return errors.New("risk limits must not be negative")
+	}
+	return nil
+}
+
+// UpdateRiskLimits задаёт лимиты риска игры (только для создателя)
+func (s *GameServiceImpl) UpdateRiskLimits(ctx context.Context, gameID uuid.UUID, creatorID uint64, limits models.GameRiskLimits) (*models.Game, error) {
+	if err := validateRiskLimits(limits); err != nil {
+		return nil, err
+	}
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID != creatorID {
+		return nil, errors.New("only the creator can change risk limits")
+	}
+
+	switch game.Status {
+	case models.GameStatusClosing, models.GameStatusClosed:
+		return nil, errors.New("game is closed")
+	}
+
+	updated := *game
+	updated.MaxDailyLoss = limits.MaxDailyLoss
+	updated.MaxActiveLobbies = limits.MaxActiveLobbies
+	updated.PoolFloor = limits.PoolFloor
+	if err := s.gameRepo.Update(ctx, &updated); err != nil {
+		return nil, err
+	}
+
+	// Новые лимиты могут оказаться уже исчерпанными
+	if err := s.EnforceRiskLimits(ctx, updated.ID); err != nil {
+		return nil, err
+	}
+
+	return s.gameRepo.GetByID(ctx, updated.ID)
+}
+
+// EnforceRiskLimits деактивирует игру, которая по уже состоявшимся результатам не может принять
+// даже минимальную ставку (исчерпан дневной лимит убытка или достигнута нижняя граница пула),
+// и уведомляет создателя. Лимит одновременных лобби только ограничивает приём ставок
+func (s *GameServiceImpl) EnforceRiskLimits(ctx context.Context, gameID uuid.UUID) error {
+	log := s.logger.With(zap.String("method", "EnforceRiskLimits"), zap.String("game_id", gameID.String()))
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status != models.GameStatusActive {
+		return nil
+	}
+
+	reason := game.RiskLimitReached(time.Now())
+	if reason == "" {
+		return nil
+	}
+
+	if err := s.DeactivateGame(ctx, game.ID); err != nil {
+		return fmt.Errorf("failed to pause game: %w", err)
+	}
+
+	log.Warn("Game paused by risk limit",
+		zap.String("reason", reason),
+		zap.Float64("daily_loss", game.GetDailyLoss(time.Now())),
+		zap.Float64("reward_pool", game.GetRewardPool()))
+
+	s.notifyCreator(ctx, game, fmt.Sprintf(
+		"Game \"%s\" was paused: risk limit reached (%s). Pool: %.4f %s, loss today: %.4f %s. "+
+			"Active lobbies will finish normally, activate the game to resume.",
+		game.Title, reason, game.GetRewardPool(), game.Currency, game.GetDailyLoss(time.Now()), game.Currency))
+
+	return nil
+}
+
+// notifyCreator отправляет уведомление создателю игры. Ошибка отправки не прерывает операцию
+func (s *GameServiceImpl) notifyCreator(ctx context.Context, game *models.Game, text string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.NotifyUser(ctx, game.CreatorID, text); err != nil {
+		s.logger.Warn("Failed to notify creator",
+			zap.String("game_id", game.ID.String()),
+			zap.Uint64("creator_id", game.CreatorID),
+			zap.Error(err))
+	}
+}
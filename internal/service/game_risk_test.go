@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// fakeNotifier запоминает отправленные уведомления
+type fakeNotifier struct {
+	messages map[uint64][]string
+}
+
+func (f *fakeNotifier) NotifyUser(ctx context.Context, userID uint64, text string) error {
+	if f.messages == nil {
+		f.messages = make(map[uint64][]string)
+	}
+	f.messages[userID] = append(f.messages[userID], text)
+	return nil
+}
+
+func TestGame_CheckExposure(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	today := models.RiskDay(now)
+
+	tests := []struct {
+		name          string
+		game          models.Game
+		reward        float64
+		activeLobbies int
+		wantErr       bool
+	}{
+		{name: "без лимитов", game: models.Game{RewardPoolTon: 10}, reward: 100},
+		{name: "лимит лобби", game: models.Game{MaxActiveLobbies: 2}, activeLobbies: 2, wantErr: true},
+		{name: "в пределах дневного убытка", game: models.Game{MaxDailyLoss: 10, ReservedAmount: 4, DailyLoss: 3, DailyLossDay: &today}, reward: 3},
+		{name: "резерв превышает дневной убыток", game: models.Game{MaxDailyLoss: 10, ReservedAmount: 4, DailyLoss: 3, DailyLossDay: &today}, reward: 4, wantErr: true},
+		{name: "вчерашний убыток не учитывается", game: models.Game{MaxDailyLoss: 10, DailyLoss: 9, DailyLossDay: &yesterday}, reward: 5},
+		{name: "нижняя граница пула", game: models.Game{RewardPoolTon: 10, PoolFloor: 5, ReservedAmount: 2}, reward: 4, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.game.Currency = models.CurrencyTON
+			err := tt.game.CheckExposure(tt.reward, tt.activeLobbies, now)
+			if tt.wantErr && !errors.Is(err, models.ErrRiskLimitReached) {
+				t.Errorf("CheckExposure() error = %v, want %v", err, models.ErrRiskLimitReached)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("CheckExposure() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestLobbyService_RiskLimitsPauseGame(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	notifier := &fakeNotifier{}
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+		Notifier:    notifier,
+	})
+	historyService := NewHistoryService(mocks.NewMockHistoryRepository(), gameRepo, userRepo, lobbyRepo)
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        mocks.NewMockAttemptRepository(),
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово"}),
+		GameAccess:         gameService,
+		RiskGuard:          gameService,
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "creator"})
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 2, Username: "first", BalanceTon: 10})
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 3, Username: "second", BalanceTon: 10})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, Title: "игра", Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+		MaxDailyLoss: 6, MaxActiveLobbies: 1,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	if err := lobbyService.CreateLobby(ctx, &models.Lobby{GameID: game.ID, UserID: 2, BetAmount: 3}); !errors.Is(err, models.ErrRiskLimitReached) {
+		t.Fatalf("CreateLobby() above daily loss error = %v, want %v", err, models.ErrRiskLimitReached)
+	}
+
+	first := &models.Lobby{GameID: game.ID, UserID: 2, BetAmount: 2}
+	if err := lobbyService.CreateLobby(ctx, first); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	if err := lobbyService.CreateLobby(ctx, &models.Lobby{GameID: game.ID, UserID: 3, BetAmount: 1}); !errors.Is(err, models.ErrRiskLimitReached) {
+		t.Fatalf("CreateLobby() above active lobbies error = %v, want %v", err, models.ErrRiskLimitReached)
+	}
+
+	// Выигрыш исчерпывает дневной лимит: минимальная ставка больше не укладывается в лимит
+	if _, err := lobbyService.ProcessAttempt(ctx, first.ID, "слово"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+
+	paused, _ := gameRepo.GetByID(ctx, game.ID)
+	if paused.Status != models.GameStatusInactive {
+		t.Errorf("status = %s, want %s", paused.Status, models.GameStatusInactive)
+	}
+	if paused.GetDailyLoss(time.Now()) <= 0 {
+		t.Errorf("daily loss = %v, want positive", paused.GetDailyLoss(time.Now()))
+	}
+	if len(notifier.messages[1]) != 1 {
+		t.Errorf("creator notifications = %v, want 1", notifier.messages[1])
+	}
+}
+
+func TestGameService_UpdateRiskLimits(t *testing.T) {
+	ctx := context.Background()
+	gameRepo := mocks.NewMockGameRepository()
+	notifier := &fakeNotifier{}
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:   gameRepo,
+		Commission: newTestCommissionService(),
+		Notifier:   notifier,
+	})
+
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, MinBet: 1, MaxBet: 2, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 10, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	if _, err := gameService.UpdateRiskLimits(ctx, game.ID, 2, models.GameRiskLimits{PoolFloor: 1}); err == nil {
+		t.Error("UpdateRiskLimits() by another user should fail")
+	}
+	if _, err := gameService.UpdateRiskLimits(ctx, game.ID, 1, models.GameRiskLimits{MaxDailyLoss: -1}); err == nil {
+		t.Error("UpdateRiskLimits() with negative limit should fail")
+	}
+
+	updated, err := gameService.UpdateRiskLimits(ctx, game.ID, 1, models.GameRiskLimits{MaxActiveLobbies: 3, PoolFloor: 5})
+	if err != nil {
+		t.Fatalf("UpdateRiskLimits() error = %v", err)
+	}
+	if updated.Status != models.GameStatusActive || updated.MaxActiveLobbies != 3 || updated.PoolFloor != 5 {
+		t.Errorf("updated game = %+v", updated)
+	}
+
+	// Нижняя граница выше текущего пула - игра сразу приостанавливается
+	updated, err = gameService.UpdateRiskLimits(ctx, game.ID, 1, models.GameRiskLimits{PoolFloor: 9})
+	if err != nil {
+		t.Fatalf("UpdateRiskLimits() error = %v", err)
+	}
+	if updated.Status != models.GameStatusInactive {
+		t.Errorf("status = %s, want %s", updated.Status, models.GameStatusInactive)
+	}
+	if len(notifier.messages[1]) != 1 {
+		t.Errorf("creator notifications = %v, want 1", notifier.messages[1])
+	}
+}
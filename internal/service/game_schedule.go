@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// scheduleBatchSize - максимальное число игр, обрабатываемых планировщиком за один проход
+const scheduleBatchSize = 100
+
+// reserveEpsilon - порог, ниже которого зарезервированная сумма считается нулевой
+const reserveEpsilon = 1e-9
+
+// validateGameSchedule проверяет запланированные время начала и окончания игры
+func validateGameSchedule(game *models.Game, now time.Time) error {
+	if game.EndsAt != nil && !game.EndsAt.After(now) {
+		return errors.New("ends_at must be in the future")
+	}
+	if game.StartsAt != nil && game.EndsAt != nil && !game.EndsAt.After(*game.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// CloseGame закрывает игру по запросу создателя.
+// Новые ставки перестают приниматься, остаток пула возвращается после завершения активных лобби
+func (s *GameServiceImpl) CloseGame(ctx context.Context, gameID uuid.UUID, creatorID uint64) (*models.Game, error) {
+	log := s.logger.With(zap.String("method", "CloseGame"), zap.String("game_id", gameID.String()))
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID != creatorID {
+		return nil, errors.New("only the creator can close the game")
+	}
+
+	switch game.Status {
+	case models.GameStatusClosing, models.GameStatusClosed:
+		return nil, errors.New("game is already closed")
+	}
+
+	ok, err := s.gameRepo.TransitionStatus(ctx, game.ID, game.Status, models.GameStatusClosing)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("game status changed, try again")
+	}
+
+	log.Info("Game is closing", zap.String("previous_status", game.Status))
+
+	if err := s.finishClosing(ctx, game.ID); err != nil {
+		return nil, err
+	}
+
+	return s.gameRepo.GetByID(ctx, game.ID)
+}
+
+// ProcessScheduledGames активирует игры, время начала которых наступило,
+// закрывает игры с истёкшим временем окончания и возвращает пул закрывающихся игр без активных лобби
+func (s *GameServiceImpl) ProcessScheduledGames(ctx context.Context) error {
+	log := s.logger.With(zap.String("method", "ProcessScheduledGames"))
+	now := time.Now()
+
+	toStart, err := s.gameRepo.GetDueToStart(ctx, now, scheduleBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get games due to start: %w", err)
+	}
+	for _, game := range toStart {
+		var ok bool
+		err := withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+			var err error
+			ok, err = s.gameRepo.TransitionStatus(ctx, game.ID, models.GameStatusScheduled, models.GameStatusActive)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return gameActivatedEvent(game)
+		})
+		if err != nil {
+			log.Error("Failed to activate scheduled game", zap.String("game_id", game.ID.String()), zap.Error(err))
+			continue
+		}
+		if ok {
+			log.Info("Scheduled game activated", zap.String("game_id", game.ID.String()))
+		}
+	}
+
+	toClose, err := s.gameRepo.GetDueToClose(ctx, now, scheduleBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get games due to close: %w", err)
+	}
+	for _, game := range toClose {
+		ok, err := s.gameRepo.TransitionStatus(ctx, game.ID, game.Status, models.GameStatusClosing)
+		if err != nil {
+			log.Error("Failed to close game", zap.String("game_id", game.ID.String()), zap.Error(err))
+			continue
+		}
+		if ok {
+			log.Info("Game reached its end time, closing", zap.String("game_id", game.ID.String()))
+		}
+	}
+
+	closing, err := s.gameRepo.GetByStatus(ctx, models.GameStatusClosing, scheduleBatchSize, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get closing games: %w", err)
+	}
+	for _, game := range closing {
+		if err := s.finishClosing(ctx, game.ID); err != nil {
+			log.Error("Failed to finish closing game", zap.String("game_id", game.ID.String()), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// finishClosing закрывает игру, если у неё не осталось активных лобби,
+// и возвращает создателю остаток пула транзакцией game_refund
+func (s *GameServiceImpl) finishClosing(ctx context.Context, gameID uuid.UUID) error {
+	log := s.logger.With(zap.String("method", "finishClosing"), zap.String("game_id", gameID.String()))
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status != models.GameStatusClosing {
+		return nil
+	}
+
+	// Ждём завершения активных лобби: их выигрыши зарезервированы в пуле
+	if game.ReservedAmount > reserveEpsilon {
+		log.Debug("Waiting for active lobbies to finish", zap.Float64("reserved", game.ReservedAmount))
+		return nil
+	}
+
+	// Закрытие, списание остатка из пула и возврат создателю выполняются в одной транзакции.
+	// Пул уменьшается относительно текущего значения, а не перезаписывается прочитанным ранее
+	refund := game.GetRefundablePool()
+	var closed bool
+	err = withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		ok, err := s.gameRepo.TransitionStatus(ctx, game.ID, models.GameStatusClosing, models.GameStatusClosed)
+		if err != nil || !ok {
+			return err
+		}
+		closed = true
+
+		if refund <= 0 {
+			return nil
+		}
+		if err := s.gameRepo.DecrementRewardPool(ctx, game.ID, refund); err != nil {
+			return fmt.Errorf("failed to update reward pool: %w", err)
+		}
+		if err := s.txService.RefundGamePool(ctx, game.CreatorID, refund, game.Currency, game.ID); err != nil {
+			return fmt.Errorf("failed to refund pool to creator: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !closed {
+		// Игру уже закрыл другой обработчик
+		return nil
+	}
+	if refund <= 0 {
+		log.Info("Game closed, nothing to refund")
+		return nil
+	}
+
+	log.Info("Game closed, pool refunded to creator",
+		zap.Uint64("creator_id", game.CreatorID),
+		zap.Float64("refund", refund),
+		zap.String("currency", game.Currency))
+
+	return nil
+}
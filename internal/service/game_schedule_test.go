@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestValidateGameSchedule(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	soon := now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name    string
+		game    models.Game
+		wantErr bool
+	}{
+		{name: "без расписания", game: models.Game{}},
+		{name: "начало и конец в будущем", game: models.Game{StartsAt: &soon, EndsAt: &later}},
+		{name: "конец в прошлом", game: models.Game{EndsAt: &past}, wantErr: true},
+		{name: "конец раньше начала", game: models.Game{StartsAt: &later, EndsAt: &soon}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGameSchedule(&tt.game, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateGameSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGameService_ScheduledActivation(t *testing.T) {
+	ctx := context.Background()
+	gameRepo := mocks.NewMockGameRepository()
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:   gameRepo,
+		Commission: newTestCommissionService(),
+	})
+
+	startsAt := time.Now().Add(time.Hour)
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, MaxBet: 1, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 2, Status: models.GameStatusPending, StartsAt: &startsAt,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	if err := gameService.ActivateGame(ctx, game.ID); err != nil {
+		t.Fatalf("ActivateGame() error = %v", err)
+	}
+	if game.Status != models.GameStatusScheduled {
+		t.Fatalf("status = %s, want %s", game.Status, models.GameStatusScheduled)
+	}
+
+	if err := gameService.ProcessScheduledGames(ctx); err != nil {
+		t.Fatalf("ProcessScheduledGames() error = %v", err)
+	}
+	if game.Status != models.GameStatusScheduled {
+		t.Fatalf("status before starts_at = %s, want %s", game.Status, models.GameStatusScheduled)
+	}
+
+	started := time.Now().Add(-time.Minute)
+	game.StartsAt = &started
+	if err := gameService.ProcessScheduledGames(ctx); err != nil {
+		t.Fatalf("ProcessScheduledGames() error = %v", err)
+	}
+	if game.Status != models.GameStatusActive {
+		t.Errorf("status after starts_at = %s, want %s", game.Status, models.GameStatusActive)
+	}
+}
+
+func TestGameService_ClosingRefundsPool(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+	txService := NewTransactionServiceImpl(txRepo, userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "creator"})
+
+	endsAt := time.Now().Add(-time.Minute)
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, MaxBet: 1, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 10, ReservedAmount: 2, Status: models.GameStatusActive, EndsAt: &endsAt,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	if active, _ := gameService.GetActiveGames(ctx, 10, ""); len(active.Games) != 0 {
+		t.Errorf("ended game should be hidden from listings, got %d games", len(active.Games))
+	}
+
+	// Активное лобби удерживает резерв - игра ждёт его завершения
+	if err := gameService.ProcessScheduledGames(ctx); err != nil {
+		t.Fatalf("ProcessScheduledGames() error = %v", err)
+	}
+	if game.Status != models.GameStatusClosing {
+		t.Fatalf("status = %s, want %s", game.Status, models.GameStatusClosing)
+	}
+	assertTonBalance(t, userRepo, 1, 0)
+
+	// Лобби завершилось проигрышем игрока - резерв освобождён
+	_ = gameRepo.DecrementReservedAmount(ctx, game.ID, 2)
+	if err := gameService.ProcessScheduledGames(ctx); err != nil {
+		t.Fatalf("ProcessScheduledGames() error = %v", err)
+	}
+	if game.Status != models.GameStatusClosed {
+		t.Fatalf("status = %s, want %s", game.Status, models.GameStatusClosed)
+	}
+	if game.RewardPoolTon != 0 {
+		t.Errorf("reward pool = %v, want 0", game.RewardPoolTon)
+	}
+	assertTonBalance(t, userRepo, 1, 10)
+
+	refunds, _ := txRepo.GetByType(ctx, models.TransactionTypeGameRefund, 10, 0)
+	if len(refunds) != 1 || refunds[0].Amount != 10 || refunds[0].GameID == nil || *refunds[0].GameID != game.ID {
+		t.Errorf("game refund transactions = %+v", refunds)
+	}
+
+	// Повторный проход не возвращает пул второй раз
+	if err := gameService.ProcessScheduledGames(ctx); err != nil {
+		t.Fatalf("ProcessScheduledGames() error = %v", err)
+	}
+	assertTonBalance(t, userRepo, 1, 10)
+}
+
+func TestGameService_CloseGame(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "creator"})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, Currency: models.CurrencyTON,
+		RewardPoolTon: 5, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	if _, err := gameService.CloseGame(ctx, game.ID, 2); err == nil {
+		t.Error("CloseGame() by another user should fail")
+	}
+
+	closed, err := gameService.CloseGame(ctx, game.ID, 1)
+	if err != nil {
+		t.Fatalf("CloseGame() error = %v", err)
+	}
+	if closed.Status != models.GameStatusClosed {
+		t.Errorf("status = %s, want %s", closed.Status, models.GameStatusClosed)
+	}
+	assertTonBalance(t, userRepo, 1, 5)
+
+	if _, err := gameService.CloseGame(ctx, game.ID, 1); err == nil {
+		t.Error("CloseGame() of a closed game should fail")
+	}
+}
+
+// staleGameRepository возвращает снимок игры, прочитанный до параллельного изменения пула
+type staleGameRepository struct {
+	*mocks.MockGameRepository
+	stale *models.Game
+}
+
+func (r *staleGameRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Game, error) {
+	copied := *r.stale
+	return &copied, nil
+}
+
+func TestGameService_ClosingKeepsConcurrentPoolChanges(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "creator"})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 1, Currency: models.CurrencyTON,
+		RewardPoolTon: 10, Status: models.GameStatusClosing,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	// Пул прочитан до того, как в него поступила ставка проигравшего лобби
+	stale := *game
+	_ = gameRepo.IncrementRewardPool(ctx, game.ID, 2)
+
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:   &staleGameRepository{MockGameRepository: gameRepo, stale: &stale},
+		TxService:  txService,
+		Commission: newTestCommissionService(),
+		Transactor: mocks.NewMockTransactor(),
+	})
+	if err := gameService.ProcessScheduledGames(ctx); err != nil {
+		t.Fatalf("ProcessScheduledGames() error = %v", err)
+	}
+
+	if game.Status != models.GameStatusClosed {
+		t.Fatalf("status = %s, want %s", game.Status, models.GameStatusClosed)
+	}
+	// Возвращён прочитанный остаток, поступившая позже ставка осталась в пуле
+	assertTonBalance(t, userRepo, 1, 10)
+	if game.RewardPoolTon != 2 {
+		t.Errorf("reward pool = %v, want 2", game.RewardPoolTon)
+	}
+}
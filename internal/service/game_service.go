@@ -12,39 +12,86 @@ import (
 	"github.com/TakuroBreath/wordle/internal/logger"
 	"github.com/TakuroBreath/wordle/internal/models"
 	"github.com/TakuroBreath/wordle/internal/repository"
-	"github.com/TakuroBreath/wordle/pkg/metrics"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// Допустимая длина загадываемого слова: в играх и дуэлях одинаковая
+const (
+	minWordLength = 1
+	maxWordLength = 15
+)
+
 // GameServiceImpl представляет собой реализацию GameService
 type GameServiceImpl struct {
-	gameRepo       models.GameRepository
-	redisRepo      repository.RedisRepository
-	userService    models.UserService
-	tonService     models.TONService
-	commissionRate float64
-	logger         *zap.Logger
+	gameRepo    models.GameRepository
+	redisRepo   repository.RedisRepository
+	userService models.UserService
+	txService   models.TransactionService
+	tonService  models.TONService
+	commission  models.CommissionService
+	membership  models.ChatMembershipChecker
+	notifier    models.UserNotifier
+	pricing     models.PricingService
+	events      models.EventService
+	transactor  models.Transactor
+	botUsername string
+	miniAppName string
+	logger      *zap.Logger
+}
+
+// GameServiceDeps зависимости GameService. GameRepo и Commission обязательны, остальные могут быть nil
+type GameServiceDeps struct {
+	GameRepo    models.GameRepository
+	RedisRepo   repository.RedisRepository
+	UserService models.UserService
+	// TxService используется для возврата остатка пула создателю при закрытии игры
+	TxService  models.TransactionService
+	TONService models.TONService
+	Commission models.CommissionService
+	// Membership проверяет доступ к играм, открытым участникам Telegram-группы
+	Membership models.ChatMembershipChecker
+	// Notifier уведомляет создателя об автоматической приостановке игры
+	Notifier models.UserNotifier
+	// Pricing оценивает коэффициент и подстраивает мультипликатор
+	Pricing models.PricingService
+	// Events - шина доменных событий: активация игры записывается в outbox в одной транзакции со сменой статуса
+	Events models.EventService
+	// Transactor объединяет в одну транзакцию изменения пула игры и баланса создателя
+	Transactor models.Transactor
+	// BotUsername и MiniAppName используются в ссылках-приглашениях в приватные игры
+	BotUsername string
+	MiniAppName string
 }
 
 // NewGameService создает новый экземпляр GameService
-func NewGameService(
-	gameRepo models.GameRepository,
-	redisRepo repository.RedisRepository,
-	userService models.UserService,
-	tonService models.TONService,
-	commissionRate float64,
-) models.GameService {
+func NewGameService(deps GameServiceDeps) models.GameService {
 	return &GameServiceImpl{
-		gameRepo:       gameRepo,
-		redisRepo:      redisRepo,
-		userService:    userService,
-		tonService:     tonService,
-		commissionRate: commissionRate,
-		logger:         logger.GetLogger(zap.String("service", "game")),
+		gameRepo:    deps.GameRepo,
+		redisRepo:   deps.RedisRepo,
+		userService: deps.UserService,
+		txService:   deps.TxService,
+		tonService:  deps.TONService,
+		commission:  deps.Commission,
+		membership:  deps.Membership,
+		notifier:    deps.Notifier,
+		pricing:     deps.Pricing,
+		events:      deps.Events,
+		transactor:  deps.Transactor,
+		botUsername: deps.BotUsername,
+		miniAppName: deps.MiniAppName,
+		logger:      logger.GetLogger(zap.String("service", "game")),
 	}
 }
 
+// gameActivatedEvent формирует событие активации игры
+func gameActivatedEvent(game *models.Game) (*models.DomainEvent, error) {
+	return models.NewDomainEvent(models.EventGameActivated, game.ID, &models.GameActivatedEvent{
+		GameID:    game.ID,
+		CreatorID: game.CreatorID,
+	})
+}
+
 // generateShortID генерирует короткий уникальный ID
 func generateShortID() string {
 	const chars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
@@ -84,8 +131,8 @@ func (s *GameServiceImpl) CreateGame(ctx context.Context, game *models.Game) err
 	game.Word = strings.ToLower(game.Word)
 	game.Length = len([]rune(game.Word))
 
-	if game.Length <= 0 || game.Length > 15 {
-		return errors.New("invalid word length (must be 1-15)")
+	if game.Length < minWordLength || game.Length > maxWordLength {
+		return fmt.Errorf("invalid word length (must be %d-%d)", minWordLength, maxWordLength)
 	}
 	if game.Title == "" {
 		return errors.New("title cannot be empty")
@@ -100,6 +147,14 @@ func (s *GameServiceImpl) CreateGame(ctx context.Context, game *models.Game) err
 		return errors.New("time_limit cannot exceed 60 minutes")
 	}
 
+	// Оценка сложности слова по словарю. Если сложность не выбрана создателем, берём рекомендуемую
+	if difficulty := s.analyzeWord(game.Word); difficulty != nil {
+		game.DifficultyScore = difficulty.Score
+		if game.Difficulty == "" {
+			game.Difficulty = difficulty.Level
+		}
+	}
+
 	// Валидация сложности
 	allowedDifficulties := map[string]bool{"easy": true, "medium": true, "hard": true}
 	if !allowedDifficulties[game.Difficulty] {
@@ -122,6 +177,37 @@ func (s *GameServiceImpl) CreateGame(ctx context.Context, game *models.Game) err
 		return errors.New("reward multiplier must be >= 1.0")
 	}
 
+	// Расписание
+	if err := validateGameSchedule(game, time.Now()); err != nil {
+		return err
+	}
+
+	// Лимиты риска
+	if err := validateRiskLimits(models.GameRiskLimits{
+		MaxDailyLoss:     game.MaxDailyLoss,
+		MaxActiveLobbies: game.MaxActiveLobbies,
+		PoolFloor:        game.PoolFloor,
+	}); err != nil {
+		return err
+	}
+
+	// Границы автоподстройки мультипликатора
+	if err := validateOddsRule(models.OddsAdjustRule{
+		MinMultiplier: game.OddsMinMultiplier,
+		MaxMultiplier: game.OddsMaxMultiplier,
+	}); err != nil {
+		return err
+	}
+
+	// Настройки доступа
+	if err := applyGameAccess(game, models.GameAccess{
+		Visibility:     game.Visibility,
+		AllowedUserIDs: game.AllowedUserIDs,
+		AllowedChatID:  game.AllowedChatID,
+	}); err != nil {
+		return err
+	}
+
 	// Вычисляем минимальный депозит
 	requiredDeposit := game.MaxBet * game.RewardMultiplier
 	if game.DepositAmount < requiredDeposit {
@@ -161,9 +247,21 @@ func (s *GameServiceImpl) CreateGame(ctx context.Context, game *models.Game) err
 		return err
 	}
 
+	game.InviteLink = s.InviteLink(game)
+
+	// Ожидаемый доход создателя при выбранном мультипликаторе
+	game.Odds = s.estimateOdds(ctx, game)
+	if game.Odds != nil && game.Odds.Warning != "" {
+		log.Warn("Game odds are unfavorable for the creator",
+			zap.String("game_id", game.ID.String()),
+			zap.Float64("creator_edge", game.Odds.CreatorEdge),
+			zap.Float64("fair_multiplier", game.Odds.FairMultiplier))
+	}
+
 	log.Info("Game created successfully",
 		zap.String("game_id", game.ID.String()),
-		zap.String("short_id", game.ShortID))
+		zap.String("short_id", game.ShortID),
+		zap.String("visibility", game.Visibility))
 
 	return nil
 }
@@ -273,11 +371,16 @@ func (s *GameServiceImpl) DeleteGame(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("game not found: %w", err)
 	}
 
-	// Нельзя удалить активную игру
-	if game.Status == models.GameStatusActive {
+	// Нельзя удалить активную или закрывающуюся игру
+	if game.Status == models.GameStatusActive || game.Status == models.GameStatusClosing {
 		return errors.New("cannot delete an active game")
 	}
 
+	// Деактивированная игра может ждать завершения активных лобби
+	if game.ReservedAmount > reserveEpsilon {
+		return errors.New("cannot delete a game with active players")
+	}
+
 	// Возвращаем средства создателю
 	var returnAmount float64
 	if game.Currency == models.CurrencyTON {
@@ -310,19 +413,19 @@ func (s *GameServiceImpl) DeleteGame(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// GetActiveGames получает список активных игр
-func (s *GameServiceImpl) GetActiveGames(ctx context.Context, limit, offset int) ([]*models.Game, error) {
+// GetActiveGames получает страницу активных публичных игр, начиная с новых
+func (s *GameServiceImpl) GetActiveGames(ctx context.Context, limit int, cursor string) (*models.GamePage, error) {
 	log := s.logger.With(zap.String("method", "GetActiveGames"))
 	log.Debug("Getting active games")
 
-	games, err := s.gameRepo.GetActive(ctx, limit, offset)
+	page, err := s.SearchGames(ctx, models.GameSearchFilter{Sort: models.GameSortNewest}, limit, cursor)
 	if err != nil {
 		log.Error("Failed to get active games", zap.Error(err))
 		return nil, err
 	}
 
-	log.Debug("Active games retrieved", zap.Int("count", len(games)))
-	return games, nil
+	log.Debug("Active games retrieved", zap.Int("count", len(page.Games)))
+	return page, nil
 }
 
 // GetPendingGames получает список игр, ожидающих депозита
@@ -330,9 +433,37 @@ func (s *GameServiceImpl) GetPendingGames(ctx context.Context, limit, offset int
 	return s.gameRepo.GetPending(ctx, limit, offset)
 }
 
-// SearchGames ищет игры по параметрам
-func (s *GameServiceImpl) SearchGames(ctx context.Context, minBet, maxBet float64, difficulty string, limit, offset int) ([]*models.Game, error) {
-	return s.gameRepo.SearchGames(ctx, minBet, maxBet, difficulty, limit, offset)
+// SearchGames ищет публичные игры по параметрам и возвращает страницу в порядке filter.Sort.
+// Курсор следующей страницы действителен только для той же сортировки
+func (s *GameServiceImpl) SearchGames(ctx context.Context, filter models.GameSearchFilter, limit int, cursor string) (*models.GamePage, error) {
+	if filter.Sort == "" {
+		filter.Sort = models.GameSortNewest
+	}
+	if !models.IsValidGameSort(filter.Sort) {
+		return nil, errors.New("invalid sort: must be newest, pool, popular, win_rate or reputation")
+	}
+
+	after, err := models.DecodePageCursor(cursor, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// Запрашиваем на одну игру больше, чтобы узнать, есть ли следующая страница
+	limit = pageLimit(limit)
+	games, err := s.gameRepo.SearchGames(ctx, filter, limit+1, after)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.GamePage{Games: games}
+	if len(games) > limit {
+		page.Games = games[:limit]
+		page.NextCursor = page.Games[limit-1].Cursor(filter.Sort).Encode()
+	}
+	if page.Games == nil {
+		page.Games = []*models.Game{}
+	}
+	return page, nil
 }
 
 // GetGameStats получает статистику игры
@@ -357,14 +488,7 @@ func (s *GameServiceImpl) AddToRewardPool(ctx context.Context, gameID uuid.UUID,
 	}
 
 	// Добавляем в пул
-	if game.Currency == models.CurrencyTON {
-		game.RewardPoolTon += amount
-	} else {
-		game.RewardPoolUsdt += amount
-	}
-	game.UpdatedAt = time.Now()
-
-	if err := s.gameRepo.Update(ctx, game); err != nil {
+	if err := s.gameRepo.IncrementRewardPool(ctx, game.ID, amount); err != nil {
 		return err
 	}
 
@@ -385,6 +509,12 @@ func (s *GameServiceImpl) ActivateGame(ctx context.Context, gameID uuid.UUID) er
 	if game.Status == models.GameStatusActive {
 		return errors.New("game is already active")
 	}
+	if game.Status == models.GameStatusClosing || game.Status == models.GameStatusClosed {
+		return errors.New("game is closed")
+	}
+	if game.IsEnded(time.Now()) {
+		return errors.New("game has already ended")
+	}
 
 	// Проверяем достаточность средств
 	requiredDeposit := game.GetRequiredDeposit()
@@ -400,13 +530,27 @@ func (s *GameServiceImpl) ActivateGame(ctx context.Context, gameID uuid.UUID) er
 			requiredDeposit, game.Currency, currentPool)
 	}
 
-	err = s.gameRepo.UpdateStatus(ctx, gameID, models.GameStatusActive)
+	// До времени начала игра ждёт в статусе scheduled, активирует её планировщик
+	status := game.ActivationStatus(time.Now())
+	err = withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+		if err := s.gameRepo.UpdateStatus(ctx, gameID, status); err != nil {
+			return nil, err
+		}
+		if status == models.GameStatusScheduled {
+			return nil, nil
+		}
+		return gameActivatedEvent(game)
+	})
 	if err != nil {
 		return err
 	}
 
+	if status == models.GameStatusScheduled {
+		log.Info("Game scheduled for activation", zap.Timep("starts_at", game.StartsAt))
+		return nil
+	}
+
 	log.Info("Game activated successfully")
-	metrics.IncrementGameStart()
 	return nil
 }
 
@@ -424,11 +568,8 @@ func (s *GameServiceImpl) DeactivateGame(ctx context.Context, gameID uuid.UUID)
 		return errors.New("game is not active")
 	}
 
-	// Проверяем, нет ли зарезервированных средств (активных игроков)
-	if game.ReservedAmount > 0 {
-		return errors.New("cannot deactivate game with active players")
-	}
-
+	// Активные лобби доигрываются: их выплаты уже зарезервированы в пуле,
+	// деактивация только прекращает приём новых ставок
 	err = s.gameRepo.UpdateStatus(ctx, gameID, models.GameStatusInactive)
 	if err != nil {
 		return err
@@ -538,8 +679,8 @@ func (s *GameServiceImpl) CalculateReward(bet float64, multiplier float64, tries
 
 	grossReward := baseReward * triesBonus
 
-	// Вычитаем комиссию 5%
-	netReward := grossReward * (1 - s.commissionRate)
+	// Вычитаем комиссию по умолчанию
+	netReward := grossReward * (1 - s.commission.GetCommissionRate())
 
 	return netReward
 }
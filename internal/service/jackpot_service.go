@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/TakuroBreath/wordle/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// jackpotCurrencies валюты, в которых всегда показывается джекпот
+var jackpotCurrencies = []string{models.CurrencyTON, models.CurrencyUSDT}
+
+// JackpotServiceImpl представляет собой реализацию JackpotService
+type JackpotServiceImpl struct {
+	jackpotRepo        models.JackpotRepository
+	transactionService models.TransactionService
+	rule               models.JackpotRule
+	transactor         models.Transactor
+	logger             *zap.Logger
+}
+
+// NewJackpotService создает новый экземпляр JackpotService.
+// По умолчанию джекпот выигрывает игрок, угадавший слово с первой попытки
+func NewJackpotService(
+	jackpotRepo models.JackpotRepository,
+	transactionService models.TransactionService,
+	rule models.JackpotRule,
+	transactor models.Transactor,
+) models.JackpotService {
+	if rule.MaxTries <= 0 {
+		rule.MaxTries = 1
+	}
+	return &JackpotServiceImpl{
+		jackpotRepo:        jackpotRepo,
+		transactionService: transactionService,
+		rule:               rule,
+		transactor:         transactor,
+		logger:             logger.GetLogger(zap.String("service", "jackpot")),
+	}
+}
+
+// Contribute отчисляет долю комиссии проигранного лобби в джекпот.
+// Повторное отчисление по тому же лобби не производится
+func (s *JackpotServiceImpl) Contribute(ctx context.Context, lobby *models.Lobby, commission float64) (float64, error) {
+	if !s.rule.Enabled() || commission <= 0 {
+		return 0, nil
+	}
+
+	entry := &models.JackpotEntry{
+		Currency: lobby.Currency,
+		Amount:   commission * s.rule.CommissionShare,
+		LobbyID:  lobby.ID,
+		GameID:   lobby.GameID,
+		UserID:   lobby.UserID,
+	}
+	applied, err := s.jackpotRepo.Contribute(ctx, entry)
+	if err != nil {
+		return 0, fmt.Errorf("failed to contribute to jackpot: %w", err)
+	}
+	if !applied {
+		return 0, nil
+	}
+
+	metrics.SetJackpotAmount(entry.Currency, entry.BalanceAfter)
+
+	return entry.Amount, nil
+}
+
+// AwardJackpot выплачивает весь джекпот в валюте лобби, если лобби выполнило условие розыгрыша.
+// Джекпот забирается из пула и зачисляется победителю в одной транзакции,
+// поэтому по одному лобби он выплачивается не более одного раза и не теряется при ошибке зачисления
+func (s *JackpotServiceImpl) AwardJackpot(ctx context.Context, lobby *models.Lobby, finalStatus string) (*models.JackpotEntry, error) {
+	if !s.rule.Wins(lobby, finalStatus) {
+		return nil, nil
+	}
+
+	log := s.logger.With(zap.String("method", "AwardJackpot"),
+		zap.String("lobby_id", lobby.ID.String()),
+		zap.Uint64("user_id", lobby.UserID))
+
+	entry := &models.JackpotEntry{
+		Currency: lobby.Currency,
+		LobbyID:  lobby.ID,
+		GameID:   lobby.GameID,
+		UserID:   lobby.UserID,
+	}
+	var won bool
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		var err error
+		won, err = s.jackpotRepo.Payout(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("failed to claim jackpot: %w", err)
+		}
+		if !won {
+			return nil
+		}
+
+		if err := s.transactionService.PayJackpot(ctx, lobby.UserID, entry.Amount, entry.Currency, lobby.GameID); err != nil {
+			return fmt.Errorf("failed to pay jackpot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !won {
+		return nil, nil
+	}
+
+	metrics.SetJackpotAmount(entry.Currency, entry.BalanceAfter)
+
+	log.Info("Jackpot won",
+		zap.Float64("amount", entry.Amount),
+		zap.String("currency", entry.Currency))
+
+	return entry, nil
+}
+
+// GetJackpots возвращает текущие джекпоты во всех валютах
+func (s *JackpotServiceImpl) GetJackpots(ctx context.Context) ([]*models.Jackpot, error) {
+	stored, err := s.jackpotRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byCurrency := make(map[string]*models.Jackpot, len(stored))
+	for _, jackpot := range stored {
+		byCurrency[jackpot.Currency] = jackpot
+	}
+
+	jackpots := make([]*models.Jackpot, 0, len(jackpotCurrencies))
+	for _, currency := range jackpotCurrencies {
+		jackpot, ok := byCurrency[currency]
+		if !ok {
+			jackpot = &models.Jackpot{Currency: currency}
+		}
+		metrics.SetJackpotAmount(currency, jackpot.Amount)
+		jackpots = append(jackpots, jackpot)
+	}
+
+	return jackpots, nil
+}
+
+// GetHistory возвращает журнал отчислений и выплат джекпота
+func (s *JackpotServiceImpl) GetHistory(ctx context.Context, currency string, limit, offset int) ([]*models.JackpotEntry, error) {
+	if currency != "" && currency != models.CurrencyTON && currency != models.CurrencyUSDT {
+		return nil, errors.New("invalid currency")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.jackpotRepo.GetEntries(ctx, currency, limit, offset)
+}
+
+// GetRule возвращает условия пополнения и розыгрыша джекпота
+func (s *JackpotServiceImpl) GetRule() models.JackpotRule {
+	return s.rule
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestJackpotRule_Wins(t *testing.T) {
+	rule := models.JackpotRule{CommissionShare: 0.5, MaxTries: 1, MinBet: 1}
+	tests := []struct {
+		name   string
+		rule   models.JackpotRule
+		lobby  models.Lobby
+		status string
+		want   bool
+	}{
+		{name: "угадал с первой попытки", rule: rule, lobby: models.Lobby{BetAmount: 1, TriesUsed: 1}, status: models.LobbyStatusSuccess, want: true},
+		{name: "со второй попытки", rule: rule, lobby: models.Lobby{BetAmount: 1, TriesUsed: 2}, status: models.LobbyStatusSuccess},
+		{name: "ставка ниже минимальной", rule: rule, lobby: models.Lobby{BetAmount: 0.5, TriesUsed: 1}, status: models.LobbyStatusSuccess},
+		{name: "проигрыш", rule: rule, lobby: models.Lobby{BetAmount: 1, TriesUsed: 1}, status: models.LobbyStatusFailedTries},
+		{name: "джекпот выключен", lobby: models.Lobby{BetAmount: 1, TriesUsed: 1}, status: models.LobbyStatusSuccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Wins(&tt.lobby, tt.status); got != tt.want {
+				t.Errorf("Wins() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLobbyService_Jackpot(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+	txService := NewTransactionServiceImpl(txRepo, userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	historyService := NewHistoryService(mocks.NewMockHistoryRepository(), gameRepo, userRepo, lobbyRepo)
+	jackpotService := NewJackpotService(mocks.NewMockJackpotRepository(), txService, models.JackpotRule{CommissionShare: 0.5}, mocks.NewMockTransactor())
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        mocks.NewMockAttemptRepository(),
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово", "слава"}),
+		Jackpot:            jackpotService,
+	})
+
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 100, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	// Два проигранных лобби пополняют джекпот половиной комиссии
+	for userID := uint64(1); userID <= 2; userID++ {
+		_ = userRepo.Create(ctx, &models.User{TelegramID: userID, Username: "loser", BalanceTon: 2})
+		lobby := &models.Lobby{GameID: game.ID, UserID: userID, BetAmount: 2}
+		if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+			t.Fatalf("CreateLobby() error = %v", err)
+		}
+		if err := lobbyService.FinishLobby(ctx, lobby.ID, false); err != nil {
+			t.Fatalf("FinishLobby() error = %v", err)
+		}
+	}
+
+	jackpots, err := jackpotService.GetJackpots(ctx)
+	if err != nil {
+		t.Fatalf("GetJackpots() error = %v", err)
+	}
+	if len(jackpots) != 2 || jackpots[0].Currency != models.CurrencyTON || math.Abs(jackpots[0].Amount-0.1) > 1e-9 {
+		t.Fatalf("jackpots = %+v, want 0.1 TON", jackpots)
+	}
+	if jackpots[1].Currency != models.CurrencyUSDT || jackpots[1].Amount != 0 {
+		t.Errorf("USDT jackpot = %+v, want empty", jackpots[1])
+	}
+
+	// Выигрыш тоже пополняет джекпот половиной комиссии, удержанной из награды
+	winContribution := func(triesUsed int) float64 {
+		return (grossReward(1, 2, triesUsed, 6) - lobbyService.CalculateReward(1, 2, triesUsed, 6)) * 0.5
+	}
+
+	// Игрок со второй попытки джекпот не выигрывает
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 3, Username: "second", BalanceTon: 1})
+	second := &models.Lobby{GameID: game.ID, UserID: 3, BetAmount: 1}
+	if err := lobbyService.CreateLobby(ctx, second); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	for _, word := range []string{"слава", "слово"} {
+		if _, err := lobbyService.ProcessAttempt(ctx, second.ID, word); err != nil {
+			t.Fatalf("ProcessAttempt() error = %v", err)
+		}
+	}
+	if jackpot, _ := jackpotService.GetJackpots(ctx); math.Abs(jackpot[0].Amount-0.1-winContribution(2)) > 1e-9 {
+		t.Errorf("jackpot after second-try win = %v, want %v", jackpot[0].Amount, 0.1+winContribution(2))
+	}
+
+	// Игрок с первой попытки забирает весь джекпот вдобавок к награде
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 4, Username: "winner", BalanceTon: 1})
+	winner := &models.Lobby{GameID: game.ID, UserID: 4, BetAmount: 1}
+	if err := lobbyService.CreateLobby(ctx, winner); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	if _, err := lobbyService.ProcessAttempt(ctx, winner.ID, "слово"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+	reward := lobbyService.CalculateReward(1, 2, 1, 6)
+	jackpot := 0.1 + winContribution(2) + winContribution(1)
+	assertTonBalance(t, userRepo, 4, reward+jackpot)
+
+	// Повторный розыгрыш по тому же лобби ничего не выплачивает
+	finished, _ := lobbyRepo.GetByID(ctx, winner.ID)
+	entry, err := jackpotService.AwardJackpot(ctx, finished, models.LobbyStatusSuccess)
+	if err != nil || entry != nil {
+		t.Errorf("second AwardJackpot() = %+v, %v, want nil", entry, err)
+	}
+	assertTonBalance(t, userRepo, 4, reward+jackpot)
+
+	txs, _ := txRepo.GetByType(ctx, models.TransactionTypeJackpot, 10, 0)
+	if len(txs) != 1 || txs[0].UserID != 4 || math.Abs(txs[0].Amount-jackpot) > 1e-9 {
+		t.Errorf("jackpot transactions = %+v, want one %v TON payout to user 4", txs, jackpot)
+	}
+
+	history, err := jackpotService.GetHistory(ctx, models.CurrencyTON, 10, 0)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(history) != 5 || history[0].Type != models.JackpotEntryPayout || history[0].BalanceAfter != 0 {
+		t.Errorf("history = %+v, want four contributions and a payout", history)
+	}
+	if _, err := jackpotService.GetHistory(ctx, "BTC", 10, 0); err == nil {
+		t.Error("GetHistory() with unknown currency should fail")
+	}
+}
@@ -15,21 +15,39 @@ import (
 
 // JobServiceImpl представляет собой реализацию models.JobService
 type JobServiceImpl struct {
-	lobbyService       models.LobbyService
-	transactionService models.TransactionService
-	gameService        models.GameService
-	userService        models.UserService
-	blockchainProvider blockchain.BlockchainProvider
-	tonapiClient       *tonapi.Client
+	lobbyService        models.LobbyService
+	transactionService  models.TransactionService
+	gameService         models.GameService
+	userService         models.UserService
+	duelService         models.DuelService
+	referralService     models.ReferralService
+	leaderboardService  models.LeaderboardService
+	reputationService   models.ReputationService
+	notificationService models.NotificationService
+	eventService        models.EventService
+	webhookService      models.WebhookService
+	blockchainProvider  blockchain.BlockchainProvider
+	tonapiClient        *tonapi.Client
+}
+
+// JobServiceDeps сервисы, задачи которых выполняет JobService. Лобби, транзакции, игры и пользователи обязательны,
+// задачи остальных сервисов пропускаются, если сервис nil
+type JobServiceDeps struct {
+	LobbyService        models.LobbyService
+	TransactionService  models.TransactionService
+	GameService         models.GameService
+	UserService         models.UserService
+	DuelService         models.DuelService
+	ReferralService     models.ReferralService
+	LeaderboardService  models.LeaderboardService
+	ReputationService   models.ReputationService
+	NotificationService models.NotificationService
+	EventService        models.EventService
+	WebhookService      models.WebhookService
 }
 
 // NewJobService создает новый экземпляр models.JobService
-func NewJobService(
-	lobbyService models.LobbyService,
-	transactionService models.TransactionService,
-	gameService models.GameService,
-	userService models.UserService,
-) models.JobService {
+func NewJobService(deps JobServiceDeps) models.JobService {
 	token := os.Getenv("TONAPI_KEY")
 
 	var client *tonapi.Client
@@ -44,23 +62,24 @@ func NewJobService(
 	}
 
 	return &JobServiceImpl{
-		lobbyService:       lobbyService,
-		transactionService: transactionService,
-		gameService:        gameService,
-		userService:        userService,
-		tonapiClient:       client,
+		lobbyService:        deps.LobbyService,
+		transactionService:  deps.TransactionService,
+		gameService:         deps.GameService,
+		userService:         deps.UserService,
+		duelService:         deps.DuelService,
+		referralService:     deps.ReferralService,
+		leaderboardService:  deps.LeaderboardService,
+		reputationService:   deps.ReputationService,
+		notificationService: deps.NotificationService,
+		eventService:        deps.EventService,
+		webhookService:      deps.WebhookService,
+		tonapiClient:        client,
 	}
 }
 
 // NewJobServiceWithBlockchain создает JobService с поддержкой блокчейн провайдера
-func NewJobServiceWithBlockchain(
-	lobbyService models.LobbyService,
-	transactionService models.TransactionService,
-	gameService models.GameService,
-	userService models.UserService,
-	blockchainProvider blockchain.BlockchainProvider,
-) models.JobService {
-	service := NewJobService(lobbyService, transactionService, gameService, userService).(*JobServiceImpl)
+func NewJobServiceWithBlockchain(deps JobServiceDeps, blockchainProvider blockchain.BlockchainProvider) models.JobService {
+	service := NewJobService(deps).(*JobServiceImpl)
 	service.blockchainProvider = blockchainProvider
 	return service
 }
@@ -88,6 +107,80 @@ func (s *JobServiceImpl) ProcessExpiredLobbies(ctx context.Context) error {
 	return nil
 }
 
+// ProcessExpiredDuels обрабатывает непринятые вызовы и дуэли с истёкшим временем
+func (s *JobServiceImpl) ProcessExpiredDuels(ctx context.Context) error {
+	if s.duelService == nil {
+		return nil
+	}
+	return s.duelService.ProcessExpiredDuels(ctx)
+}
+
+// ProcessScheduledGames активирует и закрывает игры по расписанию
+func (s *JobServiceImpl) ProcessScheduledGames(ctx context.Context) error {
+	return s.gameService.ProcessScheduledGames(ctx)
+}
+
+// ProcessAutoTopUps пополняет пулы игр, опустившиеся ниже порога автопополнения
+func (s *JobServiceImpl) ProcessAutoTopUps(ctx context.Context) error {
+	return s.gameService.ProcessAutoTopUps(ctx)
+}
+
+// ProcessOddsAdjustments подстраивает мультипликаторы игр под фактические результаты
+func (s *JobServiceImpl) ProcessOddsAdjustments(ctx context.Context) error {
+	return s.gameService.ProcessOddsAdjustments(ctx)
+}
+
+// ProcessReferralPayouts выплачивает реферерам накопленную долю комиссии
+func (s *JobServiceImpl) ProcessReferralPayouts(ctx context.Context) error {
+	if s.referralService == nil {
+		return nil
+	}
+	return s.referralService.ProcessPayouts(ctx)
+}
+
+// ProcessLeaderboardPayouts выплачивает призы победителям завершившихся сезонов таблиц лидеров
+func (s *JobServiceImpl) ProcessLeaderboardPayouts(ctx context.Context) error {
+	if s.leaderboardService == nil {
+		return nil
+	}
+	return s.leaderboardService.ProcessSeasonPayouts(ctx)
+}
+
+// ProcessReputationRefresh пересчитывает репутацию создателей с новыми завершёнными лобби и возвратами ставок
+func (s *JobServiceImpl) ProcessReputationRefresh(ctx context.Context) error {
+	if s.reputationService == nil {
+		return nil
+	}
+	return s.reputationService.ProcessStaleReputations(ctx)
+}
+
+// ProcessDomainEvents доставляет доменные события из outbox подписчикам
+func (s *JobServiceImpl) ProcessDomainEvents(ctx context.Context) error {
+	if s.eventService == nil {
+		return nil
+	}
+	return s.eventService.ProcessEvents(ctx)
+}
+
+// ProcessWebhooks отправляет накопившиеся доставки исходящих webhook
+func (s *JobServiceImpl) ProcessWebhooks(ctx context.Context) error {
+	if s.webhookService == nil {
+		return nil
+	}
+	return s.webhookService.ProcessDeliveries(ctx)
+}
+
+// ProcessNotifications ставит в очередь напоминания об истекающих лобби и отправляет накопившиеся уведомления
+func (s *JobServiceImpl) ProcessNotifications(ctx context.Context) error {
+	if s.notificationService == nil {
+		return nil
+	}
+	if err := s.notificationService.ProcessExpiringLobbies(ctx); err != nil {
+		return err
+	}
+	return s.notificationService.ProcessOutbox(ctx)
+}
+
 // ProcessPendingTransactions обрабатывает отложенные транзакции
 func (s *JobServiceImpl) ProcessPendingTransactions(ctx context.Context) error {
 	// Обрабатываем отложенные транзакции через blockchain provider
@@ -179,6 +272,36 @@ func (s *JobServiceImpl) StartJobScheduler(ctx context.Context, lobbyCheckInterv
 				if err != nil {
 					fmt.Printf("ERROR: Failed to process expired lobbies: %v\n", err)
 				}
+				if err := s.ProcessExpiredDuels(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process expired duels: %v\n", err)
+				}
+				if err := s.ProcessScheduledGames(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process scheduled games: %v\n", err)
+				}
+				if err := s.ProcessAutoTopUps(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process pool auto top-ups: %v\n", err)
+				}
+				if err := s.ProcessOddsAdjustments(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process odds adjustments: %v\n", err)
+				}
+				if err := s.ProcessReferralPayouts(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process referral payouts: %v\n", err)
+				}
+				if err := s.ProcessLeaderboardPayouts(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process leaderboard payouts: %v\n", err)
+				}
+				if err := s.ProcessReputationRefresh(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to refresh creator reputations: %v\n", err)
+				}
+				if err := s.ProcessDomainEvents(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process domain events: %v\n", err)
+				}
+				if err := s.ProcessNotifications(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process notifications: %v\n", err)
+				}
+				if err := s.ProcessWebhooks(ctx); err != nil {
+					fmt.Printf("ERROR: Failed to process webhooks: %v\n", err)
+				}
 			case <-done:
 				return
 			case <-ctx.Done():
@@ -240,6 +363,56 @@ func (s *JobServiceImpl) RunOnce(ctx context.Context) error {
 		return fmt.Errorf("failed to process expired lobbies: %w", err)
 	}
 
+	// Обрабатываем истекшие дуэли
+	if err := s.ProcessExpiredDuels(ctx); err != nil {
+		return fmt.Errorf("failed to process expired duels: %w", err)
+	}
+
+	// Активируем и закрываем игры по расписанию
+	if err := s.ProcessScheduledGames(ctx); err != nil {
+		return fmt.Errorf("failed to process scheduled games: %w", err)
+	}
+
+	// Пополняем пулы игр по правилам автопополнения
+	if err := s.ProcessAutoTopUps(ctx); err != nil {
+		return fmt.Errorf("failed to process pool auto top-ups: %w", err)
+	}
+
+	// Подстраиваем мультипликаторы игр под фактические результаты
+	if err := s.ProcessOddsAdjustments(ctx); err != nil {
+		return fmt.Errorf("failed to process odds adjustments: %w", err)
+	}
+
+	// Выплачиваем реферерам долю комиссии приглашённых пользователей
+	if err := s.ProcessReferralPayouts(ctx); err != nil {
+		return fmt.Errorf("failed to process referral payouts: %w", err)
+	}
+
+	// Выплачиваем призы сезонов таблиц лидеров
+	if err := s.ProcessLeaderboardPayouts(ctx); err != nil {
+		return fmt.Errorf("failed to process leaderboard payouts: %w", err)
+	}
+
+	// Пересчитываем репутацию создателей
+	if err := s.ProcessReputationRefresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh creator reputations: %w", err)
+	}
+
+	// Доставляем доменные события подписчикам (до отправки уведомлений, которые они ставят в очередь)
+	if err := s.ProcessDomainEvents(ctx); err != nil {
+		return fmt.Errorf("failed to process domain events: %w", err)
+	}
+
+	// Отправляем уведомления бота
+	if err := s.ProcessNotifications(ctx); err != nil {
+		return fmt.Errorf("failed to process notifications: %w", err)
+	}
+
+	// Отправляем исходящие webhook
+	if err := s.ProcessWebhooks(ctx); err != nil {
+		return fmt.Errorf("failed to process webhooks: %w", err)
+	}
+
 	// Обрабатываем отложенные транзакции
 	if err := s.ProcessPendingTransactions(ctx); err != nil {
 		return fmt.Errorf("failed to process pending transactions: %w", err)
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"go.uber.org/zap"
+)
+
+// Параметры таблиц лидеров
+const (
+	leaderboardDefaultLimit = 50
+	leaderboardMaxLimit     = 100
+)
+
+// LeaderboardServiceImpl представляет собой реализацию LeaderboardService
+type LeaderboardServiceImpl struct {
+	leaderboardRepo    models.LeaderboardRepository
+	transactionService models.TransactionService
+	rule               models.LeaderboardRule
+	transactor         models.Transactor
+	logger             *zap.Logger
+}
+
+// NewLeaderboardService создает новый экземпляр LeaderboardService.
+// Призы сезонов из rule.Seasons выплачиваются платформой после окончания периода
+func NewLeaderboardService(
+	leaderboardRepo models.LeaderboardRepository,
+	transactionService models.TransactionService,
+	rule models.LeaderboardRule,
+	transactor models.Transactor,
+) models.LeaderboardService {
+	if rule.MinGames <= 0 {
+		rule.MinGames = models.LeaderboardDefaultMinGames
+	}
+	return &LeaderboardServiceImpl{
+		leaderboardRepo:    leaderboardRepo,
+		transactionService: transactionService,
+		rule:               rule,
+		transactor:         transactor,
+		logger:             logger.GetLogger(zap.String("service", "leaderboard")),
+	}
+}
+
+// GetLeaderboard возвращает таблицу лидеров за текущий период.
+// По умолчанию - недельная таблица по прибыли в TON
+func (s *LeaderboardServiceImpl) GetLeaderboard(ctx context.Context, period, metric, currency string, limit int) (*models.Leaderboard, error) {
+	if period == "" {
+		period = models.LeaderboardPeriodWeekly
+	}
+	if metric == "" {
+		metric = models.LeaderboardMetricProfit
+	}
+	if currency == "" {
+		currency = models.CurrencyTON
+	}
+	if !models.IsValidLeaderboardPeriod(period) {
+		return nil, fmt.Errorf("invalid leaderboard period: %s", period)
+	}
+	if !models.IsValidLeaderboardMetric(metric) {
+		return nil, fmt.Errorf("invalid leaderboard metric: %s", metric)
+	}
+	if currency != models.CurrencyTON && currency != models.CurrencyUSDT {
+		return nil, fmt.Errorf("invalid currency: %s", currency)
+	}
+	if limit <= 0 || limit > leaderboardMaxLimit {
+		limit = leaderboardDefaultLimit
+	}
+
+	from, to := models.LeaderboardWindow(period, time.Now())
+	board := &models.Leaderboard{
+		Period:   period,
+		Metric:   metric,
+		Currency: currency,
+		To:       to,
+		MinGames: s.minGames(metric, 0),
+	}
+	if !from.IsZero() {
+		board.From = &from
+	}
+
+	entries, err := s.entries(ctx, metric, currency, from, to, board.MinGames, limit)
+	if err != nil {
+		return nil, err
+	}
+	board.Entries = entries
+
+	return board, nil
+}
+
+// entries возвращает строки таблицы лидеров с местами и значением метрики
+func (s *LeaderboardServiceImpl) entries(ctx context.Context, metric, currency string, from, to time.Time, minGames, limit int) ([]*models.LeaderboardEntry, error) {
+	entries, err := s.leaderboardRepo.GetEntries(ctx, &models.LeaderboardQuery{
+		Metric:   metric,
+		Currency: currency,
+		From:     from,
+		To:       to,
+		MinGames: minGames,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*models.LeaderboardEntry{}
+	}
+
+	for i, entry := range entries {
+		entry.Rank = i + 1
+		if entry.Games > 0 {
+			entry.WinRate = float64(entry.Wins) / float64(entry.Games)
+		}
+		switch metric {
+		case models.LeaderboardMetricProfit:
+			entry.Value = entry.Profit
+		case models.LeaderboardMetricWinRate:
+			entry.Value = entry.WinRate
+		case models.LeaderboardMetricEfficiency:
+			entry.Value = entry.AvgTries
+		}
+	}
+
+	return entries, nil
+}
+
+// minGames возвращает минимум сыгранных лобби для метрики.
+// Для прибыли достаточно одного лобби, для доли побед и эффективности - порога из настроек
+func (s *LeaderboardServiceImpl) minGames(metric string, override int) int {
+	if override > 0 {
+		return override
+	}
+	if metric == models.LeaderboardMetricProfit {
+		return 1
+	}
+	return s.rule.MinGames
+}
+
+// SetOptOut скрывает пользователя из публичных рейтингов или возвращает его в них
+func (s *LeaderboardServiceImpl) SetOptOut(ctx context.Context, userID uint64, optOut bool) error {
+	if userID == 0 {
+		return errors.New("user ID cannot be zero")
+	}
+	return s.leaderboardRepo.SetOptOut(ctx, userID, optOut)
+}
+
+// IsOptedOut проверяет, скрыт ли пользователь из публичных рейтингов
+func (s *LeaderboardServiceImpl) IsOptedOut(ctx context.Context, userID uint64) (bool, error) {
+	return s.leaderboardRepo.IsOptedOut(ctx, userID)
+}
+
+// ProcessSeasonPayouts выплачивает призы победителям завершившихся периодов.
+// Каждое место сезона оплачивается один раз, поэтому метод можно вызывать при каждом проходе планировщика
+func (s *LeaderboardServiceImpl) ProcessSeasonPayouts(ctx context.Context) error {
+	log := s.logger.With(zap.String("method", "ProcessSeasonPayouts"))
+
+	for _, season := range s.rule.Seasons {
+		if err := validateLeaderboardSeason(season); err != nil {
+			log.Error("Skipping invalid leaderboard season", zap.Error(err))
+			continue
+		}
+
+		from, to := models.PreviousLeaderboardWindow(season.Period, time.Now())
+		entries, err := s.entries(ctx, season.Metric, season.Currency, from, to,
+			s.minGames(season.Metric, season.MinGames), len(season.Prizes))
+		if err != nil {
+			log.Error("Failed to get season leaderboard", zap.String("period", season.Period), zap.Error(err))
+			continue
+		}
+
+		for _, entry := range entries {
+			amount := season.Prizes[entry.Rank-1]
+			if amount <= 0 {
+				continue
+			}
+
+			payout := &models.LeaderboardPayout{
+				Period:      season.Period,
+				Metric:      season.Metric,
+				Currency:    season.Currency,
+				PeriodStart: from,
+				Rank:        entry.Rank,
+				UserID:      entry.UserID,
+				Amount:      amount,
+			}
+			description := fmt.Sprintf("Leaderboard prize: %s %s, place %d", season.Period, season.Metric, entry.Rank)
+			paid, err := s.payPrize(ctx, payout, description)
+			if err != nil {
+				log.Error("Failed to pay leaderboard prize",
+					zap.Uint64("user_id", entry.UserID),
+					zap.Float64("amount", amount),
+					zap.String("currency", season.Currency),
+					zap.Error(err))
+				continue
+			}
+			if !paid {
+				continue
+			}
+
+			log.Info("Leaderboard prize paid",
+				zap.String("period", season.Period),
+				zap.String("metric", season.Metric),
+				zap.Int("rank", entry.Rank),
+				zap.Uint64("user_id", entry.UserID),
+				zap.Float64("amount", amount),
+				zap.String("currency", season.Currency))
+		}
+	}
+
+	return nil
+}
+
+// payPrize записывает приз места и зачисляет его победителю в одной транзакции.
+// Возвращает false, если приз за это место уже выплачен. При ошибке зачисления приз не записывается
+// и будет выплачен на следующем проходе
+func (s *LeaderboardServiceImpl) payPrize(ctx context.Context, payout *models.LeaderboardPayout, description string) (bool, error) {
+	var created bool
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		var err error
+		created, err = s.leaderboardRepo.CreatePayout(ctx, payout)
+		if err != nil {
+			return fmt.Errorf("failed to record leaderboard prize: %w", err)
+		}
+		if !created {
+			return nil
+		}
+		return s.transactionService.PayLeaderboardPrize(ctx, payout.UserID, payout.Amount, payout.Currency, description)
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// GetPayouts возвращает выплаченные призы сезонов
+func (s *LeaderboardServiceImpl) GetPayouts(ctx context.Context, limit, offset int) ([]*models.LeaderboardPayout, error) {
+	if limit <= 0 || limit > leaderboardMaxLimit {
+		limit = leaderboardDefaultLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.leaderboardRepo.GetPayouts(ctx, limit, offset)
+}
+
+// validateLeaderboardSeason проверяет условия сезона с призами
+func validateLeaderboardSeason(season models.LeaderboardSeason) error {
+	if season.Period == models.LeaderboardPeriodAllTime || !models.IsValidLeaderboardPeriod(season.Period) {
+		return fmt.Errorf("invalid season period: %s", season.Period)
+	}
+	if !models.IsValidLeaderboardMetric(season.Metric) {
+		return fmt.Errorf("invalid season metric: %s", season.Metric)
+	}
+	if season.Currency != models.CurrencyTON && season.Currency != models.CurrencyUSDT {
+		return fmt.Errorf("invalid season currency: %s", season.Currency)
+	}
+	if len(season.Prizes) == 0 {
+		return errors.New("season has no prizes")
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func setupLeaderboardService(t *testing.T, rule models.LeaderboardRule) (*mocks.MockUserRepository, *mocks.MockHistoryRepository, models.LeaderboardService) {
+	t.Helper()
+	userRepo := mocks.NewMockUserRepository()
+	historyRepo := mocks.NewMockHistoryRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	leaderboard := NewLeaderboardService(mocks.NewMockLeaderboardRepository(historyRepo, userRepo), txService, rule, mocks.NewMockTransactor())
+
+	ctx := context.Background()
+	for id, name := range map[uint64]string{1: "grinder", 2: "sniper", 3: "whale"} {
+		_ = userRepo.Create(ctx, &models.User{TelegramID: id, Username: name})
+	}
+	return userRepo, historyRepo, leaderboard
+}
+
+// addResults добавляет в историю результаты лобби игрока: wins побед за tries попыток и losses поражений
+func addResults(historyRepo *mocks.MockHistoryRepository, userID uint64, at time.Time, bet, reward float64, tries, wins, losses int) {
+	ctx := context.Background()
+	for i := 0; i < wins; i++ {
+		_ = historyRepo.Create(ctx, &models.History{UserID: userID, GameID: uuid.New(), LobbyID: uuid.New(),
+			Status: models.HistoryStatusPlayerWin, BetAmount: bet, Reward: reward, Currency: models.CurrencyTON,
+			TriesUsed: tries, CreatedAt: at})
+	}
+	for i := 0; i < losses; i++ {
+		_ = historyRepo.Create(ctx, &models.History{UserID: userID, GameID: uuid.New(), LobbyID: uuid.New(),
+			Status: models.HistoryStatusCreatorWin, BetAmount: bet, Currency: models.CurrencyTON,
+			TriesUsed: 6, CreatedAt: at})
+	}
+}
+
+func TestLeaderboardWindow(t *testing.T) {
+	wednesday := time.Date(2026, 3, 11, 15, 30, 0, 0, time.UTC)
+
+	from, to := models.LeaderboardWindow(models.LeaderboardPeriodWeekly, wednesday)
+	if !from.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("weekly window = [%v, %v), want week starting on Monday", from, to)
+	}
+
+	from, to = models.PreviousLeaderboardWindow(models.LeaderboardPeriodMonthly, wednesday)
+	if !from.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("previous monthly window = [%v, %v), want February", from, to)
+	}
+
+	from, _ = models.LeaderboardWindow(models.LeaderboardPeriodAllTime, wednesday)
+	if !from.IsZero() {
+		t.Errorf("all-time window starts at %v, want zero time", from)
+	}
+}
+
+func TestLeaderboardService_GetLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	_, historyRepo, leaderboard := setupLeaderboardService(t, models.LeaderboardRule{MinGames: 5})
+
+	now, _ := models.LeaderboardWindow(models.LeaderboardPeriodWeekly, time.Now())
+	now = now.Add(time.Minute)
+	addResults(historyRepo, 1, now, 1, 1.5, 4, 12, 8) // 20 игр: прибыль -2, доля побед 0.6, 4 попытки
+	addResults(historyRepo, 2, now, 1, 3, 2, 4, 1)    // 5 игр: прибыль 7, доля побед 0.8, 2 попытки
+	addResults(historyRepo, 3, now, 10, 30, 1, 1, 0)  // 1 игра: прибыль 20, меньше минимума для рейтингов
+	// Прошлая неделя в текущую таблицу не попадает
+	addResults(historyRepo, 1, now.AddDate(0, 0, -7), 1, 100, 1, 10, 0)
+
+	tests := []struct {
+		metric string
+		want   []uint64
+	}{
+		{models.LeaderboardMetricProfit, []uint64{3, 2, 1}},
+		{models.LeaderboardMetricWinRate, []uint64{2, 1}},
+		{models.LeaderboardMetricEfficiency, []uint64{2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			board, err := leaderboard.GetLeaderboard(ctx, models.LeaderboardPeriodWeekly, tt.metric, "", 10)
+			if err != nil {
+				t.Fatalf("GetLeaderboard() error = %v", err)
+			}
+			if len(board.Entries) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d", len(board.Entries), len(tt.want))
+			}
+			for i, entry := range board.Entries {
+				if entry.UserID != tt.want[i] || entry.Rank != i+1 {
+					t.Errorf("entry %d = user %d rank %d, want user %d", i, entry.UserID, entry.Rank, tt.want[i])
+				}
+			}
+		})
+	}
+
+	board, _ := leaderboard.GetLeaderboard(ctx, models.LeaderboardPeriodWeekly, models.LeaderboardMetricWinRate, "", 10)
+	if top := board.Entries[0]; top.Username != "sniper" || math.Abs(top.Value-0.8) > 1e-9 || math.Abs(top.Profit-7) > 1e-9 {
+		t.Errorf("top entry = %+v, want sniper with win rate 0.8 and profit 7", top)
+	}
+
+	// Скрытый пользователь не попадает в рейтинги
+	if err := leaderboard.SetOptOut(ctx, 3, true); err != nil {
+		t.Fatalf("SetOptOut() error = %v", err)
+	}
+	board, _ = leaderboard.GetLeaderboard(ctx, models.LeaderboardPeriodAllTime, models.LeaderboardMetricProfit, "", 10)
+	if len(board.Entries) != 2 || board.Entries[0].UserID != 1 || board.From != nil {
+		t.Errorf("all-time profit board = %+v, want user 1 first and user 3 hidden", board.Entries)
+	}
+
+	if _, err := leaderboard.GetLeaderboard(ctx, "yearly", "", "", 10); err == nil {
+		t.Error("GetLeaderboard() with unknown period should fail")
+	}
+	if _, err := leaderboard.GetLeaderboard(ctx, "", "luck", "", 10); err == nil {
+		t.Error("GetLeaderboard() with unknown metric should fail")
+	}
+}
+
+func TestLeaderboardService_ProcessSeasonPayouts(t *testing.T) {
+	ctx := context.Background()
+	rule := models.LeaderboardRule{Seasons: []models.LeaderboardSeason{
+		{Period: models.LeaderboardPeriodWeekly, Metric: models.LeaderboardMetricProfit, Currency: models.CurrencyTON, Prizes: []float64{5, 2}},
+		{Period: models.LeaderboardPeriodAllTime, Metric: models.LeaderboardMetricProfit, Currency: models.CurrencyTON, Prizes: []float64{100}},
+	}}
+	userRepo, historyRepo, leaderboard := setupLeaderboardService(t, rule)
+
+	lastWeek, _ := models.PreviousLeaderboardWindow(models.LeaderboardPeriodWeekly, time.Now())
+	addResults(historyRepo, 1, lastWeek.Add(time.Hour), 1, 2, 3, 3, 0) // прибыль 3
+	addResults(historyRepo, 2, lastWeek.Add(time.Hour), 1, 2, 3, 1, 0) // прибыль 1
+	addResults(historyRepo, 3, lastWeek.Add(time.Hour), 1, 0, 6, 0, 1) // прибыль -1, без приза
+
+	for i := 0; i < 2; i++ {
+		if err := leaderboard.ProcessSeasonPayouts(ctx); err != nil {
+			t.Fatalf("ProcessSeasonPayouts() error = %v", err)
+		}
+	}
+
+	assertTonBalance(t, userRepo, 1, 5)
+	assertTonBalance(t, userRepo, 2, 2)
+	assertTonBalance(t, userRepo, 3, 0)
+
+	payouts, err := leaderboard.GetPayouts(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("GetPayouts() error = %v", err)
+	}
+	if len(payouts) != 2 {
+		t.Errorf("got %d payouts, want 2 (all-time season is not paid)", len(payouts))
+	}
+}
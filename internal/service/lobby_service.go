@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
+	"github.com/TakuroBreath/wordle/internal/dictionary"
 	"github.com/TakuroBreath/wordle/internal/logger"
 	"github.com/TakuroBreath/wordle/internal/models"
 	"github.com/TakuroBreath/wordle/internal/repository"
@@ -25,32 +27,68 @@ type LobbyServiceImpl struct {
 	transactionService models.TransactionService
 	historyService     models.HistoryService
 	tonService         models.TONService
-	commissionRate     float64
+	commission         models.CommissionService
+	dictionary         *dictionary.Dictionary
+	sideBetService     models.SideBetService
+	gameAccess         models.GameAccessChecker
+	riskGuard          models.GameRiskGuard
+	jackpot            models.JackpotService
+	promo              models.PromoService
+	events             models.EventService
 	logger             *zap.Logger
 }
 
+// Параметры досрочной выплаты (cash-out)
+const (
+	cashOutMargin      = 0.2 // Доля, удерживаемая сервисом с честной стоимости выплаты
+	cashOutMaxFraction = 0.9 // Максимальная доля от потенциальной награды
+)
+
+// LobbyServiceDeps зависимости LobbyService. Репозитории, пользователи, транзакции, история и комиссия обязательны,
+// остальные могут быть nil - соответствующая возможность отключается (Dictionary по умолчанию встроенный словарь)
+type LobbyServiceDeps struct {
+	LobbyRepo          models.LobbyRepository
+	GameRepo           models.GameRepository
+	AttemptRepo        models.AttemptRepository
+	RedisRepo          repository.RedisRepository
+	UserService        models.UserService
+	TransactionService models.TransactionService
+	HistoryService     models.HistoryService
+	TONService         models.TONService
+	Commission         models.CommissionService
+	Dictionary         *dictionary.Dictionary
+	SideBets           models.SideBetService
+	GameAccess         models.GameAccessChecker
+	RiskGuard          models.GameRiskGuard
+	Jackpot            models.JackpotService
+	Promo              models.PromoService
+	// Events - шина доменных событий: завершение лобби записывается в outbox в одной транзакции со сменой статуса
+	Events models.EventService
+}
+
 // NewLobbyService создает новый экземпляр LobbyService
-func NewLobbyService(
-	lobbyRepo models.LobbyRepository,
-	gameRepo models.GameRepository,
-	attemptRepo models.AttemptRepository,
-	redisRepo repository.RedisRepository,
-	userService models.UserService,
-	transactionService models.TransactionService,
-	historyService models.HistoryService,
-	tonService models.TONService,
-	commissionRate float64,
-) models.LobbyService {
+func NewLobbyService(deps LobbyServiceDeps) models.LobbyService {
+	dict := deps.Dictionary
+	if dict == nil {
+		dict = dictionary.Default()
+	}
 	return &LobbyServiceImpl{
-		lobbyRepo:          lobbyRepo,
-		gameRepo:           gameRepo,
-		attemptRepo:        attemptRepo,
-		redisRepo:          redisRepo,
-		userService:        userService,
-		transactionService: transactionService,
-		historyService:     historyService,
-		tonService:         tonService,
-		commissionRate:     commissionRate,
+		lobbyRepo:          deps.LobbyRepo,
+		gameRepo:           deps.GameRepo,
+		attemptRepo:        deps.AttemptRepo,
+		redisRepo:          deps.RedisRepo,
+		userService:        deps.UserService,
+		transactionService: deps.TransactionService,
+		historyService:     deps.HistoryService,
+		tonService:         deps.TONService,
+		commission:         deps.Commission,
+		dictionary:         dict,
+		sideBetService:     deps.SideBets,
+		gameAccess:         deps.GameAccess,
+		riskGuard:          deps.RiskGuard,
+		jackpot:            deps.Jackpot,
+		promo:              deps.Promo,
+		events:             deps.Events,
 		logger:             logger.GetLogger(zap.String("service", "lobby")),
 	}
 }
@@ -83,10 +121,15 @@ func (s *LobbyServiceImpl) CreateLobby(ctx context.Context, lobby *models.Lobby)
 		return fmt.Errorf("game not found: %w", err)
 	}
 
-	if game.Status != models.GameStatusActive {
+	if !game.AcceptsBets(time.Now()) {
 		return errors.New("game is not active")
 	}
 
+	// Проверяем доступ к приватной игре
+	if err := s.checkGameAccess(ctx, game, lobby.UserID, lobby.InviteToken); err != nil {
+		return err
+	}
+
 	// Проверяем ставку
 	if lobby.BetAmount < game.MinBet || lobby.BetAmount > game.MaxBet {
 		return fmt.Errorf("bet amount must be between %.4f and %.4f", game.MinBet, game.MaxBet)
@@ -97,13 +140,8 @@ func (s *LobbyServiceImpl) CreateLobby(ctx context.Context, lobby *models.Lobby)
 		return errors.New("game cannot accept bet: insufficient reward pool")
 	}
 
-	// Проверяем баланс пользователя
-	hasBalance, err := s.userService.ValidateBalance(ctx, lobby.UserID, lobby.BetAmount, game.Currency)
-	if err != nil {
-		return fmt.Errorf("failed to validate balance: %w", err)
-	}
-	if !hasBalance {
-		return fmt.Errorf("insufficient %s balance", game.Currency)
+	if err := s.checkRiskExposure(ctx, game, lobby.BetAmount); err != nil {
+		return err
 	}
 
 	// Проверяем, нет ли уже активного лобби
@@ -112,25 +150,45 @@ func (s *LobbyServiceImpl) CreateLobby(ctx context.Context, lobby *models.Lobby)
 		return errors.New("user already has an active lobby for this game")
 	}
 
-	// Списываем ставку с баланса
-	if game.Currency == models.CurrencyTON {
-		err = s.userService.UpdateTonBalance(ctx, lobby.UserID, -lobby.BetAmount)
-	} else {
-		err = s.userService.UpdateUsdtBalance(ctx, lobby.UserID, -lobby.BetAmount)
-	}
+	lobby.ID = uuid.New()
+	lobby.Currency = game.Currency
+
+	// Сначала ставка оплачивается бонусными средствами, остаток - с основного баланса
+	bonusAmount, err := s.chargeBonusBet(ctx, lobby)
 	if err != nil {
-		return fmt.Errorf("failed to deduct bet: %w", err)
+		return err
+	}
+	realAmount := lobby.BetAmount - bonusAmount
+
+	// Проверяем баланс пользователя
+	if realAmount > 0 {
+		hasBalance, err := s.userService.ValidateBalance(ctx, lobby.UserID, realAmount, game.Currency)
+		if err != nil {
+			s.refundBonusBet(ctx, lobby, bonusAmount)
+			return fmt.Errorf("failed to validate balance: %w", err)
+		}
+		if !hasBalance {
+			s.refundBonusBet(ctx, lobby, bonusAmount)
+			return fmt.Errorf("insufficient %s balance", game.Currency)
+		}
+
+		// Списываем ставку с баланса
+		if game.Currency == models.CurrencyTON {
+			err = s.userService.UpdateTonBalance(ctx, lobby.UserID, -realAmount)
+		} else {
+			err = s.userService.UpdateUsdtBalance(ctx, lobby.UserID, -realAmount)
+		}
+		if err != nil {
+			s.refundBonusBet(ctx, lobby, bonusAmount)
+			return fmt.Errorf("failed to deduct bet: %w", err)
+		}
 	}
 
 	// Резервируем средства в игре
 	potentialReward := lobby.BetAmount * game.RewardMultiplier
 	if err := s.gameRepo.IncrementReservedAmount(ctx, game.ID, potentialReward); err != nil {
 		// Возвращаем деньги
-		if game.Currency == models.CurrencyTON {
-			_ = s.userService.UpdateTonBalance(ctx, lobby.UserID, lobby.BetAmount)
-		} else {
-			_ = s.userService.UpdateUsdtBalance(ctx, lobby.UserID, lobby.BetAmount)
-		}
+		s.refundBet(ctx, lobby, realAmount, bonusAmount)
 		return fmt.Errorf("failed to reserve funds: %w", err)
 	}
 
@@ -158,13 +216,12 @@ func (s *LobbyServiceImpl) CreateLobby(ctx context.Context, lobby *models.Lobby)
 	now := time.Now()
 	expiresAt := now.Add(time.Duration(game.TimeLimit) * time.Minute)
 
-	lobby.ID = uuid.New()
 	lobby.GameShortID = game.ShortID
 	lobby.Status = models.LobbyStatusActive
 	lobby.MaxTries = game.MaxTries
 	lobby.TriesUsed = 0
 	lobby.PotentialReward = potentialReward
-	lobby.Currency = game.Currency
+	lobby.BonusAmount = bonusAmount
 	lobby.StartedAt = &now
 	lobby.ExpiresAt = expiresAt
 	lobby.CreatedAt = now
@@ -174,11 +231,7 @@ func (s *LobbyServiceImpl) CreateLobby(ctx context.Context, lobby *models.Lobby)
 	if err := s.lobbyRepo.Create(ctx, lobby); err != nil {
 		// Откатываем
 		_ = s.gameRepo.DecrementReservedAmount(ctx, game.ID, potentialReward)
-		if game.Currency == models.CurrencyTON {
-			_ = s.userService.UpdateTonBalance(ctx, lobby.UserID, lobby.BetAmount)
-		} else {
-			_ = s.userService.UpdateUsdtBalance(ctx, lobby.UserID, lobby.BetAmount)
-		}
+		s.refundBet(ctx, lobby, realAmount, bonusAmount)
 		return fmt.Errorf("failed to create lobby: %w", err)
 	}
 
@@ -189,8 +242,69 @@ func (s *LobbyServiceImpl) CreateLobby(ctx context.Context, lobby *models.Lobby)
 	return nil
 }
 
+// chargeBonusBet списывает в счёт ставки бонусные средства пользователя и возвращает бонусную часть ставки
+func (s *LobbyServiceImpl) chargeBonusBet(ctx context.Context, lobby *models.Lobby) (float64, error) {
+	if s.promo == nil {
+		return 0, nil
+	}
+	bonusAmount, err := s.promo.ChargeBet(ctx, lobby)
+	if err != nil {
+		return 0, fmt.Errorf("failed to charge bonus balance: %w", err)
+	}
+	return bonusAmount, nil
+}
+
+// refundBonusBet возвращает бонусную часть ставки на бонусный баланс
+func (s *LobbyServiceImpl) refundBonusBet(ctx context.Context, lobby *models.Lobby, bonusAmount float64) {
+	if s.promo == nil || bonusAmount <= 0 {
+		return
+	}
+	if err := s.promo.RefundBet(ctx, lobby, bonusAmount); err != nil {
+		s.logger.Error("Failed to refund bonus bet",
+			zap.String("lobby_id", lobby.ID.String()),
+			zap.Float64("bonus_amount", bonusAmount),
+			zap.Error(err))
+	}
+}
+
+// refundBet возвращает ставку, если лобби не удалось создать
+func (s *LobbyServiceImpl) refundBet(ctx context.Context, lobby *models.Lobby, realAmount, bonusAmount float64) {
+	if realAmount > 0 {
+		if lobby.Currency == models.CurrencyTON {
+			_ = s.userService.UpdateTonBalance(ctx, lobby.UserID, realAmount)
+		} else {
+			_ = s.userService.UpdateUsdtBalance(ctx, lobby.UserID, realAmount)
+		}
+	}
+	s.refundBonusBet(ctx, lobby, bonusAmount)
+}
+
+// checkGameAccess проверяет доступ пользователя к приватной игре
+func (s *LobbyServiceImpl) checkGameAccess(ctx context.Context, game *models.Game, userID uint64, inviteToken string) error {
+	if !game.IsPrivate() {
+		return nil
+	}
+	if s.gameAccess == nil {
+		return models.ErrGameAccessDenied
+	}
+	return s.gameAccess.CheckAccess(ctx, game, userID, inviteToken)
+}
+
+// checkRiskExposure проверяет, что новая ставка укладывается в лимиты риска создателя
+func (s *LobbyServiceImpl) checkRiskExposure(ctx context.Context, game *models.Game, betAmount float64) error {
+	activeLobbies := 0
+	if game.MaxActiveLobbies > 0 {
+		count, err := s.lobbyRepo.CountActiveByGame(ctx, game.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count active lobbies: %w", err)
+		}
+		activeLobbies = count
+	}
+	return game.CheckExposure(game.MaxPotentialReward(betAmount), activeLobbies, time.Now())
+}
+
 // GetJoinPaymentInfo генерирует информацию для оплаты вступления в игру через блокчейн
-func (s *LobbyServiceImpl) GetJoinPaymentInfo(ctx context.Context, gameShortID string, userID uint64, betAmount float64) (*models.PaymentInfo, error) {
+func (s *LobbyServiceImpl) GetJoinPaymentInfo(ctx context.Context, gameShortID string, userID uint64, betAmount float64, inviteToken string) (*models.PaymentInfo, error) {
 	log := s.logger.With(zap.String("method", "GetJoinPaymentInfo"))
 
 	game, err := s.gameRepo.GetByShortID(ctx, gameShortID)
@@ -198,10 +312,14 @@ func (s *LobbyServiceImpl) GetJoinPaymentInfo(ctx context.Context, gameShortID s
 		return nil, fmt.Errorf("game not found: %w", err)
 	}
 
-	if game.Status != models.GameStatusActive {
+	if !game.AcceptsBets(time.Now()) {
 		return nil, errors.New("game is not active")
 	}
 
+	if err := s.checkGameAccess(ctx, game, userID, inviteToken); err != nil {
+		return nil, err
+	}
+
 	if betAmount < game.MinBet || betAmount > game.MaxBet {
 		return nil, fmt.Errorf("bet amount must be between %.4f and %.4f", game.MinBet, game.MaxBet)
 	}
@@ -210,8 +328,16 @@ func (s *LobbyServiceImpl) GetJoinPaymentInfo(ctx context.Context, gameShortID s
 		return nil, errors.New("game cannot accept bet")
 	}
 
-	// Генерируем комментарий
+	if err := s.checkRiskExposure(ctx, game, betAmount); err != nil {
+		return nil, err
+	}
+
+	// Генерируем комментарий. Для приватной игры по приглашению добавляем билет,
+	// привязанный к пользователю, чтобы воркер мог проверить доступ отправителя
 	comment := fmt.Sprintf("LB_%s_%d", gameShortID, time.Now().Unix())
+	if game.IsPrivate() && !game.HasMemberRestriction() {
+		comment += "_" + game.InviteTicket(userID)
+	}
 
 	masterWallet := s.tonService.GetMasterWalletAddress()
 	deepLink := s.tonService.GeneratePaymentDeepLink(masterWallet, betAmount, comment)
@@ -256,9 +382,29 @@ func (s *LobbyServiceImpl) GetGameLobbies(ctx context.Context, gameID uuid.UUID,
 	return s.lobbyRepo.GetByGameID(ctx, gameID, limit, offset)
 }
 
-// GetUserLobbies получает список лобби пользователя
-func (s *LobbyServiceImpl) GetUserLobbies(ctx context.Context, userID uint64, limit, offset int) ([]*models.Lobby, error) {
-	return s.lobbyRepo.GetByUserID(ctx, userID, limit, offset)
+// GetUserLobbies получает страницу лобби пользователя, начиная с новых
+func (s *LobbyServiceImpl) GetUserLobbies(ctx context.Context, userID uint64, limit int, cursor string) (*models.LobbyPage, error) {
+	after, err := models.DecodePageCursor(cursor, "")
+	if err != nil {
+		return nil, err
+	}
+
+	limit = pageLimit(limit)
+	lobbies, err := s.lobbyRepo.GetByUserIDAfter(ctx, userID, limit+1, after)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.LobbyPage{Lobbies: lobbies}
+	if len(lobbies) > limit {
+		page.Lobbies = lobbies[:limit]
+		last := page.Lobbies[limit-1]
+		page.NextCursor = (&models.PageCursor{Time: last.CreatedAt, ID: last.ID}).Encode()
+	}
+	if page.Lobbies == nil {
+		page.Lobbies = []*models.Lobby{}
+	}
+	return page, nil
 }
 
 // UpdateLobby обновляет лобби
@@ -442,64 +588,156 @@ func (s *LobbyServiceImpl) handleLobbyFinish(ctx context.Context, lobby *models.
 	if game == nil {
 		game, err = s.gameRepo.GetByID(ctx, lobby.GameID)
 		if err != nil {
-			_ = s.lobbyRepo.UpdateStatus(ctx, lobby.ID, finalStatus)
 			return fmt.Errorf("failed to get game: %w", err)
 		}
 	}
 
+	// Ставка комиссии, действующая для игры на момент расчёта
+	commissionRate := s.commission.GetGameRate(ctx, game)
+
+	// Атомарно переводим лобби в финальный статус, чтобы исключить двойной расчёт
+	claimed, err := s.finishLobby(ctx, lobby, game, finalStatus, lobbyReward(lobby, game, finalStatus, commissionRate, nil), commissionRate)
+	if err != nil {
+		return fmt.Errorf("failed to update lobby status: %w", err)
+	}
+	if !claimed {
+		log.Debug("Lobby already finished concurrently")
+		return nil
+	}
+
+	s.settleLobby(ctx, lobby, game, finalStatus, nil, commissionRate)
+	return nil
+}
+
+// finishLobby атомарно переводит активное лобби в финальный статус и в той же транзакции проводит
+// денежные расчёты и записывает событие LobbyFinished. Возвращает false, если лобби уже завершено параллельно
+func (s *LobbyServiceImpl) finishLobby(ctx context.Context, lobby *models.Lobby, game *models.Game, finalStatus string, reward, commissionRate float64) (bool, error) {
+	var claimed bool
+	err := withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+		var err error
+		claimed, err = s.lobbyRepo.TransitionStatus(ctx, lobby.ID, models.LobbyStatusActive, finalStatus)
+		if err != nil || !claimed {
+			return nil, err
+		}
+		if err := s.settleFunds(ctx, lobby, game, finalStatus, reward, commissionRate); err != nil {
+			return nil, err
+		}
+		return models.NewDomainEvent(models.EventLobbyFinished, lobby.ID, &models.LobbyFinishedEvent{
+			LobbyID:    lobby.ID,
+			GameID:     game.ID,
+			UserID:     lobby.UserID,
+			CreatorID:  game.CreatorID,
+			Status:     finalStatus,
+			TriesUsed:  lobby.TriesUsed,
+			BetAmount:  lobby.BetAmount,
+			Reward:     reward,
+			Currency:   game.Currency,
+			FinishedAt: time.Now(),
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+// settleFunds проводит денежные расчёты по завершаемому лобби: выплату игроку из пула игры
+// или зачисление проигранной ставки в пул, и освобождает резерв
+func (s *LobbyServiceImpl) settleFunds(ctx context.Context, lobby *models.Lobby, game *models.Game, finalStatus string, reward, commissionRate float64) error {
+	switch finalStatus {
+	case models.LobbyStatusSuccess:
+		if err := s.payReward(ctx, lobby, game, reward, fmt.Sprintf("Reward for winning game %s", game.Title)); err != nil {
+			return err
+		}
+	case models.LobbyStatusCashedOut:
+		if err := s.payReward(ctx, lobby, game, reward, fmt.Sprintf("Cash-out in game %s", game.Title)); err != nil {
+			return err
+		}
+	default:
+		// Ставка за вычетом комиссии сервиса переходит в пул игры
+		if err := s.gameRepo.IncrementRewardPool(ctx, game.ID, lobby.BetAmount-lobby.BetAmount*commissionRate); err != nil {
+			return fmt.Errorf("failed to add lost bet to reward pool: %w", err)
+		}
+
+		// Проигранная ставка тоже засчитывается в отыгрыш бонуса
+		if _, err := s.settleBonusBet(ctx, lobby, 0); err != nil {
+			return err
+		}
+	}
+
+	if err := s.gameRepo.DecrementReservedAmount(ctx, game.ID, lobby.PotentialReward); err != nil {
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+	return nil
+}
+
+// lobbyReward рассчитывает выплату игроку за вычетом комиссии: выигрыш или досрочная выплата (0 при проигрыше)
+func lobbyReward(lobby *models.Lobby, game *models.Game, finalStatus string, commissionRate float64, offer *models.CashOutOffer) float64 {
+	switch {
+	case finalStatus == models.LobbyStatusSuccess:
+		gross := grossReward(lobby.BetAmount, lobby.Multiplier(game.RewardMultiplier), lobby.TriesUsed, lobby.MaxTries)
+		return gross - gross*commissionRate
+	case finalStatus == models.LobbyStatusCashedOut && offer != nil:
+		return offer.Amount
+	}
+	return 0
+}
+
+// settleLobby производит расчёты по уже завершённому лобби, выплата которого проведена finishLobby:
+// комиссию, джекпот, статистику игры и историю. offer передаётся только для досрочной выплаты. Метрики, достижения, статистика игрока и уведомления
+// обрабатываются подписчиками события LobbyFinished
+func (s *LobbyServiceImpl) settleLobby(ctx context.Context, lobby *models.Lobby, game *models.Game, finalStatus string, offer *models.CashOutOffer, commissionRate float64) {
+	log := s.logger.With(zap.String("method", "settleLobby"),
+		zap.String("lobby_id", lobby.ID.String()),
+		zap.String("status", finalStatus))
+
+	var err error
 	var reward float64
 	var historyStatus string
+	// creatorLoss - изменение пула не в пользу создателя: выплата игроку или (со знаком минус) проигранная ставка
+	var creatorLoss float64
 
-	if finalStatus == models.LobbyStatusSuccess {
+	switch {
+	case finalStatus == models.LobbyStatusSuccess:
 		// Игрок выиграл
 		historyStatus = models.HistoryStatusPlayerWin
-		reward = s.CalculateReward(lobby.BetAmount, game.RewardMultiplier, lobby.TriesUsed, lobby.MaxTries)
+		gross := grossReward(lobby.BetAmount, lobby.Multiplier(game.RewardMultiplier), lobby.TriesUsed, lobby.MaxTries)
+		commission := gross * commissionRate
+		reward = gross - commission
 
 		log.Info("Player won",
 			zap.Float64("bet", lobby.BetAmount),
 			zap.Float64("reward", reward))
 
-		// Начисляем награду игроку
-		if game.Currency == models.CurrencyTON {
-			err = s.userService.UpdateTonBalance(ctx, lobby.UserID, reward)
-		} else {
-			err = s.userService.UpdateUsdtBalance(ctx, lobby.UserID, reward)
-		}
-		if err != nil {
-			log.Error("Failed to credit reward", zap.Error(err))
-		}
+		metrics.RecordReward(game.Currency, reward)
+		creatorLoss = reward
 
-		// Списываем из пула игры
-		if game.Currency == models.CurrencyTON {
-			game.RewardPoolTon -= reward
-		} else {
-			game.RewardPoolUsdt -= reward
-		}
+		// Часть комиссии, удержанной из выигрыша, уходит в джекпот платформы, остаток - на счёт сервиса
+		commission -= s.contributeJackpot(ctx, lobby, commission)
+		s.bookCommission(ctx, lobby, game, models.CommissionSourceLobbyWin, commissionRate, commission)
 
-		// Создаём транзакцию награды
-		rewardTx := &models.Transaction{
-			UserID:      lobby.UserID,
-			Type:        models.TransactionTypeReward,
-			Amount:      reward,
-			Currency:    game.Currency,
-			Status:      models.TransactionStatusCompleted,
-			GameID:      &game.ID,
-			GameShortID: game.ShortID,
-			LobbyID:     &lobby.ID,
-			Description: fmt.Sprintf("Reward for winning game %s", game.Title),
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+		// Разыгрываем джекпот (выплачивается из джекпота платформы, а не из пула игры)
+		if s.jackpot != nil {
+			if _, err = s.jackpot.AwardJackpot(ctx, lobby, finalStatus); err != nil {
+				log.Error("Failed to award jackpot", zap.Error(err))
+			}
 		}
-		_ = s.transactionService.CreateTransaction(ctx, rewardTx)
-
-		// Записываем награду в метрики
-		metrics.RecordReward(game.Currency, reward)
 
 		// Обновляем статистику пользователя
 		_ = s.userService.IncrementWins(ctx, lobby.UserID)
+	case finalStatus == models.LobbyStatusCashedOut && offer != nil:
+		// Игрок забрал досрочную выплату
+		historyStatus = models.HistoryStatusCashOut
+		reward = offer.Amount
 
-		metrics.IncrementGameComplete(lobby.TriesUsed)
-	} else {
+		log.Info("Player cashed out",
+			zap.Float64("bet", lobby.BetAmount),
+			zap.Float64("amount", reward),
+			zap.Float64("fraction", offer.Fraction))
+
+		metrics.RecordReward(game.Currency, reward)
+		creatorLoss = reward
+	default:
 		// Игрок проиграл
 		historyStatus = models.HistoryStatusCreatorWin
 
@@ -507,35 +745,34 @@ func (s *LobbyServiceImpl) handleLobbyFinish(ctx context.Context, lobby *models.
 			zap.Float64("bet", lobby.BetAmount),
 			zap.String("reason", finalStatus))
 
-		// Ставка остаётся в пуле игры (уже была добавлена при создании)
-		// Комиссия берётся с потенциальной награды
-		commission := lobby.BetAmount * s.commissionRate
+		// Ставка за вычетом комиссии сервиса уже перешла в пул игры
+		commission := lobby.BetAmount * commissionRate
+		creatorLoss = -(lobby.BetAmount - commission)
 
-		// Начисляем комиссию сервису (в реальности - отдельный кошелёк)
-		log.Info("Commission earned", zap.Float64("amount", commission))
+		// Часть комиссии уходит в джекпот платформы
+		commission -= s.contributeJackpot(ctx, lobby, commission)
 
-		// Записываем комиссию (revenue) в метрики
-		metrics.RecordCommission(game.Currency, commission)
+		// Зачисляем комиссию на счёт сервиса
+		log.Info("Commission earned", zap.Float64("amount", commission))
+		s.bookCommission(ctx, lobby, game, models.CommissionSourceLobbyLoss, commissionRate, commission)
 
 		// Обновляем статистику пользователя
 		_ = s.userService.IncrementLosses(ctx, lobby.UserID)
-
-		metrics.IncrementGameAbandoned()
 	}
 
-	// Освобождаем резерв
-	if err := s.gameRepo.DecrementReservedAmount(ctx, game.ID, lobby.PotentialReward); err != nil {
-		log.Error("Failed to release reservation", zap.Error(err))
+	// Учитываем лобби в популярности и доле побед игры
+	if err = s.gameRepo.RecordPlay(ctx, game.ID, finalStatus == models.LobbyStatusSuccess); err != nil {
+		log.Error("Failed to record game play", zap.Error(err))
 	}
 
-	// Обновляем игру
-	if err := s.gameRepo.Update(ctx, game); err != nil {
-		log.Error("Failed to update game", zap.Error(err))
+	// Учитываем результат в дневном убытке создателя и приостанавливаем игру при исчерпании лимитов
+	if err = s.gameRepo.AddDailyLoss(ctx, game.ID, creatorLoss, models.RiskDay(time.Now())); err != nil {
+		log.Error("Failed to record daily loss", zap.Error(err))
 	}
-
-	// Обновляем статус лобби
-	if err := s.lobbyRepo.UpdateStatus(ctx, lobby.ID, finalStatus); err != nil {
-		log.Error("Failed to update lobby status", zap.Error(err))
+	if s.riskGuard != nil {
+		if err = s.riskGuard.EnforceRiskLimits(ctx, game.ID); err != nil {
+			log.Error("Failed to enforce risk limits", zap.Error(err))
+		}
 	}
 
 	// Создаём запись в истории
@@ -549,13 +786,284 @@ func (s *LobbyServiceImpl) handleLobbyFinish(ctx context.Context, lobby *models.
 		Currency:  game.Currency,
 		TriesUsed: lobby.TriesUsed,
 	}
-	if err := s.historyService.CreateHistory(ctx, history); err != nil {
+	if err = s.historyService.CreateHistory(ctx, history); err != nil {
 		log.Error("Failed to create history", zap.Error(err))
 	}
 
+	// Рассчитываем ставки зрителей (учитываются отдельно от пула игры)
+	if s.sideBetService != nil {
+		if err = s.sideBetService.SettleLobby(ctx, lobby, finalStatus); err != nil {
+			log.Error("Failed to settle side bets", zap.Error(err))
+		}
+	}
+}
+
+// contributeJackpot отчисляет в джекпот платформы долю комиссии по лобби и возвращает отчисленную сумму
+func (s *LobbyServiceImpl) contributeJackpot(ctx context.Context, lobby *models.Lobby, commission float64) float64 {
+	if s.jackpot == nil {
+		return 0
+	}
+	contributed, err := s.jackpot.Contribute(ctx, lobby, commission)
+	if err != nil {
+		s.logger.Error("Failed to contribute to jackpot",
+			zap.String("lobby_id", lobby.ID.String()),
+			zap.Error(err))
+	}
+	return contributed
+}
+
+// bookCommission зачисляет комиссию по лобби на счёт сервиса
+func (s *LobbyServiceImpl) bookCommission(ctx context.Context, lobby *models.Lobby, game *models.Game, source string, rate, amount float64) {
+	entry := &models.CommissionEntry{
+		Source:      source,
+		ReferenceID: lobby.ID,
+		GameID:      &game.ID,
+		CreatorID:   game.CreatorID,
+		UserID:      lobby.UserID,
+		Currency:    game.Currency,
+		Volume:      lobby.BetAmount,
+		Rate:        rate,
+		Amount:      amount,
+	}
+	if err := s.commission.BookCommission(ctx, entry); err != nil {
+		s.logger.Error("Failed to book commission",
+			zap.String("lobby_id", lobby.ID.String()),
+			zap.String("source", source),
+			zap.Error(err))
+	}
+}
+
+// payReward начисляет выплату игроку из пула игры и создаёт транзакцию награды.
+// Выполняется в транзакции завершения лобби, чтобы баланс игрока, пул игры и журнал транзакций не расходились
+func (s *LobbyServiceImpl) payReward(ctx context.Context, lobby *models.Lobby, game *models.Game, reward float64, description string) error {
+	// Доля награды, приходящаяся на бонусную часть ставки, возвращается на бонусный баланс
+	realReward, err := s.settleBonusBet(ctx, lobby, reward)
+	if err != nil {
+		return err
+	}
+
+	if realReward > 0 {
+		if game.Currency == models.CurrencyTON {
+			err = s.userService.UpdateTonBalance(ctx, lobby.UserID, realReward)
+		} else {
+			err = s.userService.UpdateUsdtBalance(ctx, lobby.UserID, realReward)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to credit reward: %w", err)
+		}
+	}
+
+	// Списываем из пула игры (атомарно: создатель может параллельно выводить или пополнять пул)
+	if err := s.gameRepo.DecrementRewardPool(ctx, game.ID, reward); err != nil {
+		return fmt.Errorf("failed to deduct reward from pool: %w", err)
+	}
+
+	if realReward > 0 {
+		rewardTx := &models.Transaction{
+			UserID:      lobby.UserID,
+			Type:        models.TransactionTypeReward,
+			Amount:      realReward,
+			Currency:    game.Currency,
+			Status:      models.TransactionStatusCompleted,
+			GameID:      &game.ID,
+			GameShortID: game.ShortID,
+			LobbyID:     &lobby.ID,
+			Description: description,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		if err := s.transactionService.CreateTransaction(ctx, rewardTx); err != nil {
+			return fmt.Errorf("failed to create reward transaction: %w", err)
+		}
+	}
 	return nil
 }
 
+// settleBonusBet засчитывает ставку в отыгрыш бонуса и возвращает часть награды, зачисляемую на основной баланс
+func (s *LobbyServiceImpl) settleBonusBet(ctx context.Context, lobby *models.Lobby, reward float64) (float64, error) {
+	if s.promo == nil {
+		return reward, nil
+	}
+	realReward, err := s.promo.SettleBet(ctx, lobby, reward)
+	if err != nil {
+		return 0, fmt.Errorf("failed to settle bonus bet: %w", err)
+	}
+	return realReward, nil
+}
+
+// GetCashOutOffer рассчитывает предложение досрочной выплаты для активного лобби
+func (s *LobbyServiceImpl) GetCashOutOffer(ctx context.Context, lobbyID uuid.UUID, userID uint64) (*models.CashOutOffer, error) {
+	lobby, game, err := s.getCashOutLobby(ctx, lobbyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildCashOutOffer(ctx, lobby, game)
+}
+
+// AcceptCashOut принимает предложение досрочной выплаты и завершает лобби
+func (s *LobbyServiceImpl) AcceptCashOut(ctx context.Context, lobbyID uuid.UUID, userID uint64) (*models.CashOutOffer, error) {
+	log := s.logger.With(zap.String("method", "AcceptCashOut"),
+		zap.String("lobby_id", lobbyID.String()),
+		zap.Uint64("user_id", userID))
+
+	lobby, game, err := s.getCashOutLobby(ctx, lobbyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := s.buildCashOutOffer(ctx, lobby, game)
+	if err != nil {
+		return nil, err
+	}
+
+	// Завершаем лобби и выплачиваем сумму в одной транзакции: если параллельно пришла попытка
+	// или истекло время, выплата не производится, а при ошибке выплаты лобби остаётся активным
+	commissionRate := s.commission.GetGameRate(ctx, game)
+	claimed, err := s.finishLobby(ctx, lobby, game, models.LobbyStatusCashedOut, offer.Amount, commissionRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finish lobby: %w", err)
+	}
+	if !claimed {
+		return nil, errors.New("lobby is no longer active")
+	}
+
+	s.settleLobby(ctx, lobby, game, models.LobbyStatusCashedOut, offer, commissionRate)
+
+	log.Info("Cash-out accepted", zap.Float64("amount", offer.Amount))
+
+	return offer, nil
+}
+
+// getCashOutLobby загружает лобби и игру и проверяет, что досрочная выплата возможна
+func (s *LobbyServiceImpl) getCashOutLobby(ctx context.Context, lobbyID uuid.UUID, userID uint64) (*models.Lobby, *models.Game, error) {
+	lobby, err := s.lobbyRepo.GetByID(ctx, lobbyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("lobby not found: %w", err)
+	}
+
+	if lobby.UserID != userID {
+		return nil, nil, errors.New("access denied")
+	}
+	if !lobby.CanMakeAttempt() {
+		return nil, nil, errors.New("lobby is not active")
+	}
+	if lobby.TriesUsed == 0 {
+		return nil, nil, errors.New("cash-out is available only after the first attempt")
+	}
+
+	game, err := s.gameRepo.GetByID(ctx, lobby.GameID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("game not found: %w", err)
+	}
+
+	return lobby, game, nil
+}
+
+// buildCashOutOffer рассчитывает сумму досрочной выплаты по текущему состоянию лобби
+func (s *LobbyServiceImpl) buildCashOutOffer(ctx context.Context, lobby *models.Lobby, game *models.Game) (*models.CashOutOffer, error) {
+	attempts, err := s.attemptRepo.GetByLobbyID(ctx, lobby.ID, lobby.MaxTries, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempts: %w", err)
+	}
+
+	guesses := make([]dictionary.Guess, 0, len(attempts))
+	for _, a := range attempts {
+		guesses = append(guesses, dictionary.Guess{Word: a.Word, Result: a.Result})
+	}
+
+	greens, yellows := summarizeFeedback(guesses, game.Length)
+	candidates := s.dictionary.CountConsistent(game.Length, guesses)
+	triesLeft := lobby.MaxTries - lobby.TriesUsed
+
+	// Награда, если слово будет угадано следующей попыткой
+	potentialReward := grossReward(lobby.BetAmount, lobby.Multiplier(game.RewardMultiplier), lobby.TriesUsed+1, lobby.MaxTries) *
+		(1 - s.commission.GetGameRate(ctx, game))
+	fraction := calculateCashOutFraction(triesLeft, lobby.MaxTries, game.Length, greens, yellows, candidates)
+
+	amount := potentialReward * fraction
+	if amount > lobby.PotentialReward {
+		amount = lobby.PotentialReward
+	}
+	if amount <= 0 {
+		return nil, errors.New("no cash-out offer available")
+	}
+
+	return &models.CashOutOffer{
+		LobbyID:         lobby.ID,
+		Amount:          amount,
+		Fraction:        fraction,
+		PotentialReward: potentialReward,
+		Currency:        game.Currency,
+		TriesLeft:       triesLeft,
+		Greens:          greens,
+		Yellows:         yellows,
+		CandidatesLeft:  candidates,
+		QuotedAt:        time.Now(),
+	}, nil
+}
+
+// summarizeFeedback подсчитывает угаданные позиции и найденные буквы не на месте по всем попыткам
+func summarizeFeedback(guesses []dictionary.Guess, length int) (greens, yellows int) {
+	greenPositions := make([]bool, length)
+	greenLetters := make(map[rune]bool)
+	yellowLetters := make(map[rune]bool)
+
+	for _, g := range guesses {
+		runes := []rune(g.Word)
+		for i, r := range g.Result {
+			if i >= len(runes) || i >= length {
+				break
+			}
+			switch r {
+			case dictionary.ResultCorrect:
+				greenPositions[i] = true
+				greenLetters[runes[i]] = true
+			case dictionary.ResultPresent:
+				yellowLetters[runes[i]] = true
+			}
+		}
+	}
+
+	for _, known := range greenPositions {
+		if known {
+			greens++
+		}
+	}
+	for letter := range yellowLetters {
+		if !greenLetters[letter] {
+			yellows++
+		}
+	}
+
+	return greens, yellows
+}
+
+// calculateCashOutFraction вычисляет долю потенциальной награды для досрочной выплаты.
+// candidates - количество слов словаря, подходящих под подсказки (0 - словарь не помогает)
+func calculateCashOutFraction(triesLeft, maxTries, length, greens, yellows, candidates int) float64 {
+	if triesLeft <= 0 || maxTries <= 0 || length <= 0 {
+		return 0
+	}
+
+	// Доля известной информации о слове
+	knowledge := (float64(greens) + 0.5*float64(yellows)) / float64(length)
+	knowledge = math.Min(knowledge, 1)
+
+	// Шанс угадать слово за оставшиеся попытки
+	var solveChance float64
+	if candidates > 0 {
+		solveChance = math.Min(1, float64(triesLeft)/float64(candidates))
+	} else {
+		solveChance = knowledge * float64(triesLeft) / float64(maxTries)
+	}
+
+	chance := 0.7*solveChance + 0.3*knowledge
+	fraction := chance * (1 - cashOutMargin)
+
+	return math.Max(0, math.Min(fraction, cashOutMaxFraction))
+}
+
 // FinishLobby завершает лобби принудительно
 func (s *LobbyServiceImpl) FinishLobby(ctx context.Context, lobbyID uuid.UUID, success bool) error {
 	lobby, err := s.lobbyRepo.GetByID(ctx, lobbyID)
@@ -579,41 +1087,7 @@ func (s *LobbyServiceImpl) FinishLobby(ctx context.Context, lobbyID uuid.UUID, s
 
 // CheckWord проверяет слово и возвращает результат []int
 func (s *LobbyServiceImpl) CheckWord(word, target string) []int {
-	wordRunes := []rune(strings.ToLower(word))
-	targetRunes := []rune(strings.ToLower(target))
-
-	if len(wordRunes) != len(targetRunes) {
-		return nil
-	}
-
-	result := make([]int, len(targetRunes))
-	targetUsed := make([]bool, len(targetRunes))
-	wordUsed := make([]bool, len(wordRunes))
-
-	// Первый проход: точные совпадения (зелёные)
-	for i := 0; i < len(wordRunes); i++ {
-		if wordRunes[i] == targetRunes[i] {
-			result[i] = 2
-			targetUsed[i] = true
-			wordUsed[i] = true
-		}
-	}
-
-	// Второй проход: буквы есть, но не на месте (жёлтые)
-	for i := 0; i < len(wordRunes); i++ {
-		if wordUsed[i] {
-			continue
-		}
-		for j := 0; j < len(targetRunes); j++ {
-			if !targetUsed[j] && wordRunes[i] == targetRunes[j] {
-				result[i] = 1
-				targetUsed[j] = true
-				break
-			}
-		}
-	}
-
-	return result
+	return dictionary.Feedback(word, target)
 }
 
 // isWordCorrect проверяет, является ли результат полным совпадением
@@ -631,22 +1105,18 @@ func (s *LobbyServiceImpl) IsWordCorrect(word, target string) bool {
 	return strings.ToLower(word) == strings.ToLower(target)
 }
 
-// CalculateReward вычисляет награду с учётом комиссии
+// CalculateReward вычисляет награду с учётом комиссии по умолчанию.
+// При расчёте лобби используется ставка комиссии игры
 func (s *LobbyServiceImpl) CalculateReward(bet float64, multiplier float64, triesUsed, maxTries int) float64 {
+	return grossReward(bet, multiplier, triesUsed, maxTries) * (1 - s.commission.GetCommissionRate())
+}
+
+// grossReward вычисляет награду до вычета комиссии
+func grossReward(bet float64, multiplier float64, triesUsed, maxTries int) float64 {
 	if triesUsed <= 0 || maxTries <= 0 || triesUsed > maxTries {
 		return 0
 	}
 
-	// Базовая награда
-	baseReward := bet * multiplier
-
-	// Бонус за быстрое угадывание
-	triesBonus := 1.0 + (float64(maxTries-triesUsed)/float64(maxTries))*0.5
-
-	grossReward := baseReward * triesBonus
-
-	// Вычитаем комиссию 5%
-	netReward := grossReward * (1 - s.commissionRate)
-
-	return netReward
+	// Базовая награда с бонусом за быстрое угадывание
+	return bet * multiplier * models.RewardTriesBonus(triesUsed, maxTries)
 }
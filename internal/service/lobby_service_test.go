@@ -1,7 +1,14 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
 )
 
 func TestLobbyService_CheckWord(t *testing.T) {
@@ -192,3 +199,223 @@ func BenchmarkCalculateReward(b *testing.B) {
 		calculateReward(bet, multiplier, commission)
 	}
 }
+
+func TestCalculateCashOutFraction(t *testing.T) {
+	tests := []struct {
+		name       string
+		triesLeft  int
+		maxTries   int
+		length     int
+		greens     int
+		yellows    int
+		candidates int
+		wantMin    float64
+		wantMax    float64
+	}{
+		{
+			name:      "попытки закончились",
+			triesLeft: 0, maxTries: 6, length: 5, greens: 3, yellows: 1, candidates: 2,
+			wantMin: 0, wantMax: 0,
+		},
+		{
+			name:      "остался один кандидат",
+			triesLeft: 3, maxTries: 6, length: 5, greens: 4, yellows: 0, candidates: 1,
+			wantMin: 0.7, wantMax: cashOutMaxFraction,
+		},
+		{
+			name:      "много кандидатов и нет подсказок",
+			triesLeft: 5, maxTries: 6, length: 5, greens: 0, yellows: 0, candidates: 200,
+			wantMin: 0, wantMax: 0.05,
+		},
+		{
+			name:      "словарь не знает слов такой длины",
+			triesLeft: 3, maxTries: 6, length: 7, greens: 3, yellows: 2, candidates: 0,
+			wantMin: 0.2, wantMax: 0.4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateCashOutFraction(tt.triesLeft, tt.maxTries, tt.length, tt.greens, tt.yellows, tt.candidates)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("calculateCashOutFraction() = %v, want in [%v, %v]", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestSummarizeFeedback(t *testing.T) {
+	guesses := []dictionary.Guess{
+		{Word: "сокол", Result: []int{2, 0, 1, 0, 0}},
+		{Word: "слава", Result: []int{2, 2, 0, 0, 0}},
+	}
+
+	greens, yellows := summarizeFeedback(guesses, 5)
+	if greens != 2 {
+		t.Errorf("greens = %d, want 2", greens)
+	}
+	if yellows != 1 {
+		t.Errorf("yellows = %d, want 1", yellows)
+	}
+}
+
+func TestLobbyService_AcceptCashOut(t *testing.T) {
+	ctx := context.Background()
+
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	attemptRepo := mocks.NewMockAttemptRepository()
+	historyRepo := mocks.NewMockHistoryRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+
+	txService := NewTransactionServiceImpl(txRepo, userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	historyService := NewHistoryService(historyRepo, gameRepo, userRepo, lobbyRepo)
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        attemptRepo,
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово", "слава", "сокол", "сонар"}),
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "player", BalanceTon: 10})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 2, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	lobby := &models.Lobby{GameID: game.ID, UserID: 1, BetAmount: 1}
+	if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+
+	if _, err := lobbyService.GetCashOutOffer(ctx, lobby.ID, 1); err == nil {
+		t.Fatal("GetCashOutOffer() before first attempt should fail")
+	}
+
+	if _, err := lobbyService.ProcessAttempt(ctx, lobby.ID, "слава"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+
+	if _, err := lobbyService.GetCashOutOffer(ctx, lobby.ID, 99); err == nil {
+		t.Error("GetCashOutOffer() for another user should fail")
+	}
+
+	quote, err := lobbyService.GetCashOutOffer(ctx, lobby.ID, 1)
+	if err != nil {
+		t.Fatalf("GetCashOutOffer() error = %v", err)
+	}
+	if quote.Amount <= 0 || quote.Amount > lobby.PotentialReward {
+		t.Fatalf("unexpected offer amount %v", quote.Amount)
+	}
+	// После «слава» под подсказки подходит только «слово»: «а» отсутствует, «с», «л» и «в» на своих местах
+	if quote.CandidatesLeft != 1 {
+		t.Errorf("candidates left = %d, want 1", quote.CandidatesLeft)
+	}
+
+	offer, err := lobbyService.AcceptCashOut(ctx, lobby.ID, 1)
+	if err != nil {
+		t.Fatalf("AcceptCashOut() error = %v", err)
+	}
+	if offer.Amount != quote.Amount {
+		t.Errorf("accepted amount = %v, quoted %v", offer.Amount, quote.Amount)
+	}
+
+	finished, _ := lobbyRepo.GetByID(ctx, lobby.ID)
+	if finished.Status != models.LobbyStatusCashedOut {
+		t.Errorf("lobby status = %s, want %s", finished.Status, models.LobbyStatusCashedOut)
+	}
+
+	updatedGame, _ := gameRepo.GetByID(ctx, game.ID)
+	if updatedGame.ReservedAmount != 0 {
+		t.Errorf("reserved amount = %v, want 0", updatedGame.ReservedAmount)
+	}
+	// Досрочная выплата учитывается в популярности, но не в доле побед
+	if updatedGame.Plays != 1 || updatedGame.Wins != 0 {
+		t.Errorf("plays/wins = %d/%d, want 1/0", updatedGame.Plays, updatedGame.Wins)
+	}
+	if want := 100 - offer.Amount; updatedGame.RewardPoolTon < want-1e-9 || updatedGame.RewardPoolTon > want+1e-9 {
+		t.Errorf("reward pool = %v, want %v", updatedGame.RewardPoolTon, want)
+	}
+
+	history, err := historyRepo.GetByLobbyID(ctx, lobby.ID)
+	if err != nil {
+		t.Fatalf("history not found: %v", err)
+	}
+	if history.Status != models.HistoryStatusCashOut {
+		t.Errorf("history status = %s, want %s", history.Status, models.HistoryStatusCashOut)
+	}
+
+	user, _ := userRepo.GetByTelegramID(ctx, 1)
+	if want := 10 - 1 + offer.Amount; user.BalanceTon < want-1e-9 || user.BalanceTon > want+1e-9 {
+		t.Errorf("balance = %v, want %v", user.BalanceTon, want)
+	}
+
+	if _, err := lobbyService.AcceptCashOut(ctx, lobby.ID, 1); err == nil {
+		t.Error("second AcceptCashOut() should fail")
+	}
+}
+
+// failingPoolGameRepository не даёт списать выплату из пула игры
+type failingPoolGameRepository struct {
+	*mocks.MockGameRepository
+}
+
+func (r *failingPoolGameRepository) DecrementRewardPool(ctx context.Context, id uuid.UUID, amount float64) error {
+	return errors.New("pool unavailable")
+}
+
+func TestLobbyService_AcceptCashOutReturnsPayoutError(t *testing.T) {
+	ctx := context.Background()
+
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	attemptRepo := mocks.NewMockAttemptRepository()
+	historyRepo := mocks.NewMockHistoryRepository()
+
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           &failingPoolGameRepository{MockGameRepository: gameRepo},
+		AttemptRepo:        attemptRepo,
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     NewHistoryService(historyRepo, gameRepo, userRepo, lobbyRepo),
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово", "слава", "сокол", "сонар"}),
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "player", BalanceTon: 10})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 2, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	lobby := &models.Lobby{GameID: game.ID, UserID: 1, BetAmount: 1}
+	if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	if _, err := lobbyService.ProcessAttempt(ctx, lobby.ID, "слава"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+
+	if _, err := lobbyService.AcceptCashOut(ctx, lobby.ID, 1); err == nil {
+		t.Fatal("AcceptCashOut() with a failed payout should fail")
+	}
+	if _, err := historyRepo.GetByLobbyID(ctx, lobby.ID); err == nil {
+		t.Error("history should not be recorded for a failed payout")
+	}
+}
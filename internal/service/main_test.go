@@ -0,0 +1,15 @@
+package service
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+)
+
+// TestMain отключает файловый вывод логгера: иначе тесты пишут logs/app.json в каталог пакета
+func TestMain(m *testing.M) {
+	logger.InitForTesting(io.Discard)
+	os.Exit(m.Run())
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/TakuroBreath/wordle/internal/telegram"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Параметры отправки уведомлений по умолчанию
+const (
+	defaultNotificationMaxAttempts = 5
+	defaultNotificationRetryDelay  = 30 * time.Second
+	defaultNotificationRate        = 25 // Чуть ниже лимита Bot API в 30 сообщений в секунду
+	defaultNotificationBatchSize   = 100
+	defaultLobbyExpiryNotice       = 2 * time.Minute
+	maxNotificationRetryDelay      = time.Hour
+)
+
+// defaultNotificationLanguage язык уведомлений, если для языка пользователя нет перевода
+const defaultNotificationLanguage = "en"
+
+// notificationMessages шаблоны уведомлений по ключу сообщения и языку.
+// Аргументы во всех переводах одного сообщения идут в одном порядке
+var notificationMessages = map[string]map[string]string{
+	"deposit_credited": {
+		"en": "Your deposit of %s %s has been credited to your balance.",
+		"ru": "Депозит %s %s зачислен на ваш баланс.",
+	},
+	"game_activated": {
+		"en": "Your game \"%s\" is now active and open to players.",
+		"ru": "Ваша игра «%s» активна и открыта для игроков.",
+	},
+	"game_result_win": {
+		"en": "A player guessed the word in your game \"%s\" and won %s %s.",
+		"ru": "Игрок угадал слово в вашей игре «%s» и выиграл %s %s.",
+	},
+	"game_result_loss": {
+		"en": "A player lost in your game \"%s\", the bet was %s %s.",
+		"ru": "Игрок проиграл в вашей игре «%s», ставка %s %s.",
+	},
+	"lobby_expiring": {
+		"en": "Time is running out in \"%s\": %d min left, %d tries remaining.",
+		"ru": "В игре «%s» заканчивается время: осталось минут - %d, попыток - %d.",
+	},
+	"withdrawal_sent": {
+		"en": "Your withdrawal of %s %s has been sent to %s.",
+		"ru": "Вывод %s %s отправлен на адрес %s.",
+	},
+}
+
+// NotificationServiceImpl представляет собой реализацию NotificationService
+type NotificationServiceImpl struct {
+	notificationRepo models.NotificationRepository
+	userRepo         models.UserRepository
+	gameRepo         models.GameRepository
+	sender           models.UserNotifier
+	rule             models.NotificationRule
+	logger           *zap.Logger
+}
+
+// NewNotificationService создает новый экземпляр NotificationService.
+// sender отправляет сообщения через Bot API; без него уведомления не ставятся в очередь (может быть nil).
+// Нулевые параметры rule заменяются значениями по умолчанию
+func NewNotificationService(
+	notificationRepo models.NotificationRepository,
+	userRepo models.UserRepository,
+	gameRepo models.GameRepository,
+	sender models.UserNotifier,
+	rule models.NotificationRule,
+) models.NotificationService {
+	if rule.MaxAttempts <= 0 {
+		rule.MaxAttempts = defaultNotificationMaxAttempts
+	}
+	if rule.RetryDelay <= 0 {
+		rule.RetryDelay = defaultNotificationRetryDelay
+	}
+	if rule.RatePerSecond <= 0 {
+		rule.RatePerSecond = defaultNotificationRate
+	}
+	if rule.BatchSize <= 0 {
+		rule.BatchSize = defaultNotificationBatchSize
+	}
+	if rule.ExpiryNotice <= 0 {
+		rule.ExpiryNotice = defaultLobbyExpiryNotice
+	}
+	return &NotificationServiceImpl{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		gameRepo:         gameRepo,
+		sender:           sender,
+		rule:             rule,
+		logger:           logger.GetLogger(zap.String("service", "notification")),
+	}
+}
+
+// GetPreferences возвращает настройки по всем типам уведомлений. Не изменённые пользователем типы включены
+func (s *NotificationServiceImpl) GetPreferences(ctx context.Context, userID uint64) (map[string]bool, error) {
+	stored, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make(map[string]bool, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		enabled, ok := stored[notificationType]
+		preferences[notificationType] = !ok || enabled
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences меняет настройки переданных типов уведомлений и возвращает итоговые настройки
+func (s *NotificationServiceImpl) UpdatePreferences(ctx context.Context, userID uint64, preferences map[string]bool) (map[string]bool, error) {
+	for notificationType := range preferences {
+		if !slices.Contains(models.NotificationTypes, notificationType) {
+			return nil, fmt.Errorf("%w: %s", models.ErrInvalidNotificationType, notificationType)
+		}
+	}
+
+	for notificationType, enabled := range preferences {
+		if err := s.notificationRepo.SetPreference(ctx, userID, notificationType, enabled); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPreferences(ctx, userID)
+}
+
+// DepositCredited уведомляет пользователя о зачислении депозита
+func (s *NotificationServiceImpl) DepositCredited(ctx context.Context, tx *models.Transaction) error {
+	if tx.UserID == 0 {
+		return nil
+	}
+	return s.enqueue(ctx, tx.UserID, models.NotificationDepositCredited, transactionEventKey(models.NotificationDepositCredited, tx),
+		"deposit_credited", formatNotificationAmount(tx.Amount), tx.Currency)
+}
+
+// WithdrawalSent уведомляет пользователя об отправке вывода в блокчейн
+func (s *NotificationServiceImpl) WithdrawalSent(ctx context.Context, tx *models.Transaction) error {
+	return s.enqueue(ctx, tx.UserID, models.NotificationWithdrawalSent, transactionEventKey(models.NotificationWithdrawalSent, tx),
+		"withdrawal_sent", formatNotificationAmount(tx.Amount-tx.Fee), tx.Currency, tx.ToAddress)
+}
+
+// GameActivated уведомляет создателя о том, что игра стала активной. Игра может активироваться
+// повторно после паузы, поэтому повторные события не отбрасываются
+func (s *NotificationServiceImpl) GameActivated(ctx context.Context, game *models.Game) error {
+	return s.enqueue(ctx, game.CreatorID, models.NotificationGameActivated, "", "game_activated", game.Title)
+}
+
+// LobbyFinished уведомляет создателя игры о результате лобби: выигрыш (в том числе досрочная выплата) или проигрыш игрока
+func (s *NotificationServiceImpl) LobbyFinished(ctx context.Context, lobby *models.Lobby, game *models.Game, finalStatus string, reward float64) error {
+	key := models.NotificationGameResult + ":" + lobby.ID.String()
+	if finalStatus == models.LobbyStatusSuccess || finalStatus == models.LobbyStatusCashedOut {
+		return s.enqueue(ctx, game.CreatorID, models.NotificationGameResult, key,
+			"game_result_win", game.Title, formatNotificationAmount(reward), game.Currency)
+	}
+	return s.enqueue(ctx, game.CreatorID, models.NotificationGameResult, key,
+		"game_result_loss", game.Title, formatNotificationAmount(lobby.BetAmount), game.Currency)
+}
+
+// ProcessExpiringLobbies напоминает игрокам о лобби, которые истекут в течение rule.ExpiryNotice.
+// Напоминание по лобби отправляется один раз
+func (s *NotificationServiceImpl) ProcessExpiringLobbies(ctx context.Context) error {
+	if s.sender == nil {
+		return nil
+	}
+
+	now := time.Now()
+	lobbies, err := s.notificationRepo.GetExpiringLobbies(ctx, now, now.Add(s.rule.ExpiryNotice), s.rule.ExpiryNotice, s.rule.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get expiring lobbies: %w", err)
+	}
+
+	for _, lobby := range lobbies {
+		game, err := s.gameRepo.GetByID(ctx, lobby.GameID)
+		if err != nil {
+			s.logger.Warn("Failed to get game for expiring lobby",
+				zap.String("lobby_id", lobby.ID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		minutesLeft := int(math.Ceil(lobby.ExpiresAt.Sub(now).Minutes()))
+		key := models.NotificationLobbyExpiring + ":" + lobby.ID.String()
+		if err := s.enqueue(ctx, lobby.UserID, models.NotificationLobbyExpiring, key,
+			"lobby_expiring", game.Title, minutesLeft, lobby.MaxTries-lobby.TriesUsed); err != nil {
+			s.logger.Warn("Failed to enqueue lobby expiry reminder",
+				zap.String("lobby_id", lobby.ID.String()),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// ProcessOutbox отправляет уведомления, время попытки которых наступило, не чаще rule.RatePerSecond в секунду.
+// При ошибке сообщение откладывается с удвоением задержки, после rule.MaxAttempts попыток помечается failed.
+// Если Bot API ограничил частоту, остаток пачки переносится на следующий запуск
+func (s *NotificationServiceImpl) ProcessOutbox(ctx context.Context) error {
+	if s.sender == nil {
+		return nil
+	}
+
+	notifications, err := s.notificationRepo.GetDue(ctx, time.Now(), s.rule.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due notifications: %w", err)
+	}
+
+	interval := time.Second / time.Duration(s.rule.RatePerSecond)
+	for i, notification := range notifications {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+
+		sendErr := s.sender.NotifyUser(ctx, notification.UserID, notification.Text)
+		throttled := s.recordAttempt(notification, sendErr, time.Now())
+		if err := s.notificationRepo.UpdateDelivery(ctx, notification); err != nil {
+			return fmt.Errorf("failed to update notification %s: %w", notification.ID, err)
+		}
+
+		if sendErr != nil {
+			s.logger.Warn("Failed to send notification",
+				zap.String("notification_id", notification.ID.String()),
+				zap.Uint64("user_id", notification.UserID),
+				zap.Int("attempts", notification.Attempts),
+				zap.String("status", notification.Status),
+				zap.Error(sendErr))
+		}
+		if throttled {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// recordAttempt обновляет уведомление по результату попытки отправки.
+// Возвращает true, если Bot API ограничил частоту отправки
+func (s *NotificationServiceImpl) recordAttempt(notification *models.Notification, sendErr error, now time.Time) bool {
+	if sendErr == nil {
+		notification.Attempts++
+		notification.Status = models.NotificationStatusSent
+		notification.LastError = ""
+		notification.SentAt = &now
+		return false
+	}
+
+	notification.LastError = sendErr.Error()
+
+	var apiErr *telegram.APIError
+	if errors.As(sendErr, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusTooManyRequests:
+			// Превышение лимита не расходует попытку: сообщение уйдёт после паузы, указанной Bot API
+			delay := apiErr.RetryAfter
+			if delay <= 0 {
+				delay = s.rule.RetryDelay
+			}
+			notification.NextAttemptAt = now.Add(delay)
+			return true
+		case http.StatusBadRequest, http.StatusForbidden:
+			// Пользователь заблокировал бота или не запускал его: повтор не поможет
+			notification.Attempts++
+			notification.Status = models.NotificationStatusFailed
+			return false
+		}
+	}
+
+	notification.Attempts++
+	if notification.Attempts >= s.rule.MaxAttempts {
+		notification.Status = models.NotificationStatusFailed
+		return false
+	}
+
+	delay := s.rule.RetryDelay << (notification.Attempts - 1)
+	if delay <= 0 || delay > maxNotificationRetryDelay {
+		delay = maxNotificationRetryDelay
+	}
+	notification.NextAttemptAt = now.Add(delay)
+	return false
+}
+
+// enqueue ставит локализованное уведомление в очередь, если пользователь не отключил этот тип
+func (s *NotificationServiceImpl) enqueue(ctx context.Context, userID uint64, notificationType, dedupKey, messageKey string, args ...any) error {
+	if s.sender == nil {
+		return nil
+	}
+
+	preferences, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if enabled, ok := preferences[notificationType]; ok && !enabled {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByTelegramID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	_, err = s.notificationRepo.Enqueue(ctx, &models.Notification{
+		UserID:   userID,
+		Type:     notificationType,
+		DedupKey: dedupKey,
+		Text:     localizeNotification(user.LanguageCode, messageKey, args...),
+	})
+	return err
+}
+
+// localizeNotification формирует текст уведомления на языке пользователя (language_code из Telegram, например "ru" или "pt-br")
+func localizeNotification(languageCode, messageKey string, args ...any) string {
+	translations := notificationMessages[messageKey]
+
+	language := strings.ToLower(languageCode)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	template, ok := translations[language]
+	if !ok {
+		template = translations[defaultNotificationLanguage]
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// transactionEventKey формирует ключ события транзакции для защиты от повторных уведомлений
+func transactionEventKey(notificationType string, tx *models.Transaction) string {
+	if tx.ID == uuid.Nil && tx.TxHash != "" {
+		return notificationType + ":" + tx.TxHash
+	}
+	return notificationType + ":" + tx.ID.String()
+}
+
+// formatNotificationAmount форматирует сумму без лишних нулей (до 6 знаков после запятой)
+func formatNotificationAmount(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount*1e6)/1e6, 'f', -1, 64)
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/TakuroBreath/wordle/internal/telegram"
+	"github.com/google/uuid"
+)
+
+// fakeBotAPI имитирует sendMessage и answerInlineQuery Bot API и отвечает заданным кодом ошибки (0 - успех)
+type fakeBotAPI struct {
+	mu            sync.Mutex
+	texts         map[string][]string
+	sentAt        []time.Time
+	inlineResults []string // Параметр results каждого ответа на inline-запрос
+	errorCode     int
+	retryAfter    int
+}
+
+func newFakeBotAPI(t *testing.T) (*fakeBotAPI, *telegram.Client) {
+	t.Helper()
+	bot := &fakeBotAPI{texts: make(map[string][]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bot.mu.Lock()
+		defer bot.mu.Unlock()
+		if bot.errorCode != 0 {
+			fmt.Fprintf(w, `{"ok":false,"error_code":%d,"description":"error","parameters":{"retry_after":%d}}`,
+				bot.errorCode, bot.retryAfter)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/answerInlineQuery") {
+			bot.inlineResults = append(bot.inlineResults, r.URL.Query().Get("results"))
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+			return
+		}
+		chatID := r.URL.Query().Get("chat_id")
+		bot.texts[chatID] = append(bot.texts[chatID], r.URL.Query().Get("text"))
+		bot.sentAt = append(bot.sentAt, time.Now())
+		fmt.Fprint(w, `{"ok":true,"result":{}}`)
+	}))
+	t.Cleanup(server.Close)
+	return bot, telegram.NewClient("token").WithAPIEndpoint(server.URL)
+}
+
+func (b *fakeBotAPI) fail(code, retryAfter int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errorCode, b.retryAfter = code, retryAfter
+}
+
+type notificationFixture struct {
+	repo          *mocks.MockNotificationRepository
+	games         *mocks.MockGameRepository
+	lobbies       *mocks.MockLobbyRepository
+	bot           *fakeBotAPI
+	notifications models.NotificationService
+}
+
+func setupNotificationService(t *testing.T, rule models.NotificationRule) *notificationFixture {
+	t.Helper()
+	bot, client := newFakeBotAPI(t)
+	f := &notificationFixture{
+		games:   mocks.NewMockGameRepository(),
+		lobbies: mocks.NewMockLobbyRepository(),
+		bot:     bot,
+	}
+	f.repo = mocks.NewMockNotificationRepository(f.lobbies)
+
+	users := mocks.NewMockUserRepository()
+	_ = users.Create(context.Background(), &models.User{TelegramID: 1, LanguageCode: "ru-RU"})
+	_ = users.Create(context.Background(), &models.User{TelegramID: 2, LanguageCode: "en"})
+	_ = users.Create(context.Background(), &models.User{TelegramID: 3, LanguageCode: "de"})
+
+	f.notifications = NewNotificationService(f.repo, users, f.games, client, rule)
+	return f
+}
+
+// statuses возвращает статусы уведомлений в очереди в порядке постановки
+func (f *notificationFixture) statuses() []string {
+	var statuses []string
+	for _, notification := range f.repo.Notifications() {
+		statuses = append(statuses, notification.Status)
+	}
+	return statuses
+}
+
+func TestNotificationService_LocalizedDelivery(t *testing.T) {
+	ctx := context.Background()
+	f := setupNotificationService(t, models.NotificationRule{})
+
+	for _, userID := range []uint64{1, 2, 3} {
+		tx := &models.Transaction{ID: uuid.New(), UserID: userID, Amount: 1.5, Currency: models.CurrencyTON}
+		if err := f.notifications.DepositCredited(ctx, tx); err != nil {
+			t.Fatalf("DepositCredited() error = %v", err)
+		}
+		// Повторное событие по той же транзакции не дублирует сообщение
+		_ = f.notifications.DepositCredited(ctx, tx)
+	}
+	if err := f.notifications.ProcessOutbox(ctx); err != nil {
+		t.Fatalf("ProcessOutbox() error = %v", err)
+	}
+
+	want := map[string]string{
+		"1": "Депозит 1.5 TON зачислен на ваш баланс.",
+		"2": "Your deposit of 1.5 TON has been credited to your balance.",
+		"3": "Your deposit of 1.5 TON has been credited to your balance.",
+	}
+	for chatID, text := range want {
+		if got := f.bot.texts[chatID]; len(got) != 1 || got[0] != text {
+			t.Errorf("messages to %s = %q, want [%q]", chatID, got, text)
+		}
+	}
+	for _, status := range f.statuses() {
+		if status != models.NotificationStatusSent {
+			t.Errorf("statuses = %v, want all sent", f.statuses())
+			break
+		}
+	}
+}
+
+func TestNotificationService_Preferences(t *testing.T) {
+	ctx := context.Background()
+	f := setupNotificationService(t, models.NotificationRule{})
+
+	if _, err := f.notifications.UpdatePreferences(ctx, 2, map[string]bool{"unknown": false}); !errors.Is(err, models.ErrInvalidNotificationType) {
+		t.Errorf("UpdatePreferences() unknown type error = %v, want ErrInvalidNotificationType", err)
+	}
+	preferences, err := f.notifications.UpdatePreferences(ctx, 2, map[string]bool{models.NotificationGameResult: false})
+	if err != nil {
+		t.Fatalf("UpdatePreferences() error = %v", err)
+	}
+	if preferences[models.NotificationGameResult] || !preferences[models.NotificationGameActivated] ||
+		len(preferences) != len(models.NotificationTypes) {
+		t.Errorf("preferences = %v, want every type enabled except game_result", preferences)
+	}
+
+	game := &models.Game{CreatorID: 2, Title: "Words", Currency: models.CurrencyTON}
+	lobby := &models.Lobby{ID: uuid.New(), BetAmount: 2}
+	_ = f.notifications.LobbyFinished(ctx, lobby, game, models.LobbyStatusFailedTries, 0)
+	_ = f.notifications.GameActivated(ctx, game)
+	_ = f.notifications.ProcessOutbox(ctx)
+
+	if got := f.bot.texts["2"]; len(got) != 1 || !strings.Contains(got[0], "is now active") {
+		t.Errorf("messages = %q, want only the activation message", got)
+	}
+}
+
+func TestNotificationService_Retries(t *testing.T) {
+	ctx := context.Background()
+	f := setupNotificationService(t, models.NotificationRule{MaxAttempts: 2, RetryDelay: time.Millisecond})
+	tx := &models.Transaction{ID: uuid.New(), UserID: 2, Amount: 3, Currency: models.CurrencyTON, ToAddress: "UQ-address"}
+
+	// Превышение лимита не расходует попытку и переносит сообщение на retry_after
+	f.bot.fail(http.StatusTooManyRequests, 1)
+	_ = f.notifications.WithdrawalSent(ctx, tx)
+	_ = f.notifications.ProcessOutbox(ctx)
+	notification := f.repo.Notifications()[0]
+	if notification.Attempts != 0 || notification.Status != models.NotificationStatusPending ||
+		time.Until(notification.NextAttemptAt) < 500*time.Millisecond {
+		t.Fatalf("after 429: attempts = %d, status = %s, want a pending message delayed by retry_after",
+			notification.Attempts, notification.Status)
+	}
+
+	// Временные ошибки повторяются до MaxAttempts
+	f.bot.fail(http.StatusInternalServerError, 0)
+	second := &models.Transaction{ID: uuid.New(), UserID: 2, Amount: 1, Currency: models.CurrencyTON}
+	_ = f.notifications.WithdrawalSent(ctx, second)
+	_ = f.notifications.ProcessOutbox(ctx)
+	if got := f.statuses(); got[1] != models.NotificationStatusPending {
+		t.Fatalf("after the first 500: status = %s, want pending", got[1])
+	}
+	time.Sleep(5 * time.Millisecond)
+	_ = f.notifications.ProcessOutbox(ctx)
+	if got := f.repo.Notifications()[1]; got.Status != models.NotificationStatusFailed || got.Attempts != 2 {
+		t.Errorf("after MaxAttempts: status = %s, attempts = %d, want failed after 2", got.Status, got.Attempts)
+	}
+
+	// Пользователь заблокировал бота: сообщение сразу помечается failed
+	f.bot.fail(http.StatusForbidden, 0)
+	_ = f.notifications.DepositCredited(ctx, &models.Transaction{ID: uuid.New(), UserID: 1, Amount: 1})
+	_ = f.notifications.ProcessOutbox(ctx)
+	if got := f.repo.Notifications()[2]; got.Status != models.NotificationStatusFailed || got.Attempts != 1 {
+		t.Errorf("after 403: status = %s, attempts = %d, want failed after 1", got.Status, got.Attempts)
+	}
+}
+
+func TestNotificationService_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	f := setupNotificationService(t, models.NotificationRule{RatePerSecond: 20})
+
+	for i := 0; i < 4; i++ {
+		_ = f.notifications.DepositCredited(ctx, &models.Transaction{ID: uuid.New(), UserID: 2, Amount: 1})
+	}
+	if err := f.notifications.ProcessOutbox(ctx); err != nil {
+		t.Fatalf("ProcessOutbox() error = %v", err)
+	}
+
+	if len(f.bot.sentAt) != 4 {
+		t.Fatalf("sent %d messages, want 4", len(f.bot.sentAt))
+	}
+	if elapsed := f.bot.sentAt[3].Sub(f.bot.sentAt[0]); elapsed < 150*time.Millisecond {
+		t.Errorf("4 messages sent in %s, want at least 150ms at 20 messages per second", elapsed)
+	}
+}
+
+func TestNotificationService_ExpiringLobbies(t *testing.T) {
+	ctx := context.Background()
+	f := setupNotificationService(t, models.NotificationRule{ExpiryNotice: 2 * time.Minute})
+
+	game := &models.Game{CreatorID: 2, Title: "Words"}
+	_ = f.games.Create(ctx, game)
+	now := time.Now()
+	expiring := &models.Lobby{GameID: game.ID, UserID: 2, MaxTries: 6, TriesUsed: 2, Status: models.LobbyStatusActive,
+		CreatedAt: now.Add(-4 * time.Minute), ExpiresAt: now.Add(90 * time.Second)}
+	// Лобби короче окна напоминания: игрок только что начал
+	short := &models.Lobby{GameID: game.ID, UserID: 3, MaxTries: 6, Status: models.LobbyStatusActive,
+		CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	later := &models.Lobby{GameID: game.ID, UserID: 1, MaxTries: 6, Status: models.LobbyStatusActive,
+		CreatedAt: now, ExpiresAt: now.Add(10 * time.Minute)}
+	for _, lobby := range []*models.Lobby{expiring, short, later} {
+		_ = f.lobbies.Create(ctx, lobby)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := f.notifications.ProcessExpiringLobbies(ctx); err != nil {
+			t.Fatalf("ProcessExpiringLobbies() error = %v", err)
+		}
+	}
+	_ = f.notifications.ProcessOutbox(ctx)
+
+	want := "Time is running out in \"Words\": 2 min left, 4 tries remaining."
+	if got := f.bot.texts["2"]; len(got) != 1 || got[0] != want {
+		t.Errorf("messages = %q, want [%q]", got, want)
+	}
+	if len(f.bot.texts["1"]) != 0 || len(f.bot.texts["3"]) != 0 {
+		t.Errorf("messages = %v, want a reminder for the expiring lobby only", f.bot.texts)
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+// Размер страницы списков с курсорной пагинацией
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// pageLimit приводит запрошенный размер страницы к допустимому
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"go.uber.org/zap"
+)
+
+// playerStatsMaxAttempts сколько попыток лобби просматривается при поиске стартового слова
+const playerStatsMaxAttempts = 100
+
+// PlayerStatsServiceImpl представляет собой реализацию PlayerStatsService
+type PlayerStatsServiceImpl struct {
+	statsRepo    models.PlayerStatsRepository
+	attemptRepo  models.AttemptRepository
+	achievements models.AchievementService
+	logger       *zap.Logger
+}
+
+// NewPlayerStatsService создает новый экземпляр PlayerStatsService.
+// Серии побед и дней с игрой берутся из achievements (nil - серии не возвращаются)
+func NewPlayerStatsService(
+	statsRepo models.PlayerStatsRepository,
+	attemptRepo models.AttemptRepository,
+	achievements models.AchievementService,
+) models.PlayerStatsService {
+	return &PlayerStatsServiceImpl{
+		statsRepo:    statsRepo,
+		attemptRepo:  attemptRepo,
+		achievements: achievements,
+		logger:       logger.GetLogger(zap.String("service", "player_stats")),
+	}
+}
+
+// RecordLobby учитывает рассчитанное лобби в агрегатах статистики игрока
+func (s *PlayerStatsServiceImpl) RecordLobby(ctx context.Context, lobby *models.Lobby, game *models.Game, finalStatus string, payout float64) error {
+	if lobby == nil || game == nil {
+		return errors.New("lobby and game are required")
+	}
+
+	// Лобби рассчитывается по событию, которое может быть доставлено позже: день и время решения
+	// считаются по моменту завершения лобби
+	now := lobby.UpdatedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	result := &models.PlayerResult{
+		UserID:    lobby.UserID,
+		Day:       models.StreakDay(now),
+		Currency:  game.Currency,
+		Status:    finalStatus,
+		BetAmount: lobby.BetAmount,
+		Payout:    payout,
+		TriesUsed: lobby.TriesUsed,
+		StartWord: s.startWord(ctx, lobby),
+	}
+
+	if result.Won() {
+		started := lobby.CreatedAt
+		if lobby.StartedAt != nil {
+			started = *lobby.StartedAt
+		}
+		if !started.IsZero() && now.After(started) {
+			result.SolveSeconds = now.Sub(started).Seconds()
+		}
+	}
+
+	return s.statsRepo.RecordResult(ctx, result)
+}
+
+// startWord возвращает первое слово игрока в лобби (пусто, если попыток не было)
+func (s *PlayerStatsServiceImpl) startWord(ctx context.Context, lobby *models.Lobby) string {
+	attempts, err := s.attemptRepo.GetByLobbyID(ctx, lobby.ID, playerStatsMaxAttempts, 0)
+	if err != nil {
+		s.logger.Warn("Failed to get lobby attempts", zap.String("lobby_id", lobby.ID.String()), zap.Error(err))
+		return ""
+	}
+
+	var first *models.Attempt
+	for _, attempt := range attempts {
+		if first == nil || attempt.CreatedAt.Before(first.CreatedAt) {
+			first = attempt
+		}
+	}
+	if first == nil {
+		return ""
+	}
+	return strings.ToLower(first.Word)
+}
+
+// GetStats возвращает статистику игрока за дни [from, to].
+// Нулевой from - с начала истории, нулевой to - по текущий день
+func (s *PlayerStatsServiceImpl) GetStats(ctx context.Context, userID uint64, from, to time.Time) (*models.PlayerStats, error) {
+	if userID == 0 {
+		return nil, errors.New("user ID cannot be zero")
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	to = models.StreakDay(to)
+	if !from.IsZero() {
+		from = models.StreakDay(from)
+		if from.After(to) {
+			return nil, errors.New("from must not be after to")
+		}
+	}
+	// Агрегаты хранятся по дням, правая граница выборки исключается
+	until := to.AddDate(0, 0, 1)
+
+	stats := &models.PlayerStats{
+		UserID:             userID,
+		To:                 to,
+		Profit:             []models.CurrencyProfit{},
+		ProfitSeries:       []models.ProfitPoint{},
+		FavoriteStartWords: []models.WordCount{},
+	}
+	if !from.IsZero() {
+		stats.From = &from
+	}
+
+	daily, err := s.statsRepo.GetDaily(ctx, userID, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	var solveSeconds float64
+	var timedSolves int
+	profit := make(map[string]*models.CurrencyProfit)
+	for _, day := range daily {
+		stats.Games += day.Games
+		stats.Wins += day.Wins
+		stats.CashOuts += day.CashOuts
+		solveSeconds += day.SolveSeconds
+		timedSolves += day.TimedSolves
+
+		total, ok := profit[day.Currency]
+		if !ok {
+			total = &models.CurrencyProfit{Currency: day.Currency}
+			profit[day.Currency] = total
+		}
+		total.Wagered += day.Wagered
+		total.PaidOut += day.PaidOut
+		total.Profit += day.PaidOut - day.Wagered
+
+		// Агрегаты упорядочены по дням, поэтому накопленная прибыль считается за один проход
+		stats.ProfitSeries = append(stats.ProfitSeries, models.ProfitPoint{
+			Day:        day.Day,
+			Currency:   day.Currency,
+			Profit:     day.PaidOut - day.Wagered,
+			Cumulative: total.Profit,
+		})
+	}
+	stats.Losses = stats.Games - stats.Wins - stats.CashOuts
+	if stats.Games > 0 {
+		stats.WinRate = float64(stats.Wins) / float64(stats.Games)
+	}
+	if timedSolves > 0 {
+		stats.AvgSolveSeconds = solveSeconds / float64(timedSolves)
+	}
+	for _, total := range profit {
+		stats.Profit = append(stats.Profit, *total)
+	}
+	sort.Slice(stats.Profit, func(i, j int) bool {
+		return stats.Profit[i].Currency < stats.Profit[j].Currency
+	})
+
+	if stats.GuessDistribution, err = s.statsRepo.GetGuessDistribution(ctx, userID, from, until); err != nil {
+		return nil, err
+	}
+
+	words, err := s.statsRepo.GetStartWords(ctx, userID, from, until, models.PlayerStatsFavoriteWords)
+	if err != nil {
+		return nil, err
+	}
+	if words != nil {
+		stats.FavoriteStartWords = words
+	}
+
+	if s.achievements != nil {
+		streak, err := s.achievements.GetStreak(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		stats.CurrentStreak = streak.WinStreak
+		stats.MaxStreak = streak.BestWinStreak
+		stats.MaxPlayStreak = streak.BestPlayStreak
+		// Серия дней прервана, если игрок не играл ни сегодня, ни вчера
+		today := models.StreakDay(time.Now())
+		if streak.LastPlayedOn != nil && !models.StreakDay(*streak.LastPlayedOn).Before(today.AddDate(0, 0, -1)) {
+			stats.PlayStreak = streak.PlayStreak
+		}
+	}
+
+	return stats, nil
+}
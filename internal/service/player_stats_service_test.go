@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func setupPlayerStatsService(t *testing.T) (*mocks.MockPlayerStatsRepository, *mocks.MockAttemptRepository, models.AchievementService, models.PlayerStatsService) {
+	t.Helper()
+	_, achievements := setupAchievementService(t, models.AchievementRule{})
+	statsRepo := mocks.NewMockPlayerStatsRepository()
+	attemptRepo := mocks.NewMockAttemptRepository()
+	return statsRepo, attemptRepo, achievements, NewPlayerStatsService(statsRepo, attemptRepo, achievements)
+}
+
+// finishLobby учитывает в статистике лобби игрока 1 с попытками words
+func finishLobby(t *testing.T, attemptRepo *mocks.MockAttemptRepository, stats models.PlayerStatsService, status string, bet, payout float64, words ...string) {
+	t.Helper()
+	ctx := context.Background()
+	started := time.Now().Add(-90 * time.Second)
+	lobby := &models.Lobby{ID: uuid.New(), GameID: uuid.New(), UserID: 1, BetAmount: bet, TriesUsed: len(words),
+		StartedAt: &started, CreatedAt: started}
+	// Попытки добавляются в обратном порядке: стартовое слово определяется по времени, а не по порядку хранения
+	for i := len(words) - 1; i >= 0; i-- {
+		_ = attemptRepo.Create(ctx, &models.Attempt{ID: uuid.New(), GameID: lobby.GameID, LobbyID: &lobby.ID, UserID: 1,
+			Word: words[i], CreatedAt: started.Add(time.Duration(i) * time.Second)})
+	}
+	game := &models.Game{ID: lobby.GameID, Currency: models.CurrencyTON}
+	if err := stats.RecordLobby(ctx, lobby, game, status, payout); err != nil {
+		t.Fatalf("RecordLobby() error = %v", err)
+	}
+}
+
+func TestPlayerStatsService_RecordLobby(t *testing.T) {
+	ctx := context.Background()
+	_, attemptRepo, _, stats := setupPlayerStatsService(t)
+
+	finishLobby(t, attemptRepo, stats, models.LobbyStatusSuccess, 1, 2.5, "СЛОВО", "сокол", "осень")
+	finishLobby(t, attemptRepo, stats, models.LobbyStatusSuccess, 1, 3, "слово", "весна")
+	finishLobby(t, attemptRepo, stats, models.LobbyStatusSuccess, 2, 5, "весна", "лампа", "книга")
+	finishLobby(t, attemptRepo, stats, models.LobbyStatusFailedTries, 2, 0, "сокол", "мечта")
+	finishLobby(t, attemptRepo, stats, models.LobbyStatusCashedOut, 1, 0.5, "слово")
+
+	got, err := stats.GetStats(ctx, 1, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+
+	if got.Games != 5 || got.Wins != 3 || got.Losses != 1 || got.CashOuts != 1 {
+		t.Errorf("games/wins/losses/cash-outs = %d/%d/%d/%d, want 5/3/1/1", got.Games, got.Wins, got.Losses, got.CashOuts)
+	}
+	if math.Abs(got.WinRate-0.6) > 1e-9 {
+		t.Errorf("win rate = %v, want 0.6", got.WinRate)
+	}
+	if got.GuessDistribution[2] != 1 || got.GuessDistribution[3] != 2 || len(got.GuessDistribution) != 2 {
+		t.Errorf("guess distribution = %v, want {2: 1, 3: 2}", got.GuessDistribution)
+	}
+	if got.AvgSolveSeconds < 90 || got.AvgSolveSeconds > 100 {
+		t.Errorf("average solve time = %v, want about 90 seconds", got.AvgSolveSeconds)
+	}
+
+	if len(got.FavoriteStartWords) != 3 || got.FavoriteStartWords[0] != (models.WordCount{Word: "слово", Count: 3}) {
+		t.Errorf("favorite start words = %+v, want слово used 3 times first", got.FavoriteStartWords)
+	}
+
+	// Ставки 7, выплаты 11
+	if len(got.Profit) != 1 || math.Abs(got.Profit[0].Profit-4) > 1e-9 || math.Abs(got.Profit[0].Wagered-7) > 1e-9 {
+		t.Errorf("profit = %+v, want TON profit 4 on 7 wagered", got.Profit)
+	}
+	if got.From != nil || !got.To.Equal(models.StreakDay(time.Now())) {
+		t.Errorf("range = %v..%v, want all history up to today", got.From, got.To)
+	}
+}
+
+func TestPlayerStatsService_GetStatsRange(t *testing.T) {
+	ctx := context.Background()
+	statsRepo, _, _, stats := setupPlayerStatsService(t)
+
+	today := models.StreakDay(time.Now())
+	record := func(daysAgo int, currency, status string, bet, payout float64, tries int) {
+		_ = statsRepo.RecordResult(ctx, &models.PlayerResult{UserID: 1, Day: today.AddDate(0, 0, -daysAgo),
+			Currency: currency, Status: status, BetAmount: bet, Payout: payout, TriesUsed: tries})
+	}
+	record(10, models.CurrencyTON, models.LobbyStatusSuccess, 1, 3, 1)
+	record(2, models.CurrencyTON, models.LobbyStatusFailedTries, 1, 0, 6)
+	record(2, models.CurrencyUSDT, models.LobbyStatusSuccess, 5, 8, 4)
+	record(1, models.CurrencyTON, models.LobbyStatusSuccess, 1, 2, 2)
+	record(0, models.CurrencyTON, models.LobbyStatusSuccess, 1, 2, 3)
+	// Чужая статистика не учитывается
+	_ = statsRepo.RecordResult(ctx, &models.PlayerResult{UserID: 2, Day: today, Currency: models.CurrencyTON,
+		Status: models.LobbyStatusSuccess, BetAmount: 1, Payout: 2, TriesUsed: 1})
+
+	got, err := stats.GetStats(ctx, 1, today.AddDate(0, 0, -2), today.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if got.Games != 3 || got.Wins != 2 {
+		t.Errorf("games/wins = %d/%d, want 3/2", got.Games, got.Wins)
+	}
+	if got.GuessDistribution[1] != 0 || got.GuessDistribution[2] != 1 || got.GuessDistribution[4] != 1 {
+		t.Errorf("guess distribution = %v, want wins in 2 and 4 tries only", got.GuessDistribution)
+	}
+
+	want := []models.ProfitPoint{
+		{Day: today.AddDate(0, 0, -2), Currency: models.CurrencyTON, Profit: -1, Cumulative: -1},
+		{Day: today.AddDate(0, 0, -2), Currency: models.CurrencyUSDT, Profit: 3, Cumulative: 3},
+		{Day: today.AddDate(0, 0, -1), Currency: models.CurrencyTON, Profit: 1, Cumulative: 0},
+	}
+	if len(got.ProfitSeries) != len(want) {
+		t.Fatalf("profit series = %+v, want %d points", got.ProfitSeries, len(want))
+	}
+	for i, point := range got.ProfitSeries {
+		if !point.Day.Equal(want[i].Day) || point.Currency != want[i].Currency ||
+			math.Abs(point.Profit-want[i].Profit) > 1e-9 || math.Abs(point.Cumulative-want[i].Cumulative) > 1e-9 {
+			t.Errorf("profit point %d = %+v, want %+v", i, point, want[i])
+		}
+	}
+	if len(got.Profit) != 2 || got.Profit[0].Currency != models.CurrencyTON || got.Profit[1].Currency != models.CurrencyUSDT {
+		t.Errorf("profit = %+v, want TON and USDT totals", got.Profit)
+	}
+
+	if _, err := stats.GetStats(ctx, 1, today, today.AddDate(0, 0, -1)); err == nil {
+		t.Error("GetStats() with from after to should fail")
+	}
+}
+
+func TestPlayerStatsService_Streaks(t *testing.T) {
+	ctx := context.Background()
+	_, _, achievements, stats := setupPlayerStatsService(t)
+
+	for _, status := range []string{models.LobbyStatusSuccess, models.LobbyStatusSuccess, models.LobbyStatusSuccess,
+		models.LobbyStatusFailedTries, models.LobbyStatusSuccess} {
+		if err := achievements.HandleEvent(ctx, &models.AchievementEvent{Type: models.AchievementEventLobbyFinished,
+			UserID: 1, CreatorID: 2, LobbyID: uuid.New(), Status: status, At: time.Now()}); err != nil {
+			t.Fatalf("HandleEvent() error = %v", err)
+		}
+	}
+
+	got, err := stats.GetStats(ctx, 1, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if got.CurrentStreak != 1 || got.MaxStreak != 3 || got.PlayStreak != 1 || got.MaxPlayStreak != 1 {
+		t.Errorf("streaks = %d/%d, play %d/%d, want 1/3, play 1/1",
+			got.CurrentStreak, got.MaxStreak, got.PlayStreak, got.MaxPlayStreak)
+	}
+}
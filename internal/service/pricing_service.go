@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Параметры оценки коэффициентов
+const (
+	pricingPriorHazard       = 0.15 // Априорная вероятность угадать слово за одну попытку
+	pricingPriorWeight       = 20.0 // Вес априорной оценки (в "виртуальных" играх)
+	pricingMinutesPerTry     = 0.5  // Время на попытку, при котором лимит времени не снижает шансы игрока
+	pricingNeutralDifficulty = 0.5  // Сложность слова, если словаря нет
+	pricingTargetEdge        = 0.1  // Доля справедливого мультипликатора, которую рекомендация оставляет создателю
+	pricingMinMultiplier     = 1.0  // Минимально допустимый мультипликатор
+	pricingMinProbability    = 0.01 // Минимальная вероятность
+	pricingMaxProbability    = 0.99 // Максимальная вероятность
+)
+
+// PricingServiceImpl представляет собой реализацию PricingService
+type PricingServiceImpl struct {
+	historyRepo models.HistoryRepository
+	dict        *dictionary.Dictionary
+	commission  models.CommissionService
+	logger      *zap.Logger
+}
+
+// NewPricingService создает новый экземпляр PricingService.
+// dict используется для оценки сложности слова (может быть nil - слово считается средним)
+func NewPricingService(historyRepo models.HistoryRepository, dict *dictionary.Dictionary, commission models.CommissionService) models.PricingService {
+	return &PricingServiceImpl{
+		historyRepo: historyRepo,
+		dict:        dict,
+		commission:  commission,
+		logger:      logger.GetLogger(zap.String("service", "pricing")),
+	}
+}
+
+// AnalyzeWord оценивает сложность загаданного слова по словарю
+func (s *PricingServiceImpl) AnalyzeWord(word string) (*models.WordDifficulty, error) {
+	if s.dict == nil {
+		return nil, errors.New("dictionary is not available")
+	}
+
+	analysis := s.dict.Analyze(word)
+	if analysis.Word == "" {
+		return nil, errors.New("word cannot be empty")
+	}
+
+	return &models.WordDifficulty{
+		Word:            analysis.Word,
+		Length:          len([]rune(analysis.Word)),
+		Score:           analysis.Score,
+		Level:           analysis.Level,
+		InDictionary:    analysis.InDictionary,
+		LetterRarity:    analysis.LetterRarity,
+		RepeatedLetters: analysis.RepeatedLetters,
+		Neighbors:       analysis.Neighbors,
+		SolverTries:     analysis.SolverTries,
+	}, nil
+}
+
+// EstimateOdds оценивает вероятность победы игрока и справедливый мультипликатор игры.
+// Доля побед в играх с такими же правилами переводится в вероятность угадать за одну попытку,
+// которая корректируется на сложность слова и лимит времени, а затем уточняется результатами самой игры
+func (s *PricingServiceImpl) EstimateOdds(ctx context.Context, game *models.Game) (*models.GameOdds, error) {
+	if game == nil || game.MaxTries <= 0 {
+		return nil, errors.New("invalid game rules")
+	}
+
+	platformTotal, platformWins, err := s.historyRepo.CountResultsByRules(ctx, game.Length, game.MaxTries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get platform results: %w", err)
+	}
+
+	maxTries := float64(game.MaxTries)
+	prior := 1 - math.Pow(1-pricingPriorHazard, maxTries)
+	platformRate := clampProbability((float64(platformWins) + prior*pricingPriorWeight) / (float64(platformTotal) + pricingPriorWeight))
+
+	difficulty := wordDifficulty(s.dict, game)
+	hazard := 1 - math.Pow(1-platformRate, 1/maxTries)
+	hazard *= 1.5 - difficulty
+	hazard *= timeLimitFactor(game.TimeLimit, game.MaxTries)
+	winProbability := 1 - math.Pow(1-clampProbability(hazard), maxTries)
+
+	var gameTotal int
+	if game.ID != uuid.Nil {
+		var gameWins int
+		gameTotal, gameWins, err = s.historyRepo.CountGameResults(ctx, game.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get game results: %w", err)
+		}
+		winProbability = (float64(gameWins) + winProbability*pricingPriorWeight) / (float64(gameTotal) + pricingPriorWeight)
+	}
+
+	odds := calculateGameOdds(clampProbability(winProbability), game.RewardMultiplier, game.MaxTries, s.commission.GetGameRate(ctx, game))
+	odds.WordDifficulty = difficulty
+	odds.PlatformResults = platformTotal
+	odds.GameResults = gameTotal
+
+	return odds, nil
+}
+
+// calculateGameOdds рассчитывает ожидаемые доходы сторон на единицу ставки.
+// Игрок побеждает на каждой попытке с одинаковой вероятностью, выплата растёт с бонусом за скорость;
+// проигранная ставка за вычетом комиссии уходит в пул создателя
+func calculateGameOdds(winProbability, multiplier float64, maxTries int, commissionRate float64) *models.GameOdds {
+	hazard := 1 - math.Pow(1-winProbability, 1/float64(maxTries))
+
+	// Вероятность победы, взвешенная бонусом за скорость
+	var bonusWeight float64
+	survive := 1.0
+	for tries := 1; tries <= maxTries; tries++ {
+		bonusWeight += survive * hazard * models.RewardTriesBonus(tries, maxTries)
+		survive *= 1 - hazard
+	}
+
+	lossShare := (1 - winProbability) * (1 - commissionRate)
+	payout := multiplier * bonusWeight * (1 - commissionRate)
+
+	odds := &models.GameOdds{
+		WinProbability: winProbability,
+		Multiplier:     multiplier,
+		ExpectedPayout: payout,
+		CreatorEdge:    lossShare - payout,
+		HouseEdge:      1 - lossShare,
+	}
+
+	if bonusWeight > 0 {
+		odds.FairMultiplier = (1 - winProbability) / bonusWeight
+	}
+	odds.SuggestedMultiplier = math.Max(pricingMinMultiplier, math.Floor(odds.FairMultiplier*(1-pricingTargetEdge)*100)/100)
+
+	switch {
+	case odds.FairMultiplier < pricingMinMultiplier:
+		odds.Warning = "players are expected to win too often for any multiplier to be profitable: " +
+			"consider fewer tries, a shorter time limit or a harder word"
+	case odds.CreatorEdge < 0:
+		odds.Warning = fmt.Sprintf("multiplier %.2f exceeds the fair multiplier %.2f: the creator is expected to lose %.1f%% of each bet",
+			multiplier, odds.FairMultiplier, -odds.CreatorEdge*100)
+	}
+
+	return odds
+}
+
+// wordDifficulty возвращает сложность слова игры (0 - лёгкое, 1 - сложное): сохранённую при создании
+// или рассчитанную по словарю
+func wordDifficulty(dict *dictionary.Dictionary, game *models.Game) float64 {
+	if game.DifficultyScore > 0 {
+		return game.DifficultyScore / 100
+	}
+	if dict == nil || game.Word == "" {
+		return pricingNeutralDifficulty
+	}
+	return dict.Analyze(game.Word).Score / 100
+}
+
+// timeLimitFactor снижает шансы игрока, если на попытку приходится меньше pricingMinutesPerTry минут
+func timeLimitFactor(timeLimit, maxTries int) float64 {
+	if timeLimit <= 0 || maxTries <= 0 {
+		return 1
+	}
+	return math.Min(1, float64(timeLimit)/(float64(maxTries)*pricingMinutesPerTry))
+}
+
+// clampProbability ограничивает вероятность допустимым диапазоном
+func clampProbability(p float64) float64 {
+	return math.Max(pricingMinProbability, math.Min(p, pricingMaxProbability))
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCalculateGameOdds(t *testing.T) {
+	fair := calculateGameOdds(0.3, 1, 6, 0.05).FairMultiplier
+
+	tests := []struct {
+		name        string
+		probability float64
+		multiplier  float64
+		wantEdge    int // знак ожидаемого дохода создателя
+		wantWarning bool
+	}{
+		{name: "справедливый мультипликатор", probability: 0.3, multiplier: fair, wantEdge: 0},
+		{name: "ниже справедливого", probability: 0.3, multiplier: fair * 0.8, wantEdge: 1},
+		{name: "выше справедливого", probability: 0.3, multiplier: fair * 1.2, wantEdge: -1, wantWarning: true},
+		{name: "игрок почти всегда выигрывает", probability: 0.95, multiplier: 1, wantEdge: -1, wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			odds := calculateGameOdds(tt.probability, tt.multiplier, 6, 0.05)
+			switch {
+			case tt.wantEdge == 0 && math.Abs(odds.CreatorEdge) > 1e-9,
+				tt.wantEdge > 0 && odds.CreatorEdge <= 0,
+				tt.wantEdge < 0 && odds.CreatorEdge >= 0:
+				t.Errorf("CreatorEdge = %v, want sign %d", odds.CreatorEdge, tt.wantEdge)
+			}
+			if (odds.Warning != "") != tt.wantWarning {
+				t.Errorf("Warning = %q, want warning %v", odds.Warning, tt.wantWarning)
+			}
+			if odds.SuggestedMultiplier < pricingMinMultiplier {
+				t.Errorf("SuggestedMultiplier = %v, want >= %v", odds.SuggestedMultiplier, pricingMinMultiplier)
+			}
+		})
+	}
+}
+
+func TestWordDifficulty(t *testing.T) {
+	dict := dictionary.New([]string{"arose", "stare", "crane", "slate", "jazzy", "fuzzy"})
+
+	if got := wordDifficulty(nil, &models.Game{Word: "arose"}); got != pricingNeutralDifficulty {
+		t.Errorf("wordDifficulty() without dictionary = %v, want %v", got, pricingNeutralDifficulty)
+	}
+	if got := wordDifficulty(dict, &models.Game{Word: "arose", DifficultyScore: 80}); got != 0.8 {
+		t.Errorf("wordDifficulty() with stored score = %v, want 0.8", got)
+	}
+	if easy, hard := wordDifficulty(dict, &models.Game{Word: "stare"}), wordDifficulty(dict, &models.Game{Word: "jazzy"}); easy >= hard {
+		t.Errorf("wordDifficulty(stare) = %v, want less than wordDifficulty(jazzy) = %v", easy, hard)
+	}
+}
+
+func TestPricingService_EstimateOdds(t *testing.T) {
+	ctx := context.Background()
+	gameRepo := mocks.NewMockGameRepository()
+	historyRepo := mocks.NewMockHistoryRepository()
+	historyRepo.SetGameRepository(gameRepo)
+	pricing := NewPricingService(historyRepo, nil, newTestCommissionService())
+
+	game := &models.Game{ID: uuid.New(), Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5, RewardMultiplier: 2}
+	_ = gameRepo.Create(ctx, game)
+
+	before, err := pricing.EstimateOdds(ctx, game)
+	if err != nil {
+		t.Fatalf("EstimateOdds() error = %v", err)
+	}
+
+	// Игроки в основном проигрывают - справедливый мультипликатор растёт
+	for i := 0; i < 30; i++ {
+		status := models.HistoryStatusCreatorWin
+		if i%10 == 0 {
+			status = models.HistoryStatusPlayerWin
+		}
+		_ = historyRepo.Create(ctx, &models.History{GameID: game.ID, LobbyID: uuid.New(), Status: status})
+	}
+
+	after, err := pricing.EstimateOdds(ctx, game)
+	if err != nil {
+		t.Fatalf("EstimateOdds() error = %v", err)
+	}
+	if after.PlatformResults != 30 || after.GameResults != 30 {
+		t.Errorf("results = %d/%d, want 30/30", after.PlatformResults, after.GameResults)
+	}
+	if after.WinProbability >= before.WinProbability || after.FairMultiplier <= before.FairMultiplier {
+		t.Errorf("odds after losses = %+v, before = %+v", after, before)
+	}
+
+	// Короткий лимит времени снижает шансы игрока
+	rushed := *game
+	rushed.ID = uuid.Nil
+	rushed.TimeLimit = 1
+	fast, err := pricing.EstimateOdds(ctx, &rushed)
+	if err != nil {
+		t.Fatalf("EstimateOdds() error = %v", err)
+	}
+	rushed.TimeLimit = 5
+	slow, _ := pricing.EstimateOdds(ctx, &rushed)
+	if fast.WinProbability >= slow.WinProbability {
+		t.Errorf("win probability with 1 minute = %v, want less than %v", fast.WinProbability, slow.WinProbability)
+	}
+}
+
+func TestGameService_OddsAutoAdjust(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	historyRepo := mocks.NewMockHistoryRepository()
+	historyRepo.SetGameRepository(gameRepo)
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+		Pricing:     NewPricingService(historyRepo, nil, newTestCommissionService()),
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "creator"})
+	game := &models.Game{
+		CreatorID: 1, Title: "игра", Word: "слово", Difficulty: "medium", MaxTries: 6, TimeLimit: 5,
+		MinBet: 1, MaxBet: 2, RewardMultiplier: 20, Currency: models.CurrencyTON,
+	}
+	if err := gameService.CreateGame(ctx, game); err != nil {
+		t.Fatalf("CreateGame() error = %v", err)
+	}
+	if game.Odds == nil || game.Odds.CreatorEdge >= 0 || game.Odds.Warning == "" {
+		t.Errorf("odds for an overpriced game = %+v, want negative edge with warning", game.Odds)
+	}
+
+	if _, err := gameService.SetOddsAutoAdjust(ctx, game.ID, 1, models.OddsAdjustRule{MinMultiplier: 3, MaxMultiplier: 2}); err == nil {
+		t.Error("SetOddsAutoAdjust() with max below min should fail")
+	}
+	if _, err := gameService.SetOddsAutoAdjust(ctx, game.ID, 2, models.OddsAdjustRule{MinMultiplier: 1, MaxMultiplier: 5}); err == nil {
+		t.Error("SetOddsAutoAdjust() by another user should fail")
+	}
+	game, err := gameService.SetOddsAutoAdjust(ctx, game.ID, 1, models.OddsAdjustRule{MinMultiplier: 1.5, MaxMultiplier: 5})
+	if err != nil {
+		t.Fatalf("SetOddsAutoAdjust() error = %v", err)
+	}
+	game.Status = models.GameStatusActive
+	game.RewardPoolTon = 100
+
+	// Без достаточной истории мультипликатор не меняется
+	if err := gameService.ProcessOddsAdjustments(ctx); err != nil {
+		t.Fatalf("ProcessOddsAdjustments() error = %v", err)
+	}
+	if game.RewardMultiplier != 20 {
+		t.Errorf("multiplier without history = %v, want 20", game.RewardMultiplier)
+	}
+
+	// Игроки часто выигрывают - мультипликатор снижается до нижней границы
+	for i := 0; i < oddsMinAdjustResults; i++ {
+		_ = historyRepo.Create(ctx, &models.History{GameID: game.ID, LobbyID: uuid.New(), Status: models.HistoryStatusPlayerWin})
+	}
+	if err := gameService.ProcessOddsAdjustments(ctx); err != nil {
+		t.Fatalf("ProcessOddsAdjustments() error = %v", err)
+	}
+	if game.RewardMultiplier != 1.5 {
+		t.Errorf("multiplier after player wins = %v, want 1.5", game.RewardMultiplier)
+	}
+
+	// Лобби сохраняет мультипликатор на момент ставки
+	lobby := &models.Lobby{BetAmount: 2, PotentialReward: 40}
+	if got := lobby.Multiplier(game.RewardMultiplier); got != 20 {
+		t.Errorf("lobby multiplier = %v, want 20", got)
+	}
+}
+
+func TestGameService_WordDifficulty(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	dict := dictionary.New([]string{"cater", "hater", "later", "water", "mater", "rater", "crane", "slate", "stare"})
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    gameRepo,
+		UserService: userService,
+		TxService:   txService,
+		Commission:  newTestCommissionService(),
+		Pricing:     NewPricingService(mocks.NewMockHistoryRepository(), dict, newTestCommissionService()),
+	})
+
+	analysis, err := gameService.AnalyzeWord(ctx, "hater")
+	if err != nil {
+		t.Fatalf("AnalyzeWord() error = %v", err)
+	}
+	if analysis.Length != 5 || analysis.Neighbors != 5 || analysis.Score <= 0 {
+		t.Errorf("AnalyzeWord(hater) = %+v", analysis)
+	}
+	if _, err := gameService.AnalyzeWord(ctx, " "); err == nil {
+		t.Error("AnalyzeWord() with empty word should fail")
+	}
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "creator"})
+	newGame := func(word, difficulty string) *models.Game {
+		game := &models.Game{
+			CreatorID: 1, Title: "игра", Word: word, Difficulty: difficulty, MaxTries: 6, TimeLimit: 5,
+			MinBet: 1, MaxBet: 2, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		}
+		if err := gameService.CreateGame(ctx, game); err != nil {
+			t.Fatalf("CreateGame() error = %v", err)
+		}
+		return game
+	}
+
+	// Без сложности игра получает уровень по оценке слова, выбранная создателем сложность сохраняется
+	hard := newGame("hater", "")
+	if hard.DifficultyScore != analysis.Score || hard.Difficulty != analysis.Level {
+		t.Errorf("game difficulty = %v/%q, want %v/%q", hard.DifficultyScore, hard.Difficulty, analysis.Score, analysis.Level)
+	}
+	easy := newGame("crane", "hard")
+	if easy.Difficulty != "hard" || easy.DifficultyScore >= hard.DifficultyScore {
+		t.Errorf("game difficulty = %v/%q, want hard with score below %v", easy.DifficultyScore, easy.Difficulty, hard.DifficultyScore)
+	}
+
+	for _, game := range []*models.Game{hard, easy} {
+		game.Status = models.GameStatusActive
+		game.RewardPoolTon = 100
+	}
+
+	page, err := gameService.SearchGames(ctx, models.GameSearchFilter{MaxBet: 10, MinDifficultyScore: hard.DifficultyScore}, 10, "")
+	if err != nil {
+		t.Fatalf("SearchGames() error = %v", err)
+	}
+	if len(page.Games) != 1 || page.Games[0].ID != hard.ID {
+		t.Errorf("SearchGames() by min score = %d games, want only the hard word", len(page.Games))
+	}
+
+	page, _ = gameService.SearchGames(ctx, models.GameSearchFilter{MaxBet: 10, MaxDifficultyScore: easy.DifficultyScore}, 10, "")
+	if len(page.Games) != 1 || page.Games[0].ID != easy.ID {
+		t.Errorf("SearchGames() by max score = %d games, want only the easy word", len(page.Games))
+	}
+}
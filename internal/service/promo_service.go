@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Параметры промокодов
+const (
+	defaultPromoWagerMultiplier = 1   // Отыгрыш по умолчанию: поставить сумму бонуса один раз
+	maxPromoWagerMultiplier     = 100 // Максимальный множитель отыгрыша
+)
+
+// promoCodePattern допустимый формат промокода
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// PromoServiceImpl представляет собой реализацию PromoService
+type PromoServiceImpl struct {
+	promoRepo          models.PromoRepository
+	userRepo           models.UserRepository
+	transactionService models.TransactionService
+	transactor         models.Transactor
+	logger             *zap.Logger
+}
+
+// NewPromoService создает новый экземпляр PromoService
+func NewPromoService(
+	promoRepo models.PromoRepository,
+	userRepo models.UserRepository,
+	transactionService models.TransactionService,
+	transactor models.Transactor,
+) models.PromoService {
+	return &PromoServiceImpl{
+		promoRepo:          promoRepo,
+		userRepo:           userRepo,
+		transactionService: transactionService,
+		transactor:         transactor,
+		logger:             logger.GetLogger(zap.String("service", "promo")),
+	}
+}
+
+// normalizePromoCode приводит промокод к каноническому виду
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CreatePromoCode проверяет и создает промокод.
+// Бесплатная ставка отыгрывается одной ставкой на сумму бонуса
+func (s *PromoServiceImpl) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	if promo == nil {
+		return errors.New("promo code is nil")
+	}
+
+	promo.Code = normalizePromoCode(promo.Code)
+	if !promoCodePattern.MatchString(promo.Code) {
+		return errors.New("code must be 3-32 characters: letters, digits, '_' or '-'")
+	}
+	if promo.Currency != models.CurrencyTON && promo.Currency != models.CurrencyUSDT {
+		return errors.New("invalid currency")
+	}
+	if promo.Amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+
+	switch promo.Type {
+	case "", models.PromoTypeBonus:
+		promo.Type = models.PromoTypeBonus
+		if promo.WagerMultiplier == 0 {
+			promo.WagerMultiplier = defaultPromoWagerMultiplier
+		}
+		if promo.WagerMultiplier < 1 || promo.WagerMultiplier > maxPromoWagerMultiplier {
+			return fmt.Errorf("wager_multiplier must be between 1 and %d", maxPromoWagerMultiplier)
+		}
+	case models.PromoTypeFreeBet:
+		promo.WagerMultiplier = 1
+	default:
+		return errors.New("type must be bonus or free_bet")
+	}
+
+	if promo.MaxRedemptions < 0 {
+		return errors.New("max_redemptions cannot be negative")
+	}
+	if promo.PerUserLimit <= 0 {
+		promo.PerUserLimit = 1
+	}
+	if promo.ExpiresAt != nil && !promo.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+
+	promo.Redemptions = 0
+	promo.Active = true
+
+	if err := s.promoRepo.Create(ctx, promo); err != nil {
+		return err
+	}
+
+	s.logger.Info("Promo code created",
+		zap.String("code", promo.Code),
+		zap.String("type", promo.Type),
+		zap.Float64("amount", promo.Amount),
+		zap.String("currency", promo.Currency),
+		zap.Uint64("created_by", promo.CreatedBy))
+	return nil
+}
+
+// GetPromoCodes возвращает промокоды
+func (s *PromoServiceImpl) GetPromoCodes(ctx context.Context, limit, offset int) ([]*models.PromoCode, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.promoRepo.GetAll(ctx, limit, offset)
+}
+
+// SetPromoCodeActive включает или выключает промокод
+func (s *PromoServiceImpl) SetPromoCodeActive(ctx context.Context, id uuid.UUID, active bool) error {
+	return s.promoRepo.SetActive(ctx, id, active)
+}
+
+// RedeemPromoCode активирует промокод и зачисляет бонус на бонусный баланс пользователя.
+// Лимиты проверяются атомарно при записи активации, активация и зачисление бонуса проводятся в одной транзакции
+func (s *PromoServiceImpl) RedeemPromoCode(ctx context.Context, userID uint64, code string) (*models.PromoRedemption, error) {
+	code = normalizePromoCode(code)
+	if code == "" {
+		return nil, errors.New("code is required")
+	}
+	if _, err := s.userRepo.GetByTelegramID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	redemption := &models.PromoRedemption{UserID: userID}
+	var promo *models.PromoCode
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		var err error
+		promo, err = s.promoRepo.Redeem(ctx, code, redemption)
+		if err != nil {
+			return err
+		}
+
+		if err := s.userRepo.UpdateBonusBalance(ctx, userID, redemption.Currency, redemption.Amount, redemption.Wager); err != nil {
+			return fmt.Errorf("failed to credit bonus: %w", err)
+		}
+
+		err = s.transactionService.CreateTransaction(ctx, &models.Transaction{
+			UserID:      userID,
+			Type:        models.TransactionTypeBonus,
+			Amount:      redemption.Amount,
+			Currency:    redemption.Currency,
+			Status:      models.TransactionStatusCompleted,
+			Description: fmt.Sprintf("Promo code %s (%s)", promo.Code, promo.Type),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record bonus transaction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Promo code redeemed",
+		zap.String("code", promo.Code),
+		zap.Uint64("user_id", userID),
+		zap.Float64("amount", redemption.Amount),
+		zap.Float64("wager", redemption.Wager))
+
+	return redemption, nil
+}
+
+// GetRedemptions возвращает активации промокодов пользователем
+func (s *PromoServiceImpl) GetRedemptions(ctx context.Context, userID uint64, limit, offset int) ([]*models.PromoRedemption, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.promoRepo.GetRedemptionsByUser(ctx, userID, limit, offset)
+}
+
+// ChargeBet списывает в счёт ставки лобби бонусные средства и запоминает бонусную часть ставки.
+// Списание и бонусная ставка записываются в одной транзакции
+func (s *PromoServiceImpl) ChargeBet(ctx context.Context, lobby *models.Lobby) (float64, error) {
+	user, err := s.userRepo.GetByTelegramID(ctx, lobby.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if user.GetBonusBalance(lobby.Currency) <= 0 {
+		return 0, nil
+	}
+
+	var spent float64
+	err = withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		var err error
+		spent, err = s.userRepo.SpendBonusBalance(ctx, lobby.UserID, lobby.Currency, lobby.BetAmount)
+		if err != nil {
+			return fmt.Errorf("failed to spend bonus balance: %w", err)
+		}
+		if spent <= 0 {
+			return nil
+		}
+
+		return s.promoRepo.CreateBonusStake(ctx, &models.BonusStake{
+			LobbyID:     lobby.ID,
+			UserID:      lobby.UserID,
+			Currency:    lobby.Currency,
+			BetAmount:   lobby.BetAmount,
+			BonusAmount: spent,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return max(spent, 0), nil
+}
+
+// RefundBet возвращает бонусную часть ставки, если лобби не удалось создать
+func (s *PromoServiceImpl) RefundBet(ctx context.Context, lobby *models.Lobby, bonusAmount float64) error {
+	if bonusAmount <= 0 {
+		return nil
+	}
+	return withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if err := s.promoRepo.DeleteBonusStake(ctx, lobby.ID); err != nil {
+			return err
+		}
+		return s.userRepo.UpdateBonusBalance(ctx, lobby.UserID, lobby.Currency, bonusAmount, 0)
+	})
+}
+
+// SettleBet засчитывает ставку в отыгрыш и зачисляет на бонусный баланс долю выплаты по бонусной части ставки.
+// Когда оборот отыгран, бонусный баланс переводится в основной. Вызывается в транзакции выплаты по лобби
+func (s *PromoServiceImpl) SettleBet(ctx context.Context, lobby *models.Lobby, reward float64) (float64, error) {
+	user, err := s.userRepo.GetByTelegramID(ctx, lobby.UserID)
+	if err != nil {
+		return reward, err
+	}
+
+	realReward := reward
+	err = withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		if user.GetWagerRemaining(lobby.Currency) > 0 {
+			if err := s.userRepo.UpdateBonusBalance(ctx, lobby.UserID, lobby.Currency, 0, -lobby.BetAmount); err != nil {
+				return fmt.Errorf("failed to record wagering progress: %w", err)
+			}
+		}
+
+		stake, err := s.promoRepo.SettleBonusStake(ctx, lobby.ID)
+		if err != nil {
+			return err
+		}
+
+		if stake != nil && reward > 0 {
+			bonusReward := stake.BonusShare(reward)
+			if err := s.userRepo.UpdateBonusBalance(ctx, lobby.UserID, lobby.Currency, bonusReward, 0); err != nil {
+				return fmt.Errorf("failed to credit bonus winnings: %w", err)
+			}
+			realReward -= bonusReward
+
+			err = s.recordTransaction(ctx, &models.Transaction{
+				UserID:      lobby.UserID,
+				Type:        models.TransactionTypeBonus,
+				Amount:      bonusReward,
+				Currency:    lobby.Currency,
+				GameID:      &lobby.GameID,
+				GameShortID: lobby.GameShortID,
+				LobbyID:     &lobby.ID,
+				Description: "Winnings on bonus stake",
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.convertBonus(ctx, lobby.UserID, lobby.Currency)
+	})
+	if err != nil {
+		return reward, err
+	}
+
+	return realReward, nil
+}
+
+// convertBonus переводит бонусный баланс в основной, если оборот ставок отыгран
+func (s *PromoServiceImpl) convertBonus(ctx context.Context, userID uint64, currency string) error {
+	converted, err := s.userRepo.ConvertBonusBalance(ctx, userID, currency)
+	if err != nil {
+		return fmt.Errorf("failed to convert bonus balance: %w", err)
+	}
+	if converted <= 0 {
+		return nil
+	}
+
+	err = s.recordTransaction(ctx, &models.Transaction{
+		UserID:      userID,
+		Type:        models.TransactionTypeBonusConversion,
+		Amount:      converted,
+		Currency:    currency,
+		Description: "Wagering requirement met, bonus moved to balance",
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Bonus converted to balance",
+		zap.Uint64("user_id", userID),
+		zap.Float64("amount", converted),
+		zap.String("currency", currency))
+	return nil
+}
+
+// recordTransaction записывает завершённую транзакцию по бонусному балансу
+func (s *PromoServiceImpl) recordTransaction(ctx context.Context, tx *models.Transaction) error {
+	tx.Status = models.TransactionStatusCompleted
+	if err := s.transactionService.CreateTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to record %s transaction: %w", tx.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func setupPromoService(t *testing.T) (*mocks.MockUserRepository, *mocks.MockPromoRepository, models.PromoService) {
+	t.Helper()
+	userRepo := mocks.NewMockUserRepository()
+	promoRepo := mocks.NewMockPromoRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	promo := NewPromoService(promoRepo, userRepo, txService, mocks.NewMockTransactor())
+
+	ctx := context.Background()
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "player", BalanceTon: 10, Wallet: "UQ-player"})
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 2, Username: "other"})
+	return userRepo, promoRepo, promo
+}
+
+func TestPromoService_CreatePromoCode(t *testing.T) {
+	ctx := context.Background()
+	_, _, promo := setupPromoService(t)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		promo *models.PromoCode
+	}{
+		{"short code", &models.PromoCode{Code: "AB", Currency: models.CurrencyTON, Amount: 1}},
+		{"invalid characters", &models.PromoCode{Code: "WELCOME!", Currency: models.CurrencyTON, Amount: 1}},
+		{"invalid currency", &models.PromoCode{Code: "WELCOME", Currency: "BTC", Amount: 1}},
+		{"zero amount", &models.PromoCode{Code: "WELCOME", Currency: models.CurrencyTON}},
+		{"unknown type", &models.PromoCode{Code: "WELCOME", Type: "cashback", Currency: models.CurrencyTON, Amount: 1}},
+		{"wager too high", &models.PromoCode{Code: "WELCOME", Currency: models.CurrencyTON, Amount: 1, WagerMultiplier: 1000}},
+		{"expired", &models.PromoCode{Code: "WELCOME", Currency: models.CurrencyTON, Amount: 1, ExpiresAt: &past}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := promo.CreatePromoCode(ctx, tt.promo); err == nil {
+				t.Error("CreatePromoCode() should fail")
+			}
+		})
+	}
+
+	code := &models.PromoCode{Code: " freebet ", Type: models.PromoTypeFreeBet, Currency: models.CurrencyTON, Amount: 1, WagerMultiplier: 20}
+	if err := promo.CreatePromoCode(ctx, code); err != nil {
+		t.Fatalf("CreatePromoCode() error = %v", err)
+	}
+	if code.Code != "FREEBET" || code.WagerMultiplier != 1 || code.PerUserLimit != 1 || !code.Active {
+		t.Errorf("promo code = %+v, want normalized free bet", code)
+	}
+
+	duplicate := &models.PromoCode{Code: "FreeBet", Currency: models.CurrencyTON, Amount: 1}
+	if err := promo.CreatePromoCode(ctx, duplicate); !errors.Is(err, models.ErrPromoCodeExists) {
+		t.Errorf("CreatePromoCode() error = %v, want ErrPromoCodeExists", err)
+	}
+}
+
+func TestPromoService_RedeemLimits(t *testing.T) {
+	ctx := context.Background()
+	userRepo, promoRepo, promo := setupPromoService(t)
+
+	code := &models.PromoCode{Code: "WELCOME", Currency: models.CurrencyTON, Amount: 2, WagerMultiplier: 3, MaxRedemptions: 2}
+	if err := promo.CreatePromoCode(ctx, code); err != nil {
+		t.Fatalf("CreatePromoCode() error = %v", err)
+	}
+
+	redemption, err := promo.RedeemPromoCode(ctx, 1, "welcome")
+	if err != nil {
+		t.Fatalf("RedeemPromoCode() error = %v", err)
+	}
+	if redemption.Amount != 2 || redemption.Wager != 6 {
+		t.Errorf("redemption = %+v, want amount 2 and wager 6", redemption)
+	}
+
+	user, _ := userRepo.GetByTelegramID(ctx, 1)
+	if user.BonusTon != 2 || user.WagerTon != 6 || user.BalanceTon != 10 {
+		t.Errorf("bonus = %v, wager = %v, balance = %v, want 2, 6, 10", user.BonusTon, user.WagerTon, user.BalanceTon)
+	}
+
+	if _, err := promo.RedeemPromoCode(ctx, 1, "WELCOME"); !errors.Is(err, models.ErrPromoCodeRedeemed) {
+		t.Errorf("second redemption error = %v, want ErrPromoCodeRedeemed", err)
+	}
+	if _, err := promo.RedeemPromoCode(ctx, 2, "WELCOME"); err != nil {
+		t.Fatalf("RedeemPromoCode() error = %v", err)
+	}
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 3, Username: "late"})
+	if _, err := promo.RedeemPromoCode(ctx, 3, "WELCOME"); !errors.Is(err, models.ErrPromoCodeExhausted) {
+		t.Errorf("redemption over total limit error = %v, want ErrPromoCodeExhausted", err)
+	}
+	if _, err := promo.RedeemPromoCode(ctx, 3, "UNKNOWN"); !errors.Is(err, models.ErrPromoCodeNotFound) {
+		t.Errorf("unknown code error = %v, want ErrPromoCodeNotFound", err)
+	}
+
+	// Срок действия и выключенный промокод
+	past := time.Now().Add(-time.Minute)
+	_ = promoRepo.Create(ctx, &models.PromoCode{Code: "EXPIRED", Type: models.PromoTypeBonus, Currency: models.CurrencyTON,
+		Amount: 1, WagerMultiplier: 1, PerUserLimit: 1, ExpiresAt: &past, Active: true})
+	if _, err := promo.RedeemPromoCode(ctx, 3, "EXPIRED"); !errors.Is(err, models.ErrPromoCodeExpired) {
+		t.Errorf("expired code error = %v, want ErrPromoCodeExpired", err)
+	}
+
+	paused := &models.PromoCode{Code: "PAUSED", Currency: models.CurrencyTON, Amount: 1}
+	_ = promo.CreatePromoCode(ctx, paused)
+	if err := promo.SetPromoCodeActive(ctx, paused.ID, false); err != nil {
+		t.Fatalf("SetPromoCodeActive() error = %v", err)
+	}
+	if _, err := promo.RedeemPromoCode(ctx, 3, "PAUSED"); !errors.Is(err, models.ErrPromoCodeInactive) {
+		t.Errorf("inactive code error = %v, want ErrPromoCodeInactive", err)
+	}
+}
+
+func TestPromoService_BonusBetWagering(t *testing.T) {
+	ctx := context.Background()
+	userRepo, promoRepo, promo := setupPromoService(t)
+
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	historyService := NewHistoryService(mocks.NewMockHistoryRepository(), gameRepo, userRepo, lobbyRepo)
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        mocks.NewMockAttemptRepository(),
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово", "слава"}),
+		Promo:              promo,
+	})
+
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 5, Word: "слово", Length: 5, MaxTries: 1, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	// Бонус 1 TON с отыгрышем x3
+	_ = promo.CreatePromoCode(ctx, &models.PromoCode{Code: "WELCOME", Currency: models.CurrencyTON, Amount: 1, WagerMultiplier: 3})
+	if _, err := promo.RedeemPromoCode(ctx, 1, "WELCOME"); err != nil {
+		t.Fatalf("RedeemPromoCode() error = %v", err)
+	}
+
+	// Ставка 1.5: 1 TON бонусом, 0.5 TON с основного баланса
+	lobby := &models.Lobby{GameID: game.ID, UserID: 1, BetAmount: 1.5}
+	if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	if lobby.BonusAmount != 1 {
+		t.Errorf("BonusAmount = %v, want 1", lobby.BonusAmount)
+	}
+	assertTonBalance(t, userRepo, 1, 9.5)
+
+	// Выигрыш 2.85 (3 за вычетом 5% комиссии): 2/3 возвращаются на бонусный баланс
+	if _, err := lobbyService.ProcessAttempt(ctx, lobby.ID, "слово"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+	assertTonBalance(t, userRepo, 1, 10.45)
+	user, _ := userRepo.GetByTelegramID(ctx, 1)
+	if math.Abs(user.BonusTon-1.9) > 1e-9 || math.Abs(user.WagerTon-1.5) > 1e-9 {
+		t.Errorf("bonus = %v, wager = %v, want 1.9 and 1.5", user.BonusTon, user.WagerTon)
+	}
+
+	// Бонус нельзя вывести, пока не отыгран оборот
+	_, err := userService.RequestWithdraw(ctx, 1, 11, models.CurrencyTON, "")
+	if err == nil || !strings.Contains(err.Error(), "wagering requirement") {
+		t.Errorf("RequestWithdraw() error = %v, want wagering requirement error", err)
+	}
+
+	// Проигранная ставка целиком из бонуса закрывает отыгрыш, остаток бонуса переходит в основной баланс
+	lobby = &models.Lobby{GameID: game.ID, UserID: 1, BetAmount: 1.5}
+	if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+	assertTonBalance(t, userRepo, 1, 10.45)
+	if _, err := lobbyService.ProcessAttempt(ctx, lobby.ID, "слава"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+	assertTonBalance(t, userRepo, 1, 10.85)
+	user, _ = userRepo.GetByTelegramID(ctx, 1)
+	if user.BonusTon != 0 || user.WagerTon != 0 {
+		t.Errorf("bonus = %v, wager = %v, want both 0", user.BonusTon, user.WagerTon)
+	}
+
+	if stake, _ := promoRepo.SettleBonusStake(ctx, lobby.ID); stake != nil {
+		t.Error("bonus stake should be settled only once")
+	}
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/xssnick/tonutils-go/address"
+	"go.uber.org/zap"
+)
+
+// Параметры реферальной программы
+const (
+	referralCodeAttempts  = 5   // Попыток сгенерировать уникальный код
+	referralPayoutBatch   = 100 // Сколько сумм по рефералам выплачивается за один проход
+	referralBlockedWallet = "referrer and referee share a wallet"
+)
+
+// ReferralServiceImpl представляет собой реализацию ReferralService
+type ReferralServiceImpl struct {
+	referralRepo       models.ReferralRepository
+	userRepo           models.UserRepository
+	transactionService models.TransactionService
+	rule               models.ReferralRule
+	botUsername        string
+	miniAppName        string
+	transactor         models.Transactor
+	logger             *zap.Logger
+}
+
+// NewReferralService создает новый экземпляр ReferralService.
+// botUsername и miniAppName используются для реферальных ссылок (t.me/<bot>/<app>?startapp=...)
+func NewReferralService(
+	referralRepo models.ReferralRepository,
+	userRepo models.UserRepository,
+	transactionService models.TransactionService,
+	rule models.ReferralRule,
+	botUsername string,
+	miniAppName string,
+	transactor models.Transactor,
+) models.ReferralService {
+	return &ReferralServiceImpl{
+		referralRepo:       referralRepo,
+		userRepo:           userRepo,
+		transactionService: transactionService,
+		rule:               rule,
+		botUsername:        botUsername,
+		miniAppName:        miniAppName,
+		transactor:         transactor,
+		logger:             logger.GetLogger(zap.String("service", "referral")),
+	}
+}
+
+// GetCode возвращает реферальный код пользователя, создавая его при первом обращении
+func (s *ReferralServiceImpl) GetCode(ctx context.Context, userID uint64) (string, error) {
+	if userID == 0 {
+		return "", errors.New("user ID cannot be zero")
+	}
+
+	var lastErr error
+	for i := 0; i < referralCodeAttempts; i++ {
+		code, err := s.referralRepo.EnsureCode(ctx, userID, generateShortID())
+		if err == nil {
+			return code, nil
+		}
+		// Код мог совпасть с кодом другого пользователя - пробуем другой
+		lastErr = err
+	}
+
+	return "", fmt.Errorf("failed to generate referral code: %w", lastErr)
+}
+
+// ApplyStartParam записывает реферера нового пользователя по параметру startapp.
+// Параметры без реферального префикса игнорируются, реферер записывается только один раз
+func (s *ReferralServiceImpl) ApplyStartParam(ctx context.Context, refereeID uint64, startParam string) error {
+	code := strings.ToUpper(models.ParseReferralStartParam(startParam))
+	if code == "" || !s.rule.Enabled() {
+		return nil
+	}
+
+	referrerID, err := s.referralRepo.GetUserByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, models.ErrReferralNotFound) {
+			return errors.New("unknown referral code")
+		}
+		return err
+	}
+	if referrerID == refereeID {
+		return errors.New("cannot refer yourself")
+	}
+
+	referee, err := s.userRepo.GetByTelegramID(ctx, refereeID)
+	if err != nil {
+		return fmt.Errorf("failed to get referee: %w", err)
+	}
+	referrer, err := s.userRepo.GetByTelegramID(ctx, referrerID)
+	if err != nil {
+		return fmt.Errorf("failed to get referrer: %w", err)
+	}
+	if sameWallet(referee, referrer) {
+		return errors.New("cannot refer a user with the same wallet")
+	}
+
+	now := time.Now()
+	referral := &models.Referral{
+		RefereeID:  refereeID,
+		ReferrerID: referrerID,
+		Code:       code,
+		Status:     models.ReferralStatusActive,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(s.rule.Period),
+	}
+	created, err := s.referralRepo.Create(ctx, referral)
+	if err != nil {
+		return err
+	}
+	if created {
+		s.logger.Info("Referral recorded",
+			zap.Uint64("referee_id", refereeID),
+			zap.Uint64("referrer_id", referrerID))
+	}
+
+	return nil
+}
+
+// AccrueCommission начисляет рефереру долю комиссии, удержанной с приглашённого пользователя,
+// если реферал активен и период начислений не истёк
+func (s *ReferralServiceImpl) AccrueCommission(ctx context.Context, entry *models.CommissionEntry) error {
+	if !s.rule.Enabled() || entry.Amount <= 0 || entry.UserID == 0 {
+		return nil
+	}
+
+	referral, err := s.referralRepo.GetByReferee(ctx, entry.UserID)
+	if err != nil {
+		if errors.Is(err, models.ErrReferralNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	at := entry.CreatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if !referral.Earns(at) {
+		return nil
+	}
+
+	earning := &models.ReferralEarning{
+		CommissionEntryID: entry.ID,
+		ReferrerID:        referral.ReferrerID,
+		RefereeID:         referral.RefereeID,
+		Currency:          entry.Currency,
+		Amount:            entry.Amount * s.rule.CommissionShare,
+		Status:            models.ReferralEarningPending,
+	}
+	if _, err := s.referralRepo.AddEarning(ctx, earning); err != nil {
+		return fmt.Errorf("failed to accrue referral earning: %w", err)
+	}
+
+	return nil
+}
+
+// ProcessPayouts выплачивает накопленные начисления реферерам.
+// Если у реферера и реферала общий кошелёк, реферал блокируется, а начисления отменяются
+func (s *ReferralServiceImpl) ProcessPayouts(ctx context.Context) error {
+	log := s.logger.With(zap.String("method", "ProcessPayouts"))
+
+	totals, err := s.referralRepo.GetPendingTotals(ctx, referralPayoutBatch)
+	if err != nil {
+		return err
+	}
+
+	blocked := make(map[uint64]bool)
+	for _, total := range totals {
+		if blocked[total.RefereeID] {
+			continue
+		}
+
+		abuse, err := s.sharesWallet(ctx, total.RefereeID, total.ReferrerID)
+		if err != nil {
+			log.Error("Failed to check referral", zap.Uint64("referee_id", total.RefereeID), zap.Error(err))
+			continue
+		}
+		if abuse {
+			blocked[total.RefereeID] = true
+			if err := s.blockReferral(ctx, total.RefereeID, referralBlockedWallet); err != nil {
+				log.Error("Failed to block referral", zap.Uint64("referee_id", total.RefereeID), zap.Error(err))
+			}
+			continue
+		}
+
+		amount, err := s.payPending(ctx, total)
+		if err != nil {
+			log.Error("Failed to pay referral earnings",
+				zap.Uint64("referrer_id", total.ReferrerID),
+				zap.Uint64("referee_id", total.RefereeID),
+				zap.String("currency", total.Currency),
+				zap.Error(err))
+			continue
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		log.Info("Referral earnings paid",
+			zap.Uint64("referrer_id", total.ReferrerID),
+			zap.Uint64("referee_id", total.RefereeID),
+			zap.Float64("amount", amount),
+			zap.String("currency", total.Currency))
+	}
+
+	return nil
+}
+
+// payPending помечает начисления реферала выплаченными и зачисляет их рефереру в одной транзакции.
+// При ошибке зачисления начисления остаются невыплаченными и будут выплачены на следующем проходе
+func (s *ReferralServiceImpl) payPending(ctx context.Context, total *models.ReferralEarningTotal) (float64, error) {
+	var amount float64
+	err := withinTx(ctx, s.transactor, func(ctx context.Context) error {
+		var err error
+		amount, err = s.referralRepo.ClaimPending(ctx, total.RefereeID, total.Currency)
+		if err != nil {
+			return fmt.Errorf("failed to claim referral earnings: %w", err)
+		}
+		if amount <= 0 {
+			return nil
+		}
+		return s.transactionService.PayReferral(ctx, total.ReferrerID, amount, total.Currency, total.RefereeID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// GetSummary возвращает реферальную ссылку пользователя, приглашённых пользователей и заработок
+func (s *ReferralServiceImpl) GetSummary(ctx context.Context, userID uint64, limit, offset int) (*models.ReferralSummary, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	code, err := s.GetCode(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	referrals, err := s.referralRepo.GetByReferrer(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.referralRepo.GetTotalsByReferrer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.ReferralSummary{
+		Code:            code,
+		StartParam:      models.ReferralStartParam(code),
+		InviteLink:      s.inviteLink(code),
+		CommissionShare: s.rule.CommissionShare,
+		PeriodDays:      int(s.rule.Period / (24 * time.Hour)),
+		Referred:        make([]*models.ReferredUser, 0, len(referrals)),
+		Earned:          make(map[string]float64),
+		Pending:         make(map[string]float64),
+	}
+
+	byReferee := make(map[uint64]*models.ReferredUser, len(referrals))
+	for _, referral := range referrals {
+		referred := &models.ReferredUser{
+			UserID:    referral.RefereeID,
+			Status:    referral.Status,
+			CreatedAt: referral.CreatedAt,
+			ExpiresAt: referral.ExpiresAt,
+			Earned:    make(map[string]float64),
+			Pending:   make(map[string]float64),
+		}
+		if user, err := s.userRepo.GetByTelegramID(ctx, referral.RefereeID); err == nil {
+			referred.Username = user.Username
+		}
+		byReferee[referral.RefereeID] = referred
+		summary.Referred = append(summary.Referred, referred)
+	}
+
+	for _, total := range totals {
+		referred := byReferee[total.RefereeID]
+		switch total.Status {
+		case models.ReferralEarningCredited:
+			summary.Earned[total.Currency] += total.Amount
+			if referred != nil {
+				referred.Earned[total.Currency] += total.Amount
+			}
+		case models.ReferralEarningPending:
+			summary.Pending[total.Currency] += total.Amount
+			if referred != nil {
+				referred.Pending[total.Currency] += total.Amount
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// sharesWallet проверяет, привязан ли у реферера и реферала один и тот же кошелёк
+func (s *ReferralServiceImpl) sharesWallet(ctx context.Context, refereeID, referrerID uint64) (bool, error) {
+	referee, err := s.userRepo.GetByTelegramID(ctx, refereeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get referee: %w", err)
+	}
+	referrer, err := s.userRepo.GetByTelegramID(ctx, referrerID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get referrer: %w", err)
+	}
+	return sameWallet(referee, referrer), nil
+}
+
+// blockReferral блокирует реферала и отменяет невыплаченные начисления по нему
+func (s *ReferralServiceImpl) blockReferral(ctx context.Context, refereeID uint64, reason string) error {
+	if err := s.referralRepo.Block(ctx, refereeID, reason); err != nil {
+		return err
+	}
+	if err := s.referralRepo.CancelPending(ctx, refereeID); err != nil {
+		return err
+	}
+
+	s.logger.Warn("Referral blocked",
+		zap.Uint64("referee_id", refereeID),
+		zap.String("reason", reason))
+	return nil
+}
+
+// inviteLink возвращает реферальную ссылку на Mini App
+func (s *ReferralServiceImpl) inviteLink(code string) string {
+	if s.botUsername == "" {
+		return ""
+	}
+	if s.miniAppName != "" {
+		return fmt.Sprintf("https://t.me/%s/%s?startapp=%s", s.botUsername, s.miniAppName, models.ReferralStartParam(code))
+	}
+	return fmt.Sprintf("https://t.me/%s?startapp=%s", s.botUsername, models.ReferralStartParam(code))
+}
+
+// sameWallet проверяет, что у обоих пользователей привязан один и тот же кошелёк.
+// Адреса TON сравниваются по воркчейну и хешу, поэтому bounceable, non-bounceable и raw формы одного кошелька совпадают.
+// Адреса, которые не разбираются как TON, сравниваются как строки
+func sameWallet(a, b *models.User) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	walletA, walletB := strings.TrimSpace(a.Wallet), strings.TrimSpace(b.Wallet)
+	if walletA == "" || walletB == "" {
+		return false
+	}
+
+	addrA, errA := parseWallet(walletA)
+	addrB, errB := parseWallet(walletB)
+	if errA != nil || errB != nil {
+		return walletA == walletB
+	}
+	return addrA.Workchain() == addrB.Workchain() && bytes.Equal(addrA.Data(), addrB.Data())
+}
+
+// parseWallet разбирает адрес TON в user-friendly (base64 или base64url) или raw (workchain:hex) форме
+func parseWallet(wallet string) (*address.Address, error) {
+	if strings.Contains(wallet, ":") {
+		return address.ParseRawAddr(wallet)
+	}
+	return address.ParseAddr(strings.NewReplacer("+", "-", "/", "_").Replace(wallet))
+}
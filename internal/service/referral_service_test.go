@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// Кошелёк реферера в разных формах записи адреса
+const (
+	referrerWalletBounceable    = "EQADChEYHyYtNDtCSVBXXmVsc3qBiI-WnaSrsrnAx87V3Ls2"
+	referrerWalletNonBounceable = "UQADChEYHyYtNDtCSVBXXmVsc3qBiI-WnaSrsrnAx87V3Obz"
+	referrerWalletRaw           = "0:030a11181f262d343b424950575e656c737a81888f969da4abb2b9c0c7ced5dc"
+)
+
+func setupReferralService(t *testing.T) (*mocks.MockUserRepository, *mocks.MockTransactionRepository, models.ReferralService, models.CommissionService) {
+	t.Helper()
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+	txService := NewTransactionServiceImpl(txRepo, userRepo, nil)
+	referrals := NewReferralService(mocks.NewMockReferralRepository(), userRepo, txService,
+		models.ReferralRule{CommissionShare: 0.2, Period: 24 * time.Hour}, "wordle_bot", "play", mocks.NewMockTransactor())
+	commission := NewCommissionService(mocks.NewMockCommissionRepository(), models.CommissionPolicy{DefaultRate: 0.05}, referrals)
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "referrer", Wallet: referrerWalletBounceable})
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 2, Username: "referee"})
+	return userRepo, txRepo, referrals, commission
+}
+
+func bookLossCommission(t *testing.T, commission models.CommissionService, userID uint64, amount float64) {
+	t.Helper()
+	err := commission.BookCommission(context.Background(), &models.CommissionEntry{
+		Source:      models.CommissionSourceLobbyLoss,
+		ReferenceID: uuid.New(),
+		UserID:      userID,
+		Currency:    models.CurrencyTON,
+		Volume:      amount / 0.05,
+		Rate:        0.05,
+		Amount:      amount,
+	})
+	if err != nil {
+		t.Fatalf("BookCommission() error = %v", err)
+	}
+}
+
+func TestReferralService_ApplyStartParam(t *testing.T) {
+	ctx := context.Background()
+	userRepo, _, referrals, _ := setupReferralService(t)
+
+	code, err := referrals.GetCode(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetCode() error = %v", err)
+	}
+	if again, _ := referrals.GetCode(ctx, 1); again != code {
+		t.Errorf("GetCode() = %q on second call, want %q", again, code)
+	}
+
+	// Собственная ссылка и неизвестный код отклоняются, чужие параметры игнорируются
+	if err := referrals.ApplyStartParam(ctx, 1, models.ReferralStartParam(code)); err == nil {
+		t.Error("ApplyStartParam() should reject self-referral")
+	}
+	if err := referrals.ApplyStartParam(ctx, 2, models.ReferralStartParam("UNKNOWN")); err == nil {
+		t.Error("ApplyStartParam() should reject unknown code")
+	}
+	if err := referrals.ApplyStartParam(ctx, 2, "duel_ABCDEFGH"); err != nil {
+		t.Errorf("ApplyStartParam() error = %v for non-referral param", err)
+	}
+
+	// Пользователь с тем же кошельком не может стать рефералом
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 3, Username: "twin", Wallet: referrerWalletNonBounceable})
+	if err := referrals.ApplyStartParam(ctx, 3, models.ReferralStartParam(code)); err == nil {
+		t.Error("ApplyStartParam() should reject shared wallet")
+	}
+
+	if err := referrals.ApplyStartParam(ctx, 2, models.ReferralStartParam(code)); err != nil {
+		t.Fatalf("ApplyStartParam() error = %v", err)
+	}
+
+	summary, err := referrals.GetSummary(ctx, 1, 10, 0)
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+	if len(summary.Referred) != 1 || summary.Referred[0].UserID != 2 || summary.Referred[0].Username != "referee" {
+		t.Fatalf("Referred = %+v, want referee 2", summary.Referred)
+	}
+	wantLink := "https://t.me/wordle_bot/play?startapp=ref_" + code
+	if summary.InviteLink != wantLink {
+		t.Errorf("InviteLink = %q, want %q", summary.InviteLink, wantLink)
+	}
+}
+
+func TestReferralService_Payouts(t *testing.T) {
+	ctx := context.Background()
+	userRepo, txRepo, referrals, commission := setupReferralService(t)
+
+	code, _ := referrals.GetCode(ctx, 1)
+	if err := referrals.ApplyStartParam(ctx, 2, models.ReferralStartParam(code)); err != nil {
+		t.Fatalf("ApplyStartParam() error = %v", err)
+	}
+
+	// 20% комиссии с реферала, комиссия самого реферера не учитывается
+	bookLossCommission(t, commission, 2, 1)
+	bookLossCommission(t, commission, 2, 0.5)
+	bookLossCommission(t, commission, 1, 1)
+
+	summary, _ := referrals.GetSummary(ctx, 1, 10, 0)
+	if math.Abs(summary.Pending[models.CurrencyTON]-0.3) > 1e-9 {
+		t.Errorf("Pending = %v, want 0.3", summary.Pending)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := referrals.ProcessPayouts(ctx); err != nil {
+			t.Fatalf("ProcessPayouts() error = %v", err)
+		}
+	}
+	assertTonBalance(t, userRepo, 1, 0.3)
+
+	txs, _ := txRepo.GetByType(ctx, models.TransactionTypeReferral, 10, 0)
+	if len(txs) != 1 {
+		t.Fatalf("referral transactions = %d, want 1", len(txs))
+	}
+
+	summary, _ = referrals.GetSummary(ctx, 1, 10, 0)
+	if math.Abs(summary.Earned[models.CurrencyTON]-0.3) > 1e-9 || summary.Pending[models.CurrencyTON] != 0 {
+		t.Errorf("Earned = %v, Pending = %v, want 0.3 earned", summary.Earned, summary.Pending)
+	}
+	if math.Abs(summary.Referred[0].Earned[models.CurrencyTON]-0.3) > 1e-9 {
+		t.Errorf("referee earned = %v, want 0.3", summary.Referred[0].Earned)
+	}
+}
+
+func TestReferralService_SharedWalletBlocksReferral(t *testing.T) {
+	ctx := context.Background()
+	userRepo, _, referrals, commission := setupReferralService(t)
+
+	code, _ := referrals.GetCode(ctx, 1)
+	if err := referrals.ApplyStartParam(ctx, 2, models.ReferralStartParam(code)); err != nil {
+		t.Fatalf("ApplyStartParam() error = %v", err)
+	}
+	bookLossCommission(t, commission, 2, 1)
+
+	// Реферал привязал кошелёк реферера после регистрации
+	_ = userRepo.UpdateWallet(ctx, 2, referrerWalletRaw)
+	if err := referrals.ProcessPayouts(ctx); err != nil {
+		t.Fatalf("ProcessPayouts() error = %v", err)
+	}
+	assertTonBalance(t, userRepo, 1, 0)
+
+	// Дальнейшая комиссия реферала не начисляется
+	bookLossCommission(t, commission, 2, 1)
+	summary, _ := referrals.GetSummary(ctx, 1, 10, 0)
+	if summary.Referred[0].Status != models.ReferralStatusBlocked {
+		t.Errorf("Status = %q, want blocked", summary.Referred[0].Status)
+	}
+	if summary.Pending[models.CurrencyTON] != 0 || summary.Earned[models.CurrencyTON] != 0 {
+		t.Errorf("Earned = %v, Pending = %v, want nothing", summary.Earned, summary.Pending)
+	}
+}
+
+func TestSameWallet(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"bounceable and non-bounceable", referrerWalletBounceable, referrerWalletNonBounceable, true},
+		{"friendly and raw", referrerWalletNonBounceable, referrerWalletRaw, true},
+		{"different hash", referrerWalletRaw, "0:0000000000000000000000000000000000000000000000000000000000000000", false},
+		{"different workchain", referrerWalletRaw, "-1:030a11181f262d343b424950575e656c737a81888f969da4abb2b9c0c7ced5dc", false},
+		{"not a TON address", "wallet-1", "wallet-1", true},
+		{"empty wallet", referrerWalletBounceable, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sameWallet(&models.User{Wallet: tt.a}, &models.User{Wallet: tt.b})
+			if got != tt.want {
+				t.Errorf("sameWallet(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferral_Earns(t *testing.T) {
+	now := time.Now()
+	referral := &models.Referral{Status: models.ReferralStatusActive, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	if !referral.Earns(now.Add(time.Minute)) {
+		t.Error("Earns() = false within period")
+	}
+	if referral.Earns(now.Add(2 * time.Hour)) {
+		t.Error("Earns() = true after period")
+	}
+	referral.Status = models.ReferralStatusBlocked
+	if referral.Earns(now.Add(time.Minute)) {
+		t.Error("Earns() = true for blocked referral")
+	}
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Параметры расчёта репутации. Составляющие сглаживаются априорными наблюдениями,
+// чтобы несколько первых оценок или лобби не двигали репутацию к краям шкалы
+const (
+	reputationPriorWeight = 5.0 // Число априорных наблюдений для каждой составляющей
+	reputationPriorRating = 3.0 // Априорная оценка игры
+
+	reputationRatingWeight     = 0.5
+	reputationCompletionWeight = 0.2
+	reputationRefundWeight     = 0.15
+	reputationDisputeWeight    = 0.15
+
+	reputationRefreshBatch = 100 // Создателей за один запуск фоновой задачи
+)
+
+// ReputationServiceImpl представляет собой реализацию ReputationService
+type ReputationServiceImpl struct {
+	reputationRepo models.ReputationRepository
+	gameRepo       models.GameRepository
+	logger         *zap.Logger
+}
+
+// NewReputationService создает новый экземпляр ReputationService
+func NewReputationService(reputationRepo models.ReputationRepository, gameRepo models.GameRepository) models.ReputationService {
+	return &ReputationServiceImpl{
+		reputationRepo: reputationRepo,
+		gameRepo:       gameRepo,
+		logger:         logger.GetLogger(zap.String("service", "reputation")),
+	}
+}
+
+// RateGame сохраняет оценку и отзыв игрока и пересчитывает репутацию создателя игры.
+// Повторная оценка заменяет прежнюю
+func (s *ReputationServiceImpl) RateGame(ctx context.Context, gameID uuid.UUID, userID uint64, rating int, review string) (*models.GameReview, error) {
+	if rating < 1 || rating > 5 {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+	review = strings.TrimSpace(review)
+	if utf8.RuneCountInString(review) > models.ReviewMaxLength {
+		return nil, fmt.Errorf("review cannot exceed %d characters", models.ReviewMaxLength)
+	}
+
+	game, err := s.gameRepo.GetByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.CreatorID == userID {
+		return nil, models.ErrReviewNotAllowed
+	}
+
+	finished, err := s.reputationRepo.HasFinishedLobby(ctx, gameID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !finished {
+		return nil, models.ErrReviewNotAllowed
+	}
+
+	saved := &models.GameReview{
+		GameID:    gameID,
+		CreatorID: game.CreatorID,
+		UserID:    userID,
+		Rating:    rating,
+		Review:    review,
+	}
+	if err := s.reputationRepo.SaveReview(ctx, saved); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.RefreshReputation(ctx, game.CreatorID); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// GetGameReviews возвращает опубликованные отзывы об игре
+func (s *ReputationServiceImpl) GetGameReviews(ctx context.Context, gameID uuid.UUID, limit, offset int) ([]*models.GameReview, error) {
+	if _, err := s.gameRepo.GetByID(ctx, gameID); err != nil {
+		return nil, err
+	}
+
+	reviews, err := s.reputationRepo.GetGameReviews(ctx, gameID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if reviews == nil {
+		reviews = []*models.GameReview{}
+	}
+	return reviews, nil
+}
+
+// GetGameRating возвращает сводку опубликованных оценок игры
+func (s *ReputationServiceImpl) GetGameRating(ctx context.Context, gameID uuid.UUID) (*models.GameRating, error) {
+	if _, err := s.gameRepo.GetByID(ctx, gameID); err != nil {
+		return nil, err
+	}
+	return s.reputationRepo.GetGameRating(ctx, gameID)
+}
+
+// GetCreatorReputation возвращает сохранённую репутацию создателя.
+// Если репутация ещё не рассчитывалась, она считается на лету без сохранения
+func (s *ReputationServiceImpl) GetCreatorReputation(ctx context.Context, creatorID uint64) (*models.CreatorReputation, error) {
+	reputation, err := s.reputationRepo.GetReputation(ctx, creatorID)
+	if errors.Is(err, models.ErrReputationNotFound) {
+		return s.calculate(ctx, creatorID)
+	}
+	return reputation, err
+}
+
+// RefreshReputation пересчитывает и сохраняет репутацию создателя
+func (s *ReputationServiceImpl) RefreshReputation(ctx context.Context, creatorID uint64) (*models.CreatorReputation, error) {
+	reputation, err := s.calculate(ctx, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reputationRepo.SaveReputation(ctx, reputation); err != nil {
+		return nil, err
+	}
+	return reputation, nil
+}
+
+// ProcessStaleReputations пересчитывает репутацию создателей, у которых после прошлого расчёта
+// завершились лобби или были возвраты ставок. Ошибка по одному создателю не останавливает остальных
+func (s *ReputationServiceImpl) ProcessStaleReputations(ctx context.Context) error {
+	creators, err := s.reputationRepo.GetStaleCreators(ctx, reputationRefreshBatch)
+	if err != nil {
+		return fmt.Errorf("failed to get stale creators: %w", err)
+	}
+
+	for _, creatorID := range creators {
+		if _, err := s.RefreshReputation(ctx, creatorID); err != nil {
+			s.logger.Error("Failed to refresh creator reputation", zap.Uint64("creator_id", creatorID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// GetReviewsForModeration возвращает отзывы с указанным статусом (пустой статус - все)
+func (s *ReputationServiceImpl) GetReviewsForModeration(ctx context.Context, status string, limit, offset int) ([]*models.GameReview, error) {
+	if status != "" && status != models.ReviewStatusVisible && status != models.ReviewStatusHidden {
+		return nil, errors.New("invalid status: must be visible or hidden")
+	}
+
+	reviews, err := s.reputationRepo.GetReviewsByStatus(ctx, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if reviews == nil {
+		reviews = []*models.GameReview{}
+	}
+	return reviews, nil
+}
+
+// ModerateReview скрывает отзыв или публикует его снова и пересчитывает репутацию создателя
+func (s *ReputationServiceImpl) ModerateReview(ctx context.Context, reviewID uuid.UUID, moderatorID uint64, hidden bool, note string) (*models.GameReview, error) {
+	review, err := s.reputationRepo.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.ReviewStatusVisible
+	if hidden {
+		status = models.ReviewStatusHidden
+	}
+	if err := s.reputationRepo.ModerateReview(ctx, reviewID, status, strings.TrimSpace(note), moderatorID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.RefreshReputation(ctx, review.CreatorID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Review moderated",
+		zap.String("review_id", reviewID.String()),
+		zap.Uint64("moderator_id", moderatorID),
+		zap.String("status", status))
+
+	return s.reputationRepo.GetReview(ctx, reviewID)
+}
+
+// RecordDispute записывает исход спора игрока с создателем и пересчитывает репутацию создателя
+func (s *ReputationServiceImpl) RecordDispute(ctx context.Context, dispute *models.CreatorDispute) error {
+	if dispute.CreatorID == 0 || dispute.UserID == 0 {
+		return errors.New("creator ID and user ID are required")
+	}
+	if dispute.CreatorID == dispute.UserID {
+		return errors.New("creator and player must be different users")
+	}
+	if dispute.Outcome != models.DisputeOutcomePlayer && dispute.Outcome != models.DisputeOutcomeCreator {
+		return errors.New("invalid outcome: must be player or creator")
+	}
+	dispute.Note = strings.TrimSpace(dispute.Note)
+
+	if err := s.reputationRepo.CreateDispute(ctx, dispute); err != nil {
+		return err
+	}
+
+	_, err := s.RefreshReputation(ctx, dispute.CreatorID)
+	return err
+}
+
+// calculate рассчитывает репутацию создателя по текущим данным
+func (s *ReputationServiceImpl) calculate(ctx context.Context, creatorID uint64) (*models.CreatorReputation, error) {
+	inputs, err := s.reputationRepo.GetReputationInputs(ctx, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	return calculateReputation(creatorID, inputs, time.Now()), nil
+}
+
+// calculateReputation рассчитывает репутацию (0-100) по оценкам, доле доигранных лобби, возвратам ставок и спорам.
+// Без данных каждая составляющая равна априорной, и репутация совпадает с models.DefaultReputationScore
+func calculateReputation(creatorID uint64, inputs *models.ReputationInputs, now time.Time) *models.CreatorReputation {
+	rating := (inputs.RatingSum + reputationPriorRating*reputationPriorWeight) / (float64(inputs.RatingCount) + reputationPriorWeight)
+	ratingScore := (rating - 1) / 4
+	completionScore := (float64(inputs.Completed) + reputationPriorWeight) / (float64(inputs.Lobbies) + reputationPriorWeight)
+	refundScore := 1 - float64(inputs.Refunds)/(float64(inputs.Lobbies+inputs.Refunds)+reputationPriorWeight)
+	disputeScore := 1 - float64(inputs.DisputesLost)/(float64(inputs.Disputes)+reputationPriorWeight)
+
+	score := 100 * (reputationRatingWeight*ratingScore +
+		reputationCompletionWeight*completionScore +
+		reputationRefundWeight*refundScore +
+		reputationDisputeWeight*disputeScore)
+
+	reputation := &models.CreatorReputation{
+		CreatorID:    creatorID,
+		Score:        math.Round(score*100) / 100,
+		RatingCount:  inputs.RatingCount,
+		Disputes:     inputs.Disputes,
+		DisputesLost: inputs.DisputesLost,
+		UpdatedAt:    now,
+	}
+	if inputs.RatingCount > 0 {
+		reputation.RatingAverage = inputs.RatingSum / float64(inputs.RatingCount)
+	}
+	if inputs.Lobbies > 0 {
+		reputation.CompletionRate = float64(inputs.Completed) / float64(inputs.Lobbies)
+	}
+	if inputs.Lobbies+inputs.Refunds > 0 {
+		reputation.RefundRate = float64(inputs.Refunds) / float64(inputs.Lobbies+inputs.Refunds)
+	}
+
+	return reputation
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+type reputationFixture struct {
+	games        *mocks.MockGameRepository
+	lobbies      *mocks.MockLobbyRepository
+	history      *mocks.MockHistoryRepository
+	transactions *mocks.MockTransactionRepository
+	reputation   models.ReputationService
+}
+
+func setupReputationService(t *testing.T) *reputationFixture {
+	t.Helper()
+	f := &reputationFixture{
+		games:        mocks.NewMockGameRepository(),
+		lobbies:      mocks.NewMockLobbyRepository(),
+		history:      mocks.NewMockHistoryRepository(),
+		transactions: mocks.NewMockTransactionRepository(),
+	}
+	repo := mocks.NewMockReputationRepository(f.games, f.lobbies, f.history, f.transactions)
+	f.reputation = NewReputationService(repo, f.games)
+	return f
+}
+
+// addGame создаёт активную публичную игру создателя
+func (f *reputationFixture) addGame(creatorID uint64) *models.Game {
+	game := &models.Game{CreatorID: creatorID, Title: "Test", Length: 5, MinBet: 1, MaxBet: 2, RewardMultiplier: 2,
+		Currency: models.CurrencyTON, RewardPoolTon: 10, Status: models.GameStatusActive, Visibility: models.GameVisibilityPublic}
+	_ = f.games.Create(context.Background(), game)
+	return game
+}
+
+// finish записывает в историю рассчитанное лобби игрока
+func (f *reputationFixture) finish(game *models.Game, userID uint64, at time.Time) {
+	_ = f.history.Create(context.Background(), &models.History{UserID: userID, GameID: game.ID, LobbyID: uuid.New(),
+		Status: models.HistoryStatusPlayerWin, Currency: game.Currency, CreatedAt: at})
+}
+
+func TestReputationService_RateGame(t *testing.T) {
+	ctx := context.Background()
+	f := setupReputationService(t)
+
+	game := f.addGame(1)
+	f.finish(game, 10, time.Now())
+	f.finish(game, 12, time.Now())
+
+	if _, err := f.reputation.RateGame(ctx, game.ID, 11, 5, ""); !errors.Is(err, models.ErrReviewNotAllowed) {
+		t.Errorf("RateGame() without finished lobby error = %v, want ErrReviewNotAllowed", err)
+	}
+	if _, err := f.reputation.RateGame(ctx, game.ID, 1, 5, ""); !errors.Is(err, models.ErrReviewNotAllowed) {
+		t.Errorf("RateGame() by creator error = %v, want ErrReviewNotAllowed", err)
+	}
+	if _, err := f.reputation.RateGame(ctx, game.ID, 10, 6, ""); err == nil {
+		t.Error("RateGame() with rating 6 should fail")
+	}
+	if _, err := f.reputation.RateGame(ctx, game.ID, 10, 5, strings.Repeat("я", models.ReviewMaxLength+1)); err == nil {
+		t.Error("RateGame() with too long review should fail")
+	}
+	if _, err := f.reputation.RateGame(ctx, uuid.New(), 10, 5, ""); !errors.Is(err, models.ErrGameNotFound) {
+		t.Errorf("RateGame() for unknown game error = %v, want ErrGameNotFound", err)
+	}
+
+	// Повторная оценка заменяет прежнюю
+	if _, err := f.reputation.RateGame(ctx, game.ID, 10, 5, "Отлично"); err != nil {
+		t.Fatalf("RateGame() error = %v", err)
+	}
+	review, err := f.reputation.RateGame(ctx, game.ID, 10, 4, "  Хорошо  ")
+	if err != nil {
+		t.Fatalf("RateGame() error = %v", err)
+	}
+	if review.Review != "Хорошо" || review.CreatorID != 1 || review.Status != models.ReviewStatusVisible {
+		t.Errorf("review = %+v, want trimmed visible review of creator 1", review)
+	}
+	if _, err := f.reputation.RateGame(ctx, game.ID, 12, 1, "Скучно"); err != nil {
+		t.Fatalf("RateGame() error = %v", err)
+	}
+
+	rating, err := f.reputation.GetGameRating(ctx, game.ID)
+	if err != nil {
+		t.Fatalf("GetGameRating() error = %v", err)
+	}
+	if rating.Count != 2 || math.Abs(rating.Average-2.5) > 1e-9 || rating.Distribution[4] != 1 || rating.Distribution[1] != 1 {
+		t.Errorf("rating = %+v, want two ratings averaging 2.5", rating)
+	}
+
+	// Оценка сразу влияет на репутацию создателя и сортировку его игр
+	if game.CreatorReputation >= models.DefaultReputationScore {
+		t.Errorf("creator reputation = %v, want below default after low ratings", game.CreatorReputation)
+	}
+}
+
+func TestReputationService_ModerateReview(t *testing.T) {
+	ctx := context.Background()
+	f := setupReputationService(t)
+
+	game := f.addGame(1)
+	f.finish(game, 10, time.Now())
+	f.finish(game, 12, time.Now())
+	_, _ = f.reputation.RateGame(ctx, game.ID, 10, 4, "Хорошо")
+	spam, _ := f.reputation.RateGame(ctx, game.ID, 12, 1, "Реклама")
+
+	hidden, err := f.reputation.ModerateReview(ctx, spam.ID, 99, true, "spam")
+	if err != nil {
+		t.Fatalf("ModerateReview() error = %v", err)
+	}
+	if hidden.Status != models.ReviewStatusHidden || hidden.ModeratedBy != 99 || hidden.ModerationNote != "spam" || hidden.ModeratedAt == nil {
+		t.Errorf("moderated review = %+v, want hidden by 99", hidden)
+	}
+
+	// Скрытый отзыв не учитывается в оценке игры и репутации создателя
+	rating, _ := f.reputation.GetGameRating(ctx, game.ID)
+	if rating.Count != 1 || rating.Average != 4 {
+		t.Errorf("rating = %+v, want only the visible rating 4", rating)
+	}
+	reviews, _ := f.reputation.GetGameReviews(ctx, game.ID, 10, 0)
+	if len(reviews) != 1 || reviews[0].Rating != 4 {
+		t.Errorf("reviews = %+v, want only the visible review", reviews)
+	}
+	queue, err := f.reputation.GetReviewsForModeration(ctx, models.ReviewStatusHidden, 10, 0)
+	if err != nil || len(queue) != 1 || queue[0].ID != spam.ID {
+		t.Errorf("GetReviewsForModeration(hidden) = %+v, %v, want the hidden review", queue, err)
+	}
+
+	// Оценка 4 со сглаживанием: (4 + 3*5) / 6
+	reputation, err := f.reputation.GetCreatorReputation(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetCreatorReputation() error = %v", err)
+	}
+	if reputation.RatingCount != 1 || reputation.RatingAverage != 4 || math.Abs(reputation.Score-77.08) > 1e-9 {
+		t.Errorf("reputation = %+v, want score 77.08 from one rating 4", reputation)
+	}
+
+	if _, err := f.reputation.ModerateReview(ctx, uuid.New(), 99, true, ""); !errors.Is(err, models.ErrReviewNotFound) {
+		t.Errorf("ModerateReview() for unknown review error = %v, want ErrReviewNotFound", err)
+	}
+	if _, err := f.reputation.GetReviewsForModeration(ctx, "deleted", 10, 0); err == nil {
+		t.Error("GetReviewsForModeration() with unknown status should fail")
+	}
+}
+
+func TestReputationService_Score(t *testing.T) {
+	ctx := context.Background()
+	f := setupReputationService(t)
+
+	// Без оценок и истории репутация равна значению по умолчанию
+	reputation, err := f.reputation.GetCreatorReputation(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetCreatorReputation() error = %v", err)
+	}
+	if reputation.Score != models.DefaultReputationScore {
+		t.Errorf("score without data = %v, want %v", reputation.Score, models.DefaultReputationScore)
+	}
+
+	game := f.addGame(2)
+	for i := 0; i < 5; i++ {
+		_ = f.lobbies.Create(ctx, &models.Lobby{GameID: game.ID, UserID: 10, Status: models.LobbyStatusSuccess})
+		_ = f.lobbies.Create(ctx, &models.Lobby{GameID: game.ID, UserID: 10, Status: models.LobbyStatusCanceled})
+	}
+	// Внутренние ошибки и незавершённые лобби не учитываются
+	_ = f.lobbies.Create(ctx, &models.Lobby{GameID: game.ID, UserID: 10, Status: models.LobbyStatusFailedInternal})
+	_ = f.lobbies.Create(ctx, &models.Lobby{GameID: game.ID, UserID: 10, Status: models.LobbyStatusActive})
+	for i := 0; i < 2; i++ {
+		_ = f.transactions.Create(ctx, &models.Transaction{UserID: 10, Type: models.TransactionTypeRefund, Amount: 1,
+			Currency: models.CurrencyTON, Status: models.TransactionStatusCompleted, GameID: &game.ID})
+	}
+
+	if err := f.reputation.RecordDispute(ctx, &models.CreatorDispute{CreatorID: 2, UserID: 10, Outcome: "draw"}); err == nil {
+		t.Error("RecordDispute() with unknown outcome should fail")
+	}
+	_ = f.reputation.RecordDispute(ctx, &models.CreatorDispute{CreatorID: 2, UserID: 10, Outcome: models.DisputeOutcomeCreator, ResolvedBy: 99})
+	if err := f.reputation.RecordDispute(ctx, &models.CreatorDispute{CreatorID: 2, UserID: 11,
+		Outcome: models.DisputeOutcomePlayer, ResolvedBy: 99}); err != nil {
+		t.Fatalf("RecordDispute() error = %v", err)
+	}
+
+	reputation, err = f.reputation.GetCreatorReputation(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetCreatorReputation() error = %v", err)
+	}
+	// Оценки 0.5, доигранные лобби 10/15, возвраты 1 - 2/17, споры 1 - 1/7
+	if math.Abs(reputation.Score-64.43) > 1e-9 {
+		t.Errorf("score = %v, want 64.43", reputation.Score)
+	}
+	if reputation.CompletionRate != 0.5 || math.Abs(reputation.RefundRate-2.0/12) > 1e-9 ||
+		reputation.Disputes != 2 || reputation.DisputesLost != 1 {
+		t.Errorf("reputation = %+v, want completion 0.5, refund rate 1/6 and one lost dispute of two", reputation)
+	}
+	if game.CreatorReputation != reputation.Score {
+		t.Errorf("game creator reputation = %v, want %v", game.CreatorReputation, reputation.Score)
+	}
+}
+
+func TestReputationService_ProcessStaleReputations(t *testing.T) {
+	ctx := context.Background()
+	f := setupReputationService(t)
+	gameService := NewGameService(GameServiceDeps{
+		GameRepo:    f.games,
+		Commission:  newTestCommissionService(),
+		BotUsername: "wordle_bot",
+		MiniAppName: "play",
+	})
+
+	rated := f.addGame(3)
+	disputed := f.addGame(4)
+	fresh := f.addGame(5)
+	f.finish(rated, 10, time.Now().Add(-time.Minute))
+	f.finish(disputed, 10, time.Now().Add(-time.Minute))
+
+	if err := f.reputation.ProcessStaleReputations(ctx); err != nil {
+		t.Fatalf("ProcessStaleReputations() error = %v", err)
+	}
+	refreshed, err := f.reputation.GetCreatorReputation(ctx, 3)
+	if err != nil || refreshed.UpdatedAt.IsZero() {
+		t.Fatalf("reputation of creator 3 was not refreshed: %+v, %v", refreshed, err)
+	}
+
+	// Без новых лобби репутация не пересчитывается, после нового лобби - пересчитывается
+	_ = f.reputation.ProcessStaleReputations(ctx)
+	if again, _ := f.reputation.GetCreatorReputation(ctx, 3); !again.UpdatedAt.Equal(refreshed.UpdatedAt) {
+		t.Error("reputation without new lobbies should not be refreshed")
+	}
+	f.finish(rated, 11, time.Now().Add(time.Second))
+	_ = f.reputation.ProcessStaleReputations(ctx)
+	if again, _ := f.reputation.GetCreatorReputation(ctx, 3); !again.UpdatedAt.After(refreshed.UpdatedAt) {
+		t.Error("reputation after a new lobby should be refreshed")
+	}
+
+	_, _ = f.reputation.RateGame(ctx, rated.ID, 10, 5, "")
+	_ = f.reputation.RecordDispute(ctx, &models.CreatorDispute{CreatorID: 4, UserID: 10, Outcome: models.DisputeOutcomePlayer, ResolvedBy: 99})
+
+	// Сначала игры создателей с лучшей репутацией; создатель без расчёта получает значение по умолчанию
+	var got []*models.Game
+	cursor := ""
+	for {
+		page, err := gameService.SearchGames(ctx, models.GameSearchFilter{Sort: models.GameSortReputation}, 1, cursor)
+		if err != nil {
+			t.Fatalf("SearchGames() error = %v", err)
+		}
+		got = append(got, page.Games...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	want := []*models.Game{rated, fresh, disputed}
+	if len(got) != len(want) {
+		t.Fatalf("got %d games, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Errorf("game %d has creator reputation %v, want creator %d first", i, got[i].CreatorReputation, want[i].CreatorID)
+		}
+	}
+	if fresh.CreatorReputation != models.DefaultReputationScore {
+		t.Errorf("creator without reputation has %v, want default", fresh.CreatorReputation)
+	}
+}
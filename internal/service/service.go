@@ -6,8 +6,12 @@ import (
 	"github.com/TakuroBreath/wordle/internal/blockchain/mock"
 	"github.com/TakuroBreath/wordle/internal/blockchain/ton"
 	"github.com/TakuroBreath/wordle/internal/config"
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/logger"
 	"github.com/TakuroBreath/wordle/internal/models"
 	"github.com/TakuroBreath/wordle/internal/repository"
+	"github.com/TakuroBreath/wordle/internal/telegram"
+	"go.uber.org/zap"
 )
 
 // Service представляет собой интерфейс для всех сервисов в приложении
@@ -16,6 +20,22 @@ type Service interface {
 	User() models.UserService
 	Lobby() models.LobbyService
 	History() models.HistoryService
+	SideBet() models.SideBetService
+	Jackpot() models.JackpotService
+	Commission() models.CommissionService
+	Referral() models.ReferralService
+	Promo() models.PromoService
+	Achievement() models.AchievementService
+	Leaderboard() models.LeaderboardService
+	PlayerStats() models.PlayerStatsService
+	CreatorAnalytics() models.CreatorAnalyticsService
+	Reputation() models.ReputationService
+	Follow() models.FollowService
+	Notification() models.NotificationService
+	Event() models.EventService
+	Webhook() models.WebhookService
+	Bot() models.BotService
+	Duel() models.DuelService
 	Transaction() models.TransactionService
 	Auth() models.AuthService
 	Job() models.JobService
@@ -25,20 +45,36 @@ type Service interface {
 
 // ServiceImpl представляет собой реализацию сервисного слоя
 type ServiceImpl struct {
-	repo               repository.Repository
-	redisRepo          repository.RedisRepository
-	gameService        models.GameService
-	userService        models.UserService
-	lobbyService       models.LobbyService
-	historyService     models.HistoryService
-	txService          models.TransactionService
-	authService        models.AuthService
-	jobService         models.JobService
-	blockchainProvider blockchain.BlockchainProvider
-	tonService         models.TONService
-	jwtSecret          string
-	botToken           string
-	commissionRate     float64
+	repo                repository.Repository
+	redisRepo           repository.RedisRepository
+	gameService         models.GameService
+	userService         models.UserService
+	lobbyService        models.LobbyService
+	historyService      models.HistoryService
+	sideBetService      models.SideBetService
+	jackpotService      models.JackpotService
+	commissionService   models.CommissionService
+	referralService     models.ReferralService
+	promoService        models.PromoService
+	achievementService  models.AchievementService
+	leaderboardService  models.LeaderboardService
+	playerStatsService  models.PlayerStatsService
+	analyticsService    models.CreatorAnalyticsService
+	reputationService   models.ReputationService
+	followService       models.FollowService
+	notificationService models.NotificationService
+	eventService        models.EventService
+	webhookService      models.WebhookService
+	botService          models.BotService
+	duelService         models.DuelService
+	txService           models.TransactionService
+	authService         models.AuthService
+	jobService          models.JobService
+	blockchainProvider  blockchain.BlockchainProvider
+	tonService          models.TONService
+	jwtSecret           string
+	botToken            string
+	commissionRate      float64
 }
 
 // ServiceConfig конфигурация для создания сервисов
@@ -47,8 +83,20 @@ type ServiceConfig struct {
 	BotToken        string
 	Network         string // "ton" или "evm"
 	UseMockProvider bool
-	CommissionRate  float64 // Ставка комиссии (по умолчанию 0.05 = 5%)
+	CommissionRate  float64                 // Ставка комиссии (по умолчанию 0.05 = 5%)
+	Commission      models.CommissionPolicy // Уровни и переопределения комиссии (ставка по умолчанию - CommissionRate)
+	DictionaryPath  string                  // Путь к файлу словаря (пусто - встроенный словарь)
+	BotUsername     string                  // Username Telegram бота для ссылок-приглашений
+	MiniAppName     string                  // Короткое имя Mini App (пусто - основное приложение бота)
+	BotAPIEndpoint  string                  // Адрес Telegram Bot API (пусто - api.telegram.org)
 	Blockchain      config.BlockchainConfig
+	Jackpot         models.JackpotRule      // Условия джекпота (нулевая доля комиссии - джекпот выключен)
+	Referral        models.ReferralRule     // Условия реферальной программы (нулевая доля комиссии - программа выключена)
+	Achievements    models.AchievementRule  // Награды за достижения и ежедневный вход (нулевые - только бейджи)
+	Leaderboard     models.LeaderboardRule  // Таблицы лидеров и призы сезонов (нет сезонов - призы выключены)
+	Notifications   models.NotificationRule // Отправка уведомлений бота (нулевые - значения по умолчанию)
+	Events          models.EventRule        // Доставка доменных событий подписчикам (нулевые - значения по умолчанию)
+	Webhooks        models.WebhookRule      // Доставка исходящих webhook (нулевые - значения по умолчанию)
 }
 
 // NewService создает новый экземпляр Service
@@ -69,6 +117,9 @@ func NewServiceWithConfig(repo repository.Repository, redisRepo repository.Redis
 		commissionRate = 0.05 // 5% по умолчанию
 	}
 
+	commissionPolicy := cfg.Commission
+	commissionPolicy.DefaultRate = commissionRate
+
 	service := &ServiceImpl{
 		repo:           repo,
 		redisRepo:      redisRepo,
@@ -85,37 +136,191 @@ func NewServiceWithConfig(repo repository.Repository, redisRepo repository.Redis
 	var tonService models.TONService = nil
 	service.tonService = tonService
 
+	// Шина доменных событий: события пишутся в outbox в транзакции изменения состояния,
+	// подписчики регистрируются после создания всех сервисов
+	service.eventService = NewEventService(repo.Event(), repo, cfg.Events)
+
 	// Инициализация сервисов - сначала создаем базовые сервисы
 	txService := NewTransactionServiceImpl(repo.Transaction(), repo.User(), service.blockchainProvider)
 	service.txService = txService
+	if transactionService, ok := txService.(*TransactionServiceImpl); ok {
+		transactionService.SetEvents(service.eventService)
+	}
+
+	service.userService = NewUserService(repo.User(), repo.Transaction(), tonService, service.eventService, UserServiceConfig{
+		MinWithdrawTON:      0.1,
+		MinWithdrawUSDT:     1.0,
+		WithdrawFeeTON:      0.05,
+		WithdrawFeeUSDT:     0.5,
+		WithdrawLockMinutes: 5,
+	})
+
+	// Достижения и серии: бейджи и награды на бонусный баланс, статистика пользователя их показывает
+	service.achievementService = NewAchievementService(repo.Achievement(), repo.User(), txService, cfg.Achievements, repo)
+	if userService, ok := service.userService.(*UserServiceImpl); ok {
+		userService.SetAchievements(service.achievementService)
+	}
+
+	// Проверка членства в Telegram-группе для приватных игр, уведомления и ответы бота
+	var membership models.ChatMembershipChecker
+	var notifier models.UserNotifier
+	var messenger models.BotMessenger
+	if cfg.BotToken != "" {
+		botClient := telegram.NewClient(cfg.BotToken)
+		if cfg.BotAPIEndpoint != "" {
+			botClient.WithAPIEndpoint(cfg.BotAPIEndpoint)
+		}
+		membership = botClient
+		notifier = botClient
+		messenger = botClient
+	}
+
+	// Загружаем словарь для анализа подсказок и оценки сложности слов
+	dict := dictionary.Default()
+	if cfg.DictionaryPath != "" {
+		loaded, err := dictionary.Load(cfg.DictionaryPath)
+		if err != nil {
+			logger.GetLogger(zap.String("service", "dictionary")).Warn("Failed to load dictionary, using built-in one", zap.String("path", cfg.DictionaryPath), zap.Error(err))
+		} else {
+			dict = loaded
+		}
+	}
+
+	// Рефереры получают долю комиссии приглашённых пользователей
+	service.referralService = NewReferralService(repo.Referral(), repo.User(), txService, cfg.Referral, cfg.BotUsername, cfg.MiniAppName, repo)
+
+	// Комиссия сервиса: ставка по умолчанию, уровни по обороту создателя и переопределения
+	service.commissionService = NewCommissionService(repo.Commission(), commissionPolicy, service.referralService)
+
+	// Оценка коэффициентов игр по сложности слова и истории результатов
+	pricing := NewPricingService(repo.History(), dict, service.commissionService)
+
+	service.gameService = NewGameService(GameServiceDeps{
+		GameRepo:    repo.Game(),
+		RedisRepo:   redisRepo,
+		UserService: service.userService,
+		TxService:   txService,
+		TONService:  tonService,
+		Commission:  service.commissionService,
+		Membership:  membership,
+		Notifier:    notifier,
+		Pricing:     pricing,
+		Events:      service.eventService,
+		Transactor:  repo,
+		BotUsername: cfg.BotUsername,
+		MiniAppName: cfg.MiniAppName,
+	})
+
+	// Уведомления бота: события ставят сообщения в очередь, фоновая задача отправляет их с повторами
+	service.notificationService = NewNotificationService(repo.Notification(), repo.User(), repo.Game(), notifier, cfg.Notifications)
+
+	// Подписки на создателей: лента новых игр и уведомления подписчиков при активации игры
+	service.followService = NewFollowService(repo.Follow(), repo.Game(), repo.User(), notifier)
 
-	service.userService = NewUserServiceImpl(repo.User(), txService)
-	service.gameService = NewGameService(repo.Game(), redisRepo, service.userService, tonService, commissionRate)
 	service.historyService = NewHistoryService(repo.History(), repo.Game(), repo.User(), repo.Lobby())
 
-	// Создаем лобби-сервис с зависимостями
-	service.lobbyService = NewLobbyService(
+	service.sideBetService = NewSideBetService(
+		repo.SideBet(),
 		repo.Lobby(),
 		repo.Game(),
 		repo.Attempt(),
-		redisRepo,
+		repo.History(),
 		service.userService,
 		txService,
-		service.historyService,
-		tonService,
-		commissionRate,
+		service.gameService,
+		repo,
 	)
 
-	service.authService = NewAuthService(repo.User(), redisRepo, cfg.JWTSecret, cfg.BotToken)
+	// Джекпот пополняется долей комиссии и разыгрывается среди победителей лобби
+	service.jackpotService = NewJackpotService(repo.Jackpot(), txService, cfg.Jackpot, repo)
 
-	// Создаем сервис фоновых задач
-	service.jobService = NewJobService(
-		service.lobbyService,
-		service.txService,
-		service.gameService,
+	// Промокоды: бонусные средства с отыгрышем, которыми можно оплачивать ставки
+	service.promoService = NewPromoService(repo.Promo(), repo.User(), txService, repo)
+
+	// Статистика игроков обновляется при расчёте каждого лобби
+	service.playerStatsService = NewPlayerStatsService(repo.PlayerStats(), repo.Attempt(), service.achievementService)
+
+	// Создаем лобби-сервис с зависимостями
+	service.lobbyService = NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          repo.Lobby(),
+		GameRepo:           repo.Game(),
+		AttemptRepo:        repo.Attempt(),
+		RedisRepo:          redisRepo,
+		UserService:        service.userService,
+		TransactionService: txService,
+		HistoryService:     service.historyService,
+		TONService:         tonService,
+		Commission:         service.commissionService,
+		Dictionary:         dict,
+		SideBets:           service.sideBetService,
+		GameAccess:         service.gameService,
+		RiskGuard:          service.gameService,
+		Jackpot:            service.jackpotService,
+		Promo:              service.promoService,
+		Events:             service.eventService,
+	})
+
+	// Игра командами в чате с ботом и inline-режим (только при заданном токене бота)
+	if messenger != nil {
+		service.botService = NewBotService(service.userService, service.gameService, service.lobbyService, messenger, cfg.BotUsername)
+	}
+
+	service.duelService = NewDuelService(
+		repo.Duel(),
 		service.userService,
+		txService,
+		service.commissionService,
+		dict,
+		cfg.BotUsername,
+		cfg.MiniAppName,
+		repo,
 	)
 
+	// Таблицы лидеров строятся по истории игр, призы сезонов выплачиваются фоновой задачей
+	service.leaderboardService = NewLeaderboardService(repo.Leaderboard(), txService, cfg.Leaderboard, repo)
+
+	// Аналитика создателей строится по истории, попыткам, транзакциям и журналу комиссии
+	service.analyticsService = NewCreatorAnalyticsService(repo.Analytics(), repo.Game())
+
+	// Репутация создателей пересчитывается при оценках и спорах, а по итогам лобби - фоновой задачей
+	service.reputationService = NewReputationService(repo.Reputation(), repo.Game())
+
+	// Побочные эффекты изменений состояния выполняются подписчиками доменных событий
+	service.eventService.Subscribe(subscriberMetrics, metricsEventHandler,
+		models.EventLobbyFinished, models.EventDepositCredited, models.EventGameActivated,
+		models.EventWithdrawalRequested, models.EventWithdrawalSent)
+	service.eventService.Subscribe(subscriberNotifications,
+		notificationEventHandler(service.notificationService, repo.Lobby(), repo.Game()),
+		models.EventLobbyFinished, models.EventDepositCredited, models.EventGameActivated, models.EventWithdrawalSent)
+	service.eventService.Subscribe(subscriberFollows, gameActivatedHandler(repo.Game(), service.followService),
+		models.EventGameActivated)
+	service.eventService.Subscribe(subscriberAchievements, achievementEventHandler(service.achievementService),
+		models.EventLobbyFinished)
+	service.eventService.Subscribe(subscriberPlayerStats,
+		playerStatsEventHandler(service.playerStatsService, repo.Lobby(), repo.Game()),
+		models.EventLobbyFinished)
+
+	// Исходящие webhook партнёров и создателей получают доменные события своих владельцев
+	service.webhookService = NewWebhookService(repo.Webhook(), cfg.Webhooks)
+	service.eventService.Subscribe(subscriberWebhooks, service.webhookService.HandleEvent, models.WebhookEventTypes...)
+
+	service.authService = NewAuthService(repo.User(), redisRepo, cfg.JWTSecret, cfg.BotToken, service.referralService)
+
+	// Создаем сервис фоновых задач
+	service.jobService = NewJobService(JobServiceDeps{
+		LobbyService:        service.lobbyService,
+		TransactionService:  service.txService,
+		GameService:         service.gameService,
+		UserService:         service.userService,
+		DuelService:         service.duelService,
+		ReferralService:     service.referralService,
+		LeaderboardService:  service.leaderboardService,
+		ReputationService:   service.reputationService,
+		NotificationService: service.notificationService,
+		EventService:        service.eventService,
+		WebhookService:      service.webhookService,
+	})
+
 	return service
 }
 
@@ -184,6 +389,86 @@ func (s *ServiceImpl) History() models.HistoryService {
 	return s.historyService
 }
 
+// SideBet возвращает сервис для работы со ставками зрителей
+func (s *ServiceImpl) SideBet() models.SideBetService {
+	return s.sideBetService
+}
+
+// Jackpot возвращает сервис для работы с джекпотом
+func (s *ServiceImpl) Jackpot() models.JackpotService {
+	return s.jackpotService
+}
+
+// Commission возвращает сервис для работы с комиссией
+func (s *ServiceImpl) Commission() models.CommissionService {
+	return s.commissionService
+}
+
+// Referral возвращает сервис для работы с реферальной программой
+func (s *ServiceImpl) Referral() models.ReferralService {
+	return s.referralService
+}
+
+// Promo возвращает сервис для работы с промокодами и бонусными балансами
+func (s *ServiceImpl) Promo() models.PromoService {
+	return s.promoService
+}
+
+// Achievement возвращает сервис для работы с достижениями и сериями
+func (s *ServiceImpl) Achievement() models.AchievementService {
+	return s.achievementService
+}
+
+// Leaderboard возвращает сервис для работы с таблицами лидеров
+func (s *ServiceImpl) Leaderboard() models.LeaderboardService {
+	return s.leaderboardService
+}
+
+// PlayerStats возвращает сервис для работы со статистикой игроков
+func (s *ServiceImpl) PlayerStats() models.PlayerStatsService {
+	return s.playerStatsService
+}
+
+// CreatorAnalytics возвращает сервис для работы с аналитикой создателей
+func (s *ServiceImpl) CreatorAnalytics() models.CreatorAnalyticsService {
+	return s.analyticsService
+}
+
+// Reputation возвращает сервис для работы с оценками игр и репутацией создателей
+func (s *ServiceImpl) Reputation() models.ReputationService {
+	return s.reputationService
+}
+
+// Follow возвращает сервис для работы с подписками, закладками и лентой игр
+func (s *ServiceImpl) Follow() models.FollowService {
+	return s.followService
+}
+
+// Notification возвращает сервис для работы с уведомлениями бота
+func (s *ServiceImpl) Notification() models.NotificationService {
+	return s.notificationService
+}
+
+// Event возвращает шину доменных событий
+func (s *ServiceImpl) Event() models.EventService {
+	return s.eventService
+}
+
+// Webhook возвращает сервис исходящих webhook
+func (s *ServiceImpl) Webhook() models.WebhookService {
+	return s.webhookService
+}
+
+// Bot возвращает сервис Telegram бота (nil, если токен бота не задан)
+func (s *ServiceImpl) Bot() models.BotService {
+	return s.botService
+}
+
+// Duel возвращает сервис для работы с дуэлями
+func (s *ServiceImpl) Duel() models.DuelService {
+	return s.duelService
+}
+
 // Transaction возвращает сервис для работы с транзакциями
 func (s *ServiceImpl) Transaction() models.TransactionService {
 	return s.txService
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// errSideBetMarketMoved возвращается при ставке по котировке, устаревшей после попытки игрока
+var errSideBetMarketMoved = errors.New("odds have changed: an attempt was made, refresh the market")
+
+// Параметры рынка ставок зрителей
+const (
+	sideBetMargin         = 0.1  // Маржа сервиса, закладываемая в коэффициенты
+	sideBetPriorWinRate   = 0.5  // Априорная доля побед для игр без истории
+	sideBetPriorWeight    = 5.0  // Вес априорной доли побед (в "виртуальных" играх)
+	sideBetMinProbability = 0.01 // Минимальная вероятность исхода
+	sideBetMaxProbability = 0.99 // Максимальная вероятность исхода
+	sideBetMinOdds        = 1.01 // Минимальный коэффициент
+	sideBetMaxOdds        = 100  // Максимальный коэффициент
+)
+
+// SideBetServiceImpl представляет собой реализацию SideBetService
+type SideBetServiceImpl struct {
+	sideBetRepo        models.SideBetRepository
+	lobbyRepo          models.LobbyRepository
+	gameRepo           models.GameRepository
+	attemptRepo        models.AttemptRepository
+	historyRepo        models.HistoryRepository
+	userService        models.UserService
+	transactionService models.TransactionService
+	gameAccess         models.GameAccessChecker
+	transactor         models.Transactor
+	logger             *zap.Logger
+}
+
+// NewSideBetService создает новый экземпляр SideBetService.
+// gameAccess проверяет доступ зрителя к лобби приватных игр.
+// transactor объединяет в одну транзакцию запись ставки, движение баланса зрителя и журнал транзакций
+func NewSideBetService(
+	sideBetRepo models.SideBetRepository,
+	lobbyRepo models.LobbyRepository,
+	gameRepo models.GameRepository,
+	attemptRepo models.AttemptRepository,
+	historyRepo models.HistoryRepository,
+	userService models.UserService,
+	transactionService models.TransactionService,
+	gameAccess models.GameAccessChecker,
+	transactor models.Transactor,
+) models.SideBetService {
+	return &SideBetServiceImpl{
+		sideBetRepo:        sideBetRepo,
+		lobbyRepo:          lobbyRepo,
+		gameRepo:           gameRepo,
+		attemptRepo:        attemptRepo,
+		historyRepo:        historyRepo,
+		userService:        userService,
+		transactionService: transactionService,
+		gameAccess:         gameAccess,
+		transactor:         transactor,
+		logger:             logger.GetLogger(zap.String("service", "side_bet")),
+	}
+}
+
+// GetMarket возвращает состояние лобби для зрителя (только цвета) и текущие котировки.
+// Лобби приватной игры видны только зрителям с доступом к игре
+func (s *SideBetServiceImpl) GetMarket(ctx context.Context, lobbyID uuid.UUID, userID uint64, inviteToken string) (*models.SideBetMarket, error) {
+	lobby, err := s.lobbyRepo.GetByID(ctx, lobbyID)
+	if err != nil {
+		return nil, fmt.Errorf("lobby not found: %w", err)
+	}
+
+	game, err := s.gameRepo.GetByID(ctx, lobby.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("game not found: %w", err)
+	}
+
+	if err := s.checkGameAccess(ctx, game, userID, inviteToken); err != nil {
+		return nil, err
+	}
+
+	return s.buildMarket(ctx, lobby, game)
+}
+
+// PlaceSideBet принимает ставку зрителя на то, что игрок угадает слово не позднее попытки withinTries.
+// atTry - значение tries_used из котировки, по которой делается ставка: после каждой попытки
+// игрока рынок закрывается и ставки по старым котировкам отклоняются
+func (s *SideBetServiceImpl) PlaceSideBet(ctx context.Context, lobbyID uuid.UUID, userID uint64, withinTries int, amount float64, atTry int, inviteToken string) (*models.SideBet, error) {
+	log := s.logger.With(zap.String("method", "PlaceSideBet"),
+		zap.String("lobby_id", lobbyID.String()),
+		zap.Uint64("user_id", userID))
+
+	if amount <= 0 {
+		return nil, errors.New("bet amount must be positive")
+	}
+
+	lobby, err := s.lobbyRepo.GetByID(ctx, lobbyID)
+	if err != nil {
+		return nil, fmt.Errorf("lobby not found: %w", err)
+	}
+
+	game, err := s.gameRepo.GetByID(ctx, lobby.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("game not found: %w", err)
+	}
+
+	if err := s.checkGameAccess(ctx, game, userID, inviteToken); err != nil {
+		return nil, err
+	}
+
+	// Игрок и создатель игры не могут ставить на лобби
+	if lobby.UserID == userID {
+		return nil, errors.New("player cannot bet on own lobby")
+	}
+	if game.CreatorID == userID {
+		return nil, errors.New("game creator cannot bet on lobbies of own game")
+	}
+
+	if !lobby.CanMakeAttempt() {
+		return nil, errors.New("side bets are closed for this lobby")
+	}
+	if lobby.TriesUsed != atTry {
+		return nil, errSideBetMarketMoved
+	}
+
+	if withinTries <= lobby.TriesUsed || withinTries > lobby.MaxTries {
+		return nil, fmt.Errorf("within_tries must be between %d and %d", lobby.TriesUsed+1, lobby.MaxTries)
+	}
+
+	if amount < game.MinBet || amount > game.MaxBet {
+		return nil, fmt.Errorf("bet amount must be between %.4f and %.4f", game.MinBet, game.MaxBet)
+	}
+
+	market, err := s.buildMarket(ctx, lobby, game)
+	if err != nil {
+		return nil, err
+	}
+
+	var odds float64
+	for _, q := range market.Quotes {
+		if q.WithinTries == withinTries {
+			odds = q.Odds
+			break
+		}
+	}
+	if odds == 0 {
+		return nil, errors.New("no odds available for this outcome")
+	}
+
+	// Проверяем баланс зрителя, списание выполняется вместе с записью ставки
+	hasBalance, err := s.userService.ValidateBalance(ctx, userID, amount, game.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate balance: %w", err)
+	}
+	if !hasBalance {
+		return nil, fmt.Errorf("insufficient %s balance", game.Currency)
+	}
+
+	bet := &models.SideBet{
+		ID:          uuid.New(),
+		LobbyID:     lobby.ID,
+		GameID:      game.ID,
+		UserID:      userID,
+		WithinTries: withinTries,
+		PlacedAtTry: lobby.TriesUsed,
+		Amount:      amount,
+		Odds:        odds,
+		Currency:    game.Currency,
+		Status:      models.SideBetStatusOpen,
+	}
+
+	// Ставка записывается условно по tries_used лобби: если игрок успел сделать попытку после
+	// чтения котировки, ставка не записывается и баланс не списывается
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		placed, err := s.sideBetRepo.Place(ctx, bet)
+		if err != nil {
+			return fmt.Errorf("failed to create side bet: %w", err)
+		}
+		if !placed {
+			return errSideBetMarketMoved
+		}
+
+		// Списание условное: параллельная трата баланса не уведёт его в минус
+		if err := s.userService.DebitBalance(ctx, userID, game.Currency, amount); err != nil {
+			if errors.Is(err, models.ErrInsufficientFunds) {
+				return fmt.Errorf("insufficient %s balance", game.Currency)
+			}
+			return fmt.Errorf("failed to deduct side bet: %w", err)
+		}
+
+		return s.createTransaction(ctx, bet, models.TransactionTypeSideBet, amount,
+			fmt.Sprintf("Side bet on lobby %s (within %d tries)", lobby.ID, withinTries))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Side bet placed",
+		zap.String("bet_id", bet.ID.String()),
+		zap.Int("within_tries", withinTries),
+		zap.Float64("amount", amount),
+		zap.Float64("odds", odds))
+
+	return bet, nil
+}
+
+// checkGameAccess проверяет доступ зрителя к приватной игре
+func (s *SideBetServiceImpl) checkGameAccess(ctx context.Context, game *models.Game, userID uint64, inviteToken string) error {
+	if !game.IsPrivate() {
+		return nil
+	}
+	if s.gameAccess == nil {
+		return models.ErrGameAccessDenied
+	}
+	return s.gameAccess.CheckAccess(ctx, game, userID, inviteToken)
+}
+
+// GetLobbySideBets возвращает все ставки зрителей на лобби
+func (s *SideBetServiceImpl) GetLobbySideBets(ctx context.Context, lobbyID uuid.UUID) ([]*models.SideBet, error) {
+	return s.sideBetRepo.GetByLobbyID(ctx, lobbyID)
+}
+
+// GetUserSideBets возвращает ставки зрителя
+func (s *SideBetServiceImpl) GetUserSideBets(ctx context.Context, userID uint64, limit, offset int) ([]*models.SideBet, error) {
+	return s.sideBetRepo.GetByUserID(ctx, userID, limit, offset)
+}
+
+// SettleLobby рассчитывает открытые ставки по завершённому лобби
+func (s *SideBetServiceImpl) SettleLobby(ctx context.Context, lobby *models.Lobby, finalStatus string) error {
+	log := s.logger.With(zap.String("method", "SettleLobby"),
+		zap.String("lobby_id", lobby.ID.String()),
+		zap.String("status", finalStatus))
+
+	bets, err := s.sideBetRepo.GetOpenByLobbyID(ctx, lobby.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get open side bets: %w", err)
+	}
+
+	for _, bet := range bets {
+		if err := s.settleBet(ctx, bet, lobby, finalStatus); err != nil {
+			log.Error("Failed to settle side bet", zap.String("bet_id", bet.ID.String()), zap.Error(err))
+		}
+	}
+
+	if len(bets) > 0 {
+		log.Info("Side bets settled", zap.Int("count", len(bets)))
+	}
+
+	return nil
+}
+
+// GetAccounts возвращает счета рынка ставок зрителей
+func (s *SideBetServiceImpl) GetAccounts(ctx context.Context) ([]*models.SideBetAccount, error) {
+	return s.sideBetRepo.GetAccounts(ctx)
+}
+
+// settleBet рассчитывает ставку: статус, списание выплаты со счёта рынка, зачисление зрителю
+// и запись в журнал выполняются в одной транзакции
+func (s *SideBetServiceImpl) settleBet(ctx context.Context, bet *models.SideBet, lobby *models.Lobby, finalStatus string) error {
+	status, payout := settleSideBet(bet, finalStatus, lobby.TriesUsed)
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		settled, err := s.sideBetRepo.Settle(ctx, bet.ID, status, payout)
+		if err != nil {
+			return err
+		}
+		if !settled || payout <= 0 {
+			// Ставка уже рассчитана параллельно или проиграла
+			return nil
+		}
+
+		if err := s.updateBalance(ctx, bet.UserID, bet.Currency, payout); err != nil {
+			return fmt.Errorf("failed to credit side bet payout: %w", err)
+		}
+
+		txType := models.TransactionTypeSideBetPayout
+		description := fmt.Sprintf("Side bet payout for lobby %s", lobby.ID)
+		if status == models.SideBetStatusRefunded {
+			txType = models.TransactionTypeRefund
+			description = fmt.Sprintf("Side bet refund for lobby %s", lobby.ID)
+		}
+		return s.createTransaction(ctx, bet, txType, payout, description)
+	})
+}
+
+// buildMarket формирует рынок ставок для лобби
+func (s *SideBetServiceImpl) buildMarket(ctx context.Context, lobby *models.Lobby, game *models.Game) (*models.SideBetMarket, error) {
+	attempts, err := s.attemptRepo.GetByLobbyID(ctx, lobby.ID, lobby.MaxTries, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempts: %w", err)
+	}
+
+	// Зритель видит только цвета, буквы не раскрываются
+	board := make([]models.SpectatorAttempt, 0, len(attempts))
+	guesses := make([]dictionary.Guess, 0, len(attempts))
+	for _, a := range attempts {
+		board = append(board, models.SpectatorAttempt{Result: a.Result, CreatedAt: a.CreatedAt})
+		guesses = append(guesses, dictionary.Guess{Word: a.Word, Result: a.Result})
+	}
+
+	total, wins, err := s.historyRepo.CountGameResults(ctx, game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game results: %w", err)
+	}
+	winRate := (float64(wins) + sideBetPriorWinRate*sideBetPriorWeight) / (float64(total) + sideBetPriorWeight)
+
+	greens, yellows := summarizeFeedback(guesses, game.Length)
+
+	market := &models.SideBetMarket{
+		LobbyID:       lobby.ID,
+		GameShortID:   game.ShortID,
+		LobbyStatus:   lobby.Status,
+		Open:          lobby.CanMakeAttempt(),
+		TriesUsed:     lobby.TriesUsed,
+		MaxTries:      lobby.MaxTries,
+		WordLength:    game.Length,
+		Currency:      game.Currency,
+		MinStake:      game.MinBet,
+		MaxStake:      game.MaxBet,
+		RemainingTime: lobby.GetRemainingTime(),
+		WinRate:       winRate,
+		Board:         board,
+		Quotes:        []models.SideBetQuote{},
+	}
+
+	if !market.Open {
+		return market, nil
+	}
+
+	for within := lobby.TriesUsed + 1; within <= lobby.MaxTries; within++ {
+		p := calculateSideBetProbability(winRate, lobby.MaxTries, lobby.TriesUsed, within, game.Length, greens, yellows)
+		market.Quotes = append(market.Quotes, models.SideBetQuote{
+			WithinTries: within,
+			Probability: p,
+			Odds:        calculateSideBetOdds(p),
+		})
+	}
+
+	return market, nil
+}
+
+// updateBalance изменяет баланс пользователя в валюте ставки
+func (s *SideBetServiceImpl) updateBalance(ctx context.Context, userID uint64, currency string, amount float64) error {
+	if currency == models.CurrencyTON {
+		return s.userService.UpdateTonBalance(ctx, userID, amount)
+	}
+	return s.userService.UpdateUsdtBalance(ctx, userID, amount)
+}
+
+// createTransaction записывает движение средств по ставке зрителя
+func (s *SideBetServiceImpl) createTransaction(ctx context.Context, bet *models.SideBet, txType string, amount float64, description string) error {
+	tx := &models.Transaction{
+		UserID:      bet.UserID,
+		Type:        txType,
+		Amount:      amount,
+		Currency:    bet.Currency,
+		Status:      models.TransactionStatusCompleted,
+		GameID:      &bet.GameID,
+		LobbyID:     &bet.LobbyID,
+		Description: description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.transactionService.CreateTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to create side bet transaction: %w", err)
+	}
+	return nil
+}
+
+// settleSideBet определяет исход ставки по финальному статусу лобби.
+// Если лобби отменено, завершилось с ошибкой или досрочной выплатой, исход не определён и ставка возвращается
+func settleSideBet(bet *models.SideBet, finalStatus string, triesUsed int) (status string, payout float64) {
+	switch finalStatus {
+	case models.LobbyStatusFailedInternal, models.LobbyStatusCanceled, models.LobbyStatusCashedOut:
+		return models.SideBetStatusRefunded, bet.Amount
+	case models.LobbyStatusSuccess:
+		if triesUsed <= bet.WithinTries {
+			return models.SideBetStatusWon, bet.PotentialPayout()
+		}
+	}
+	return models.SideBetStatusLost, 0
+}
+
+// calculateSideBetProbability оценивает вероятность того, что игрок угадает слово не позднее попытки withinTries.
+// Историческая доля побед переводится в вероятность угадать за одну попытку, которая затем
+// увеличивается пропорционально уже открытой информации о слове
+func calculateSideBetProbability(winRate float64, maxTries, triesUsed, withinTries, length, greens, yellows int) float64 {
+	remaining := withinTries - triesUsed
+	if remaining <= 0 || maxTries <= 0 || length <= 0 {
+		return 0
+	}
+
+	winRate = math.Max(sideBetMinProbability, math.Min(winRate, sideBetMaxProbability))
+
+	// Вероятность угадать за одну попытку: 1 - (1 - h)^maxTries = winRate
+	perTry := 1 - math.Pow(1-winRate, 1/float64(maxTries))
+
+	knowledge := math.Min(1, (float64(greens)+0.5*float64(yellows))/float64(length))
+	perTry += (1 - perTry) * knowledge * 0.5
+
+	p := 1 - math.Pow(1-perTry, float64(remaining))
+
+	return math.Max(sideBetMinProbability, math.Min(p, sideBetMaxProbability))
+}
+
+// calculateSideBetOdds вычисляет десятичный коэффициент с учётом маржи сервиса
+func calculateSideBetOdds(probability float64) float64 {
+	if probability <= 0 {
+		return 0
+	}
+	odds := (1 - sideBetMargin) / probability
+	odds = math.Floor(odds*100) / 100
+	return math.Max(sideBetMinOdds, math.Min(odds, sideBetMaxOdds))
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/dictionary"
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCalculateSideBetProbability(t *testing.T) {
+	base := calculateSideBetProbability(0.5, 6, 0, 6, 5, 0, 0)
+	if math.Abs(base-0.5) > 0.01 {
+		t.Errorf("probability for all tries = %v, want ~0.5", base)
+	}
+
+	short := calculateSideBetProbability(0.5, 6, 0, 2, 5, 0, 0)
+	if short >= base {
+		t.Errorf("probability within 2 tries (%v) should be less than within 6 (%v)", short, base)
+	}
+
+	informed := calculateSideBetProbability(0.5, 6, 2, 4, 5, 4, 1)
+	uninformed := calculateSideBetProbability(0.5, 6, 2, 4, 5, 0, 0)
+	if informed <= uninformed {
+		t.Errorf("probability with hints (%v) should be greater than without (%v)", informed, uninformed)
+	}
+
+	if p := calculateSideBetProbability(0.5, 6, 3, 3, 5, 0, 0); p != 0 {
+		t.Errorf("probability for past tries = %v, want 0", p)
+	}
+
+	if p := calculateSideBetProbability(1, 6, 0, 6, 5, 5, 0); p > sideBetMaxProbability {
+		t.Errorf("probability = %v, want <= %v", p, sideBetMaxProbability)
+	}
+}
+
+func TestCalculateSideBetOdds(t *testing.T) {
+	tests := []struct {
+		name        string
+		probability float64
+		expected    float64
+	}{
+		{name: "равные шансы", probability: 0.5, expected: 1.8},
+		{name: "маловероятный исход", probability: 0.1, expected: 9},
+		{name: "почти наверняка", probability: 0.99, expected: sideBetMinOdds},
+		{name: "очень маловероятный исход", probability: 0.001, expected: sideBetMaxOdds},
+		{name: "невозможный исход", probability: 0, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateSideBetOdds(tt.probability)
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("calculateSideBetOdds() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSettleSideBet(t *testing.T) {
+	bet := &models.SideBet{WithinTries: 3, Amount: 2, Odds: 2.5}
+
+	tests := []struct {
+		name        string
+		finalStatus string
+		triesUsed   int
+		wantStatus  string
+		wantPayout  float64
+	}{
+		{name: "угадал вовремя", finalStatus: models.LobbyStatusSuccess, triesUsed: 3, wantStatus: models.SideBetStatusWon, wantPayout: 5},
+		{name: "угадал позже", finalStatus: models.LobbyStatusSuccess, triesUsed: 4, wantStatus: models.SideBetStatusLost},
+		{name: "не угадал", finalStatus: models.LobbyStatusFailedTries, triesUsed: 6, wantStatus: models.SideBetStatusLost},
+		{name: "досрочная выплата", finalStatus: models.LobbyStatusCashedOut, triesUsed: 2, wantStatus: models.SideBetStatusRefunded, wantPayout: 2},
+		{name: "отменено", finalStatus: models.LobbyStatusCanceled, triesUsed: 1, wantStatus: models.SideBetStatusRefunded, wantPayout: 2},
+		{name: "внутренняя ошибка", finalStatus: models.LobbyStatusFailedInternal, triesUsed: 1, wantStatus: models.SideBetStatusRefunded, wantPayout: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, payout := settleSideBet(bet, tt.finalStatus, tt.triesUsed)
+			if status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", status, tt.wantStatus)
+			}
+			if math.Abs(payout-tt.wantPayout) > 1e-9 {
+				t.Errorf("payout = %v, want %v", payout, tt.wantPayout)
+			}
+		})
+	}
+}
+
+func TestSideBetService_PlaceAndSettle(t *testing.T) {
+	ctx := context.Background()
+
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	attemptRepo := mocks.NewMockAttemptRepository()
+	historyRepo := mocks.NewMockHistoryRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+	sideBetRepo := mocks.NewMockSideBetRepository(lobbyRepo)
+
+	txService := NewTransactionServiceImpl(txRepo, userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	historyService := NewHistoryService(historyRepo, gameRepo, userRepo, lobbyRepo)
+	sideBetService := NewSideBetService(sideBetRepo, lobbyRepo, gameRepo, attemptRepo, historyRepo, userService, txService,
+		nil, mocks.NewMockTransactor())
+	lobbyService := NewLobbyService(LobbyServiceDeps{
+		LobbyRepo:          lobbyRepo,
+		GameRepo:           gameRepo,
+		AttemptRepo:        attemptRepo,
+		RedisRepo:          mocks.NewMockRedisRepository(),
+		UserService:        userService,
+		TransactionService: txService,
+		HistoryService:     historyService,
+		Commission:         newTestCommissionService(),
+		Dictionary:         dictionary.New([]string{"слово", "слава", "сокол"}),
+		SideBets:           sideBetService,
+	})
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 1, Username: "player", BalanceTon: 10})
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 3, Username: "spectator", BalanceTon: 10})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 2, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON,
+		RewardPoolTon: 100, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+
+	lobby := &models.Lobby{GameID: game.ID, UserID: 1, BetAmount: 1}
+	if err := lobbyService.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("CreateLobby() error = %v", err)
+	}
+
+	if _, err := sideBetService.PlaceSideBet(ctx, lobby.ID, 1, 3, 1, 0, ""); err == nil {
+		t.Error("PlaceSideBet() by the player should fail")
+	}
+	if _, err := sideBetService.PlaceSideBet(ctx, lobby.ID, 2, 3, 1, 0, ""); err == nil {
+		t.Error("PlaceSideBet() by the game creator should fail")
+	}
+
+	bet, err := sideBetService.PlaceSideBet(ctx, lobby.ID, 3, 3, 1, 0, "")
+	if err != nil {
+		t.Fatalf("PlaceSideBet() error = %v", err)
+	}
+
+	if _, err := lobbyService.ProcessAttempt(ctx, lobby.ID, "слава"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+
+	market, err := sideBetService.GetMarket(ctx, lobby.ID, 3, "")
+	if err != nil {
+		t.Fatalf("GetMarket() error = %v", err)
+	}
+	if len(market.Board) != 1 || len(market.Quotes) != 5 {
+		t.Errorf("market board = %d, quotes = %d, want 1 and 5", len(market.Board), len(market.Quotes))
+	}
+
+	if _, err := sideBetService.PlaceSideBet(ctx, lobby.ID, 3, 3, 1, 0, ""); err == nil {
+		t.Error("PlaceSideBet() with stale odds should fail")
+	}
+
+	if _, err := lobbyService.ProcessAttempt(ctx, lobby.ID, "слово"); err != nil {
+		t.Fatalf("ProcessAttempt() error = %v", err)
+	}
+
+	settled, err := sideBetRepo.GetByID(ctx, bet.ID)
+	if err != nil {
+		t.Fatalf("side bet not found: %v", err)
+	}
+	if settled.Status != models.SideBetStatusWon {
+		t.Errorf("side bet status = %s, want %s", settled.Status, models.SideBetStatusWon)
+	}
+
+	spectator, _ := userRepo.GetByTelegramID(ctx, 3)
+	if want := 10 - 1 + bet.PotentialPayout(); math.Abs(spectator.BalanceTon-want) > 1e-9 {
+		t.Errorf("spectator balance = %v, want %v", spectator.BalanceTon, want)
+	}
+
+	// Ставка и выплата проведены через счёт рынка ставок
+	accounts, _ := sideBetService.GetAccounts(ctx)
+	if want := 1 - bet.PotentialPayout(); len(accounts) != 1 || math.Abs(accounts[0].Balance-want) > 1e-9 {
+		t.Errorf("side bet accounts = %+v, want balance %v", accounts, want)
+	}
+
+	// Ставки зрителей не затрагивают пул наград создателя
+	updatedGame, _ := gameRepo.GetByID(ctx, game.ID)
+	if updatedGame.ReservedAmount != 0 {
+		t.Errorf("reserved amount = %v, want 0", updatedGame.ReservedAmount)
+	}
+}
+
+// staleLobbyRepository возвращает снимок лобби, прочитанный до попытки игрока
+type staleLobbyRepository struct {
+	models.LobbyRepository
+	stale *models.Lobby
+}
+
+func (r *staleLobbyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Lobby, error) {
+	copied := *r.stale
+	return &copied, nil
+}
+
+func TestSideBetService_AttemptClosesMarket(t *testing.T) {
+	ctx := context.Background()
+
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	txRepo := mocks.NewMockTransactionRepository()
+	sideBetRepo := mocks.NewMockSideBetRepository(lobbyRepo)
+	txService := NewTransactionServiceImpl(txRepo, userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 3, Username: "spectator", BalanceTon: 10})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 2, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON, Status: models.GameStatusActive,
+	}
+	_ = gameRepo.Create(ctx, game)
+	lobby := &models.Lobby{
+		ID: uuid.New(), GameID: game.ID, UserID: 1, BetAmount: 1, MaxTries: 6,
+		Status: models.LobbyStatusActive, ExpiresAt: time.Now().Add(time.Minute),
+	}
+	_ = lobbyRepo.Create(ctx, lobby)
+
+	// Сервис прочитал лобби до попытки, а игрок успел сделать попытку до записи ставки
+	stale := *lobby
+	stored, _ := lobbyRepo.GetByID(ctx, lobby.ID)
+	stored.TriesUsed = 1
+
+	sideBetService := NewSideBetService(sideBetRepo, &staleLobbyRepository{LobbyRepository: lobbyRepo, stale: &stale},
+		gameRepo, mocks.NewMockAttemptRepository(), mocks.NewMockHistoryRepository(), userService, txService,
+		nil, mocks.NewMockTransactor())
+
+	if _, err := sideBetService.PlaceSideBet(ctx, lobby.ID, 3, 3, 1, 0, ""); err == nil {
+		t.Fatal("PlaceSideBet() after a concurrent attempt should fail")
+	}
+
+	bets, _ := sideBetRepo.GetByLobbyID(ctx, lobby.ID)
+	if len(bets) != 0 {
+		t.Errorf("side bets = %d, want 0", len(bets))
+	}
+	assertTonBalance(t, userRepo, 3, 10)
+	if txs, _ := txRepo.GetByUserID(ctx, 3, 10, 0); len(txs) != 0 {
+		t.Errorf("spectator transactions = %d, want 0", len(txs))
+	}
+}
+
+func TestSideBetService_PrivateGameRequiresAccess(t *testing.T) {
+	ctx := context.Background()
+
+	userRepo := mocks.NewMockUserRepository()
+	gameRepo := mocks.NewMockGameRepository()
+	lobbyRepo := mocks.NewMockLobbyRepository()
+	sideBetRepo := mocks.NewMockSideBetRepository(lobbyRepo)
+	txService := NewTransactionServiceImpl(mocks.NewMockTransactionRepository(), userRepo, nil)
+	userService := NewUserServiceImpl(userRepo, txService)
+	gameService := NewGameService(GameServiceDeps{GameRepo: gameRepo, Commission: newTestCommissionService()})
+	sideBetService := NewSideBetService(sideBetRepo, lobbyRepo, gameRepo, mocks.NewMockAttemptRepository(),
+		mocks.NewMockHistoryRepository(), userService, txService, gameService, mocks.NewMockTransactor())
+
+	_ = userRepo.Create(ctx, &models.User{TelegramID: 3, Username: "spectator", BalanceTon: 10})
+	game := &models.Game{
+		ID: uuid.New(), CreatorID: 2, Word: "слово", Length: 5, MaxTries: 6, TimeLimit: 5,
+		MinBet: 0.1, MaxBet: 5, RewardMultiplier: 2, Currency: models.CurrencyTON, Status: models.GameStatusActive,
+		Visibility: models.GameVisibilityPrivate, InviteToken: "abc123",
+	}
+	_ = gameRepo.Create(ctx, game)
+	lobby := &models.Lobby{
+		ID: uuid.New(), GameID: game.ID, UserID: 1, BetAmount: 1, MaxTries: 6,
+		Status: models.LobbyStatusActive, ExpiresAt: time.Now().Add(time.Minute),
+	}
+	_ = lobbyRepo.Create(ctx, lobby)
+
+	if _, err := sideBetService.GetMarket(ctx, lobby.ID, 3, ""); !errors.Is(err, models.ErrGameAccessDenied) {
+		t.Errorf("GetMarket() without invite error = %v, want %v", err, models.ErrGameAccessDenied)
+	}
+	if _, err := sideBetService.PlaceSideBet(ctx, lobby.ID, 3, 3, 1, 0, ""); !errors.Is(err, models.ErrGameAccessDenied) {
+		t.Errorf("PlaceSideBet() without invite error = %v, want %v", err, models.ErrGameAccessDenied)
+	}
+	assertTonBalance(t, userRepo, 3, 10)
+
+	if _, err := sideBetService.GetMarket(ctx, lobby.ID, 3, "abc123"); err != nil {
+		t.Errorf("GetMarket() with invite error = %v", err)
+	}
+	if _, err := sideBetService.PlaceSideBet(ctx, lobby.ID, 3, 3, 1, 0, "abc123"); err != nil {
+		t.Errorf("PlaceSideBet() with invite error = %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/TakuroBreath/wordle/internal/blockchain"
@@ -17,6 +18,7 @@ type TransactionServiceImpl struct {
 	transactionRepo    models.TransactionRepository
 	userRepo           models.UserRepository
 	blockchainProvider blockchain.BlockchainProvider
+	events             models.EventService
 }
 
 // NewTransactionServiceImpl создает новый экземпляр TransactionServiceImpl
@@ -37,6 +39,17 @@ func (s *TransactionServiceImpl) SetBlockchainProvider(provider blockchain.Block
 	s.blockchainProvider = provider
 }
 
+// SetEvents подключает шину доменных событий: зачисление депозитов и выводы записываются в outbox
+// в одной транзакции с изменением баланса (для отложенной инициализации)
+func (s *TransactionServiceImpl) SetEvents(events models.EventService) {
+	s.events = events
+}
+
+// transactionEvent формирует событие по транзакции
+func transactionEvent(eventType string, tx *models.Transaction) (*models.DomainEvent, error) {
+	return models.NewDomainEvent(eventType, tx.ID, models.NewTransactionEvent(tx))
+}
+
 // CreateTransaction создает новую транзакцию
 func (s *TransactionServiceImpl) CreateTransaction(ctx context.Context, tx *models.Transaction) error {
 	if tx == nil {
@@ -64,12 +77,19 @@ func (s *TransactionServiceImpl) CreateTransaction(ctx context.Context, tx *mode
 	// }
 
 	allowedTypes := map[string]bool{
-		models.TransactionTypeDeposit:    true,
-		models.TransactionTypeWithdraw:   true,
-		models.TransactionTypeBet:        true,
-		models.TransactionTypeReward:     true,
-		models.TransactionTypeCommission: true, // Если есть комиссия
-		models.TransactionTypeRefund:     true, // Если есть возвраты
+		models.TransactionTypeDeposit:         true,
+		models.TransactionTypeWithdraw:        true,
+		models.TransactionTypeBet:             true,
+		models.TransactionTypeReward:          true,
+		models.TransactionTypeCommission:      true, // Если есть комиссия
+		models.TransactionTypeRefund:          true, // Если есть возвраты
+		models.TransactionTypeSideBet:         true,
+		models.TransactionTypeSideBetPayout:   true,
+		models.TransactionTypeDuelStake:       true,
+		models.TransactionTypeDuelPayout:      true,
+		models.TransactionTypeDuelRefund:      true,
+		models.TransactionTypeBonus:           true,
+		models.TransactionTypeBonusConversion: true,
 	}
 	if !allowedTypes[tx.Type] {
 		return fmt.Errorf("invalid transaction type: %s", tx.Type)
@@ -105,12 +125,33 @@ func (s *TransactionServiceImpl) GetTransaction(ctx context.Context, id uuid.UUI
 	return s.transactionRepo.GetByID(ctx, id)
 }
 
-// GetUserTransactions получает транзакции пользователя
-func (s *TransactionServiceImpl) GetUserTransactions(ctx context.Context, userID uint64, limit, offset int) ([]*models.Transaction, error) {
+// GetUserTransactions получает страницу транзакций пользователя, начиная с новых
+func (s *TransactionServiceImpl) GetUserTransactions(ctx context.Context, userID uint64, limit int, cursor string) (*models.TransactionPage, error) {
 	if userID == 0 {
 		return nil, errors.New("user ID (TelegramID) cannot be zero")
 	}
-	return s.transactionRepo.GetByUserID(ctx, userID, limit, offset)
+
+	after, err := models.DecodePageCursor(cursor, "")
+	if err != nil {
+		return nil, err
+	}
+
+	limit = pageLimit(limit)
+	transactions, err := s.transactionRepo.GetByUserIDAfter(ctx, userID, limit+1, after)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TransactionPage{Transactions: transactions}
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+		last := page.Transactions[limit-1]
+		page.NextCursor = (&models.PageCursor{Time: last.CreatedAt, ID: last.ID}).Encode()
+	}
+	if page.Transactions == nil {
+		page.Transactions = []*models.Transaction{}
+	}
+	return page, nil
 }
 
 // UpdateTransaction обновляет транзакцию (в основном статус)
@@ -159,10 +200,22 @@ func (s *TransactionServiceImpl) DeleteTransaction(ctx context.Context, id uuid.
 // GetTransactionsByType получает транзакции по типу
 func (s *TransactionServiceImpl) GetTransactionsByType(ctx context.Context, transactionType string, limit, offset int) ([]*models.Transaction, error) {
 	allowedTypes := map[string]bool{ // Перепроверить с константами в models
-		models.TransactionTypeDeposit:  true,
-		models.TransactionTypeWithdraw: true,
-		models.TransactionTypeBet:      true,
-		models.TransactionTypeReward:   true,
+		models.TransactionTypeDeposit:          true,
+		models.TransactionTypeWithdraw:         true,
+		models.TransactionTypeBet:              true,
+		models.TransactionTypeReward:           true,
+		models.TransactionTypeSideBet:          true,
+		models.TransactionTypeSideBetPayout:    true,
+		models.TransactionTypeDuelStake:        true,
+		models.TransactionTypeDuelPayout:       true,
+		models.TransactionTypeDuelRefund:       true,
+		models.TransactionTypePoolWithdraw:     true,
+		models.TransactionTypePoolTopUp:        true,
+		models.TransactionTypeJackpot:          true,
+		models.TransactionTypeReferral:         true,
+		models.TransactionTypeBonus:            true,
+		models.TransactionTypeBonusConversion:  true,
+		models.TransactionTypeLeaderboardPrize: true,
 	}
 	if !allowedTypes[transactionType] {
 		return nil, fmt.Errorf("invalid transaction type for query: %s", transactionType)
@@ -185,6 +238,14 @@ func (s *TransactionServiceImpl) GetUserBalance(ctx context.Context, userID uint
 	return user.BalanceTon, nil
 }
 
+// GetGameTotals возвращает суммы завершённых транзакций игры по типам
+func (s *TransactionServiceImpl) GetGameTotals(ctx context.Context, gameID uuid.UUID) (map[string]float64, error) {
+	if gameID == uuid.Nil {
+		return nil, errors.New("game ID cannot be nil")
+	}
+	return s.transactionRepo.SumByGame(ctx, gameID)
+}
+
 // GetTransactionStats получает статистику транзакций пользователя
 func (s *TransactionServiceImpl) GetTransactionStats(ctx context.Context, userID uint64) (map[string]interface{}, error) {
 	if userID == 0 {
@@ -330,13 +391,16 @@ func (s *TransactionServiceImpl) ProcessWithdraw(ctx context.Context, userID uin
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := s.transactionRepo.Create(ctx, tx); err != nil {
-		return nil, fmt.Errorf("failed to create withdrawal transaction: %w", err)
+	err = withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+		if err := s.transactionRepo.Create(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to create withdrawal transaction: %w", err)
+		}
+		return transactionEvent(models.EventWithdrawalRequested, tx)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Увеличиваем счётчик ожидающих выводов
-	metrics.IncrementPendingWithdrawals()
-
 	// Списание с баланса происходит ПОСЛЕ успешного подтверждения вывода
 	// Здесь мы только создаем транзакцию. Фактическое обновление баланса - отдельный шаг.
 	return tx, nil
@@ -439,6 +503,160 @@ func (s *TransactionServiceImpl) ProcessReward(ctx context.Context, userID uint6
 	return nil
 }
 
+// HoldDuelStake списывает ставку дуэли с баланса пользователя в эскроу
+func (s *TransactionServiceImpl) HoldDuelStake(ctx context.Context, userID uint64, amount float64, currency string, duelID uuid.UUID) error {
+	if amount <= 0 {
+		return errors.New("duel stake must be positive")
+	}
+
+	err := s.applyBalanceTransaction(ctx, userID, models.TransactionTypeDuelStake, -amount, 0, currency,
+		fmt.Sprintf("Duel %s stake", duelID), nil)
+	if errors.Is(err, models.ErrInsufficientFunds) {
+		return fmt.Errorf("insufficient %s balance for duel stake", currency)
+	}
+	return err
+}
+
+// RefundDuelStake возвращает ставку дуэли из эскроу на баланс пользователя
+func (s *TransactionServiceImpl) RefundDuelStake(ctx context.Context, userID uint64, amount float64, currency string, duelID uuid.UUID) error {
+	if amount <= 0 {
+		return errors.New("duel refund must be positive")
+	}
+
+	return s.applyBalanceTransaction(ctx, userID, models.TransactionTypeDuelRefund, amount, 0, currency,
+		fmt.Sprintf("Duel %s refund", duelID), nil)
+}
+
+// PayDuelWinnings выплачивает победителю дуэли эскроу за вычетом комиссии
+func (s *TransactionServiceImpl) PayDuelWinnings(ctx context.Context, userID uint64, amount, commission float64, currency string, duelID uuid.UUID) error {
+	if amount <= 0 {
+		return errors.New("duel payout must be positive")
+	}
+
+	if err := s.applyBalanceTransaction(ctx, userID, models.TransactionTypeDuelPayout, amount, commission, currency,
+		fmt.Sprintf("Duel %s winnings", duelID), nil); err != nil {
+		return err
+	}
+
+	metrics.RecordReward(currency, amount)
+
+	return nil
+}
+
+// RefundGamePool возвращает создателю остаток пула закрытой игры
+func (s *TransactionServiceImpl) RefundGamePool(ctx context.Context, creatorID uint64, amount float64, currency string, gameID uuid.UUID) error {
+	if amount <= 0 {
+		return errors.New("game pool refund must be positive")
+	}
+
+	return s.applyBalanceTransaction(ctx, creatorID, models.TransactionTypeGameRefund, amount, 0, currency,
+		fmt.Sprintf("Game %s pool refund", gameID), &gameID)
+}
+
+// WithdrawGamePool зачисляет создателю сумму, выведенную из пула активной игры
+func (s *TransactionServiceImpl) WithdrawGamePool(ctx context.Context, creatorID uint64, amount float64, currency string, gameID uuid.UUID) error {
+	if amount <= 0 {
+		return errors.New("pool withdrawal must be positive")
+	}
+
+	return s.applyBalanceTransaction(ctx, creatorID, models.TransactionTypePoolWithdraw, amount, 0, currency,
+		fmt.Sprintf("Game %s pool withdrawal", gameID), &gameID)
+}
+
+// TopUpGamePool списывает с баланса создателя сумму автопополнения пула игры
+func (s *TransactionServiceImpl) TopUpGamePool(ctx context.Context, creatorID uint64, amount float64, currency string, gameID uuid.UUID) error {
+	if amount <= 0 {
+		return errors.New("pool top-up must be positive")
+	}
+
+	err := s.applyBalanceTransaction(ctx, creatorID, models.TransactionTypePoolTopUp, -amount, 0, currency,
+		fmt.Sprintf("Game %s pool top-up", gameID), &gameID)
+	if errors.Is(err, models.ErrInsufficientFunds) {
+		return fmt.Errorf("insufficient %s balance for pool top-up", currency)
+	}
+	return err
+}
+
+// PayJackpot зачисляет победителю выигрыш джекпота
+func (s *TransactionServiceImpl) PayJackpot(ctx context.Context, userID uint64, amount float64, currency string, gameID uuid.UUID) error {
+	if amount <= 0 {
+		return errors.New("jackpot payout must be positive")
+	}
+
+	if err := s.applyBalanceTransaction(ctx, userID, models.TransactionTypeJackpot, amount, 0, currency,
+		fmt.Sprintf("Jackpot won in game %s", gameID), &gameID); err != nil {
+		return err
+	}
+
+	metrics.RecordReward(currency, amount)
+	return nil
+}
+
+// PayReferral зачисляет рефереру долю комиссии, удержанной с приглашённого пользователя
+func (s *TransactionServiceImpl) PayReferral(ctx context.Context, referrerID uint64, amount float64, currency string, refereeID uint64) error {
+	if amount <= 0 {
+		return errors.New("referral payout must be positive")
+	}
+
+	return s.applyBalanceTransaction(ctx, referrerID, models.TransactionTypeReferral, amount, 0, currency,
+		fmt.Sprintf("Referral earnings from user %d", refereeID), nil)
+}
+
+// PayLeaderboardPrize зачисляет приз победителю сезона таблицы лидеров
+func (s *TransactionServiceImpl) PayLeaderboardPrize(ctx context.Context, userID uint64, amount float64, currency string, description string) error {
+	if amount <= 0 {
+		return errors.New("leaderboard prize must be positive")
+	}
+
+	return s.applyBalanceTransaction(ctx, userID, models.TransactionTypeLeaderboardPrize, amount, 0, currency, description, nil)
+}
+
+// applyBalanceTransaction изменяет баланс пользователя и записывает транзакцию (дуэли, операции с пулом игры)
+// в одной транзакции БД. delta < 0 - списание: выполняется, только если доступного баланса хватает,
+// иначе возвращается ErrInsufficientFunds. delta > 0 - зачисление
+func (s *TransactionServiceImpl) applyBalanceTransaction(ctx context.Context, userID uint64, txType string, delta, fee float64, currency, description string, gameID *uuid.UUID) error {
+	if userID == 0 {
+		return errors.New("user ID (TelegramID) cannot be zero")
+	}
+	if currency != models.CurrencyTON && currency != models.CurrencyUSDT {
+		return fmt.Errorf("unknown currency '%s'", currency)
+	}
+
+	return withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+		var err error
+		switch {
+		case delta < 0:
+			err = s.userRepo.DebitBalance(ctx, userID, currency, -delta)
+		case currency == models.CurrencyTON:
+			err = s.userRepo.UpdateTonBalance(ctx, userID, delta)
+		default:
+			err = s.userRepo.UpdateUsdtBalance(ctx, userID, delta)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update %s balance: %w", currency, err)
+		}
+
+		tx := &models.Transaction{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Type:        txType,
+			Amount:      math.Abs(delta),
+			Fee:         fee,
+			Currency:    currency,
+			Status:      models.TransactionStatusCompleted,
+			Description: description,
+			GameID:      gameID,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		if err := s.transactionRepo.Create(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to create %s transaction: %w", txType, err)
+		}
+		return nil, nil
+	})
+}
+
 // ConfirmDeposit подтверждает транзакцию пополнения и обновляет баланс пользователя.
 func (s *TransactionServiceImpl) ConfirmDeposit(ctx context.Context, transactionID uuid.UUID) error {
 	if transactionID == uuid.Nil {
@@ -458,37 +676,44 @@ func (s *TransactionServiceImpl) ConfirmDeposit(ctx context.Context, transaction
 		return fmt.Errorf("deposit transaction %s is not in pending status, got %s", transactionID, tx.Status)
 	}
 
-	// Зачисляем средства на баланс
-	var errUpdateBalance error
-	if tx.Currency == models.CurrencyTON {
-		errUpdateBalance = s.userRepo.UpdateTonBalance(ctx, tx.UserID, tx.Amount)
-	} else if tx.Currency == models.CurrencyUSDT {
-		errUpdateBalance = s.userRepo.UpdateUsdtBalance(ctx, tx.UserID, tx.Amount)
-	} else {
+	if tx.Currency != models.CurrencyTON && tx.Currency != models.CurrencyUSDT {
 		tx.Status = models.TransactionStatusFailed
 		tx.Description = fmt.Sprintf("Failed due to unknown currency: %s", tx.Currency)
 		_ = s.transactionRepo.Update(ctx, tx) // Попытка обновить статус, ошибку не обрабатываем критично здесь
 		return fmt.Errorf("unknown currency '%s' for deposit %s, balance not updated", tx.Currency, transactionID)
 	}
 
+	// Зачисляем средства, завершаем транзакцию и записываем событие в одной транзакции БД:
+	// баланс не может измениться без смены статуса транзакции
+	var errUpdateBalance error
+	err = withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+		if tx.Currency == models.CurrencyTON {
+			errUpdateBalance = s.userRepo.UpdateTonBalance(ctx, tx.UserID, tx.Amount)
+		} else {
+			errUpdateBalance = s.userRepo.UpdateUsdtBalance(ctx, tx.UserID, tx.Amount)
+		}
+		if errUpdateBalance != nil {
+			return nil, errUpdateBalance
+		}
+
+		tx.Status = models.TransactionStatusCompleted
+		tx.UpdatedAt = time.Now()
+		if err := s.transactionRepo.Update(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to update transaction status to completed: %w", err)
+		}
+		return transactionEvent(models.EventDepositCredited, tx)
+	})
+
 	if errUpdateBalance != nil {
 		tx.Status = models.TransactionStatusFailed
 		tx.Description = fmt.Sprintf("Failed to update user balance: %s", errUpdateBalance.Error())
 		_ = s.transactionRepo.Update(ctx, tx) // Попытка обновить статус
 		return fmt.Errorf("failed to update user balance for deposit %s: %w", transactionID, errUpdateBalance)
 	}
-
-	tx.Status = models.TransactionStatusCompleted
-	tx.UpdatedAt = time.Now()
-	if err := s.transactionRepo.Update(ctx, tx); err != nil {
-		// Баланс обновлен, но статус транзакции не удалось обновить. Это серьезная проблема.
-		// Требуется логика для обработки таких несоответствий, возможно, фоновый процесс.
-		return fmt.Errorf("user balance updated for deposit %s, but failed to update transaction status to completed: %w", transactionID, err)
+	if err != nil {
+		return fmt.Errorf("failed to confirm deposit %s: %w", transactionID, err)
 	}
 
-	// Записываем успешный депозит в метрики
-	metrics.RecordDeposit(tx.Currency, tx.Amount)
-
 	return nil
 }
 
@@ -543,38 +768,45 @@ func (s *TransactionServiceImpl) ConfirmWithdrawal(ctx context.Context, transact
 			tx.Amount)
 	}
 
-	// Списываем средства с баланса
-	var errUpdateBalance error
-	if tx.Currency == models.CurrencyTON {
-		errUpdateBalance = s.userRepo.UpdateTonBalance(ctx, tx.UserID, -tx.Amount) // Отрицательная сумма для списания
-	} else if tx.Currency == models.CurrencyUSDT {
-		errUpdateBalance = s.userRepo.UpdateUsdtBalance(ctx, tx.UserID, -tx.Amount) // Отрицательная сумма для списания
-	} else {
+	if tx.Currency != models.CurrencyTON && tx.Currency != models.CurrencyUSDT {
 		tx.Status = models.TransactionStatusFailed
 		tx.Description = fmt.Sprintf("Failed due to unknown currency: %s", tx.Currency)
 		_ = s.transactionRepo.Update(ctx, tx)
 		return fmt.Errorf("unknown currency '%s' for withdrawal %s, balance not updated", tx.Currency, transactionID)
 	}
 
+	// Списываем средства, завершаем транзакцию и записываем событие в одной транзакции БД
+	var errUpdateBalance error
+	err = withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+		// Отрицательная сумма для списания
+		if tx.Currency == models.CurrencyTON {
+			errUpdateBalance = s.userRepo.UpdateTonBalance(ctx, tx.UserID, -tx.Amount)
+		} else {
+			errUpdateBalance = s.userRepo.UpdateUsdtBalance(ctx, tx.UserID, -tx.Amount)
+		}
+		if errUpdateBalance != nil {
+			return nil, errUpdateBalance
+		}
+
+		tx.Status = models.TransactionStatusCompleted
+		tx.TxHash = txHash
+		tx.UpdatedAt = time.Now()
+		if err := s.transactionRepo.Update(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to update transaction status to completed: %w", err)
+		}
+		return transactionEvent(models.EventWithdrawalSent, tx)
+	})
+
 	if errUpdateBalance != nil {
 		tx.Status = models.TransactionStatusFailed
 		tx.Description = fmt.Sprintf("Failed to update user balance: %s", errUpdateBalance.Error())
 		_ = s.transactionRepo.Update(ctx, tx)
 		return fmt.Errorf("failed to update user balance for withdrawal %s: %w", transactionID, errUpdateBalance)
 	}
-
-	tx.Status = models.TransactionStatusCompleted
-	tx.TxHash = txHash
-	tx.UpdatedAt = time.Now()
-	if err := s.transactionRepo.Update(ctx, tx); err != nil {
-		// Баланс обновлен, но статус транзакции не удалось обновить. Это серьезная проблема.
-		return fmt.Errorf("user balance updated for withdrawal %s, but failed to update transaction status to completed: %w", transactionID, err)
+	if err != nil {
+		return fmt.Errorf("failed to confirm withdrawal %s: %w", transactionID, err)
 	}
 
-	// Записываем успешный вывод в метрики
-	metrics.RecordWithdrawal(tx.Currency, tx.Amount)
-	metrics.DecrementPendingWithdrawals()
-
 	return nil
 }
 
@@ -744,35 +976,27 @@ func (s *TransactionServiceImpl) ProcessBlockchainDeposit(ctx context.Context, u
 		UpdatedAt:   time.Now(),
 	}
 
-	// Сохраняем транзакцию
-	if err := s.transactionRepo.Create(ctx, tx); err != nil {
-		return fmt.Errorf("failed to create deposit transaction: %w", err)
-	}
-
-	// Обновляем баланс пользователя
-	var balanceErr error
-	if currency == models.CurrencyTON {
-		balanceErr = s.userRepo.UpdateTonBalance(ctx, userID, amount)
-	} else if currency == models.CurrencyUSDT {
-		balanceErr = s.userRepo.UpdateUsdtBalance(ctx, userID, amount)
-	} else {
-		balanceErr = fmt.Errorf("unsupported currency: %s", currency)
-	}
-
-	if balanceErr != nil {
-		// Если не удалось обновить баланс, отмечаем транзакцию как неуспешную
-		errorMsg := fmt.Sprintf("Failed to update user balance: %v", balanceErr)
-		failErr := s.FailTransaction(ctx, tx.ID, errorMsg)
-		if failErr != nil {
-			fmt.Printf("ERROR: Failed to mark transaction %s as failed: %v\n", tx.ID, failErr)
+	// Сохраняем транзакцию, обновляем баланс пользователя и записываем событие в одной транзакции БД.
+	// Если баланс не обновился, транзакция депозита не сохраняется и депозит можно обработать повторно
+	return withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+		if err := s.transactionRepo.Create(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to create deposit transaction: %w", err)
 		}
-		return fmt.Errorf("failed to update %s balance: %w", currency, balanceErr)
-	}
 
-	// Записываем успешный депозит в метрики
-	metrics.RecordDeposit(currency, amount)
+		var balanceErr error
+		if currency == models.CurrencyTON {
+			balanceErr = s.userRepo.UpdateTonBalance(ctx, userID, amount)
+		} else if currency == models.CurrencyUSDT {
+			balanceErr = s.userRepo.UpdateUsdtBalance(ctx, userID, amount)
+		} else {
+			balanceErr = fmt.Errorf("unsupported currency: %s", currency)
+		}
+		if balanceErr != nil {
+			return nil, fmt.Errorf("failed to update %s balance: %w", currency, balanceErr)
+		}
 
-	return nil
+		return transactionEvent(models.EventDepositCredited, tx)
+	})
 }
 
 // GenerateDepositAddress генерирует адрес кошелька для депозита
@@ -841,8 +1065,15 @@ func (s *TransactionServiceImpl) MonitorPendingWithdrawals(ctx context.Context)
 				// Транзакция подтверждена
 				tx.Status = models.TransactionStatusCompleted
 				tx.UpdatedAt = time.Now()
-				if err := s.transactionRepo.Update(ctx, tx); err != nil {
+				err := withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+					if err := s.transactionRepo.Update(ctx, tx); err != nil {
+						return nil, err
+					}
+					return transactionEvent(models.EventWithdrawalSent, tx)
+				})
+				if err != nil {
 					fmt.Printf("ERROR: Failed to update transaction %s status: %v\n", tx.ID, err)
+					continue
 				}
 			case blockchain.TxStatusFailed:
 				// Транзакция провалилась - возвращаем средства
@@ -22,6 +22,8 @@ type UserServiceImpl struct {
 	withdrawFeeTON     float64
 	withdrawFeeUSDT    float64
 	withdrawLockMinutes int
+	achievements       models.AchievementService
+	events             models.EventService
 	logger             *zap.Logger
 }
 
@@ -39,12 +41,14 @@ func NewUserService(
 	repo models.UserRepository,
 	transactionRepo models.TransactionRepository,
 	tonService models.TONService,
+	events models.EventService,
 	config UserServiceConfig,
 ) models.UserService {
 	return &UserServiceImpl{
 		repo:                repo,
 		transactionRepo:     transactionRepo,
 		tonService:          tonService,
+		events:              events,
 		minWithdrawTON:      config.MinWithdrawTON,
 		minWithdrawUSDT:     config.MinWithdrawUSDT,
 		withdrawFeeTON:      config.WithdrawFeeTON,
@@ -54,6 +58,11 @@ func NewUserService(
 	}
 }
 
+// SetAchievements устанавливает сервис достижений (для отложенной инициализации)
+func (s *UserServiceImpl) SetAchievements(achievements models.AchievementService) {
+	s.achievements = achievements
+}
+
 // CreateUser создает нового пользователя
 func (s *UserServiceImpl) CreateUser(ctx context.Context, user *models.User) error {
 	if user == nil {
@@ -191,6 +200,14 @@ func (s *UserServiceImpl) UpdateUsdtBalance(ctx context.Context, telegramID uint
 	return s.repo.UpdateUsdtBalance(ctx, telegramID, amount)
 }
 
+// DebitBalance атомарно списывает сумму с доступного баланса пользователя
+func (s *UserServiceImpl) DebitBalance(ctx context.Context, telegramID uint64, currency string, amount float64) error {
+	if telegramID == 0 {
+		return errors.New("telegram ID must be valid")
+	}
+	return s.repo.DebitBalance(ctx, telegramID, currency, amount)
+}
+
 // GetTopUsers получает список топ-пользователей
 func (s *UserServiceImpl) GetTopUsers(ctx context.Context, limit int) ([]*models.User, error) {
 	if limit <= 0 {
@@ -231,6 +248,16 @@ func (s *UserServiceImpl) GetUserStats(ctx context.Context, telegramID uint64) (
 		stats["win_rate"] = 0.0
 	}
 
+	// Серии и достижения (ошибка не мешает вернуть основную статистику)
+	if s.achievements != nil {
+		if summary, err := s.achievements.GetAchievements(ctx, telegramID); err != nil {
+			s.logger.Error("Failed to get achievements", zap.Uint64("telegram_id", telegramID), zap.Error(err))
+		} else {
+			stats["streak"] = summary.Streak
+			stats["achievements"] = summary.Earned
+		}
+	}
+
 	return stats, nil
 }
 
@@ -335,6 +362,12 @@ func (s *UserServiceImpl) RequestWithdraw(ctx context.Context, telegramID uint64
 		return nil, errors.New("invalid wallet address")
 	}
 
+	// Бонусные средства выводятся только после отыгрыша и перевода в основной баланс
+	if amount > user.GetAvailableBalance(currency) && user.GetBonusBalance(currency) > 0 {
+		return nil, fmt.Errorf("bonus funds cannot be withdrawn until the wagering requirement is met (%.4f %s left to wager)",
+			user.GetWagerRemaining(currency), currency)
+	}
+
 	// Проверяем баланс (с учётом комиссии)
 	totalRequired := amount
 	hasBalance, err := s.ValidateBalance(ctx, telegramID, totalRequired, currency)
@@ -350,41 +383,11 @@ func (s *UserServiceImpl) RequestWithdraw(ctx context.Context, telegramID uint64
 		return nil, errors.New("there is already a pending withdrawal")
 	}
 
-	// Начинаем транзакцию (упрощённо без реальной БД транзакции)
 	log.Info("Processing withdrawal request",
 		zap.Float64("amount", amount),
 		zap.Float64("fee", fee),
 		zap.String("to_address", toAddress))
 
-	// Списываем с баланса
-	if currency == models.CurrencyTON {
-		if err := s.repo.UpdateTonBalance(ctx, telegramID, -amount); err != nil {
-			return nil, fmt.Errorf("failed to deduct balance: %w", err)
-		}
-	} else {
-		if err := s.repo.UpdateUsdtBalance(ctx, telegramID, -amount); err != nil {
-			return nil, fmt.Errorf("failed to deduct balance: %w", err)
-		}
-	}
-
-	// Устанавливаем pending withdrawal и lock
-	if err := s.repo.UpdatePendingWithdrawal(ctx, telegramID, amount); err != nil {
-		// Откатываем
-		if currency == models.CurrencyTON {
-			_ = s.repo.UpdateTonBalance(ctx, telegramID, amount)
-		} else {
-			_ = s.repo.UpdateUsdtBalance(ctx, telegramID, amount)
-		}
-		return nil, fmt.Errorf("failed to set pending withdrawal: %w", err)
-	}
-
-	// Устанавливаем блокировку на вывод
-	lockUntil := time.Now().Add(time.Duration(s.withdrawLockMinutes) * time.Minute)
-	if err := s.repo.SetWithdrawalLock(ctx, telegramID, lockUntil); err != nil {
-		log.Warn("Failed to set withdrawal lock", zap.Error(err))
-	}
-
-	// Создаём транзакцию
 	tx := &models.Transaction{
 		ID:          uuid.New(),
 		UserID:      telegramID,
@@ -399,15 +402,36 @@ func (s *UserServiceImpl) RequestWithdraw(ctx context.Context, telegramID uint64
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := s.transactionRepo.Create(ctx, tx); err != nil {
-		// Откатываем
-		_ = s.repo.UpdatePendingWithdrawal(ctx, telegramID, -amount)
+	// Списание, pending withdrawal, транзакция и событие заявки сохраняются вместе
+	err = withinEventTx(ctx, s.events, func(ctx context.Context) (*models.DomainEvent, error) {
+		var balanceErr error
 		if currency == models.CurrencyTON {
-			_ = s.repo.UpdateTonBalance(ctx, telegramID, amount)
+			balanceErr = s.repo.UpdateTonBalance(ctx, telegramID, -amount)
 		} else {
-			_ = s.repo.UpdateUsdtBalance(ctx, telegramID, amount)
+			balanceErr = s.repo.UpdateUsdtBalance(ctx, telegramID, -amount)
+		}
+		if balanceErr != nil {
+			return nil, fmt.Errorf("failed to deduct balance: %w", balanceErr)
+		}
+
+		if err := s.repo.UpdatePendingWithdrawal(ctx, telegramID, amount); err != nil {
+			return nil, fmt.Errorf("failed to set pending withdrawal: %w", err)
 		}
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+
+		if err := s.transactionRepo.Create(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		return transactionEvent(models.EventWithdrawalRequested, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Устанавливаем блокировку на вывод
+	lockUntil := time.Now().Add(time.Duration(s.withdrawLockMinutes) * time.Minute)
+	if err := s.repo.SetWithdrawalLock(ctx, telegramID, lockUntil); err != nil {
+		log.Warn("Failed to set withdrawal lock", zap.Error(err))
 	}
 
 	log.Info("Withdrawal request created",
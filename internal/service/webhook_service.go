@@ -0,0 +1,531 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/logger"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Параметры доставки webhook по умолчанию
+const (
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookRetryDelay  = 30 * time.Second
+	defaultWebhookBatchSize   = 100
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxPerOwner = 10
+	maxWebhookRetryDelay      = time.Hour
+	minWebhookSecretLength    = 16
+	maxWebhookResponseBody    = 64 << 10
+)
+
+// Заголовки запроса на webhook. Подпись - HMAC-SHA256 ключом webhook от строки "<timestamp>.<тело запроса>"
+const (
+	webhookEventHeader     = "X-Wordle-Event"
+	webhookDeliveryHeader  = "X-Wordle-Delivery"
+	webhookTimestampHeader = "X-Wordle-Timestamp"
+	webhookSignatureHeader = "X-Wordle-Signature"
+)
+
+// sharedAddressSpace диапазон 100.64.0.0/10 (RFC 6598): внутренняя сеть провайдера и облачных сервисов
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// webhookResolver разрешает имя хоста webhook в IP-адреса
+type webhookResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// WebhookServiceImpl представляет собой реализацию WebhookService
+type WebhookServiceImpl struct {
+	webhookRepo models.WebhookRepository
+	httpClient  *http.Client
+	resolver    webhookResolver
+	// addrAllowed проверяет, можно ли отправлять webhook на адрес. Тесты разрешают loopback для httptest
+	addrAllowed func(ip net.IP) bool
+	rule        models.WebhookRule
+	logger      *zap.Logger
+}
+
+// NewWebhookService создает новый экземпляр WebhookService.
+// Нулевые параметры rule заменяются значениями по умолчанию
+func NewWebhookService(webhookRepo models.WebhookRepository, rule models.WebhookRule) models.WebhookService {
+	if rule.MaxAttempts <= 0 {
+		rule.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if rule.RetryDelay <= 0 {
+		rule.RetryDelay = defaultWebhookRetryDelay
+	}
+	if rule.BatchSize <= 0 {
+		rule.BatchSize = defaultWebhookBatchSize
+	}
+	if rule.Timeout <= 0 {
+		rule.Timeout = defaultWebhookTimeout
+	}
+	if rule.MaxPerOwner <= 0 {
+		rule.MaxPerOwner = defaultWebhookMaxPerOwner
+	}
+	s := &WebhookServiceImpl{
+		webhookRepo: webhookRepo,
+		resolver:    net.DefaultResolver,
+		addrAllowed: isPublicWebhookAddr,
+		rule:        rule,
+		logger:      logger.GetLogger(zap.String("service", "webhook")),
+	}
+
+	// Адрес проверяется ещё раз при подключении: имя, прошедшее проверку при создании webhook,
+	// может позже разрешиться во внутренний адрес (DNS rebinding). Прокси не используется, чтобы проверялся адрес получателя
+	dialer := &net.Dialer{Timeout: rule.Timeout, Control: s.controlWebhookDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	s.httpClient = &http.Client{
+		Timeout:   rule.Timeout,
+		Transport: transport,
+		// Перенаправление считается ошибкой доставки: получатель должен указать конечный адрес
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return s
+}
+
+// CreateWebhook создаёт webhook пользователя. Ключ подписи возвращается только здесь
+func (s *WebhookServiceImpl) CreateWebhook(ctx context.Context, ownerID uint64, webhookURL, secret string, eventTypes []string) (*models.Webhook, error) {
+	if err := s.validateWebhookURL(ctx, webhookURL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	} else if len(secret) < minWebhookSecretLength {
+		return nil, fmt.Errorf("%w: at least %d characters required", models.ErrInvalidWebhookSecret, minWebhookSecretLength)
+	}
+
+	existing, err := s.webhookRepo.GetByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= s.rule.MaxPerOwner {
+		return nil, fmt.Errorf("%w: at most %d webhooks per user", models.ErrWebhookLimitReached, s.rule.MaxPerOwner)
+	}
+
+	webhook := &models.Webhook{
+		OwnerID:    ownerID,
+		URL:        webhookURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Webhook created",
+		zap.String("webhook_id", webhook.ID.String()),
+		zap.Uint64("owner_id", ownerID),
+		zap.Strings("event_types", eventTypes))
+	return webhook, nil
+}
+
+// GetWebhooks возвращает webhook пользователя без ключей подписи
+func (s *WebhookServiceImpl) GetWebhooks(ctx context.Context, ownerID uint64) ([]*models.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook меняет адрес, типы событий или активность webhook
+func (s *WebhookServiceImpl) UpdateWebhook(ctx context.Context, ownerID uint64, id uuid.UUID, update *models.WebhookUpdate) (*models.Webhook, error) {
+	webhook, err := s.ownedWebhook(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.URL != nil {
+		if err := s.validateWebhookURL(ctx, *update.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *update.URL
+	}
+	if update.EventTypes != nil {
+		if webhook.EventTypes, err = normalizeWebhookEventTypes(update.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if update.Active != nil {
+		webhook.Active = *update.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// DeleteWebhook удаляет webhook вместе с журналом доставок
+func (s *WebhookServiceImpl) DeleteWebhook(ctx context.Context, ownerID uint64, id uuid.UUID) error {
+	if _, err := s.ownedWebhook(ctx, ownerID, id); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+// GetDeliveries возвращает журнал доставок webhook, начиная с последних
+func (s *WebhookServiceImpl) GetDeliveries(ctx context.Context, ownerID uint64, id uuid.UUID, limit, offset int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.ownedWebhook(ctx, ownerID, id); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetDeliveries(ctx, id, limit, offset)
+}
+
+// ReplayDelivery ставит в очередь новую доставку события из журнала. Идентификатор события сохраняется,
+// поэтому получатель может отличить повтор от нового события. Исходная запись журнала не меняется
+func (s *WebhookServiceImpl) ReplayDelivery(ctx context.Context, ownerID uint64, id, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.ownedWebhook(ctx, ownerID, id); err != nil {
+		return nil, err
+	}
+	original, err := s.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.WebhookID != id {
+		return nil, models.ErrWebhookDeliveryNotFound
+	}
+
+	replay := &models.WebhookDelivery{
+		WebhookID: id,
+		EventID:   original.EventID,
+		EventType: original.EventType,
+		Payload:   original.Payload,
+	}
+	if _, err := s.webhookRepo.CreateDelivery(ctx, replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+// SendTestEvent сразу отправляет тестовое событие, в том числе на отключённый webhook.
+// Тестовое событие не повторяется: при ошибке его можно отправить повторно из журнала
+func (s *WebhookServiceImpl) SendTestEvent(ctx context.Context, ownerID uint64, id uuid.UUID) (*models.WebhookDelivery, error) {
+	webhook, err := s.ownedWebhook(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"webhook_id": webhook.ID,
+		"message":    "This is a test event",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal test event: %w", err)
+	}
+	delivery := &models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   uuid.New(),
+		EventType: models.WebhookEventTest,
+		Payload:   payload,
+	}
+	if _, err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	code, sendErr := s.send(ctx, webhook, delivery)
+	s.recordAttempt(delivery, code, sendErr, time.Now())
+	if delivery.Status == models.WebhookDeliveryPending {
+		delivery.Status = models.WebhookDeliveryFailed
+	}
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to update webhook delivery %s: %w", delivery.ID, err)
+	}
+	return delivery, nil
+}
+
+// HandleEvent ставит доменное событие в очередь доставки на webhook участников события.
+// Повторная доставка события шиной не создаёт дублей
+func (s *WebhookServiceImpl) HandleEvent(ctx context.Context, event *models.DomainEvent) error {
+	ownerIDs, err := webhookEventOwners(event)
+	if err != nil || len(ownerIDs) == 0 {
+		return err
+	}
+
+	webhooks, err := s.webhookRepo.GetSubscribed(ctx, ownerIDs, event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to get subscribed webhooks: %w", err)
+	}
+	for _, webhook := range webhooks {
+		_, err := s.webhookRepo.CreateDelivery(ctx, &models.WebhookDelivery{
+			WebhookID: webhook.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			DedupKey:  event.ID.String(),
+			Payload:   event.Payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessDeliveries отправляет доставки, время попытки которых наступило. При ошибке доставка откладывается
+// с удвоением задержки, после rule.MaxAttempts попыток помечается failed
+func (s *WebhookServiceImpl) ProcessDeliveries(ctx context.Context) error {
+	deliveries, err := s.webhookRepo.GetDueDeliveries(ctx, time.Now(), s.rule.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	webhooks := make(map[uuid.UUID]*models.Webhook)
+	for _, delivery := range deliveries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			if webhook, err = s.webhookRepo.GetByID(ctx, delivery.WebhookID); err != nil {
+				if errors.Is(err, models.ErrWebhookNotFound) {
+					continue
+				}
+				return fmt.Errorf("failed to get webhook %s: %w", delivery.WebhookID, err)
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		code, sendErr := s.send(ctx, webhook, delivery)
+		s.recordAttempt(delivery, code, sendErr, time.Now())
+		if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to update webhook delivery %s: %w", delivery.ID, err)
+		}
+
+		if sendErr != nil {
+			s.logger.Warn("Failed to deliver webhook",
+				zap.String("delivery_id", delivery.ID.String()),
+				zap.String("webhook_id", webhook.ID.String()),
+				zap.String("event_type", delivery.EventType),
+				zap.Int("response_code", code),
+				zap.Int("attempts", delivery.Attempts),
+				zap.String("status", delivery.Status),
+				zap.Error(sendErr))
+		}
+	}
+
+	return nil
+}
+
+// send отправляет подписанное событие на адрес webhook и возвращает HTTP-код ответа (0 - ответа не было)
+func (s *WebhookServiceImpl) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(&models.WebhookEnvelope{
+		ID:        delivery.EventID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, delivery.EventType)
+	req.Header.Set(webhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(webhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// recordAttempt обновляет доставку по результату попытки отправки
+func (s *WebhookServiceImpl) recordAttempt(delivery *models.WebhookDelivery, code int, sendErr error, now time.Time) {
+	delivery.Attempts++
+	delivery.ResponseCode = code
+	if sendErr == nil {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = sendErr.Error()
+	if delivery.Attempts >= s.rule.MaxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		return
+	}
+
+	delay := s.rule.RetryDelay << (delivery.Attempts - 1)
+	if delay <= 0 || delay > maxWebhookRetryDelay {
+		delay = maxWebhookRetryDelay
+	}
+	delivery.NextAttemptAt = now.Add(delay)
+}
+
+// ownedWebhook получает webhook пользователя. Чужой webhook не отличается от несуществующего
+func (s *WebhookServiceImpl) ownedWebhook(ctx context.Context, ownerID uint64, id uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.OwnerID != ownerID {
+		return nil, models.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// webhookEventOwners возвращает пользователей, чьи webhook получают событие: создателя игры и игрока
+// для лобби, создателя для активации игры, владельца транзакции для депозитов и выводов
+func webhookEventOwners(event *models.DomainEvent) ([]uint64, error) {
+	var ownerIDs []uint64
+	switch event.Type {
+	case models.EventLobbyFinished:
+		var payload models.LobbyFinishedEvent
+		if err := event.Decode(&payload); err != nil {
+			return nil, err
+		}
+		ownerIDs = []uint64{payload.CreatorID, payload.UserID}
+	case models.EventGameActivated:
+		var payload models.GameActivatedEvent
+		if err := event.Decode(&payload); err != nil {
+			return nil, err
+		}
+		ownerIDs = []uint64{payload.CreatorID}
+	case models.EventDepositCredited, models.EventWithdrawalRequested, models.EventWithdrawalSent:
+		var payload models.TransactionEvent
+		if err := event.Decode(&payload); err != nil {
+			return nil, err
+		}
+		ownerIDs = []uint64{payload.UserID}
+	}
+
+	owners := ownerIDs[:0]
+	for _, ownerID := range ownerIDs {
+		if ownerID != 0 && !slices.Contains(owners, ownerID) {
+			owners = append(owners, ownerID)
+		}
+	}
+	return owners, nil
+}
+
+// validateWebhookURL проверяет, что адрес webhook - абсолютный http или https URL,
+// все адреса хоста которого публичные: loopback, частные сети и link-local (в том числе метаданные облака) запрещены
+func (s *WebhookServiceImpl) validateWebhookURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return models.ErrInvalidWebhookURL
+	}
+
+	host := parsed.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !s.addrAllowed(ip) {
+			return fmt.Errorf("%w: %s is not a public address", models.ErrInvalidWebhookURL, host)
+		}
+		return nil
+	}
+
+	addrs, err := s.resolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: failed to resolve %s", models.ErrInvalidWebhookURL, host)
+	}
+	for _, addr := range addrs {
+		if !s.addrAllowed(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to a non-public address", models.ErrInvalidWebhookURL, host)
+		}
+	}
+	return nil
+}
+
+// controlWebhookDial запрещает подключение к непубличному адресу в момент соединения
+func (s *WebhookServiceImpl) controlWebhookDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook address %s: %w", address, err)
+	}
+	if ip := net.ParseIP(host); ip == nil || !s.addrAllowed(ip) {
+		return fmt.Errorf("webhook address %s is not a public address", host)
+	}
+	return nil
+}
+
+// isPublicWebhookAddr проверяет, что адрес не относится к loopback, частным, link-local и служебным сетям
+func isPublicWebhookAddr(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() && !sharedAddressSpace.Contains(ip)
+}
+
+// normalizeWebhookEventTypes проверяет типы событий подписки и убирает повторы
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, models.ErrWebhookEventTypesMissing
+	}
+	normalized := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(models.WebhookEventTypes, eventType) {
+			return nil, fmt.Errorf("%w: %s", models.ErrInvalidWebhookEventType, eventType)
+		}
+		if !slices.Contains(normalized, eventType) {
+			normalized = append(normalized, eventType)
+		}
+	}
+	return normalized, nil
+}
+
+// generateWebhookSecret генерирует случайный ключ подписи
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// signWebhookPayload подписывает тело запроса: HMAC-SHA256 от "<timestamp>.<body>" в hex
+func signWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
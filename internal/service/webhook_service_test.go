@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/mocks"
+	"github.com/TakuroBreath/wordle/internal/models"
+	"github.com/google/uuid"
+)
+
+// webhookReceiver тестовый получатель webhook, отвечающий заданными кодами по очереди
+type webhookReceiver struct {
+	mu       sync.Mutex
+	server   *httptest.Server
+	codes    []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookReceiver(t *testing.T, codes ...int) *webhookReceiver {
+	t.Helper()
+	receiver := &webhookReceiver{codes: codes}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, r)
+		receiver.bodies = append(receiver.bodies, body)
+		code := http.StatusOK
+		if len(receiver.codes) > 0 {
+			code = receiver.codes[0]
+			if len(receiver.codes) > 1 {
+				receiver.codes = receiver.codes[1:]
+			}
+		}
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+// staticResolver разрешает имена хостов по таблице без обращения к DNS
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+// newTestWebhookService создаёт сервис webhook, который разрешает example.com в публичный адрес
+// и, если allowLoopback, отправляет webhook на httptest-сервер
+func newTestWebhookService(repo models.WebhookRepository, rule models.WebhookRule, allowLoopback bool) *WebhookServiceImpl {
+	webhooks := NewWebhookService(repo, rule).(*WebhookServiceImpl)
+	webhooks.resolver = staticResolver{
+		"example.com":   {"93.184.215.14"},
+		"localhost":     {"127.0.0.1", "::1"},
+		"internal.test": {"93.184.215.14", "10.0.0.5"},
+		"metadata.test": {"169.254.169.254"},
+	}
+	if allowLoopback {
+		webhooks.addrAllowed = func(ip net.IP) bool {
+			return ip.IsLoopback() || isPublicWebhookAddr(ip)
+		}
+	}
+	return webhooks
+}
+
+func (r *webhookReceiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func TestWebhookService_ManageWebhooks(t *testing.T) {
+	ctx := context.Background()
+	webhooks := newTestWebhookService(mocks.NewMockWebhookRepository(), models.WebhookRule{MaxPerOwner: 2}, false)
+
+	invalid := []struct {
+		name       string
+		url        string
+		secret     string
+		eventTypes []string
+		want       error
+	}{
+		{"relative url", "/hooks", "", []string{models.EventGameActivated}, models.ErrInvalidWebhookURL},
+		{"unsupported scheme", "ftp://example.com/hooks", "", []string{models.EventGameActivated}, models.ErrInvalidWebhookURL},
+		{"no event types", "https://example.com/hooks", "", nil, models.ErrWebhookEventTypesMissing},
+		{"unknown event type", "https://example.com/hooks", "", []string{"game_deleted"}, models.ErrInvalidWebhookEventType},
+		{"short secret", "https://example.com/hooks", "secret", []string{models.EventGameActivated}, models.ErrInvalidWebhookSecret},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := webhooks.CreateWebhook(ctx, 1, tt.url, tt.secret, tt.eventTypes); !errors.Is(err, tt.want) {
+				t.Errorf("CreateWebhook() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	created, err := webhooks.CreateWebhook(ctx, 1, "https://example.com/hooks", "",
+		[]string{models.EventLobbyFinished, models.EventLobbyFinished, models.EventWithdrawalSent})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	if len(created.Secret) != 64 || !created.Active || len(created.EventTypes) != 2 {
+		t.Errorf("created webhook = %+v, want a generated secret, active and deduplicated event types", created)
+	}
+	if _, err := webhooks.CreateWebhook(ctx, 1, "https://example.com/other", "0123456789abcdef", []string{models.EventGameActivated}); err != nil {
+		t.Fatalf("CreateWebhook() second webhook error = %v", err)
+	}
+	if _, err := webhooks.CreateWebhook(ctx, 1, "https://example.com/third", "", []string{models.EventGameActivated}); !errors.Is(err, models.ErrWebhookLimitReached) {
+		t.Errorf("CreateWebhook() over the limit error = %v, want ErrWebhookLimitReached", err)
+	}
+
+	list, _ := webhooks.GetWebhooks(ctx, 1)
+	if len(list) != 2 || list[0].Secret != "" || list[1].Secret != "" {
+		t.Errorf("GetWebhooks() = %d webhooks, want 2 without secrets", len(list))
+	}
+
+	// Чужой webhook не отличается от несуществующего
+	if _, err := webhooks.SendTestEvent(ctx, 2, created.ID); !errors.Is(err, models.ErrWebhookNotFound) {
+		t.Errorf("SendTestEvent() by another user error = %v, want ErrWebhookNotFound", err)
+	}
+	if err := webhooks.DeleteWebhook(ctx, 2, created.ID); !errors.Is(err, models.ErrWebhookNotFound) {
+		t.Errorf("DeleteWebhook() by another user error = %v, want ErrWebhookNotFound", err)
+	}
+
+	inactive := false
+	updated, err := webhooks.UpdateWebhook(ctx, 1, created.ID, &models.WebhookUpdate{
+		EventTypes: []string{models.EventGameActivated},
+		Active:     &inactive,
+	})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+	if updated.Active || updated.URL != created.URL || len(updated.EventTypes) != 1 || updated.Secret != "" {
+		t.Errorf("updated webhook = %+v, want inactive with only game_activated and the same url", updated)
+	}
+
+	if err := webhooks.DeleteWebhook(ctx, 1, created.ID); err != nil {
+		t.Fatalf("DeleteWebhook() error = %v", err)
+	}
+	if list, _ := webhooks.GetWebhooks(ctx, 1); len(list) != 1 {
+		t.Errorf("GetWebhooks() after delete = %d webhooks, want 1", len(list))
+	}
+}
+
+func TestWebhookService_DeliversSignedEventsToParticipants(t *testing.T) {
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t)
+	webhookRepo := mocks.NewMockWebhookRepository()
+	webhooks := newTestWebhookService(webhookRepo, models.WebhookRule{}, true)
+	events := NewEventService(mocks.NewMockEventRepository(), mocks.NewMockTransactor(), models.EventRule{})
+	events.Subscribe(subscriberWebhooks, webhooks.HandleEvent, models.WebhookEventTypes...)
+
+	creatorHook, _ := webhooks.CreateWebhook(ctx, 100, receiver.server.URL+"/creator", "creator-secret-0123", []string{models.EventLobbyFinished})
+	playerHook, _ := webhooks.CreateWebhook(ctx, 1, receiver.server.URL+"/player", "", []string{models.EventGameActivated})
+	outsiderHook, _ := webhooks.CreateWebhook(ctx, 2, receiver.server.URL+"/outsider", "", []string{models.EventLobbyFinished})
+
+	event, _ := models.NewDomainEvent(models.EventLobbyFinished, uuid.New(), &models.LobbyFinishedEvent{
+		LobbyID: uuid.New(), GameID: uuid.New(), UserID: 1, CreatorID: 100,
+		Status: models.LobbyStatusSuccess, TriesUsed: 2, BetAmount: 1, Reward: 1.9, Currency: models.CurrencyTON,
+	})
+	if err := events.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := events.ProcessEvents(ctx); err != nil {
+		t.Fatalf("ProcessEvents() error = %v", err)
+	}
+	// Повторная доставка события шиной не создаёт вторую отправку
+	if err := webhooks.HandleEvent(ctx, event); err != nil {
+		t.Fatalf("HandleEvent() error = %v", err)
+	}
+
+	if err := webhooks.ProcessDeliveries(ctx); err != nil {
+		t.Fatalf("ProcessDeliveries() error = %v", err)
+	}
+	if receiver.received() != 1 {
+		t.Fatalf("receiver got %d requests, want only the creator webhook", receiver.received())
+	}
+
+	req, body := receiver.requests[0], receiver.bodies[0]
+	if req.URL.Path != "/creator" || req.Header.Get(webhookEventHeader) != models.EventLobbyFinished {
+		t.Errorf("request to %s with event %q, want the creator webhook with lobby_finished", req.URL.Path, req.Header.Get(webhookEventHeader))
+	}
+	timestamp, err := strconv.ParseInt(req.Header.Get(webhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp header = %q, want unix seconds", req.Header.Get(webhookTimestampHeader))
+	}
+	if got, want := req.Header.Get(webhookSignatureHeader), "sha256="+signWebhookPayload("creator-secret-0123", timestamp, body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+
+	var envelope models.WebhookEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("failed to decode webhook body: %v", err)
+	}
+	var data models.LobbyFinishedEvent
+	_ = json.Unmarshal(envelope.Data, &data)
+	if envelope.ID != event.ID || envelope.Type != models.EventLobbyFinished || data.Reward != 1.9 || data.CreatorID != 100 {
+		t.Errorf("envelope = %+v, data = %+v, want the lobby finished event", envelope, data)
+	}
+
+	deliveries, _ := webhooks.GetDeliveries(ctx, 100, creatorHook.ID, 10, 0)
+	if len(deliveries) != 1 || deliveries[0].Status != models.WebhookDeliveryDelivered || deliveries[0].ResponseCode != http.StatusOK ||
+		req.Header.Get(webhookDeliveryHeader) != deliveries[0].ID.String() {
+		t.Errorf("creator deliveries = %+v, want one delivered with code 200", deliveries)
+	}
+	for _, hook := range []*models.Webhook{playerHook, outsiderHook} {
+		if deliveries, _ := webhookRepo.GetDeliveries(ctx, hook.ID, 10, 0); len(deliveries) != 0 {
+			t.Errorf("webhook %s got %d deliveries, want none", hook.URL, len(deliveries))
+		}
+	}
+}
+
+func TestWebhookService_RetriesAndReplay(t *testing.T) {
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+	webhooks := newTestWebhookService(mocks.NewMockWebhookRepository(), models.WebhookRule{MaxAttempts: 2, RetryDelay: time.Millisecond}, true)
+
+	hook, _ := webhooks.CreateWebhook(ctx, 1, receiver.server.URL, "", []string{models.EventDepositCredited})
+	event, _ := models.NewDomainEvent(models.EventDepositCredited, uuid.New(), models.TransactionEvent{
+		TransactionID: uuid.New(), UserID: 1, Amount: 5, Currency: models.CurrencyTON,
+	})
+	if err := webhooks.HandleEvent(ctx, event); err != nil {
+		t.Fatalf("HandleEvent() error = %v", err)
+	}
+
+	_ = webhooks.ProcessDeliveries(ctx)
+	deliveries, _ := webhooks.GetDeliveries(ctx, 1, hook.ID, 10, 0)
+	if deliveries[0].Status != models.WebhookDeliveryPending || deliveries[0].ResponseCode != http.StatusInternalServerError ||
+		!deliveries[0].NextAttemptAt.After(deliveries[0].CreatedAt) {
+		t.Fatalf("after the first attempt: %+v, want pending retry with code 500", deliveries[0])
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	_ = webhooks.ProcessDeliveries(ctx)
+	deliveries, _ = webhooks.GetDeliveries(ctx, 1, hook.ID, 10, 0)
+	original := deliveries[0]
+	if original.Status != models.WebhookDeliveryFailed || original.Attempts != 2 || original.ResponseCode != http.StatusBadGateway {
+		t.Fatalf("after the last attempt: %+v, want failed with code 502", original)
+	}
+
+	if _, err := webhooks.ReplayDelivery(ctx, 1, hook.ID, uuid.New()); !errors.Is(err, models.ErrWebhookDeliveryNotFound) {
+		t.Errorf("ReplayDelivery() unknown delivery error = %v, want ErrWebhookDeliveryNotFound", err)
+	}
+	replay, err := webhooks.ReplayDelivery(ctx, 1, hook.ID, original.ID)
+	if err != nil {
+		t.Fatalf("ReplayDelivery() error = %v", err)
+	}
+	_ = webhooks.ProcessDeliveries(ctx)
+
+	deliveries, _ = webhooks.GetDeliveries(ctx, 1, hook.ID, 10, 0)
+	if len(deliveries) != 2 || deliveries[0].ID != replay.ID || deliveries[0].EventID != event.ID ||
+		deliveries[0].Status != models.WebhookDeliveryDelivered || deliveries[1].Status != models.WebhookDeliveryFailed {
+		t.Errorf("deliveries = %+v, want the delivered replay of the same event above the failed original", deliveries)
+	}
+	if receiver.received() != 3 {
+		t.Errorf("receiver got %d requests, want 3", receiver.received())
+	}
+}
+
+func TestWebhookService_SendTestEvent(t *testing.T) {
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t)
+	webhooks := newTestWebhookService(mocks.NewMockWebhookRepository(), models.WebhookRule{}, true)
+
+	hook, _ := webhooks.CreateWebhook(ctx, 1, receiver.server.URL, "", []string{models.EventGameActivated})
+	delivery, err := webhooks.SendTestEvent(ctx, 1, hook.ID)
+	if err != nil {
+		t.Fatalf("SendTestEvent() error = %v", err)
+	}
+	if delivery.Status != models.WebhookDeliveryDelivered || delivery.ResponseCode != http.StatusOK ||
+		receiver.requests[0].Header.Get(webhookEventHeader) != models.WebhookEventTest {
+		t.Errorf("test delivery = %+v, want delivered webhook_test with code 200", delivery)
+	}
+
+	// Недоступный получатель: тестовое событие не ставится на повтор
+	receiver.server.Close()
+	delivery, err = webhooks.SendTestEvent(ctx, 1, hook.ID)
+	if err != nil {
+		t.Fatalf("SendTestEvent() error = %v", err)
+	}
+	if delivery.Status != models.WebhookDeliveryFailed || delivery.ResponseCode != 0 || delivery.LastError == "" {
+		t.Errorf("test delivery to a closed server = %+v, want failed without a response code", delivery)
+	}
+	_ = webhooks.ProcessDeliveries(ctx)
+	if deliveries, _ := webhooks.GetDeliveries(ctx, 1, hook.ID, 10, 0); len(deliveries) != 2 || deliveries[0].Attempts != 1 {
+		t.Errorf("deliveries = %+v, want two test deliveries with one attempt each", deliveries)
+	}
+}
+
+func TestWebhookService_RejectsNonPublicAddresses(t *testing.T) {
+	ctx := context.Background()
+	webhooks := newTestWebhookService(mocks.NewMockWebhookRepository(), models.WebhookRule{}, false)
+	eventTypes := []string{models.EventGameActivated}
+
+	rejected := []struct {
+		name string
+		url  string
+	}{
+		{"loopback", "http://127.0.0.1:8080/hooks"},
+		{"localhost ipv6", "http://[::1]/hooks"},
+		{"private network", "https://10.1.2.3/hooks"},
+		{"cloud metadata", "http://169.254.169.254/latest/meta-data"},
+		{"shared address space", "http://100.100.100.200/hooks"},
+		{"unspecified", "http://0.0.0.0/hooks"},
+		{"name resolving to metadata", "http://metadata.test/hooks"},
+		{"name with one private address", "https://internal.test/hooks"},
+		{"unresolvable name", "https://unknown.test/hooks"},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := webhooks.CreateWebhook(ctx, 1, tt.url, "", eventTypes); !errors.Is(err, models.ErrInvalidWebhookURL) {
+				t.Errorf("CreateWebhook(%q) error = %v, want ErrInvalidWebhookURL", tt.url, err)
+			}
+		})
+	}
+
+	hook, err := webhooks.CreateWebhook(ctx, 1, "https://93.184.215.14/hooks", "", eventTypes)
+	if err != nil {
+		t.Fatalf("CreateWebhook() with a public address error = %v", err)
+	}
+	loopback := "http://localhost/hooks"
+	if _, err := webhooks.UpdateWebhook(ctx, 1, hook.ID, &models.WebhookUpdate{URL: &loopback}); !errors.Is(err, models.ErrInvalidWebhookURL) {
+		t.Errorf("UpdateWebhook() to %q error = %v, want ErrInvalidWebhookURL", loopback, err)
+	}
+}
+
+func TestWebhookService_DialRejectsNonPublicAddresses(t *testing.T) {
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t)
+	webhooks := newTestWebhookService(mocks.NewMockWebhookRepository(), models.WebhookRule{}, true)
+	hook, err := webhooks.CreateWebhook(ctx, 1, receiver.server.URL, "", []string{models.EventGameActivated})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+
+	// Адрес прошёл проверку при создании, но к моменту отправки указывает на loopback (DNS rebinding)
+	webhooks.addrAllowed = isPublicWebhookAddr
+	delivery, err := webhooks.SendTestEvent(ctx, 1, hook.ID)
+	if err != nil {
+		t.Fatalf("SendTestEvent() error = %v", err)
+	}
+	if delivery.Status != models.WebhookDeliveryFailed || !strings.Contains(delivery.LastError, "not a public address") {
+		t.Errorf("delivery = %+v, want failed on the address check", delivery)
+	}
+	if receiver.received() != 0 {
+		t.Errorf("receiver got %d requests, want none", receiver.received())
+	}
+}
@@ -0,0 +1,207 @@
+package telegram
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/TakuroBreath/wordle/internal/models"
+)
+
+// DefaultAPIEndpoint адрес Telegram Bot API
+const DefaultAPIEndpoint = "https://api.telegram.org"
+
+// Статусы участника чата, при которых пользователь считается членом группы
+var memberStatuses = map[string]bool{
+	"creator":       true,
+	"administrator": true,
+	"member":        true,
+	"restricted":    true,
+}
+
+// Client клиент Telegram Bot API
+type Client struct {
+	botToken    string
+	apiEndpoint string
+	httpClient  *http.Client
+}
+
+// NewClient создает новый клиент Telegram Bot API
+func NewClient(botToken string) *Client {
+	return &Client{
+		botToken:    botToken,
+		apiEndpoint: DefaultAPIEndpoint,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// WithAPIEndpoint задает адрес Bot API (для локального сервера Bot API или тестов)
+func (c *Client) WithAPIEndpoint(endpoint string) *Client {
+	c.apiEndpoint = endpoint
+	return c
+}
+
+// apiResponse общий формат ответа Bot API
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// call выполняет метод Bot API и декодирует поле result
+func (c *Client) call(ctx context.Context, method string, params url.Values, result any) error {
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.apiEndpoint, c.botToken, method)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.URL.RawQuery = params.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var response apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+
+	if !response.OK {
+		return &APIError{
+			Code:        response.ErrorCode,
+			Description: response.Description,
+			RetryAfter:  time.Duration(response.Parameters.RetryAfter) * time.Second,
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+// APIError ошибка, возвращённая Bot API
+type APIError struct {
+	Code        int
+	Description string
+	RetryAfter  time.Duration // Через сколько можно повторить запрос при превышении лимита (429)
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", e.Code, e.Description)
+}
+
+// IsChatMember проверяет, состоит ли пользователь в чате (getChatMember).
+// Бот должен быть участником чата, иначе Bot API вернёт ошибку
+func (c *Client) IsChatMember(ctx context.Context, chatID int64, userID uint64) (bool, error) {
+	params := url.Values{}
+	params.Set("chat_id", strconv.FormatInt(chatID, 10))
+	params.Set("user_id", strconv.FormatUint(userID, 10))
+
+	var member struct {
+		Status   string `json:"status"`
+		IsMember bool   `json:"is_member"`
+	}
+
+	if err := c.call(ctx, "getChatMember", params, &member); err != nil {
+		// Пользователь ни разу не заходил в чат
+		if apiErr, ok := err.(*APIError); ok && apiErr.Code == http.StatusBadRequest {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if member.Status == "restricted" {
+		return member.IsMember, nil
+	}
+	return memberStatuses[member.Status], nil
+}
+
+// SendMessage отправляет текстовое сообщение в чат (sendMessage)
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	params := url.Values{}
+	params.Set("chat_id", strconv.FormatInt(chatID, 10))
+	params.Set("text", text)
+
+	return c.call(ctx, "sendMessage", params, nil)
+}
+
+// NotifyUser отправляет личное сообщение пользователю. Личный чат с ботом
+// совпадает с Telegram ID, пользователь должен был запустить бота
+func (c *Client) NotifyUser(ctx context.Context, userID uint64, text string) error {
+	return c.SendMessage(ctx, int64(userID), text)
+}
+
+// inlineQueryResultArticle результат inline-запроса типа article
+type inlineQueryResultArticle struct {
+	Type                string `json:"type"`
+	ID                  string `json:"id"`
+	Title               string `json:"title"`
+	Description         string `json:"description,omitempty"`
+	URL                 string `json:"url,omitempty"`
+	InputMessageContent struct {
+		MessageText string `json:"message_text"`
+	} `json:"input_message_content"`
+}
+
+// AnswerInlineQuery отвечает на inline-запрос списком карточек (answerInlineQuery)
+func (c *Client) AnswerInlineQuery(ctx context.Context, queryID string, results []models.BotInlineResult) error {
+	articles := make([]inlineQueryResultArticle, 0, len(results))
+	for _, result := range results {
+		article := inlineQueryResultArticle{
+			Type:        "article",
+			ID:          result.ID,
+			Title:       result.Title,
+			Description: result.Description,
+			URL:         result.URL,
+		}
+		article.InputMessageContent.MessageText = result.MessageText
+		articles = append(articles, article)
+	}
+
+	encoded, err := json.Marshal(articles)
+	if err != nil {
+		return fmt.Errorf("failed to encode inline results: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("inline_query_id", queryID)
+	params.Set("results", string(encoded))
+	// Список игр меняется, поэтому ответ кешируется ненадолго
+	params.Set("cache_time", "10")
+
+	return c.call(ctx, "answerInlineQuery", params, nil)
+}
+
+// SetWebhook регистрирует адрес webhook (setWebhook). Bot API передаёт secret
+// в заголовке X-Telegram-Bot-Api-Secret-Token каждого обновления
+func (c *Client) SetWebhook(ctx context.Context, webhookURL, secret string) error {
+	params := url.Values{}
+	params.Set("url", webhookURL)
+	params.Set("secret_token", secret)
+	params.Set("allowed_updates", `["message","inline_query"]`)
+
+	return c.call(ctx, "setWebhook", params, nil)
+}
+
+// WebhookSecret вычисляет секрет webhook из токена бота, если он не задан в конфигурации.
+// Bot API допускает в секрете только символы A-Z, a-z, 0-9, _ и -, поэтому используется hex
+func WebhookSecret(botToken string) string {
+	sum := sha256.Sum256([]byte("webhook:" + botToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -12,6 +13,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxTransactionAttempts сколько опросов подряд повторяется входящая транзакция, обработка которой
+// завершилась ошибкой. Пока попытки не исчерпаны, lt не сдвигается дальше неё
+const maxTransactionAttempts = 5
+
 // BlockchainWorker обрабатывает транзакции из блокчейна
 type BlockchainWorker struct {
 	tonService         models.TONService
@@ -19,10 +24,13 @@ type BlockchainWorker struct {
 	lobbyRepo          models.LobbyRepository
 	userRepo           models.UserRepository
 	transactionRepo    models.TransactionRepository
+	gameAccess         models.GameAccessChecker
+	events             models.EventService
 	
 	masterWalletAddress string
 	pollInterval        time.Duration
 	lastProcessedLt     int64
+	failedAttempts      map[string]int // Неудачные попытки обработки по хешу транзакции
 	
 	mu       sync.RWMutex
 	running  bool
@@ -43,6 +51,8 @@ func NewBlockchainWorker(
 	lobbyRepo models.LobbyRepository,
 	userRepo models.UserRepository,
 	transactionRepo models.TransactionRepository,
+	gameAccess models.GameAccessChecker,
+	events models.EventService,
 	config WorkerConfig,
 ) *BlockchainWorker {
 	return &BlockchainWorker{
@@ -51,8 +61,11 @@ func NewBlockchainWorker(
 		lobbyRepo:           lobbyRepo,
 		userRepo:            userRepo,
 		transactionRepo:     transactionRepo,
+		gameAccess:          gameAccess,
+		events:              events,
 		masterWalletAddress: config.MasterWalletAddress,
 		pollInterval:        config.PollInterval,
+		failedAttempts:      make(map[string]int),
 		stopChan:            make(chan struct{}),
 		logger:              logger.GetLogger(zap.String("worker", "blockchain")),
 	}
@@ -157,7 +170,8 @@ func (w *BlockchainWorker) processTransactions(ctx context.Context) {
 		exists, err := w.transactionRepo.ExistsByTxHash(ctx, tx.Hash)
 		if err != nil {
 			w.logger.Error("Failed to check transaction existence", zap.Error(err), zap.String("hash", tx.Hash))
-			continue
+			// Транзакция и следующие за ней будут обработаны на следующем опросе
+			return
 		}
 		if exists {
 			w.logger.Debug("Transaction already processed", zap.String("hash", tx.Hash))
@@ -167,16 +181,40 @@ func (w *BlockchainWorker) processTransactions(ctx context.Context) {
 
 		// Обрабатываем транзакцию
 		if err := w.processTransaction(ctx, tx); err != nil {
-			w.logger.Error("Failed to process transaction",
-				zap.Error(err),
-				zap.String("hash", tx.Hash),
-				zap.String("comment", tx.Comment))
+			if !w.giveUpTransaction(tx, err) {
+				// Не сдвигаем lt: платёж уже пришёл, транзакция повторится на следующем опросе
+				return
+			}
 		}
+		delete(w.failedAttempts, tx.Hash)
 
 		w.updateLastProcessedLt(ctx, tx.Lt)
 	}
 }
 
+// giveUpTransaction учитывает неудачную попытку обработки транзакции.
+// Возвращает true, если попытки исчерпаны и транзакцию нужно пропустить для разбора вручную
+func (w *BlockchainWorker) giveUpTransaction(tx *models.BlockchainTransaction, err error) bool {
+	w.failedAttempts[tx.Hash]++
+	attempts := w.failedAttempts[tx.Hash]
+
+	log := w.logger.With(
+		zap.Error(err),
+		zap.String("hash", tx.Hash),
+		zap.String("from", tx.FromAddress),
+		zap.Float64("amount", tx.Amount),
+		zap.String("comment", tx.Comment),
+		zap.Int("attempts", attempts))
+
+	if attempts < maxTransactionAttempts {
+		log.Warn("Failed to process transaction, will retry")
+		return false
+	}
+
+	log.Error("Failed to process transaction, giving up: manual review required")
+	return true
+}
+
 // updateLastProcessedLt обновляет последний обработанный lt
 func (w *BlockchainWorker) updateLastProcessedLt(ctx context.Context, lt int64) {
 	w.mu.Lock()
@@ -282,11 +320,6 @@ func (w *BlockchainWorker) processGameDeposit(ctx context.Context, gameShortID s
 	dbTx.GameShortID = gameShortID
 	dbTx.Description = fmt.Sprintf("Game deposit for %s", game.Title)
 
-	// Сохраняем транзакцию
-	if err := w.transactionRepo.Create(ctx, dbTx); err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
-	}
-
 	// Добавляем в reward pool
 	if game.Currency == models.CurrencyTON {
 		game.RewardPoolTon += tx.Amount
@@ -298,6 +331,7 @@ func (w *BlockchainWorker) processGameDeposit(ctx context.Context, gameShortID s
 	game.UpdatedAt = time.Now()
 
 	// Проверяем, достаточно ли средств для активации
+	wasActive := game.Status == models.GameStatusActive
 	requiredDeposit := game.GetRequiredDeposit()
 	var currentPool float64
 	if game.Currency == models.CurrencyTON {
@@ -312,7 +346,8 @@ func (w *BlockchainWorker) processGameDeposit(ctx context.Context, gameShortID s
 			zap.Float64("required", requiredDeposit),
 			zap.Float64("current", currentPool))
 
-		game.Status = models.GameStatusActive
+		// До времени начала игра ждёт в статусе scheduled
+		game.Status = game.ActivationStatus(time.Now())
 	} else {
 		w.logger.Info("Insufficient funds for game activation",
 			zap.String("game_id", game.ID.String()),
@@ -320,9 +355,24 @@ func (w *BlockchainWorker) processGameDeposit(ctx context.Context, gameShortID s
 			zap.Float64("current", currentPool))
 	}
 
-	// Обновляем игру
-	if err := w.gameRepo.Update(ctx, game); err != nil {
-		return fmt.Errorf("failed to update game: %w", err)
+	// Транзакция, игра и событие активации для подписчиков создателя сохраняются вместе
+	err = w.withinEventTx(ctx, func(ctx context.Context) (*models.DomainEvent, error) {
+		if err := w.transactionRepo.Create(ctx, dbTx); err != nil {
+			return nil, fmt.Errorf("failed to create transaction: %w", err)
+		}
+		if err := w.gameRepo.Update(ctx, game); err != nil {
+			return nil, fmt.Errorf("failed to update game: %w", err)
+		}
+		if wasActive || game.Status != models.GameStatusActive {
+			return nil, nil
+		}
+		return models.NewDomainEvent(models.EventGameActivated, game.ID, &models.GameActivatedEvent{
+			GameID:    game.ID,
+			CreatorID: game.CreatorID,
+		})
+	})
+	if err != nil {
+		return err
 	}
 
 	w.logger.Info("Game deposit processed successfully",
@@ -346,10 +396,15 @@ func (w *BlockchainWorker) processLobbyBet(ctx context.Context, gameShortID stri
 	}
 
 	// Проверяем статус игры
-	if game.Status != models.GameStatusActive {
-		w.logger.Warn("Game is not active",
+	if !game.AcceptsBets(time.Now()) {
+		w.logger.Warn("Game is not accepting bets",
 			zap.String("game_id", game.ID.String()),
 			zap.String("status", game.Status))
+		// Закрывающаяся игра больше не примет ставку, возвращаем её отправителю
+		if game.Status == models.GameStatusClosing || game.Status == models.GameStatusClosed || game.IsEnded(time.Now()) {
+			user, _ := w.userRepo.GetByWallet(ctx, tx.FromAddress)
+			return w.refundLobbyBet(ctx, game, user, tx, dbTx, "game is closed")
+		}
 		// TODO: Вернуть деньги отправителю
 		return nil
 	}
@@ -359,10 +414,27 @@ func (w *BlockchainWorker) processLobbyBet(ctx context.Context, gameShortID stri
 	if err != nil {
 		w.logger.Warn("User not found by wallet address",
 			zap.String("wallet", tx.FromAddress))
+		if game.IsPrivate() {
+			// Доступ к приватной игре нельзя проверить без пользователя
+			return w.refundLobbyBet(ctx, game, nil, tx, dbTx, "sender is not allowed to play this private game")
+		}
 		// TODO: Вернуть деньги или создать пользователя
 		return nil
 	}
 
+	// Проверяем доступ к приватной игре
+	if game.IsPrivate() {
+		if err := w.checkGameAccess(ctx, game, user.TelegramID, tx.Comment); err != nil {
+			if !errors.Is(err, models.ErrGameAccessDenied) {
+				return fmt.Errorf("failed to check game access: %w", err)
+			}
+			w.logger.Warn("Sender is not allowed to play private game",
+				zap.String("game_id", game.ID.String()),
+				zap.Uint64("user_id", user.TelegramID))
+			return w.refundLobbyBet(ctx, game, user, tx, dbTx, "sender is not allowed to play this private game")
+		}
+	}
+
 	// Проверяем сумму ставки
 	if tx.Amount < game.MinBet {
 		w.logger.Warn("Bet amount too low",
@@ -372,22 +444,36 @@ func (w *BlockchainWorker) processLobbyBet(ctx context.Context, gameShortID stri
 		return nil
 	}
 
-	if tx.Amount > game.MaxBet {
+	// Ставка ограничивается max_bet, а tx.Amount остаётся полученной суммой: возврат отправляет её целиком
+	betAmount := tx.Amount
+	if betAmount > game.MaxBet {
 		w.logger.Warn("Bet amount too high, capping to max_bet",
 			zap.Float64("bet", tx.Amount),
 			zap.Float64("max_bet", game.MaxBet))
-		tx.Amount = game.MaxBet
+		betAmount = game.MaxBet
 	}
 
 	// Проверяем, достаточно ли средств в пуле игры
-	if !game.CanAcceptBet(tx.Amount) {
+	if !game.CanAcceptBet(betAmount) {
 		w.logger.Warn("Game cannot accept bet, insufficient pool",
-			zap.Float64("bet", tx.Amount),
+			zap.Float64("bet", betAmount),
 			zap.Float64("available", game.GetAvailableRewardPool()))
 		// TODO: Вернуть деньги
 		return nil
 	}
 
+	// Проверяем лимиты риска создателя: худший исход всех активных лобби должен укладываться в лимиты
+	if err := w.checkRiskExposure(ctx, game, betAmount); err != nil {
+		if !errors.Is(err, models.ErrRiskLimitReached) {
+			return err
+		}
+		w.logger.Warn("Bet exceeds game risk limits",
+			zap.String("game_id", game.ID.String()),
+			zap.Float64("bet", betAmount),
+			zap.Error(err))
+		return w.refundLobbyBet(ctx, game, user, tx, dbTx, "game risk limits reached")
+	}
+
 	// Проверяем, нет ли уже активного лобби
 	existingLobby, err := w.lobbyRepo.GetActiveByGameAndUser(ctx, game.ID, user.TelegramID)
 	if err == nil && existingLobby != nil {
@@ -404,7 +490,7 @@ func (w *BlockchainWorker) processLobbyBet(ctx context.Context, gameShortID stri
 	}
 
 	// Резервируем средства в игре
-	potentialReward := tx.Amount * game.RewardMultiplier
+	potentialReward := betAmount * game.RewardMultiplier
 	if err := w.gameRepo.IncrementReservedAmount(ctx, game.ID, potentialReward); err != nil {
 		return fmt.Errorf("failed to reserve funds: %w", err)
 	}
@@ -418,7 +504,7 @@ func (w *BlockchainWorker) processLobbyBet(ctx context.Context, gameShortID stri
 		UserID:          user.TelegramID,
 		MaxTries:        game.MaxTries,
 		TriesUsed:       0,
-		BetAmount:       tx.Amount,
+		BetAmount:       betAmount,
 		PotentialReward: potentialReward,
 		PaymentTxHash:   tx.Hash,
 		Currency:        game.Currency,
@@ -451,12 +537,98 @@ func (w *BlockchainWorker) processLobbyBet(ctx context.Context, gameShortID stri
 	w.logger.Info("Lobby created successfully",
 		zap.String("lobby_id", lobby.ID.String()),
 		zap.Uint64("user_id", user.TelegramID),
-		zap.Float64("bet", tx.Amount),
+		zap.Float64("bet", betAmount),
 		zap.Float64("potential_reward", potentialReward))
 
 	return nil
 }
 
+// checkGameAccess проверяет доступ отправителя ставки к приватной игре.
+// Токен приглашения подтверждается билетом из комментария, выданным этому пользователю
+func (w *BlockchainWorker) checkGameAccess(ctx context.Context, game *models.Game, userID uint64, comment string) error {
+	if w.gameAccess == nil {
+		return models.ErrGameAccessDenied
+	}
+
+	inviteToken := ""
+	if ticket := models.ParsePaymentInviteTicket(comment); ticket != "" && ticket == game.InviteTicket(userID) {
+		inviteToken = game.InviteToken
+	}
+
+	return w.gameAccess.CheckAccess(ctx, game, userID, inviteToken)
+}
+
+// checkRiskExposure проверяет, что ставка укладывается в лимиты риска создателя
+func (w *BlockchainWorker) checkRiskExposure(ctx context.Context, game *models.Game, betAmount float64) error {
+	activeLobbies := 0
+	if game.MaxActiveLobbies > 0 {
+		count, err := w.lobbyRepo.CountActiveByGame(ctx, game.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count active lobbies: %w", err)
+		}
+		activeLobbies = count
+	}
+	return game.CheckExposure(game.MaxPotentialReward(betAmount), activeLobbies, time.Now())
+}
+
+// refundLobbyBet возвращает ставку отправителю on-chain и записывает возврат с хешем исходной транзакции.
+// Запись создаётся до отправки: она не даёт обработать исходную транзакцию повторно и вернуть ставку ещё раз,
+// даже если воркер упадёт после отправки. Возврат неизвестному отправителю записывается без пользователя
+func (w *BlockchainWorker) refundLobbyBet(ctx context.Context, game *models.Game, user *models.User, tx *models.BlockchainTransaction, dbTx *models.Transaction, reason string) error {
+	if w.tonService == nil {
+		return fmt.Errorf("cannot refund bet %s: TON service is not configured", tx.Hash)
+	}
+
+	dbTx.Type = models.TransactionTypeRefund
+	dbTx.Amount = tx.Amount
+	dbTx.Status = models.TransactionStatusPending
+	dbTx.GameID = &game.ID
+	if user != nil {
+		dbTx.UserID = user.TelegramID
+		dbTx.Description = fmt.Sprintf("Bet for game %s refunded (%s)", game.Title, reason)
+	} else {
+		dbTx.UserID = 0
+		dbTx.Description = fmt.Sprintf("Bet from unknown wallet %s for game %s refunded (%s)",
+			tx.FromAddress, game.Title, reason)
+	}
+	if err := w.transactionRepo.Create(ctx, dbTx); err != nil {
+		return fmt.Errorf("failed to create refund transaction: %w", err)
+	}
+
+	comment := "Refund: " + reason
+
+	var refundHash string
+	var err error
+	if tx.Currency == models.CurrencyUSDT {
+		refundHash, err = w.tonService.SendUSDT(ctx, tx.FromAddress, tx.Amount, comment)
+	} else {
+		refundHash, err = w.tonService.SendTON(ctx, tx.FromAddress, tx.Amount, comment)
+	}
+	if err != nil {
+		// Возврат мог уйти в сеть, поэтому повторно не отправляется: запись остаётся для разбора вручную
+		dbTx.Status = models.TransactionStatusFailed
+		dbTx.ErrorMessage = err.Error()
+		if updateErr := w.transactionRepo.Update(ctx, dbTx); updateErr != nil {
+			w.logger.Error("Failed to mark refund failed", zap.String("tx_hash", tx.Hash), zap.Error(updateErr))
+		}
+		return fmt.Errorf("failed to refund bet: %w", err)
+	}
+
+	dbTx.Status = models.TransactionStatusCompleted
+	if err := w.transactionRepo.Update(ctx, dbTx); err != nil {
+		return fmt.Errorf("failed to complete refund transaction: %w", err)
+	}
+
+	w.logger.Info("Lobby bet refunded",
+		zap.String("game_id", game.ID.String()),
+		zap.String("from", tx.FromAddress),
+		zap.Float64("amount", tx.Amount),
+		zap.String("refund_tx_hash", refundHash),
+		zap.String("reason", reason))
+
+	return nil
+}
+
 // processUserDeposit обрабатывает обычный депозит пользователя
 func (w *BlockchainWorker) processUserDeposit(ctx context.Context, tx *models.BlockchainTransaction) error {
 	w.logger.Info("Processing user deposit",
@@ -489,18 +661,6 @@ func (w *BlockchainWorker) processUserDeposit(ctx context.Context, tx *models.Bl
 		return w.transactionRepo.Create(ctx, dbTx)
 	}
 
-	// Обновляем баланс пользователя
-	if tx.Currency == models.CurrencyTON {
-		if err := w.userRepo.UpdateTonBalance(ctx, user.TelegramID, tx.Amount); err != nil {
-			return fmt.Errorf("failed to update user balance: %w", err)
-		}
-	} else {
-		if err := w.userRepo.UpdateUsdtBalance(ctx, user.TelegramID, tx.Amount); err != nil {
-			return fmt.Errorf("failed to update user balance: %w", err)
-		}
-	}
-
-	// Сохраняем транзакцию
 	dbTx := &models.Transaction{
 		UserID:       user.TelegramID,
 		Type:         models.TransactionTypeDeposit,
@@ -518,8 +678,26 @@ func (w *BlockchainWorker) processUserDeposit(ctx context.Context, tx *models.Bl
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := w.transactionRepo.Create(ctx, dbTx); err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
+	// Баланс, транзакция и событие зачисления сохраняются вместе
+	err = w.withinEventTx(ctx, func(ctx context.Context) (*models.DomainEvent, error) {
+		var balanceErr error
+		if tx.Currency == models.CurrencyTON {
+			balanceErr = w.userRepo.UpdateTonBalance(ctx, user.TelegramID, tx.Amount)
+		} else {
+			balanceErr = w.userRepo.UpdateUsdtBalance(ctx, user.TelegramID, tx.Amount)
+		}
+		if balanceErr != nil {
+			return nil, fmt.Errorf("failed to update user balance: %w", balanceErr)
+		}
+
+		if err := w.transactionRepo.Create(ctx, dbTx); err != nil {
+			return nil, fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		return models.NewDomainEvent(models.EventDepositCredited, dbTx.ID, models.NewTransactionEvent(dbTx))
+	})
+	if err != nil {
+		return err
 	}
 
 	w.logger.Info("User deposit processed successfully",
@@ -589,15 +767,18 @@ func (w *BlockchainWorker) processWithdrawal(ctx context.Context, tx *models.Tra
 	tx.ProcessedAt = &now
 	tx.UpdatedAt = now
 
-	if err := w.transactionRepo.Update(ctx, tx); err != nil {
-		return fmt.Errorf("failed to update transaction: %w", err)
-	}
-
-	// Обновляем pending_withdrawal у пользователя
-	if err := w.userRepo.UpdatePendingWithdrawal(ctx, tx.UserID, -tx.Amount); err != nil {
-		w.logger.Error("Failed to update user pending withdrawal",
-			zap.Error(err),
-			zap.Uint64("user_id", tx.UserID))
+	// Транзакция, pending_withdrawal пользователя и событие отправки сохраняются вместе
+	err = w.withinEventTx(ctx, func(ctx context.Context) (*models.DomainEvent, error) {
+		if err := w.transactionRepo.Update(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to update transaction: %w", err)
+		}
+		if err := w.userRepo.UpdatePendingWithdrawal(ctx, tx.UserID, -tx.Amount); err != nil {
+			return nil, fmt.Errorf("failed to update user pending withdrawal: %w", err)
+		}
+		return models.NewDomainEvent(models.EventWithdrawalSent, tx.ID, models.NewTransactionEvent(tx))
+	})
+	if err != nil {
+		return err
 	}
 
 	w.logger.Info("Withdrawal processed successfully",
@@ -607,6 +788,22 @@ func (w *BlockchainWorker) processWithdrawal(ctx context.Context, tx *models.Tra
 	return nil
 }
 
+// withinEventTx выполняет изменение состояния change и записывает возвращённое им событие в outbox
+// в той же транзакции. Без шины событий изменение выполняется без транзакции, а событие не записывается
+func (w *BlockchainWorker) withinEventTx(ctx context.Context, change func(ctx context.Context) (*models.DomainEvent, error)) error {
+	if w.events == nil {
+		_, err := change(ctx)
+		return err
+	}
+	return w.events.WithinTx(ctx, func(ctx context.Context) error {
+		event, err := change(ctx)
+		if err != nil || event == nil {
+			return err
+		}
+		return w.events.Publish(ctx, event)
+	})
+}
+
 // IsValidPaymentComment проверяет, является ли комментарий валидным платёжным комментарием
 func IsValidPaymentComment(comment string) bool {
 	if len(comment) < 4 {
@@ -0,0 +1,9 @@
+-- Откат миграции досрочной выплаты
+
+DROP INDEX IF EXISTS idx_history_status;
+
+-- Завершённые досрочно лобби считаем отменёнными
+UPDATE lobbies SET status = 'canceled' WHERE status = 'cashed_out';
+
+ALTER TABLE lobbies DROP CONSTRAINT IF EXISTS check_lobby_status;
+ALTER TABLE lobbies ADD CONSTRAINT check_lobby_status CHECK (status IN ('pending', 'active', 'success', 'failed_tries', 'failed_expired', 'failed_internal', 'canceled'));
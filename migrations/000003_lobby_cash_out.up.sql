@@ -0,0 +1,8 @@
+-- Миграция для досрочной выплаты (cash-out) по активному лобби
+
+-- Добавляем статус лобби cashed_out
+ALTER TABLE lobbies DROP CONSTRAINT IF EXISTS check_lobby_status;
+ALTER TABLE lobbies ADD CONSTRAINT check_lobby_status CHECK (status IN ('pending', 'active', 'success', 'failed_tries', 'failed_expired', 'failed_internal', 'canceled', 'cashed_out'));
+
+-- Индекс для выборки истории по статусу (в том числе cash_out)
+CREATE INDEX IF NOT EXISTS idx_history_status ON history(status);
@@ -0,0 +1,8 @@
+-- Откат миграции ставок зрителей
+
+DELETE FROM transactions WHERE type IN ('side_bet', 'side_bet_payout');
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve'));
+
+DROP TABLE IF EXISTS side_bets;
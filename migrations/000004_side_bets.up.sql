@@ -0,0 +1,27 @@
+-- Миграция для ставок зрителей на активные лобби
+
+CREATE TABLE IF NOT EXISTS side_bets (
+    id UUID PRIMARY KEY,
+    lobby_id UUID NOT NULL REFERENCES lobbies(id),
+    game_id UUID NOT NULL REFERENCES games(id),
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    within_tries INTEGER NOT NULL,
+    placed_at_try INTEGER NOT NULL,
+    amount DECIMAL(18, 6) NOT NULL,
+    odds DECIMAL(8, 2) NOT NULL,
+    payout DECIMAL(18, 6) DEFAULT 0,
+    currency VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    settled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT check_side_bet_status CHECK (status IN ('open', 'won', 'lost', 'refunded'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_side_bets_lobby_id ON side_bets(lobby_id);
+CREATE INDEX IF NOT EXISTS idx_side_bets_user_id ON side_bets(user_id);
+CREATE INDEX IF NOT EXISTS idx_side_bets_status ON side_bets(status);
+
+-- Добавляем типы транзакций для ставок зрителей
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout'));
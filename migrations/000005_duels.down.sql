@@ -0,0 +1,9 @@
+-- Откат миграции дуэлей
+
+DELETE FROM transactions WHERE type IN ('duel_stake', 'duel_payout', 'duel_refund');
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout'));
+
+DROP TABLE IF EXISTS duel_attempts;
+DROP TABLE IF EXISTS duels;
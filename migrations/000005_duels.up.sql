@@ -0,0 +1,51 @@
+-- Миграция для дуэлей игроков
+
+CREATE TABLE IF NOT EXISTS duels (
+    id UUID PRIMARY KEY,
+    short_id VARCHAR(16) NOT NULL UNIQUE, -- Код приглашения (startapp=duel_<short_id>)
+    challenger_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    opponent_id BIGINT REFERENCES users(telegram_id), -- NULL - открытый вызов
+    stake DECIMAL(18, 6) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    word_length INTEGER NOT NULL,
+    max_tries INTEGER NOT NULL,
+    time_limit INTEGER NOT NULL, -- Минуты на игру
+    challenger_word VARCHAR(50) NOT NULL,
+    opponent_word VARCHAR(50),
+    challenger_tries INTEGER NOT NULL DEFAULT 0,
+    challenger_solved BOOLEAN NOT NULL DEFAULT FALSE,
+    challenger_finished_at TIMESTAMP WITH TIME ZONE,
+    opponent_tries INTEGER NOT NULL DEFAULT 0,
+    opponent_solved BOOLEAN NOT NULL DEFAULT FALSE,
+    opponent_finished_at TIMESTAMP WITH TIME ZONE,
+    winner_id BIGINT REFERENCES users(telegram_id),
+    payout DECIMAL(18, 6) DEFAULT 0,
+    commission DECIMAL(18, 6) DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    invite_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    settled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT check_duel_status CHECK (status IN ('pending', 'active', 'finished', 'draw', 'expired', 'canceled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_duels_challenger_id ON duels(challenger_id);
+CREATE INDEX IF NOT EXISTS idx_duels_opponent_id ON duels(opponent_id);
+CREATE INDEX IF NOT EXISTS idx_duels_status ON duels(status);
+
+CREATE TABLE IF NOT EXISTS duel_attempts (
+    id UUID PRIMARY KEY,
+    duel_id UUID NOT NULL REFERENCES duels(id),
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    word VARCHAR(50) NOT NULL,
+    result JSONB NOT NULL, -- Массив результатов (0, 1, 2) для каждой буквы
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_duel_attempts_duel_user ON duel_attempts(duel_id, user_id);
+
+-- Добавляем типы транзакций для дуэлей
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund'));
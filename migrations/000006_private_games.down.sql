@@ -0,0 +1,10 @@
+-- Откат миграции приватных игр
+
+DROP INDEX IF EXISTS idx_games_public_active;
+
+ALTER TABLE games DROP CONSTRAINT IF EXISTS check_game_visibility;
+
+ALTER TABLE games DROP COLUMN IF EXISTS allowed_chat_id;
+ALTER TABLE games DROP COLUMN IF EXISTS allowed_user_ids;
+ALTER TABLE games DROP COLUMN IF EXISTS invite_token;
+ALTER TABLE games DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,11 @@
+-- Миграция для приватных игр по приглашению
+
+ALTER TABLE games ADD COLUMN IF NOT EXISTS visibility VARCHAR(10) NOT NULL DEFAULT 'public';
+ALTER TABLE games ADD COLUMN IF NOT EXISTS invite_token VARCHAR(64) UNIQUE;
+ALTER TABLE games ADD COLUMN IF NOT EXISTS allowed_user_ids BIGINT[] NOT NULL DEFAULT '{}';
+ALTER TABLE games ADD COLUMN IF NOT EXISTS allowed_chat_id BIGINT;
+
+ALTER TABLE games ADD CONSTRAINT check_game_visibility CHECK (visibility IN ('public', 'private'));
+
+-- Публичные списки выбирают только активные публичные игры
+CREATE INDEX IF NOT EXISTS idx_games_public_active ON games(created_at DESC) WHERE status = 'active' AND visibility = 'public';
@@ -0,0 +1,12 @@
+-- Откат миграции расписания игр
+
+DROP INDEX IF EXISTS idx_games_ends_at;
+DROP INDEX IF EXISTS idx_games_starts_at;
+
+UPDATE games SET status = 'inactive' WHERE status IN ('scheduled', 'closing');
+
+ALTER TABLE games DROP CONSTRAINT IF EXISTS check_game_status;
+ALTER TABLE games ADD CONSTRAINT check_game_status CHECK (status IN ('pending', 'active', 'inactive', 'closed'));
+
+ALTER TABLE games DROP COLUMN IF EXISTS ends_at;
+ALTER TABLE games DROP COLUMN IF EXISTS starts_at;
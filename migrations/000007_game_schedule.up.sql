@@ -0,0 +1,12 @@
+-- Миграция для запланированной активации и автоматического закрытия игр
+
+ALTER TABLE games ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE games ADD COLUMN IF NOT EXISTS ends_at TIMESTAMP WITH TIME ZONE;
+
+-- Добавляем статусы scheduled и closing
+ALTER TABLE games DROP CONSTRAINT IF EXISTS check_game_status;
+ALTER TABLE games ADD CONSTRAINT check_game_status CHECK (status IN ('pending', 'scheduled', 'active', 'inactive', 'closing', 'closed'));
+
+-- Индексы для планировщика
+CREATE INDEX IF NOT EXISTS idx_games_starts_at ON games(starts_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_games_ends_at ON games(ends_at) WHERE ends_at IS NOT NULL AND status NOT IN ('closing', 'closed');
@@ -0,0 +1,12 @@
+-- Откат миграции управления пулом игры
+
+DELETE FROM transactions WHERE type IN ('pool_withdraw', 'pool_top_up');
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund'));
+
+DROP INDEX IF EXISTS idx_games_auto_top_up;
+
+ALTER TABLE games DROP CONSTRAINT IF EXISTS check_auto_top_up;
+ALTER TABLE games DROP COLUMN IF EXISTS auto_top_up_amount;
+ALTER TABLE games DROP COLUMN IF EXISTS auto_top_up_threshold;
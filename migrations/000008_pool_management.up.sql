@@ -0,0 +1,13 @@
+-- Миграция для управления пулом игры: частичный вывод и автопополнение
+
+ALTER TABLE games ADD COLUMN IF NOT EXISTS auto_top_up_threshold DECIMAL(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE games ADD COLUMN IF NOT EXISTS auto_top_up_amount DECIMAL(18, 6) NOT NULL DEFAULT 0;
+
+ALTER TABLE games ADD CONSTRAINT check_auto_top_up CHECK (auto_top_up_threshold >= 0 AND auto_top_up_amount >= 0);
+
+-- Индекс для выборки игр с включённым автопополнением
+CREATE INDEX IF NOT EXISTS idx_games_auto_top_up ON games(updated_at) WHERE auto_top_up_amount > 0 AND status = 'active';
+
+-- Добавляем типы транзакций для операций с пулом
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up'));
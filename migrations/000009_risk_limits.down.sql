@@ -0,0 +1,9 @@
+-- Откат миграции лимитов риска
+
+ALTER TABLE games DROP CONSTRAINT IF EXISTS check_risk_limits;
+
+ALTER TABLE games DROP COLUMN IF EXISTS daily_loss_day;
+ALTER TABLE games DROP COLUMN IF EXISTS daily_loss;
+ALTER TABLE games DROP COLUMN IF EXISTS pool_floor;
+ALTER TABLE games DROP COLUMN IF EXISTS max_active_lobbies;
+ALTER TABLE games DROP COLUMN IF EXISTS max_daily_loss;
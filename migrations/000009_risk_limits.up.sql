@@ -0,0 +1,11 @@
+-- Миграция для лимитов риска создателя и автоматической приостановки игр
+
+ALTER TABLE games ADD COLUMN IF NOT EXISTS max_daily_loss DECIMAL(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE games ADD COLUMN IF NOT EXISTS max_active_lobbies INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE games ADD COLUMN IF NOT EXISTS pool_floor DECIMAL(18, 6) NOT NULL DEFAULT 0;
+
+-- Убыток создателя за день (выплаты минус проигранные ставки), сбрасывается при смене дня
+ALTER TABLE games ADD COLUMN IF NOT EXISTS daily_loss DECIMAL(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE games ADD COLUMN IF NOT EXISTS daily_loss_day DATE;
+
+ALTER TABLE games ADD CONSTRAINT check_risk_limits CHECK (max_daily_loss >= 0 AND max_active_lobbies >= 0 AND pool_floor >= 0);
@@ -0,0 +1,8 @@
+-- Откат миграции автоподстройки мультипликатора
+
+DROP INDEX IF EXISTS idx_games_auto_odds;
+
+ALTER TABLE games DROP CONSTRAINT IF EXISTS check_odds_bounds;
+
+ALTER TABLE games DROP COLUMN IF EXISTS odds_max_multiplier;
+ALTER TABLE games DROP COLUMN IF EXISTS odds_min_multiplier;
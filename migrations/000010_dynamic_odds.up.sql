@@ -0,0 +1,11 @@
+-- Миграция для автоподстройки мультипликатора по фактическим результатам игры
+
+-- Границы автоподстройки, задаваемые создателем (0 - автоподстройка выключена)
+ALTER TABLE games ADD COLUMN IF NOT EXISTS odds_min_multiplier DECIMAL(8, 2) NOT NULL DEFAULT 0;
+ALTER TABLE games ADD COLUMN IF NOT EXISTS odds_max_multiplier DECIMAL(8, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE games ADD CONSTRAINT check_odds_bounds CHECK (
+    odds_max_multiplier = 0 OR (odds_min_multiplier >= 1 AND odds_max_multiplier >= odds_min_multiplier)
+);
+
+CREATE INDEX IF NOT EXISTS idx_games_auto_odds ON games(updated_at) WHERE status = 'active' AND odds_max_multiplier > 0;
@@ -0,0 +1,5 @@
+-- Откат миграции оценки сложности слова
+
+DROP INDEX IF EXISTS idx_games_difficulty_score;
+
+ALTER TABLE games DROP COLUMN IF EXISTS difficulty_score;
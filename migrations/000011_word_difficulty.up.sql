@@ -0,0 +1,6 @@
+-- Миграция для оценки сложности загаданного слова
+
+-- Оценка сложности слова по словарю от 0 (лёгкое) до 100 (сложное), рассчитывается при создании игры
+ALTER TABLE games ADD COLUMN IF NOT EXISTS difficulty_score DECIMAL(5, 2) NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_games_difficulty_score ON games(difficulty_score) WHERE status = 'active';
@@ -0,0 +1,10 @@
+-- Откат миграции джекпота
+
+DELETE FROM transactions WHERE type = 'jackpot';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up'));
+
+DROP INDEX IF EXISTS idx_jackpot_entries_currency_created;
+DROP TABLE IF EXISTS jackpot_entries;
+DROP TABLE IF EXISTS jackpots;
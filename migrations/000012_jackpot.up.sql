@@ -0,0 +1,30 @@
+-- Миграция для прогрессивного джекпота платформы
+
+CREATE TABLE IF NOT EXISTS jackpots (
+    currency VARCHAR(10) PRIMARY KEY,
+    amount DECIMAL(18, 6) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT check_jackpot_amount CHECK (amount >= 0)
+);
+
+-- Журнал отчислений в джекпот и его выплат.
+-- По каждому лобби допускается не более одной записи каждого типа
+CREATE TABLE IF NOT EXISTS jackpot_entries (
+    id UUID PRIMARY KEY,
+    currency VARCHAR(10) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    amount DECIMAL(18, 6) NOT NULL,
+    balance_after DECIMAL(18, 6) NOT NULL,
+    lobby_id UUID NOT NULL REFERENCES lobbies(id),
+    game_id UUID NOT NULL REFERENCES games(id),
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT check_jackpot_entry_type CHECK (type IN ('contribution', 'payout')),
+    CONSTRAINT uq_jackpot_entries_lobby_type UNIQUE (lobby_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_jackpot_entries_currency_created ON jackpot_entries(currency, created_at DESC);
+
+-- Добавляем тип транзакции для выплаты джекпота
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up', 'jackpot'));
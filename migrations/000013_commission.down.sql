@@ -0,0 +1,6 @@
+-- Откат миграции учёта комиссии
+
+DROP INDEX IF EXISTS idx_commission_entries_creator;
+DROP INDEX IF EXISTS idx_commission_entries_created;
+DROP TABLE IF EXISTS commission_entries;
+DROP TABLE IF EXISTS house_accounts;
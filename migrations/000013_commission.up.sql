@@ -0,0 +1,29 @@
+-- Миграция для учёта комиссии сервиса на отдельном счёте
+
+CREATE TABLE IF NOT EXISTS house_accounts (
+    currency VARCHAR(10) PRIMARY KEY,
+    balance DECIMAL(18, 6) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Журнал комиссии. По каждому лобби или дуэли допускается не более одной записи каждого источника
+CREATE TABLE IF NOT EXISTS commission_entries (
+    id UUID PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    reference_id UUID NOT NULL,
+    game_id UUID REFERENCES games(id),
+    creator_id BIGINT NOT NULL DEFAULT 0,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    currency VARCHAR(10) NOT NULL,
+    volume DECIMAL(18, 6) NOT NULL DEFAULT 0,
+    rate DECIMAL(8, 6) NOT NULL,
+    amount DECIMAL(18, 6) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT check_commission_source CHECK (source IN ('lobby_loss', 'lobby_win', 'duel')),
+    CONSTRAINT check_commission_amount CHECK (amount >= 0 AND rate >= 0 AND rate < 1),
+    CONSTRAINT uq_commission_entries_reference_source UNIQUE (reference_id, source)
+);
+
+-- Индексы для отчётов по периодам и оборота создателя
+CREATE INDEX IF NOT EXISTS idx_commission_entries_created ON commission_entries(created_at);
+CREATE INDEX IF NOT EXISTS idx_commission_entries_creator ON commission_entries(creator_id, currency, created_at);
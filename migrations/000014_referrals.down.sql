@@ -0,0 +1,13 @@
+-- Откат миграции реферальной программы
+
+DELETE FROM transactions WHERE type = 'referral';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up', 'jackpot'));
+
+DROP INDEX IF EXISTS idx_referral_earnings_referrer;
+DROP INDEX IF EXISTS idx_referral_earnings_pending;
+DROP TABLE IF EXISTS referral_earnings;
+DROP INDEX IF EXISTS idx_referrals_referrer;
+DROP TABLE IF EXISTS referrals;
+DROP TABLE IF EXISTS referral_codes;
@@ -0,0 +1,45 @@
+-- Миграция для реферальной программы
+
+-- Реферальные коды пользователей
+CREATE TABLE IF NOT EXISTS referral_codes (
+    user_id BIGINT PRIMARY KEY REFERENCES users(telegram_id),
+    code VARCHAR(32) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Приглашённые пользователи. У пользователя может быть только один реферер
+CREATE TABLE IF NOT EXISTS referrals (
+    referee_id BIGINT PRIMARY KEY REFERENCES users(telegram_id),
+    referrer_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    code VARCHAR(32) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    block_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT check_referral_status CHECK (status IN ('active', 'blocked')),
+    CONSTRAINT check_referral_self CHECK (referee_id <> referrer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals(referrer_id, created_at DESC);
+
+-- Начисления рефереру с комиссии рефералов. По каждой записи комиссии допускается одно начисление
+CREATE TABLE IF NOT EXISTS referral_earnings (
+    id UUID PRIMARY KEY,
+    commission_entry_id UUID NOT NULL UNIQUE REFERENCES commission_entries(id),
+    referrer_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    referee_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    currency VARCHAR(10) NOT NULL,
+    amount DECIMAL(18, 6) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    credited_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT check_referral_earning_status CHECK (status IN ('pending', 'credited', 'canceled')),
+    CONSTRAINT check_referral_earning_amount CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_earnings_pending ON referral_earnings(referee_id, currency) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_referral_earnings_referrer ON referral_earnings(referrer_id);
+
+-- Добавляем тип транзакции для реферальных выплат
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up', 'jackpot', 'referral'));
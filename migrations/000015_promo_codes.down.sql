@@ -0,0 +1,18 @@
+-- Откат миграции промокодов и бонусных балансов
+
+DELETE FROM transactions WHERE type IN ('bonus', 'bonus_conversion');
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up', 'jackpot', 'referral'));
+
+DROP TABLE IF EXISTS bonus_stakes;
+DROP INDEX IF EXISTS idx_promo_redemptions_user;
+DROP INDEX IF EXISTS idx_promo_redemptions_code_user;
+DROP TABLE IF EXISTS promo_redemptions;
+DROP TABLE IF EXISTS promo_codes;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS check_bonus_balance;
+ALTER TABLE users DROP COLUMN IF EXISTS wager_usdt;
+ALTER TABLE users DROP COLUMN IF EXISTS wager_ton;
+ALTER TABLE users DROP COLUMN IF EXISTS bonus_usdt;
+ALTER TABLE users DROP COLUMN IF EXISTS bonus_ton;
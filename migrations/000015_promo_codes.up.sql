@@ -0,0 +1,60 @@
+-- Миграция для промокодов и бонусных балансов
+
+-- Бонусные балансы и оборот ставок, оставшийся до их перевода в основной баланс
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bonus_ton DECIMAL(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bonus_usdt DECIMAL(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS wager_ton DECIMAL(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS wager_usdt DECIMAL(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE users ADD CONSTRAINT check_bonus_balance CHECK (bonus_ton >= 0 AND bonus_usdt >= 0 AND wager_ton >= 0 AND wager_usdt >= 0);
+
+-- Промокоды
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id UUID PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL DEFAULT 'bonus',
+    currency VARCHAR(10) NOT NULL,
+    amount DECIMAL(18, 6) NOT NULL,
+    wager_multiplier DECIMAL(10, 4) NOT NULL DEFAULT 1,
+    max_redemptions INTEGER NOT NULL DEFAULT 0,
+    per_user_limit INTEGER NOT NULL DEFAULT 1,
+    redemptions INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT check_promo_type CHECK (type IN ('bonus', 'free_bet')),
+    CONSTRAINT check_promo_currency CHECK (currency IN ('TON', 'USDT')),
+    CONSTRAINT check_promo_amount CHECK (amount > 0),
+    CONSTRAINT check_promo_limits CHECK (max_redemptions >= 0 AND per_user_limit > 0)
+);
+
+-- Активации промокодов
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id UUID PRIMARY KEY,
+    promo_code_id UUID NOT NULL REFERENCES promo_codes(id),
+    code VARCHAR(32) NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    currency VARCHAR(10) NOT NULL,
+    amount DECIMAL(18, 6) NOT NULL,
+    wager DECIMAL(18, 6) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_code_user ON promo_redemptions(promo_code_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_user ON promo_redemptions(user_id, created_at DESC);
+
+-- Части ставок лобби, оплаченные бонусными средствами
+CREATE TABLE IF NOT EXISTS bonus_stakes (
+    lobby_id UUID PRIMARY KEY REFERENCES lobbies(id),
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    currency VARCHAR(10) NOT NULL,
+    bet_amount DECIMAL(18, 6) NOT NULL,
+    bonus_amount DECIMAL(18, 6) NOT NULL,
+    settled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT check_bonus_stake_amount CHECK (bonus_amount > 0 AND bonus_amount <= bet_amount)
+);
+
+-- Добавляем типы транзакций для бонусных начислений и перевода бонуса в основной баланс
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up', 'jackpot', 'referral', 'bonus', 'bonus_conversion'));
@@ -0,0 +1,6 @@
+-- Откат миграции достижений, серий и ежедневных наград
+
+DROP TABLE IF EXISTS game_players;
+DROP INDEX IF EXISTS idx_user_achievements_awarded_at;
+DROP TABLE IF EXISTS user_achievements;
+DROP TABLE IF EXISTS user_streaks;
@@ -0,0 +1,36 @@
+-- Миграция для достижений, серий и ежедневных наград
+
+-- Серии пользователя: победы подряд, дни с игрой и дни со входом
+CREATE TABLE IF NOT EXISTS user_streaks (
+    user_id BIGINT PRIMARY KEY REFERENCES users(telegram_id),
+    games_played INTEGER NOT NULL DEFAULT 0,
+    win_streak INTEGER NOT NULL DEFAULT 0,
+    best_win_streak INTEGER NOT NULL DEFAULT 0,
+    play_streak INTEGER NOT NULL DEFAULT 0,
+    best_play_streak INTEGER NOT NULL DEFAULT 0,
+    last_played_on TIMESTAMP WITH TIME ZONE,
+    login_streak INTEGER NOT NULL DEFAULT 0,
+    best_login_streak INTEGER NOT NULL DEFAULT 0,
+    last_login_on TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Полученные достижения (каждое выдаётся пользователю один раз)
+CREATE TABLE IF NOT EXISTS user_achievements (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    code VARCHAR(32) NOT NULL,
+    reward DECIMAL(18, 6) NOT NULL DEFAULT 0,
+    currency VARCHAR(10) NOT NULL,
+    awarded_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_achievements_awarded_at ON user_achievements(user_id, awarded_at);
+
+-- Разные игроки игры (для достижения создателя игры)
+CREATE TABLE IF NOT EXISTS game_players (
+    game_id UUID NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (game_id, user_id)
+);
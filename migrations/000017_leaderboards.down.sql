@@ -0,0 +1,15 @@
+-- Откат миграции таблиц лидеров и призов сезонов
+
+DELETE FROM transactions WHERE type = 'leaderboard_prize';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up', 'jackpot', 'referral', 'bonus', 'bonus_conversion'));
+
+DROP INDEX IF EXISTS idx_leaderboard_payouts_user;
+DROP TABLE IF EXISTS leaderboard_payouts;
+DROP TABLE IF EXISTS leaderboard_opt_outs;
+DROP INDEX IF EXISTS idx_history_created_at;
+
+ALTER TABLE history DROP COLUMN IF EXISTS tries_used;
+ALTER TABLE history DROP COLUMN IF EXISTS currency;
+ALTER TABLE history DROP COLUMN IF EXISTS bet_amount;
@@ -0,0 +1,42 @@
+-- Миграция для таблиц лидеров и призов сезонов
+
+-- Ставка, валюта и попытки в истории: таблицы лидеров строятся по истории, а не по счётчикам пользователей
+ALTER TABLE history ADD COLUMN IF NOT EXISTS bet_amount DECIMAL(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE history ADD COLUMN IF NOT EXISTS currency VARCHAR(10) NOT NULL DEFAULT 'TON';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS tries_used INTEGER NOT NULL DEFAULT 0;
+
+UPDATE history h
+SET bet_amount = l.bet_amount, tries_used = COALESCE(l.tries_used, 0), currency = g.currency
+FROM lobbies l
+JOIN games g ON g.id = l.game_id
+WHERE l.id = h.lobby_id;
+
+-- Индекс для выборок истории за период
+CREATE INDEX IF NOT EXISTS idx_history_created_at ON history(created_at);
+
+-- Пользователи, скрытые из публичных рейтингов
+CREATE TABLE IF NOT EXISTS leaderboard_opt_outs (
+    user_id BIGINT PRIMARY KEY REFERENCES users(telegram_id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Выплаченные призы сезонов (одна выплата на место сезона)
+CREATE TABLE IF NOT EXISTS leaderboard_payouts (
+    id UUID PRIMARY KEY,
+    period VARCHAR(20) NOT NULL,
+    metric VARCHAR(20) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    rank INTEGER NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    amount DECIMAL(18, 6) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_leaderboard_payout UNIQUE (period, metric, currency, period_start, rank),
+    CONSTRAINT check_leaderboard_payout_amount CHECK (amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_leaderboard_payouts_user ON leaderboard_payouts(user_id);
+
+-- Добавляем тип транзакции для призов сезонов
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS check_transaction_type;
+ALTER TABLE transactions ADD CONSTRAINT check_transaction_type CHECK (type IN ('deposit', 'withdraw', 'reward', 'bet', 'commission', 'refund', 'game_deposit', 'game_refund', 'reserve', 'release_reserve', 'side_bet', 'side_bet_payout', 'duel_stake', 'duel_payout', 'duel_refund', 'pool_withdraw', 'pool_top_up', 'jackpot', 'referral', 'bonus', 'bonus_conversion', 'leaderboard_prize'));
@@ -0,0 +1,5 @@
+-- Откат миграции агрегатов статистики игроков
+
+DROP TABLE IF EXISTS player_daily_start_words;
+DROP TABLE IF EXISTS player_daily_guesses;
+DROP TABLE IF EXISTS player_daily_stats;
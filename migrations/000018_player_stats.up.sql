@@ -0,0 +1,65 @@
+-- Миграция для агрегатов статистики игроков
+
+-- Дневные итоги игрока по валютам (обновляются при расчёте каждого лобби)
+CREATE TABLE IF NOT EXISTS player_daily_stats (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    day DATE NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    games INTEGER NOT NULL DEFAULT 0,
+    wins INTEGER NOT NULL DEFAULT 0,
+    cash_outs INTEGER NOT NULL DEFAULT 0,
+    wagered DECIMAL(18, 6) NOT NULL DEFAULT 0,
+    paid_out DECIMAL(18, 6) NOT NULL DEFAULT 0,
+    solve_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    timed_solves INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day, currency)
+);
+
+-- Число побед по количеству попыток
+CREATE TABLE IF NOT EXISTS player_daily_guesses (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    day DATE NOT NULL,
+    tries INTEGER NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day, tries)
+);
+
+-- Стартовые слова игрока
+CREATE TABLE IF NOT EXISTS player_daily_start_words (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    day DATE NOT NULL,
+    word VARCHAR(50) NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day, word)
+);
+
+-- Заполняем агрегаты по уже сыгранным лобби (время решения для них неизвестно)
+INSERT INTO player_daily_stats (user_id, day, currency, games, wins, cash_outs, wagered, paid_out)
+SELECT user_id, (created_at AT TIME ZONE 'UTC')::date, currency,
+       COUNT(*),
+       COUNT(*) FILTER (WHERE status = 'player_win'),
+       COUNT(*) FILTER (WHERE status = 'cash_out'),
+       SUM(bet_amount),
+       SUM(reward)
+FROM history
+GROUP BY user_id, (created_at AT TIME ZONE 'UTC')::date, currency
+ON CONFLICT DO NOTHING;
+
+INSERT INTO player_daily_guesses (user_id, day, tries, count)
+SELECT user_id, (created_at AT TIME ZONE 'UTC')::date, tries_used, COUNT(*)
+FROM history
+WHERE status = 'player_win' AND tries_used > 0
+GROUP BY user_id, (created_at AT TIME ZONE 'UTC')::date, tries_used
+ON CONFLICT DO NOTHING;
+
+INSERT INTO player_daily_start_words (user_id, day, word, count)
+SELECT h.user_id, (h.created_at AT TIME ZONE 'UTC')::date, LOWER(a.word), COUNT(*)
+FROM history h
+JOIN LATERAL (
+    SELECT word FROM attempts
+    WHERE lobby_id = h.lobby_id AND user_id = h.user_id
+    ORDER BY created_at
+    LIMIT 1
+) a ON TRUE
+GROUP BY h.user_id, (h.created_at AT TIME ZONE 'UTC')::date, LOWER(a.word)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+-- Откат миграции аналитики создателей
+
+DROP INDEX IF EXISTS idx_commission_entries_game;
+DROP INDEX IF EXISTS idx_transactions_game_created;
+DROP INDEX IF EXISTS idx_attempts_game_created;
+DROP INDEX IF EXISTS idx_history_game_created;
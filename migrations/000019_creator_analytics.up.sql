@@ -0,0 +1,7 @@
+-- Миграция для аналитики создателей
+
+-- Выборки по играм создателя за период
+CREATE INDEX IF NOT EXISTS idx_history_game_created ON history(game_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_attempts_game_created ON attempts(game_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_game_created ON transactions(game_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_commission_entries_game ON commission_entries(game_id, created_at);
@@ -0,0 +1,10 @@
+-- Откат миграции поиска и сортировки игр
+
+DROP INDEX IF EXISTS idx_transactions_user_created;
+DROP INDEX IF EXISTS idx_lobbies_user_created;
+DROP INDEX IF EXISTS idx_games_public_created;
+DROP INDEX IF EXISTS idx_games_search;
+
+ALTER TABLE games
+    DROP COLUMN IF EXISTS wins_count,
+    DROP COLUMN IF EXISTS plays_count;
@@ -0,0 +1,25 @@
+-- Миграция для поиска и сортировки игр
+
+-- Счётчики рассчитанных лобби для сортировки по популярности и доле побед
+ALTER TABLE games
+    ADD COLUMN IF NOT EXISTS plays_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS wins_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE games g
+SET plays_count = h.plays, wins_count = h.wins
+FROM (
+    SELECT game_id, COUNT(*) AS plays, COUNT(*) FILTER (WHERE status = 'player_win') AS wins
+    FROM history
+    GROUP BY game_id
+) h
+WHERE h.game_id = g.id;
+
+-- Полнотекстовый поиск по названию и описанию
+CREATE INDEX IF NOT EXISTS idx_games_search ON games
+    USING GIN (to_tsvector('simple', COALESCE(title, '') || ' ' || COALESCE(description, '')));
+
+-- Keyset-пагинация списков
+CREATE INDEX IF NOT EXISTS idx_games_public_created ON games(created_at DESC, id DESC)
+    WHERE status = 'active' AND visibility = 'public';
+CREATE INDEX IF NOT EXISTS idx_lobbies_user_created ON lobbies(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_created ON transactions(user_id, created_at DESC, id DESC);
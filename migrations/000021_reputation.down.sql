@@ -0,0 +1,5 @@
+-- Откат миграции оценок игр и репутации создателей
+
+DROP TABLE IF EXISTS creator_reputations;
+DROP TABLE IF EXISTS creator_disputes;
+DROP TABLE IF EXISTS game_reviews;
@@ -0,0 +1,51 @@
+-- Миграция для оценок игр и репутации создателей
+
+-- Оценки и отзывы игроков, завершивших лобби в игре
+CREATE TABLE IF NOT EXISTS game_reviews (
+    id UUID PRIMARY KEY,
+    game_id UUID NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    creator_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    review TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'visible',
+    moderation_note TEXT NOT NULL DEFAULT '',
+    moderated_by BIGINT,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (game_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_reviews_game ON game_reviews(game_id, status, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_game_reviews_creator ON game_reviews(creator_id, status);
+CREATE INDEX IF NOT EXISTS idx_game_reviews_status ON game_reviews(status, updated_at DESC);
+
+-- Исходы споров игроков с создателями
+CREATE TABLE IF NOT EXISTS creator_disputes (
+    id UUID PRIMARY KEY,
+    creator_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    lobby_id UUID,
+    outcome VARCHAR(20) NOT NULL CHECK (outcome IN ('player', 'creator')),
+    note TEXT NOT NULL DEFAULT '',
+    resolved_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_creator_disputes_creator ON creator_disputes(creator_id);
+
+-- Рассчитанная репутация создателей
+CREATE TABLE IF NOT EXISTS creator_reputations (
+    creator_id BIGINT PRIMARY KEY,
+    score DOUBLE PRECISION NOT NULL,
+    rating_average DOUBLE PRECISION NOT NULL DEFAULT 0,
+    rating_count INTEGER NOT NULL DEFAULT 0,
+    completion_rate DOUBLE PRECISION NOT NULL DEFAULT 0,
+    refund_rate DOUBLE PRECISION NOT NULL DEFAULT 0,
+    disputes INTEGER NOT NULL DEFAULT 0,
+    disputes_lost INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_creator_reputations_score ON creator_reputations(score DESC);
@@ -0,0 +1,5 @@
+-- Откат миграции подписок на создателей, закладок и ленты игр
+
+DROP TABLE IF EXISTS game_activations;
+DROP TABLE IF EXISTS game_bookmarks;
+DROP TABLE IF EXISTS creator_follows;
@@ -0,0 +1,34 @@
+-- Миграция для подписок на создателей, закладок и ленты игр
+
+-- Подписки пользователей на создателей игр
+CREATE TABLE IF NOT EXISTS creator_follows (
+    follower_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    creator_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    notify BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (follower_id, creator_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_creator_follows_creator ON creator_follows(creator_id) WHERE notify;
+
+-- Закладки игр
+CREATE TABLE IF NOT EXISTS game_bookmarks (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    game_id UUID NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, game_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_bookmarks_user_created ON game_bookmarks(user_id, created_at DESC);
+
+-- Время последней активации игры для ленты подписок
+CREATE TABLE IF NOT EXISTS game_activations (
+    game_id UUID PRIMARY KEY REFERENCES games(id) ON DELETE CASCADE,
+    activated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_activations_activated_at ON game_activations(activated_at DESC);
+
+INSERT INTO game_activations (game_id, activated_at)
+SELECT id, COALESCE(starts_at, updated_at, created_at) FROM games WHERE status = 'active'
+ON CONFLICT (game_id) DO NOTHING;
@@ -0,0 +1,6 @@
+-- Откат миграции уведомлений бота
+
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
+
+ALTER TABLE users DROP COLUMN IF EXISTS language_code;
@@ -0,0 +1,31 @@
+-- Миграция для уведомлений бота: очередь отправки, настройки пользователей и язык Telegram
+
+-- Язык интерфейса Telegram из initData, по нему локализуются уведомления
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language_code VARCHAR(16) NOT NULL DEFAULT '';
+
+-- Очередь уведомлений (outbox): сообщения сохраняются вместе с событием и отправляются фоновой задачей
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    dedup_key VARCHAR(128) UNIQUE,
+    text TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC);
+
+-- Настройки уведомлений: хранятся только изменённые пользователем типы, остальные включены
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, type)
+);
@@ -0,0 +1,3 @@
+-- Откат миграции outbox доменных событий
+
+DROP TABLE IF EXISTS domain_events;
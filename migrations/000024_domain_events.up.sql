@@ -0,0 +1,20 @@
+-- Миграция для outbox доменных событий: события пишутся в транзакции изменения состояния и доставляются подписчикам фоновой задачей
+
+CREATE TABLE IF NOT EXISTS domain_events (
+    id UUID PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    -- Подписчики, уже обработавшие событие: повторная доставка их пропускает
+    delivered_to TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_events_due ON domain_events(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_domain_events_dead ON domain_events(created_at DESC) WHERE status = 'dead';
+CREATE INDEX IF NOT EXISTS idx_domain_events_aggregate ON domain_events(aggregate_id);
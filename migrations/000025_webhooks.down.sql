@@ -0,0 +1,4 @@
+-- Откат миграции исходящих webhook
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,3 @@
+-- Откат миграции счёта рынка ставок зрителей
+
+DROP TABLE IF EXISTS side_bet_accounts;
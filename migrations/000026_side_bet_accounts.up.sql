@@ -0,0 +1,14 @@
+-- Миграция для счёта рынка ставок зрителей: ставки зачисляются на него, выигрыши и возвраты списываются с него
+
+CREATE TABLE IF NOT EXISTS side_bet_accounts (
+    currency VARCHAR(10) PRIMARY KEY,
+    balance DECIMAL(18, 6) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Начальный баланс по уже принятым ставкам: все ставки минус выплаты по рассчитанным
+INSERT INTO side_bet_accounts (currency, balance, updated_at)
+SELECT currency, SUM(amount) - SUM(COALESCE(payout, 0)), NOW()
+FROM side_bets
+GROUP BY currency
+ON CONFLICT (currency) DO NOTHING;
@@ -0,0 +1,4 @@
+-- Откат миграции транзакций без пользователя. Транзакции с неизвестных кошельков удаляются
+
+DELETE FROM transactions WHERE user_id IS NULL;
+ALTER TABLE transactions ALTER COLUMN user_id SET NOT NULL;
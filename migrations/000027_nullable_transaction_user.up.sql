@@ -0,0 +1,4 @@
+-- Миграция для транзакций без пользователя: депозиты и возвраты ставок с неизвестных кошельков
+-- записываются с NULL вместо пользователя, ссылка на users сохраняется для остальных транзакций
+
+ALTER TABLE transactions ALTER COLUMN user_id DROP NOT NULL;
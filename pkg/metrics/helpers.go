@@ -136,3 +136,10 @@ func SetTotalUsersBalance(currency string, balance float64) {
 		registry.TotalUsersBalance.WithLabelValues(currency).Set(balance)
 	}
 }
+
+// SetJackpotAmount устанавливает текущий размер джекпота
+func SetJackpotAmount(currency string, amount float64) {
+	if registry != nil {
+		registry.JackpotAmount.WithLabelValues(currency).Set(amount)
+	}
+}
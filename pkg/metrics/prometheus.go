@@ -34,6 +34,7 @@ type MetricsRegistry struct {
 	RewardsTotal      *prometheus.CounterVec // Сумма выплат по валюте
 	PendingWithdrawals prometheus.Gauge       // Количество ожидающих выводов
 	TotalUsersBalance *prometheus.GaugeVec   // Общий баланс пользователей по валюте
+	JackpotAmount     *prometheus.GaugeVec   // Текущий размер джекпота по валюте
 }
 
 // NewMetricsRegistry создает и регистрирует все метрики
@@ -159,6 +160,13 @@ func NewMetricsRegistry() *MetricsRegistry {
 			},
 			[]string{"currency"},
 		),
+		JackpotAmount: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "wordle_jackpot_amount",
+				Help: "Текущий размер джекпота по валюте",
+			},
+			[]string{"currency"},
+		),
 	}
 
 	return registry